@@ -97,6 +97,7 @@ func TestAcceptance(t *testing.T) {
 		"pool",
 		"api",
 		"queue",
+		"test",
 		"alias",
 		"link",
 		"skill",
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,12 +13,14 @@ import (
 
 // AgentsOptions represents options for listing agents
 type AgentsOptions struct {
-	Authorized bool   // Filter by authorization status
-	Connected  bool   // Filter by connection status
-	Enabled    bool   // Filter by enabled status
-	Pool       string // Filter by pool name
-	Limit      int
-	Fields     []string // Fields to return (uses AgentFields.Default if empty)
+	Authorized   bool   // Filter by authorization status
+	Connected    bool   // Filter by connection status
+	Disconnected bool   // Only disconnected agents; exclusive with Connected
+	Enabled      bool   // Filter by enabled status
+	Disabled     bool   // Only disabled agents; exclusive with Enabled
+	Pool         string // Filter by pool name
+	Limit        int
+	Fields       []string // Fields to return (uses AgentFields.Default if empty)
 }
 
 // GetAgents returns a list of agents, following pagination; the bool is true when a finite limit capped the result.
@@ -31,9 +35,13 @@ func (c *Client) GetAgents(opts AgentsOptions) (*AgentList, bool, error) {
 
 	if opts.Connected {
 		locator.Add("connected", "true")
+	} else if opts.Disconnected {
+		locator.Add("connected", "false")
 	}
 	if opts.Enabled {
 		locator.Add("enabled", "true")
+	} else if opts.Disabled {
+		locator.Add("enabled", "false")
 	}
 	if opts.Pool != "" {
 		if _, err := strconv.Atoi(opts.Pool); err == nil {
@@ -79,7 +87,10 @@ func (c *Client) AuthorizeAgent(id int, authorized bool) error {
 }
 
 // agentDetailFields is the fields parameter used for agent detail requests
-const agentDetailFields = "id,name,typeId,connected,enabled,authorized,href,webUrl,pool(id,name),build(id,number,status,buildType(id,name))"
+const agentDetailFields = "id,name,typeId,connected,enabled,authorized,href,webUrl,pool(id,name)," +
+	"build(id,number,status,state,statusText,branchName,startDate,percentageComplete,webUrl,buildTypeId,buildType(id,name))," +
+	"enabledInfo(status,comment(text,timestamp,user(username,name))),authorizedInfo(status,comment(text,timestamp,user(username,name)))," +
+	"lastActivityTime,idleSinceTime,disconnectionComment"
 
 // GetAgent returns details for a single agent
 func (c *Client) GetAgent(id int) (*Agent, error) {
@@ -107,6 +118,20 @@ func (c *Client) GetAgentByName(name string) (*Agent, error) {
 	return &result, nil
 }
 
+// GetAgentProperties returns the configuration parameters an agent reports (env.*, system.*, teamcity.agent.*), as the server stores them.
+func (c *Client) GetAgentProperties(ctx context.Context, id int) (*PropertyList, error) {
+	path := fmt.Sprintf("/app/rest/agents/id:%d?fields=%s", id, url.QueryEscape("properties(property(name,value))"))
+
+	var result struct {
+		Properties PropertyList `json:"properties"`
+	}
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Properties, nil
+}
+
 // EnableAgent sets the enabled status of an agent
 func (c *Client) EnableAgent(id int, enabled bool) error {
 	path := fmt.Sprintf("/app/rest/agents/id:%d/enabled", id)
@@ -117,6 +142,25 @@ func (c *Client) EnableAgent(id int, enabled bool) error {
 	return c.doNoContent(c.ctx(), "PUT", path, strings.NewReader(value), "text/plain")
 }
 
+// SetAgentEnabledInfo enables or disables an agent, leaving comment as the reason shown with its state.
+func (c *Client) SetAgentEnabledInfo(id int, enabled bool, comment string) error {
+	return c.setAgentStateInfo(id, "enabledInfo", enabled, comment)
+}
+
+// SetAgentAuthorizedInfo authorizes or deauthorizes an agent, leaving comment as the reason shown with its state.
+func (c *Client) SetAgentAuthorizedInfo(id int, authorized bool, comment string) error {
+	return c.setAgentStateInfo(id, "authorizedInfo", authorized, comment)
+}
+
+func (c *Client) setAgentStateInfo(id int, info string, status bool, comment string) error {
+	path := fmt.Sprintf("/app/rest/agents/id:%d/%s", id, info)
+	body, err := json.Marshal(AgentStateInfo{Status: status, Comment: &AgentComment{Text: comment}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.doNoContent(c.ctx(), "PUT", path, bytes.NewReader(body), "")
+}
+
 // GetAgentCompatibleBuildTypes returns build types compatible with an agent
 func (c *Client) GetAgentCompatibleBuildTypes(id int) (*BuildTypeList, error) {
 	fields := "count,buildType(id,name,projectName,projectId)"
@@ -197,7 +241,7 @@ func (c *Client) GetAgentBuildTypeCompatibility(agentID int, buildTypeID string,
 // This uses the web UI endpoint as there is no REST API for agent reboot.
 func (c *Client) RebootAgent(ctx context.Context, id int, afterBuild bool) error {
 	if c.ReadOnly {
-		return fmt.Errorf("%w: POST /remoteAccess/reboot.html", ErrReadOnly)
+		return fmt.Errorf("%w: POST /remoteAccess/reboot.html", c.readOnlyError())
 	}
 
 	formData := url.Values{}
@@ -218,7 +262,7 @@ func (c *Client) RebootAgent(ctx context.Context, id int, afterBuild bool) error
 
 	c.debugLogRequest(req)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.HTTPClient.Do(c.traceTiming(req))
 	if err != nil {
 		return &NetworkError{URL: c.BaseURL, Cause: err}
 	}
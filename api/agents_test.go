@@ -95,6 +95,21 @@ func TestGetAgent(t *testing.T) {
 	assert.Equal(t, 42, agent.ID)
 }
 
+func TestGetAgentProperties(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/agents/id:42", r.URL.Path)
+		assert.Equal(t, "properties(property(name,value))", r.URL.Query().Get("fields"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"properties":{"count":2,"property":[{"name":"env.JAVA_HOME","value":"/opt/jdk"},{"name":"teamcity.agent.jvm.os.name","value":"Linux"}]}}`))
+	})
+
+	props, err := client.GetAgentProperties(t.Context(), 42)
+	require.NoError(t, err)
+	require.Len(t, props.Property, 2)
+	assert.Equal(t, Property{Name: "env.JAVA_HOME", Value: "/opt/jdk"}, props.Property[0])
+}
+
 func TestGetAgentByName(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -2,12 +2,15 @@ package api
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +26,9 @@ type BuildsOptions struct {
 	Project     string
 	Number      string
 	Revision    string
+	Tag         string
+	Agent       string // agent ID or name
+	Change      int    // only builds that include the change with this ID
 	Favorites   bool
 	Limit       int
 	SinceDate   string
@@ -67,6 +73,22 @@ func (opts BuildsOptions) Locator() *Locator {
 		Add("revision", opts.Revision).
 		Add("sinceDate", opts.SinceDate).
 		Add("untilDate", opts.UntilDate)
+	if opts.Tag != "" {
+		locator.AddLocator("tag", NewLocator().
+			AddLocator("condition", NewLocator().
+				Add("value", opts.Tag).
+				Add("matchType", "equals")))
+	}
+	if opts.Agent != "" {
+		if _, err := strconv.Atoi(opts.Agent); err == nil {
+			locator.AddLocator("agent", NewLocator().Add("id", opts.Agent))
+		} else {
+			locator.AddLocator("agent", NewLocator().Add("name", opts.Agent))
+		}
+	}
+	if opts.Change > 0 {
+		locator.AddLocator("change", NewLocator().AddInt("id", opts.Change))
+	}
 	if opts.Favorites {
 		locator.AddLocator("tag", currentUserFavoriteBuildsTagLocator())
 	}
@@ -87,6 +109,7 @@ func currentUserFavoriteBuildsTagLocator() *Locator {
 }
 
 // GetBuilds returns a list of builds, following pagination; the bool is true when a finite limit capped the result.
+// When ctx is canceled partway, it returns the builds fetched so far along with the error.
 func (c *Client) GetBuilds(ctx context.Context, opts BuildsOptions) (*BuildList, bool, error) {
 	locator := opts.Locator().
 		AddInt("count", pageCount(opts.Limit))
@@ -109,7 +132,7 @@ func (c *Client) GetBuilds(ctx context.Context, opts BuildsOptions) (*BuildList,
 		}
 		return page.Builds, page.NextHref, nil
 	})
-	if err != nil {
+	if err != nil && (ctx.Err() == nil || len(builds) == 0) {
 		return nil, false, err
 	}
 
@@ -117,7 +140,7 @@ func (c *Client) GetBuilds(ctx context.Context, opts BuildsOptions) (*BuildList,
 		cleanupBuildTriggered(&builds[i])
 	}
 
-	return &BuildList{Count: len(builds), Builds: builds}, truncated, nil
+	return &BuildList{Count: len(builds), Builds: builds}, truncated, err
 }
 
 // cleanupBuildTriggered removes empty User objects from build trigger info
@@ -132,8 +155,18 @@ func cleanupBuildTriggered(b *Build) {
 
 // ResolveBuildID resolves a build reference to an ID.
 // If ref starts with #, it's treated as a build number and looked up.
+// A TeamCity web URL of a build yields the ID in it.
 // Otherwise it's used as-is (assumed to be an ID).
 func (c *Client) ResolveBuildID(ctx context.Context, ref string) (string, error) {
+	if id, isURL := BuildIDFromURL(ref); isURL {
+		if id == "" {
+			return "", Validation(
+				fmt.Sprintf("%s does not link to a run", ref),
+				"Pass the run's URL (e.g. .../buildConfiguration/<job>/<id>) or its ID",
+			)
+		}
+		return id, nil
+	}
 	number, ok := strings.CutPrefix(ref, "#")
 	if !ok {
 		return ref, nil
@@ -148,6 +181,37 @@ func (c *Client) ResolveBuildID(ctx context.Context, ref string) (string, error)
 	return strconv.Itoa(builds.Builds[0].ID), nil
 }
 
+// BuildIDFromURL returns the build ID in a TeamCity web URL: the buildId query parameter
+// (viewLog.html?buildId=12345), or the path segment after /buildConfiguration/<job>/ or /build/.
+// isURL is false when ref is not an http(s) URL; id is empty when a URL names no build.
+func BuildIDFromURL(ref string) (id string, isURL bool) {
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return "", false
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", true
+	}
+	if id := u.Query().Get("buildId"); isBuildID(id) {
+		return id, true
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, s := range segments {
+		switch {
+		case s == "buildConfiguration" && i+2 < len(segments) && isBuildID(segments[i+2]):
+			return segments[i+2], true
+		case s == "build" && i+1 < len(segments) && isBuildID(segments[i+1]):
+			return segments[i+1], true
+		}
+	}
+	return "", true
+}
+
+// isBuildID reports whether s is a non-empty string of digits.
+func isBuildID(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
 // GetBuild returns a single build by ID or #number
 func (c *Client) GetBuild(ctx context.Context, ref string) (*Build, error) {
 	id, err := c.ResolveBuildID(ctx, ref)
@@ -165,6 +229,30 @@ func (c *Client) GetBuild(ctx context.Context, ref string) (*Build, error) {
 	return &build, nil
 }
 
+// buildRerunFields requests how a build was triggered: its custom parameters with their types, tags, comment,
+// personal change, and the revision of each VCS root.
+const buildRerunFields = "id,number,buildTypeId,branchName,personal," +
+	"properties(property(name,value,type(rawValue)))," +
+	"tags(tag(name)),comment(text),lastChanges(change(id,personal))," +
+	"revisions(revision(version,vcsBranchName,vcs-root-instance(vcs-root-id)))"
+
+// GetBuildForRerun returns a build by ID or #number with what triggering it again needs: Properties (the
+// parameters set when it was triggered), Tags, Comment, LastChanges, and Revisions.
+func (c *Client) GetBuildForRerun(ctx context.Context, ref string) (*RerunBuild, error) {
+	id, err := c.ResolveBuildID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/app/rest/builds/id:%s?fields=%s", id, url.QueryEscape(buildRerunFields))
+
+	var build RerunBuild
+	if err := c.get(ctx, path, &build); err != nil {
+		return nil, err
+	}
+	return &build, nil
+}
+
 // GetBuildUsedByOtherBuilds checks whether a build's results were shared with other builds.
 // This field is not included in TC's default response, so it requires a targeted request.
 func (c *Client) GetBuildUsedByOtherBuilds(id string) (bool, error) {
@@ -234,14 +322,15 @@ func (c *Client) WaitForBuild(ctx context.Context, buildID string, opts WaitForB
 
 // getFinishedBuild fetches the full build after state transitions to "finished".
 // TeamCity briefly reports status as "UNKNOWN" during post-processing; this retries
-// a few times to let the final status (SUCCESS/FAILURE/etc.) settle.
+// a few times to let the final status (SUCCESS/FAILURE/etc.) settle. A canceled run's
+// UNKNOWN is final, so it is returned at once.
 func (c *Client) getFinishedBuild(ctx context.Context, id string) (*Build, error) {
 	for range 10 {
 		build, err := c.GetBuild(ctx, id)
 		if err != nil {
 			return nil, err
 		}
-		if build.Status != "UNKNOWN" {
+		if build.Status != "UNKNOWN" || build.CanceledInfo != nil {
 			return build, nil
 		}
 		select {
@@ -269,8 +358,61 @@ type RunBuildOptions struct {
 	Tags                      []string
 	PersonalChangeID          string
 	Revision                  string
+	Revisions                 []Revision // Exact revision per VCS root, as an earlier build used; overrides Revision
 	SnapshotDependencies      []int
-	FreezeSettings            *bool // nil = build configuration default; true = settings from VCS; false = current server settings
+	ArtifactBuilds            []ArtifactBuild         // Pin artifact dependencies on these jobs to specific builds
+	ArtifactDependencies      *ArtifactDependencyList // The job's artifact dependencies, if already fetched; used with ArtifactBuilds
+	FreezeSettings            *bool                   // nil = build configuration default; true = settings from VCS; false = current server settings
+}
+
+// ArtifactBuild pins the artifact dependency on BuildTypeID to the build with BuildID.
+type ArtifactBuild struct {
+	BuildTypeID string
+	BuildID     int
+}
+
+// pinArtifactDependencies returns buildTypeID's artifact dependencies with those on pinned jobs resolved to the given builds; the result replaces the configured list, so unpinned dependencies are carried over unchanged.
+// deps are the dependencies the caller already fetched, or nil to fetch them.
+func (c *Client) pinArtifactDependencies(buildTypeID string, deps *ArtifactDependencyList, pins []ArtifactBuild) (*ArtifactDependencyList, error) {
+	if deps == nil {
+		var err error
+		if deps, err = c.GetArtifactDependencies(buildTypeID); err != nil {
+			return nil, fmt.Errorf("failed to get artifact dependencies: %w", err)
+		}
+	}
+	out := &ArtifactDependencyList{Replace: "true", ArtifactDependency: deps.ArtifactDependency}
+	for _, pin := range pins {
+		found := false
+		for i, dep := range out.ArtifactDependency {
+			if dep.SourceBuildType == nil || dep.SourceBuildType.ID != pin.BuildTypeID {
+				continue
+			}
+			found = true
+			out.ArtifactDependency[i].Properties = withRevision(dep.Properties, pin.BuildID)
+		}
+		if !found {
+			return nil, fmt.Errorf("build configuration %s has no artifact dependency on %s", buildTypeID, pin.BuildTypeID)
+		}
+	}
+	out.Count = len(out.ArtifactDependency)
+	return out, nil
+}
+
+// withRevision copies props with revisionName/revisionValue pointing at a specific build ID.
+func withRevision(props *PropertyList, buildID int) *PropertyList {
+	var out []Property
+	if props != nil {
+		for _, p := range props.Property {
+			if p.Name != "revisionName" && p.Name != "revisionValue" {
+				out = append(out, p)
+			}
+		}
+	}
+	out = append(out,
+		Property{Name: "revisionName", Value: "buildId"},
+		Property{Name: "revisionValue", Value: strconv.Itoa(buildID)},
+	)
+	return &PropertyList{Property: out}
 }
 
 // RunBuild runs a new build with full options
@@ -341,7 +483,17 @@ func (c *Client) RunBuild(buildTypeID string, opts RunBuildOptions) (*Build, err
 		req.SnapshotDependencies = &SnapshotDepBuilds{Build: refs}
 	}
 
-	if opts.Revision != "" {
+	if len(opts.ArtifactBuilds) > 0 {
+		deps, err := c.pinArtifactDependencies(buildTypeID, opts.ArtifactDependencies, opts.ArtifactBuilds)
+		if err != nil {
+			return nil, err
+		}
+		req.CustomArtifactDependencies = deps
+	}
+
+	if len(opts.Revisions) > 0 {
+		req.Revisions = &Revisions{Revision: opts.Revisions}
+	} else if opts.Revision != "" {
 		entries, err := c.GetVcsRootEntries(buildTypeID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get VCS root entries: %w", err)
@@ -389,6 +541,15 @@ func (c *Client) RunBuild(buildTypeID string, opts RunBuildOptions) (*Build, err
 	return &build, nil
 }
 
+// DeleteBuild deletes a finished build and its artifacts and logs (accepts ID or #number)
+func (c *Client) DeleteBuild(ctx context.Context, buildID string) error {
+	id, err := c.ResolveBuildID(ctx, buildID)
+	if err != nil {
+		return err
+	}
+	return c.doNoContent(ctx, "DELETE", "/app/rest/builds/id:"+id, nil, "")
+}
+
 // CancelBuild cancels a running or queued build (accepts ID or #number)
 func (c *Client) CancelBuild(buildID string, comment string) error {
 	id, err := c.ResolveBuildID(c.ctx(), buildID)
@@ -429,7 +590,16 @@ func (c *Client) CancelBuild(buildID string, comment string) error {
 
 // GetBuildSnapshotDependencies returns all immediate dependency builds in a snapshot dependency chain.
 func (c *Client) GetBuildSnapshotDependencies(buildID string) (*BuildList, error) {
-	locator := fmt.Sprintf("snapshotDependency:(to:(id:%s),recursive:false),defaultFilter:false,count:%d", buildID, pageCount(0))
+	builds, err := c.listSnapshotDependencyBuilds(c.ctx(), buildID, "id,number,status,statusText,state,buildTypeId,buildType(id,name)")
+	if err != nil {
+		return nil, err
+	}
+	return &BuildList{Count: len(builds), Builds: builds}, nil
+}
+
+// GetBuildArtifactDependencies returns the builds buildID took artifacts from.
+func (c *Client) GetBuildArtifactDependencies(buildID string) (*BuildList, error) {
+	locator := fmt.Sprintf("artifactDependency:(to:(id:%s),recursive:false),defaultFilter:false,count:%d", buildID, pageCount(0))
 	fields := "count,nextHref,build(id,number,status,statusText,state,buildTypeId,buildType(id,name))"
 	path := fmt.Sprintf("/app/rest/builds?locator=%s&fields=%s", url.QueryEscape(locator), url.QueryEscape(fields))
 
@@ -445,3 +615,84 @@ func (c *Client) GetBuildSnapshotDependencies(buildID string) (*BuildList, error
 	}
 	return &BuildList{Count: len(builds), Builds: builds}, nil
 }
+
+// GetBuildDependents returns the builds, queued and running ones included, that snapshot-depend directly on buildID.
+func (c *Client) GetBuildDependents(ctx context.Context, buildID string) ([]Build, error) {
+	return c.listSnapshotLinkedBuilds(ctx, "from", buildID, "id,number,state,buildTypeId,buildType(id,name)")
+}
+
+func (c *Client) listSnapshotDependencyBuilds(ctx context.Context, buildID, buildFields string) ([]Build, error) {
+	return c.listSnapshotLinkedBuilds(ctx, "to", buildID, buildFields)
+}
+
+// listSnapshotLinkedBuilds lists the builds one snapshot-dependency hop from buildID:
+// its dependencies when direction is "to", the builds depending on it when "from".
+func (c *Client) listSnapshotLinkedBuilds(ctx context.Context, direction, buildID, buildFields string) ([]Build, error) {
+	locator := fmt.Sprintf("snapshotDependency:(%s:(id:%s),recursive:false),defaultFilter:false,count:%d", direction, buildID, pageCount(0))
+	fields := "count,nextHref,build(" + buildFields + ")"
+	path := fmt.Sprintf("/app/rest/builds?locator=%s&fields=%s", url.QueryEscape(locator), url.QueryEscape(fields))
+
+	builds, _, err := collectPages(c, path, 0, func(p string) ([]Build, string, error) {
+		var page BuildList
+		if err := c.get(ctx, p, &page); err != nil {
+			return nil, "", err
+		}
+		return page.Builds, page.NextHref, nil
+	})
+	return builds, err
+}
+
+// BuildBatch is one child build that a parallel-tests or matrix build fanned out into.
+type BuildBatch struct {
+	Index int    `json:"index"`
+	Label string `json:"label"`
+	Build *Build `json:"build"`
+}
+
+// batchNameRE matches the "(batch N)" suffix of parallel-tests batch configurations.
+var batchNameRE = regexp.MustCompile(`\(batch (\d+)\)\s*$`)
+
+// GetBuildBatches returns the batch builds of a parallel-tests or matrix build, ordered by batch; ordinary builds have none.
+// Batches are the build's snapshot dependencies whose configuration is named "<job> (batch N)" or, for matrix, "<job> (<values>)".
+func (c *Client) GetBuildBatches(ctx context.Context, build *Build) ([]BuildBatch, error) {
+	deps, err := c.listSnapshotDependencyBuilds(ctx, strconv.Itoa(build.ID),
+		"id,number,status,statusText,state,percentageComplete,startDate,finishDate,webUrl,buildTypeId,buildType(id,name)")
+	if err != nil {
+		return nil, err
+	}
+
+	parentName := ""
+	if build.BuildType != nil {
+		parentName = build.BuildType.Name
+	}
+
+	batches := []BuildBatch{}
+	for i := range deps {
+		if deps[i].BuildType == nil {
+			continue
+		}
+		name := deps[i].BuildType.Name
+		if m := batchNameRE.FindStringSubmatch(name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			batches = append(batches, BuildBatch{Index: n, Label: "batch " + m[1], Build: &deps[i]})
+			continue
+		}
+		if parentName == "" {
+			continue
+		}
+		if label, ok := strings.CutPrefix(name, parentName+" ("); ok && strings.HasSuffix(label, ")") {
+			batches = append(batches, BuildBatch{Label: strings.TrimSuffix(label, ")"), Build: &deps[i]})
+		}
+	}
+
+	// Matrix batches carry no number; keep server order stable by build ID and number them after sorting.
+	slices.SortFunc(batches, func(a, b BuildBatch) int {
+		return cmp.Or(cmp.Compare(a.Index, b.Index), cmp.Compare(a.Build.ID, b.Build.ID))
+	})
+	for i := range batches {
+		if batches[i].Index == 0 {
+			batches[i].Index = i + 1
+		}
+	}
+	return batches, nil
+}
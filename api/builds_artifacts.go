@@ -104,7 +104,7 @@ func (c *Client) GetBuildLogStream(ctx context.Context, buildID string) (io.Read
 	if err != nil {
 		return nil, err
 	}
-	return resp.Body, nil
+	return gunzipIfCompressed(resp.Body), nil
 }
 
 // GetBuildLog returns the build log (accepts ID or #number); for large logs prefer GetBuildLogStream to avoid buffering in memory. Bypasses HTTPClient.Timeout — bound the read via ctx if needed.
@@ -169,7 +169,7 @@ func (c *Client) GetBuildChanges(ctx context.Context, buildID string) (*ChangeLi
 		return nil, err
 	}
 
-	fields := "count,change(id,version,username,date,comment,files(file(file,changeType)))"
+	fields := "count,change(id,version,username,date,comment,files(file(file,changeType)),vcsRootInstance(vcs-root-id),parentRevisions(item))"
 	path := fmt.Sprintf("/app/rest/changes?locator=build:(id:%s)&fields=%s", id, url.QueryEscape(fields))
 
 	var changes ChangeList
@@ -238,7 +238,7 @@ func (c *Client) GetBuildTestSummary(buildID string) (*TestOccurrences, error) {
 	}
 
 	locator := fmt.Sprintf("build:(id:%s)", id)
-	fields := "count,passed,failed,ignored,muted"
+	fields := "count,passed,failed,ignored,muted,newFailed"
 	path := fmt.Sprintf("/app/rest/testOccurrences?locator=%s&fields=%s", url.QueryEscape(locator), url.QueryEscape(fields))
 
 	var summary TestOccurrences
@@ -264,6 +264,43 @@ func (c *Client) GetBuildResultingProperties(buildID string) (*ParameterList, er
 	return &params, nil
 }
 
+// GetBuildStatistics returns the statistic values a build reported, such as test counts, durations, and
+// artifact sizes, by ID or #number.
+func (c *Client) GetBuildStatistics(ctx context.Context, buildID string) (*PropertyList, error) {
+	id, err := c.ResolveBuildID(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats PropertyList
+	if err := c.get(ctx, fmt.Sprintf("/app/rest/builds/id:%s/statistics", id), &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// buildParameterFields requests a build's trigger-time and resolved parameters with their types.
+const buildParameterFields = "id,number,buildTypeId,state," +
+	"properties(property(name,value,type(rawValue)))," +
+	"resultingProperties(property(name,value,type(rawValue)))"
+
+// GetBuildParameters returns a build by ID or #number with only Properties and ResultingProperties
+// populated besides its identity. ResultingProperties is empty until the build has started.
+func (c *Client) GetBuildParameters(ctx context.Context, buildID string) (*Build, error) {
+	id, err := c.ResolveBuildID(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/app/rest/builds/id:%s?fields=%s", id, url.QueryEscape(buildParameterFields))
+
+	var build Build
+	if err := c.get(ctx, path, &build); err != nil {
+		return nil, err
+	}
+	return &build, nil
+}
+
 func (c *Client) GetBuildProblems(buildID string) (*ProblemOccurrences, error) {
 	id, err := c.ResolveBuildID(c.ctx(), buildID)
 	if err != nil {
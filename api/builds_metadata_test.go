@@ -235,3 +235,38 @@ func TestGetBuildProblems(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, problems.Count)
 }
+
+func TestGetBuildParameters(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/builds/id:42", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("fields"), "resultingProperties(property(name,value,type(rawValue)))")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"state":"finished",
+			"properties":{"property":[{"name":"version","value":"1.0"}]},
+			"resultingProperties":{"property":[{"name":"version","value":"1.0"},
+				{"name":"token","value":"","type":{"rawValue":"password display='hidden'"}}]}}`))
+	})
+
+	build, err := client.GetBuildParameters(t.Context(), "42")
+	require.NoError(t, err)
+	require.NotNil(t, build.Properties)
+	assert.Len(t, build.Properties.Property, 1)
+	require.Len(t, build.ResultingProperties.Property, 2)
+	assert.False(t, build.ResultingProperties.Property[0].IsPassword())
+	assert.True(t, build.ResultingProperties.Property[1].IsPassword())
+}
+
+func TestGetBuildStatistics(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/builds/id:42/statistics", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":2,"property":[{"name":"BuildDuration","value":"61000"},{"name":"PassedTestCount","value":"12"}]}`))
+	})
+
+	stats, err := client.GetBuildStatistics(t.Context(), "42")
+	require.NoError(t, err)
+	require.Len(t, stats.Property, 2)
+	assert.Equal(t, Property{Name: "PassedTestCount", Value: "12"}, stats.Property[1])
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 // QueueOptions represents options for listing queued builds
 type QueueOptions struct {
 	BuildTypeID string
+	Project     string // affectedProject locator
 	Limit       int
 	Fields      []string
 }
@@ -18,6 +20,7 @@ type QueueOptions struct {
 func (c *Client) GetBuildQueue(opts QueueOptions) (*BuildQueue, bool, error) {
 	locator := NewLocator().
 		Add("buildType", opts.BuildTypeID).
+		Add("affectedProject", opts.Project).
 		AddInt("count", pageCount(opts.Limit))
 
 	fields := opts.Fields
@@ -46,6 +49,55 @@ func (c *Client) GetBuildQueue(opts QueueOptions) (*BuildQueue, bool, error) {
 	return &BuildQueue{Count: len(builds), Builds: builds}, truncated, nil
 }
 
+// GetQueueDependencyHolds maps each queued build that a running build is waiting on to that running build.
+// A running composite build's parts stay queued until an agent picks them up, linked to it through snapshot-dependencies;
+// holds follow those links through other queued builds, since removing any build of the chain fails the running one.
+func (c *Client) GetQueueDependencyHolds(ctx context.Context) (map[int]Build, error) {
+	running, _, err := c.GetBuilds(ctx, BuildsOptions{
+		State:  "running",
+		Fields: []string{"id", "number", "buildTypeId", "webUrl", "buildType.id", "buildType.name", "snapshot-dependencies.build.id"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	queue, _, err := c.GetBuildQueue(QueueOptions{Fields: []string{"id", "snapshot-dependencies.build.id"}})
+	if err != nil {
+		return nil, err
+	}
+
+	queuedDeps := make(map[int][]int, len(queue.Builds))
+	for _, q := range queue.Builds {
+		queuedDeps[q.ID] = dependencyIDs(q.SnapshotDependencies)
+	}
+
+	holds := map[int]Build{}
+	for _, r := range running.Builds {
+		pending := dependencyIDs(r.SnapshotDependencies)
+		for len(pending) > 0 {
+			id := pending[0]
+			pending = pending[1:]
+			deps, queued := queuedDeps[id]
+			if _, seen := holds[id]; seen || !queued {
+				continue
+			}
+			holds[id] = r
+			pending = append(pending, deps...)
+		}
+	}
+	return holds, nil
+}
+
+func dependencyIDs(deps *BuildList) []int {
+	if deps == nil {
+		return nil
+	}
+	ids := make([]int, len(deps.Builds))
+	for i, b := range deps.Builds {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
 // RemoveFromQueue removes a build from the queue
 func (c *Client) RemoveFromQueue(id string) error {
 	path := "/app/rest/buildQueue/id:" + id
@@ -69,6 +121,49 @@ func (c *Client) ApproveQueuedBuild(buildID string) error {
 	return c.doNoContent(c.ctx(), "PUT", path, strings.NewReader(`"approved"`), "application/json")
 }
 
+// GetQueuePosition returns a queued build's 1-based position and the queue length; position is 0 when the build is no longer queued.
+// The queue lists builds in the order they will start, so this reads it with ids only, page by page, and stops at the
+// page holding the build. The length is then 0 if the queue goes on past it, as the rest was not read.
+func (c *Client) GetQueuePosition(ctx context.Context, buildID int) (int, int, error) {
+	path := fmt.Sprintf("/app/rest/buildQueue?locator=%s&fields=%s",
+		NewLocator().AddInt("count", allPageSize).Encode(), url.QueryEscape("nextHref,build(id)"))
+	seen := 0
+	for path != "" {
+		var page BuildQueue
+		if err := c.get(ctx, path, &page); err != nil {
+			return 0, 0, err
+		}
+		next, err := c.NormalizePaginationPath(page.NextHref)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i, q := range page.Builds {
+			if q.ID != buildID {
+				continue
+			}
+			if next != "" {
+				return seen + i + 1, 0, nil
+			}
+			return seen + i + 1, seen + len(page.Builds), nil
+		}
+		seen += len(page.Builds)
+		path = next
+	}
+	return 0, seen, nil
+}
+
+// GetQueuedBuildStartEstimate returns when the server expects a queued build to start (TeamCity time format), or "" when it has no estimate.
+func (c *Client) GetQueuedBuildStartEstimate(ctx context.Context, buildID int) (string, error) {
+	path := fmt.Sprintf("/app/rest/buildQueue/id:%d?fields=startEstimate", buildID)
+	var result struct {
+		StartEstimate string `json:"startEstimate"`
+	}
+	if err := c.get(ctx, path, &result); err != nil {
+		return "", err
+	}
+	return result.StartEstimate, nil
+}
+
 // GetQueuedBuildApprovalInfo returns approval information for a queued build
 func (c *Client) GetQueuedBuildApprovalInfo(buildID string) (*ApprovalInfo, error) {
 	path := fmt.Sprintf("/app/rest/buildQueue/id:%s/approval", buildID)
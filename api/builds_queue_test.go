@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -86,3 +88,98 @@ func TestGetQueuedBuildApprovalInfo(t *testing.T) {
 	assert.Equal(t, "waitingForApproval", info.Status)
 	assert.True(t, info.CanBeApprovedByCurrentUser)
 }
+
+func TestGetQueueDependencyHolds(t *testing.T) {
+	t.Parallel()
+	deps := func(ids ...int) *BuildList {
+		l := &BuildList{Count: len(ids)}
+		for _, id := range ids {
+			l.Builds = append(l.Builds, Build{ID: id})
+		}
+		return l
+	}
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/app/rest/builds":
+			assert.Contains(t, r.URL.Query().Get("locator"), "state:running")
+			assert.Contains(t, r.URL.Query().Get("fields"), "snapshot-dependencies(build(id))")
+			json.NewEncoder(w).Encode(BuildList{Count: 1, Builds: []Build{
+				// 5 already finished; only queued dependencies are held.
+				{ID: 1, BuildTypeID: "Composite", SnapshotDependencies: deps(5, 10)},
+			}})
+		case "/app/rest/buildQueue":
+			json.NewEncoder(w).Encode(BuildQueue{Count: 3, Builds: []QueuedBuild{
+				{ID: 10, SnapshotDependencies: deps(11)},
+				{ID: 11},
+				{ID: 12},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	holds, err := client.GetQueueDependencyHolds(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, holds, 2)
+	assert.Equal(t, 1, holds[10].ID)
+	assert.Equal(t, 1, holds[11].ID, "dependencies of held queued builds are held too")
+	assert.NotContains(t, holds, 12)
+}
+
+func TestGetQueuePosition(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/buildQueue", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildQueue{Count: 3, Builds: []QueuedBuild{{ID: 7}, {ID: 100}, {ID: 9}}})
+	})
+
+	pos, length, err := client.GetQueuePosition(t.Context(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pos)
+	assert.Equal(t, 3, length)
+
+	pos, _, err = client.GetQueuePosition(t.Context(), 42)
+	require.NoError(t, err)
+	assert.Zero(t, pos, "a build that left the queue has no position")
+}
+
+func TestGetQueuePositionStopsAtBuild(t *testing.T) {
+	t.Parallel()
+	var pages atomic.Int32
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		pages.Add(1)
+		if !strings.Contains(r.URL.Query().Get("locator"), "start:2") {
+			json.NewEncoder(w).Encode(BuildQueue{Builds: []QueuedBuild{{ID: 7}, {ID: 100}}, NextHref: "/app/rest/buildQueue?locator=start:2"})
+			return
+		}
+		json.NewEncoder(w).Encode(BuildQueue{Builds: []QueuedBuild{{ID: 9}, {ID: 42}}})
+	})
+
+	pos, length, err := client.GetQueuePosition(t.Context(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pos)
+	assert.Zero(t, length, "the rest of the queue is not read")
+	assert.Equal(t, int32(1), pages.Load())
+
+	pos, length, err = client.GetQueuePosition(t.Context(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, 4, pos)
+	assert.Equal(t, 4, length)
+}
+
+func TestGetQueuedBuildStartEstimate(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/buildQueue/id:100", r.URL.Path)
+		assert.Equal(t, "startEstimate", r.URL.Query().Get("fields"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"startEstimate":"20250101T120000+0000"}`))
+	})
+
+	est, err := client.GetQueuedBuildStartEstimate(t.Context(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, "20250101T120000+0000", est)
+}
@@ -71,6 +71,51 @@ func TestBuildsOptionsLocator(T *testing.T) {
 				"lookupLimit",
 			},
 		},
+		{
+			name: "tag filter matches the tag name exactly",
+			opts: BuildsOptions{Tag: "release-2024.3"},
+			want: []string{
+				"tag:(condition:(value:release-2024.3,matchType:equals))",
+			},
+		},
+		{
+			name: "tag with locator delimiters is escaped",
+			opts: BuildsOptions{Tag: "rc:1"},
+			want: []string{
+				"tag:(condition:(value:(rc:1),matchType:equals))",
+			},
+		},
+		{
+			name: "tag filter combines with favorites",
+			opts: BuildsOptions{Tag: "release", Favorites: true},
+			want: []string{
+				"tag:(condition:(value:release,matchType:equals))",
+				"tag:(private:true,owner:current,",
+			},
+		},
+		{
+			name: "agent filter by ID",
+			opts: BuildsOptions{Agent: "7", State: "finished"},
+			want: []string{
+				"agent:(id:7)",
+				"state:finished",
+			},
+		},
+		{
+			name: "agent filter by name",
+			opts: BuildsOptions{Agent: "Agent-Linux-01"},
+			want: []string{
+				"agent:(name:Agent-Linux-01)",
+			},
+		},
+		{
+			name: "change filter selects builds that include the change",
+			opts: BuildsOptions{Change: 4711, BuildTypeID: "MyBuild"},
+			want: []string{
+				"change:(id:4711)",
+				"buildType:MyBuild",
+			},
+		},
 		{
 			name: "deep lookup (exact number) skips the unscoped lookup-limit cap",
 			opts: BuildsOptions{Number: "123", DeepLookup: true},
@@ -182,6 +227,69 @@ func TestRunBuildSendsSnapshotDependencies(T *testing.T) {
 	assert.Equal(T, 6922, captured.SnapshotDependencies.Build[2].ID)
 }
 
+func TestRunBuildPinsArtifactDependencies(T *testing.T) {
+	T.Parallel()
+
+	var captured TriggerBuildRequest
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			assert.Contains(T, r.URL.Path, "/app/rest/buildTypes/id:MyBuild/artifact-dependencies")
+			json.NewEncoder(w).Encode(ArtifactDependencyList{Count: 2, ArtifactDependency: []ArtifactDependency{
+				{ID: "ARTIFACT_DEPENDENCY_1", Type: "artifact_dependency", SourceBuildType: &BuildType{ID: "Lib"},
+					Properties: &PropertyList{Property: []Property{
+						{Name: "pathRules", Value: "lib.jar"},
+						{Name: "revisionName", Value: "lastSuccessful"},
+						{Name: "revisionValue", Value: "latest.lastSuccessful"},
+					}}},
+				{ID: "ARTIFACT_DEPENDENCY_2", Type: "artifact_dependency", SourceBuildType: &BuildType{ID: "Tools"},
+					Properties: &PropertyList{Property: []Property{{Name: "pathRules", Value: "tools.zip"}}}},
+			}})
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(T, err)
+		require.NoError(T, json.Unmarshal(body, &captured))
+		json.NewEncoder(w).Encode(Build{ID: 1})
+	})
+
+	_, err := client.RunBuild("MyBuild", RunBuildOptions{
+		ArtifactBuilds: []ArtifactBuild{{BuildTypeID: "Lib", BuildID: 12345}},
+	})
+	require.NoError(T, err)
+
+	deps := captured.CustomArtifactDependencies
+	require.NotNil(T, deps)
+	assert.Equal(T, "true", deps.Replace)
+	require.Len(T, deps.ArtifactDependency, 2, "unpinned dependencies must be carried over when replacing")
+	assert.Equal(T, []Property{
+		{Name: "pathRules", Value: "lib.jar"},
+		{Name: "revisionName", Value: "buildId"},
+		{Name: "revisionValue", Value: "12345"},
+	}, deps.ArtifactDependency[0].Properties.Property)
+	assert.Equal(T, []Property{{Name: "pathRules", Value: "tools.zip"}}, deps.ArtifactDependency[1].Properties.Property)
+}
+
+func TestRunBuildArtifactPinWithoutDependency(T *testing.T) {
+	T.Parallel()
+
+	posted := false
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ArtifactDependencyList{})
+	})
+
+	_, err := client.RunBuild("MyBuild", RunBuildOptions{
+		ArtifactBuilds: []ArtifactBuild{{BuildTypeID: "Lib", BuildID: 12345}},
+	})
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "has no artifact dependency on Lib")
+	assert.False(T, posted)
+}
+
 func TestRunBuildOmitsEmptySnapshotDependencies(T *testing.T) {
 	T.Parallel()
 
@@ -198,3 +306,109 @@ func TestRunBuildOmitsEmptySnapshotDependencies(T *testing.T) {
 	require.NoError(T, err)
 	assert.NotContains(T, string(rawBody), "snapshot-dependencies")
 }
+
+func TestGetBuildBatches(T *testing.T) {
+	T.Parallel()
+
+	var capturedQuery string
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		capturedQuery = r.URL.Query().Get("locator")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildList{Count: 4, Builds: []Build{
+			{ID: 12, BuildType: &BuildType{Name: "Tests (batch 2)"}},
+			{ID: 11, BuildType: &BuildType{Name: "Tests (batch 1)"}},
+			{ID: 13, BuildType: &BuildType{Name: "Compile"}},
+			{ID: 14, BuildType: &BuildType{Name: "Tests (batch 10)"}},
+		}})
+	})
+
+	batches, err := client.GetBuildBatches(T.Context(), &Build{ID: 10, BuildType: &BuildType{Name: "Tests"}})
+	require.NoError(T, err)
+
+	assert.Contains(T, capturedQuery, "snapshotDependency:(to:(id:10),recursive:false)")
+	require.Len(T, batches, 3, "non-batch dependencies are skipped")
+	assert.Equal(T, []int{1, 2, 10}, []int{batches[0].Index, batches[1].Index, batches[2].Index})
+	assert.Equal(T, "batch 1", batches[0].Label)
+	assert.Equal(T, 11, batches[0].Build.ID)
+}
+
+func TestGetBuildDependents(T *testing.T) {
+	T.Parallel()
+
+	var capturedQuery string
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		capturedQuery = r.URL.Query().Get("locator")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildList{Count: 1, Builds: []Build{{ID: 20, State: "queued"}}})
+	})
+
+	builds, err := client.GetBuildDependents(T.Context(), "10")
+	require.NoError(T, err)
+
+	assert.Contains(T, capturedQuery, "snapshotDependency:(from:(id:10),recursive:false)")
+	assert.Contains(T, capturedQuery, "defaultFilter:false")
+	require.Len(T, builds, 1)
+	assert.Equal(T, 20, builds[0].ID)
+}
+
+func TestGetBuildBatchesMatrix(T *testing.T) {
+	T.Parallel()
+
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildList{Count: 3, Builds: []Build{
+			{ID: 22, BuildType: &BuildType{Name: "Build (os: windows)"}},
+			{ID: 21, BuildType: &BuildType{Name: "Build (os: linux)"}},
+			{ID: 23, BuildType: &BuildType{Name: "Lint"}},
+		}})
+	})
+
+	batches, err := client.GetBuildBatches(T.Context(), &Build{ID: 20, BuildType: &BuildType{Name: "Build"}})
+	require.NoError(T, err)
+
+	require.Len(T, batches, 2)
+	assert.Equal(T, BuildBatch{Index: 1, Label: "os: linux", Build: batches[0].Build}, batches[0])
+	assert.Equal(T, 21, batches[0].Build.ID)
+	assert.Equal(T, 2, batches[1].Index)
+	assert.Equal(T, "os: windows", batches[1].Label)
+}
+
+func TestGetBuildBatchesOrdinaryBuild(T *testing.T) {
+	T.Parallel()
+
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildList{Count: 0, Builds: []Build{}})
+	})
+
+	batches, err := client.GetBuildBatches(T.Context(), &Build{ID: 30})
+	require.NoError(T, err)
+	assert.Empty(T, batches)
+	assert.NotNil(T, batches)
+}
+
+func TestDeleteBuild(T *testing.T) {
+	T.Parallel()
+	var method, path string
+	client := setupTestServer(T, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(T, client.DeleteBuild(T.Context(), "4812"))
+	assert.Equal(T, http.MethodDelete, method)
+	assert.Equal(T, "/app/rest/builds/id:4812", path)
+}
+
+func TestBuildRunningInfo(T *testing.T) {
+	T.Parallel()
+	var b Build
+	require.NoError(T, json.Unmarshal([]byte(`{"id":7,"state":"running","percentageComplete":40,
+		"running-info":{"percentageComplete":40,"elapsedSeconds":120,"estimatedTotalSeconds":300,
+		"currentStageText":"Step 2/3: Gradle","outdated":false,"probablyHanging":true}}`), &b))
+	require.NotNil(T, b.RunningInfo)
+	assert.Equal(T, "Step 2/3: Gradle", b.RunningInfo.CurrentStageText)
+	assert.Equal(T, 120, b.RunningInfo.ElapsedSeconds)
+	assert.Equal(T, 300, b.RunningInfo.EstimatedTotalSeconds)
+	assert.True(T, b.RunningInfo.ProbablyHanging)
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedBody caps the responses a ResponseCache keeps; larger ones, such as logs and artifacts, always go to the server.
+const maxCachedBody = 1 << 20
+
+// uncachedPaths are never answered from, or written to, a ResponseCache: who the caller is and their tokens, secure
+// values, and the logs and artifacts of runs, which are large and may still be growing.
+var uncachedPaths = []string{
+	"/users/current",
+	"/tokens",
+	"/secure/",
+	"/authenticationTest.html",
+	"/downloadBuildLog.html",
+	"/artifacts/",
+}
+
+// ResponseCache keeps successful GET responses on disk for a while, so commands that read the same thing again,
+// such as shell completion and repeated listings, don't wait on a slow server. Entries are kept per server and
+// credential. Any other request through it drops everything cached for its server, whoever wrote: a write can
+// change many listings (a started run shows in the queue, the job's runs, and the agent's), so none is trusted after one.
+//
+// Within one process, a URL is answered from the cache only the first time it is asked for: asking again is
+// polling, as run watch does, and goes to the server.
+type ResponseCache struct {
+	dir   string
+	ttl   time.Duration
+	debug func(format string, args ...any)
+	now   func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewResponseCache returns a cache kept in dir that answers with responses younger than ttl; debug, if set, is told
+// about hits and invalidations.
+func NewResponseCache(dir string, ttl time.Duration, debug func(format string, args ...any)) *ResponseCache {
+	return &ResponseCache{dir: dir, ttl: ttl, debug: debug, now: time.Now, seen: map[string]bool{}}
+}
+
+// Wrap returns a transport that answers GET requests from the cache when it can and sends everything else through
+// base; use it with WithRoundTripper.
+func (rc *ResponseCache) Wrap(base http.RoundTripper) http.RoundTripper {
+	return &cachingTransport{base: base, cache: rc}
+}
+
+// Clear removes every cached response, of every server, and returns how many there were.
+func (rc *ResponseCache) Clear() (int, error) {
+	n := 0
+	err := filepath.WalkDir(rc.dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".json") {
+			n++
+		}
+		return err
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, os.RemoveAll(rc.dir)
+}
+
+func (rc *ResponseCache) log(format string, args ...any) {
+	if rc.debug != nil {
+		rc.debug(format, args...)
+	}
+}
+
+// cachedResponse is a cache entry: a response body with what is needed to answer with it again.
+type cachedResponse struct {
+	URL         string    `json:"url"`
+	Stored      time.Time `json:"stored"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body"`
+}
+
+// serverDir is where the responses of req's server are kept.
+func (rc *ResponseCache) serverDir(req *http.Request) string {
+	sum := sha256.Sum256([]byte(requestOrigin(req)))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:12]))
+}
+
+// entryPath is where the response to req is kept: under its server and, hashed, its credentials, so one user's
+// responses are never shown to another.
+func (rc *ResponseCache) entryPath(req *http.Request) string {
+	cred := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	sum := sha256.Sum256([]byte(req.URL.RequestURI() + "\n" + req.Header.Get("Accept")))
+	return filepath.Join(rc.serverDir(req), hex.EncodeToString(cred[:12]), hex.EncodeToString(sum[:16])+".json")
+}
+
+// firstAsk reports whether this process has not asked for path before, and notes that it now has.
+func (rc *ResponseCache) firstAsk(path string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.seen[path] {
+		return false
+	}
+	rc.seen[path] = true
+	return true
+}
+
+func (rc *ResponseCache) load(path string) (*cachedResponse, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if age := rc.now().Sub(entry.Stored); age < 0 || age >= rc.ttl {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes entry through a temporary file, so concurrent commands never read half of one.
+func (rc *ResponseCache) store(path string, entry cachedResponse) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		rc.log("Response cache: %v", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		rc.log("Response cache: %v", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		rc.log("Response cache: %v", err)
+	}
+}
+
+func cacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for _, p := range uncachedPaths {
+		if strings.Contains(req.URL.Path, p) {
+			return false
+		}
+	}
+	return true
+}
+
+type cachingTransport struct {
+	base  http.RoundTripper
+	cache *ResponseCache
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rc := t.cache
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.base.RoundTrip(req)
+		// Even a failed write may have reached the server.
+		if err := os.RemoveAll(rc.serverDir(req)); err != nil {
+			rc.log("Response cache: %v", err)
+		} else {
+			rc.log("Response cache: dropped after %s %s", req.Method, req.URL.Path)
+		}
+		return resp, err
+	}
+	if !cacheable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	path := rc.entryPath(req)
+	if rc.firstAsk(path) {
+		if entry, ok := rc.load(path); ok {
+			rc.log("Response cache: %s %s from %s ago", req.Method, req.URL.RequestURI(), rc.now().Sub(entry.Stored).Round(time.Second))
+			return entry.response(req), nil
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || len(resp.Header.Values("Set-Cookie")) > 0 {
+		return resp, err
+	}
+	decodeContentEncoding(resp)
+	if resp.ContentLength > maxCachedBody {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	rc.store(path, cachedResponse{
+		URL:         req.URL.RequestURI(),
+		Stored:      rc.now(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	return resp, nil
+}
+
+// response rebuilds the cached response to req. It carries no Date header, so a cached answer is never mistaken
+// for the server's current time.
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
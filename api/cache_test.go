@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	hits := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Date", "Mon, 02 Jan 2006 15:04:05 GMT")
+		switch r.URL.Path {
+		case "/app/rest/builds/id:1":
+			_, _ = w.Write([]byte(`{"id":1,"number":"42"}`))
+		case "/app/rest/users/current":
+			_, _ = w.Write([]byte(`{"username":"alice"}`))
+		case "/app/rest/buildQueue":
+			_, _ = w.Write([]byte(`{"id":2}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	served := func(key string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[key]
+	}
+
+	dir := t.TempDir()
+	now := time.Now()
+	// Each call is a new process: a fresh cache over the same directory.
+	newClient := func(token string, ttl time.Duration) *Client {
+		rc := NewResponseCache(dir, ttl, nil)
+		rc.now = func() time.Time { return now }
+		var clock []time.Time
+		return NewClient(ts.URL, token, WithRoundTripper(rc.Wrap), WithServerClock(func(server, _ time.Time) {
+			clock = append(clock, server)
+			assert.Len(t, clock, 1, "a cached response carries no Date")
+		}))
+	}
+	getBuild := func(c *Client) {
+		t.Helper()
+		build, err := c.GetBuild(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, "42", build.Number)
+	}
+
+	getBuild(newClient("token-a", time.Minute))
+	require.Equal(t, 1, served("GET /app/rest/builds/id:1"))
+
+	c := newClient("token-a", time.Minute)
+	getBuild(c)
+	assert.Equal(t, 1, served("GET /app/rest/builds/id:1"), "a new process reads the cache")
+	getBuild(c)
+	assert.Equal(t, 2, served("GET /app/rest/builds/id:1"), "asking again in one process polls the server")
+
+	getBuild(newClient("token-b", time.Minute))
+	assert.Equal(t, 3, served("GET /app/rest/builds/id:1"), "other credentials have a cache of their own")
+
+	now = now.Add(2 * time.Minute)
+	getBuild(newClient("token-a", time.Minute))
+	assert.Equal(t, 4, served("GET /app/rest/builds/id:1"), "an expired entry is fetched again")
+	getBuild(newClient("token-b", time.Minute))
+	require.Equal(t, 5, served("GET /app/rest/builds/id:1"))
+
+	for range 2 {
+		_, err := newClient("token-a", time.Minute).GetCurrentUser()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, served("GET /app/rest/users/current"), "who the caller is is never cached")
+
+	resp, err := newClient("token-a", time.Minute).doRequest(t.Context(), "POST", "/app/rest/buildQueue", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	getBuild(newClient("token-a", time.Minute))
+	assert.Equal(t, 6, served("GET /app/rest/builds/id:1"), "a write drops the server's cached responses")
+	getBuild(newClient("token-b", time.Minute))
+	assert.Equal(t, 7, served("GET /app/rest/builds/id:1"), "whoever wrote")
+
+	n, err := NewResponseCache(dir, 0, nil).Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	getBuild(newClient("token-b", time.Minute))
+	assert.Equal(t, 8, served("GET /app/rest/builds/id:1"))
+}
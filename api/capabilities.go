@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// CapabilityStatus says whether the server supports a capability; unknown means it could not be determined (no access, server error, offline).
+type CapabilityStatus string
+
+const (
+	CapabilitySupported   CapabilityStatus = "supported"
+	CapabilityUnsupported CapabilityStatus = "unsupported"
+	CapabilityUnknown     CapabilityStatus = "unknown"
+)
+
+// versionFeatures are the SupportsFeature names reported as capabilities.
+var versionFeatures = []string{"csrf_token", "pipelines", "vcs_test_connection"}
+
+// capabilityProbes check the endpoints the CLI only uses when present, each with one or two tiny GETs.
+var capabilityProbes = map[string]func(c *Client, ctx context.Context) CapabilityStatus{
+	"pipelines_api": func(c *Client, ctx context.Context) CapabilityStatus {
+		return c.probeEndpoint(ctx, "/app/rest/pipelines?locator=count:1&fields=count")
+	},
+	// The tokens endpoint only accepts POST; an existing endpoint answers GET with 405.
+	"secure_tokens": func(c *Client, ctx context.Context) CapabilityStatus {
+		return c.probeEndpoint(ctx, "/app/rest/projects/id:_Root/secure/tokens")
+	},
+	// A missing build and a missing endpoint both 404, so these probe a real build when there is one.
+	"structured_log_messages": func(c *Client, ctx context.Context) CapabilityStatus {
+		return c.probeWithBuild(ctx, "/app/rest/builds?locator=defaultFilter:false,count:1&fields=build(id)", func(id string) string {
+			return "/app/messages?" + url.Values{"buildId": {id}, "messagesCount": {"0,0"}}.Encode()
+		})
+	},
+	"build_approvals": func(c *Client, ctx context.Context) CapabilityStatus {
+		return c.probeWithBuild(ctx, "/app/rest/buildQueue?locator=count:1&fields=build(id)", func(id string) string {
+			return "/app/rest/buildQueue/id:" + id + "/approval"
+		})
+	},
+}
+
+// CapabilityNames lists every capability Capabilities reports, sorted; callers that skip the server report these as unknown.
+func CapabilityNames() []string {
+	names := append(slices.Clone(versionFeatures), slices.Collect(maps.Keys(capabilityProbes))...)
+	slices.Sort(names)
+	return names
+}
+
+// capabilityCache memoizes Capabilities across copies of a Client, like serverInfoCache.
+type capabilityCache struct {
+	once sync.Once
+	caps map[string]CapabilityStatus
+}
+
+// Capabilities reports what the server supports: version-gated features from SupportsFeature plus a probe per conditionally used endpoint.
+// Probes run concurrently and once per Client; failures are reported as unknown rather than returned.
+func (c *Client) Capabilities(ctx context.Context) map[string]CapabilityStatus {
+	c.capabilities.once.Do(func() {
+		caps := make(map[string]CapabilityStatus, len(versionFeatures)+len(capabilityProbes))
+		_, verErr := c.ServerVersion()
+		for _, name := range versionFeatures {
+			switch {
+			case verErr != nil:
+				caps[name] = CapabilityUnknown
+			case c.SupportsFeature(name):
+				caps[name] = CapabilitySupported
+			default:
+				caps[name] = CapabilityUnsupported
+			}
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for name, probe := range capabilityProbes {
+			wg.Go(func() {
+				status := probe(c, ctx)
+				mu.Lock()
+				caps[name] = status
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+		c.capabilities.caps = caps
+	})
+	return c.capabilities.caps
+}
+
+// probeEndpoint classifies path by the status of a GET: 404 means the server lacks it; 400 and 405 mean it exists but wants other input.
+func (c *Client) probeEndpoint(ctx context.Context, path string) CapabilityStatus {
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return CapabilityUnknown
+	}
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300,
+		resp.StatusCode == http.StatusBadRequest,
+		resp.StatusCode == http.StatusMethodNotAllowed:
+		return CapabilitySupported
+	case resp.StatusCode == http.StatusNotFound:
+		return CapabilityUnsupported
+	default:
+		return CapabilityUnknown
+	}
+}
+
+// probeWithBuild probes the path built from the first build listPath returns; with no build to probe, the capability is unknown.
+func (c *Client) probeWithBuild(ctx context.Context, listPath string, probePath func(id string) string) CapabilityStatus {
+	var list BuildList
+	if err := c.get(ctx, listPath, &list); err != nil || len(list.Builds) == 0 {
+		return CapabilityUnknown
+	}
+	return c.probeEndpoint(ctx, probePath(strconv.Itoa(list.Builds[0].ID)))
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, http.MethodGet, r.Method, "probes must not change anything")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/app/rest/server":
+			json.NewEncoder(w).Encode(Server{Version: "2023.11", VersionMajor: 2023, VersionMinor: 11})
+		case r.URL.Path == "/app/rest/pipelines":
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/secure/tokens"):
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/app/rest/builds":
+			json.NewEncoder(w).Encode(BuildList{Count: 1, Builds: []Build{{ID: 7}}})
+		case r.URL.Path == "/app/messages":
+			assert.Equal(t, "7", r.URL.Query().Get("buildId"))
+			json.NewEncoder(w).Encode(BuildMessagesResponse{})
+		case r.URL.Path == "/app/rest/buildQueue":
+			json.NewEncoder(w).Encode(BuildQueue{})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	caps := client.Capabilities(t.Context())
+	assert.Equal(t, map[string]CapabilityStatus{
+		"csrf_token":              CapabilitySupported,
+		"pipelines":               CapabilityUnsupported,
+		"vcs_test_connection":     CapabilityUnsupported,
+		"pipelines_api":           CapabilityUnsupported,
+		"secure_tokens":           CapabilitySupported,
+		"structured_log_messages": CapabilitySupported,
+		"build_approvals":         CapabilityUnknown, // empty queue: nothing to probe
+	}, caps)
+	assert.ElementsMatch(t, CapabilityNames(), slices.Collect(maps.Keys(caps)))
+
+	sent := requests.Load()
+	client.WithContext(t.Context()).Capabilities(t.Context())
+	assert.Equal(t, sent, requests.Load(), "capabilities are probed once per client")
+}
+
+func TestCapabilities_unreachable(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	for name, status := range client.Capabilities(t.Context()) {
+		assert.Equal(t, CapabilityUnknown, status, name)
+	}
+	require.Len(t, client.Capabilities(t.Context()), len(CapabilityNames()))
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CassetteVersion is the format version written to new cassettes.
+const CassetteVersion = 1
+
+// maxCassetteBody caps each recorded body; the rest is dropped and the body is marked truncated.
+const maxCassetteBody = 64 << 10
+
+// cassetteServer stands in for the recorded server's origin in cassette bodies and headers, so a cassette
+// names no host and replays against any base URL.
+const cassetteServer = "{{server}}"
+
+// openSecretJSONValue matches a secret JSON member whose value a truncated body cuts off; secretJSONValue
+// only masks complete values, and the cut part of a secret must not be kept either.
+var openSecretJSONValue = regexp.MustCompile(`(?i)("[^"]*(?:password|passwd|token|secret)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*\\?$`)
+
+// cassetteHeaders are the only headers a cassette keeps; everything else, credentials included, is dropped.
+var cassetteHeaders = []string{"Accept", "Content-Type", "Location", "Retry-After"}
+
+// Cassette is a recording of HTTP interactions with a TeamCity server, in the order they happened.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and the server's response to it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized request. URL is the path and query without scheme, host, or credentials.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a sanitized response. A body that is not UTF-8 text is kept in BinaryBody instead of Body.
+type RecordedResponse struct {
+	Status     int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BinaryBody []byte      `json:"binaryBody,omitempty"`
+	Truncated  bool        `json:"truncated,omitempty"`
+}
+
+// LoadCassette reads a cassette written by a Recorder.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Recorder writes every request made through its transports, with the response, to a cassette file.
+// Credentials are never recorded, secrets in bodies are masked, and bodies are capped at 64 KiB.
+// The file is rewritten after each interaction, so it is complete whenever the process exits.
+type Recorder struct {
+	path     string
+	mu       sync.Mutex
+	cassette Cassette
+	err      error
+}
+
+// NewRecorder returns a Recorder that writes to path, replacing any cassette already there.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path, cassette: Cassette{Version: CassetteVersion, Interactions: []Interaction{}}}
+}
+
+// Wrap returns a transport that sends requests through base and records them; use it with WithRoundTripper.
+func (r *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{base: base, recorder: r}
+}
+
+// Err returns the first error writing the cassette, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) add(i Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err == nil {
+		err = os.WriteFile(r.path, append(data, '\n'), 0o600)
+	}
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordRequest(req)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	// Decode here so the cassette holds what callers read, not what the wire carried.
+	decodeContentEncoding(resp)
+	origin := requestOrigin(req)
+	rec := &recordingBody{ReadCloser: resp.Body, size: resp.ContentLength, done: func(body []byte, truncated bool) {
+		t.recorder.add(Interaction{Request: recorded, Response: recordResponse(req, resp, origin, body, truncated)})
+	}}
+	resp.Body = rec
+	return resp, nil
+}
+
+// recordingBody copies the first maxCassetteBody bytes the caller reads and records them when the body is
+// closed or read to the end, so recording neither buffers a stream nor changes when the caller sees its data.
+// A body closed before its end is marked truncated, since the rest was never read.
+type recordingBody struct {
+	io.ReadCloser
+	size      int64 // Content-Length, or -1 when unknown
+	read      int64
+	eof       bool
+	buf       bytes.Buffer
+	truncated bool
+	once      sync.Once
+	done      func(body []byte, truncated bool)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if room := maxCassetteBody - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+		if n > room {
+			b.truncated = true
+		}
+	} else if n > 0 {
+		b.truncated = true
+	}
+	if err == io.EOF {
+		b.eof = true
+		b.record()
+	}
+	return n, err
+}
+
+func (b *recordingBody) record() {
+	b.once.Do(func() { b.done(b.buf.Bytes(), b.truncated) })
+}
+
+func (b *recordingBody) Close() error {
+	if !b.eof && (b.size < 0 || b.read < b.size) {
+		b.truncated = true
+	}
+	b.record()
+	return b.ReadCloser.Close()
+}
+
+func recordRequest(req *http.Request) RecordedRequest {
+	rec := RecordedRequest{Method: req.Method, URL: req.URL.RequestURI(), Headers: keepHeaders(req.Header)}
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return rec
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return rec
+	}
+	defer func() { _ = rc.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(rc, maxCassetteBody+1))
+	switch {
+	case len(body) > maxCassetteBody:
+		rec.Body = fmt.Sprintf("[%d+ bytes]", maxCassetteBody)
+	case !isTextBody(req.Header, body):
+		rec.Body = fmt.Sprintf("[%d bytes]", len(body))
+	default:
+		rec.Body = scrubBody(req.Method, req.URL.Path, body)
+	}
+	return rec
+}
+
+func recordResponse(req *http.Request, resp *http.Response, origin string, body []byte, truncated bool) RecordedResponse {
+	rec := RecordedResponse{Status: resp.StatusCode, Headers: keepHeaders(resp.Header), Truncated: truncated}
+	if loc := rec.Headers.Get("Location"); loc != "" && origin != "" {
+		rec.Headers.Set("Location", strings.ReplaceAll(loc, origin, cassetteServer))
+	}
+	switch {
+	case len(body) == 0:
+	case sensitiveResponse(req):
+		rec.Body = redacted
+	case isTextBody(resp.Header, trimPartialRune(body, truncated)):
+		text := scrubSecrets(string(trimPartialRune(body, truncated)))
+		if truncated {
+			text = openSecretJSONValue.ReplaceAllString(text, `${1}"`+redacted)
+		}
+		if origin != "" {
+			text = strings.ReplaceAll(text, origin, cassetteServer)
+		}
+		rec.Body = text
+	default:
+		rec.BinaryBody = body
+	}
+	return rec
+}
+
+// sensitiveResponse reports whether a response body is a secret as a whole: secure values and new access tokens.
+func sensitiveResponse(req *http.Request) bool {
+	path := req.URL.Path
+	return strings.Contains(path, "/secure/values/") ||
+		(req.Method == http.MethodPost && (strings.HasSuffix(path, "/tokens") || strings.Contains(path, "/tokens/")))
+}
+
+// trimPartialRune drops the incomplete UTF-8 sequence a truncated body may end with.
+func trimPartialRune(body []byte, truncated bool) []byte {
+	if !truncated {
+		return body
+	}
+	for cut := 0; cut < utf8.UTFMax && len(body) > cut; cut++ {
+		if utf8.Valid(body[:len(body)-cut]) {
+			return body[:len(body)-cut]
+		}
+	}
+	return body
+}
+
+func keepHeaders(h http.Header) http.Header {
+	var kept http.Header
+	for _, name := range cassetteHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			if kept == nil {
+				kept = http.Header{}
+			}
+			kept[name] = v
+		}
+	}
+	return kept
+}
+
+func isTextBody(h http.Header, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	textual := mediaType == "" || mediaType == "application/json" || strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "xml")
+	return textual && utf8.Valid(body)
+}
+
+// requestOrigin returns scheme://host of req, or "" when it has none.
+func requestOrigin(req *http.Request) string {
+	if req.URL.Host == "" {
+		return ""
+	}
+	return req.URL.Scheme + "://" + req.URL.Host
+}
+
+// Replayer returns a transport that answers requests from c instead of a server. Requests are matched by
+// method and URL; repeated requests get the recorded responses in order, and the last one once they run out,
+// which keeps polling commands working. A request the cassette has no response for fails.
+func (c *Cassette) Replayer() http.RoundTripper {
+	return &replayTransport{cassette: c, served: map[string]int{}}
+}
+
+type replayTransport struct {
+	cassette *Cassette
+	mu       sync.Mutex
+	served   map[string]int
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	key := req.Method + " " + req.URL.RequestURI()
+	t.mu.Lock()
+	var matches []*Interaction
+	for i := range t.cassette.Interactions {
+		in := &t.cassette.Interactions[i]
+		if in.Request.Method+" "+in.Request.URL == key {
+			matches = append(matches, in)
+		}
+	}
+	n := t.served[key]
+	t.served[key]++
+	t.mu.Unlock()
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("cassette has no response for %s", key)
+	}
+	rec := matches[min(n, len(matches)-1)].Response
+	body := rec.BinaryBody
+	if body == nil {
+		body = []byte(strings.ReplaceAll(rec.Body, cassetteServer, requestOrigin(req)))
+	}
+	header := rec.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if loc := header.Get("Location"); loc != "" {
+		header.Set("Location", strings.ReplaceAll(loc, cassetteServer, requestOrigin(req)))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
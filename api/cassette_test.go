@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/rest/builds/id:1":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "TCSESSIONID=abc")
+			_, _ = w.Write([]byte(`{"id":1,"webUrl":"http://` + r.Host + `/viewLog.html?buildId=1","password":"hunter2"}`))
+		case "/app/rest/projects/P/secure/values/tok":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("s3cret"))
+		case "/app/rest/builds/id:1/log":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			_, _ = gz.Write([]byte("step 1\n" + strings.Repeat("x", maxCassetteBody)))
+			_ = gz.Close()
+		case "/app/rest/builds/id:1/artifacts/files/app.bin":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	ts := recordedServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec := NewRecorder(path)
+	client := NewClient(ts.URL, "secret-token", WithRoundTripper(rec.Wrap))
+
+	build, err := client.GetBuild(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, ts.URL+"/viewLog.html?buildId=1", build.WebURL, "recording leaves the caller's response alone")
+	_, err = client.GetSecureValue("P", "tok")
+	require.NoError(t, err)
+	resp, err := client.doRequestFull(t.Context(), "POST", "/app/rest/projects/P/parameters", strings.NewReader(`{"name":"x","value":"y","token":"abc"}`), "application/json", "application/json")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	resp, err = client.doRequest(t.Context(), "GET", "/app/rest/builds/id:1/log", nil)
+	require.NoError(t, err)
+	var log bytes.Buffer
+	_, _ = log.ReadFrom(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, len("step 1\n")+maxCassetteBody, log.Len(), "the caller reads the whole body")
+	resp, err = client.doRequest(t.Context(), "GET", "/app/rest/builds/id:1/artifacts/files/app.bin", nil)
+	require.NoError(t, err)
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, rec.Err())
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 5)
+	assert.Equal(t, CassetteVersion, cassette.Version)
+
+	build1 := cassette.Interactions[0]
+	assert.Equal(t, "/app/rest/builds/id:1", build1.Request.URL)
+	assert.Empty(t, build1.Request.Headers.Get("Authorization"))
+	assert.Empty(t, build1.Response.Headers.Get("Set-Cookie"))
+	assert.Equal(t, `{"id":1,"webUrl":"{{server}}/viewLog.html?buildId=1","password":"[REDACTED]"}`, build1.Response.Body)
+
+	assert.Equal(t, redacted, cassette.Interactions[1].Response.Body, "secure values are never recorded")
+	assert.Equal(t, `{"name":"x","value":"y","token":"[REDACTED]"}`, cassette.Interactions[2].Request.Body)
+
+	logResp := cassette.Interactions[3].Response
+	assert.True(t, logResp.Truncated)
+	assert.Len(t, logResp.Body, maxCassetteBody)
+	assert.True(t, strings.HasPrefix(logResp.Body, "step 1\n"), "the cassette holds the decoded body")
+
+	assert.Equal(t, []byte{0xff, 0xfe, 0x00}, cassette.Interactions[4].Response.BinaryBody)
+	assert.False(t, cassette.Interactions[4].Response.Truncated)
+
+	for _, in := range cassette.Interactions {
+		assert.NotContains(t, in.Response.Body, "secret-token")
+		assert.NotContains(t, in.Response.Body, ts.URL)
+	}
+}
+
+func TestRecorderMasksCutSecret(t *testing.T) {
+	t.Parallel()
+	body := []byte(`{"items":[` + strings.Repeat(`{"n":1},`, 10) + `{"password":"hunter2`)
+	req := httptest.NewRequest("GET", "/app/rest/anything", nil)
+	resp := &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}}
+
+	rec := recordResponse(req, resp, "", body, true)
+	assert.NotContains(t, rec.Body, "hunter2")
+	assert.True(t, strings.HasSuffix(rec.Body, `"password":"[REDACTED]`))
+}
+
+func TestReplayer(t *testing.T) {
+	t.Parallel()
+	cassette := &Cassette{Version: CassetteVersion, Interactions: []Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: "/app/rest/builds/id:1"},
+			Response: RecordedResponse{Status: 200, Headers: http.Header{"Content-Type": {"application/json"}}, Body: `{"id":1,"state":"running","webUrl":"{{server}}/viewLog.html?buildId=1"}`}},
+		{Request: RecordedRequest{Method: "GET", URL: "/app/rest/builds/id:1"},
+			Response: RecordedResponse{Status: 200, Headers: http.Header{"Content-Type": {"application/json"}}, Body: `{"id":1,"state":"finished"}`}},
+		{Request: RecordedRequest{Method: "GET", URL: "/app/rest/builds/id:2"},
+			Response: RecordedResponse{Status: 404, Headers: http.Header{"Content-Type": {"application/json"}}, Body: `{"errors":[{"message":"No build found by locator 'id:2'"}]}`}},
+	}}
+	replayer := cassette.Replayer()
+	client := NewClient("https://replay.example", "t", WithRoundTripper(func(http.RoundTripper) http.RoundTripper { return replayer }))
+
+	first, err := client.GetBuild(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "running", first.State)
+	assert.Equal(t, "https://replay.example/viewLog.html?buildId=1", first.WebURL)
+
+	for range 2 {
+		next, err := client.GetBuild(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, "finished", next.State, "repeated requests get later responses, then the last one")
+	}
+
+	_, err = client.GetBuild(t.Context(), "2")
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	_, err = client.GetBuild(t.Context(), "3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cassette has no response for GET /app/rest/builds/id:3")
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ChangesOptions selects changes by ID or by commit SHA; BuildTypeID narrows them to the VCS roots of one build configuration.
+type ChangesOptions struct {
+	ID          int
+	Version     string
+	BuildTypeID string
+}
+
+const changeFields = "count,change(id,version,username,date,comment,webUrl,files(file(file,changeType)),vcsRootInstance(name,vcs-root-id),parentRevisions(item))"
+
+// GetChanges returns the changes matching opts. One commit is a separate change in each VCS root that fetches it,
+// so a SHA can match several.
+func (c *Client) GetChanges(ctx context.Context, opts ChangesOptions) (*ChangeList, error) {
+	locator := NewLocator().
+		AddInt("id", opts.ID).
+		Add("version", opts.Version).
+		Add("buildType", opts.BuildTypeID)
+	path := fmt.Sprintf("/app/rest/changes?locator=%s&fields=%s", locator.Encode(), url.QueryEscape(changeFields))
+
+	var changes ChangeList
+	if err := c.get(ctx, path, &changes); err != nil {
+		return nil, err
+	}
+	if changes.Change == nil {
+		changes.Change = []Change{} // non-nil so --json emits [] not null
+	}
+
+	return &changes, nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChanges(t *testing.T) {
+	t.Parallel()
+	var seenLocator string
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/changes", r.URL.Path)
+		seenLocator = r.URL.Query().Get("locator")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChangeList{Count: 2, Change: []Change{
+			{ID: 10, Version: "abc123", VcsRootInstance: &VcsRootInstanceRef{VcsRootID: "Falcon_GitHub"}},
+			{ID: 11, Version: "abc123", VcsRootInstance: &VcsRootInstanceRef{VcsRootID: "Falcon_Mirror"}},
+		}})
+	})
+
+	result, err := client.GetChanges(t.Context(), ChangesOptions{Version: "abc123", BuildTypeID: "Falcon_Build"})
+	require.NoError(t, err)
+	assert.Equal(t, "version:abc123,buildType:Falcon_Build", seenLocator)
+	require.Len(t, result.Change, 2)
+	assert.Equal(t, "Falcon_Mirror", result.Change[1].VcsRootInstance.VcsRootID)
+}
+
+func TestGetChangesEmpty(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":0}`))
+	})
+
+	result, err := client.GetChanges(t.Context(), ChangesOptions{ID: 42})
+	require.NoError(t, err)
+	assert.NotNil(t, result.Change)
+	assert.Empty(t, result.Change)
+}
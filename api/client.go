@@ -3,14 +3,12 @@ package api
 import (
 	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"mime"
 	"net/http"
-	"runtime"
 	"slices"
 	"strings"
 	"sync"
@@ -64,9 +62,21 @@ type Client struct {
 	// serverInfo is a pointer so WithContext copies share the cache instead of copying sync.Once.
 	serverInfo *serverInfoCache
 
+	// capabilities is shared by WithContext copies for the same reason; see Capabilities.
+	capabilities *capabilityCache
+
 	// extraHeaders is set on every outgoing request via WithExtraHeaders.
 	// Names are canonical-cased; values are scrubbed of CR/LF/NUL at construction.
 	extraHeaders map[string]string
+
+	// serverClock receives the Date header of every response; set via WithServerClock.
+	serverClock func(server, local time.Time)
+
+	// cliContext is sent as HeaderCLIContext; set via WithCLIContext.
+	cliContext string
+
+	// retries overrides ReadRetry for reads; set via WithRetries.
+	retries *RetryConfig
 }
 
 // serverInfoCache memoizes the result of GetServer across copies of a Client.
@@ -92,6 +102,9 @@ func (c *Client) debugLogRequest(req *http.Request) {
 	if req.ContentLength > 0 {
 		c.debugLog("> Content-Length: %d", req.ContentLength)
 	}
+	if body := debugBody(req); body != "" {
+		c.debugLog("> %s", body)
+	}
 }
 
 func (c *Client) debugLogResponse(resp *http.Response) {
@@ -99,6 +112,7 @@ func (c *Client) debugLogResponse(resp *http.Response) {
 		return
 	}
 	c.debugLog("< %s %s", resp.Proto, resp.Status)
+	c.debugLogTiming(resp)
 	c.debugLogHeaders("<", resp.Header)
 }
 
@@ -184,6 +198,48 @@ func WithExtraHeaders(h map[string]string) ClientOption {
 	}
 }
 
+// WithCLIContext labels every request with HeaderCLIContext for audit attribution; labels failing ValidateCLIContext are ignored.
+func WithCLIContext(label string) ClientOption {
+	return func(c *Client) {
+		if ValidateCLIContext(label) == nil {
+			c.cliContext = label
+		}
+	}
+}
+
+// WithServerClock reports the server's time from the Date header of each response, with the local time it arrived.
+func WithServerClock(fn func(server, local time.Time)) ClientOption {
+	return func(c *Client) {
+		c.serverClock = fn
+	}
+}
+
+// WithRetries sets how many times reads are retried on transient failures and the initial backoff, which doubles,
+// with jitter, on each retry; 0 retries disables retrying, and a non-positive backoff keeps ReadRetry's. Writes
+// are never retried unless the caller opts in.
+func WithRetries(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		cfg := RetryConfig{MaxRetries: uint(max(maxRetries, 0)), Interval: backoff}
+		if cfg.Interval <= 0 {
+			cfg.Interval = ReadRetry.Interval
+		}
+		c.retries = &cfg
+	}
+}
+
+// WithRoundTripper replaces the client's transport with wrap(current); every request path, streams and
+// downloads included, goes through it. Recorder.Wrap records traffic, and Cassette.Replayer ignores the
+// current transport to answer from a recording.
+func WithRoundTripper(wrap func(base http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		base := c.HTTPClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		c.HTTPClient.Transport = wrap(base)
+	}
+}
+
 // newClientBase returns a Client populated with shared defaults: trimmed BaseURL, default HTTPClient, env extras.
 func newClientBase(baseURL string) *Client {
 	return &Client{
@@ -193,6 +249,7 @@ func newClientBase(baseURL string) *Client {
 			Transport: defaultTransport(),
 		},
 		serverInfo:   &serverInfoCache{},
+		capabilities: &capabilityCache{},
 		extraHeaders: EnvHeaders(),
 	}
 }
@@ -219,15 +276,25 @@ func NewClientWithBasicAuth(baseURL, username, password string, opts ...ClientOp
 }
 
 // NewGuestClient creates a TeamCity API client using /guestAuth/ paths; TEAMCITY_HEADER_* env vars are honored.
+// A guest client is always read-only, whatever WithReadOnly says.
 func NewGuestClient(baseURL string, opts ...ClientOption) *Client {
 	c := newClientBase(baseURL)
 	c.guestAuth = true
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.ReadOnly = true
 	return c
 }
 
+// readOnlyError is the error a blocked write wraps: guest clients say why they cannot write.
+func (c *Client) readOnlyError() error {
+	if c.guestAuth {
+		return ErrGuestReadOnly
+	}
+	return ErrReadOnly
+}
+
 // apiPath returns the API path, optionally with version prefix
 func (c *Client) apiPath(path string) string {
 	if !strings.HasPrefix(path, "/") {
@@ -247,7 +314,7 @@ func (c *Client) cliVersion() string {
 }
 
 func (c *Client) userAgent() string {
-	return fmt.Sprintf("teamcity-cli/%s (%s; %s)", c.cliVersion(), runtime.GOOS, runtime.GOARCH)
+	return UserAgent(c.cliVersion())
 }
 
 func (c *Client) teamCityClientHeader() string {
@@ -268,10 +335,14 @@ func (c *Client) SetCommandName(name string) {
 	c.commandName = name
 }
 
-// applyStandardHeaders sets User-Agent, X-TeamCity-Client, and any WithExtraHeaders extras on req.
+// applyStandardHeaders sets User-Agent, X-TeamCity-Client, the CLI context label, and any WithExtraHeaders extras on req.
 func (c *Client) applyStandardHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("X-TeamCity-Client", c.teamCityClientHeader())
+	// Belt and braces: a label that is the credential itself never leaves, whatever ValidateCLIContext let through.
+	if c.cliContext != "" && c.cliContext != c.Token && c.cliContext != c.basicPass {
+		req.Header.Set(HeaderCLIContext, c.cliContext)
+	}
 	for k, v := range c.extraHeaders {
 		req.Header.Set(k, v)
 	}
@@ -361,7 +432,7 @@ func (c *Client) doRequestWithAccept(ctx context.Context, method, path string, b
 
 func (c *Client) doRequestFull(ctx context.Context, method, path string, body io.Reader, contentType, accept string) (*http.Response, error) {
 	if c.ReadOnly && method != "GET" {
-		return nil, fmt.Errorf("%w: %s %s", ErrReadOnly, method, path)
+		return nil, fmt.Errorf("%w: %s %s", c.readOnlyError(), method, path)
 	}
 
 	reqURL := fmt.Sprintf("%s%s", c.BaseURL, c.apiPath(path))
@@ -380,23 +451,51 @@ func (c *Client) doRequestFull(ctx context.Context, method, path string, body io
 
 	c.debugLogRequest(req)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.HTTPClient.Do(c.traceTiming(req))
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	c.debugLogResponse(resp)
+	c.observeServerClock(resp)
+	decodeContentEncoding(resp)
 
 	return resp, nil
 }
 
+func (c *Client) observeServerClock(resp *http.Response) {
+	if c.serverClock == nil {
+		return
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		c.serverClock(t, time.Now())
+	}
+}
+
 func (c *Client) get(ctx context.Context, path string, result any) error {
-	return c.getWithRetry(ctx, path, result, ReadRetry)
+	return c.getWithRetry(ctx, path, result, c.readRetry())
+}
+
+// readRetry is the retry policy for reads: the one set by WithRetries, or ReadRetry.
+func (c *Client) readRetry() RetryConfig {
+	if c.retries != nil {
+		return *c.retries
+	}
+	return ReadRetry
 }
 
-// doGetStream GETs with ReadRetry and returns the raw 2xx response; non-2xx → typed api error.
+// retry runs op under cfg like withRetry, logging each retry of method path through DebugFunc.
+func (c *Client) retry(ctx context.Context, cfg RetryConfig, method, path string, op func() (*http.Response, error)) (*http.Response, error) {
+	attempt := uint(0)
+	return withRetryNotify(ctx, cfg, func(err error, wait time.Duration) {
+		attempt++
+		c.debugLog("Retry %d/%d of %s %s in %s: %v", attempt, cfg.MaxRetries, method, path, wait.Round(time.Millisecond), err)
+	}, op)
+}
+
+// doGetStream GETs with the read retry policy and returns the raw 2xx response; non-2xx → typed api error.
 func (c *Client) doGetStream(ctx context.Context, path string) (*http.Response, error) {
-	resp, err := withRetry(ctx, ReadRetry, func() (*http.Response, error) {
+	resp, err := c.retry(ctx, c.readRetry(), "GET", path, func() (*http.Response, error) {
 		return c.doRequest(ctx, "GET", path, nil)
 	})
 	if err != nil {
@@ -416,13 +515,13 @@ func (c *Client) doGetStream(ctx context.Context, path string) (*http.Response,
 	return resp, nil
 }
 
-// streamRequest GETs path with ReadRetry on a copy of c.HTTPClient that omits the wall-clock Timeout but preserves Transport/Jar/CheckRedirect; intended for endpoints with large or open-ended response bodies (build logs, artifacts).
+// streamRequest GETs path with the read retry policy on a copy of c.HTTPClient that omits the wall-clock Timeout but preserves Transport/Jar/CheckRedirect; intended for endpoints with large or open-ended response bodies (build logs, artifacts).
 func (c *Client) streamRequest(ctx context.Context, path string) (*http.Response, error) {
 	reqURL := fmt.Sprintf("%s%s", c.BaseURL, c.apiPath(path))
 	streamClient := *c.HTTPClient
 	streamClient.Timeout = 0
 
-	resp, err := withRetry(ctx, ReadRetry, func() (*http.Response, error) {
+	resp, err := c.retry(ctx, c.readRetry(), "GET", path, func() (*http.Response, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 		if err != nil {
 			return nil, err
@@ -430,7 +529,7 @@ func (c *Client) streamRequest(ctx context.Context, path string) (*http.Response
 		c.setAuth(req)
 		c.applyStandardHeaders(req)
 		c.debugLogRequest(req)
-		return streamClient.Do(req)
+		return streamClient.Do(c.traceTiming(req))
 	})
 	if err != nil {
 		if resp != nil {
@@ -443,6 +542,7 @@ func (c *Client) streamRequest(ctx context.Context, path string) (*http.Response
 		return nil, &NetworkError{URL: c.BaseURL, Cause: err}
 	}
 	c.debugLogResponse(resp)
+	decodeContentEncoding(resp)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		defer func() { _ = resp.Body.Close() }()
 		return nil, c.handleErrorResponse(resp)
@@ -451,7 +551,7 @@ func (c *Client) streamRequest(ctx context.Context, path string) (*http.Response
 }
 
 func (c *Client) getWithRetry(ctx context.Context, path string, result any, retry RetryConfig) error {
-	resp, err := withRetry(ctx, retry, func() (*http.Response, error) {
+	resp, err := c.retry(ctx, retry, "GET", path, func() (*http.Response, error) {
 		return c.doRequest(ctx, "GET", path, nil)
 	})
 	if err != nil {
@@ -471,8 +571,8 @@ func (c *Client) getWithRetry(ctx context.Context, path string, result any, retr
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		if err := decodeResponse(resp.Body, path, result); err != nil {
+			return err
 		}
 	}
 
@@ -500,7 +600,7 @@ func (c *Client) post(ctx context.Context, path string, body io.Reader, result a
 
 // postWithRetry performs a POST request with configurable retry.
 func (c *Client) postWithRetry(ctx context.Context, path string, body io.Reader, result any, retry RetryConfig) error {
-	resp, err := withRetry(ctx, retry, func() (*http.Response, error) {
+	resp, err := c.retry(ctx, retry, "POST", path, func() (*http.Response, error) {
 		return c.doRequest(ctx, "POST", path, body)
 	})
 	if err != nil {
@@ -523,8 +623,8 @@ func (c *Client) postWithRetry(ctx context.Context, path string, body io.Reader,
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		if err := decodeResponse(resp.Body, path, result); err != nil {
+			return err
 		}
 	}
 
@@ -577,7 +677,7 @@ func hasHeader(headers map[string]string, name string) bool {
 // RawRequest performs a raw HTTP request and returns the response without parsing.
 func (c *Client) RawRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*RawResponse, error) {
 	if c.ReadOnly && method != "GET" {
-		return nil, fmt.Errorf("%w: %s %s", ErrReadOnly, method, path)
+		return nil, fmt.Errorf("%w: %s %s", c.readOnlyError(), method, path)
 	}
 
 	resp, err := c.doRawRequest(ctx, method, path, body, headers, "application/json")
@@ -611,8 +711,12 @@ func (c *Client) doRawRequest(ctx context.Context, method, path string, body io.
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	// Per-request headers run last so callers can override Accept / Content-Type / extras.
+	// Per-request headers run last so callers can override Accept / Content-Type / extras. Accept-Encoding is
+	// the exception: setting it stops the transport from decompressing, so the transport negotiates it instead.
 	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) == "Accept-Encoding" {
+			continue
+		}
 		req.Header.Set(k, v)
 	}
 
@@ -626,13 +730,15 @@ func (c *Client) doRawRequest(ctx context.Context, method, path string, body io.
 
 	c.debugLogRequest(req)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.HTTPClient.Do(c.traceTiming(req))
 	if err != nil {
 		return nil, &NetworkError{URL: c.BaseURL, Cause: err}
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	c.debugLogResponse(resp)
+	c.observeServerClock(resp)
+	decodeContentEncoding(resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -346,7 +346,11 @@ func TestParseTeamCityTime(T *testing.T) {
 	}{
 		{"20250710T080607+0000", time.Date(2025, 7, 10, 8, 6, 7, 0, time.UTC), false},
 		{"20240115T143022+0000", time.Date(2024, 1, 15, 14, 30, 22, 0, time.UTC), false},
+		{"20240115T143022.250+0000", time.Date(2024, 1, 15, 14, 30, 22, 250e6, time.UTC), false},
+		{"20240115T143022Z", time.Date(2024, 1, 15, 14, 30, 22, 0, time.UTC), false},
+		{"2024-01-15T16:30:22+02:00", time.Date(2024, 1, 15, 14, 30, 22, 0, time.UTC), false},
 		{"", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
 	}
 
 	for _, tc := range tests {
@@ -460,6 +464,47 @@ func TestResolveBuildID(T *testing.T) {
 		_, err := client.ResolveBuildID(T.Context(), "#42")
 		assert.Error(t, err)
 	})
+
+	T.Run("web URLs", func(t *testing.T) {
+		t.Parallel()
+
+		client := NewClient("https://example.com", "token")
+		got, err := client.ResolveBuildID(T.Context(), "https://tc.example.com/viewLog.html?buildId=12345&buildTypeId=Falcon_Build")
+		require.NoError(t, err)
+		assert.Equal(t, "12345", got)
+
+		_, err = client.ResolveBuildID(T.Context(), "https://tc.example.com/project/Falcon")
+		assert.ErrorContains(t, err, "does not link to a run")
+	})
+}
+
+func TestBuildIDFromURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref   string
+		id    string
+		isURL bool
+	}{
+		{"https://tc.example.com/viewLog.html?buildId=12345", "12345", true},
+		{"http://tc.example.com/tc/viewLog.html?buildTypeId=Falcon_Build&buildId=12345&tab=buildLog", "12345", true},
+		{"https://tc.example.com/buildConfiguration/Falcon_Build/12345", "12345", true},
+		{"https://tc.example.com/buildConfiguration/Falcon_Build/12345?buildTab=tests#all", "12345", true},
+		{"https://tc.example.com/build/12345", "12345", true},
+		{"https://tc.example.com/buildConfiguration/Falcon_Build", "", true},
+		{"https://tc.example.com/viewLog.html?buildId=abc", "", true},
+		{"12345", "", false},
+		{"#42", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			t.Parallel()
+
+			id, isURL := BuildIDFromURL(tc.ref)
+			assert.Equal(t, tc.id, id)
+			assert.Equal(t, tc.isURL, isURL)
+		})
+	}
 }
 
 func TestCleanupBuildTriggered(T *testing.T) {
@@ -655,6 +700,25 @@ func TestDebugLogging(T *testing.T) {
 		assert.Contains(t, buf.String(), "> Content-Length: 11")
 	})
 
+	T.Run("logs connect and first byte timing", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		client.DebugFunc = func(format string, args ...any) {
+			fmt.Fprintf(&buf, format+"\n", args...)
+		}
+
+		_, _ = client.RawRequest(T.Context(), "GET", "/api/test", nil, nil)
+		_, _ = client.RawRequest(T.Context(), "GET", "/api/test", nil, nil)
+
+		captured := buf.String()
+		assert.Regexp(t, `< Timing: connect [\dµm.]+s, first byte [\dµm.]+s\n`, captured)
+		assert.Regexp(t, `< Timing: connection reused, first byte [\dµm.]+s\n`, captured)
+	})
+
 	T.Run("silent when DebugFunc not set", func(t *testing.T) {
 		t.Parallel()
 
@@ -667,6 +731,49 @@ func TestDebugLogging(T *testing.T) {
 	})
 }
 
+func TestWithServerClock(T *testing.T) {
+	T.Parallel()
+	serverTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	T.Run("reports the Date header", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"2025.07"}`))
+		}))
+		t.Cleanup(server.Close)
+
+		var got []time.Time
+		client := NewClient(server.URL, "test-token", WithServerClock(func(server, local time.Time) {
+			got = append(got, server)
+			assert.WithinDuration(t, time.Now(), local, time.Minute)
+		}))
+
+		_, err := client.GetServer()
+		require.NoError(t, err)
+		_, _ = client.RawRequest(t.Context(), "GET", "/app/rest/server", nil, nil)
+		require.Len(t, got, 2)
+		assert.True(t, serverTime.Equal(got[0]))
+	})
+
+	T.Run("ignores a missing Date header", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header()["Date"] = nil
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(server.Close)
+
+		called := false
+		client := NewClient(server.URL, "test-token", WithServerClock(func(time.Time, time.Time) { called = true }))
+		_, _ = client.RawRequest(t.Context(), "GET", "/app/rest/server", nil, nil)
+		assert.False(t, called)
+	})
+}
+
 func TestRawRequestAcceptDefaultForNonJSONBody(T *testing.T) {
 	T.Parallel()
 
@@ -1094,4 +1201,16 @@ func TestReadOnlyMode(T *testing.T) {
 		client := NewClient("https://example.com", "token", WithReadOnly(true))
 		assert.True(t, client.ReadOnly)
 	})
+
+	T.Run("guest client is always read-only", func(t *testing.T) {
+		t.Parallel()
+
+		client := NewGuestClient("https://example.com", WithReadOnly(false))
+		assert.True(t, client.ReadOnly)
+
+		_, err := client.RunBuild("SomeJob", RunBuildOptions{})
+		require.ErrorIs(t, err, ErrGuestReadOnly)
+		require.ErrorIs(t, err, ErrReadOnly)
+		assert.Contains(t, err.Error(), "guest access is read-only")
+	})
 }
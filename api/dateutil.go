@@ -9,8 +9,11 @@ import (
 )
 
 // userDateLayouts are the absolute-date formats accepted by ParseUserDate.
+// Layouts without a zone are read as UTC.
 var userDateLayouts = []string{
 	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
 	"2006-01-02 15:04:05",
 	"2006-01-02T15:04:05",
 	time.RFC3339,
@@ -40,7 +43,50 @@ func ParseUserDate(input string) (string, error) {
 		return input, nil
 	}
 
-	return "", fmt.Errorf("invalid date: %s (expected duration like 24h/7d/2w or date like 2026-01-21)", input)
+	return "", fmt.Errorf("invalid date: %s (expected duration like 30m/24h/7d/2w, date like 2026-01-21, or time like 2026-01-21T15:04)", input)
+}
+
+// ParseUserExpiry converts a lifetime (90d, 12h) or an absolute date to the TeamCity time that far in the future.
+// Unlike ParseUserDate, durations count forward from now, and the result must be in the future.
+func ParseUserExpiry(input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+
+	now := time.Now().UTC()
+	var expiry time.Time
+	if duration, err := parseRelativeDuration(input); err == nil {
+		expiry = now.Add(duration)
+	} else {
+		parsed := false
+		for _, layout := range userDateLayouts {
+			if t, err := time.Parse(layout, input); err == nil {
+				expiry, parsed = t.UTC(), true
+				break
+			}
+		}
+		if !parsed {
+			t, err := ParseTeamCityTime(input)
+			if err != nil {
+				return "", fmt.Errorf("invalid expiry: %s (expected duration like 30d/12h or date like 2026-01-21)", input)
+			}
+			expiry = t.UTC()
+		}
+	}
+
+	if !expiry.After(now) {
+		return "", fmt.Errorf("expiry must be in the future: %s", input)
+	}
+	return FormatTeamCityTime(expiry), nil
+}
+
+// ParseUserDuration parses a positive length of time such as 90s, 15m, 1h30m, or 2d.
+func ParseUserDuration(input string) (time.Duration, error) {
+	d, err := parseRelativeDuration(input)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration: %s (expected duration like 90s/15m/1h30m)", input)
+	}
+	return d, nil
 }
 
 // FormatTeamCityTime formats time to TeamCity's date format.
@@ -93,6 +93,24 @@ func TestParseUserDate(T *testing.T) {
 				return strings.HasPrefix(s, "20260121T150405")
 			},
 		},
+		{
+			name:    "date and time without seconds",
+			input:   "2025-01-01T00:00",
+			wantErr: false,
+			validateFn: func(t *testing.T, s string) bool {
+				t.Helper()
+				return s == "20250101T000000+0000"
+			},
+		},
+		{
+			name:    "date and time without seconds, space-separated",
+			input:   "2025-01-01 09:30",
+			wantErr: false,
+			validateFn: func(t *testing.T, s string) bool {
+				t.Helper()
+				return s == "20250101T093000+0000"
+			},
+		},
 		{
 			name:    "ISO8601 format",
 			input:   "2026-01-21T15:04:05Z",
@@ -143,6 +161,45 @@ func TestParseUserDate(T *testing.T) {
 	}
 }
 
+func TestParseUserExpiry(T *testing.T) {
+	T.Parallel()
+
+	got, err := ParseUserExpiry("90d")
+	require.NoError(T, err)
+	expiry, err := ParseTeamCityTime(got)
+	require.NoError(T, err)
+	assert.WithinDuration(T, time.Now().Add(90*24*time.Hour), expiry, time.Minute)
+
+	got, err = ParseUserExpiry("2999-01-02")
+	require.NoError(T, err)
+	assert.True(T, strings.HasPrefix(got, "29990102T000000"), got)
+
+	empty, err := ParseUserExpiry("")
+	require.NoError(T, err)
+	assert.Empty(T, empty)
+
+	for _, bad := range []string{"2001-01-01", "-1d", "0s", "soon"} {
+		_, err := ParseUserExpiry(bad)
+		assert.Error(T, err, bad)
+	}
+}
+
+func TestParseUserDuration(T *testing.T) {
+	T.Parallel()
+	got, err := ParseUserDuration("1h30m")
+	require.NoError(T, err)
+	assert.Equal(T, 90*time.Minute, got)
+
+	got, err = ParseUserDuration("2d")
+	require.NoError(T, err)
+	assert.Equal(T, 48*time.Hour, got)
+
+	for _, in := range []string{"", "10", "0s", "fast", "-5m"} {
+		_, err := ParseUserDuration(in)
+		assert.Error(T, err, in)
+	}
+}
+
 func TestFormatTeamCityTime(T *testing.T) {
 	T.Parallel()
 	testTime := time.Date(2026, 1, 21, 15, 4, 5, 0, time.UTC)
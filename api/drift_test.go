@@ -73,34 +73,36 @@ type GoField struct {
 
 // Mapping of Go struct names to TeamCity swagger definition names
 var typeMapping = map[string]string{
-	"User":                "user",
-	"Project":             "project",
-	"ProjectList":         "projects",
-	"BuildType":           "buildType",
-	"BuildTypeList":       "buildTypes",
-	"Build":               "build",
-	"BuildList":           "builds",
-	"Triggered":           "TriggeredBy",
-	"Agent":               "agent",
-	"AgentList":           "agents",
-	"Pool":                "agentPool",
-	"QueuedBuild":         "build", // Same as Build in API
-	"BuildQueue":          "builds",
-	"TriggerBuildRequest": "build",
-	"TagList":             "tags",
-	"Tag":                 "tag",
-	"ApprovalInfo":        "approvalInfo",
-	"PropertyList":        "properties",
-	"Property":            "property",
-	"Server":              "server",
-	"Change":              "change",
-	"ChangeList":          "changes",
-	"Files":               "files",
-	"FileChange":          "FileChange",
-	"TestOccurrence":      "testOccurrence",
-	"TestOccurrences":     "testOccurrences",
-	"TriggeringOptions":   "buildTriggeringOptions",
-	"BuildComment":        "comment",
+	"User":                   "user",
+	"Project":                "project",
+	"ProjectList":            "projects",
+	"BuildType":              "buildType",
+	"BuildTypeList":          "buildTypes",
+	"Build":                  "build",
+	"BuildList":              "builds",
+	"Triggered":              "TriggeredBy",
+	"Agent":                  "agent",
+	"AgentList":              "agents",
+	"Pool":                   "agentPool",
+	"QueuedBuild":            "build", // Same as Build in API
+	"BuildQueue":             "builds",
+	"TriggerBuildRequest":    "build",
+	"TagList":                "tags",
+	"Tag":                    "tag",
+	"ApprovalInfo":           "approvalInfo",
+	"PropertyList":           "properties",
+	"Property":               "property",
+	"Server":                 "server",
+	"Change":                 "change",
+	"ChangeList":             "changes",
+	"Files":                  "files",
+	"FileChange":             "FileChange",
+	"TestOccurrence":         "testOccurrence",
+	"TestOccurrences":        "testOccurrences",
+	"TriggeringOptions":      "buildTriggeringOptions",
+	"BuildComment":           "comment",
+	"ArtifactDependency":     "artifact-dependency",
+	"ArtifactDependencyList": "artifact-dependencies",
 }
 
 // Types intentionally not validated against swagger:
@@ -322,6 +324,7 @@ func TestAPIDriftEndpoints(t *testing.T) {
 	cases := []endpointDriftCase{
 		// Server / users
 		{"GetServer", func() (any, error) { return client.GetServer() }},
+		{"GetLicensingData", func() (any, error) { return client.GetLicensingData() }},
 		{"GetCurrentUser", func() (any, error) { return client.GetCurrentUser() }},
 
 		// Project graph
@@ -329,12 +332,14 @@ func TestAPIDriftEndpoints(t *testing.T) {
 		{"GetProject", func() (any, error) { return client.GetProject(testProject) }},
 		{"GetVersionedSettingsConfig", func() (any, error) { return client.GetVersionedSettingsConfig(testProject) }},
 		{"GetVersionedSettingsStatus", func() (any, error) { return client.GetVersionedSettingsStatus(testProject) }},
+		{"GetProjectRoleHolders", func() (any, error) { return client.GetProjectRoleHolders(t.Context(), testProject) }},
 		{"GetSSHKeys", func() (any, error) { return client.GetSSHKeys(testProject) }},
 		{"GetProjectConnections", func() (any, error) { return client.GetProjectConnections(testProject) }},
 
 		// Build configurations
 		{"GetBuildTypes", func() (any, error) { r, _, err := client.GetBuildTypes(api.BuildTypesOptions{}); return r, err }},
 		{"GetBuildType", func() (any, error) { return client.GetBuildType(testConfig) }},
+		{"FindRenamedBuildTypes", func() (any, error) { return client.FindRenamedBuildTypes(testConfig) }},
 		{"GetBuildTypeParameters", func() (any, error) { return client.GetBuildTypeParameters(testConfig) }},
 		{"GetSnapshotDependencies", func() (any, error) { return client.GetSnapshotDependencies(testConfig) }},
 		{"GetDependentBuildTypes", func() (any, error) { return client.GetDependentBuildTypes(testConfig) }},
@@ -357,7 +362,10 @@ func TestAPIDriftEndpoints(t *testing.T) {
 			return client.GetBuildMessages(t.Context(), buildID, api.BuildMessagesOptions{})
 		}},
 		{"GetBuildSnapshotDependencies", func() (any, error) { return client.GetBuildSnapshotDependencies(buildID) }},
+		{"GetBuildDependents", func() (any, error) { return client.GetBuildDependents(t.Context(), buildID) }},
 		{"GetBuildResultingProperties", func() (any, error) { return client.GetBuildResultingProperties(buildID) }},
+		{"GetBuildStatistics", func() (any, error) { return client.GetBuildStatistics(t.Context(), buildID) }},
+		{"GetBuildParameters", func() (any, error) { return client.GetBuildParameters(t.Context(), buildID) }},
 		{"GetBuildUsedByOtherBuilds", func() (any, error) { return client.GetBuildUsedByOtherBuilds(buildID) }},
 		{"GetArtifacts", func() (any, error) { return client.GetArtifacts(t.Context(), buildID, "") }},
 
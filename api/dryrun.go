@@ -0,0 +1,438 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+// redacted replaces secret values in dry-run body summaries.
+const redacted = "[REDACTED]"
+
+// maxDryRunBody caps the body summary so large payloads (patches, YAML) stay readable.
+const maxDryRunBody = 200
+
+// DryRunCall describes a mutating request that DryRunClient recorded instead of sending.
+type DryRunCall struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+func (c DryRunCall) String() string {
+	if c.Body == "" {
+		return c.Method + " " + c.Path
+	}
+	return c.Method + " " + c.Path + "  " + c.Body
+}
+
+// DryRunClient wraps a ClientInterface: reads pass through, mutations are recorded and reported as successful.
+// New mutating methods on ClientInterface must be overridden here, or dry-run would send them.
+type DryRunClient struct {
+	ClientInterface
+	ctx    context.Context
+	record func(DryRunCall)
+}
+
+// NewDryRunClient wraps c so that every mutating call is handed to record instead of the server; ctx bounds the lookups it still performs.
+func NewDryRunClient(ctx context.Context, c ClientInterface, record func(DryRunCall)) *DryRunClient {
+	return &DryRunClient{ClientInterface: c, ctx: ctx, record: record}
+}
+
+var _ ClientInterface = (*DryRunClient)(nil)
+
+func (d *DryRunClient) note(method, path string, body any) {
+	d.record(DryRunCall{Method: method, Path: path, Body: summarizeBody(body)})
+}
+
+// summarizeBody renders a request body as a single short line: strings verbatim, bytes as a size, structs as JSON.
+// Values of JSON keys naming a password, token, or secret are masked.
+func summarizeBody(body any) string {
+	var s string
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case string:
+		s = b
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(b))
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Sprintf("%v", b)
+		}
+		s = string(data)
+	}
+	s = scrubSecrets(s)
+	if len(s) > maxDryRunBody {
+		cut := maxDryRunBody
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return s
+}
+
+func (d *DryRunClient) buildPath(ref, suffix string) (string, error) {
+	id, err := d.ResolveBuildID(d.ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return "/app/rest/builds/id:" + id + suffix, nil
+}
+
+func (d *DryRunClient) CreateUser(req CreateUserRequest) (*User, error) {
+	req.Password = redacted
+	d.note("POST", "/app/rest/users", req)
+	return &User{Username: req.Username, Name: req.Name, Email: req.Email}, nil
+}
+
+func (d *DryRunClient) CreateAccessToken(_ context.Context, req Token) (*Token, error) {
+	d.note("POST", "/app/rest/users/current/tokens", req)
+	return &Token{Name: req.Name, ExpirationTime: req.ExpirationTime, PermissionRestrictions: req.PermissionRestrictions}, nil
+}
+
+func (d *DryRunClient) DeleteAccessToken(_ context.Context, name string) error {
+	d.note("DELETE", "/app/rest/users/current/tokens/"+url.PathEscape(name), nil)
+	return nil
+}
+
+// Deprecated: Use CreateAccessToken.
+func (d *DryRunClient) CreateAPIToken(name string) (*Token, error) {
+	return d.CreateAccessToken(d.ctx, Token{Name: name})
+}
+
+// Deprecated: Use DeleteAccessToken.
+func (d *DryRunClient) DeleteAPIToken(name string) error {
+	return d.DeleteAccessToken(d.ctx, name)
+}
+
+func (d *DryRunClient) CreateProject(req CreateProjectRequest) (*Project, error) {
+	d.note("POST", "/app/rest/projects", req)
+	p := &Project{ID: req.ID, Name: req.Name}
+	if req.ParentProject != nil {
+		p.ParentProjectID = req.ParentProject.ID
+	}
+	return p, nil
+}
+
+func (d *DryRunClient) SetProjectField(projectID, field, value string) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/projects/id:%s/%s", url.PathEscape(projectID), url.PathEscape(field)), value)
+	return nil
+}
+
+func (d *DryRunClient) CopyProject(sourceID string, req CopyProjectRequest) (*Project, error) {
+	req.SourceProject = &ProjectRef{ID: sourceID}
+	d.note("POST", "/app/rest/projects", req)
+	p := &Project{ID: req.ID, Name: req.Name}
+	if req.ParentProject != nil {
+		p.ParentProjectID = req.ParentProject.ID
+	}
+	return p, nil
+}
+
+func (d *DryRunClient) CreateSecureToken(projectID, _ string) (string, error) {
+	d.note("POST", fmt.Sprintf("/app/rest/projects/%s/secure/tokens", url.PathEscape(projectID)), redacted)
+	return "credentialsJSON:dry-run", nil
+}
+
+func (d *DryRunClient) SetBuildTypePaused(id string, paused bool) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/buildTypes/id:%s/paused", url.PathEscape(id)), strconv.FormatBool(paused))
+	return nil
+}
+
+func (d *DryRunClient) CreateBuildType(projectID string, req CreateBuildTypeRequest) (*BuildType, error) {
+	req.Project = &ProjectRef{ID: projectID}
+	d.note("POST", "/app/rest/buildTypes", req)
+	return &BuildType{ID: req.ID, Name: req.Name, ProjectID: projectID}, nil
+}
+
+func (d *DryRunClient) CreateBuildStep(buildTypeID string, step BuildStep) (*BuildStep, error) {
+	d.note("POST", fmt.Sprintf("/app/rest/buildTypes/id:%s/steps", url.PathEscape(buildTypeID)), step)
+	return &step, nil
+}
+
+func (d *DryRunClient) DeleteBuildStep(buildTypeID, stepID string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/buildTypes/id:%s/steps/%s", url.PathEscape(buildTypeID), url.PathEscape(stepID)), nil)
+	return nil
+}
+
+func (d *DryRunClient) SetBuildTypeSetting(buildTypeID, setting, value string) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/buildTypes/id:%s/settings/%s", url.PathEscape(buildTypeID), url.PathEscape(setting)), value)
+	return nil
+}
+
+func (d *DryRunClient) RunBuild(buildTypeID string, opts RunBuildOptions) (*Build, error) {
+	d.note("POST", "/app/rest/buildQueue", struct {
+		BuildType string `json:"buildType"`
+		Branch    string `json:"branchName,omitempty"`
+		Personal  bool   `json:"personal,omitempty"`
+		Params    int    `json:"properties,omitempty"`
+		Comment   string `json:"comment,omitempty"`
+		Artifacts int    `json:"custom-artifact-dependencies,omitempty"`
+	}{buildTypeID, opts.Branch, opts.Personal, len(opts.Params) + len(opts.SystemProps) + len(opts.EnvVars), opts.Comment, len(opts.ArtifactBuilds)})
+	return &Build{BuildTypeID: buildTypeID, BranchName: opts.Branch, State: "queued", Personal: opts.Personal}, nil
+}
+
+func (d *DryRunClient) CancelBuild(buildID string, comment string) error {
+	id, err := d.ResolveBuildID(d.ctx, buildID)
+	if err != nil {
+		return err
+	}
+	if b, err := d.GetBuild(d.ctx, id); err == nil && b.State == "queued" {
+		return d.RemoveFromQueue(id)
+	}
+	d.note("POST", "/app/rest/builds/id:"+id, map[string]any{"comment": comment, "readdIntoQueue": false})
+	return nil
+}
+
+func (d *DryRunClient) DeleteBuild(_ context.Context, buildID string) error {
+	path, err := d.buildPath(buildID, "")
+	if err != nil {
+		return err
+	}
+	d.note("DELETE", path, nil)
+	return nil
+}
+
+func (d *DryRunClient) PinBuild(buildID string, comment string) error {
+	path, err := d.buildPath(buildID, "/pin")
+	if err != nil {
+		return err
+	}
+	d.note("PUT", path, cmp.Or(comment, "Pinned via teamcity CLI"))
+	return nil
+}
+
+func (d *DryRunClient) UnpinBuild(buildID string) error {
+	path, err := d.buildPath(buildID, "/pin")
+	if err != nil {
+		return err
+	}
+	d.note("DELETE", path, nil)
+	return nil
+}
+
+func (d *DryRunClient) AddBuildTags(buildID string, tags []string) error {
+	path, err := d.buildPath(buildID, "/tags")
+	if err != nil {
+		return err
+	}
+	d.note("POST", path, tags)
+	return nil
+}
+
+func (d *DryRunClient) RemoveBuildTag(buildID string, tag string) error {
+	path, err := d.buildPath(buildID, "/tags")
+	if err != nil {
+		return err
+	}
+	d.note("PUT", path, "without "+strconv.Quote(tag))
+	return nil
+}
+
+func (d *DryRunClient) SetBuildComment(buildID string, comment string) error {
+	path, err := d.buildPath(buildID, "/comment")
+	if err != nil {
+		return err
+	}
+	d.note("PUT", path, comment)
+	return nil
+}
+
+func (d *DryRunClient) DeleteBuildComment(buildID string) error {
+	path, err := d.buildPath(buildID, "/comment")
+	if err != nil {
+		return err
+	}
+	d.note("DELETE", path, nil)
+	return nil
+}
+
+func (d *DryRunClient) UploadDiffChanges(patch []byte, description string) (string, error) {
+	d.note("POST", "/uploadDiffChanges.html?description="+url.QueryEscape(description)+"&commitType=0", patch)
+	return "dry-run", nil
+}
+
+func (d *DryRunClient) RemoveFromQueue(id string) error {
+	d.note("DELETE", "/app/rest/buildQueue/id:"+id, nil)
+	return nil
+}
+
+func (d *DryRunClient) SetQueuedBuildPosition(buildID string, position int) error {
+	d.note("PUT", "/app/rest/buildQueue/order/"+buildID, strconv.Itoa(position))
+	return nil
+}
+
+func (d *DryRunClient) MoveQueuedBuildToTop(buildID string) error {
+	return d.SetQueuedBuildPosition(buildID, 0)
+}
+
+func (d *DryRunClient) ApproveQueuedBuild(buildID string) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/buildQueue/id:%s/approval/status", buildID), `"approved"`)
+	return nil
+}
+
+func (d *DryRunClient) setParameter(basePath, name, value string, secure bool) {
+	if secure {
+		value = redacted
+	}
+	d.note("PUT", fmt.Sprintf("%s/parameters/%s", basePath, url.PathEscape(name)), Parameter{Name: name, Value: value})
+}
+
+func (d *DryRunClient) SetProjectParameter(projectID, name, value string, secure bool) error {
+	d.setParameter("/app/rest/projects/id:"+url.PathEscape(projectID), name, value, secure)
+	return nil
+}
+
+func (d *DryRunClient) DeleteProjectParameter(projectID, name string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/projects/id:%s/parameters/%s", url.PathEscape(projectID), url.PathEscape(name)), nil)
+	return nil
+}
+
+func (d *DryRunClient) SetBuildTypeParameter(buildTypeID, name, value string, secure bool) error {
+	d.setParameter("/app/rest/buildTypes/id:"+url.PathEscape(buildTypeID), name, value, secure)
+	return nil
+}
+
+func (d *DryRunClient) DeleteBuildTypeParameter(buildTypeID, name string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/buildTypes/id:%s/parameters/%s", url.PathEscape(buildTypeID), url.PathEscape(name)), nil)
+	return nil
+}
+
+func (d *DryRunClient) AuthorizeAgent(id int, authorized bool) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/agents/id:%d/authorized", id), strconv.FormatBool(authorized))
+	return nil
+}
+
+func (d *DryRunClient) EnableAgent(id int, enabled bool) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/agents/id:%d/enabled", id), strconv.FormatBool(enabled))
+	return nil
+}
+
+func (d *DryRunClient) SetAgentEnabledInfo(id int, enabled bool, comment string) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/agents/id:%d/enabledInfo", id), AgentStateInfo{Status: enabled, Comment: &AgentComment{Text: comment}})
+	return nil
+}
+
+func (d *DryRunClient) SetAgentAuthorizedInfo(id int, authorized bool, comment string) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/agents/id:%d/authorizedInfo", id), AgentStateInfo{Status: authorized, Comment: &AgentComment{Text: comment}})
+	return nil
+}
+
+func (d *DryRunClient) RebootAgent(_ context.Context, id int, afterBuild bool) error {
+	form := url.Values{"agent": {strconv.Itoa(id)}}
+	if afterBuild {
+		form.Set("rebootAfterBuild", "true")
+	}
+	d.note("POST", "/remoteAccess/reboot.html", form.Encode())
+	return nil
+}
+
+func (d *DryRunClient) AddProjectToPool(poolID int, projectID string) error {
+	d.note("POST", fmt.Sprintf("/app/rest/agentPools/id:%d/projects", poolID), ProjectRef{ID: projectID})
+	return nil
+}
+
+func (d *DryRunClient) RemoveProjectFromPool(poolID int, projectID string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/agentPools/id:%d/projects/id:%s", poolID, projectID), nil)
+	return nil
+}
+
+func (d *DryRunClient) SetAgentPool(agentID int, poolID int) error {
+	d.note("PUT", fmt.Sprintf("/app/rest/agents/id:%d/pool", agentID), map[string]int{"id": poolID})
+	return nil
+}
+
+func (d *DryRunClient) StartCloudInstance(imageID string) (*CloudInstance, error) {
+	d.note("POST", "/app/rest/cloud/instances", StartCloudInstanceRequest{Image: CloudImageRef{ID: imageID}})
+	return &CloudInstance{}, nil
+}
+
+func (d *DryRunClient) StopCloudInstance(locator string, force bool) error {
+	action := "stop"
+	if force {
+		action = "forceStop"
+	}
+	d.note("POST", fmt.Sprintf("/app/rest/cloud/instances/%s/actions/%s", cloudLocator(locator, "id"), action), nil)
+	return nil
+}
+
+func (d *DryRunClient) CreatePipeline(parentProjectID, name, yaml, vcsRootID string) (*Pipeline, error) {
+	d.note("POST", "/app/pipeline?parentProjectExtId="+url.QueryEscape(parentProjectID), map[string]string{
+		"name":    name,
+		"vcsRoot": vcsRootID,
+		"yaml":    fmt.Sprintf("<%d bytes>", len(yaml)),
+	})
+	return &Pipeline{Name: name}, nil
+}
+
+func (d *DryRunClient) UpdatePipelineYAML(id string, yaml string) error {
+	d.note("POST", "/app/pipeline/"+id, map[string]string{"yaml": fmt.Sprintf("<%d bytes>", len(yaml))})
+	return nil
+}
+
+func (d *DryRunClient) DeletePipeline(id string) error {
+	d.note("DELETE", "/app/rest/projects/id:"+id, nil)
+	return nil
+}
+
+func (d *DryRunClient) CreateVcsRoot(root VcsRoot) (*VcsRoot, error) {
+	summary := root
+	summary.Properties = nil
+	d.note("POST", "/app/rest/vcs-roots", summary)
+	return &root, nil
+}
+
+func (d *DryRunClient) DeleteVcsRoot(id string) error {
+	d.note("DELETE", "/app/rest/vcs-roots/id:"+id, nil)
+	return nil
+}
+
+func (d *DryRunClient) UploadSSHKey(projectID, name string, privateKey []byte) error {
+	d.note("POST", fmt.Sprintf("/app/rest/projects/id:%s/sshKeys/?fileName=%s", url.PathEscape(projectID), url.QueryEscape(name)), privateKey)
+	return nil
+}
+
+func (d *DryRunClient) GenerateSSHKey(projectID, name, keyType string) (*SSHKey, error) {
+	d.note("POST", fmt.Sprintf("/app/rest/projects/id:%s/sshKeys/generated?keyName=%s&keyType=%s", url.PathEscape(projectID), url.QueryEscape(name), url.QueryEscape(keyType)), nil)
+	return &SSHKey{Name: name}, nil
+}
+
+func (d *DryRunClient) DeleteSSHKey(projectID, name string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/projects/id:%s/sshKeys/%s", url.PathEscape(projectID), url.PathEscape(name)), nil)
+	return nil
+}
+
+func (d *DryRunClient) CreateProjectFeature(projectID string, feat ProjectFeature) (*ProjectFeature, error) {
+	d.note("POST", fmt.Sprintf("/app/rest/projects/id:%s/projectFeatures", url.PathEscape(projectID)), map[string]string{"type": feat.Type})
+	return &feat, nil
+}
+
+func (d *DryRunClient) DeleteProjectFeature(projectID, featureID string) error {
+	d.note("DELETE", fmt.Sprintf("/app/rest/projects/id:%s/projectFeatures/id:%s", url.PathEscape(projectID), url.PathEscape(featureID)), nil)
+	return nil
+}
+
+// RawRequest passes GETs through and records every other method.
+func (d *DryRunClient) RawRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*RawResponse, error) {
+	if method == "GET" {
+		return d.ClientInterface.RawRequest(ctx, method, path, body, headers)
+	}
+	var summary any
+	if body != nil {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		summary = string(data)
+	}
+	d.note(method, path, summary)
+	return &RawResponse{StatusCode: 200}, nil
+}
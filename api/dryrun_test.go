@@ -0,0 +1,38 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunClientRecordsMutations(T *testing.T) {
+	T.Parallel()
+
+	var calls []DryRunCall
+	d := NewDryRunClient(T.Context(), NewClient("http://unused.invalid", "t"), func(c DryRunCall) {
+		calls = append(calls, c)
+	})
+
+	require.NoError(T, d.SetBuildTypeParameter("Falcon_Build", "secret", "hunter2", true))
+	require.NoError(T, d.EnableAgent(7, false))
+	_, err := d.UploadDiffChanges([]byte("diff --git a b"), "wip")
+	require.NoError(T, err)
+
+	require.Len(T, calls, 3)
+	assert.Equal(T, "PUT", calls[0].Method)
+	assert.Equal(T, "/app/rest/buildTypes/id:Falcon_Build/parameters/secret", calls[0].Path)
+	assert.NotContains(T, calls[0].Body, "hunter2")
+	assert.Equal(T, "PUT /app/rest/agents/id:7/enabled  false", calls[1].String())
+	assert.Equal(T, "<14 bytes>", calls[2].Body)
+}
+
+func TestSummarizeBodyTruncates(T *testing.T) {
+	T.Parallel()
+
+	s := summarizeBody(strings.Repeat("é", maxDryRunBody))
+	assert.True(T, strings.HasSuffix(s, "..."))
+	assert.LessOrEqual(T, len(s), maxDryRunBody+3)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Compression names the compression format body starts with ("gzip" or "zlib"), judged by magic bytes; it is
+// empty for anything else. Use it before printing a body to tell compressed data from text.
+func Compression(body []byte) string {
+	switch {
+	case bytes.HasPrefix(body, gzipMagic):
+		return "gzip"
+	case len(body) >= 2 && isZlibHeader(body[0], body[1]):
+		return "zlib"
+	}
+	return ""
+}
+
+// isZlibHeader reports whether b0 b1 form a zlib header (RFC 1950): deflate method, a window of at most 32K, and a
+// check sum that makes the pair a multiple of 31.
+func isZlibHeader(b0, b1 byte) bool {
+	return b0&0x0f == 8 && b0>>4 <= 7 && (uint16(b0)<<8|uint16(b1))%31 == 0
+}
+
+// decodeContentEncoding undoes a gzip or deflate Content-Encoding the transport left in place. Go's transport only
+// decompresses when it asked for gzip itself, so a proxy compressing unasked (or deflating) would otherwise hand
+// callers compressed bytes. A body that does not decode as declared is passed through unchanged.
+func decodeContentEncoding(resp *http.Response) {
+	if resp == nil || resp.Body == nil || resp.Uncompressed {
+		return
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "x-gzip" && encoding != "deflate" {
+		return
+	}
+
+	br := bufio.NewReader(resp.Body)
+	head, _ := br.Peek(2)
+	var decoded io.Reader
+	switch {
+	case len(head) == 0:
+		// Empty body (204, HEAD): nothing to decode.
+	case encoding == "deflate" && len(head) == 2 && isZlibHeader(head[0], head[1]):
+		if zr, err := zlib.NewReader(br); err == nil {
+			decoded = zr
+		}
+	case encoding == "deflate":
+		// Some servers send raw DEFLATE without the zlib wrapper that RFC 9110 asks for.
+		decoded = flate.NewReader(br)
+	case bytes.HasPrefix(head, gzipMagic):
+		if gz, err := gzip.NewReader(br); err == nil {
+			decoded = gz
+		}
+	}
+
+	if decoded == nil {
+		resp.Body = readCloser{Reader: br, Closer: resp.Body}
+		return
+	}
+	resp.Body = readCloser{Reader: decoded, Closer: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gunzipIfCompressed transparently decompresses a text stream that arrived gzip-compressed without saying so, as
+// happens when a proxy compresses a response twice or drops Content-Encoding. Only use it for text endpoints such
+// as build logs: artifacts are legitimately gzip files.
+func gunzipIfCompressed(body io.ReadCloser) io.ReadCloser {
+	br := bufio.NewReader(body)
+	if head, _ := br.Peek(2); bytes.Equal(head, gzipMagic) {
+		if gz, err := gzip.NewReader(br); err == nil {
+			return readCloser{Reader: gz, Closer: body}
+		}
+	}
+	return readCloser{Reader: br, Closer: body}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
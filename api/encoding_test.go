@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compress(t *testing.T, format, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch format {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	default:
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+		w = fw
+	}
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "gzip", Compression(compress(t, "gzip", "hello")))
+	assert.Equal(t, "zlib", Compression(compress(t, "zlib", "hello")))
+	assert.Empty(t, Compression([]byte(`{"id":1}`)))
+	assert.Empty(t, Compression([]byte("[12:00:00] Build started")))
+	assert.Empty(t, Compression(nil))
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+	t.Parallel()
+	const text = "[12:00:00] Build started"
+	for name, tc := range map[string]struct {
+		encoding string
+		body     []byte
+		want     string
+	}{
+		"gzip":            {"gzip", compress(t, "gzip", text), text},
+		"deflate zlib":    {"deflate", compress(t, "zlib", text), text},
+		"deflate raw":     {"deflate", compress(t, "flate", text), text},
+		"mislabeled gzip": {"gzip", []byte(text), text},
+		"identity":        {"", []byte(text), text},
+		"empty":           {"gzip", nil, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(tc.body))}
+			if tc.encoding != "" {
+				resp.Header.Set("Content-Encoding", tc.encoding)
+			}
+			decodeContentEncoding(resp)
+			got, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestGetBuildLogCompressed(t *testing.T) {
+	t.Parallel()
+	const text = "[12:00:00] Build started\n[12:00:01] Done"
+	for name, respond := range map[string]func(w http.ResponseWriter){
+		"declared gzip": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(compress(t, "gzip", text))
+		},
+		"declared deflate": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Encoding", "deflate")
+			_, _ = w.Write(compress(t, "zlib", text))
+		},
+		"undeclared gzip": func(w http.ResponseWriter) {
+			_, _ = w.Write(compress(t, "gzip", text))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "/app/rest/builds") {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(BuildList{Count: 1, Builds: []Build{{ID: 1}}})
+					return
+				}
+				respond(w)
+			})
+
+			log, err := client.GetBuildLog(t.Context(), "1")
+			require.NoError(t, err)
+			assert.Equal(t, text, log)
+		})
+	}
+}
+
+func TestRawRequestDecodesDespiteUserAcceptEncoding(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"), "the transport negotiates encoding, not the caller")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compress(t, "gzip", `{"version":"2026.1"}`))
+	})
+
+	resp, err := client.RawRequest(t.Context(), "GET", "/app/rest/server", nil, map[string]string{"Accept-Encoding": "gzip, deflate, br"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":"2026.1"}`, string(resp.Body))
+	assert.Empty(t, resp.Headers.Get("Content-Encoding"))
+}
@@ -151,6 +151,16 @@ func (readOnlyError) Category() Category { return CatReadOnly }
 // ErrReadOnly is returned when a non-GET request is attempted in read-only mode.
 var ErrReadOnly UserError = readOnlyError{}
 
+// guestReadOnlyError is the read-only error of a guest client; it matches ErrReadOnly too.
+type guestReadOnlyError struct{}
+
+func (guestReadOnlyError) Error() string        { return "guest access is read-only" }
+func (guestReadOnlyError) Category() Category   { return CatReadOnly }
+func (guestReadOnlyError) Is(target error) bool { return target == ErrReadOnly }
+
+// ErrGuestReadOnly is returned when a guest client attempts a non-GET request.
+var ErrGuestReadOnly UserError = guestReadOnlyError{}
+
 // joinSnippet appends a body-snippet diagnostic to base when one is available.
 func joinSnippet(base, snippet string) string {
 	if snippet == "" {
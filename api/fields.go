@@ -81,6 +81,7 @@ var BuildFields = FieldSpec{
 		"triggered.type", "triggered.date", "triggered.user.name", "triggered.user.username",
 		"agent.id", "agent.name", "agent.href", "agent.webUrl",
 		"usedByOtherBuilds",
+		"canceledInfo.user.name", "canceledInfo.user.username", "canceledInfo.timestamp", "canceledInfo.text",
 	},
 	Default: []string{
 		"id", "number", "status", "statusText", "state", "branchName", "buildTypeId",
@@ -114,8 +115,10 @@ var QueuedBuildFields = FieldSpec{
 }
 
 var AgentFields = FieldSpec{
-	Available: []string{"id", "name", "typeId", "connected", "enabled", "authorized", "href", "webUrl", "pool.id", "pool.name"},
-	Default:   []string{"id", "name", "connected", "enabled", "authorized", "href", "webUrl", "pool.id", "pool.name"},
+	Available: []string{"id", "name", "typeId", "connected", "enabled", "authorized", "href", "webUrl", "pool.id", "pool.name",
+		"build.id", "build.number", "build.buildTypeId"},
+	Default: []string{"id", "name", "connected", "enabled", "authorized", "href", "webUrl", "pool.id", "pool.name",
+		"build.id", "build.number", "build.buildTypeId"},
 }
 
 var VcsRootFields = FieldSpec{
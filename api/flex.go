@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// flexInt stores a JSON number, or a string holding one, into *dst; null, "" and an absent field (raw == nil)
+// leave it unchanged. Some TeamCity versions quote numeric IDs in nested objects (a run's agent, a change, a
+// user), which strict int fields reject.
+func flexInt(raw json.RawMessage, field string, dst *int) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) || bytes.Equal(raw, []byte(`""`)) {
+		return nil
+	}
+	s, kind := string(raw), jsonKind(raw)
+	if kind == "string" {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = strings.TrimSpace(unquoted)
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: kind, Type: reflect.TypeFor[int](), Field: field}
+	}
+	*dst = v
+	return nil
+}
+
+// jsonKind names the kind of the JSON value raw starts, as json.UnmarshalTypeError.Value does.
+func jsonKind(raw []byte) string {
+	switch raw[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	}
+	return "number"
+}
+
+// The hot-path types below decode through an alias whose numeric fields are taken raw and parsed with flexInt:
+// the outer field shadows the embedded one with the same JSON name.
+
+func (b *Build) UnmarshalJSON(data []byte) error {
+	type plain Build
+	aux := struct {
+		*plain
+		ID                 json.RawMessage `json:"id"`
+		PercentageComplete json.RawMessage `json:"percentageComplete"`
+	}{plain: (*plain)(b)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return errors.Join(flexInt(aux.ID, "id", &b.ID), flexInt(aux.PercentageComplete, "percentageComplete", &b.PercentageComplete))
+}
+
+// UnmarshalJSON decodes the embedded Build with its own decoder, whose promoted method would otherwise drop
+// Comment and Revisions.
+func (b *RerunBuild) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &b.Build); err != nil {
+		return err
+	}
+	var aux struct {
+		Comment   *BuildComment `json:"comment"`
+		Revisions *Revisions    `json:"revisions"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	b.Comment, b.Revisions = aux.Comment, aux.Revisions
+	return nil
+}
+
+func (q *QueuedBuild) UnmarshalJSON(data []byte) error {
+	type plain QueuedBuild
+	aux := struct {
+		*plain
+		ID json.RawMessage `json:"id"`
+	}{plain: (*plain)(q)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return flexInt(aux.ID, "id", &q.ID)
+}
+
+func (a *Agent) UnmarshalJSON(data []byte) error {
+	type plain Agent
+	aux := struct {
+		*plain
+		ID     json.RawMessage `json:"id"`
+		TypeID json.RawMessage `json:"typeId"`
+	}{plain: (*plain)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return errors.Join(flexInt(aux.ID, "id", &a.ID), flexInt(aux.TypeID, "typeId", &a.TypeID))
+}
+
+func (u *User) UnmarshalJSON(data []byte) error {
+	type plain User
+	aux := struct {
+		*plain
+		ID json.RawMessage `json:"id"`
+	}{plain: (*plain)(u)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return flexInt(aux.ID, "id", &u.ID)
+}
+
+func (c *Change) UnmarshalJSON(data []byte) error {
+	type plain Change
+	aux := struct {
+		*plain
+		ID json.RawMessage `json:"id"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return flexInt(aux.ID, "id", &c.ID)
+}
+
+// DecodeError reports a response body that does not have the shape the CLI expects, usually because a server
+// version changed a field's type. Field is the path of the offending value, such as build[0].agent.id, when it
+// can be found, and Snippet the raw JSON around it.
+type DecodeError struct {
+	Path    string
+	Field   string
+	Snippet string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	msg := "failed to decode response: " + e.Err.Error()
+	if typeErr, ok := errors.AsType[*json.UnmarshalTypeError](e.Err); ok && e.Field != "" {
+		kind, _, _ := strings.Cut(typeErr.Value, " ")
+		msg = fmt.Sprintf("failed to decode response: %s is a %s, expected %s", e.Field, kind, typeErr.Type)
+	}
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(" (near %s)", e.Snippet)
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() error    { return e.Err }
+func (*DecodeError) Category() Category { return CatInternal }
+
+func (e *DecodeError) Suggestion() string {
+	return fmt.Sprintf("The server may be newer than this CLI supports; get the raw response with 'teamcity api \"%s\"'", e.Path)
+}
+
+// decodeSnippetContext is how many bytes of raw JSON a DecodeError shows either side of the failure.
+const decodeSnippetContext = 40
+
+// decodeResponse decodes a JSON response body from path into result, turning a failure into a DecodeError.
+func decodeResponse(body io.Reader, path string, result any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	err = json.Unmarshal(data, result)
+	if err == nil {
+		return nil
+	}
+
+	decodeErr := &DecodeError{Path: path, Err: err}
+	offset := int64(-1)
+	if typeErr, ok := errors.AsType[*json.UnmarshalTypeError](err); ok && typeErr.Field != "" {
+		// Errors raised inside a custom UnmarshalJSON carry only the path within that object and an offset into
+		// it, so find the value in the whole document instead.
+		kind, _, _ := strings.Cut(typeErr.Value, " ")
+		decodeErr.Field, offset = locateValue(data, typeErr.Field, kind)
+	} else if syntaxErr, ok := errors.AsType[*json.SyntaxError](err); ok {
+		offset = syntaxErr.Offset
+	}
+	if offset >= 0 && offset <= int64(len(data)) {
+		start, end := max(int(offset)-decodeSnippetContext, 0), min(int(offset)+decodeSnippetContext, len(data))
+		decodeErr.Snippet = bodySnippet(data[start:end])
+	}
+	return decodeErr
+}
+
+// locateValue finds the first value of the given kind whose path ends with field (dotted, as in
+// json.UnmarshalTypeError), returning its full path with array indices and its offset. It returns field and -1 when
+// there is no such value.
+func locateValue(data []byte, field, kind string) (string, int64) {
+	type frame struct {
+		array bool
+		index int
+		key   string
+	}
+	var stack []frame
+	path := func() (full, bare string) {
+		var b, plain strings.Builder
+		for _, f := range stack {
+			if f.array {
+				fmt.Fprintf(&b, "[%d]", f.index)
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+				plain.WriteByte('.')
+			}
+			b.WriteString(f.key)
+			plain.WriteString(f.key)
+		}
+		return b.String(), plain.String()
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	expectKey := false
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return field, -1
+		}
+		if expectKey {
+			if key, ok := tok.(string); ok {
+				stack[len(stack)-1].key = key
+				expectKey = false
+				continue
+			}
+		}
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && !stack[len(stack)-1].array {
+				expectKey = true
+			} else if len(stack) > 0 {
+				stack[len(stack)-1].index++
+			}
+			continue
+		}
+
+		// tok starts a value: check it, then step into it or past it.
+		if full, bare := path(); tokenKind(tok) == kind && (bare == field || strings.HasSuffix(bare, "."+field)) {
+			return full, offset
+		}
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, frame{})
+			expectKey = true
+		case json.Delim('['):
+			stack = append(stack, frame{array: true})
+		default:
+			if len(stack) > 0 && stack[len(stack)-1].array {
+				stack[len(stack)-1].index++
+			} else if len(stack) > 0 {
+				expectKey = true
+			}
+		}
+	}
+}
+
+func tokenKind(tok json.Token) string {
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			return "object"
+		}
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return "null"
+}
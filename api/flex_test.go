@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodePayloadVariants decodes the shapes servers send the same run in: numeric IDs, IDs quoted as strings in
+// the run and its nested agent, user, and change, and dates with milliseconds or in RFC 3339.
+func TestDecodePayloadVariants(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob("testdata/payloads/builds-*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var list BuildList
+			require.NoError(t, decodeResponse(strings.NewReader(string(data)), "/app/rest/builds", &list))
+			require.Len(t, list.Builds, 1)
+			b := list.Builds[0]
+			assert.Equal(t, 4812, b.ID)
+			assert.Equal(t, "Falcon_Build", b.BuildType.ID)
+			require.NotNil(t, b.Agent)
+			assert.Equal(t, 31, b.Agent.ID)
+			assert.Equal(t, 7, b.Triggered.User.ID)
+			if b.LastChanges != nil {
+				assert.Equal(t, 90211, b.LastChanges.Change[0].ID)
+			}
+			start, err := ParseTeamCityTime(b.StartDate)
+			require.NoError(t, err)
+			assert.True(t, start.Equal(time.Date(2025, 7, 10, 8, 6, 7, 0, time.UTC)), "start %s", start)
+		})
+	}
+}
+
+func TestDecodeQuotedIDsMarshalAsNumbers(t *testing.T) {
+	t.Parallel()
+	var b Build
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"12","percentageComplete":"40","agent":{"id":"3"}}`), &b))
+	assert.Equal(t, 12, b.ID)
+	assert.Equal(t, 40, b.PercentageComplete)
+
+	out, err := json.Marshal(b)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"id":12`)
+	assert.Contains(t, string(out), `"agent":{"id":3}`)
+}
+
+func TestDecodeError(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		body, field, snippet string
+	}{
+		"non-numeric quoted id":        {`{"count":2,"build":[{"id":1},{"id":"x12"}]}`, "build[1].id", `"x12"`},
+		"nested inside a lenient type": {`{"count":1,"build":[{"id":1,"agent":{"id":"3","name":5}}]}`, "build[0].agent.name", `"name":5`},
+		"plain struct field":           {`{"count":1,"build":[{"id":1,"buildType":{"id":5}}]}`, "build[0].buildType.id", `"buildType":{"id":5}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.body))
+			})
+
+			_, _, err := client.GetBuilds(t.Context(), BuildsOptions{Limit: 2})
+			decodeErr, ok := errors.AsType[*DecodeError](err)
+			require.True(t, ok, "got %v", err)
+			assert.Equal(t, tc.field, decodeErr.Field)
+			assert.Contains(t, decodeErr.Snippet, tc.snippet)
+			assert.Contains(t, err.Error(), tc.field)
+			assert.Contains(t, decodeErr.Suggestion(), "teamcity api \"/app/rest/builds?")
+			assert.Equal(t, CatInternal, decodeErr.Category())
+		})
+	}
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+		var b Build
+		err := decodeResponse(strings.NewReader(`{"id":1,"status":"SUCC`), "/app/rest/builds/id:1", &b)
+		decodeErr, ok := errors.AsType[*DecodeError](err)
+		require.True(t, ok)
+		assert.Empty(t, decodeErr.Field)
+		assert.Contains(t, decodeErr.Snippet, `"status":"SUCC`)
+	})
+}
@@ -1,11 +1,50 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"os"
+	"regexp"
+	"runtime"
 	"strings"
 )
 
+// HeaderCLIContext carries the automation label set via TC_CONTEXT or the context config key, so server audit logs can tell which team or bot made a call.
+const HeaderCLIContext = "X-TC-CLI-Context"
+
+// maxCLIContextLen bounds the context label: it names a caller, it doesn't carry data.
+const maxCLIContextLen = 64
+
+var cliContextPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@+-]*$`)
+
+// UserAgent is the User-Agent the CLI sends: teamcity-cli/<version> (<os>; <arch>).
+func UserAgent(version string) string {
+	return fmt.Sprintf("teamcity-cli/%s (%s; %s)", version, runtime.GOOS, runtime.GOARCH)
+}
+
+// ValidateCLIContext rejects a context label that isn't a short identifier, or that looks like a credential.
+// The header is sent in clear and logged by --verbose, so it must never carry a secret.
+func ValidateCLIContext(label string) error {
+	switch {
+	case len(label) > maxCLIContextLen:
+		return fmt.Errorf("context label is %d characters; keep it to %d", len(label), maxCLIContextLen)
+	case !cliContextPattern.MatchString(label):
+		return fmt.Errorf("context label %q may only contain letters, digits, and . _ : / @ + -", label)
+	case looksLikeSecret(label):
+		return fmt.Errorf("context label looks like a token; use a name such as release-bot")
+	}
+	return nil
+}
+
+// looksLikeSecret reports labels shaped like access tokens: JWT-style values (as TeamCity tokens are) or long unbroken runs of letters and digits.
+func looksLikeSecret(label string) bool {
+	if strings.HasPrefix(label, "eyJ") {
+		return true
+	}
+	return len(label) >= 24 && !strings.ContainsAny(label, "._:/@+-") &&
+		strings.ContainsAny(label, "0123456789") && strings.ToLower(label) != label
+}
+
 // EnvHeaderPrefix is the env-var prefix that contributes extra HTTP headers to every request.
 // TEAMCITY_HEADER_FOO_BAR=value sends "Foo-Bar: value" — underscores become hyphens, name is canonical-cased.
 const EnvHeaderPrefix = "TEAMCITY_HEADER_"
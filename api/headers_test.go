@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// TestApplyStandardHeadersOnEveryEntryPoint asserts every HTTP entry point sends UA, X-TeamCity-Client, and extras.
+// TestApplyStandardHeadersOnEveryEntryPoint asserts every HTTP entry point sends UA, X-TeamCity-Client, the CLI context, and extras.
 func TestApplyStandardHeadersOnEveryEntryPoint(T *testing.T) {
 	T.Parallel()
 
@@ -26,6 +28,7 @@ func TestApplyStandardHeadersOnEveryEntryPoint(T *testing.T) {
 			var b bytes.Buffer
 			_, _ = c.DownloadArtifactTo(T.Context(), "1", "x.txt", &b)
 		}},
+		{"GetBuildLog", func(c *Client) { _, _ = c.GetBuildLog(T.Context(), "1") }},
 		{"Probe", func(c *Client) { _ = c.Probe(T.Context()) }},
 		{"IsPkceEnabled", func(c *Client) { _, _ = c.IsPkceEnabled(T.Context()) }},
 		{"ExchangeCodeForToken", func(c *Client) {
@@ -47,10 +50,11 @@ func TestApplyStandardHeadersOnEveryEntryPoint(T *testing.T) {
 			c := NewClient(server.URL, "tok", WithExtraHeaders(map[string]string{
 				"CF-Access-Client-Id":     "abc.id",
 				"CF-Access-Client-Secret": "shh",
-			}))
+			}), WithVersion("1.2.3"), WithCLIContext("release-bot"))
 			tc.run(c)
 
-			assert.Contains(t, got.Get("User-Agent"), "teamcity-cli/")
+			assert.Equal(t, UserAgent("1.2.3"), got.Get("User-Agent"))
+			assert.Equal(t, "release-bot", got.Get(HeaderCLIContext))
 			assert.Contains(t, got.Get("X-TeamCity-Client"), "teamcity-cli/")
 			assert.Equal(t, "abc.id", got.Get("Cf-Access-Client-Id"))
 			assert.Equal(t, "shh", got.Get("Cf-Access-Client-Secret"))
@@ -164,3 +168,63 @@ func TestWithExtraHeaders_RedactedInDebugLog(T *testing.T) {
 	assert.Contains(T, out, "X-Secret-Header: [REDACTED]")
 	assert.NotContains(T, out, "verysecret", "extra-header values must never appear in debug output")
 }
+
+func TestUserAgent(T *testing.T) {
+	T.Parallel()
+	assert.Equal(T, fmt.Sprintf("teamcity-cli/1.2.3 (%s; %s)", runtime.GOOS, runtime.GOARCH), UserAgent("1.2.3"))
+}
+
+func TestValidateCLIContext(T *testing.T) {
+	T.Parallel()
+
+	for _, label := range []string{"release-bot", "team:payments", "ci/nightly", "deploy@eu-west-1", "bot_v2.1"} {
+		assert.NoError(T, ValidateCLIContext(label), label)
+	}
+
+	for label, want := range map[string]string{
+		"":                               "may only contain",
+		"release bot":                    "may only contain",
+		"-leading-dash":                  "may only contain",
+		"team=payments":                  "may only contain",
+		strings.Repeat("a", 65):          "keep it to 64",
+		"eyJ0eXAiOiAiVENWMiJ9":           "looks like a token",
+		"ghp4f9aKd02LmQx7Zr81TyBn6Wc3Ve": "looks like a token",
+	} {
+		err := ValidateCLIContext(label)
+		require.Error(T, err, label)
+		assert.Contains(T, err.Error(), want, label)
+	}
+}
+
+func TestCLIContextHeader(T *testing.T) {
+	T.Parallel()
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	T.Cleanup(server.Close)
+
+	T.Run("shown in verbose output", func(t *testing.T) {
+		var debug bytes.Buffer
+		c := NewClient(server.URL, "tok", WithCLIContext("release-bot"), WithDebugFunc(func(format string, args ...any) {
+			fmt.Fprintf(&debug, format+"\n", args...)
+		}))
+		_, _ = c.GetServer()
+		assert.Contains(t, debug.String(), "> X-Tc-Cli-Context: release-bot")
+	})
+
+	T.Run("invalid labels are not sent", func(t *testing.T) {
+		c := NewClient(server.URL, "tok", WithCLIContext("not a label"))
+		_, _ = c.GetServer()
+		assert.Empty(t, got.Get(HeaderCLIContext))
+	})
+
+	T.Run("a label equal to the credential is never sent", func(t *testing.T) {
+		c := NewClientWithBasicAuth(server.URL, "alice", "hunter2", WithCLIContext("hunter2"))
+		_, _ = c.GetServer()
+		assert.Empty(t, got.Get(HeaderCLIContext))
+	})
+}
@@ -9,42 +9,57 @@ import (
 // Cmd package uses this interface for dependency injection in tests.
 type ClientInterface interface {
 	GetServer() (*Server, error)
+	GetLicensingData() (*LicensingData, error)
 	ServerVersion() (*Server, error)
 	CheckVersion() error
 	SupportsFeature(feature string) bool
+	Capabilities(ctx context.Context) map[string]CapabilityStatus
 
 	GetCurrentUser() (*User, error)
 	GetUser(username string) (*User, error)
 	UserExists(username string) bool
 	CreateUser(req CreateUserRequest) (*User, error)
+	CreateAccessToken(ctx context.Context, req Token) (*Token, error)
+	ListAccessTokens(ctx context.Context) (*TokenList, error)
+	DeleteAccessToken(ctx context.Context, name string) error
+	// Deprecated: Use CreateAccessToken.
 	CreateAPIToken(name string) (*Token, error)
+	// Deprecated: Use DeleteAccessToken.
 	DeleteAPIToken(name string) error
 
 	GetProjects(opts ProjectsOptions) (*ProjectList, bool, error)
 	GetProject(id string) (*Project, error)
 	CreateProject(req CreateProjectRequest) (*Project, error)
+	CopyProject(sourceID string, req CopyProjectRequest) (*Project, error)
+	SetProjectField(projectID, field, value string) error
 	ProjectExists(id string) bool
 	CreateSecureToken(projectID, value string) (string, error)
 	GetSecureValue(projectID, token string) (string, error)
 	GetVersionedSettingsStatus(projectID string) (*VersionedSettingsStatus, error)
 	GetVersionedSettingsConfig(projectID string) (*VersionedSettingsConfig, error)
+	GetProjectRoleHolders(ctx context.Context, projectIDs ...string) (map[string][]RoleHolder, error)
 	ExportProjectSettings(projectID, format string, useRelativeIds bool) ([]byte, error)
 
 	GetBuildTypes(opts BuildTypesOptions) (*BuildTypeList, bool, error)
 	GetBuildType(id string) (*BuildType, error)
+	GetBuildTypeDetail(id string) (*BuildTypeDetail, error)
+	GetTemplate(id string) (*BuildType, error)
 	SetBuildTypePaused(id string, paused bool) error
 	CreateBuildType(projectID string, req CreateBuildTypeRequest) (*BuildType, error)
 	BuildTypeExists(id string) bool
+	FindRenamedBuildTypes(ref string) ([]BuildType, error)
 	GetBuildSteps(buildTypeID string) (*BuildStepList, error)
 	GetBuildStep(buildTypeID, stepID string) (*BuildStep, error)
 	CreateBuildStep(buildTypeID string, step BuildStep) (*BuildStep, error)
 	DeleteBuildStep(buildTypeID, stepID string) error
 	GetSnapshotDependencies(buildTypeID string) (*SnapshotDependencyList, error)
+	GetArtifactDependencies(buildTypeID string) (*ArtifactDependencyList, error)
 	GetDependentBuildTypes(buildTypeID string) (*BuildTypeList, error)
 	GetVcsRootEntries(buildTypeID string) (*VcsRootEntries, error)
 	SetBuildTypeSetting(buildTypeID, setting, value string) error
 	GetBuildTypeSettings(buildTypeID string) (*SettingsList, error)
 	GetBuildTypeSetting(buildTypeID, name string) (string, error)
+	GetBuildTypeBranches(ctx context.Context, buildTypeID string) (*BranchList, error)
 
 	GetBuilds(ctx context.Context, opts BuildsOptions) (*BuildList, bool, error)
 	GetBuild(ctx context.Context, ref string) (*Build, error)
@@ -52,7 +67,9 @@ type ClientInterface interface {
 	WaitForBuild(ctx context.Context, buildID string, opts WaitForBuildOptions) (*Build, error)
 	ResolveBuildID(ctx context.Context, ref string) (string, error)
 	RunBuild(buildTypeID string, opts RunBuildOptions) (*Build, error)
+	GetBuildForRerun(ctx context.Context, ref string) (*RerunBuild, error)
 	CancelBuild(buildID string, comment string) error
+	DeleteBuild(ctx context.Context, buildID string) error
 	GetBuildLog(ctx context.Context, buildID string) (string, error)
 	GetBuildLogStream(ctx context.Context, buildID string) (io.ReadCloser, error)
 	GetBuildMessages(ctx context.Context, buildID string, opts BuildMessagesOptions) (*BuildMessagesResponse, error)
@@ -65,12 +82,18 @@ type ClientInterface interface {
 	GetBuildComment(buildID string) (string, error)
 	DeleteBuildComment(buildID string) error
 	GetBuildSnapshotDependencies(buildID string) (*BuildList, error)
+	GetBuildArtifactDependencies(buildID string) (*BuildList, error)
+	GetBuildDependents(ctx context.Context, buildID string) ([]Build, error)
+	GetBuildBatches(ctx context.Context, build *Build) ([]BuildBatch, error)
+	GetChanges(ctx context.Context, opts ChangesOptions) (*ChangeList, error)
 	GetBuildChanges(ctx context.Context, buildID string) (*ChangeList, error)
 	ListTestOccurrences(ctx context.Context, q TestOccurrenceQuery) (*TestOccurrences, error)
 	GetBuildTests(ctx context.Context, buildID string, opts BuildTestsOptions) (*TestOccurrences, error)
 	GetBuildTestSummary(buildID string) (*TestOccurrences, error)
 	GetBuildProblems(buildID string) (*ProblemOccurrences, error)
 	GetBuildResultingProperties(buildID string) (*ParameterList, error)
+	GetBuildParameters(ctx context.Context, buildID string) (*Build, error)
+	GetBuildStatistics(ctx context.Context, buildID string) (*PropertyList, error)
 	UploadDiffChanges(patch []byte, description string) (string, error)
 
 	GetArtifacts(ctx context.Context, buildID string, path string) (*Artifacts, error)
@@ -78,10 +101,13 @@ type ClientInterface interface {
 	DownloadArtifactTo(ctx context.Context, buildID, artifactPath string, w io.Writer) (int64, error)
 
 	GetBuildQueue(opts QueueOptions) (*BuildQueue, bool, error)
+	GetQueueDependencyHolds(ctx context.Context) (map[int]Build, error)
 	RemoveFromQueue(id string) error
 	SetQueuedBuildPosition(buildID string, position int) error
 	MoveQueuedBuildToTop(buildID string) error
 	ApproveQueuedBuild(buildID string) error
+	GetQueuePosition(ctx context.Context, buildID int) (int, int, error)
+	GetQueuedBuildStartEstimate(ctx context.Context, buildID int) (string, error)
 	GetQueuedBuildApprovalInfo(buildID string) (*ApprovalInfo, error)
 
 	GetProjectParameters(projectID string) (*ParameterList, error)
@@ -97,8 +123,11 @@ type ClientInterface interface {
 	GetAgents(opts AgentsOptions) (*AgentList, bool, error)
 	GetAgent(id int) (*Agent, error)
 	GetAgentByName(name string) (*Agent, error)
+	GetAgentProperties(ctx context.Context, id int) (*PropertyList, error)
 	AuthorizeAgent(id int, authorized bool) error
 	EnableAgent(id int, enabled bool) error
+	SetAgentEnabledInfo(id int, enabled bool, comment string) error
+	SetAgentAuthorizedInfo(id int, authorized bool, comment string) error
 	RebootAgent(ctx context.Context, id int, afterBuild bool) error
 	GetAgentCompatibleBuildTypes(id int) (*BuildTypeList, error)
 	GetAgentIncompatibleBuildTypes(id int) (*CompatibilityList, error)
@@ -146,7 +175,7 @@ type ClientInterface interface {
 	DeleteProjectFeature(projectID, featureID string) error
 
 	RawRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*RawResponse, error)
-	NormalizePaginationPath(href string) string
+	NormalizePaginationPath(href string) (string, error)
 
 	SetCommandName(name string)
 	ServerURL() string
@@ -2,10 +2,13 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -14,15 +17,26 @@ import (
 type BuildTypesOptions struct {
 	Project    string
 	VcsRootURL string // server-side substring filter on each VCS root's `url` property
+	VcsRoot    string // only build types attached to the VCS root with this ID
 	Limit      int
 	Fields     []string
+	// PageSize, when set, fetches at most this many per request instead of one page sized to Limit.
+	PageSize int
+	// LastRun also fetches each build type's latest finished run on its default branch into BuildType.LastRun,
+	// in the same request.
+	LastRun bool
+	// OnPage, when set, receives each page as it arrives; an error from it stops the fetch and is returned.
+	OnPage func([]BuildType) error
 }
 
+// lastRunFields is the builds expansion BuildTypesOptions.LastRun adds to each build type.
+const lastRunFields = "builds($locator(count:1),build(id,number,status,statusText,state,branchName,finishDate,webUrl))"
+
 // GetBuildTypes returns a list of build configurations, following pagination; the bool is true when a finite limit capped the result.
 func (c *Client) GetBuildTypes(opts BuildTypesOptions) (*BuildTypeList, bool, error) {
 	locator := NewLocator().
 		Add("affectedProject", opts.Project).
-		AddInt("count", pageCount(opts.Limit))
+		AddInt("count", pageSize(opts.Limit, opts.PageSize))
 	if opts.VcsRootURL != "" {
 		locator.AddLocator("vcsRoot", NewLocator().
 			AddLocator("property", NewLocator().
@@ -30,21 +44,35 @@ func (c *Client) GetBuildTypes(opts BuildTypesOptions) (*BuildTypeList, bool, er
 				Add("value", opts.VcsRootURL).
 				Add("matchType", "contains")))
 	}
+	if opts.VcsRoot != "" {
+		locator.AddLocator("vcsRoot", NewLocator().Add("id", opts.VcsRoot))
+	}
 
 	fields := opts.Fields
 	if len(fields) == 0 {
 		fields = BuildTypeFields.Default
 	}
-	fieldsParam := fmt.Sprintf("count,nextHref,buildType(%s)", ToAPIFields(fields))
+	apiFields := ToAPIFields(fields)
+	if opts.LastRun {
+		apiFields += "," + lastRunFields
+	}
+	fieldsParam := fmt.Sprintf("count,nextHref,buildType(%s)", apiFields)
 	path := fmt.Sprintf("/app/rest/buildTypes?locator=%s&fields=%s", locator.Encode(), url.QueryEscape(fieldsParam))
 
-	buildTypes, truncated, err := collectPages(c, path, opts.Limit, func(p string) ([]BuildType, string, error) {
+	buildTypes, truncated, err := streamPages(c, path, opts.Limit, func(p string) ([]BuildType, string, error) {
 		var page BuildTypeList
 		if err := c.get(c.ctx(), p, &page); err != nil {
 			return nil, "", err
 		}
+		for i := range page.BuildTypes {
+			bt := &page.BuildTypes[i]
+			if bt.Builds != nil && len(bt.Builds.Builds) > 0 {
+				bt.LastRun = &bt.Builds.Builds[0]
+			}
+			bt.Builds = nil
+		}
 		return page.BuildTypes, page.NextHref, nil
-	})
+	}, opts.OnPage)
 	if err != nil {
 		return nil, false, err
 	}
@@ -64,6 +92,62 @@ func (c *Client) GetBuildType(id string) (*BuildType, error) {
 	return &buildType, nil
 }
 
+// BuildTypeDetail is a build configuration with its steps, triggers, VCS roots, dependencies and settings expanded.
+type BuildTypeDetail struct {
+	BuildType
+	Steps                *BuildStepList          `json:"steps,omitempty"`
+	Triggers             *BuildTriggerList       `json:"triggers,omitempty"`
+	SnapshotDependencies *SnapshotDependencyList `json:"snapshot-dependencies,omitempty"`
+	ArtifactDependencies *ArtifactDependencyList `json:"artifact-dependencies,omitempty"`
+	Settings             *SettingsList           `json:"settings,omitempty"`
+}
+
+// BuildTrigger is a trigger of a build configuration; its properties hold the branch filter, schedule, or dependency.
+type BuildTrigger struct {
+	ID         string        `json:"id,omitempty"`
+	Type       string        `json:"type"`
+	Disabled   bool          `json:"disabled,omitempty"`
+	Properties *PropertyList `json:"properties,omitempty"`
+}
+
+// BuildTriggerList represents the triggers of a build configuration
+type BuildTriggerList struct {
+	Count   int            `json:"count"`
+	Trigger []BuildTrigger `json:"trigger"`
+}
+
+const buildTypeDetailFields = "id,name,projectName,projectId,webUrl,paused," +
+	"steps(" + buildStepFields + ")," +
+	"triggers(count,trigger(id,type,disabled,properties(property(name,value))))," +
+	"vcs-root-entries(count,vcs-root-entry(id,checkout-rules,vcs-root(id,name,vcsName)))," +
+	"snapshot-dependencies(count,snapshot-dependency(id,source-buildType(id,name,projectId)))," +
+	"artifact-dependencies(count,artifact-dependency(id,type,disabled,properties(property(name,value)),source-buildType(id,name,projectId)))," +
+	"settings(count,property(name,value))"
+
+// GetBuildTypeDetail returns a build configuration with its steps, triggers, VCS roots, dependencies and settings in one request.
+func (c *Client) GetBuildTypeDetail(id string) (*BuildTypeDetail, error) {
+	path := fmt.Sprintf("/app/rest/buildTypes/id:%s?fields=%s", url.PathEscape(id), url.QueryEscape(buildTypeDetailFields))
+
+	var detail BuildTypeDetail
+	if err := c.get(c.ctx(), path, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// GetTemplate returns a build configuration template by ID
+func (c *Client) GetTemplate(id string) (*BuildType, error) {
+	path := "/app/rest/buildTypes/id:" + url.PathEscape(id) + ",templateFlag:true"
+
+	var template BuildType
+	if err := c.get(c.ctx(), path, &template); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
 // SetBuildTypePaused sets the paused state of a build configuration
 func (c *Client) SetBuildTypePaused(id string, paused bool) error {
 	path := fmt.Sprintf("/app/rest/buildTypes/id:%s/paused", url.PathEscape(id))
@@ -114,6 +198,53 @@ func (c *Client) BuildTypeExists(id string) bool {
 	return err == nil
 }
 
+var (
+	internalBuildTypeIDRE = regexp.MustCompile(`^bt\d+$`)
+	uuidRE                = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// maxRenameCandidates caps how many configurations FindRenamedBuildTypes returns.
+const maxRenameCandidates = 10
+
+// FindRenamedBuildTypes returns the build configurations a reference that no longer resolves may have been
+// renamed to. Internal IDs (bt123) and UUIDs survive renames and are looked up directly; any other reference
+// is taken as an external ID, whose last _ segment is searched for as a name, case-insensitively.
+func (c *Client) FindRenamedBuildTypes(ref string) ([]BuildType, error) {
+	locator := NewLocator()
+	switch {
+	case internalBuildTypeIDRE.MatchString(ref):
+		locator.Add("internalId", ref)
+	case uuidRE.MatchString(ref):
+		locator.Add("uuid", ref)
+	default:
+		name := ref[strings.LastIndex(ref, "_")+1:]
+		if name == "" {
+			return nil, nil
+		}
+		locator.AddRaw("name", "(value:"+escapeLocatorValue(name)+",ignoreCase:true)")
+	}
+	locator.AddInt("count", maxRenameCandidates)
+
+	fieldsParam := "buildType(" + ToAPIFields([]string{"id", "name", "projectName", "projectId", "webUrl"}) + ")"
+	path := fmt.Sprintf("/app/rest/buildTypes?locator=%s&fields=%s", locator.Encode(), url.QueryEscape(fieldsParam))
+
+	var list BuildTypeList
+	if err := c.get(c.ctx(), path, &list); err != nil {
+		if _, ok := errors.AsType[*NotFoundError](err); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var candidates []BuildType
+	for _, bt := range list.BuildTypes {
+		if !strings.EqualFold(bt.ID, ref) {
+			candidates = append(candidates, bt)
+		}
+	}
+	return candidates, nil
+}
+
 // BuildStep represents a build step configuration
 type BuildStep struct {
 	ID         string       `json:"id,omitempty"`
@@ -193,6 +324,18 @@ func (c *Client) GetSnapshotDependencies(buildTypeID string) (*SnapshotDependenc
 	return &result, nil
 }
 
+// GetArtifactDependencies returns the artifact dependencies of a build configuration, including their properties.
+func (c *Client) GetArtifactDependencies(buildTypeID string) (*ArtifactDependencyList, error) {
+	path := fmt.Sprintf("/app/rest/buildTypes/id:%s/artifact-dependencies?fields=count,artifact-dependency(id,type,disabled,properties(property(name,value)),source-buildType(id,name,projectId))", url.PathEscape(buildTypeID))
+
+	var result ArtifactDependencyList
+	if err := c.get(c.ctx(), path, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // GetDependentBuildTypes returns build types that have a snapshot dependency on the given build type.
 func (c *Client) GetDependentBuildTypes(buildTypeID string) (*BuildTypeList, error) {
 	path := fmt.Sprintf("/app/rest/buildTypes?locator=snapshotDependency:(from:(id:%s),recursive:false)&fields=count,buildType(id,name,projectId)", buildTypeID)
@@ -217,6 +360,20 @@ func (c *Client) GetVcsRootEntries(buildTypeID string) (*VcsRootEntries, error)
 	return &result, nil
 }
 
+// GetBuildTypeBranches returns every branch of a build configuration, active or not, including the default one.
+func (c *Client) GetBuildTypeBranches(ctx context.Context, buildTypeID string) (*BranchList, error) {
+	locator := NewLocator().Add("policy", "ALL_BRANCHES")
+	path := fmt.Sprintf("/app/rest/buildTypes/id:%s/branches?locator=%s&fields=%s", url.PathEscape(buildTypeID),
+		locator.Encode(), url.QueryEscape("count,branch(name,default,unspecified,active,lastActivity)"))
+
+	var result BranchList
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // SetBuildTypeSetting sets a build configuration setting
 func (c *Client) SetBuildTypeSetting(buildTypeID, setting, value string) error {
 	path := fmt.Sprintf("/app/rest/buildTypes/id:%s/settings/%s", url.PathEscape(buildTypeID), url.PathEscape(setting))
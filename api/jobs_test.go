@@ -40,6 +40,20 @@ func TestGetBuildTypesVcsRootURLFilter(t *testing.T) {
 	assert.Contains(t, seenLocator, "vcsRoot:(property:(name:url,value:acme/repo,matchType:contains))")
 }
 
+func TestGetBuildTypesVcsRootFilter(t *testing.T) {
+	t.Parallel()
+	var seenLocator string
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		seenLocator = r.URL.Query().Get("locator")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(BuildTypeList{Count: 0})
+	})
+
+	_, _, err := client.GetBuildTypes(BuildTypesOptions{VcsRoot: "App_Git"})
+	require.NoError(t, err)
+	assert.Contains(t, seenLocator, "vcsRoot:(id:App_Git)")
+}
+
 func TestGetBuildType(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +67,19 @@ func TestGetBuildType(t *testing.T) {
 	assert.Equal(t, "Build", bt.Name)
 }
 
+func TestGetTemplate(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/buildTypes/id:Tmpl,templateFlag:true", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BuildType{ID: "Tmpl", Name: "Template"})
+	})
+
+	tmpl, err := client.GetTemplate("Tmpl")
+	require.NoError(t, err)
+	assert.Equal(t, "Template", tmpl.Name)
+}
+
 func TestSetBuildTypePaused(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -89,6 +116,54 @@ func TestBuildTypeExists(t *testing.T) {
 	})
 }
 
+func TestFindRenamedBuildTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref     string
+		locator string
+	}{
+		{"bt42", "internalId:bt42"},
+		{"0b6e3f2a-5d1c-4c8e-9a7b-2f4d6e8a0c1b", "uuid:0b6e3f2a-5d1c-4c8e-9a7b-2f4d6e8a0c1b"},
+		{"Falcon_Build", "name:(value:Build,ignoreCase:true)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			t.Parallel()
+			client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/app/rest/buildTypes", r.URL.Path)
+				assert.Contains(t, r.URL.Query().Get("locator"), tc.locator)
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(BuildTypeList{BuildTypes: []BuildType{{ID: "Falcon_Build"}, {ID: "Falcon_Backend_Build"}}})
+			})
+
+			candidates, err := client.FindRenamedBuildTypes(tc.ref)
+			require.NoError(t, err)
+			var ids []string
+			for _, bt := range candidates {
+				ids = append(ids, bt.ID)
+			}
+			if tc.ref == "Falcon_Build" {
+				assert.Equal(t, []string{"Falcon_Backend_Build"}, ids, "the reference itself is not a candidate")
+			} else {
+				assert.Equal(t, []string{"Falcon_Build", "Falcon_Backend_Build"}, ids)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"No build type found by locator 'internalId:bt42'."}]}`))
+		})
+		candidates, err := client.FindRenamedBuildTypes("bt42")
+		require.NoError(t, err)
+		assert.Empty(t, candidates)
+	})
+}
+
 func TestCreateBuildType(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -261,3 +336,21 @@ func TestGetBuildStepsEmptyIsNonNil(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, string(b), `"step":[]`)
 }
+
+func TestGetBuildTypeBranches(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/buildTypes/id:Falcon_Build/branches", r.URL.Path)
+		assert.Equal(t, "policy:ALL_BRANCHES", r.URL.Query().Get("locator"))
+		assert.Contains(t, r.URL.Query().Get("fields"), "lastActivity")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":2,"branch":[{"name":"<default>","default":true,"active":true},{"name":"feature/old","lastActivity":"20250110T080607+0000"}]}`))
+	})
+
+	branches, err := client.GetBuildTypeBranches(t.Context(), "Falcon_Build")
+	require.NoError(t, err)
+	require.Len(t, branches.Branch, 2)
+	assert.True(t, branches.Branch[0].Default)
+	assert.False(t, branches.Branch[1].Active)
+	assert.Equal(t, "20250110T080607+0000", branches.Branch[1].LastActivity)
+}
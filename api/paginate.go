@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/url"
 	"strings"
 )
@@ -16,36 +17,81 @@ func pageCount(limit int) int {
 	return limit
 }
 
+// pageSize returns the per-page item count for a fetch chunked into pages of size: size when set and below a finite limit, otherwise pageCount(limit).
+func pageSize(limit, size int) int {
+	if size > 0 && (limit == 0 || size < limit) {
+		return size
+	}
+	return pageCount(limit)
+}
+
 // collectPages follows NextHref links to accumulate items up to the limit (0 collects all); the bool is true when a finite limit capped the result and more exist.
+// When a page fails, it returns the items of the pages before it along with the error.
 func collectPages[T any](c *Client, path string, limit int, fetch func(string) ([]T, string, error)) ([]T, bool, error) {
+	return streamPages(c, path, limit, fetch, nil)
+}
+
+// streamPages is collectPages that also hands each page, trimmed to the limit, to onPage as it arrives; an error from onPage stops paging and is returned.
+func streamPages[T any](c *Client, path string, limit int, fetch func(string) ([]T, string, error), onPage func([]T) error) ([]T, bool, error) {
 	all := []T{} // non-nil so an empty result serializes as JSON [] not null
 	for path != "" {
 		items, nextHref, err := fetch(path)
 		if err != nil {
-			return nil, false, err
+			return all, false, err
+		}
+		next, err := c.NormalizePaginationPath(nextHref)
+		if err != nil {
+			return all, false, err
+		}
+		truncated := false
+		if limit > 0 && len(all)+len(items) >= limit {
+			truncated = len(all)+len(items) > limit || next != ""
+			items, next = items[:limit-len(all)], ""
 		}
 		all = append(all, items...)
-		if limit > 0 && len(all) >= limit {
-			truncated := len(all) > limit || c.NormalizePaginationPath(nextHref) != ""
-			return all[:limit], truncated, nil
+		if onPage != nil {
+			if err := onPage(items); err != nil {
+				return nil, false, err
+			}
 		}
-		path = c.NormalizePaginationPath(nextHref)
+		if next == "" {
+			return all, truncated, nil
+		}
+		path = next
 	}
 	return all, false, nil
 }
 
+// sameOrigin reports whether a and b have the same scheme, host, and port, an omitted port being the scheme's default.
+func sameOrigin(a, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		if strings.EqualFold(u.Scheme, "http") {
+			return "80"
+		}
+		return "443"
+	}
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Hostname(), b.Hostname()) && port(a) == port(b)
+}
+
 // NormalizePaginationPath converts a TeamCity NextHref value into a path
 // suitable for c.get / RawRequest. It strips the scheme/host, context path,
 // guestAuth prefix, and API version so that apiPath() can re-apply them consistently.
-func (c *Client) NormalizePaginationPath(href string) string {
+// An href on another origin than BaseURL (host, scheme, or port) is an error rather than a path to follow.
+func (c *Client) NormalizePaginationPath(href string) (string, error) {
 	if href == "" {
-		return ""
+		return "", nil
 	}
 
 	path := href
 
-	// Absolute URL → path+query only
-	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+	// Absolute URL → path+query only, once it is known to be on this server
+	if u, err := url.Parse(path); err == nil && u.Host != "" {
+		if base, err := url.Parse(c.BaseURL); err != nil || !sameOrigin(u, base) {
+			return "", fmt.Errorf("refusing to follow nextHref %q: it is not on the configured server %s", href, c.BaseURL)
+		}
 		path = u.RequestURI()
 	}
 
@@ -69,5 +115,5 @@ func (c *Client) NormalizePaginationPath(href string) string {
 		}
 	}
 
-	return path
+	return path, nil
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -131,6 +132,66 @@ func TestPageCount(t *testing.T) {
 	assert.Equal(t, 1, pageCount(1))
 }
 
+func TestPageSize(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, allPageSize, pageSize(0, 0))
+	assert.Equal(t, 100, pageSize(0, 100))
+	assert.Equal(t, 30, pageSize(30, 100))
+	assert.Equal(t, 100, pageSize(500, 100))
+}
+
+func TestStreamPages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hands each page over as it arrives, trimmed to the limit", func(t *testing.T) {
+		t.Parallel()
+		c := &Client{BaseURL: "http://localhost"}
+		var pages [][]int
+		items, truncated, err := streamPages(c, "/app/rest/builds", 5, func(path string) ([]int, string, error) {
+			n := len(pages) * 10
+			return []int{n, n + 1, n + 2}, "/app/rest/builds?next", nil
+		}, func(page []int) error {
+			pages = append(pages, page)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][]int{{0, 1, 2}, {10, 11}}, pages)
+		assert.Equal(t, []int{0, 1, 2, 10, 11}, items)
+		assert.True(t, truncated)
+	})
+
+	t.Run("a failed page keeps the pages before it", func(t *testing.T) {
+		t.Parallel()
+		c := &Client{BaseURL: "http://localhost"}
+		failed := errors.New("interrupted")
+		call := 0
+		items, _, err := collectPages(c, "/app/rest/builds", 0, func(path string) ([]int, string, error) {
+			call++
+			if call == 2 {
+				return nil, "", failed
+			}
+			return []int{1, 2}, "/app/rest/builds?next", nil
+		})
+		assert.ErrorIs(t, err, failed)
+		assert.Equal(t, []int{1, 2}, items)
+	})
+
+	t.Run("an error from onPage stops paging", func(t *testing.T) {
+		t.Parallel()
+		c := &Client{BaseURL: "http://localhost"}
+		stop := errors.New("stop")
+		call := 0
+		_, _, err := streamPages(c, "/app/rest/builds", 0, func(path string) ([]int, string, error) {
+			call++
+			return []int{call}, "/app/rest/builds?next", nil
+		}, func(page []int) error {
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, call)
+	})
+}
+
 func TestNormalizePaginationPath(t *testing.T) {
 	t.Parallel()
 
@@ -173,8 +234,32 @@ func TestNormalizePaginationPath(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			c := &Client{BaseURL: tt.baseURL, APIVersion: tt.apiVersion}
-			got := c.NormalizePaginationPath(tt.href)
+			got, err := c.NormalizePaginationPath(tt.href)
+			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
+
+	t.Run("another origin is refused", func(t *testing.T) {
+		t.Parallel()
+		c := &Client{BaseURL: "https://teamcity.example.com"}
+		for _, href := range []string{
+			"https://evil.example.net/app/rest/builds?locator=start:100",
+			"//evil.example.net/app/rest/builds",
+			"http://teamcity.example.com/app/rest/builds?locator=start:100",
+			"https://teamcity.example.com:8443/app/rest/builds?locator=start:100",
+		} {
+			_, err := c.NormalizePaginationPath(href)
+			require.Error(t, err, href)
+			assert.Contains(t, err.Error(), "not on the configured server")
+		}
+		for _, href := range []string{
+			"https://TeamCity.example.com/app/rest/builds?locator=start:100",
+			"https://teamcity.example.com:443/app/rest/builds?locator=start:100",
+		} {
+			got, err := c.NormalizePaginationPath(href)
+			require.NoError(t, err, href)
+			assert.Equal(t, "/app/rest/builds?locator=start:100", got)
+		}
+	})
 }
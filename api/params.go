@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // ParameterList represents a list of parameters
@@ -15,11 +16,18 @@ type ParameterList struct {
 	Property []Parameter `json:"property"`
 }
 
-// Parameter represents a TeamCity parameter
+// Parameter represents a TeamCity parameter. Inherited is set when the value comes from a parent
+// project or template rather than the owner itself.
 type Parameter struct {
-	Name  string         `json:"name"`
-	Value string         `json:"value"`
-	Type  *ParameterType `json:"type,omitempty"`
+	Name      string         `json:"name"`
+	Value     string         `json:"value"`
+	Type      *ParameterType `json:"type,omitempty"`
+	Inherited bool           `json:"inherited,omitempty"`
+}
+
+// IsPassword reports whether p is a password (secure) parameter, whose value must not be shown.
+func (p Parameter) IsPassword() bool {
+	return p.Type != nil && strings.HasPrefix(p.Type.RawValue, "password")
 }
 
 // ParameterType represents parameter type info
@@ -39,7 +47,7 @@ func (c *Client) getParameters(basePath string) (*ParameterList, error) {
 }
 
 func (c *Client) getParameter(basePath, name string) (*Parameter, error) {
-	path := fmt.Sprintf("%s/parameters/%s", basePath, url.PathEscape(name))
+	path := fmt.Sprintf("%s/parameters/%s?fields=name,value,inherited,type(rawValue)", basePath, url.PathEscape(name))
 
 	var param Parameter
 	if err := c.get(c.ctx(), path, &param); err != nil {
@@ -96,13 +96,15 @@ func TestGetBuildTypeParameters(t *testing.T) {
 func TestGetBuildTypeParameter(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("fields"), "inherited")
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(Parameter{Name: "p1", Value: "v1"})
+		_, _ = io.WriteString(w, `{"name":"p1","value":"v1","inherited":true}`)
 	})
 
 	p, err := client.GetBuildTypeParameter("MyBuild", "p1")
 	require.NoError(t, err)
 	assert.Equal(t, "v1", p.Value)
+	assert.True(t, p.Inherited)
 }
 
 func TestSetBuildTypeParameter(t *testing.T) {
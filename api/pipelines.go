@@ -83,7 +83,7 @@ func (c *Client) GetPipelineYAML(id string) (string, error) {
 // CreatePipeline creates a new pipeline in the given project with a VCS root.
 func (c *Client) CreatePipeline(parentProjectID, name, yaml, vcsRootID string) (*Pipeline, error) {
 	if c.ReadOnly {
-		return nil, fmt.Errorf("%w: POST /app/pipeline", ErrReadOnly)
+		return nil, fmt.Errorf("%w: POST /app/pipeline", c.readOnlyError())
 	}
 
 	req := CreatePipelineRequest{
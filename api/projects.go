@@ -19,13 +19,17 @@ type ProjectsOptions struct {
 	Permission string
 	// ExcludeArchived, when true, drops archived projects (which can't accept new features).
 	ExcludeArchived bool
+	// PageSize, when set, fetches at most this many per request instead of one page sized to Limit.
+	PageSize int
+	// OnPage, when set, receives each page as it arrives; an error from it stops the fetch and is returned.
+	OnPage func([]Project) error
 }
 
 // GetProjects returns a list of projects, following pagination; the bool is true when a finite limit capped the result.
 func (c *Client) GetProjects(opts ProjectsOptions) (*ProjectList, bool, error) {
 	locator := NewLocator().
 		Add("parentProject", opts.Parent).
-		AddInt("count", pageCount(opts.Limit))
+		AddInt("count", pageSize(opts.Limit, opts.PageSize))
 	if opts.Permission != "" {
 		locator.AddLocator("userPermission", NewLocator().
 			Add("permission", opts.Permission).
@@ -42,13 +46,13 @@ func (c *Client) GetProjects(opts ProjectsOptions) (*ProjectList, bool, error) {
 	fieldsParam := fmt.Sprintf("count,nextHref,project(%s)", ToAPIFields(fields))
 	path := fmt.Sprintf("/app/rest/projects?locator=%s&fields=%s", locator.Encode(), url.QueryEscape(fieldsParam))
 
-	projects, truncated, err := collectPages(c, path, opts.Limit, func(p string) ([]Project, string, error) {
+	projects, truncated, err := streamPages(c, path, opts.Limit, func(p string) ([]Project, string, error) {
 		var page ProjectList
 		if err := c.get(c.ctx(), p, &page); err != nil {
 			return nil, "", err
 		}
 		return page.Projects, page.NextHref, nil
-	})
+	}, opts.OnPage)
 	if err != nil {
 		return nil, false, err
 	}
@@ -90,6 +94,38 @@ func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
 	return &project, nil
 }
 
+// CopyProjectRequest describes the project CopyProject creates.
+type CopyProjectRequest struct {
+	ID            string      `json:"id,omitempty"`
+	Name          string      `json:"name"`
+	ParentProject *ProjectRef `json:"parentProject,omitempty"`
+	SourceProject *ProjectRef `json:"sourceProject,omitempty"`
+	// CopyAllAssociatedSettings also copies the VCS roots, templates, and other settings the source uses from its parent projects.
+	CopyAllAssociatedSettings bool `json:"copyAllAssociatedSettings"`
+}
+
+// CopyProject creates a copy of a project with its subprojects and build configurations
+func (c *Client) CopyProject(sourceID string, req CopyProjectRequest) (*Project, error) {
+	req.SourceProject = &ProjectRef{ID: sourceID}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var project Project
+	if err := c.post(c.ctx(), "/app/rest/projects", bytes.NewReader(body), &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// SetProjectField sets a single project field, such as name or description.
+func (c *Client) SetProjectField(projectID, field, value string) error {
+	path := fmt.Sprintf("/app/rest/projects/id:%s/%s", url.PathEscape(projectID), url.PathEscape(field))
+	return c.doNoContent(c.ctx(), "PUT", path, strings.NewReader(value), "text/plain")
+}
+
 // ProjectExists checks if a project exists
 func (c *Client) ProjectExists(id string) bool {
 	_, err := c.GetProject(id)
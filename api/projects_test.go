@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -36,6 +37,43 @@ func TestGetProjectsLocator(t *testing.T) {
 	assert.Contains(t, decoded, "archived:false")
 }
 
+func TestCopyProject(t *testing.T) {
+	t.Parallel()
+
+	var payload map[string]any
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/app/rest/projects", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		_ = json.NewEncoder(w).Encode(Project{ID: "Copy", Name: "Copy", ParentProjectID: "Parent"})
+	})
+
+	project, err := client.CopyProject("Source", CopyProjectRequest{
+		ID:            "Copy",
+		Name:          "Copy",
+		ParentProject: &ProjectRef{ID: "Parent"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Copy", project.ID)
+	assert.Equal(t, map[string]any{"id": "Source"}, payload["sourceProject"])
+	assert.Equal(t, map[string]any{"id": "Parent"}, payload["parentProject"])
+	assert.Equal(t, false, payload["copyAllAssociatedSettings"])
+}
+
+func TestSetProjectField(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/app/rest/projects/id:P1/description", r.URL.Path)
+		assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "Nightly jobs", string(body))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	require.NoError(t, client.SetProjectField("P1", "description", "Nightly jobs"))
+}
+
 func TestGetVersionedSettingsStatus(T *testing.T) {
 	T.Parallel()
 
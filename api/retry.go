@@ -40,6 +40,11 @@ const maxRetryDrain = 4 << 10
 
 // withRetry retries op on transient network errors, 429, and 5xx (except 501/505), honoring Retry-After and ctx cancellation. Timeouts are not retried.
 func withRetry(ctx context.Context, cfg RetryConfig, op func() (*http.Response, error)) (*http.Response, error) {
+	return withRetryNotify(ctx, cfg, nil, op)
+}
+
+// withRetryNotify is withRetry calling notify, when non-nil, before each retry with why and how long it waits.
+func withRetryNotify(ctx context.Context, cfg RetryConfig, notify backoff.Notify, op func() (*http.Response, error)) (*http.Response, error) {
 	if cfg.MaxRetries == 0 {
 		return op()
 	}
@@ -69,10 +74,10 @@ func withRetry(ctx context.Context, cfg RetryConfig, op func() (*http.Response,
 			return resp, nil
 		}
 		if d := retryAfter(resp); d > 0 {
-			return resp, &backoff.RetryAfterError{Duration: d}
+			return resp, fmt.Errorf("server returned %d: %w", resp.StatusCode, &backoff.RetryAfterError{Duration: d})
 		}
 		return resp, fmt.Errorf("server returned %d", resp.StatusCode)
-	}, backoff.WithBackOff(expo), backoff.WithMaxTries(cfg.MaxRetries+1), backoff.WithMaxElapsedTime(0))
+	}, backoff.WithBackOff(expo), backoff.WithMaxTries(cfg.MaxRetries+1), backoff.WithMaxElapsedTime(0), backoff.WithNotify(notify))
 }
 
 // isRetryableNetworkError reports whether err is a transient network issue worth retrying; timeouts are excluded since retrying just re-runs the same slow op.
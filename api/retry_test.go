@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -304,3 +307,32 @@ func TestClientRetryBehavior(T *testing.T) {
 		assert.Equal(t, int32(1), attempts.Load())
 	})
 }
+
+// TestWithRetries pins that WithRetries replaces ReadRetry for reads and that each retry is logged.
+func TestWithRetries(T *testing.T) {
+	T.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	T.Cleanup(server.Close)
+
+	var debug []string
+	client := NewClient(server.URL, "test-token", WithRetries(1, 10*time.Millisecond), WithDebugFunc(func(format string, args ...any) {
+		debug = append(debug, fmt.Sprintf(format, args...))
+	}))
+	err := client.get(T.Context(), "/app/rest/server", nil)
+
+	require.Error(T, err)
+	assert.Equal(T, int32(2), attempts.Load())
+	assert.True(T, slices.ContainsFunc(debug, func(line string) bool {
+		return strings.HasPrefix(line, "Retry 1/1 of GET /app/rest/server in ") && strings.HasSuffix(line, ": server returned 502")
+	}), "retry not logged: %q", debug)
+
+	attempts.Store(0)
+	client = NewClient(server.URL, "test-token", WithRetries(0, 0))
+	require.Error(T, client.get(T.Context(), "/app/rest/server", nil))
+	assert.Equal(T, int32(1), attempts.Load(), "0 retries disables retrying")
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maxDebugBody caps the request bodies written to debug output. A larger body is logged as its size
+// only: cutting it first could split a secret and leave part of it unmasked.
+const maxDebugBody = 4096
+
+// secretJSONValue matches a JSON string member whose key names a secret; group 1 is everything before the value.
+var secretJSONValue = regexp.MustCompile(`(?i)("[^"]*(?:password|passwd|token|secret)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// passwordParameter matches the type spec of a password parameter in a parameter body.
+var passwordParameter = regexp.MustCompile(`"rawValue"\s*:\s*"password`)
+
+// sensitiveBody reports whether a request body to path is secret as a whole: secure token values,
+// password parameters, and new users with their initial password.
+func sensitiveBody(method, path string, body []byte) bool {
+	path, _, _ = strings.Cut(path, "?")
+	switch {
+	case strings.HasSuffix(path, "/secure/tokens"):
+		return true
+	case method == http.MethodPost && strings.HasSuffix(path, "/app/rest/users"):
+		return true
+	case strings.Contains(path, "/parameters"):
+		return passwordParameter.Match(body)
+	}
+	return false
+}
+
+// scrubSecrets masks the value of every JSON member whose key names a password, token, or secret.
+func scrubSecrets(s string) string {
+	return secretJSONValue.ReplaceAllString(s, `${1}"`+redacted+`"`)
+}
+
+// scrubBody renders a request body for debug output with its secrets masked.
+func scrubBody(method, path string, body []byte) string {
+	if sensitiveBody(method, path, body) {
+		return fmt.Sprintf("[REDACTED %d bytes]", len(body))
+	}
+	return scrubSecrets(string(body))
+}
+
+// debugBody returns the request's body for debug output, or "" when it can't be read without
+// consuming it. It reads a copy from req.GetBody and leaves req untouched.
+func debugBody(req *http.Request) string {
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	textual := mediaType == "application/json" || strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "xml")
+	if !textual || req.ContentLength > maxDebugBody {
+		return fmt.Sprintf("[%d bytes]", req.ContentLength)
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = rc.Close() }()
+	body, err := io.ReadAll(io.LimitReader(rc, maxDebugBody+1))
+	if err != nil || len(body) > maxDebugBody {
+		return ""
+	}
+	return scrubBody(req.Method, req.URL.Path, body)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubSecrets(T *testing.T) {
+	T.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"password key", `{"username":"bob","password":"hunter2"}`, `{"username":"bob","password":"[REDACTED]"}`},
+		{"key containing token", `{"apiToken": "abc", "name":"x"}`, `{"apiToken": "[REDACTED]", "name":"x"}`},
+		{"case-insensitive", `{"clientSecret":"s","SECRET_KEY":"t"}`, `{"clientSecret":"[REDACTED]","SECRET_KEY":"[REDACTED]"}`},
+		{"escaped quote in value", `{"password":"a\"b","x":1}`, `{"password":"[REDACTED]","x":1}`},
+		{"nested", `{"auth":{"token":"abc"}}`, `{"auth":{"token":"[REDACTED]"}}`},
+		{"non-string value kept", `{"tokenCount":3}`, `{"tokenCount":3}`},
+		{"other keys kept", `{"name":"password","value":"x"}`, `{"name":"password","value":"x"}`},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, scrubSecrets(tc.in))
+		})
+	}
+}
+
+func TestSensitiveBody(T *testing.T) {
+	T.Parallel()
+
+	assert.True(T, sensitiveBody("POST", "/app/rest/projects/P/secure/tokens", []byte("x")))
+	assert.True(T, sensitiveBody("POST", "/app/rest/users", []byte("{}")))
+	assert.False(T, sensitiveBody("PUT", "/app/rest/users/id:1/roles", []byte("{}")))
+	assert.True(T, sensitiveBody("PUT", "/app/rest/projects/id:P/parameters/X", []byte(`{"name":"X","value":"v","type":{"rawValue":"password"}}`)))
+	assert.False(T, sensitiveBody("PUT", "/app/rest/projects/id:P/parameters/X", []byte(`{"name":"X","value":"v"}`)))
+}
+
+// debugServer returns a client whose debug output goes to the returned buffer, and the bodies its server received.
+func debugServer(t *testing.T, respond string) (*Client, *bytes.Buffer, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, string(body))
+		mu.Unlock()
+		_, _ = w.Write([]byte(respond))
+	}))
+	t.Cleanup(server.Close)
+
+	var buf bytes.Buffer
+	client := NewClient(server.URL, "test-token", WithDebugFunc(func(format string, args ...any) {
+		fmt.Fprintf(&buf, format+"\n", args...)
+	}))
+	return client, &buf, &received
+}
+
+func TestDebugLogScrubsRequestBodies(T *testing.T) {
+	T.Parallel()
+	const secret = "s3cr3t-Value-42"
+
+	T.Run("secure project parameter", func(t *testing.T) {
+		t.Parallel()
+		client, debug, received := debugServer(t, `{}`)
+
+		require.NoError(t, client.SetProjectParameter("P", "DEPLOY_KEY", secret, true))
+		assert.NotContains(t, debug.String(), secret)
+		assert.Contains(t, debug.String(), "[REDACTED ")
+		require.Len(t, *received, 1)
+		assert.Contains(t, (*received)[0], secret, "the request itself must carry the value")
+	})
+
+	T.Run("secure token", func(t *testing.T) {
+		t.Parallel()
+		client, debug, received := debugServer(t, "credentialsJSON:abc")
+
+		_, err := client.CreateSecureToken("P", secret)
+		require.NoError(t, err)
+		assert.NotContains(t, debug.String(), secret)
+		assert.Contains(t, debug.String(), fmt.Sprintf("[REDACTED %d bytes]", len(secret)))
+		assert.Equal(t, []string{secret}, *received)
+	})
+
+	T.Run("new user", func(t *testing.T) {
+		t.Parallel()
+		client, debug, received := debugServer(t, `{"username":"bob"}`)
+
+		_, err := client.CreateUser(CreateUserRequest{Username: "bob", Password: secret})
+		require.NoError(t, err)
+		assert.NotContains(t, debug.String(), secret)
+		assert.Contains(t, (*received)[0], secret)
+	})
+
+	T.Run("secret keys in any JSON body", func(t *testing.T) {
+		t.Parallel()
+		client, debug, received := debugServer(t, `{}`)
+
+		body := `{"name":"deploy","clientSecret":"` + secret + `"}`
+		_, err := client.RawRequest(t.Context(), "POST", "/app/rest/whatever", strings.NewReader(body), nil)
+		require.NoError(t, err)
+		assert.NotContains(t, debug.String(), secret)
+		assert.Contains(t, debug.String(), `> {"name":"deploy","clientSecret":"[REDACTED]"}`)
+		assert.Equal(t, []string{body}, *received)
+	})
+
+	T.Run("plain parameter is logged", func(t *testing.T) {
+		t.Parallel()
+		client, debug, _ := debugServer(t, `{}`)
+
+		require.NoError(t, client.SetProjectParameter("P", "GREETING", "hello", false))
+		assert.Contains(t, debug.String(), `"value":"hello"`)
+	})
+
+	T.Run("large and binary bodies are logged by size", func(t *testing.T) {
+		t.Parallel()
+		client, debug, _ := debugServer(t, `{}`)
+
+		large := `{"password":"` + secret + `","pad":"` + strings.Repeat("x", maxDebugBody) + `"}`
+		_, err := client.RawRequest(t.Context(), "POST", "/app/rest/whatever", strings.NewReader(large), nil)
+		require.NoError(t, err)
+		_, err = client.RawRequest(t.Context(), "POST", "/app/rest/whatever", strings.NewReader(secret),
+			map[string]string{"Content-Type": "application/octet-stream"})
+		require.NoError(t, err)
+
+		assert.NotContains(t, debug.String(), secret)
+		assert.Contains(t, debug.String(), fmt.Sprintf("> [%d bytes]", len(large)))
+		assert.Contains(t, debug.String(), fmt.Sprintf("> [%d bytes]", len(secret)))
+	})
+}
+
+func TestDryRunSummaryScrubsSecrets(t *testing.T) {
+	t.Parallel()
+	got := summarizeBody(map[string]string{"name": "ci", "token": "abc123"})
+	assert.NotContains(t, got, "abc123")
+	assert.Contains(t, got, `"token":"[REDACTED]"`)
+}
@@ -433,8 +433,8 @@ func setupServer(serverURL, superToken, projectID, configID string) (string, err
 	}
 
 	adminClient := api.NewClientWithBasicAuth(serverURL, "admin", "admin123")
-	_ = adminClient.DeleteAPIToken("tc-cli-test")
-	token, err := adminClient.CreateAPIToken("tc-cli-test")
+	_ = adminClient.DeleteAccessToken(context.Background(), "tc-cli-test")
+	token, err := adminClient.CreateAccessToken(context.Background(), api.Token{Name: "tc-cli-test"})
 	if err != nil {
 		return "", err
 	}
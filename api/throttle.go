@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxThrottleRetries bounds how often a write rejected with 429 is resent; reads already retry through withRetry.
+const maxThrottleRetries = 3
+
+// maxThrottleWait caps a single Retry-After sleep so a misbehaving proxy can't park the CLI indefinitely.
+const maxThrottleWait = time.Minute
+
+// Throttle paces requests to an optional client-side rate and records server-side throttling; share one across clients to pace them together.
+type Throttle struct {
+	interval time.Duration
+	backoff  time.Duration // base wait when the server sends no usable Retry-After
+	notify   func(wait time.Duration)
+
+	mu   sync.Mutex
+	next time.Time
+
+	hits atomic.Int64
+}
+
+// NewThrottle allows at most maxRPS requests per second (<= 0 for unlimited); notify, if set, runs on every throttled response with the wait the server asked for.
+func NewThrottle(maxRPS float64, notify func(wait time.Duration)) *Throttle {
+	t := &Throttle{backoff: time.Second, notify: notify}
+	if maxRPS > 0 {
+		t.interval = time.Duration(float64(time.Second) / maxRPS)
+	}
+	return t
+}
+
+// Hits returns how many throttled responses (429, or 503 with Retry-After) the server has sent so far; nil-safe.
+func (t *Throttle) Hits() int64 {
+	if t == nil {
+		return 0
+	}
+	return t.hits.Load()
+}
+
+// wait blocks until the next request slot under the client-side rate, or ctx is done.
+func (t *Throttle) wait(ctx context.Context) error {
+	if t.interval <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	slot := time.Now()
+	if t.next.After(slot) {
+		slot = t.next
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	return sleepCtx(ctx, time.Until(slot))
+}
+
+// record counts a throttled response and tells the caller how long the server asked to wait.
+func (t *Throttle) record(wait time.Duration) {
+	t.hits.Add(1)
+	if t.notify != nil {
+		t.notify(wait)
+	}
+}
+
+// WithThrottle routes every request through t: client-side pacing, throttling counts, and bounded 429 resends for writes.
+func WithThrottle(t *Throttle) ClientOption {
+	return func(c *Client) {
+		if t == nil {
+			return
+		}
+		base := c.HTTPClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		c.HTTPClient.Transport = &throttleTransport{base: base, throttle: t}
+	}
+}
+
+// throttleTransport applies a Throttle to each request and resends writes rejected with 429, which TeamCity refuses before processing.
+type throttleTransport struct {
+	base     http.RoundTripper
+	throttle *Throttle
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.throttle.wait(req.Context()); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || !isThrottled(resp) {
+			return resp, err
+		}
+
+		wait := min(retryAfter(resp), maxThrottleWait)
+		if wait <= 0 {
+			wait = t.throttle.backoff << attempt
+		}
+		t.throttle.record(wait)
+
+		// Reads go back to withRetry, which honors the same Retry-After; a 503 may have been processed, so only 429 writes are resent.
+		if isIdempotent(req.Method) || resp.StatusCode != http.StatusTooManyRequests || attempt >= maxThrottleRetries {
+			return resp, nil
+		}
+		next := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			if next.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryDrain))
+		_ = resp.Body.Close()
+		if err := sleepCtx(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// isThrottled reports whether resp is the server asking the client to slow down.
+func isThrottled(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != ""
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// sleepCtx sleeps for d, returning early with ctx's error if it is canceled first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
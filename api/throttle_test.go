@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupThrottledServer(t *testing.T, handler http.HandlerFunc, th *Throttle) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "test-token", WithThrottle(th))
+}
+
+func TestThrottle_WriteHonorsRetryAfter(T *testing.T) {
+	T.Parallel()
+
+	var calls atomic.Int32
+	var notified []time.Duration
+	th := NewThrottle(0, func(wait time.Duration) { notified = append(notified, wait) })
+	client := setupThrottledServer(T, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, th)
+
+	start := time.Now()
+	resp, err := client.RawRequest(T.Context(), "POST", "/app/rest/buildQueue", strings.NewReader(`{}`), nil)
+	require.NoError(T, err)
+	assert.Equal(T, http.StatusOK, resp.StatusCode)
+	assert.Equal(T, int32(2), calls.Load())
+	assert.GreaterOrEqual(T, time.Since(start), 900*time.Millisecond, "resend must wait for Retry-After")
+	assert.Equal(T, int64(1), th.Hits())
+	assert.Equal(T, []time.Duration{time.Second}, notified)
+}
+
+func TestThrottle_WriteRetriesBounded(T *testing.T) {
+	T.Parallel()
+
+	var calls atomic.Int32
+	th := NewThrottle(0, nil)
+	th.backoff = time.Millisecond
+	client := setupThrottledServer(T, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, th)
+
+	_, err := client.RawRequest(T.Context(), "POST", "/app/rest/buildQueue", strings.NewReader(`{}`), nil)
+	require.NoError(T, err)
+	assert.Equal(T, int32(maxThrottleRetries+1), calls.Load())
+	assert.Equal(T, int64(maxThrottleRetries+1), th.Hits())
+}
+
+func TestThrottle_ServiceUnavailableWriteNotResent(T *testing.T) {
+	T.Parallel()
+
+	var calls atomic.Int32
+	th := NewThrottle(0, nil)
+	client := setupThrottledServer(T, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, th)
+
+	resp, err := client.RawRequest(T.Context(), "POST", "/app/rest/buildQueue", strings.NewReader(`{}`), nil)
+	require.NoError(T, err)
+	assert.Equal(T, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(T, int32(1), calls.Load(), "a 503 write may have been processed and must not be resent")
+	assert.Equal(T, int64(1), th.Hits())
+}
+
+func TestThrottle_ReadCountedAndRetriedOnce(T *testing.T) {
+	T.Parallel()
+
+	var calls atomic.Int32
+	th := NewThrottle(0, nil)
+	client := setupThrottledServer(T, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"_Root","name":"Root"}`))
+	}, th)
+
+	start := time.Now()
+	project, err := client.GetProject("_Root")
+	require.NoError(T, err)
+	assert.Equal(T, "Root", project.Name)
+	assert.Equal(T, int32(2), calls.Load(), "reads are retried by withRetry, not resent again by the transport")
+	assert.GreaterOrEqual(T, time.Since(start), 900*time.Millisecond)
+	assert.Equal(T, int64(1), th.Hits())
+}
+
+func TestThrottle_MaxRPSPacesRequests(T *testing.T) {
+	T.Parallel()
+
+	var calls atomic.Int32
+	client := setupThrottledServer(T, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}, NewThrottle(20, nil))
+
+	start := time.Now()
+	for range 5 {
+		resp, err := client.RawRequest(T.Context(), "GET", "/app/rest/server", nil, nil)
+		require.NoError(T, err)
+		assert.Equal(T, http.StatusOK, resp.StatusCode)
+	}
+	// The first request goes out immediately; the other four wait 50ms each.
+	assert.GreaterOrEqual(T, time.Since(start), 200*time.Millisecond)
+	assert.Equal(T, int32(5), calls.Load())
+}
+
+func TestThrottle_WaitCanceled(T *testing.T) {
+	T.Parallel()
+
+	th := NewThrottle(0.5, nil)
+	require.NoError(T, th.wait(T.Context()))
+
+	ctx, cancel := context.WithCancel(T.Context())
+	cancel()
+	assert.ErrorIs(T, th.wait(ctx), context.Canceled)
+}
+
+func TestThrottle_NilHits(T *testing.T) {
+	T.Parallel()
+
+	var th *Throttle
+	assert.Zero(T, th.Hits())
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"runtime"
@@ -14,16 +15,87 @@ import (
 
 // defaultTransport returns a transport with PEM fallback when the platform TLS verifier is blocked; no ResponseHeaderTimeout, so slow scans aren't cut off.
 var defaultTransport = sync.OnceValue(func() http.RoundTripper {
-	platform := http.DefaultTransport.(*http.Transport).Clone()
+	platform := baseTransport()
 	pool := loadRootCAs()
 	if pool == nil {
 		return platform
 	}
-	pem := http.DefaultTransport.(*http.Transport).Clone()
+	pem := baseTransport()
 	pem.TLSClientConfig = &tls.Config{RootCAs: pool}
 	return &pemFallbackTransport{platform: platform, pem: pem}
 })
 
+// baseTransport clones http.DefaultTransport, going through HTTPS_PROXY, HTTP_PROXY and NO_PROXY like it does.
+func baseTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	return t
+}
+
+// TLSOptions adjust how a client verifies the server's certificate: a CA bundle to trust on top of the system's,
+// or no verification at all.
+type TLSOptions struct {
+	CACertFile         string
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether o leaves verification as the platform does it.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// Config returns the TLS configuration for o, nil when o is zero.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	cfg := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify} //nolint:gosec // asked for with insecure_skip_verify
+	if o.CACertFile == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(o.CACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("CA certificate file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = loadRootCAs()
+	}
+	if pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA certificate file %s: no PEM certificates found", o.CACertFile)
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
+
+// WithTLS makes the client verify the server as o says. It replaces the transport, so pass it before WithRoundTripper.
+// A CA file that can't be read fails every request with the reason, rather than falling back to the system's CAs.
+func WithTLS(o TLSOptions) ClientOption {
+	return func(c *Client) {
+		if o.IsZero() {
+			return
+		}
+		cfg, err := o.Config()
+		if err != nil {
+			c.HTTPClient.Transport = failingTransport{err}
+			return
+		}
+		t := baseTransport()
+		t.TLSClientConfig = cfg
+		c.HTTPClient.Transport = t
+	}
+}
+
+// failingTransport fails every request with err.
+type failingTransport struct{ err error }
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
 // pemFallbackTransport tries the platform verifier first, switching permanently to PEM on TLS errors.
 type pemFallbackTransport struct {
 	platform http.RoundTripper
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseTransportUsesProxyFromEnvironment(t *testing.T) {
+	t.Parallel()
+	assert.NotNil(t, baseTransport().Proxy)
+}
+
+func TestWithTLS(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"number":"42"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+	notPEM := filepath.Join(dir, "ca.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	getBuild := func(o TLSOptions) error {
+		_, err := NewClient(ts.URL, "token", WithTLS(o), WithRetries(0, 0)).GetBuild(t.Context(), "1")
+		return err
+	}
+
+	t.Run("self-signed is refused by default", func(t *testing.T) {
+		t.Parallel()
+		assert.Error(t, getBuild(TLSOptions{}))
+	})
+	t.Run("CA file", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, getBuild(TLSOptions{CACertFile: caFile}))
+	})
+	t.Run("skip verify", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, getBuild(TLSOptions{InsecureSkipVerify: true}))
+	})
+	t.Run("artifact downloads keep the settings", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		_, err := NewClient(ts.URL, "token", WithTLS(TLSOptions{CACertFile: caFile})).DownloadArtifactTo(t.Context(), "1", "out.txt", &buf)
+		require.NoError(t, err)
+		assert.NotEmpty(t, buf.String())
+	})
+	t.Run("missing CA file", func(t *testing.T) {
+		t.Parallel()
+		err := getBuild(TLSOptions{CACertFile: filepath.Join(dir, "missing.pem")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CA certificate file")
+	})
+	t.Run("CA file without certificates", func(t *testing.T) {
+		t.Parallel()
+		err := getBuild(TLSOptions{CACertFile: notPEM})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no PEM certificates found")
+	})
+}
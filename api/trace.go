@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+type timingKey struct{}
+
+// requestTiming records when each phase of a request ended, for the timing line of debug output. The trace hooks
+// may run on the transport's goroutines, hence the lock.
+type requestTiming struct {
+	mu sync.Mutex
+	phaseTimes
+}
+
+// phaseTimes are the marks of one attempt; a retry on a new connection starts them over.
+type phaseTimes struct {
+	start               time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte           time.Time
+	reused              bool
+}
+
+// traceTiming returns req traced for DNS, connect, TLS and time to first byte when debug logging is on, for
+// debugLogResponse to report; otherwise req itself.
+func (c *Client) traceTiming(req *http.Request) *http.Request {
+	if c.DebugFunc == nil {
+		return req
+	}
+	t := &requestTiming{}
+	at := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			t.phaseTimes = phaseTimes{start: time.Now()}
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { at(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { at(&t.dnsDone) },
+		ConnectStart:         func(string, string) { at(&t.connStart) },
+		ConnectDone:          func(string, string, error) { at(&t.connDone) },
+		TLSHandshakeStart:    func() { at(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { at(&t.tlsDone) },
+		GotFirstResponseByte: func() { at(&t.firstByte) },
+	}
+	ctx := context.WithValue(req.Context(), timingKey{}, t)
+	return req.WithContext(httptrace.WithClientTrace(ctx, trace))
+}
+
+// debugLogTiming logs how long the request behind resp spent in each phase, e.g.
+// "< Timing: dns 2ms, connect 11ms, tls 35ms, first byte 240ms".
+func (c *Client) debugLogTiming(resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+	t, ok := resp.Request.Context().Value(timingKey{}).(*requestTiming)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.start.IsZero() || t.firstByte.IsZero() {
+		return
+	}
+	var parts []string
+	phase := func(name string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s %s", name, roundTiming(to.Sub(from))))
+		}
+	}
+	if t.reused {
+		parts = append(parts, "connection reused")
+	}
+	phase("dns", t.dnsStart, t.dnsDone)
+	phase("connect", t.connStart, t.connDone)
+	phase("tls", t.tlsStart, t.tlsDone)
+	phase("first byte", t.start, t.firstByte)
+	c.debugLog("< Timing: %s", strings.Join(parts, ", "))
+}
+
+// roundTiming keeps a phase readable: whole milliseconds, or microseconds below one.
+func roundTiming(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
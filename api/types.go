@@ -43,6 +43,10 @@ type BuildType struct {
 	Paused         bool            `json:"paused,omitempty"`
 	Project        *Project        `json:"project,omitempty"`
 	VcsRootEntries *VcsRootEntries `json:"vcs-root-entries,omitempty"`
+	// LastRun is the latest finished run on the default branch, set only when listed with BuildTypesOptions.LastRun.
+	LastRun *Build `json:"lastRun,omitempty"`
+	// Builds carries the server's builds expansion that LastRun is taken from.
+	Builds *BuildList `json:"builds,omitempty"`
 }
 
 // BuildTypeList represents a list of build configurations
@@ -52,31 +56,82 @@ type BuildTypeList struct {
 	BuildTypes []BuildType `json:"buildType"`
 }
 
+// Branch is a VCS branch a build configuration has seen. Active is the server's own judgement: the branch had
+// commits or builds recently (teamcity.activeVcsBranch.age.days, a week by default).
+type Branch struct {
+	Name         string `json:"name"`
+	Default      bool   `json:"default,omitempty"`
+	Unspecified  bool   `json:"unspecified,omitempty"`
+	Active       bool   `json:"active,omitempty"`
+	LastActivity string `json:"lastActivity,omitempty"`
+}
+
+// BranchList represents the branches of a build configuration
+type BranchList struct {
+	Count  int      `json:"count"`
+	Branch []Branch `json:"branch"`
+}
+
 // Build represents a TeamCity build
 type Build struct {
-	ID                 int         `json:"id"`
-	BuildTypeID        string      `json:"buildTypeId,omitempty"`
-	Number             string      `json:"number,omitempty"`
-	Status             string      `json:"status,omitempty"`
-	State              string      `json:"state,omitempty"`
-	Personal           bool        `json:"personal,omitempty"`
-	BranchName         string      `json:"branchName,omitempty"`
-	DefaultBranch      bool        `json:"defaultBranch,omitempty"`
-	Href               string      `json:"href,omitempty"`
-	WebURL             string      `json:"webUrl,omitempty"`
-	StatusText         string      `json:"statusText,omitempty"`
-	QueuedDate         string      `json:"queuedDate,omitempty"`
-	StartDate          string      `json:"startDate,omitempty"`
-	FinishDate         string      `json:"finishDate,omitempty"`
-	BuildType          *BuildType  `json:"buildType,omitempty"`
-	Triggered          *Triggered  `json:"triggered,omitempty"`
-	Agent              *Agent      `json:"agent,omitempty"`
-	PercentageComplete int         `json:"percentageComplete,omitempty"`
-	Pinned             bool        `json:"pinned,omitempty"`
-	Tags               *TagList    `json:"tags,omitempty"`
-	LastChanges        *ChangeList `json:"lastChanges,omitempty"`
-	WaitReason         string      `json:"waitReason,omitempty"`
-	UsedByOtherBuilds  bool        `json:"usedByOtherBuilds,omitempty"`
+	ID                 int          `json:"id"`
+	BuildTypeID        string       `json:"buildTypeId,omitempty"`
+	Number             string       `json:"number,omitempty"`
+	Status             string       `json:"status,omitempty"`
+	State              string       `json:"state,omitempty"`
+	Personal           bool         `json:"personal,omitempty"`
+	BranchName         string       `json:"branchName,omitempty"`
+	DefaultBranch      bool         `json:"defaultBranch,omitempty"`
+	Href               string       `json:"href,omitempty"`
+	WebURL             string       `json:"webUrl,omitempty"`
+	StatusText         string       `json:"statusText,omitempty"`
+	QueuedDate         string       `json:"queuedDate,omitempty"`
+	StartDate          string       `json:"startDate,omitempty"`
+	FinishDate         string       `json:"finishDate,omitempty"`
+	BuildType          *BuildType   `json:"buildType,omitempty"`
+	Triggered          *Triggered   `json:"triggered,omitempty"`
+	Agent              *Agent       `json:"agent,omitempty"`
+	PercentageComplete int          `json:"percentageComplete,omitempty"`
+	RunningInfo        *RunningInfo `json:"running-info,omitempty"`
+	Pinned             bool         `json:"pinned,omitempty"`
+	Tags               *TagList     `json:"tags,omitempty"`
+	LastChanges        *ChangeList  `json:"lastChanges,omitempty"`
+	WaitReason         string       `json:"waitReason,omitempty"`
+	UsedByOtherBuilds  bool         `json:"usedByOtherBuilds,omitempty"`
+	// CanceledInfo is set as soon as someone stops the run, while it may still be running.
+	CanceledInfo *CanceledInfo `json:"canceledInfo,omitempty"`
+
+	SnapshotDependencies *BuildList `json:"snapshot-dependencies,omitempty"`
+	// ArtifactDependencies are the builds this one took artifacts from, see GetBuildArtifactDependencies.
+	ArtifactDependencies *BuildList `json:"artifact-dependencies,omitempty"`
+	// Properties are the parameters set when the run was triggered; ResultingProperties are all
+	// parameters it ran with, resolved. Both are only populated when requested, see GetBuildParameters.
+	Properties          *ParameterList `json:"properties,omitempty"`
+	ResultingProperties *ParameterList `json:"resultingProperties,omitempty"`
+}
+
+// RerunBuild is a build with the comment and VCS revisions it was triggered with, see GetBuildForRerun.
+type RerunBuild struct {
+	Build
+	Comment   *BuildComment `json:"comment,omitempty"`
+	Revisions *Revisions    `json:"revisions,omitempty"`
+}
+
+// CanceledInfo records who canceled a run, when, and the comment they gave.
+type CanceledInfo struct {
+	User      *User  `json:"user,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+// RunningInfo is the progress of a running build: how far along it is and what it is doing now
+type RunningInfo struct {
+	PercentageComplete    int    `json:"percentageComplete,omitempty"`
+	ElapsedSeconds        int    `json:"elapsedSeconds,omitempty"`
+	EstimatedTotalSeconds int    `json:"estimatedTotalSeconds,omitempty"`
+	CurrentStageText      string `json:"currentStageText,omitempty"`
+	Outdated              bool   `json:"outdated,omitempty"`
+	ProbablyHanging       bool   `json:"probablyHanging,omitempty"`
 }
 
 // BuildList represents a list of builds
@@ -106,6 +161,26 @@ type Agent struct {
 	WebURL     string `json:"webUrl,omitempty"`
 	Pool       *Pool  `json:"pool,omitempty"`
 	Build      *Build `json:"build,omitempty"`
+
+	// Populated by GetAgent and GetAgentByName only.
+	EnabledInfo          *AgentStateInfo `json:"enabledInfo,omitempty"`
+	AuthorizedInfo       *AgentStateInfo `json:"authorizedInfo,omitempty"`
+	LastActivityTime     string          `json:"lastActivityTime,omitempty"`
+	IdleSinceTime        string          `json:"idleSinceTime,omitempty"`
+	DisconnectionComment string          `json:"disconnectionComment,omitempty"`
+}
+
+// AgentStateInfo is an agent's enabled or authorized state with the comment left when it last changed
+type AgentStateInfo struct {
+	Status  bool          `json:"status"`
+	Comment *AgentComment `json:"comment,omitempty"`
+}
+
+// AgentComment is the note, author, and time of an agent state change
+type AgentComment struct {
+	Text      string `json:"text,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	User      *User  `json:"user,omitempty"`
 }
 
 // AgentList represents a list of agents
@@ -186,6 +261,9 @@ type QueuedBuild struct {
 	Triggered   *Triggered `json:"triggered,omitempty"`
 	QueuedDate  string     `json:"queuedDate,omitempty"`
 	WaitReason  string     `json:"waitReason,omitempty"`
+
+	SnapshotDependencies *BuildList     `json:"snapshot-dependencies,omitempty"`
+	Properties           *ParameterList `json:"properties,omitempty"`
 }
 
 // BuildQueue represents the build queue
@@ -198,17 +276,18 @@ type BuildQueue struct {
 
 // TriggerBuildRequest represents a request to trigger a build
 type TriggerBuildRequest struct {
-	BuildType            BuildTypeRef       `json:"buildType"`
-	BranchName           string             `json:"branchName,omitempty"`
-	Properties           *PropertyList      `json:"properties,omitempty"`
-	Comment              *BuildComment      `json:"comment,omitempty"`
-	Personal             bool               `json:"personal,omitempty"`
-	TriggeringOptions    *TriggeringOptions `json:"triggeringOptions,omitempty"`
-	Agent                *AgentRef          `json:"agent,omitempty"`
-	Tags                 *TagList           `json:"tags,omitempty"`
-	LastChanges          *LastChanges       `json:"lastChanges,omitempty"`
-	Revisions            *Revisions         `json:"revisions,omitempty"`
-	SnapshotDependencies *SnapshotDepBuilds `json:"snapshot-dependencies,omitempty"`
+	BuildType                  BuildTypeRef            `json:"buildType"`
+	BranchName                 string                  `json:"branchName,omitempty"`
+	Properties                 *PropertyList           `json:"properties,omitempty"`
+	Comment                    *BuildComment           `json:"comment,omitempty"`
+	Personal                   bool                    `json:"personal,omitempty"`
+	TriggeringOptions          *TriggeringOptions      `json:"triggeringOptions,omitempty"`
+	Agent                      *AgentRef               `json:"agent,omitempty"`
+	Tags                       *TagList                `json:"tags,omitempty"`
+	LastChanges                *LastChanges            `json:"lastChanges,omitempty"`
+	Revisions                  *Revisions              `json:"revisions,omitempty"`
+	SnapshotDependencies       *SnapshotDepBuilds      `json:"snapshot-dependencies,omitempty"`
+	CustomArtifactDependencies *ArtifactDependencyList `json:"custom-artifact-dependencies,omitempty"`
 }
 
 type SnapshotDepBuilds struct {
@@ -227,6 +306,7 @@ type Revision struct {
 
 type VcsRootInstanceRef struct {
 	VcsRootID string `json:"vcs-root-id"`
+	Name      string `json:"name,omitempty"`
 }
 
 type VcsRootEntries struct {
@@ -235,8 +315,9 @@ type VcsRootEntries struct {
 }
 
 type VcsRootEntry struct {
-	ID      string   `json:"id,omitempty"`
-	VcsRoot *VcsRoot `json:"vcs-root,omitempty"`
+	ID            string   `json:"id,omitempty"`
+	CheckoutRules string   `json:"checkout-rules,omitempty"`
+	VcsRoot       *VcsRoot `json:"vcs-root,omitempty"`
 }
 
 // LastChanges represents the changes to include in a build
@@ -283,9 +364,50 @@ type Tag struct {
 
 // ApprovalInfo represents approval information for a queued build
 type ApprovalInfo struct {
-	Status                     string `json:"status"`
-	ConfigurationValid         bool   `json:"configurationValid"`
-	CanBeApprovedByCurrentUser bool   `json:"canBeApprovedByCurrentUser"`
+	Status                     string             `json:"status"`
+	ConfigurationValid         bool               `json:"configurationValid"`
+	CanBeApprovedByCurrentUser bool               `json:"canBeApprovedByCurrentUser"`
+	UserApprovalRule           *UserApprovalRule  `json:"userApprovalRule,omitempty"`
+	GroupApprovalRule          *GroupApprovalRule `json:"groupApprovalRule,omitempty"`
+}
+
+// UserApprovalRule names the users who can approve a queued build, and how many of them must
+type UserApprovalRule struct {
+	RequiredApprovalsCount int            `json:"requiredApprovalsCount,omitempty"`
+	UserApprovals          *UserApprovals `json:"userApprovals,omitempty"`
+}
+
+// UserApprovals represents a list of user approvals
+type UserApprovals struct {
+	UserApproval []UserApproval `json:"userApproval"`
+}
+
+// UserApproval is one user who can approve a queued build, and whether they did
+type UserApproval struct {
+	User     *User `json:"user,omitempty"`
+	Approved bool  `json:"approved"`
+}
+
+// GroupApprovalRule names the user groups whose members can approve a queued build
+type GroupApprovalRule struct {
+	GroupApprovals *GroupApprovals `json:"groupApprovals,omitempty"`
+}
+
+// GroupApprovals represents a list of group approvals
+type GroupApprovals struct {
+	GroupApproval []GroupApproval `json:"groupApproval"`
+}
+
+// GroupApproval is one group whose members can approve a queued build, and how many of them must
+type GroupApproval struct {
+	Group                  *UserGroupRef `json:"group,omitempty"`
+	RequiredApprovalsCount int           `json:"requiredApprovalsCount,omitempty"`
+}
+
+// UserGroupRef is a reference to a user group
+type UserGroupRef struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
 }
 
 // BuildTypeRef is a reference to a build type
@@ -314,6 +436,18 @@ type Server struct {
 	InternalID   string `json:"internalId,omitempty"`
 }
 
+// LicensingData is the server's agent and job license usage. AgentsLeft is -1 with UnlimitedAgents.
+type LicensingData struct {
+	LicenseUseExceeded  bool   `json:"licenseUseExceeded"`
+	MaxAgents           int    `json:"maxAgents"`
+	AgentsLeft          int    `json:"agentsLeft"`
+	UnlimitedAgents     bool   `json:"unlimitedAgents"`
+	MaxBuildTypes       int    `json:"maxBuildTypes"`
+	BuildTypesLeft      int    `json:"buildTypesLeft"`
+	UnlimitedBuildTypes bool   `json:"unlimitedBuildTypes"`
+	ServerLicenseType   string `json:"serverLicenseType,omitempty"`
+}
+
 type Change struct {
 	ID       int    `json:"id,omitempty"`
 	Version  string `json:"version,omitempty"` // commit SHA
@@ -322,6 +456,16 @@ type Change struct {
 	Comment  string `json:"comment,omitempty"`
 	WebURL   string `json:"webUrl,omitempty"`
 	Files    *Files `json:"files,omitempty"`
+	// Personal marks an uploaded diff rather than a commit.
+	Personal bool `json:"personal,omitempty"`
+	// VcsRootInstance and ParentRevisions let callers link commits and spot merges (two or more parents).
+	VcsRootInstance *VcsRootInstanceRef `json:"vcsRootInstance,omitempty"`
+	ParentRevisions *Items              `json:"parentRevisions,omitempty"`
+}
+
+// Items is TeamCity's generic list of strings.
+type Items struct {
+	Item []string `json:"item"`
 }
 
 type ChangeList struct {
@@ -351,6 +495,23 @@ type TestOccurrence struct {
 
 	FirstFailed *TestOccurrence `json:"firstFailed,omitempty"`
 	Build       *Build          `json:"build,omitempty"`
+	Test        *Test           `json:"test,omitempty"`
+}
+
+// Test is the test a TestOccurrence is a run of.
+type Test struct {
+	ID             string          `json:"id,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	ParsedTestName *ParsedTestName `json:"parsedTestName,omitempty"`
+}
+
+// ParsedTestName is a test name split into the parts TeamCity recognizes; parts the name lacks are empty.
+type ParsedTestName struct {
+	TestSuite      string `json:"testSuite,omitempty"`
+	TestPackage    string `json:"testPackage,omitempty"`
+	TestClass      string `json:"testClass,omitempty"`
+	TestShortName  string `json:"testShortName,omitempty"`
+	TestMethodName string `json:"testMethodName,omitempty"`
 }
 
 type TestOccurrences struct {
@@ -359,6 +520,7 @@ type TestOccurrences struct {
 	Failed         int              `json:"failed,omitempty"`
 	Ignored        int              `json:"ignored,omitempty"`
 	Muted          int              `json:"muted,omitempty"`
+	NewFailed      int              `json:"newFailed,omitempty"`
 	NextHref       string           `json:"nextHref,omitempty"`
 	TestOccurrence []TestOccurrence `json:"testOccurrence"`
 }
@@ -375,9 +537,22 @@ type ProblemOccurrences struct {
 	ProblemOccurrence []ProblemOccurrence `json:"problemOccurrence"`
 }
 
-// ParseTeamCityTime parses TeamCity's time format (20250710T080607+0000)
+// teamCityTimeLayouts are the timestamp formats servers send: the REST default (20250710T080607+0000), the same
+// with milliseconds or a Z zone, and RFC 3339 from endpoints and proxies that rewrite dates.
+var teamCityTimeLayouts = []string{"20060102T150405Z0700", "20060102T150405.000Z0700", time.RFC3339Nano}
+
+// ParseTeamCityTime parses a TeamCity timestamp in any of teamCityTimeLayouts; the error names the REST default.
 func ParseTeamCityTime(s string) (time.Time, error) {
-	return time.Parse("20060102T150405-0700", s)
+	t, err := time.Parse(teamCityTimeLayouts[0], s)
+	if err == nil {
+		return t, nil
+	}
+	for _, layout := range teamCityTimeLayouts[1:] {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
 }
 
 // VersionedSettingsStatus represents the sync status of versioned settings
@@ -399,6 +574,22 @@ type VersionedSettingsConfig struct {
 	ShowSettingsChanges bool   `json:"showSettingsChanges,omitempty"`
 }
 
+// ArtifactDependency represents an artifact dependency of a build configuration; Properties hold pathRules, revisionName, and revisionValue.
+type ArtifactDependency struct {
+	ID              string        `json:"id,omitempty"`
+	Type            string        `json:"type,omitempty"`
+	Disabled        bool          `json:"disabled,omitempty"`
+	Properties      *PropertyList `json:"properties,omitempty"`
+	SourceBuildType *BuildType    `json:"source-buildType,omitempty"`
+}
+
+// ArtifactDependencyList represents a list of artifact dependencies; Replace "true" makes a trigger request's list replace the configured one.
+type ArtifactDependencyList struct {
+	Count              int                  `json:"count,omitempty"`
+	Replace            string               `json:"replace,omitempty"`
+	ArtifactDependency []ArtifactDependency `json:"artifact-dependency"`
+}
+
 // SnapshotDependency represents a snapshot dependency between build configurations
 type SnapshotDependency struct {
 	ID              string     `json:"id"`
@@ -490,6 +681,7 @@ type VcsRootList struct {
 // VcsRootsOptions represents options for listing VCS roots
 type VcsRootsOptions struct {
 	Project string // affectedProject locator
+	URL     string // server-side substring filter on the `url` property
 	Limit   int
 	Fields  []string
 }
@@ -2,9 +2,12 @@ package api
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // GetCurrentUser returns the authenticated user
@@ -44,6 +47,68 @@ type RoleList struct {
 	Role []Role `json:"role"`
 }
 
+// RoleHolder is a user or group assigned roles directly in a project.
+type RoleHolder struct {
+	Kind  string   `json:"kind"` // "user" or "group"
+	ID    string   `json:"id"`   // username or group key
+	Name  string   `json:"name,omitempty"`
+	Roles []string `json:"roles"`
+}
+
+type roleHolderList struct {
+	User  []roleHolderEntry `json:"user"`
+	Group []roleHolderEntry `json:"group"`
+}
+
+type roleHolderEntry struct {
+	Username string   `json:"username"`
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Roles    RoleList `json:"roles"`
+}
+
+// GetProjectRoleHolders returns, for each of projectIDs, the users, then the groups, assigned a role directly in it;
+// roles inherited from parent projects or granted server-wide are not included. One users request and one groups
+// request cover every project; with a single project the users request asks only for that project's holders.
+func (c *Client) GetProjectRoleHolders(ctx context.Context, projectIDs ...string) (map[string][]RoleHolder, error) {
+	path := "/app/rest/users?fields=user(username,name,roles(role(roleId,scope)))"
+	if len(projectIDs) == 1 {
+		locator := NewLocator().AddLocator("role", NewLocator().Add("scope", "p:"+projectIDs[0]))
+		path += "&locator=" + locator.Encode()
+	}
+	var users roleHolderList
+	if err := c.get(ctx, path, &users); err != nil {
+		return nil, err
+	}
+	// Groups have no role locator, and a server has few of them, so they are filtered here.
+	var groups roleHolderList
+	if err := c.get(ctx, "/app/rest/userGroups?fields=group(key,name,roles(role(roleId,scope)))", &groups); err != nil {
+		return nil, err
+	}
+
+	holders := make(map[string][]RoleHolder, len(projectIDs))
+	for _, id := range projectIDs {
+		holders[id] = nil
+	}
+	collect := func(kind string, entries []roleHolderEntry) {
+		for _, e := range entries {
+			roles := map[string][]string{}
+			for _, r := range e.Roles.Role {
+				id, ok := strings.CutPrefix(r.Scope, "p:")
+				if _, wanted := holders[id]; ok && wanted {
+					roles[id] = append(roles[id], r.RoleID)
+				}
+			}
+			for id, r := range roles {
+				holders[id] = append(holders[id], RoleHolder{Kind: kind, ID: cmp.Or(e.Username, e.Key), Name: e.Name, Roles: r})
+			}
+		}
+	}
+	collect("user", users.User)
+	collect("group", groups.Group)
+	return holders, nil
+}
+
 // CreateUserRequest represents a request to create a user
 type CreateUserRequest struct {
 	Username string   `json:"username"`
@@ -68,28 +133,84 @@ func (c *Client) CreateUser(req CreateUserRequest) (*User, error) {
 	return &user, nil
 }
 
-// Token represents an API token
+// Token is a user's access token. TeamCity returns Value only in the response that creates the token.
 type Token struct {
-	Name  string `json:"name"`
-	Value string `json:"value,omitempty"`
+	Name                   string                  `json:"name"`
+	Value                  string                  `json:"value,omitempty"`
+	CreationTime           string                  `json:"creationTime,omitempty"`
+	ExpirationTime         string                  `json:"expirationTime,omitempty"`
+	PermissionRestrictions *PermissionRestrictions `json:"permissionRestrictions,omitempty"`
 }
 
-// CreateAPIToken creates an API token for the current user
-func (c *Client) CreateAPIToken(name string) (*Token, error) {
-	path := "/app/rest/users/current/tokens/" + url.PathEscape(name)
+// TokenList is the current user's access tokens.
+type TokenList struct {
+	Count int     `json:"count"`
+	Token []Token `json:"token"`
+}
+
+// PermissionRestrictions limits a token to some permissions; a token without them has all of its user's permissions.
+type PermissionRestrictions struct {
+	PermissionRestriction []PermissionRestriction `json:"permissionRestriction"`
+}
+
+// PermissionRestriction grants one permission, server-wide or in one project.
+type PermissionRestriction struct {
+	IsGlobalScope bool        `json:"isGlobalScope"`
+	Project       *Project    `json:"project,omitempty"`
+	Permission    *Permission `json:"permission,omitempty"`
+}
+
+// Permission is a TeamCity permission such as run_build or view_project.
+type Permission struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+const tokenFields = "name,creationTime,expirationTime,permissionRestrictions(permissionRestriction(isGlobalScope,project(id),permission(id)))"
+
+// CreateAccessToken creates an access token for the current user from req's name, expiration time, and permission restrictions.
+func (c *Client) CreateAccessToken(ctx context.Context, req Token) (*Token, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
 
 	var token Token
-	if err := c.post(c.ctx(), path, nil, &token); err != nil {
+	if err := c.post(ctx, "/app/rest/users/current/tokens", bytes.NewReader(body), &token); err != nil {
 		return nil, err
 	}
-
 	return &token, nil
 }
 
+// ListAccessTokens returns the current user's access tokens, without their values.
+func (c *Client) ListAccessTokens(ctx context.Context) (*TokenList, error) {
+	path := "/app/rest/users/current/tokens?fields=" + url.QueryEscape("count,token("+tokenFields+")")
+
+	var tokens TokenList
+	if err := c.get(ctx, path, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// DeleteAccessToken revokes the current user's access token called name.
+func (c *Client) DeleteAccessToken(ctx context.Context, name string) error {
+	path := "/app/rest/users/current/tokens/" + url.PathEscape(name)
+	return c.doNoContent(ctx, "DELETE", path, nil, "")
+}
+
+// CreateAPIToken creates an API token for the current user
+//
+// Deprecated: Use CreateAccessToken.
+func (c *Client) CreateAPIToken(name string) (*Token, error) {
+	return c.CreateAccessToken(c.ctx(), Token{Name: name})
+}
+
 // DeleteAPIToken deletes an API token for the current user
+//
+// Deprecated: Use DeleteAccessToken.
 func (c *Client) DeleteAPIToken(name string) error {
-	path := "/app/rest/users/current/tokens/" + url.PathEscape(name)
-	return c.doNoContent(c.ctx(), "DELETE", path, nil, "")
+	return c.DeleteAccessToken(c.ctx(), name)
 }
 
 // GetServer returns server information
@@ -100,3 +221,12 @@ func (c *Client) GetServer() (*Server, error) {
 	}
 	return &server, nil
 }
+
+// GetLicensingData returns how many agent and job licenses the server has and how many are left.
+func (c *Client) GetLicensingData() (*LicensingData, error) {
+	var data LicensingData
+	if err := c.get(c.ctx(), "/app/rest/server/licensingData", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -73,29 +73,130 @@ func TestUserExists(t *testing.T) {
 	})
 }
 
-func TestCreateAPIToken(t *testing.T) {
+func TestCreateAccessToken(t *testing.T) {
 	t.Parallel()
+	var got Token
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "POST", r.Method)
-		assert.Contains(t, r.URL.Path, "/app/rest/users/current/tokens/my-token")
+		assert.Equal(t, "/app/rest/users/current/tokens", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(Token{Name: "my-token", Value: "secret-value"})
+		json.NewEncoder(w).Encode(Token{Name: "my-token", Value: "secret-value", ExpirationTime: got.ExpirationTime})
 	})
 
-	token, err := client.CreateAPIToken("my-token")
+	token, err := client.CreateAccessToken(t.Context(), Token{
+		Name:           "my-token",
+		ExpirationTime: "20260101T000000+0000",
+		PermissionRestrictions: &PermissionRestrictions{PermissionRestriction: []PermissionRestriction{
+			{Project: &Project{ID: "Falcon"}, Permission: &Permission{ID: "run_build"}},
+		}},
+	})
 	require.NoError(t, err)
 	assert.Equal(t, "my-token", token.Name)
 	assert.Equal(t, "secret-value", token.Value)
+	assert.Equal(t, "20260101T000000+0000", got.ExpirationTime)
+	require.NotNil(t, got.PermissionRestrictions)
+	assert.Equal(t, "run_build", got.PermissionRestrictions.PermissionRestriction[0].Permission.ID)
+	assert.Equal(t, "Falcon", got.PermissionRestrictions.PermissionRestriction[0].Project.ID)
+}
+
+func TestListAccessTokens(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/users/current/tokens", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("fields"), "expirationTime")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":1,"token":[{"name":"ci-bot","creationTime":"20260101T000000+0000"}]}`))
+	})
+
+	tokens, err := client.ListAccessTokens(t.Context())
+	require.NoError(t, err)
+	require.Len(t, tokens.Token, 1)
+	assert.Equal(t, "ci-bot", tokens.Token[0].Name)
+	assert.Empty(t, tokens.Token[0].Value)
 }
 
-func TestDeleteAPIToken(t *testing.T) {
+func TestDeleteAccessToken(t *testing.T) {
 	t.Parallel()
 	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method)
-		assert.Contains(t, r.URL.Path, "/app/rest/users/current/tokens/my-token")
+		assert.Equal(t, "/app/rest/users/current/tokens/my token", r.URL.Path)
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	err := client.DeleteAPIToken("my-token")
+	err := client.DeleteAccessToken(t.Context(), "my token")
+	require.NoError(t, err)
+}
+
+func TestDeprecatedAPITokenWrappers(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			assert.Equal(t, "/app/rest/users/current/tokens", r.URL.Path)
+			var got Token
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			assert.Equal(t, "legacy", got.Name)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(Token{Name: got.Name, Value: "secret-value"})
+		case "DELETE":
+			assert.Equal(t, "/app/rest/users/current/tokens/legacy", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	token, err := client.CreateAPIToken("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-value", token.Value)
+	require.NoError(t, client.DeleteAPIToken("legacy"))
+}
+
+func TestGetLicensingData(t *testing.T) {
+	t.Parallel()
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/rest/server/licensingData", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"licenseUseExceeded":false,"maxAgents":10,"agentsLeft":2,"unlimitedAgents":false,"serverLicenseType":"enterprise"}`))
+	})
+
+	data, err := client.GetLicensingData()
+	require.NoError(t, err)
+	assert.Equal(t, 10, data.MaxAgents)
+	assert.Equal(t, 2, data.AgentsLeft)
+	assert.Equal(t, "enterprise", data.ServerLicenseType)
+}
+
+func TestGetProjectRoleHolders(t *testing.T) {
+	t.Parallel()
+	var locators []string
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/app/rest/users":
+			locators = append(locators, r.URL.Query().Get("locator"))
+			_, _ = w.Write([]byte(`{"user":[{"username":"jane","name":"Jane","roles":{"role":[
+				{"roleId":"PROJECT_ADMIN","scope":"p:Falcon"},{"roleId":"PROJECT_VIEWER","scope":"p:Other"}]}}]}`))
+		case "/app/rest/userGroups":
+			_, _ = w.Write([]byte(`{"group":[
+				{"key":"DEVS","name":"Developers","roles":{"role":[{"roleId":"PROJECT_DEVELOPER","scope":"p:Falcon"}]}},
+				{"key":"ALL_USERS_GROUP","name":"All Users","roles":{"role":[{"roleId":"PROJECT_VIEWER","scope":"g"}]}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	holders, err := client.GetProjectRoleHolders(t.Context(), "Falcon")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]RoleHolder{"Falcon": {
+		{Kind: "user", ID: "jane", Name: "Jane", Roles: []string{"PROJECT_ADMIN"}},
+		{Kind: "group", ID: "DEVS", Name: "Developers", Roles: []string{"PROJECT_DEVELOPER"}},
+	}}, holders)
+
+	holders, err = client.GetProjectRoleHolders(t.Context(), "Falcon", "Other", "Empty")
 	require.NoError(t, err)
+	assert.Len(t, holders["Falcon"], 2)
+	assert.Equal(t, []RoleHolder{{Kind: "user", ID: "jane", Name: "Jane", Roles: []string{"PROJECT_VIEWER"}}}, holders["Other"])
+	assert.Contains(t, holders, "Empty")
+	assert.Empty(t, holders["Empty"])
+	assert.Equal(t, []string{"role:(scope:(p:Falcon))", ""}, locators, "several projects share one unfiltered users request")
 }
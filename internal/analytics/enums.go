@@ -8,16 +8,19 @@ import (
 // allCommands enumerates every command path the CLI exposes for the `command` field; unknowns → "other".
 func allCommands() []string {
 	return []string{
-		"auth.login", "auth.logout", "auth.status",
-		"run.list", "run.view", "run.start", "run.cancel", "run.restart", "run.watch",
+		"auth.guest", "auth.login", "auth.logout", "auth.status", "auth.switch", "auth.token.create", "auth.token.list", "auth.token.revoke", "auth.token.rotate",
+		"run.list", "run.view", "run.start", "run.cancel", "run.approve", "run.restart", "run.watch",
 		"run.log", "run.download", "run.artifacts", "run.tests", "run.pin", "run.unpin",
-		"run.tag", "run.untag", "run.comment", "run.changes", "run.tree", "run.diff",
-		"run.analysis", "run.metadata", "run.git",
-		"job.create", "job.list", "job.view", "job.tree", "job.pause", "job.resume",
-		"job.param.list", "job.param.get", "job.param.set", "job.param.delete",
+		"run.tag", "run.untag", "run.comment", "run.changes", "run.tree", "run.diff", "run.params", "run.problems", "run.bisect", "run.history",
+		"run.snapshot", "run.show-snapshot", "run.analysis", "run.metadata", "run.git",
+		"test.flaky",
+		"tag.list",
+		"change.view", "change.builds",
+		"job.create", "job.list", "job.view", "job.tree", "job.tokens", "job.artifact-usage", "job.prune-branches", "job.pause", "job.resume",
+		"job.param.list", "job.param.get", "job.param.set", "job.param.delete", "job.param.export", "job.param.import",
 		"job.settings.list", "job.settings.get", "job.settings.set",
 		"job.step.list", "job.step.view", "job.step.add", "job.step.delete",
-		"project.list", "project.view", "project.tree", "project.create",
+		"project.list", "project.view", "project.tree", "project.report", "project.create", "project.copy",
 		"project.vcs.list", "project.vcs.view", "project.vcs.create", "project.vcs.test", "project.vcs.delete",
 		"project.ssh.list", "project.ssh.upload", "project.ssh.generate", "project.ssh.delete",
 		"project.cloud.profile.list", "project.cloud.profile.view",
@@ -26,20 +29,22 @@ func allCommands() []string {
 		"project.connection.list", "project.connection.view", "project.connection.authorize", "project.connection.delete",
 		"project.connection.create.docker", "project.connection.create.github-app",
 		"project.token.put", "project.token.get",
-		"project.settings.status", "project.settings.export", "project.settings.validate",
-		"project.param.list", "project.param.get", "project.param.set", "project.param.delete",
-		"queue.list", "queue.remove", "queue.top", "queue.approve",
-		"agent.list", "agent.view", "agent.jobs", "agent.move", "agent.enable",
+		"project.settings.status", "project.settings.watch", "project.settings.export", "project.settings.validate",
+		"project.param.list", "project.param.get", "project.param.set", "project.param.delete", "project.param.export", "project.param.import",
+		"queue.list", "queue.remove", "queue.top", "queue.move", "queue.approve", "queue.drain", "queue.forecast",
+		"agent.list", "agent.view", "agent.jobs", "agent.config-params", "agent.move", "agent.enable",
 		"agent.disable", "agent.authorize", "agent.deauthorize", "agent.term",
-		"agent.exec", "agent.reboot",
-		"pool.list", "pool.view", "pool.link", "pool.unlink",
+		"agent.exec", "agent.reboot", "agent.pool.list", "agent.pool.assign",
+		"pool.list", "pool.view", "pool.link", "pool.unlink", "pool.quota",
 		"pipeline.list", "pipeline.view", "pipeline.validate", "pipeline.create",
 		"pipeline.delete", "pipeline.pull", "pipeline.push", "pipeline.schema",
-		"api", "link", "migrate",
+		"api", "batch", "examples", "link", "migrate",
 		"alias.list", "alias.set", "alias.delete",
-		"config.list", "config.get", "config.set",
+		"plugins.list",
+		"config.list", "config.get", "config.set", "config.doctor",
+		"cache.clear",
 		"skill.list", "skill.install", "skill.update", "skill.remove",
-		"update", "other",
+		"update", "version", "other",
 	}
 }
 
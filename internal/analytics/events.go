@@ -81,6 +81,7 @@ const (
 	AuthSourceEnv             = "env"
 	AuthSourceBuildProperties = "build_properties"
 	AuthSourceGuest           = "guest"
+	AuthSourceCommand         = "command"
 	AuthSourceNone            = "none"
 )
 
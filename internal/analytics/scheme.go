@@ -72,7 +72,7 @@ var Scheme = &fus.Scheme{
 			enumArch:        {"amd64", "arm64", "386", "other"},
 			enumServerType:  {"cloud", "on_prem"},
 			enumCISystem:    {"github_actions", "gitlab", "jenkins", "circleci", "buildkite", "azure", "travis", "teamcity", "other", "none"},
-			enumAuthSource:  {"keyring", "env", "build_properties", "guest", "none"},
+			enumAuthSource:  {"keyring", "env", "command", "build_properties", "guest", "none"},
 			enumAIAgent:     allAIAgents(),
 			enumSource:      {"human", "agent", "ci", "build_step"},
 			enumExitCode:    {"0", "1", "2"},
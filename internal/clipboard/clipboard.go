@@ -0,0 +1,115 @@
+// Package clipboard copies text to the system clipboard through the platform's command-line tools:
+// pbcopy on macOS, clip or PowerShell on Windows, and wl-copy, xclip, or xsel elsewhere.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool can be used, such as on a headless Linux machine.
+var ErrUnavailable = errors.New("no clipboard available")
+
+// tool is a command that reads the text to copy from stdin.
+type tool struct {
+	name string
+	args []string
+}
+
+func (t tool) String() string {
+	return strings.Join(append([]string{t.name}, t.args...), " ")
+}
+
+// runner runs name with args, writing stdin to it.
+type runner func(name string, args []string, stdin string) error
+
+// copier picks and runs a clipboard tool; its platform, environment, and command execution are injectable for tests.
+type copier struct {
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	run      runner
+}
+
+func newCopier() copier {
+	return copier{goos: runtime.GOOS, getenv: os.Getenv, lookPath: exec.LookPath, run: runCommand}
+}
+
+// tools lists the clipboard tools to try on the platform, most preferred first.
+func (c copier) tools() []tool {
+	switch c.goos {
+	case "darwin":
+		return []tool{{name: "pbcopy"}}
+	case "windows":
+		return []tool{
+			{name: "clip.exe"},
+			{name: "powershell.exe", args: []string{"-NoProfile", "-NonInteractive", "-Command", "$input | Set-Clipboard"}},
+		}
+	}
+	var tools []tool
+	if c.getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools, tool{name: "wl-copy"})
+	}
+	if c.getenv("DISPLAY") != "" {
+		tools = append(tools,
+			tool{name: "xclip", args: []string{"-selection", "clipboard"}},
+			tool{name: "xsel", args: []string{"--clipboard", "--input"}},
+		)
+	}
+	// Under WSL the Windows clipboard is reachable without a display server.
+	if c.getenv("WSL_DISTRO_NAME") != "" {
+		tools = append(tools, tool{name: "clip.exe"})
+	}
+	return tools
+}
+
+// copy runs the first available tool that succeeds; a tool that fails falls through to the next one.
+func (c copier) copy(text string) error {
+	var errs []error
+	for _, t := range c.tools() {
+		if _, err := c.lookPath(t.name); err != nil {
+			continue
+		}
+		err := c.run(t.name, t.args, text)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t, err))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("%w: %s", ErrUnavailable, c.hint())
+}
+
+// hint says what would make a clipboard available on the platform.
+func (c copier) hint() string {
+	switch c.goos {
+	case "darwin":
+		return "pbcopy not found"
+	case "windows":
+		return "neither clip.exe nor PowerShell found"
+	}
+	if c.getenv("WAYLAND_DISPLAY") == "" && c.getenv("DISPLAY") == "" {
+		return "no display server (WAYLAND_DISPLAY or DISPLAY) to hold a clipboard"
+	}
+	return "install wl-clipboard, xclip, or xsel"
+}
+
+// runCommand runs the tool without capturing its output: xclip and wl-copy leave a child serving the
+// clipboard that keeps inherited pipes open, so reading them would block until the clipboard changes.
+func runCommand(name string, args []string, stdin string) error {
+	//nolint:gosec // name and args come from the fixed tool list
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	return cmd.Run()
+}
+
+// Copy puts text on the system clipboard.
+func Copy(text string) error {
+	return newCopier().copy(text)
+}
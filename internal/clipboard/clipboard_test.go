@@ -0,0 +1,121 @@
+package clipboard
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	name  string
+	args  []string
+	stdin string
+}
+
+// fakeCopier returns a copier for goos with env, where only the tools in installed exist and the ones in
+// failing exit with an error. Every run is recorded in calls.
+func fakeCopier(goos string, env map[string]string, installed, failing []string, calls *[]call) copier {
+	return copier{
+		goos:   goos,
+		getenv: func(k string) string { return env[k] },
+		lookPath: func(name string) (string, error) {
+			if slices.Contains(installed, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", exec.ErrNotFound
+		},
+		run: func(name string, args []string, stdin string) error {
+			*calls = append(*calls, call{name, args, stdin})
+			if slices.Contains(failing, name) {
+				return errors.New("exit status 1")
+			}
+			return nil
+		},
+	}
+}
+
+func TestTools(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want []string
+	}{
+		{"macOS", "darwin", nil, []string{"pbcopy"}},
+		{"Windows", "windows", nil, []string{"clip.exe", "powershell.exe -NoProfile -NonInteractive -Command $input | Set-Clipboard"}},
+		{"Wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"wl-copy"}},
+		{"X11", "linux", map[string]string{"DISPLAY": ":0"}, []string{"xclip -selection clipboard", "xsel --clipboard --input"}},
+		{"XWayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"wl-copy", "xclip -selection clipboard", "xsel --clipboard --input"}},
+		{"WSL", "linux", map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, []string{"clip.exe"}},
+		{"headless Linux", "linux", nil, nil},
+		{"FreeBSD with X11", "freebsd", map[string]string{"DISPLAY": ":0"}, []string{"xclip -selection clipboard", "xsel --clipboard --input"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []call
+			var got []string
+			for _, tl := range fakeCopier(tc.goos, tc.env, nil, nil, &calls).tools() {
+				got = append(got, tl.String())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCopyUsesFirstInstalledTool(t *testing.T) {
+	var calls []call
+	c := fakeCopier("linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"xclip", "xsel"}, nil, &calls)
+
+	require.NoError(t, c.copy("https://tc.example.com/buildConfiguration/Falcon_Build/42"))
+	require.Len(t, calls, 1, "wl-copy is not installed, xclip succeeds, xsel is never tried")
+	assert.Equal(t, call{"xclip", []string{"-selection", "clipboard"}, "https://tc.example.com/buildConfiguration/Falcon_Build/42"}, calls[0])
+}
+
+func TestCopyFallsBackWhenToolFails(t *testing.T) {
+	var calls []call
+	c := fakeCopier("windows", nil, []string{"clip.exe", "powershell.exe"}, []string{"clip.exe"}, &calls)
+
+	require.NoError(t, c.copy("42"))
+	require.Len(t, calls, 2)
+	assert.Equal(t, "clip.exe", calls[0].name)
+	assert.Equal(t, "powershell.exe", calls[1].name)
+	assert.Equal(t, "42", calls[1].stdin)
+}
+
+func TestCopyReportsEveryFailure(t *testing.T) {
+	var calls []call
+	c := fakeCopier("linux", map[string]string{"DISPLAY": ":0"}, []string{"xclip", "xsel"}, []string{"xclip", "xsel"}, &calls)
+
+	err := c.copy("42")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+	assert.Contains(t, err.Error(), "xclip -selection clipboard: exit status 1")
+	assert.Contains(t, err.Error(), "xsel --clipboard --input: exit status 1")
+}
+
+func TestCopyUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		hint string
+	}{
+		{"headless Linux", "linux", nil, "no display server"},
+		{"X11 without tools", "linux", map[string]string{"DISPLAY": ":0"}, "install wl-clipboard, xclip, or xsel"},
+		{"macOS without pbcopy", "darwin", nil, "pbcopy not found"},
+		{"Windows without tools", "windows", nil, "neither clip.exe nor PowerShell found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []call
+			err := fakeCopier(tc.goos, tc.env, nil, nil, &calls).copy("42")
+			require.ErrorIs(t, err, ErrUnavailable)
+			assert.Contains(t, err.Error(), tc.hint)
+			assert.Empty(t, calls)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"cmp"
 	"fmt"
 	"strconv"
 
@@ -43,6 +44,7 @@ See: https://www.jetbrains.com/help/teamcity/build-agent.html`,
 		newAgentListCmd(f),
 		newAgentViewCmd(f),
 		newAgentJobsCmd(f),
+		newAgentParamsCmd(f),
 	)
 	addInGroup("state",
 		newAgentActionCmd(f, agentActions["enable"]),
@@ -50,6 +52,7 @@ See: https://www.jetbrains.com/help/teamcity/build-agent.html`,
 		newAgentActionCmd(f, agentActions["authorize"]),
 		newAgentActionCmd(f, agentActions["deauthorize"]),
 		newAgentMoveCmd(f),
+		newAgentPoolCmd(f),
 		newAgentRebootCmd(f),
 	)
 	addInGroup("shell",
@@ -61,10 +64,12 @@ See: https://www.jetbrains.com/help/teamcity/build-agent.html`,
 }
 
 type agentListOptions struct {
-	pool       string
-	connected  bool
-	enabled    bool
-	authorized bool
+	pool         string
+	connected    bool
+	disconnected bool
+	enabled      bool
+	disabled     bool
+	authorized   bool
 	cmdutil.ListFlags
 	cmdutil.ViewOptions
 }
@@ -73,16 +78,25 @@ func newAgentListCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &agentListOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List build agents",
+		Use:   "list",
+		Short: "List build agents",
+		Long: `List build agents, sorted by ID.
+
+The BUILD column shows the run an agent is busy with. Use --sort to order
+by other columns; status sorts connected agents first, then disconnected,
+disabled, and unauthorized ones.`,
 		Aliases: []string{"ls"},
 		Example: `  teamcity agent list
   teamcity agent list --pool Default
   teamcity agent list --connected
+  teamcity agent list --disconnected --pool Default
+  teamcity agent list --disabled
+  teamcity agent list --sort pool,name
   teamcity agent list --json
   teamcity agent list --json=id,name,connected,enabled
   teamcity agent list --plain
   teamcity agent list --plain --no-header
+  teamcity agent list --template '{{.Name}} {{.Pool.Name}}'
   teamcity agent list --web`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.Web {
@@ -93,143 +107,102 @@ func newAgentListCmd(f *cmdutil.Factory) *cobra.Command {
 			if done, err := opts.EmitListWebURL(f.Printer, config.ResolveServerURL(), "/agents.html"); done {
 				return err
 			}
+			if err := cmdutil.ValidateSort(&opts.SortFlags, agentSortColumns); err != nil {
+				return err
+			}
 			return cmdutil.RunList(f, cmd, &opts.ListFlags, &api.AgentFields, opts.fetch)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.pool, "pool", "p", "", "Filter by agent pool")
 	cmd.Flags().BoolVar(&opts.connected, "connected", false, "Show only connected agents")
+	cmd.Flags().BoolVar(&opts.disconnected, "disconnected", false, "Show only disconnected agents")
 	cmd.Flags().BoolVar(&opts.enabled, "enabled", false, "Show only enabled agents")
+	cmd.Flags().BoolVar(&opts.disabled, "disabled", false, "Show only disabled agents")
+	cmd.MarkFlagsMutuallyExclusive("connected", "disconnected")
+	cmd.MarkFlagsMutuallyExclusive("enabled", "disabled")
 	cmd.Flags().BoolVar(&opts.authorized, "authorized", false, "Show only authorized agents")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
+	cmdutil.AddTemplateFlag(cmd, &opts.Template)
+	cmdutil.AddSortFlags(cmd, &opts.SortFlags, agentSortColumns, "id")
 	cmdutil.AddWebFlags(cmd, &opts.ViewOptions)
 
 	return cmd
 }
 
+var agentSortColumns = []cmdutil.SortColumn[api.Agent]{
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b api.Agent) int { return cmp.Compare(a.ID, b.ID) }},
+	{Name: "name", Fields: []string{"name"}, Compare: func(a, b api.Agent) int { return cmdutil.CompareFold(a.Name, b.Name) }},
+	{Name: "pool", Fields: []string{"pool.name"}, Compare: func(a, b api.Agent) int { return cmdutil.CompareFold(agentPoolName(a), agentPoolName(b)) }},
+	{Name: "status", Fields: []string{"connected", "enabled", "authorized"}, Compare: func(a, b api.Agent) int {
+		return cmp.Compare(agentStatusRank(a), agentStatusRank(b))
+	}},
+}
+
+// agentStatusRank orders agents the way FormatAgentStatus labels them, most usable first.
+func agentStatusRank(a api.Agent) int {
+	switch {
+	case !a.Authorized:
+		return 3
+	case !a.Enabled:
+		return 2
+	case !a.Connected:
+		return 1
+	}
+	return 0
+}
+
+func agentPoolName(a api.Agent) string {
+	if a.Pool == nil {
+		return ""
+	}
+	return a.Pool.Name
+}
+
 func (opts *agentListOptions) fetch(client api.ClientInterface, fields []string) (*cmdutil.ListResult, error) {
+	if err := cmdutil.ValidateSortFields(&opts.SortFlags, agentSortColumns, fields); err != nil {
+		return nil, err
+	}
 	agents, truncated, err := client.GetAgents(api.AgentsOptions{
-		Pool:       opts.pool,
-		Connected:  opts.connected,
-		Enabled:    opts.enabled,
-		Authorized: opts.authorized,
-		Limit:      opts.Limit,
-		Fields:     fields,
+		Pool:         opts.pool,
+		Connected:    opts.connected,
+		Disconnected: opts.disconnected,
+		Enabled:      opts.enabled,
+		Disabled:     opts.disabled,
+		Authorized:   opts.authorized,
+		Limit:        opts.Limit,
+		Fields:       fields,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := cmdutil.SortItems(agents.Agents, &opts.SortFlags, agentSortColumns); err != nil {
+		return nil, err
+	}
 
-	headers := []string{"ID", "NAME", "POOL", "STATUS"}
+	headers := []string{"ID", "NAME", "POOL", "STATUS", "BUILD"}
 	var rows [][]string
 
 	for _, a := range agents.Agents {
-		status := cmdutil.FormatAgentStatus(a)
-		poolName := ""
-		if a.Pool != nil {
-			poolName = a.Pool.Name
+		build := "-"
+		if b := a.Build; b != nil {
+			build = fmt.Sprintf("%s %s", b.BuildTypeID, cmdutil.RunNumber(b.Number))
 		}
-
 		rows = append(rows, []string{
 			strconv.Itoa(a.ID),
 			a.Name,
-			poolName,
-			status,
+			agentPoolName(a),
+			cmdutil.FormatAgentStatus(a),
+			build,
 		})
 	}
 
 	return &cmdutil.ListResult{
 		JSON:      agents,
-		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: []int{1, 2}},
+		Items:     agents.Agents,
+		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: []int{1, 2, 4}},
 		EmptyMsg:  "No agents found",
 		EmptyTip:  output.TipNoAgents,
 		Truncated: truncated,
 	}, nil
 }
-
-func newAgentViewCmd(f *cmdutil.Factory) *cobra.Command {
-	opts := &cmdutil.ViewOptions{}
-	cmd := &cobra.Command{
-		Use:     "view <agent>",
-		Short:   "View agent details",
-		Aliases: []string{"show"},
-		Args:    cobra.ExactArgs(1),
-		Example: `  teamcity agent view 1
-  teamcity agent view Agent-Linux-01
-  teamcity agent view Agent-Linux-01 --web
-  teamcity agent view 1 --json`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return runAgentView(f, args[0], opts)
-		},
-	}
-	cmdutil.AddViewFlags(cmd, opts)
-	return cmd
-}
-
-func runAgentView(f *cmdutil.Factory, nameOrID string, opts *cmdutil.ViewOptions) error {
-	client, err := f.Client()
-	if err != nil {
-		return err
-	}
-
-	agent, err := cmdutil.ResolveAgent(client, nameOrID)
-	if err != nil {
-		return err
-	}
-
-	if done, err := opts.EmitWebURL(f.Printer, agent.WebURL); done {
-		return err
-	}
-
-	if opts.JSON {
-		return f.Printer.PrintJSON(agent)
-	}
-
-	p := f.Printer
-	_, _ = fmt.Fprintf(p.Out, "%s\n", output.Cyan(agent.Name))
-	_, _ = fmt.Fprintf(p.Out, "ID: %d\n", agent.ID)
-
-	if agent.Pool != nil {
-		_, _ = fmt.Fprintf(p.Out, "Pool: %s\n", agent.Pool.Name)
-	}
-
-	_, _ = fmt.Fprintf(p.Out, "Status: %s\n", cmdutil.FormatAgentStatus(*agent))
-
-	if agent.Connected {
-		_, _ = fmt.Fprintf(p.Out, "Connected: %s\n", output.Green("Yes"))
-	} else {
-		_, _ = fmt.Fprintf(p.Out, "Connected: %s\n", output.Red("No"))
-	}
-
-	if agent.Enabled {
-		_, _ = fmt.Fprintf(p.Out, "Enabled: %s\n", output.Green("Yes"))
-	} else {
-		_, _ = fmt.Fprintf(p.Out, "Enabled: %s\n", output.Faint("No"))
-	}
-
-	if agent.Authorized {
-		_, _ = fmt.Fprintf(p.Out, "Authorized: %s\n", output.Green("Yes"))
-	} else {
-		_, _ = fmt.Fprintf(p.Out, "Authorized: %s\n", output.Yellow("No"))
-	}
-
-	if agent.Build != nil {
-		jobName := agent.Build.BuildTypeID
-		if agent.Build.BuildType != nil {
-			jobName = agent.Build.BuildType.Name
-		}
-		_, _ = fmt.Fprintf(p.Out, "\nCurrent build: %s %d  #%s (%s)\n",
-			jobName,
-			agent.Build.ID,
-			agent.Build.Number,
-			agent.Build.Status)
-	}
-
-	_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), output.Green(agent.WebURL))
-
-	if agent.Connected {
-		_, _ = fmt.Fprintf(p.Out, "%s teamcity agent term %d\n", output.Faint("Open terminal:"), agent.ID)
-	}
-
-	return nil
-}
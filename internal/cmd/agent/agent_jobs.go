@@ -6,6 +6,7 @@ import (
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -26,7 +27,8 @@ func newAgentJobsCmd(f *cmdutil.Factory) *cobra.Command {
 Pass --incompatible to show jobs that would not run on this agent,
 with the requirement reasons (missing parameters, unmet tool
 versions, pool restrictions, etc.).`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: `  teamcity agent jobs 1
   teamcity agent jobs Agent-Linux-01
   teamcity agent jobs Agent-Linux-01 --incompatible
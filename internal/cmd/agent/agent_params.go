@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type agentParamsOptions struct {
+	filter string
+	diff   string
+	json   bool
+}
+
+func newAgentParamsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &agentParamsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "config-params <agent>",
+		Short: "Show configuration parameters an agent reports",
+		Long: `Show the configuration parameters an agent reports to the server:
+environment variables (env.*), system properties (system.*), and
+teamcity.agent.* values. These are what job requirements are matched
+against.
+
+Pass --diff with another agent to show only the parameters that differ,
+the fastest way to find why a job runs on one agent but not its twin.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
+		Example: `  teamcity agent config-params Agent-Linux-01
+  teamcity agent config-params 1 --filter java
+  teamcity agent config-params Agent-Linux-01 --diff Agent-Linux-02
+  teamcity agent config-params 1 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentParams(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.filter, "filter", "", "Only show parameters whose name contains this text (case-insensitive)")
+	cmd.Flags().StringVar(&opts.diff, "diff", "", "Show only parameters that differ from this agent (name or ID)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+type agentRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// paramDiff is one differing parameter; a null value means the agent does not report it.
+type paramDiff struct {
+	Name       string  `json:"name"`
+	Value      *string `json:"value"`
+	OtherValue *string `json:"otherValue"`
+}
+
+type paramDiffResult struct {
+	Agent       agentRef    `json:"agent"`
+	Other       agentRef    `json:"other"`
+	Differences []paramDiff `json:"differences"`
+}
+
+func runAgentParams(f *cmdutil.Factory, nameOrID string, opts *agentParamsOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	agent, props, err := fetchAgentParams(f, client, nameOrID, opts.filter)
+	if err != nil {
+		return err
+	}
+
+	p := f.Printer
+	if opts.diff != "" {
+		other, otherProps, err := fetchAgentParams(f, client, opts.diff, opts.filter)
+		if err != nil {
+			return err
+		}
+		result := paramDiffResult{Agent: agent, Other: other, Differences: diffAgentParams(props, otherProps)}
+		if opts.json {
+			return p.PrintJSON(result)
+		}
+		printParamDiff(p, result)
+		return nil
+	}
+
+	if opts.json {
+		if props == nil {
+			props = []api.Property{}
+		}
+		return p.PrintJSON(api.PropertyList{Property: props})
+	}
+	if len(props) == 0 {
+		tip := ""
+		if opts.filter != "" {
+			tip = "Drop --filter to see every parameter the agent reports"
+		}
+		p.Empty("No matching parameters found", tip)
+		return nil
+	}
+	headers := []string{"NAME", "VALUE"}
+	rows := make([][]string, len(props))
+	for i, prop := range props {
+		rows[i] = []string{prop.Name, prop.Value}
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1)
+	p.PrintTable(headers, rows)
+	return nil
+}
+
+// fetchAgentParams resolves nameOrID and returns its parameters whose name contains filter, sorted by name.
+func fetchAgentParams(f *cmdutil.Factory, client api.ClientInterface, nameOrID, filter string) (agentRef, []api.Property, error) {
+	id, name, err := cmdutil.ResolveAgentID(client, nameOrID)
+	if err != nil {
+		return agentRef{}, nil, err
+	}
+	list, err := client.GetAgentProperties(f.Context(), id)
+	if err != nil {
+		return agentRef{}, nil, fmt.Errorf("failed to get parameters of agent %s: %w", name, err)
+	}
+	filter = strings.ToLower(filter)
+	props := slices.DeleteFunc(list.Property, func(p api.Property) bool {
+		return !strings.Contains(strings.ToLower(p.Name), filter)
+	})
+	slices.SortFunc(props, func(a, b api.Property) int { return cmp.Compare(a.Name, b.Name) })
+	return agentRef{ID: id, Name: name}, props, nil
+}
+
+// diffAgentParams returns the parameters missing from either side or valued differently, sorted by name.
+func diffAgentParams(props, otherProps []api.Property) []paramDiff {
+	values := make(map[string]string, len(props))
+	for _, p := range props {
+		values[p.Name] = p.Value
+	}
+	otherValues := make(map[string]string, len(otherProps))
+	for _, p := range otherProps {
+		otherValues[p.Name] = p.Value
+	}
+
+	diffs := []paramDiff{}
+	for _, p := range props {
+		if other, ok := otherValues[p.Name]; !ok || other != p.Value {
+			d := paramDiff{Name: p.Name, Value: &p.Value}
+			if ok {
+				d.OtherValue = &other
+			}
+			diffs = append(diffs, d)
+		}
+	}
+	for _, p := range otherProps {
+		if _, ok := values[p.Name]; !ok {
+			diffs = append(diffs, paramDiff{Name: p.Name, OtherValue: &p.Value})
+		}
+	}
+	slices.SortFunc(diffs, func(a, b paramDiff) int { return cmp.Compare(a.Name, b.Name) })
+	return diffs
+}
+
+func printParamDiff(p *output.Printer, result paramDiffResult) {
+	if len(result.Differences) == 0 {
+		p.Empty(fmt.Sprintf("No differing parameters between %s and %s", result.Agent.Name, result.Other.Name), "")
+		return
+	}
+	value := func(v *string) string {
+		if v == nil {
+			return output.Faint("(not reported)")
+		}
+		return *v
+	}
+	headers := []string{"NAME", strings.ToUpper(result.Agent.Name), strings.ToUpper(result.Other.Name)}
+	rows := make([][]string, len(result.Differences))
+	for i, d := range result.Differences {
+		rows[i] = []string{d.Name, value(d.Value), value(d.OtherValue)}
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1, 2)
+	p.PrintTable(headers, rows)
+}
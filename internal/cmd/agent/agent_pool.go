@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newAgentPoolCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pool",
+		Short: "List pools and assign agents to them",
+		Long: `List agent pools with how many agents each holds, and assign agents to them.
+
+To link projects to pools, see 'teamcity pool'.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+	cmd.AddCommand(newAgentPoolListCmd(f), newAgentPoolAssignCmd(f))
+	return cmd
+}
+
+// agentPoolSummary is one pool of agent pool list; MaxAgents is 0 when unlimited.
+type agentPoolSummary struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Agents    int    `json:"agents"`
+	MaxAgents int    `json:"maxAgents,omitempty"`
+}
+
+func newAgentPoolListCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &cmdutil.ListOptions{}
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List agent pools with their agent counts",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		Example: `  teamcity agent pool list
+  teamcity agent pool list --json
+  teamcity agent pool list --plain`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentPoolList(f, opts)
+		},
+	}
+	opts.AddFlags(cmd, false)
+	return cmd
+}
+
+func runAgentPoolList(f *cmdutil.Factory, opts *cmdutil.ListOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	pools, err := client.GetAgentPools([]string{"id", "name", "maxAgents", "agents.count"})
+	if err != nil {
+		return err
+	}
+
+	summaries := make([]agentPoolSummary, len(pools.Pools))
+	for i, pool := range pools.Pools {
+		summaries[i] = agentPoolSummary{ID: pool.ID, Name: pool.Name, MaxAgents: pool.MaxAgents}
+		if pool.Agents != nil {
+			summaries[i].Agents = pool.Agents.Count
+		}
+	}
+
+	p := f.Printer
+	if opts.JSON {
+		return p.PrintJSON(summaries)
+	}
+	if len(summaries) == 0 {
+		p.Empty("No agent pools found", output.TipNoPools)
+		return nil
+	}
+
+	headers := []string{"ID", "NAME", "AGENTS", "MAX AGENTS"}
+	rows := make([][]string, len(summaries))
+	for i, s := range summaries {
+		maxAgents := "unlimited"
+		if s.MaxAgents > 0 {
+			maxAgents = strconv.Itoa(s.MaxAgents)
+		}
+		rows[i] = []string{strconv.Itoa(s.ID), s.Name, strconv.Itoa(s.Agents), maxAgents}
+	}
+	if opts.Plain {
+		p.PrintPlainTable(headers, rows, opts.NoHeader)
+		return nil
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1)
+	p.PrintTable(headers, rows)
+	return nil
+}
+
+func newAgentPoolAssignCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:               "assign <agent> <pool>",
+		Short:             "Assign an agent to a pool",
+		Long:              `Move an agent to a pool, given by ID or name. Same as 'teamcity agent move'.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
+		Example: `  teamcity agent pool assign Agent-Linux-01 Linux
+  teamcity agent pool assign 1 0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentMove(f, args[0], args[1])
+		},
+	}
+}
+
+// resolvePool returns the ID and name of the pool ref names, by ID or by name ignoring case. A numeric ref is taken
+// as an ID without a lookup, and named by it.
+func resolvePool(client api.ClientInterface, ref string) (int, string, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, ref, nil
+	}
+	pools, err := client.GetAgentPools([]string{"id", "name"})
+	if err != nil {
+		return 0, "", err
+	}
+	for _, pool := range pools.Pools {
+		if strings.EqualFold(pool.Name, ref) {
+			return pool.ID, pool.Name, nil
+		}
+	}
+	return 0, "", api.Validation(fmt.Sprintf("no agent pool named %q", ref), "Run 'teamcity agent pool list' to see the pools")
+}
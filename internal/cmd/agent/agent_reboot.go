@@ -6,29 +6,29 @@ import (
 
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/spf13/cobra"
 )
 
 func newAgentMoveCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "move <agent> <pool-id>",
-		Short: "Move an agent to a different pool",
-		Args:  cobra.ExactArgs(2),
+		Use:               "move <agent> <pool>",
+		Short:             "Move an agent to a different pool",
+		Long:              "Move an agent to a different pool, given by ID or name.",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: `  teamcity agent move 1 0
-  teamcity agent move Agent-Linux-01 2`,
+  teamcity agent move Agent-Linux-01 2
+  teamcity agent move Agent-Linux-01 Linux`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			poolID, err := cmdutil.ParseID(args[1], "pool")
-			if err != nil {
-				return err
-			}
-			return runAgentMove(f, args[0], poolID)
+			return runAgentMove(f, args[0], args[1])
 		},
 	}
 
 	return cmd
 }
 
-func runAgentMove(f *cmdutil.Factory, nameOrID string, poolID int) error {
+func runAgentMove(f *cmdutil.Factory, nameOrID, poolRef string) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
@@ -39,18 +39,23 @@ func runAgentMove(f *cmdutil.Factory, nameOrID string, poolID int) error {
 		return err
 	}
 
+	poolID, poolName, err := resolvePool(client, poolRef)
+	if err != nil {
+		return err
+	}
+
 	if err := client.SetAgentPool(agentID, poolID); err != nil {
 		return fmt.Errorf("failed to move agent: %w", err)
 	}
 
 	f.Analytics.Track(analytics.GroupAgent, analytics.EventStateChanged, map[string]any{"action": analytics.AgentActionMove})
-	f.Printer.Success("Moved agent %s to pool %d", agentName, poolID)
+	f.Printer.Success("Moved agent %s to pool %s", agentName, poolName)
 	return nil
 }
 
 type agentRebootOptions struct {
-	graceful bool
-	yes      bool
+	afterBuild bool
+	yes        bool
 }
 
 func newAgentRebootCmd(f *cmdutil.Factory) *cobra.Command {
@@ -62,20 +67,24 @@ func newAgentRebootCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `Request a reboot of an agent.
 
 The agent can be specified by ID or name. By default, the agent reboots immediately.
-Use --graceful to wait for current work to finish before rebooting.
+Use --after-build to wait for the build it is running to finish before rebooting.
 
 Note: Local agents (running on the same machine as the server) cannot be rebooted.`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: `  teamcity agent reboot 1
   teamcity agent reboot Agent-Linux-01
-  teamcity agent reboot Agent-Linux-01 --graceful
+  teamcity agent reboot Agent-Linux-01 --after-build
   teamcity agent reboot Agent-Linux-01 --yes`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runAgentReboot(f, f.Context(), args[0], opts)
 		},
 	}
 
-	cmd.Flags().BoolVar(&opts.graceful, "graceful", false, "Wait for current work to finish before rebooting")
+	cmd.Flags().BoolVar(&opts.afterBuild, "after-build", false, "Wait for the current build to finish before rebooting")
+	// --graceful is the flag's earlier name.
+	cmd.Flags().BoolVar(&opts.afterBuild, "graceful", false, "Wait for the current build to finish before rebooting")
+	_ = cmd.Flags().MarkHidden("graceful")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
 
 	return cmd
@@ -104,15 +113,15 @@ func runAgentReboot(f *cmdutil.Factory, ctx context.Context, nameOrID string, op
 		}
 	}
 
-	if err := client.RebootAgent(ctx, agentID, opts.graceful); err != nil {
+	if err := client.RebootAgent(ctx, agentID, opts.afterBuild); err != nil {
 		return fmt.Errorf("failed to reboot agent: %w", err)
 	}
 
 	f.Analytics.Track(analytics.GroupAgent, analytics.EventStateChanged, map[string]any{"action": analytics.AgentActionReboot})
 
-	if opts.graceful {
+	if opts.afterBuild {
 		f.Printer.Success("Reboot scheduled for %s", agentName)
-		_, _ = fmt.Fprintln(f.Printer.Out, "  The agent will reboot after current work finishes.")
+		_, _ = fmt.Fprintln(f.Printer.Out, "  The agent will reboot after the current build finishes.")
 	} else {
 		f.Printer.Success("Reboot initiated for %s", agentName)
 	}
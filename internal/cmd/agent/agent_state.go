@@ -6,36 +6,60 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/spf13/cobra"
 )
 
 type agentAction struct {
-	use     string
-	short   string
-	long    string
-	verb    string
-	execute func(api.ClientInterface, int) error
+	use   string
+	short string
+	long  string
+	verb  string
+	// execute changes the state, leaving comment as the reason when it is not empty.
+	execute func(c api.ClientInterface, id int, comment string) error
 }
 
 var agentActions = map[string]agentAction{
-	"enable": {"enable", "Enable an agent", "Enable an agent to allow it to run builds.", "Enabled",
-		func(c api.ClientInterface, id int) error { return c.EnableAgent(id, true) }},
-	"disable": {"disable", "Disable an agent", "Disable an agent to prevent it from running builds.", "Disabled",
-		func(c api.ClientInterface, id int) error { return c.EnableAgent(id, false) }},
+	"enable":  {"enable", "Enable an agent", "Enable an agent to allow it to run builds.", "Enabled", setEnabled(true)},
+	"disable": {"disable", "Disable an agent", "Disable an agent to prevent it from running builds.", "Disabled", setEnabled(false)},
 	"authorize": {"authorize", "Authorize an agent", "Authorize an agent to allow it to connect and run builds.", "Authorized",
-		func(c api.ClientInterface, id int) error { return c.AuthorizeAgent(id, true) }},
+		setAuthorized(true)},
 	"deauthorize": {"deauthorize", "Deauthorize an agent", "Deauthorize an agent to revoke its permission to connect.", "Deauthorized",
-		func(c api.ClientInterface, id int) error { return c.AuthorizeAgent(id, false) }},
+		setAuthorized(false)},
+}
+
+func setEnabled(enabled bool) func(api.ClientInterface, int, string) error {
+	return func(c api.ClientInterface, id int, comment string) error {
+		if comment == "" {
+			return c.EnableAgent(id, enabled)
+		}
+		return c.SetAgentEnabledInfo(id, enabled, comment)
+	}
+}
+
+func setAuthorized(authorized bool) func(api.ClientInterface, int, string) error {
+	return func(c api.ClientInterface, id int, comment string) error {
+		if comment == "" {
+			return c.AuthorizeAgent(id, authorized)
+		}
+		return c.SetAgentAuthorizedInfo(id, authorized, comment)
+	}
 }
 
 func newAgentActionCmd(f *cmdutil.Factory, a agentAction) *cobra.Command {
-	return &cobra.Command{
+	var comment string
+	cmd := &cobra.Command{
 		Use:   a.use + " <agent>",
 		Short: a.short,
-		Long:  a.long,
-		Args:  cobra.ExactArgs(1),
+		Long: a.long + `
+
+--comment records why, shown with the agent's state in 'agent view' and
+the TeamCity UI.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: fmt.Sprintf(`  teamcity agent %s 1
-  teamcity agent %s Agent-Linux-01`, a.use, a.use),
+  teamcity agent %s Agent-Linux-01
+  teamcity agent %s Agent-Linux-01 --comment "Disk replacement"`, a.use, a.use, a.use),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := f.Client()
 			if err != nil {
@@ -45,7 +69,7 @@ func newAgentActionCmd(f *cmdutil.Factory, a agentAction) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if err := a.execute(client, agentID); err != nil {
+			if err := a.execute(client, agentID, comment); err != nil {
 				return fmt.Errorf("failed to %s agent: %w", a.use, err)
 			}
 			f.Analytics.Track(analytics.GroupAgent, analytics.EventStateChanged, map[string]any{"action": a.use})
@@ -53,4 +77,6 @@ func newAgentActionCmd(f *cmdutil.Factory, a agentAction) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&comment, "comment", "", "Reason for the change, shown with the agent's state")
+	return cmd
 }
@@ -1,10 +1,18 @@
 package agent_test
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 )
@@ -40,12 +48,37 @@ func TestAgentListWebValidatesLimit(t *testing.T) {
 func TestAgentList_plain(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 	got := cmdtest.CaptureOutput(t, ts.Factory, "agent", "list", "--plain")
-	want := "ID\tNAME   \tPOOL   \tSTATUS      \n" +
-		"1 \tAgent 1\tDefault\tConnected   \n" +
-		"2 \tAgent 2\tDefault\tDisconnected\n"
+	want := "ID\tNAME   \tPOOL   \tSTATUS      \tBUILD\n" +
+		"1 \tAgent 1\tDefault\tConnected   \t-    \n" +
+		"2 \tAgent 2\tDefault\tDisconnected\t-    \n"
 	assert.Equal(t, want, got)
 }
 
+func TestAgentList_template(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	got := cmdtest.CaptureOutput(t, ts.Factory, "agent", "list", "--template", "{{.Name}}: {{.Pool.Name}}")
+	assert.Equal(t, "Agent 1: Default\nAgent 2: Default\n", got)
+}
+
+func TestAgentListStateFilters(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var locator string
+	ts.Handle("GET /app/rest/agents", func(w http.ResponseWriter, r *http.Request) {
+		locator = r.URL.Query().Get("locator")
+		cmdtest.JSON(w, api.AgentList{Count: 1, Agents: []api.Agent{
+			{ID: 3, Name: "Agent 3", Connected: true, Authorized: true, Pool: &api.Pool{Name: "Default"},
+				Build: &api.Build{ID: 7, Number: "42", BuildTypeID: "Falcon_Build"}},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "list", "--disconnected", "--disabled")
+	assert.Contains(t, locator, "connected:false")
+	assert.Contains(t, locator, "enabled:false")
+	assert.Contains(t, out, "Falcon_Build #42")
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "none of the others can be", "agent", "list", "--connected", "--disconnected")
+}
+
 func TestAgentView(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
@@ -54,6 +87,77 @@ func TestAgentView(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "agent", "view", "Agent 1", "--json")
 }
 
+// setupBusyAgent serves agent 1 running a build, disabled with a comment, with two finished builds.
+func setupBusyAgent(t *testing.T) *cmdtest.TestServer {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/agents/id:1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("fields"), "enabledInfo(status,comment(")
+		cmdtest.JSON(w, api.Agent{
+			ID: 1, Name: "Agent 1", Connected: true, Authorized: true,
+			WebURL: ts.URL + "/agentDetails.html?id=1",
+			Pool:   &api.Pool{ID: 0, Name: "Default"},
+			Build: &api.Build{ID: 300, Number: "12", BuildTypeID: "Falcon_Build", State: "running", Status: "SUCCESS",
+				BranchName: "main", StartDate: "20260101T120000+0000", PercentageComplete: 40},
+			EnabledInfo: &api.AgentStateInfo{Comment: &api.AgentComment{
+				Text: "disk full", User: &api.User{Username: "jdoe"}, Timestamp: "20260101T110000+0000",
+			}},
+			AuthorizedInfo: &api.AgentStateInfo{Status: true},
+		})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		assert.Contains(t, locator, "agent:(id:1)")
+		assert.Contains(t, locator, "state:finished")
+		assert.Contains(t, locator, "count:5")
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{
+			{ID: 299, Number: "11", BuildTypeID: "Falcon_Build", State: "finished", Status: "FAILURE", BranchName: "main"},
+			{ID: 250, Number: "7", BuildTypeID: "Falcon_Test", State: "finished", Status: "SUCCESS"},
+		}})
+	})
+	return ts
+}
+
+func TestAgentViewSections(t *testing.T) {
+	ts := setupBusyAgent(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "view", "1")
+	assert.Contains(t, out, "Pool: Default")
+	assert.Contains(t, out, `Enabled: No  "disk full", by jdoe,`)
+	assert.Regexp(t, `Current build\n.*Falcon_Build 300  #12  main  running .* \(40%\)`, out)
+	assert.Regexp(t, `Recent builds\n.*Falcon_Build 299  #11  main\n.*Falcon_Test 250  #7\n`, out)
+	assert.Contains(t, out, "Build history: "+ts.URL+"/agentDetails.html?id=1&tab=agentBuildHistory")
+}
+
+func TestAgentViewJSON(t *testing.T) {
+	ts := setupBusyAgent(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "view", "1", "--json")
+	var view struct {
+		ID           int
+		Build        struct{ ID int }
+		EnabledInfo  struct{ Comment struct{ Text string } }
+		RecentBuilds []struct{ ID int }
+		HistoryURL   string
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &view))
+	assert.Equal(t, 1, view.ID, "agent fields stay at the top level")
+	assert.Equal(t, 300, view.Build.ID)
+	assert.Equal(t, "disk full", view.EnabledInfo.Comment.Text)
+	assert.Len(t, view.RecentBuilds, 2)
+	assert.NotEmpty(t, view.HistoryURL)
+}
+
+func TestAgentViewRecentBuildsUnavailable(t *testing.T) {
+	ts := setupBusyAgent(t)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusForbidden, "no permission to view builds")
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "view", "1")
+	assert.Contains(t, out, "Agent 1")
+	assert.Contains(t, out, "Recent builds unavailable")
+}
+
 func TestAgentEnableDisable(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
@@ -86,10 +190,155 @@ func TestAgentMove(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "agent", "move", "Agent 1", "0")
 }
 
+func TestAgentPool(T *testing.T) {
+	T.Run("list counts agents", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		ts.Handle("GET /app/rest/agentPools", func(w http.ResponseWriter, r *http.Request) {
+			assert.Contains(t, r.URL.Query().Get("fields"), "agents(count)")
+			cmdtest.JSON(w, api.PoolList{Count: 2, Pools: []api.Pool{
+				{ID: 0, Name: "Default", Agents: &api.AgentList{Count: 3}},
+				{ID: 1, Name: "Linux Agents", MaxAgents: 10, Agents: &api.AgentList{Count: 7}},
+			}})
+		})
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "pool", "list", "--plain")
+		assert.Regexp(t, `1 *\tLinux Agents\t7 *\t10`, out)
+		assert.Regexp(t, `0 *\tDefault *\t3 *\tunlimited`, out)
+
+		out = cmdtest.CaptureOutput(t, ts.Factory, "agent", "pool", "list", "--json")
+		assert.JSONEq(t, `[{"id":0,"name":"Default","agents":3},{"id":1,"name":"Linux Agents","agents":7,"maxAgents":10}]`, out)
+	})
+
+	T.Run("assign by pool name", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		var body string
+		ts.Handle("PUT /app/rest/agents/id:1/pool", func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "pool", "assign", "Agent 1", "linux agents")
+		assert.JSONEq(t, `{"id":1}`, body)
+		assert.Contains(t, out, "Moved agent Agent 1 to pool Linux Agents")
+
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `no agent pool named "Windows"`, "agent", "pool", "assign", "Agent 1", "Windows")
+	})
+}
+
+func TestAgentStateComment(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var path, body string
+	ts.Handle("PUT /app/rest/agents/id:1/enabledInfo", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(data)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "agent", "disable", "Agent 1", "--comment", "Disk replacement")
+	assert.Equal(T, "/app/rest/agents/id:1/enabledInfo", path)
+	assert.JSONEq(T, `{"status":false,"comment":{"text":"Disk replacement"}}`, body)
+}
+
+func TestAgentStateComment_dryRun(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var writes atomic.Int32
+	ts.Handle("PUT /app/rest/agents/id:1/", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "agent", "disable", "Agent 1", "--comment", "Disk replacement", "--dry-run")
+	assert.Contains(T, got, "[dry-run] Would send PUT /app/rest/agents/id:1/enabledInfo")
+	got = cmdtest.CaptureOutput(T, ts.Factory, "agent", "deauthorize", "Agent 1", "--comment", "Decommissioned", "--dry-run")
+	assert.Contains(T, got, "[dry-run] Would send PUT /app/rest/agents/id:1/authorizedInfo")
+	assert.Zero(T, writes.Load(), "--dry-run sends no write")
+}
+
+func TestAgentViewIncompatibleJobs(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "agent", "view", "1")
+	assert.Contains(T, out, "Incompatible jobs (1)")
+	assert.Contains(T, out, "OtherProject_Build  Missing requirement: docker")
+
+	var view struct {
+		IncompatibleJobs []api.Compatibility `json:"incompatibleJobs"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "agent", "view", "1", "--json")), &view))
+	require.Len(T, view.IncompatibleJobs, 1)
+	assert.Equal(T, "OtherProject_Build", view.IncompatibleJobs[0].BuildType.ID)
+}
+
 func TestAgentReboot(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
 
 	cmdtest.RunCmdWithFactory(T, f, "agent", "reboot", "Agent 1")
 	cmdtest.RunCmdWithFactory(T, f, "agent", "reboot", "1", "--graceful")
+
+	var form string
+	ts.Handle("POST /remoteAccess/reboot.html", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.Form.Encode()
+	})
+	out := cmdtest.CaptureOutput(T, f, "agent", "reboot", "1", "--after-build", "--yes")
+	assert.Contains(T, form, "rebootAfterBuild=true")
+	assert.Contains(T, out, "after the current build finishes")
+}
+
+func TestAgentConfigParams(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	props := map[string][]api.Property{
+		"1": {{Name: "teamcity.agent.jvm.os.name", Value: "Linux"}, {Name: "env.JAVA_HOME", Value: "/opt/jdk-21"}, {Name: "docker.version", Value: "27.1"}},
+		"2": {{Name: "env.JAVA_HOME", Value: "/opt/jdk-17"}, {Name: "teamcity.agent.jvm.os.name", Value: "Linux"}, {Name: "env.NODE_HOME", Value: "/opt/node"}},
+	}
+	ts.Handle("GET /app/rest/agents/id:", func(w http.ResponseWriter, r *http.Request) {
+		id := cmdtest.ExtractID(r.URL.Path, "id:")
+		if strings.Contains(r.URL.Query().Get("fields"), "properties") {
+			cmdtest.JSON(w, map[string]any{"properties": api.PropertyList{Property: props[id]}})
+			return
+		}
+		n, _ := strconv.Atoi(id)
+		cmdtest.JSON(w, api.Agent{ID: n, Name: "Agent " + id})
+	})
+
+	T.Run("sorted", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1")
+		assert.Regexp(t, `(?s)docker\.version.*env\.JAVA_HOME.*teamcity\.agent\.jvm\.os\.name`, out)
+	})
+
+	T.Run("filter", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1", "--filter", "JAVA", "--json")
+		assert.JSONEq(t, `{"property":[{"name":"env.JAVA_HOME","value":"/opt/jdk-21"}]}`, out)
+	})
+
+	T.Run("diff", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1", "--diff", "2", "--json")
+		assert.JSONEq(t, `{
+			"agent": {"id": 1, "name": "Agent 1"},
+			"other": {"id": 2, "name": "Agent 2"},
+			"differences": [
+				{"name": "docker.version", "value": "27.1", "otherValue": null},
+				{"name": "env.JAVA_HOME", "value": "/opt/jdk-21", "otherValue": "/opt/jdk-17"},
+				{"name": "env.NODE_HOME", "value": null, "otherValue": "/opt/node"}
+			]
+		}`, out)
+
+		out = cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1", "--diff", "2")
+		assert.Contains(t, out, "AGENT 1")
+		assert.Contains(t, out, "(not reported)")
+		assert.NotContains(t, out, "teamcity.agent.jvm.os.name")
+	})
+
+	T.Run("no differences", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1", "--diff", "2", "--filter", "os.name")
+		assert.Contains(t, out, "No differing parameters between Agent 1 and Agent 2")
+	})
+
+	T.Run("no matches", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "agent", "config-params", "1", "--filter", "nothing-like-this")
+		assert.Contains(t, out, "No matching parameters found")
+		assert.Contains(t, out, "Drop --filter")
+	})
 }
@@ -0,0 +1,255 @@
+package agent
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/spf13/cobra"
+)
+
+// recentBuildsLimit is how many finished builds agent view lists.
+const recentBuildsLimit = 5
+
+// incompatibleJobsShown caps how many incompatible jobs agent view names; agent jobs --incompatible lists them all.
+const incompatibleJobsShown = 5
+
+// agentView is the --json shape of agent view: the agent, with its current build and pool nested, plus its recent builds.
+type agentView struct {
+	*api.Agent
+	RecentBuilds      []api.Build `json:"recentBuilds"`
+	RecentBuildsError string      `json:"recentBuildsError,omitempty"`
+	HistoryURL        string      `json:"historyUrl,omitempty"`
+	// IncompatibleJobs are the jobs the agent cannot run, with the reasons; nil when they could not be fetched.
+	IncompatibleJobs []api.Compatibility `json:"incompatibleJobs,omitempty"`
+}
+
+func newAgentViewCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &cmdutil.ViewOptions{}
+	cmd := &cobra.Command{
+		Use:     "view <agent>",
+		Short:   "View agent details",
+		Aliases: []string{"show"},
+		Long: `Show an agent's state and what it has been doing.
+
+Lists the pool, whether the agent is connected, enabled, and authorized
+(with the comment left when that last changed), when it was last active,
+the build it is running right now, the last 5 builds it finished, and the
+jobs it cannot run with the reasons why; 'agent jobs --incompatible'
+lists all of those.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
+		Example: `  teamcity agent view 1
+  teamcity agent view Agent-Linux-01
+  teamcity agent view Agent-Linux-01 --web
+  teamcity agent view 1 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentView(f, args[0], opts)
+		},
+	}
+	cmdutil.AddViewFlags(cmd, opts)
+	return cmd
+}
+
+func runAgentView(f *cmdutil.Factory, nameOrID string, opts *cmdutil.ViewOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	var (
+		agent     *api.Agent
+		agentErr  error
+		builds    *api.BuildList
+		buildsErr error
+	)
+	var wg sync.WaitGroup
+	wg.Go(func() { agent, agentErr = cmdutil.ResolveAgent(client, nameOrID) })
+	if !opts.Web {
+		wg.Go(func() { builds, buildsErr = recentAgentBuilds(f.Context(), client, nameOrID) })
+	}
+	wg.Wait()
+	if agentErr != nil {
+		return agentErr
+	}
+
+	if done, err := opts.EmitWebURL(f.Printer, agent.WebURL); done {
+		return err
+	}
+
+	view := agentView{Agent: agent, RecentBuilds: []api.Build{}}
+	if incompatible, err := client.GetAgentIncompatibleBuildTypes(agent.ID); err == nil {
+		view.IncompatibleJobs = incompatible.Compatibility
+	}
+	if buildsErr != nil {
+		view.RecentBuildsError = buildsErr.Error()
+	} else {
+		view.RecentBuilds = builds.Builds
+	}
+	if agent.WebURL != "" {
+		view.HistoryURL = agent.WebURL + "&tab=agentBuildHistory"
+	}
+
+	if opts.JSON {
+		return f.Printer.PrintJSON(view)
+	}
+
+	p := f.Printer
+	_, _ = fmt.Fprintf(p.Out, "%s\n", output.Cyan(agent.Name))
+	_, _ = fmt.Fprintf(p.Out, "ID: %d\n", agent.ID)
+
+	if agent.Pool != nil {
+		_, _ = fmt.Fprintf(p.Out, "Pool: %s\n", agent.Pool.Name)
+	}
+
+	_, _ = fmt.Fprintf(p.Out, "Status: %s\n", cmdutil.FormatAgentStatus(*agent))
+
+	if agent.Connected {
+		_, _ = fmt.Fprintf(p.Out, "Connected: %s\n", output.Green("Yes"))
+	} else {
+		_, _ = fmt.Fprintf(p.Out, "Connected: %s%s\n", output.Red("No"), stateNote(agent.DisconnectionComment, nil))
+	}
+
+	if agent.Enabled {
+		_, _ = fmt.Fprintf(p.Out, "Enabled: %s%s\n", output.Green("Yes"), stateInfoNote(agent.EnabledInfo))
+	} else {
+		_, _ = fmt.Fprintf(p.Out, "Enabled: %s%s\n", output.Faint("No"), stateInfoNote(agent.EnabledInfo))
+	}
+
+	if agent.Authorized {
+		_, _ = fmt.Fprintf(p.Out, "Authorized: %s%s\n", output.Green("Yes"), stateInfoNote(agent.AuthorizedInfo))
+	} else {
+		_, _ = fmt.Fprintf(p.Out, "Authorized: %s%s\n", output.Yellow("No"), stateInfoNote(agent.AuthorizedInfo))
+	}
+
+	switch {
+	case !agent.Connected:
+		if t, err := api.ParseTeamCityTime(agent.LastActivityTime); err == nil {
+			_, _ = fmt.Fprintf(p.Out, "Last seen: %s\n", output.RelativeTime(t))
+		}
+	case agent.Build == nil:
+		if t, err := api.ParseTeamCityTime(agent.IdleSinceTime); err == nil {
+			_, _ = fmt.Fprintf(p.Out, "Idle since: %s\n", output.RelativeTime(t))
+		}
+	}
+
+	if b := agent.Build; b != nil {
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Bold("Current build"))
+		line := fmt.Sprintf("  %s %s %d  %s", output.StatusIcon(b.Status, b.State, b.StatusText), output.Cyan(cmdutil.JobName(b.BuildType, b.BuildTypeID)), b.ID, cmdutil.RunNumber(b.Number))
+		if b.BranchName != "" {
+			line += "  " + output.Faint(b.BranchName)
+		}
+		if start, err := api.ParseTeamCityTime(b.StartDate); err == nil {
+			line += fmt.Sprintf("  running %s", output.FormatDuration(timeref.Since(start)))
+			if b.PercentageComplete > 0 {
+				line += fmt.Sprintf(" (%d%%)", b.PercentageComplete)
+			}
+		}
+		_, _ = fmt.Fprintln(p.Out, line)
+	}
+
+	switch {
+	case buildsErr != nil:
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint("Recent builds unavailable: "+buildsErr.Error()))
+	case len(view.RecentBuilds) == 0:
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint("No finished builds on this agent"))
+	default:
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Bold("Recent builds"))
+		for _, b := range view.RecentBuilds {
+			line := fmt.Sprintf("  %s %s %d  %s", output.StatusIcon(b.Status, b.State, b.StatusText), output.Cyan(cmdutil.JobName(b.BuildType, b.BuildTypeID)), b.ID, cmdutil.RunNumber(b.Number))
+			if b.BranchName != "" {
+				line += "  " + output.Faint(b.BranchName)
+			}
+			if t, err := api.ParseTeamCityTime(cmp.Or(b.FinishDate, b.StartDate)); err == nil {
+				line += "  " + output.Faint(output.RelativeTime(t))
+			}
+			_, _ = fmt.Fprintln(p.Out, line)
+		}
+	}
+
+	printIncompatibleJobs(p, agent.ID, view.IncompatibleJobs)
+
+	if view.HistoryURL != "" {
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("Build history:"), output.Green(view.HistoryURL))
+		_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint("View in browser:"), output.Green(agent.WebURL))
+	}
+
+	if agent.Connected {
+		_, _ = fmt.Fprintf(p.Out, "%s teamcity agent term %d\n", output.Faint("Open terminal:"), agent.ID)
+	}
+
+	return nil
+}
+
+// printIncompatibleJobs names the first jobs the agent cannot run, each with its first reason.
+func printIncompatibleJobs(p *output.Printer, agentID int, jobs []api.Compatibility) {
+	if len(jobs) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Bold(fmt.Sprintf("Incompatible jobs (%d)", len(jobs))))
+	for _, c := range jobs[:min(len(jobs), incompatibleJobsShown)] {
+		if c.BuildType == nil {
+			continue
+		}
+		line := "  " + output.Cyan(c.BuildType.ID)
+		if c.Reasons != nil && len(c.Reasons.Reasons) > 0 {
+			line += "  " + output.Faint(c.Reasons.Reasons[0])
+			if more := len(c.Reasons.Reasons) - 1; more > 0 {
+				line += output.Faint(fmt.Sprintf(" (+%d more)", more))
+			}
+		}
+		_, _ = fmt.Fprintln(p.Out, line)
+	}
+	if len(jobs) > incompatibleJobsShown {
+		_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(fmt.Sprintf("All: teamcity agent jobs %d --incompatible", agentID)))
+	}
+}
+
+// recentAgentBuilds returns the last builds nameOrID finished, newest first.
+func recentAgentBuilds(ctx context.Context, client api.ClientInterface, nameOrID string) (*api.BuildList, error) {
+	builds, _, err := client.GetBuilds(ctx, api.BuildsOptions{
+		Agent: nameOrID,
+		State: "finished",
+		Limit: recentBuildsLimit,
+		Fields: []string{
+			"id", "number", "status", "state", "statusText", "branchName", "buildTypeId",
+			"buildType.id", "buildType.name", "startDate", "finishDate", "webUrl",
+		},
+	})
+	return builds, err
+}
+
+// stateInfoNote is stateNote for an agent's enabled or authorized info.
+func stateInfoNote(info *api.AgentStateInfo) string {
+	if info == nil || info.Comment == nil {
+		return ""
+	}
+	return stateNote(info.Comment.Text, info.Comment)
+}
+
+// stateNote renders the comment on an agent state, with who left it and when if known, as a faint suffix.
+func stateNote(text string, c *api.AgentComment) string {
+	var parts []string
+	if text = strings.TrimSpace(text); text != "" {
+		parts = append(parts, fmt.Sprintf("%q", text))
+	}
+	if c != nil {
+		if name := cmdutil.UserName(c.User); name != "" {
+			parts = append(parts, "by "+name)
+		}
+		if t, err := api.ParseTeamCityTime(c.Timestamp); err == nil {
+			parts = append(parts, output.RelativeTime(t))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  " + output.Faint(strings.Join(parts, ", "))
+}
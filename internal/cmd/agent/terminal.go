@@ -10,9 +10,11 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/JetBrains/teamcity-cli/internal/terminal"
+	"github.com/JetBrains/teamcity-cli/internal/version"
 	"github.com/spf13/cobra"
 )
 
@@ -29,7 +31,8 @@ CONNECT_TO_AGENT permission. Terminal access is independent of agent
 authorization, so an unauthorized or disabled agent can still be
 reached. The session runs over a WebSocket and exits when the remote
 shell exits or the connection drops.`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: `  teamcity agent term 1
   teamcity agent term Agent-Linux-01`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -71,7 +74,8 @@ func newAgentExecCmd(f *cmdutil.Factory) *cobra.Command {
 Use 'teamcity agent term' for an interactive shell. Commands longer
 than the default timeout (5m) need --timeout; use -- to separate
 agent-side commands from teamcity flags.`,
-		Args: cobra.MinimumNArgs(2),
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Agents()),
 		Example: `  teamcity agent exec 1 "ls -la"
   teamcity agent exec Agent-Linux-01 "cat /etc/os-release"
   teamcity agent exec Agent-Linux-01 --timeout 10m -- long-running-script.sh`,
@@ -107,9 +111,12 @@ agent-side commands from teamcity flags.`,
 
 func connectToAgent(f *cmdutil.Factory, ctx context.Context, nameOrID string, showProgress bool) (*terminal.Conn, error) {
 	serverURL := config.GetServerURL()
-	token, _, keyringErr := config.GetTokenWithSource()
+	token, _, tokenErr := config.GetTokenWithSource()
+	if cmdErr, ok := errors.AsType[*config.TokenCommandError](tokenErr); ok {
+		return nil, cmdErr
+	}
 	if serverURL == "" || token == "" {
-		return nil, cmdutil.NotAuthenticatedError(ctx, serverURL, keyringErr)
+		return nil, cmdutil.NotAuthenticatedError(ctx, serverURL, tokenErr)
 	}
 
 	client, err := f.Client()
@@ -144,7 +151,15 @@ func connectToAgent(f *cmdutil.Factory, ctx context.Context, nameOrID string, sh
 		username = user.Username
 	}
 
-	termClient := terminal.NewClient(serverURL, username, token, f.Printer.Debug)
+	termClient, err := terminal.NewClient(serverURL, username, token, f.Printer.Debug).
+		Identify(api.UserAgent(version.String()), f.CLIContext()).
+		WithTLS(config.ServerTLS(serverURL))
+	if err != nil {
+		return nil, err
+	}
+	if r := f.Recorder(); r != nil {
+		termClient.WithRoundTripper(r.Wrap)
+	}
 	session, err := termClient.OpenSession(agent.ID)
 	if err != nil {
 		return nil, err
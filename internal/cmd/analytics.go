@@ -196,6 +196,10 @@ func detectAuthSourceForAnalytics() string {
 	if config.IsGuestAuth() {
 		return analytics.AuthSourceGuest
 	}
+	// Running a token command only to name its source would fork on every invocation.
+	if config.TokenCommand() != "" {
+		return analytics.AuthSourceCommand
+	}
 	_, source, _ := config.GetTokenWithSource()
 	switch source {
 	case "env":
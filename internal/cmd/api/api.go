@@ -8,19 +8,31 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+	"github.com/itchyny/gojq"
 	"github.com/spf13/cobra"
 )
 
+// maxPaginationPages is the default --max-pages.
 const maxPaginationPages = 100
 
+// largeBodyThreshold is the response size above which printing to a terminal asks first.
+const largeBodyThreshold = 5 << 20
+
+// terminalOutput reports whether w is an interactive terminal; tests override it.
+var terminalOutput = func(w io.Writer) bool { return output.IsStdout(w) && output.IsTerminal() }
+
 var knownArrayKeys = []string{
 	"build", "buildType", "project", "agent", "agentPool",
 	"vcsRoot", "change", "user", "group", "test", "problem",
@@ -36,6 +48,16 @@ type apiOptions struct {
 	raw      bool
 	paginate bool
 	slurp    bool
+	fail     bool
+	output   string
+	jq       string
+	maxPages int
+	perPage  int
+	delay    time.Duration
+
+	maxPagesSet bool
+	interactive bool
+	jqCode      *gojq.Code
 }
 
 func NewCmd(f *cmdutil.Factory) *cobra.Command {
@@ -50,6 +72,27 @@ The endpoint argument should be the path portion of the URL,
 starting with /app/rest/. The base URL and authentication
 are handled automatically.
 
+--jq filters a JSON response with a jq expression, without an external jq:
+strings are printed without quotes, anything else as JSON, compacted with
+--raw. With --paginate, the pages are merged into one array first, as with
+--slurp, and the filter runs once over it.
+
+--paginate follows the nextHref of each page, up to --max-pages pages (0 for
+no limit). Without --slurp, the items of each page are printed as they
+arrive, one compact JSON object per line whether or not --raw is given, so
+a long listing never piles up in memory; --include then needs --slurp.
+Reaching the default cap of 100 pages with more still available exits 1,
+after the earlier pages have already been printed.
+--per-page sets the page size as count in the locator, unless the locator
+already has one, and --delay waits between page requests to spare a busy
+server. A nextHref on another host than the configured server is refused.
+
+A response outside 2xx is an error, exiting 1. With --fail the exit status
+tells them apart instead: 4 for a 4xx response and 5 for a 5xx one. The
+global --verbose flag traces the request and response, with auth headers
+redacted and the time spent on DNS, connecting, TLS and the first byte, on
+stderr.
+
 This command is useful for:
 - Accessing API features not yet supported by the CLI
 - Scripting and automation
@@ -67,8 +110,22 @@ See: https://www.jetbrains.com/help/teamcity/rest/teamcity-rest-api-documentatio
   teamcity api '/app/rest/buildQueue' -X POST -f 'buildType=id:MyBuild'
 
   # Fetch all pages and combine into array
-  teamcity api '/app/rest/builds' --paginate --slurp`,
+  teamcity api '/app/rest/builds' --paginate --slurp
+
+  # Stream runs one per line, 500 a page, pausing between pages
+  teamcity api '/app/rest/builds' --paginate --per-page 500 --delay 200ms --max-pages 0
+
+  # Filter the response with a jq expression
+  teamcity api '/app/rest/builds' --paginate --jq '.[] | .id'
+  teamcity api '/app/rest/server' --jq '.version'
+
+  # Exit 4 on a 4xx and 5 on a 5xx response, tracing the exchange on stderr
+  teamcity api '/app/rest/builds/id:123' --fail --verbose
+
+  # Save a large or binary response to a file
+  teamcity api '/app/rest/builds/id:123/artifacts/content/dist.tar.gz' --output dist.tar.gz`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.maxPagesSet = cmd.Flags().Changed("max-pages")
 			return runAPI(f, args[0], opts)
 		},
 	}
@@ -79,14 +136,24 @@ See: https://www.jetbrains.com/help/teamcity/rest/teamcity-rest-api-documentatio
 	cmd.Flags().StringVar(&opts.input, "input", "", "Read request body from file (use - for stdin)")
 	cmd.Flags().BoolVarP(&opts.include, "include", "i", false, "Include response headers in output")
 	cmd.Flags().BoolVar(&opts.silent, "silent", false, "Suppress output on success")
+	cmd.Flags().BoolVar(&opts.fail, "fail", false, "Exit 4 on a 4xx response and 5 on a 5xx one")
 	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Output raw response without formatting")
 	cmd.Flags().BoolVar(&opts.paginate, "paginate", false, "Make additional requests to fetch all pages")
 	cmd.Flags().BoolVar(&opts.slurp, "slurp", false, "Combine paginated results into a JSON array (requires --paginate)")
+	cmd.Flags().IntVar(&opts.maxPages, "max-pages", maxPaginationPages, "Stop --paginate after this many pages (0 for no limit)")
+	cmd.Flags().IntVar(&opts.perPage, "per-page", 0, "Items per page for --paginate, as count in the locator unless it sets one")
+	cmd.Flags().DurationVar(&opts.delay, "delay", 0, "Wait this long between page requests of --paginate (e.g. 200ms)")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the response body to a file instead of stdout")
+	cmd.Flags().StringVar(&opts.jq, "jq", "", "Filter the JSON response with a jq expression")
 
 	cmd.MarkFlagsMutuallyExclusive("input", "field")
+	cmd.MarkFlagsMutuallyExclusive("silent", "output")
+	cmd.MarkFlagsMutuallyExclusive("jq", "output")
+	cmd.MarkFlagsMutuallyExclusive("jq", "silent")
 
 	_ = cmd.RegisterFlagCompletionFunc("method", completion.HTTPMethods())
 	_ = cmd.MarkFlagFilename("input")
+	_ = cmd.MarkFlagFilename("output")
 
 	return cmd
 }
@@ -98,6 +165,28 @@ func runAPI(f *cmdutil.Factory, endpoint string, opts *apiOptions) error {
 	if opts.slurp && !opts.paginate {
 		return errors.New("--slurp requires --paginate")
 	}
+	if opts.output != "" && opts.paginate && !opts.slurp {
+		return errors.New("--output with --paginate requires --slurp")
+	}
+	if opts.include && opts.paginate && !opts.slurp && opts.jq == "" {
+		return errors.New("--include with --paginate requires --slurp")
+	}
+	if !opts.paginate && (opts.maxPagesSet || opts.perPage != 0 || opts.delay != 0) {
+		return errors.New("--max-pages, --per-page and --delay require --paginate")
+	}
+	if opts.maxPages < 0 || opts.perPage < 0 || opts.delay < 0 {
+		return errors.New("--max-pages, --per-page and --delay cannot be negative")
+	}
+	if opts.jq != "" {
+		code, err := compileJQ(opts.jq)
+		if err != nil {
+			return err
+		}
+		opts.jqCode = code
+		// The filter sees the whole result, so pages are merged first.
+		opts.slurp = opts.slurp || opts.paginate
+	}
+	opts.interactive = f.IsInteractive()
 	if opts.method == "GET" && len(opts.fields) > 0 {
 		f.Printer.Warn("--field is ignored for GET requests. Use -X POST to send a request body.")
 	}
@@ -162,6 +251,12 @@ func runAPI(f *cmdutil.Factory, endpoint string, opts *apiOptions) error {
 	}
 
 	if opts.paginate {
+		if opts.perPage > 0 {
+			var ok bool
+			if endpoint, ok = withPageSize(endpoint, opts.perPage); !ok {
+				f.Printer.Warn("--per-page is ignored: the locator already sets count")
+			}
+		}
 		lastStatus, err := runAPIPaginated(f.Context(), f.Printer, client, endpoint, headers, opts)
 		f.Analytics.TrackAPI(analytics.APIEvent{
 			Method:     opts.method,
@@ -172,7 +267,7 @@ func runAPI(f *cmdutil.Factory, endpoint string, opts *apiOptions) error {
 			HadFields:  len(opts.fields) > 0,
 			HadInput:   opts.input != "",
 		})
-		return err
+		return failExit(f.Printer, opts, lastStatus, err)
 	}
 
 	resp, err := client.RawRequest(f.Context(), opts.method, endpoint, body, headers)
@@ -189,7 +284,21 @@ func runAPI(f *cmdutil.Factory, endpoint string, opts *apiOptions) error {
 		return err
 	}
 
-	return outputAPIResponse(f.Printer, resp.Body, resp.StatusCode, resp.Headers, opts)
+	err = outputAPIResponse(f.Context(), f.Printer, resp.Body, resp.StatusCode, resp.Headers, opts)
+	return failExit(f.Printer, opts, resp.StatusCode, err)
+}
+
+// failExit turns err from a 4xx or 5xx response into exit status 4 or 5 under --fail, printing it first, since
+// the CLI prints nothing for an error that only sets the exit status.
+func failExit(p *output.Printer, opts *apiOptions, status int, err error) error {
+	if !opts.fail || err == nil || status < 400 || status > 599 {
+		return err
+	}
+	_, _ = fmt.Fprintf(p.ErrOut, "Error: %v\n", output.RenderError(err))
+	if status < 500 {
+		return &cmdutil.ExitError{Code: cmdutil.ExitHTTPClientError}
+	}
+	return &cmdutil.ExitError{Code: cmdutil.ExitHTTPServerError}
 }
 
 func statusCodeOf(r *api.RawResponse) int {
@@ -207,8 +316,22 @@ func statusCodeForTracking(err error, observed int) int {
 }
 
 // runAPIPaginated drives the multi-page fetch and returns (lastStatus, err); lastStatus is the HTTP status of the failed request when err is non-nil and 200 on success, so analytics never silently records 200 for a failed pagination.
+// With --slurp the pages are merged before printing; otherwise each page's items are printed as it arrives.
 func runAPIPaginated(ctx context.Context, p *output.Printer, client api.ClientInterface, endpoint string, headers map[string]string, opts *apiOptions) (int, error) {
-	pages, lastStatus, err := fetchAllPages(ctx, client, endpoint, headers)
+	if !opts.slurp {
+		return fetchPages(ctx, p, client, endpoint, headers, opts, func(page []byte) error {
+			if opts.silent {
+				return nil
+			}
+			return writePageItems(p.Out, page)
+		})
+	}
+
+	var pages [][]byte
+	lastStatus, err := fetchPages(ctx, p, client, endpoint, headers, opts, func(page []byte) error {
+		pages = append(pages, page)
+		return nil
+	})
 	if err != nil {
 		return lastStatus, err
 	}
@@ -217,35 +340,46 @@ func runAPIPaginated(ctx context.Context, p *output.Printer, client api.ClientIn
 		return lastStatus, nil
 	}
 
-	if opts.slurp {
-		arrayKey, err := detectArrayKey(pages[0])
-		if err != nil {
-			return lastStatus, fmt.Errorf("failed to detect array key: %w", err)
-		}
-		if arrayKey == "" {
-			return lastStatus, errors.New("--slurp requires response with array field (build, project, etc.)")
-		}
+	arrayKey, err := detectArrayKey(pages[0])
+	if err != nil {
+		return lastStatus, fmt.Errorf("failed to detect array key: %w", err)
+	}
+	if arrayKey == "" {
+		return lastStatus, errors.New("--slurp requires response with array field (build, project, etc.)")
+	}
 
-		merged, err := mergePages(pages, arrayKey)
-		if err != nil {
-			return lastStatus, fmt.Errorf("failed to merge pages: %w", err)
-		}
-		return lastStatus, outputAPIResponse(p, merged, http.StatusOK, nil, opts)
+	merged, err := mergePages(pages, arrayKey)
+	if err != nil {
+		return lastStatus, fmt.Errorf("failed to merge pages: %w", err)
 	}
+	return lastStatus, outputAPIResponse(ctx, p, merged, http.StatusOK, nil, opts)
+}
 
-	for i, page := range pages {
-		if i > 0 {
-			_, _ = fmt.Fprintln(p.Out)
+// writePageItems writes the items of a page as NDJSON, one compact object per line, or the whole page on one line
+// when it has no known array field.
+func writePageItems(w io.Writer, page []byte) error {
+	key, err := detectArrayKey(page)
+	if err != nil {
+		return fmt.Errorf("--paginate requires JSON response: %w", err)
+	}
+	items := []json.RawMessage{page}
+	if key != "" {
+		if items, err = extractArrayItems(page, key); err != nil {
+			return fmt.Errorf("failed to extract items from page: %w", err)
 		}
-		if err := outputAPIResponse(p, page, http.StatusOK, nil, opts); err != nil {
-			return lastStatus, err
+	}
+	var buf bytes.Buffer
+	for _, item := range items {
+		if err := json.Compact(&buf, item); err != nil {
+			return err
 		}
+		buf.WriteByte('\n')
 	}
-
-	return lastStatus, nil
+	_, err = w.Write(buf.Bytes())
+	return err
 }
 
-func outputAPIResponse(p *output.Printer, body []byte, statusCode int, respHeaders map[string][]string, opts *apiOptions) error {
+func outputAPIResponse(ctx context.Context, p *output.Printer, body []byte, statusCode int, respHeaders map[string][]string, opts *apiOptions) error {
 	if opts.silent && statusCode >= 200 && statusCode < 300 {
 		return nil
 	}
@@ -271,6 +405,24 @@ func outputAPIResponse(p *output.Printer, body []byte, statusCode int, respHeade
 		return api.ErrorFromBody(statusCode, body)
 	}
 
+	if opts.jqCode != nil {
+		return writeJQ(ctx, p.Out, opts.jqCode, body, opts.raw)
+	}
+
+	if opts.output != "" {
+		if err := os.WriteFile(opts.output, body, 0o644); err != nil {
+			return fmt.Errorf("failed to write response to %s: %w", opts.output, err)
+		}
+		p.Success("Saved response to %s (%s)", opts.output, humanize.IBytes(uint64(len(body))))
+		return nil
+	}
+
+	if len(body) > 0 && terminalOutput(p.Out) {
+		if ok, err := confirmTerminalBody(p, body, opts); !ok || err != nil {
+			return err
+		}
+	}
+
 	if len(body) > 0 {
 		switch {
 		case opts.raw:
@@ -289,41 +441,145 @@ func outputAPIResponse(p *output.Printer, body []byte, statusCode int, respHeade
 	return nil
 }
 
-// fetchAllPages walks the pagination chain and returns (pages, lastStatus, err); lastStatus is the HTTP status of the failed request when err is non-nil (0 for transport errors that produced no response), or 200 on success.
-func fetchAllPages(ctx context.Context, client api.ClientInterface, endpoint string, headers map[string]string) ([][]byte, int, error) {
-	var pages [][]byte
+// confirmTerminalBody guards the terminal against bodies it cannot usefully show: compressed data would print as
+// binary garbage, and a very large body floods the scrollback, so that asks first (or warns without a prompt).
+func confirmTerminalBody(p *output.Printer, body []byte, opts *apiOptions) (bool, error) {
+	size := humanize.IBytes(uint64(len(body)))
+	if kind := api.Compression(body); kind != "" {
+		return false, api.Validation(
+			fmt.Sprintf("response is %s-compressed data (%s) and would print as binary", kind, size),
+			"Save it with --output FILE, or pipe it to a file")
+	}
+	if len(body) <= largeBodyThreshold {
+		return true, nil
+	}
+	if !opts.interactive {
+		p.Warn("Printing a %s response - use --output FILE to save it instead", size)
+		return true, nil
+	}
+	var confirm bool
+	if err := cmdutil.Confirm(fmt.Sprintf("Print the %s response to the terminal?", size), &confirm); err != nil {
+		return false, err
+	}
+	if !confirm {
+		p.Info("Not printed; save it with --output FILE")
+	}
+	return confirm, nil
+}
+
+// fetchPages walks the pagination chain, handing each page to onPage as it arrives, and returns (lastStatus, err);
+// lastStatus is the HTTP status of the failed request when err is non-nil (0 for transport errors that produced no
+// response), or 200 on success. It stops after opts.maxPages pages: with a warning when --max-pages was given, and
+// with an error at the default cap.
+func fetchPages(ctx context.Context, p *output.Printer, client api.ClientInterface, endpoint string, headers map[string]string, opts *apiOptions, onPage func([]byte) error) (int, error) {
 	currentEndpoint := endpoint
 	lastStatus := 0
 
-	for range maxPaginationPages {
+	for page := 1; ; page++ {
 		resp, err := client.RawRequest(ctx, "GET", currentEndpoint, nil, headers)
 		if err != nil {
 			// Transport error: no HTTP response observed. Returning the previous page's status here
 			// would misclassify the failure as the prior page's success in analytics.
-			return nil, 0, err
+			return 0, err
 		}
 		lastStatus = resp.StatusCode
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, lastStatus, api.ErrorFromBody(resp.StatusCode, resp.Body)
+			return lastStatus, api.ErrorFromBody(resp.StatusCode, resp.Body)
 		}
 
-		pages = append(pages, resp.Body)
-
 		nextHref, err := extractNextHref(resp.Body)
 		if err != nil {
-			return nil, lastStatus, fmt.Errorf("--paginate requires JSON response: %w", err)
+			return lastStatus, fmt.Errorf("--paginate requires JSON response: %w", err)
+		}
+		if err := onPage(resp.Body); err != nil {
+			return lastStatus, err
 		}
 
 		if nextHref == "" {
-			return pages, lastStatus, nil
+			return lastStatus, nil
+		}
+		if opts.maxPages > 0 && page >= opts.maxPages {
+			if opts.maxPagesSet {
+				p.Warn("Stopped after %s; more are available (raise --max-pages, or 0 for no limit)", english.Plural(page, "page", ""))
+				return lastStatus, nil
+			}
+			return lastStatus, fmt.Errorf("--paginate hit the page cap of %d with more pages still available; refine your query (e.g. add a locator filter) or raise --max-pages (0 for no limit)", opts.maxPages)
 		}
 
 		// nextHref carries the server's context path (e.g. /bs); strip it so RawRequest's BaseURL doesn't double it.
-		currentEndpoint = client.NormalizePaginationPath(nextHref)
+		currentEndpoint, err = client.NormalizePaginationPath(nextHref)
+		if err != nil {
+			return lastStatus, err
+		}
+
+		if opts.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return lastStatus, ctx.Err()
+			case <-time.After(opts.delay):
+			}
+		}
+	}
+}
+
+// withPageSize adds count:n to the locator of endpoint, or a locator of just that; it reports false and leaves
+// endpoint as it is when the locator already sets count.
+func withPageSize(endpoint string, n int) (string, bool) {
+	path, query, _ := strings.Cut(endpoint, "?")
+	count := fmt.Sprintf("count:%d", n)
+	var params []string
+	if query != "" {
+		params = strings.Split(query, "&")
+	}
+	for i, param := range params {
+		value, ok := strings.CutPrefix(param, "locator=")
+		if !ok {
+			continue
+		}
+		locator, err := url.QueryUnescape(value)
+		if err != nil {
+			locator = value
+		}
+		if locatorHasDimension(locator, "count") {
+			return endpoint, false
+		}
+		if locator == "" {
+			params[i] = "locator=" + count
+		} else {
+			params[i] = param + "," + count
+		}
+		return path + "?" + strings.Join(params, "&"), true
 	}
+	return path + "?" + strings.Join(append(params, "locator="+count), "&"), true
+}
 
-	return nil, lastStatus, fmt.Errorf("--paginate hit the page cap of %d with more pages still available; refine your query (e.g. add a locator filter)", maxPaginationPages)
+// locatorHasDimension reports whether locator has the top-level dimension name, outside any nested locator.
+func locatorHasDimension(locator, name string) bool {
+	depth, start := 0, 0
+	for i := 0; i <= len(locator); i++ {
+		if i < len(locator) {
+			switch locator[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if dim, _, _ := strings.Cut(locator[start:i], ":"); strings.TrimSpace(dim) == name {
+			return true
+		}
+		start = i + 1
+	}
+	return false
 }
 
 func extractNextHref(data []byte) (string, error) {
@@ -2,17 +2,21 @@ package api
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -479,6 +483,76 @@ func TestAPICommandPaginateNoNextHref(T *testing.T) {
 	assert.Equal(T, 1, requestCount, "request count (no pagination needed)")
 }
 
+func TestAPICommandPaginateStreams(T *testing.T) {
+	var locators []string
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		locators = append(locators, r.URL.Query().Get("locator"))
+		switch r.URL.Query().Get("start") {
+		case "":
+			w.Write([]byte(`{"count":2,"nextHref":"/app/rest/builds?locator=count:2,start:2&start=2","build":[{"id":1,"number":"10"},{"id":2}]}`))
+		case "2":
+			w.Write([]byte(`{"count":2,"nextHref":"/app/rest/builds?locator=count:2,start:4&start=4","build":[{"id":3},{"id":4}]}`))
+		default:
+			w.Write([]byte(`{"count":1,"build":[{"id":5}]}`))
+		}
+	})
+
+	T.Run("items print one per line", func(t *testing.T) {
+		locators = nil
+		out, _, err := runAPIWithOutput(t, "/app/rest/builds?locator=branch:main", "--paginate", "--per-page", "2", "--delay", "1ms")
+		require.NoError(t, err)
+		assert.Equal(t, "{\"id\":1,\"number\":\"10\"}\n{\"id\":2}\n{\"id\":3}\n{\"id\":4}\n{\"id\":5}\n", out)
+		assert.Equal(t, "branch:main,count:2", locators[0])
+	})
+
+	T.Run("max-pages stops early with a warning", func(t *testing.T) {
+		out, errOut, err := runAPIWithOutput(t, "/app/rest/builds", "--paginate", "--max-pages", "2")
+		require.NoError(t, err)
+		assert.Equal(t, 4, strings.Count(out, "\n"))
+		assert.Contains(t, errOut, "Stopped after 2 pages")
+	})
+
+	T.Run("flags require paginate", func(t *testing.T) {
+		_, _, err := runAPIWithOutput(t, "/app/rest/builds", "--per-page", "10")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "require --paginate")
+	})
+}
+
+func TestAPICommandPaginateRefusesOtherHost(T *testing.T) {
+	requests := 0
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"count":1,"nextHref":"https://elsewhere.example.com/app/rest/builds?start=1","build":[{"id":1}]}`))
+	})
+
+	_, _, err := runAPIWithOutput(T, "/app/rest/builds", "--paginate")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "not on the configured server")
+	assert.Equal(T, 1, requests)
+}
+
+func TestWithPageSize(T *testing.T) {
+	T.Parallel()
+	tests := []struct {
+		endpoint string
+		want     string
+		added    bool
+	}{
+		{"/app/rest/builds", "/app/rest/builds?locator=count:50", true},
+		{"/app/rest/builds?fields=build(id)", "/app/rest/builds?fields=build(id)&locator=count:50", true},
+		{"/app/rest/builds?locator=branch:main&fields=count", "/app/rest/builds?locator=branch:main,count:50&fields=count", true},
+		{"/app/rest/builds?locator=affectedProject:(id:P,count:3)", "/app/rest/builds?locator=affectedProject:(id:P,count:3),count:50", true},
+		{"/app/rest/builds?locator=state:any,count:10", "/app/rest/builds?locator=state:any,count:10", false},
+		{"/app/rest/builds?locator=count%3A10", "/app/rest/builds?locator=count%3A10", false},
+	}
+	for _, tt := range tests {
+		got, added := withPageSize(tt.endpoint, 50)
+		assert.Equal(T, tt.want, got, tt.endpoint)
+		assert.Equal(T, tt.added, added, tt.endpoint)
+	}
+}
+
 func TestAPICommandSlurp(T *testing.T) {
 	pageNum := 0
 	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
@@ -688,7 +762,7 @@ func TestMergePages(T *testing.T) {
 	}
 }
 
-func TestFetchAllPages(T *testing.T) {
+func TestFetchPages(T *testing.T) {
 	pageNum := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		pageNum++
@@ -717,10 +791,10 @@ func TestFetchAllPages(T *testing.T) {
 
 	client := api.NewClient(server.URL, "test-token")
 
-	pages, status, err := fetchAllPages(T.Context(), client, "/app/rest/builds", nil)
+	pages, status, err := collectPages(T, client, &apiOptions{maxPages: maxPaginationPages})
 	require.NoError(T, err)
-	assert.Equal(T, http.StatusOK, status, "fetchAllPages() last status on success")
-	assert.Len(T, pages, 3, "fetchAllPages() page count")
+	assert.Equal(T, http.StatusOK, status, "fetchPages() last status on success")
+	assert.Len(T, pages, 3, "fetchPages() page count")
 
 	arrayKey, _ := detectArrayKey(pages[0])
 	merged, err := mergePages(pages, arrayKey)
@@ -731,7 +805,7 @@ func TestFetchAllPages(T *testing.T) {
 	assert.Len(T, items, 5, "merged result item count")
 }
 
-func TestFetchAllPagesSinglePage(T *testing.T) {
+func TestFetchPagesSinglePage(T *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]any{"count": 2, "build": []map[string]int{{"id": 1}, {"id": 2}}})
@@ -751,14 +825,26 @@ func TestFetchAllPagesSinglePage(T *testing.T) {
 
 	client := api.NewClient(server.URL, "test-token")
 
-	pages, status, err := fetchAllPages(T.Context(), client, "/app/rest/builds", nil)
+	pages, status, err := collectPages(T, client, &apiOptions{maxPages: maxPaginationPages})
 	require.NoError(T, err)
-	assert.Equal(T, http.StatusOK, status, "fetchAllPages() last status on success")
-	assert.Len(T, pages, 1, "fetchAllPages() page count")
+	assert.Equal(T, http.StatusOK, status, "fetchPages() last status on success")
+	assert.Len(T, pages, 1, "fetchPages() page count")
 }
 
-// TestFetchAllPagesErrorsWhenCapExceeded locks the behavior change from silent truncation to an explicit error so future refactors can't regress to dropping pages without telling the caller.
-func TestFetchAllPagesErrorsWhenCapExceeded(T *testing.T) {
+// collectPages runs fetchPages over /app/rest/builds and returns the pages it handed over.
+func collectPages(t *testing.T, client api.ClientInterface, opts *apiOptions) ([][]byte, int, error) {
+	t.Helper()
+	var pages [][]byte
+	p := &output.Printer{Out: io.Discard, ErrOut: io.Discard}
+	status, err := fetchPages(t.Context(), p, client, "/app/rest/builds", nil, opts, func(page []byte) error {
+		pages = append(pages, page)
+		return nil
+	})
+	return pages, status, err
+}
+
+// TestFetchPagesErrorsWhenCapExceeded locks the behavior change from silent truncation to an explicit error so future refactors can't regress to dropping pages without telling the caller.
+func TestFetchPagesErrorsWhenCapExceeded(T *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -770,14 +856,18 @@ func TestFetchAllPagesErrorsWhenCapExceeded(T *testing.T) {
 	defer server.Close()
 
 	client := api.NewClient(server.URL, "test-token")
-	pages, status, err := fetchAllPages(T.Context(), client, "/app/rest/builds", nil)
-	require.Error(T, err, "fetchAllPages must error when nextHref still set after the page cap")
+	pages, status, err := collectPages(T, client, &apiOptions{maxPages: maxPaginationPages})
+	require.Error(T, err, "fetchPages must error when nextHref still set after the page cap")
 	assert.Contains(T, err.Error(), "page cap", "error should explain why")
 	assert.Equal(T, http.StatusOK, status, "last status reflects the last successful page")
-	assert.Empty(T, pages, "no pages should be surfaced when the result is incomplete")
+	assert.Len(T, pages, maxPaginationPages)
+
+	pages, _, err = collectPages(T, client, &apiOptions{maxPages: 3, maxPagesSet: true})
+	require.NoError(T, err, "an explicit --max-pages stops without an error")
+	assert.Len(T, pages, 3)
 }
 
-func TestFetchAllPagesPropagatesErrorStatus(T *testing.T) {
+func TestFetchPagesPropagatesErrorStatus(T *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
 		_, _ = w.Write([]byte(`{"message":"nope"}`))
@@ -785,9 +875,9 @@ func TestFetchAllPagesPropagatesErrorStatus(T *testing.T) {
 	defer server.Close()
 
 	client := api.NewClient(server.URL, "test-token")
-	pages, status, err := fetchAllPages(T.Context(), client, "/app/rest/builds", nil)
-	require.Error(T, err, "fetchAllPages() must surface non-2xx as error")
-	assert.Equal(T, http.StatusForbidden, status, "fetchAllPages() must return the failed status (not 200) so analytics records the real code")
+	pages, status, err := collectPages(T, client, &apiOptions{maxPages: maxPaginationPages})
+	require.Error(T, err, "fetchPages() must surface non-2xx as error")
+	assert.Equal(T, http.StatusForbidden, status, "fetchPages() must return the failed status (not 200) so analytics records the real code")
 	assert.Empty(T, pages, "no pages should be returned on first-page failure")
 }
 
@@ -846,3 +936,200 @@ func TestPrettyPrintJSON(T *testing.T) {
 		})
 	}
 }
+
+func runAPIWithOutput(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	f := cmdutil.NewFactory()
+	f.Printer = &output.Printer{Out: &out, ErrOut: &errOut}
+	rootCmd := createTestRootCmdWithFactory(f)
+	rootCmd.SetArgs(append([]string{"api"}, args...))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	err := rootCmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestAPICommandGzipResponseWithAcceptEncodingHeader(T *testing.T) {
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipBytes(T, `{"version":"2026.1"}`))
+	})
+
+	out, _, err := runAPIWithOutput(T, "/app/rest/server", "-H", "Accept-Encoding: gzip")
+	require.NoError(T, err)
+	assert.Contains(T, out, `"version": "2026.1"`)
+}
+
+func TestAPICommandTerminalGuards(T *testing.T) {
+	old := terminalOutput
+	terminalOutput = func(io.Writer) bool { return true }
+	T.Cleanup(func() { terminalOutput = old })
+
+	archive := gzipBytes(T, "dist contents")
+	large := `{"log":"` + strings.Repeat("x", largeBodyThreshold) + `"}`
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".tar.gz") {
+			w.Write(archive)
+			return
+		}
+		w.Write([]byte(large))
+	})
+	const artifact = "/app/rest/builds/id:1/artifacts/content/dist.tar.gz"
+
+	T.Run("compressed body is not printed", func(t *testing.T) {
+		out, _, err := runAPIWithOutput(t, artifact)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "response is gzip-compressed data")
+		assert.NotContains(t, out, string(archive))
+	})
+
+	T.Run("output writes the body to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dist.tar.gz")
+		out, _, err := runAPIWithOutput(t, artifact, "--output", path)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Saved response to "+path)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, archive, data)
+	})
+
+	T.Run("large body warns without a prompt", func(t *testing.T) {
+		out, errOut, err := runAPIWithOutput(t, "/app/rest/builds/id:1/log", "--raw")
+		require.NoError(t, err)
+		assert.Contains(t, errOut, "use --output FILE")
+		assert.Len(t, out, len(large))
+	})
+}
+
+func TestAPICommandOutputPaginateRequiresSlurp(T *testing.T) {
+	_, _, err := runAPIWithOutput(T, "/app/rest/builds", "--paginate", "--output", "builds.json")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "--output with --paginate requires --slurp")
+}
+
+func TestAPICommandIncludePaginateRequiresSlurp(T *testing.T) {
+	_, _, err := runAPIWithOutput(T, "/app/rest/builds", "--paginate", "--include")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "--include with --paginate requires --slurp")
+}
+
+func TestAPICommandJQ(T *testing.T) {
+	requests := 0
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/app/rest/server":
+			w.Write([]byte(`{"version":"2025.11","versionMajor":2025,"plugins":{"count":2},"serial":9007199254740993}`))
+		case r.URL.Path == "/app/rest/agents":
+			w.Write([]byte(`<html>not json</html>`))
+		case r.URL.Query().Get("start") == "2":
+			w.Write([]byte(`{"count":1,"build":[{"id":3,"branchName":"b"}]}`))
+		default:
+			w.Write([]byte(`{"count":2,"nextHref":"/app/rest/builds?start=2","build":[{"id":1,"branchName":"main"},{"id":2}]}`))
+		}
+	})
+
+	T.Run("strings print without quotes", func(t *testing.T) {
+		out, _, err := runAPIWithOutput(t, "/app/rest/server", "--jq", ".version")
+		require.NoError(t, err)
+		assert.Equal(t, "2025.11\n", out)
+	})
+
+	T.Run("objects print indented, or compact with --raw", func(t *testing.T) {
+		out, _, err := runAPIWithOutput(t, "/app/rest/server", "--jq", ".plugins")
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"count\": 2\n}\n", out)
+		out, _, err = runAPIWithOutput(t, "/app/rest/server", "--jq", ".plugins, .versionMajor", "--raw")
+		require.NoError(t, err)
+		assert.Equal(t, "{\"count\":2}\n2025\n", out)
+	})
+
+	T.Run("large numbers keep every digit", func(t *testing.T) {
+		out, _, err := runAPIWithOutput(t, "/app/rest/server", "--jq", ".serial")
+		require.NoError(t, err)
+		assert.Equal(t, "9007199254740993\n", out)
+		out, _, err = runAPIWithOutput(t, "/app/rest/server", "--jq", "{serial}", "--raw")
+		require.NoError(t, err)
+		assert.Equal(t, "{\"serial\":9007199254740993}\n", out)
+	})
+
+	T.Run("paginate filters the merged pages", func(t *testing.T) {
+		out, _, err := runAPIWithOutput(t, "/app/rest/builds", "--paginate", "--jq", ".[] | .id")
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n", out)
+		out, _, err = runAPIWithOutput(t, "/app/rest/builds", "--paginate", "--slurp", "--jq", `map(.branchName // "-") | join(",")`)
+		require.NoError(t, err)
+		assert.Equal(t, "main,-,b\n", out)
+	})
+
+	T.Run("response that is not JSON", func(t *testing.T) {
+		_, _, err := runAPIWithOutput(t, "/app/rest/agents", "--jq", ".")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--jq needs a JSON response")
+	})
+
+	T.Run("invalid expression fails before the request", func(t *testing.T) {
+		before := requests
+		_, _, err := runAPIWithOutput(t, "/app/rest/server", "--jq", ".[")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --jq expression")
+		assert.Equal(t, before, requests)
+	})
+}
+
+func TestAPICommandFail(T *testing.T) {
+	setupMockServerForAPI(T, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/rest/builds/id:999":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"No build found by locator 'id:999'"}]}`))
+		case "/app/rest/server":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"count":0,"build":[]}`))
+		}
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"4xx exits 4", []string{"/app/rest/builds/id:999", "--fail"}, cmdutil.ExitHTTPClientError, `run "999" not found`},
+		{"5xx exits 5", []string{"/app/rest/server", "--fail"}, cmdutil.ExitHTTPServerError, "Error: "},
+		{"paginate", []string{"/app/rest/builds/id:999", "--fail", "--paginate"}, cmdutil.ExitHTTPClientError, `run "999" not found`},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			_, errOut, err := runAPIWithOutput(t, tc.args...)
+			exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+			require.True(t, ok, "want an ExitError, got %v", err)
+			assert.Equal(t, tc.wantCode, exitErr.Code)
+			assert.Contains(t, errOut, tc.wantErr)
+		})
+	}
+
+	T.Run("success is untouched", func(t *testing.T) {
+		_, _, err := runAPIWithOutput(t, "/app/rest/builds", "--fail")
+		require.NoError(t, err)
+	})
+
+	T.Run("without --fail the error comes back as is", func(t *testing.T) {
+		_, _, err := runAPIWithOutput(t, "/app/rest/builds/id:999")
+		var nf *api.NotFoundError
+		assert.ErrorAs(t, err, &nf)
+	})
+}
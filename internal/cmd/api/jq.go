@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/itchyny/gojq"
+)
+
+// compileJQ compiles a --jq expression; it is called before any request so a broken filter fails at once.
+func compileJQ(expr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, api.Validation(fmt.Sprintf("invalid --jq expression: %v", err), "The syntax is jq's, e.g. --jq '.build[].id'")
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, api.Validation(fmt.Sprintf("invalid --jq expression: %v", err), "The syntax is jq's, e.g. --jq '.build[].id'")
+	}
+	return code, nil
+}
+
+// writeJQ runs code over the JSON body and writes each result on its own line: strings as they are, without
+// quotes, and anything else as JSON, indented unless raw.
+func writeJQ(ctx context.Context, w io.Writer, code *gojq.Code, body []byte, raw bool) error {
+	// UseNumber keeps IDs and timestamps beyond float64 precision exact; gojq takes json.Number as a number.
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var input any
+	if err := dec.Decode(&input); err != nil || dec.Decode(new(any)) != io.EOF {
+		return api.Validation("--jq needs a JSON response, but the server sent something else",
+			"Ask for JSON with -H 'Accept: application/json', or drop --jq to see the response")
+	}
+
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := v.(error); ok {
+			if halt, ok := errors.AsType[*gojq.HaltError](err); ok && halt.Value() == nil {
+				return nil
+			}
+			return fmt.Errorf("--jq: %w", err)
+		}
+		if s, ok := v.(string); ok {
+			_, _ = fmt.Fprintln(w, s)
+			continue
+		}
+		var out []byte
+		var err error
+		if raw {
+			out, err = gojq.Marshal(v)
+		} else {
+			out, err = json.MarshalIndent(v, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("--jq: %w", err)
+		}
+		_, _ = fmt.Fprintln(w, string(out))
+	}
+}
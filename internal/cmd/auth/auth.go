@@ -9,11 +9,13 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "auth",
 		Short: "Authenticate with TeamCity",
-		Long: `Log in, log out, and inspect authentication state for TeamCity servers.
+		Long: `Log in, log out, inspect authentication state for TeamCity servers,
+and manage your access tokens.
 
 Credentials are stored in the system keyring by default and can be
 overridden via TEAMCITY_URL and TEAMCITY_TOKEN environment variables
-for CI/CD usage.
+for CI/CD usage. Guest access (auth guest, or TEAMCITY_GUEST=1) needs no
+token and is always read-only.
 
 See: https://www.jetbrains.com/help/teamcity/managing-your-user-account.html#Managing+Access+Tokens`,
 		Args: cobra.NoArgs,
@@ -21,8 +23,11 @@ See: https://www.jetbrains.com/help/teamcity/managing-your-user-account.html#Man
 	}
 
 	cmd.AddCommand(newAuthLoginCmd(f))
+	cmd.AddCommand(newAuthGuestCmd(f))
 	cmd.AddCommand(newAuthLogoutCmd(f))
 	cmd.AddCommand(newAuthStatusCmd(f))
+	cmd.AddCommand(newAuthSwitchCmd(f))
+	cmd.AddCommand(newAuthTokenCmd(f))
 
 	return cmd
 }
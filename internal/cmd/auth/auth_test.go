@@ -1,6 +1,8 @@
 package auth_test
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -168,6 +171,57 @@ func TestAuthStatusEnvTokenWithoutURL(T *testing.T) {
 	assert.NotContains(T, got, "2099", "stored expiry must not be reported for an env token")
 }
 
+func TestAuthStatusClockSkew(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	T.Setenv("TEAMCITY_GUEST", "")
+	T.Setenv("TEAMCITY_BUILD_PROPERTIES_FILE", "")
+	T.Setenv("TC_INSECURE_SKIP_WARN", "1")
+	T.Setenv("TEAMCITY_URL", ts.URL)
+	T.Setenv("TEAMCITY_TOKEN", "env-token")
+	config.ResetForTest()
+
+	// The server's clock runs 10 minutes behind the local one.
+	skewed := func(w http.ResponseWriter) {
+		w.Header().Set("Date", time.Now().Add(-10*time.Minute).UTC().Format(http.TimeFormat))
+	}
+	ts.Handle("GET /app/rest/users/current", func(w http.ResponseWriter, r *http.Request) {
+		skewed(w)
+		cmdtest.JSON(w, api.User{ID: 1, Username: "admin", Name: "Administrator"})
+	})
+	ts.Handle("GET /app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		skewed(w)
+		cmdtest.JSON(w, api.Server{VersionMajor: 2025, VersionMinor: 7, BuildNumber: "197398"})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--json")
+	assert.Regexp(T, `"clock_skew_seconds": -(599|600|601)`, got)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status")
+	assert.Regexp(T, `Local clock is 10m [0-9]+s ahead of the server`, got)
+}
+
+func TestAuthStatusTLS(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	T.Setenv("TEAMCITY_GUEST", "")
+	T.Setenv("TEAMCITY_BUILD_PROPERTIES_FILE", "")
+	T.Setenv("TC_INSECURE_SKIP_WARN", "1")
+	T.Setenv("TEAMCITY_URL", ts.URL)
+	T.Setenv("TEAMCITY_TOKEN", "env-token")
+	T.Setenv(config.EnvCACert, "")
+	T.Setenv(config.EnvInsecureSkipVerify, "")
+	config.ResetForTest()
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--json")
+	assert.NotContains(T, got, `"tls"`)
+
+	T.Setenv(config.EnvInsecureSkipVerify, "1")
+	got = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--json")
+	assert.Contains(T, got, `"insecure_skip_verify": true`)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status")
+	assert.Contains(T, got, "TLS certificate verification is off")
+}
+
 func TestIsBuildEnvironment(T *testing.T) {
 	T.Setenv("TEAMCITY_BUILD_PROPERTIES_FILE", "/some/path")
 	assert.True(T, config.IsBuildEnvironment())
@@ -354,3 +408,114 @@ func TestAuthStatusWithDSLHint(T *testing.T) {
 	// should still succeed (shows authenticated server + hint)
 	cmdtest.RunCmd(T, "auth", "status")
 }
+
+func TestAuthStatusAll(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	setupConfigAuthStatus(T, ts)
+	T.Setenv("TEAMCITY_URL", ts.URL)
+	T.Setenv("TEAMCITY_TOKEN", "env-token")
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/rest/server/version" {
+			_, _ = w.Write([]byte("2025.07"))
+			return
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	T.Cleanup(rejecting.Close)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	cfg := config.Get()
+	cfg.DefaultServer = ts.URL
+	cfg.Servers[ts.URL] = config.ServerConfig{Token: "token-1", User: "admin", Context: "prod"}
+	cfg.Servers[rejecting.URL] = config.ServerConfig{Token: "stale", User: "ci", RO: true}
+	cfg.Servers[down.URL] = config.ServerConfig{Token: "token-3", User: "me", Context: "local"}
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--all")
+	for _, want := range []string{"SERVER", "CONTEXT", "REACHABLE", "READ-ONLY", ts.URL + " (active)", "prod", "admin", "2025.7", "Token is invalid or expired", "connection refused", "local"} {
+		assert.Contains(T, out, want)
+	}
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--all", "--json")
+	var results []map[string]any
+	require.NoError(T, json.Unmarshal([]byte(out), &results))
+	require.Len(T, results, 3, "every configured server is checked, not the TEAMCITY_URL override")
+	byServer := map[string]map[string]any{}
+	for _, r := range results {
+		byServer[r["server"].(string)] = r
+	}
+	assert.Equal(T, "authenticated", byServer[ts.URL]["status"])
+	assert.Equal(T, "config", byServer[ts.URL]["token_source"])
+	assert.Equal(T, "prod", byServer[ts.URL]["context"])
+	assert.Equal(T, true, byServer[rejecting.URL]["reachable"])
+	assert.Equal(T, true, byServer[rejecting.URL]["read_only"])
+	assert.Equal(T, "error", byServer[rejecting.URL]["status"])
+	assert.Equal(T, false, byServer[down.URL]["reachable"])
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "auth", "status", "--all", "--strict")
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	require.True(T, ok, "got %v", err)
+	assert.Equal(T, cmdutil.ExitFailure, exitErr.Code)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--strict requires --all", "auth", "status", "--strict")
+}
+
+func TestAuthStatusAllTimeout(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	setupConfigAuthStatus(T, ts)
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	T.Cleanup(slow.Close)
+	T.Cleanup(func() { close(release) })
+
+	cfg := config.Get()
+	cfg.DefaultServer = ts.URL
+	cfg.Servers[ts.URL] = config.ServerConfig{Token: "token-1", User: "admin"}
+	cfg.Servers[slow.URL] = config.ServerConfig{Token: "token-2", User: "admin"}
+
+	start := time.Now()
+	out := cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--all", "--timeout", "200ms")
+	assert.Less(T, time.Since(start), 5*time.Second, "the global --timeout bounds the sweep")
+	assert.Contains(T, out, "connection timed out")
+	assert.Contains(T, out, "authenticated", "the sweep goes on past a failing server")
+}
+
+func TestAuthSwitch(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	setupConfigAuthStatus(T, ts)
+	config.SetConfigPathForTest(filepath.Join(T.TempDir(), "config.yml"))
+
+	other := ts.URL + "/other"
+	ts.Handle("GET /other/app/rest/users/current", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.User{ID: 2, Username: "jdoe", Name: "Jane Doe"})
+	})
+	ts.Handle("GET /other/app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Server{VersionMajor: 2024, VersionMinor: 12, BuildNumber: "176523"})
+	})
+	cfg := config.Get()
+	cfg.DefaultServer = ts.URL
+	cfg.Servers[ts.URL] = config.ServerConfig{Token: "token-1", User: "admin"}
+	cfg.Servers[other] = config.ServerConfig{Token: "token-2", User: "jdoe", Context: "staging"}
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "auth", "switch", other+"/")
+	assert.Contains(T, out, "Switched to "+other)
+	assert.Contains(T, out, "Acting as Jane Doe (jdoe)")
+	assert.Equal(T, other, config.Get().DefaultServer)
+
+	cmdtest.CaptureOutput(T, ts.Factory, "auth", "switch", ts.URL)
+	assert.Equal(T, ts.URL, config.Get().DefaultServer)
+
+	// --server picks a server for one command, by context label, without changing the default.
+	out = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--server", "staging")
+	assert.Contains(T, out, other+" (active)")
+	assert.NotContains(T, out, ts.URL+" (active)")
+	assert.Equal(T, ts.URL, config.Get().DefaultServer)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `no configured server has the URL or context label "prod"`, "auth", "switch", "prod")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `no configured server has the URL or context label "prod"`, "auth", "status", "--server", "prod")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "https://tc.example.com is not a configured server", "auth", "switch", "tc.example.com")
+}
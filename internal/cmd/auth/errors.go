@@ -21,6 +21,9 @@ func friendlyError(err error, serverURL string) string {
 		return err.Error()
 	}
 	s := err.Error()
+	if i := strings.Index(s, "CA certificate file"); i >= 0 {
+		return s[i:]
+	}
 	switch {
 	case strings.Contains(s, "no such host"):
 		return "DNS lookup failed - check the hostname"
@@ -29,7 +32,7 @@ func friendlyError(err error, serverURL string) string {
 	case strings.Contains(s, "i/o timeout"):
 		return "connection timed out"
 	case strings.Contains(s, "x509"), strings.Contains(s, "certificate"):
-		return "TLS certificate error - check the URL scheme and hostname"
+		return "TLS certificate error - check the URL scheme and hostname, or trust the server's CA with ca_cert"
 	case strings.Contains(s, "401"), strings.Contains(strings.ToLower(s), "unauthorized"):
 		return "token rejected - the token is invalid, expired, or lacks required permissions"
 	}
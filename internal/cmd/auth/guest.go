@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/JetBrains/teamcity-cli/internal/analytics"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newAuthGuestCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "guest [server-url]",
+		Short: "Use guest access to a TeamCity server",
+		Long: `Configure a TeamCity server for guest access and make it the default.
+
+Guest access needs no token: requests go through the server's /guestAuth/
+endpoints, so guest login must be enabled on the server. A guest server is
+always read-only; commands that would change something fail with
+"guest access is read-only".
+
+For CI/CD, set TEAMCITY_URL and TEAMCITY_GUEST=1 instead.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  teamcity auth guest https://teamcity.example.com
+  TEAMCITY_URL=https://teamcity.example.com TEAMCITY_GUEST=1 teamcity run list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var serverURL string
+			if len(args) > 0 {
+				serverURL = args[0]
+			}
+			return runAuthGuest(f, serverURL)
+		},
+	}
+
+	return cmd
+}
+
+func runAuthGuest(f *cmdutil.Factory, serverURL string) (err error) {
+	failedStep := analytics.AuthStepServer
+	defer func() { trackLoginOutcome(f, analytics.AuthMethodGuest, failedStep, err) }()
+
+	p := f.Printer
+	ctx := f.Context()
+	interactive := f.IsInteractive()
+
+	if interactive && serverURL == "" {
+		p.Tip(output.TipCancelAnytime)
+		_, _ = fmt.Fprintln(p.Out)
+	}
+
+	serverURL, err = resolveServerURL(ctx, p, serverURL, interactive)
+	if err != nil {
+		return err
+	}
+	f.WarnInsecureHTTP(serverURL, "guest access")
+
+	failedStep = analytics.AuthStepVerify
+	return finishGuestLogin(ctx, f, serverURL)
+}
@@ -0,0 +1,118 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guestServer serves the mock server's API under /guestAuth/ and fails any request that carries credentials.
+// It returns the server and the number of requests made through /guestAuth/ so far.
+func guestServer(t *testing.T, ts *cmdtest.TestServer) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var guestRequests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("guest request %s %s sent credentials", r.Method, r.URL.Path)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if rest, ok := strings.CutPrefix(r.URL.Path, "/guestAuth"); ok {
+			guestRequests.Add(1)
+			r.URL.Path = rest
+		}
+		ts.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &guestRequests
+}
+
+// guestFactory points the CLI at srv with TEAMCITY_GUEST=1 and returns a Factory that resolves its real client.
+func guestFactory(t *testing.T, serverURL string) *cmdutil.Factory {
+	t.Helper()
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	t.Setenv("TEAMCITY_URL", serverURL)
+	t.Setenv("TEAMCITY_TOKEN", "")
+	t.Setenv("TEAMCITY_GUEST", "1")
+	config.ResetForTest()
+	require.NoError(t, config.Init())
+
+	f := cmdutil.NewFactory()
+	f.SkipLinkLookup()
+	return f
+}
+
+func TestGuestReadPathSweep(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	srv, guestRequests := guestServer(T, ts)
+	f := guestFactory(T, srv.URL)
+
+	for _, args := range [][]string{
+		{"project", "list"},
+		{"project", "view", "TestProject"},
+		{"job", "list"},
+		{"job", "view", "TestProject_Build"},
+		{"run", "list"},
+		{"run", "view", "1"},
+	} {
+		T.Run(strings.Join(args, " "), func(t *testing.T) {
+			before := guestRequests.Load()
+			cmdtest.RunCmdWithFactory(t, f, args...)
+			assert.Greater(t, guestRequests.Load(), before, "expected requests through /guestAuth/")
+		})
+	}
+}
+
+func TestGuestWritesFailFast(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var writes atomic.Int32
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		cmdtest.JSON(w, api.Build{ID: 1})
+	})
+	srv, _ := guestServer(T, ts)
+	f := guestFactory(T, srv.URL)
+
+	err := cmdtest.CaptureErr(T, f, "run", "start", "TestProject_Build")
+	require.ErrorIs(T, err, api.ErrReadOnly)
+	assert.Contains(T, err.Error(), "guest access is read-only")
+	assert.Zero(T, writes.Load(), "the write must not reach the server")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, f, "guest access cannot manage access tokens", "auth", "token", "list")
+}
+
+func TestAuthGuest(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	srv, _ := guestServer(T, ts)
+	config.SetConfigPathForTest(filepath.Join(T.TempDir(), "config.yml"))
+	T.Setenv("TEAMCITY_URL", "")
+	T.Setenv("TEAMCITY_TOKEN", "")
+	T.Setenv("TEAMCITY_GUEST", "")
+	config.ResetForTest()
+	require.NoError(T, config.Init())
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "auth", "guest", srv.URL)
+	assert.Contains(T, out, "Guest access to "+srv.URL)
+	assert.Contains(T, out, "Guest, read-only")
+
+	assert.Equal(T, srv.URL, config.Get().DefaultServer)
+	assert.True(T, config.Get().Servers[srv.URL].Guest)
+	assert.True(T, config.IsGuestAuth())
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "auth", "status", "--json")
+	assert.Contains(T, out, `"auth_method": "guest"`)
+	assert.Contains(T, out, `"read_only": true`)
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "auth", "logout", "--server", srv.URL)
+	assert.NotContains(T, config.Get().Servers, srv.URL)
+}
@@ -25,6 +25,9 @@ type loginOpts struct {
 	guest           bool
 	insecureStorage bool
 	noBrowser       bool
+	username        string
+	passwordStdin   bool
+	expires         string
 }
 
 func newAuthLoginCmd(f *cmdutil.Factory) *cobra.Command {
@@ -39,6 +42,11 @@ The token is stored in the system keyring (macOS Keychain, GNOME Keyring,
 Windows Credential Manager). Use --insecure-storage to fall back to plain
 text in the config file.
 
+With --username, signs in with your password instead and creates an access
+token named tc-cli-<hostname> that expires after --expires (90 days by
+default). Only the token is stored, never the password. If the server does
+not allow creating tokens, you are asked to paste one instead.
+
 For CI/CD, set TEAMCITY_URL and TEAMCITY_TOKEN environment variables
 (or TEAMCITY_URL + TEAMCITY_GUEST=1 for guest access).`,
 		Example: `  # Interactive login with auto-discovered browser-based auth
@@ -47,6 +55,10 @@ For CI/CD, set TEAMCITY_URL and TEAMCITY_TOKEN environment variables
   # Skip browser-based auth, enter a token manually
   teamcity auth login -s https://teamcity.example.com --no-browser
 
+  # Sign in with a password and let the CLI create the token
+  teamcity auth login -s https://teamcity.example.com --username jdoe
+  echo "$TC_PASSWORD" | teamcity auth login -s https://teamcity.example.com --username jdoe --password-stdin --expires 30d
+
   # Guest access (read-only, if enabled on the server)
   teamcity auth login -s https://teamcity.example.com --guest`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -59,6 +71,11 @@ For CI/CD, set TEAMCITY_URL and TEAMCITY_TOKEN environment variables
 	cmd.Flags().BoolVar(&opts.guest, "guest", false, "Use guest authentication (no token needed; must be enabled on the server)")
 	cmd.Flags().BoolVar(&opts.insecureStorage, "insecure-storage", false, "Store token in plain text config file instead of system keyring")
 	cmd.Flags().BoolVar(&opts.noBrowser, "no-browser", false, "Skip browser-based auth, use manual token entry")
+	cmd.Flags().StringVarP(&opts.username, "username", "u", "", "Sign in with this user's password and create an access token")
+	cmd.Flags().BoolVar(&opts.passwordStdin, "password-stdin", false, "Read the password for --username from stdin")
+	cmd.Flags().StringVar(&opts.expires, "expires", defaultPasswordTokenExpiry, "With --username, lifetime or expiry date of the created token, e.g. 90d or 2026-12-31")
+	cmd.MarkFlagsMutuallyExclusive("username", "token")
+	cmd.MarkFlagsMutuallyExclusive("username", "guest")
 
 	_ = cmd.RegisterFlagCompletionFunc("server", completion.ConfiguredServers())
 
@@ -79,6 +96,9 @@ func runAuthLogin(f *cmdutil.Factory, opts *loginOpts) (err error) {
 			"Use either --guest for guest access or --token for token authentication",
 		)
 	}
+	if opts.passwordStdin && opts.username == "" {
+		return api.Validation("--password-stdin requires --username", "Pass --username <user>")
+	}
 
 	p := f.Printer
 	ctx := f.Context()
@@ -98,7 +118,10 @@ func runAuthLogin(f *cmdutil.Factory, opts *loginOpts) (err error) {
 	if opts.guest {
 		reason = "guest access"
 	}
-	f.WarnInsecureHTTP(serverURL, reason)
+	// A password login warns about the credentials when it signs in.
+	if opts.username == "" {
+		f.WarnInsecureHTTP(serverURL, reason)
+	}
 
 	if opts.guest {
 		failedStep = analytics.AuthStepVerify
@@ -150,7 +173,7 @@ func resolveServerURL(ctx context.Context, p *output.Printer, initial string, in
 		serverURL = config.NormalizeURL(serverURL)
 
 		p.Progress("Checking %s... ", output.Cyan(serverURL))
-		probeClient := api.NewGuestClient(serverURL, api.WithVersion(version.String()))
+		probeClient := api.NewGuestClient(serverURL, api.WithTLS(config.ServerTLS(serverURL)), api.WithVersion(version.String()))
 		if err := probeClient.Probe(ctx); err != nil {
 			p.Info("%s", output.Red(output.Sym().Cross))
 			if errors.Is(err, context.Canceled) {
@@ -174,6 +197,7 @@ func finishGuestLogin(ctx context.Context, f *cmdutil.Factory, serverURL string)
 	p.Progress("Validating guest access... ")
 
 	client := api.NewGuestClient(serverURL,
+		f.TLSOption(serverURL),
 		api.WithDebugFunc(p.Debug),
 		api.WithVersion(version.String()),
 	).WithContext(ctx)
@@ -196,14 +220,26 @@ func finishGuestLogin(ctx context.Context, f *cmdutil.Factory, serverURL string)
 	p.Success("Guest access to %s", output.Cyan(serverURL))
 	_, _ = fmt.Fprintf(p.Out, "  Server: TeamCity %d.%d (build %s)\n",
 		server.VersionMajor, server.VersionMinor, server.BuildNumber)
+	_, _ = fmt.Fprintf(p.Out, "  Auth: %s\n", output.Faint("Guest, read-only"))
 	return nil
 }
 
 func finishTokenLogin(ctx context.Context, f *cmdutil.Factory, serverURL string, opts *loginOpts, interactive bool) error {
 	p := f.Printer
 	token := opts.token
-	var tokenValidUntil string
-	pkceTried := token == "" && !opts.noBrowser && interactive
+	var tokenName, tokenValidUntil string
+	if opts.username != "" {
+		var err error
+		token, tokenName, tokenValidUntil, err = passwordLogin(ctx, f, serverURL, opts, interactive)
+		if err != nil {
+			if _, ok := errors.AsType[*tokenCreationError](err); !ok || !interactive {
+				return err
+			}
+			p.Warn("Signed in, but could not create an access token: %v", err)
+			p.Info("Create one in the TeamCity UI and paste it instead")
+		}
+	}
+	pkceTried := token == "" && opts.username == "" && !opts.noBrowser && interactive
 	if pkceTried {
 		token, tokenValidUntil = attemptPkceLogin(ctx, p, serverURL)
 	}
@@ -213,12 +249,13 @@ func finishTokenLogin(ctx context.Context, f *cmdutil.Factory, serverURL string,
 		return err
 	}
 
-	insecureFallback, err := config.SetServerWithKeyring(serverURL, token, user.Username, tokenValidUntil, opts.insecureStorage)
+	insecureFallback, err := config.SetServerWithKeyring(serverURL, token, user.Username, tokenName, tokenValidUntil, opts.insecureStorage)
 	if err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
 	cacheServerInfo(serverURL, api.NewClient(serverURL, token,
+		f.TLSOption(serverURL),
 		api.WithDebugFunc(p.Debug),
 		api.WithVersion(version.String()),
 	).WithContext(ctx))
@@ -263,6 +300,7 @@ func resolveToken(ctx context.Context, p *output.Printer, serverURL, initial str
 
 		p.Progress("Validating... ")
 		client := api.NewClient(serverURL, token,
+			api.WithTLS(config.ServerTLS(serverURL)),
 			api.WithDebugFunc(p.Debug),
 			api.WithVersion(version.String()),
 		).WithContext(ctx)
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+// defaultPasswordTokenExpiry is the lifetime of the tokens created from a password when --expires is not set.
+const defaultPasswordTokenExpiry = "90d"
+
+// readPasswordStdin reads a password from the first line of stdin.
+func readPasswordStdin(f *cmdutil.Factory) (string, error) {
+	password, err := bufio.NewReader(f.IOStreams.In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return "", api.Validation("no password on stdin", "Pipe the password for --username into the command")
+	}
+	return password, nil
+}
+
+// tokenCreationError is a password login that signed in but could not create a token, so a pasted token is the
+// only way left.
+type tokenCreationError struct{ err error }
+
+func (e *tokenCreationError) Error() string { return e.err.Error() }
+func (e *tokenCreationError) Unwrap() error { return e.err }
+
+// passwordLogin authenticates as opts.username and creates an access token for this machine, returning the token, its
+// name, and its expiry in RFC 3339. The password is used for these calls only and never stored.
+func passwordLogin(ctx context.Context, f *cmdutil.Factory, serverURL string, opts *loginOpts, interactive bool) (token, name, validUntil string, err error) {
+	p := f.Printer
+	expiry, err := api.ParseUserExpiry(opts.expires)
+	if err != nil {
+		return "", "", "", api.Validation(err.Error(), "Use a lifetime like 90d or a date like 2026-12-31")
+	}
+
+	var password string
+	if opts.passwordStdin {
+		if password, err = readPasswordStdin(f); err != nil {
+			return "", "", "", err
+		}
+	} else {
+		if !interactive {
+			return "", "", "", api.Validation("--username requires --password-stdin when not running interactively", "Pipe the password in, e.g. 'echo \"$PASSWORD\" | teamcity auth login -s <url> --username <user> --password-stdin'")
+		}
+		if err := cmdutil.PromptSecret("Password for "+opts.username, &password); err != nil {
+			return "", "", "", err
+		}
+	}
+
+	client := f.BasicAuthClient(serverURL, opts.username, password)
+	p.Progress("Signing in as %s... ", opts.username)
+	if _, err := client.GetCurrentUser(); err != nil {
+		p.Info("%s", output.Red(output.Sym().Cross))
+		if errors.Is(err, context.Canceled) {
+			return "", "", "", err
+		}
+		return "", "", "", api.Validation("could not sign in as "+opts.username+": "+friendlyError(err, serverURL), "Check the username and password, or log in with a token instead")
+	}
+	p.Info("%s", output.Green(output.Sym().Check))
+
+	req := api.Token{Name: cliTokenName(), ExpirationTime: expiry}
+	if tokens, err := client.ListAccessTokens(ctx); err == nil {
+		req.Name = freeTokenName(tokens.Token, req.Name)
+	}
+	created, err := client.CreateAccessToken(ctx, req)
+	if err != nil {
+		return "", "", "", &tokenCreationError{tokenError(err, serverURL)}
+	}
+	p.Info("Created access token %s", output.Cyan(created.Name))
+	return created.Value, created.Name, tokenExpiry(created.ExpirationTime), nil
+}
+
+// cliTokenName is the name of the tokens the CLI creates for this machine: tc-cli-<hostname>.
+func cliTokenName() string {
+	host, err := os.Hostname()
+	host, _, _ = strings.Cut(host, ".")
+	host = strings.Trim(regexp.MustCompile(`[^A-Za-z0-9_-]+`).ReplaceAllString(host, "-"), "-")
+	if err != nil || host == "" {
+		return "tc-cli"
+	}
+	return "tc-cli-" + strings.ToLower(host)
+}
+
+var tokenNameSuffix = regexp.MustCompile(`-\d+$`)
+
+// freeTokenName returns name, or name with the lowest -N suffix no token in tokens has yet. A -N suffix already on
+// name is dropped first, so rotating tc-cli-host-2 gives tc-cli-host back once that is free.
+func freeTokenName(tokens []api.Token, name string) string {
+	base := tokenNameSuffix.ReplaceAllString(name, "")
+	taken := func(n string) bool {
+		return slices.ContainsFunc(tokens, func(t api.Token) bool { return t.Name == n })
+	}
+	if !taken(base) {
+		return base
+	}
+	for i := 2; ; i++ {
+		if n := base + "-" + strconv.Itoa(i); !taken(n) {
+			return n
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/browserflow"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/JetBrains/teamcity-cli/internal/version"
 	"github.com/charmbracelet/huh"
@@ -23,7 +24,7 @@ const authCodeLifetime = 5 * time.Minute
 
 // attemptPkceLogin probes for PKCE support, lets the user pick which scopes to grant, then runs the browser flow.
 func attemptPkceLogin(ctx context.Context, p *output.Printer, serverURL string) (token, validUntil string) {
-	client := api.NewGuestClient(serverURL, api.WithVersion(version.String()))
+	client := api.NewGuestClient(serverURL, api.WithTLS(config.ServerTLS(serverURL)), api.WithVersion(version.String()))
 	pctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	enabled, _ := client.IsPkceEnabled(pctx)
 	cancel()
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -12,25 +13,44 @@ import (
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
 	"github.com/JetBrains/teamcity-cli/internal/version"
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 )
 
+// authSweepWorkers bounds how many servers auth status checks at once.
+const authSweepWorkers = 8
+
+// authServerTimeout bounds each server's checks in auth status --all; the global --timeout bounds the sweep.
+const authServerTimeout = 10 * time.Second
+
 type authStatusOptions struct {
-	json bool
+	json   bool
+	all    bool
+	strict bool
 }
 
 type authStatus struct {
 	Server      string      `json:"server"`
 	AuthMethod  string      `json:"auth_method"`
 	TokenSource string      `json:"token_source,omitempty"`
+	Context     string      `json:"context,omitempty"`
+	ReadOnly    bool        `json:"read_only,omitempty"`
 	User        *authUser   `json:"user,omitempty"`
 	ServerInfo  *serverInfo `json:"server_info,omitempty"`
 	TokenExpiry string      `json:"token_expiry,omitempty"`
 	Status      string      `json:"status"`
 	Error       string      `json:"error,omitempty"`
 	IsDefault   bool        `json:"is_default,omitempty"`
+	// Active marks the server commands use now: the default, unless --server or TEAMCITY_URL picks another.
+	Active bool `json:"active,omitempty"`
+	// Reachable is whether the server answered at all; it is unset when there were no credentials to check.
+	Reachable *bool `json:"reachable,omitempty"`
+	// ClockSkewSeconds is the server's clock minus the local one, from the Date header of its responses.
+	ClockSkewSeconds *int64 `json:"clock_skew_seconds,omitempty"`
+	// TLS is how the server's certificate is verified, when it isn't the platform's usual way.
+	TLS *tlsStatus `json:"tls,omitempty"`
 
 	versionCheckErr string
 	keyringErr      error
@@ -43,6 +63,20 @@ type authUser struct {
 	Name     string `json:"name"`
 }
 
+// tlsStatus reports the ca_cert and insecure_skip_verify settings in effect for a server.
+type tlsStatus struct {
+	CACert             string `json:"ca_cert,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+func tlsStatusFor(serverURL string) *tlsStatus {
+	o := config.ServerTLS(serverURL)
+	if o.IsZero() {
+		return nil
+	}
+	return &tlsStatus{CACert: o.CACertFile, InsecureSkipVerify: o.InsecureSkipVerify}
+}
+
 type serverInfo struct {
 	VersionMajor int    `json:"version_major"`
 	VersionMinor int    `json:"version_minor"`
@@ -55,19 +89,42 @@ func newAuthStatusCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show authentication status",
+		Long: `Show which TeamCity servers you are logged in to and whether their credentials work.
+
+Credentials from TEAMCITY_URL and TEAMCITY_TOKEN, or from a build's
+properties file, are shown instead of the configured servers when set.
+
+--all checks every server in the configuration, ignoring those overrides,
+and prints one table row per server: its context label, user, where the
+token comes from, whether the server answered, its version, and whether
+it is read-only. Servers are checked a few at a time, each within 10s,
+and the global --timeout bounds the whole sweep; a failing server is
+reported in its row and the sweep goes on.
+With --strict, the command exits with status 1 when any server is
+unreachable or its token is missing or invalid.`,
 		Example: `  teamcity auth status
-  teamcity auth status --json`,
+  teamcity auth status --json
+  teamcity auth status --all
+  teamcity auth status --all --strict --timeout 5s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.all && cmd.Flags().Changed("strict") {
+				return api.Validation("--strict requires --all", "Add --all to check every configured server")
+			}
 			return runAuthStatus(f, opts)
 		},
 	}
 
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Check every configured server and print a table")
+	cmd.Flags().BoolVar(&opts.strict, "strict", false, "With --all, exit with status 1 if any server is unreachable or has a missing or invalid token")
 
 	return cmd
 }
 
 func runAuthStatus(f *cmdutil.Factory, opts *authStatusOptions) error {
+	if opts.all {
+		return runAuthStatusAll(f, opts)
+	}
 	results := collectAuthStatuses(f)
 	if opts.json {
 		if len(results) == 0 {
@@ -78,21 +135,106 @@ func runAuthStatus(f *cmdutil.Factory, opts *authStatusOptions) error {
 	return renderAuthStatusHuman(f, results)
 }
 
+// runAuthStatusAll checks every configured server, ignoring environment and build credentials, and prints a table.
+func runAuthStatusAll(f *cmdutil.Factory, opts *authStatusOptions) error {
+	p := f.Printer
+	results := collectConfiguredStatuses(f, authServerTimeout)
+
+	if opts.json {
+		if err := p.PrintJSON(results); err != nil {
+			return err
+		}
+	} else if len(results) == 0 {
+		p.Empty("No TeamCity servers configured", "Run 'teamcity auth login' to add one")
+	} else {
+		renderAuthStatusTable(p, results)
+	}
+
+	failed := 0
+	for _, s := range results {
+		if s.Status == "error" {
+			failed++
+		}
+	}
+	if failed > 0 && opts.strict {
+		return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+	}
+	return nil
+}
+
+func renderAuthStatusTable(p *output.Printer, results []authStatus) {
+	headers := []string{"SERVER", "CONTEXT", "USER", "TOKEN", "REACHABLE", "VERSION", "READ-ONLY", "STATUS"}
+	rows := make([][]string, 0, len(results))
+	for _, s := range results {
+		server := s.Server
+		if s.Active {
+			server += " " + output.Faint("(active)")
+		}
+		user, token := "-", s.TokenSource
+		switch {
+		case s.User != nil:
+			user = s.User.Username
+		case s.AuthMethod == "guest":
+			user, token = "guest", "-"
+		case s.configUser != "":
+			user = s.configUser
+		}
+		if token == "" {
+			token = output.Red("missing")
+		}
+		reachable := "-"
+		if s.Reachable != nil {
+			reachable = output.Green("yes")
+			if !*s.Reachable {
+				reachable = output.Red("no")
+			}
+		}
+		serverVersion := "-"
+		if s.ServerInfo != nil {
+			serverVersion = fmt.Sprintf("%d.%d", s.ServerInfo.VersionMajor, s.ServerInfo.VersionMinor)
+		}
+		readOnly := "no"
+		if s.ReadOnly {
+			readOnly = "yes"
+		}
+		status := output.Green(output.Sym().Check) + " " + s.Status
+		if s.Status == "error" {
+			status = output.Red(output.Sym().Cross + " " + s.Error)
+		}
+		rows = append(rows, []string{server, cmp.Or(s.Context, "-"), user, token, reachable, serverVersion, readOnly, status})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 7)
+	p.PrintTable(headers, rows)
+}
+
 func collectAuthStatuses(f *cmdutil.Factory) []authStatus {
-	if envURL := os.Getenv(config.EnvServerURL); envURL != "" {
-		envURL = config.NormalizeURL(envURL)
+	if envURL := config.NormalizeURL(os.Getenv(config.EnvServerURL)); envURL != "" && envURL == config.GetServerURL() {
 		if config.IsGuestAuth() {
-			return []authStatus{collectGuestStatus(f, envURL, false)}
+			return []authStatus{collectGuestStatus(f.Context(), f, envURL, false)}
 		}
 		if envToken := os.Getenv(config.EnvToken); envToken != "" {
-			return []authStatus{collectTokenStatus(f, envURL, envToken, "env", false)}
+			return []authStatus{collectTokenStatus(f.Context(), f, envURL, envToken, "env", false)}
 		}
 	}
 
 	// TEAMCITY_TOKEN takes precedence over stored credentials even without TEAMCITY_URL, so report it against the resolved server (matching defaultGetClient).
 	if envToken := os.Getenv(config.EnvToken); envToken != "" && !config.IsGuestAuth() {
 		if serverURL := config.GetServerURL(); serverURL != "" {
-			return []authStatus{collectTokenStatus(f, serverURL, envToken, "env", false)}
+			// A server picked with --server keeps its stored token; see config.GetTokenWithSource.
+			if _, source, _ := config.GetTokenWithSource(); source == "env" {
+				return []authStatus{collectTokenStatus(f.Context(), f, serverURL, envToken, "env", false)}
+			}
+		}
+	}
+
+	// So does TEAMCITY_TOKEN_COMMAND, unless TEAMCITY_TOKEN is set too.
+	if envCommand := os.Getenv(config.EnvTokenCommand); envCommand != "" && config.TokenCommand() == envCommand && !config.IsGuestAuth() {
+		if serverURL := config.GetServerURL(); serverURL != "" {
+			token, _, err := config.GetTokenWithSource()
+			if err != nil {
+				return []authStatus{{Server: serverURL, AuthMethod: "token", Status: "error", Error: err.Error()}}
+			}
+			return []authStatus{collectTokenStatus(f.Context(), f, serverURL, token, "command", false)}
 		}
 	}
 
@@ -100,15 +242,34 @@ func collectAuthStatuses(f *cmdutil.Factory) []authStatus {
 		return []authStatus{collectBuildStatus(f, buildAuth)}
 	}
 
+	return collectConfiguredStatuses(f, 0)
+}
+
+// collectConfiguredStatuses checks every configured server, at most authSweepWorkers at a time. A positive timeout
+// bounds each server's checks.
+func collectConfiguredStatuses(f *cmdutil.Factory, timeout time.Duration) []authStatus {
 	cfg := config.Get()
 	urls := config.SortedServerURLs(cfg)
+	active := config.GetServerURL()
 	results := make([]authStatus, len(urls))
+	sem := make(chan struct{}, authSweepWorkers)
 	var wg sync.WaitGroup
 	for i, serverURL := range urls {
 		sc := cfg.Servers[serverURL]
 		isDefault := len(urls) > 1 && serverURL == cfg.DefaultServer
 		wg.Go(func() {
-			results[i] = collectServerStatus(f, serverURL, sc, isDefault)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			ctx := f.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			results[i] = collectServerStatus(ctx, f, serverURL, sc, isDefault)
+			results[i].Context = sc.Context
+			results[i].Active = len(urls) > 1 && serverURL == active
+			results[i].configUser = sc.User
 		})
 	}
 	wg.Wait()
@@ -116,13 +277,16 @@ func collectAuthStatuses(f *cmdutil.Factory) []authStatus {
 }
 
 // collectServerStatus fetches the status for a single configured server (guest, token, or missing).
-func collectServerStatus(f *cmdutil.Factory, serverURL string, sc config.ServerConfig, isDefault bool) authStatus {
+func collectServerStatus(ctx context.Context, f *cmdutil.Factory, serverURL string, sc config.ServerConfig, isDefault bool) authStatus {
 	if sc.Guest {
-		return collectGuestStatus(f, serverURL, isDefault)
+		return collectGuestStatus(ctx, f, serverURL, isDefault)
 	}
 	token, src, krErr := config.GetTokenForServer(serverURL)
 	if token != "" {
-		return collectTokenStatus(f, serverURL, token, src, isDefault)
+		return collectTokenStatus(ctx, f, serverURL, token, src, isDefault)
+	}
+	if cmdErr, ok := errors.AsType[*config.TokenCommandError](krErr); ok {
+		return authStatus{Server: serverURL, AuthMethod: "token", Status: "error", Error: cmdErr.Error(), IsDefault: isDefault}
 	}
 	return authStatus{
 		Server:     serverURL,
@@ -134,12 +298,21 @@ func collectServerStatus(f *cmdutil.Factory, serverURL string, sc config.ServerC
 	}
 }
 
-func collectGuestStatus(f *cmdutil.Factory, serverURL string, isDefault bool) authStatus {
-	s := authStatus{Server: serverURL, AuthMethod: "guest", IsDefault: isDefault}
-	client := api.NewGuestClient(serverURL, api.WithDebugFunc(f.Printer.Debug), api.WithVersion(version.String())).WithContext(f.Context())
-	if err := client.Probe(f.Context()); err != nil {
+// probe records in s whether serverURL answers, and the error when it does not.
+func probe(ctx context.Context, client *api.Client, s *authStatus) bool {
+	err := client.Probe(ctx)
+	s.Reachable = new(err == nil)
+	if err != nil {
 		s.Status = "error"
-		s.Error = friendlyError(err, serverURL)
+		s.Error = friendlyError(err, s.Server)
+	}
+	return err == nil
+}
+
+func collectGuestStatus(ctx context.Context, f *cmdutil.Factory, serverURL string, isDefault bool) authStatus {
+	s := authStatus{Server: serverURL, AuthMethod: "guest", ReadOnly: true, IsDefault: isDefault, TLS: tlsStatusFor(serverURL)}
+	client := api.NewGuestClient(serverURL, f.TLSOption(serverURL), api.WithDebugFunc(f.Printer.Debug), api.WithVersion(version.String()), observeClock(&s)).WithContext(ctx)
+	if !probe(ctx, client, &s) {
 		return s
 	}
 	server, err := client.GetServer()
@@ -156,12 +329,10 @@ func collectGuestStatus(f *cmdutil.Factory, serverURL string, isDefault bool) au
 	return s
 }
 
-func collectTokenStatus(f *cmdutil.Factory, serverURL, token, tokenSource string, isDefault bool) authStatus {
-	s := authStatus{Server: serverURL, AuthMethod: "token", TokenSource: tokenSource, IsDefault: isDefault}
-	client := api.NewClient(serverURL, token, api.WithDebugFunc(f.Printer.Debug), api.WithVersion(version.String())).WithContext(f.Context())
-	if err := client.Probe(f.Context()); err != nil {
-		s.Status = "error"
-		s.Error = friendlyError(err, serverURL)
+func collectTokenStatus(ctx context.Context, f *cmdutil.Factory, serverURL, token, tokenSource string, isDefault bool) authStatus {
+	s := authStatus{Server: serverURL, AuthMethod: "token", TokenSource: tokenSource, ReadOnly: isReadOnlyServer(serverURL), IsDefault: isDefault, TLS: tlsStatusFor(serverURL)}
+	client := api.NewClient(serverURL, token, f.TLSOption(serverURL), api.WithDebugFunc(f.Printer.Debug), api.WithVersion(version.String()), observeClock(&s)).WithContext(ctx)
+	if !probe(ctx, client, &s) {
 		return s
 	}
 	user, err := client.GetCurrentUser()
@@ -171,8 +342,10 @@ func collectTokenStatus(f *cmdutil.Factory, serverURL, token, tokenSource string
 			if api.IsSandboxBlocked(netErr) {
 				s.Error = "network access blocked by sandbox"
 			} else {
-				s.Error = netErr.Error()
+				s.Error = friendlyError(netErr, serverURL)
 			}
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			s.Error = "connection timed out"
 		} else {
 			s.Error = "Token is invalid or expired"
 		}
@@ -201,14 +374,14 @@ func collectTokenStatus(f *cmdutil.Factory, serverURL, token, tokenSource string
 }
 
 func collectBuildStatus(f *cmdutil.Factory, buildAuth *config.BuildAuth) authStatus {
-	s := authStatus{Server: buildAuth.ServerURL, AuthMethod: "build"}
+	s := authStatus{Server: buildAuth.ServerURL, AuthMethod: "build", ReadOnly: isReadOnlyServer(buildAuth.ServerURL), TLS: tlsStatusFor(buildAuth.ServerURL)}
 	client := api.NewClientWithBasicAuth(buildAuth.ServerURL, buildAuth.Username, buildAuth.Password,
+		f.TLSOption(buildAuth.ServerURL),
 		api.WithDebugFunc(f.Printer.Debug),
 		api.WithVersion(version.String()),
+		observeClock(&s),
 	).WithContext(f.Context())
-	if err := client.Probe(f.Context()); err != nil {
-		s.Status = "error"
-		s.Error = friendlyError(err, buildAuth.ServerURL)
+	if !probe(f.Context(), client, &s) {
 		return s
 	}
 	server, err := client.GetServer()
@@ -222,6 +395,25 @@ func collectBuildStatus(f *cmdutil.Factory, buildAuth *config.BuildAuth) authSta
 	return s
 }
 
+// isReadOnlyServer reports whether writes to serverURL are blocked, by TEAMCITY_RO or the server's ro setting.
+func isReadOnlyServer(serverURL string) bool {
+	if serverURL == config.GetServerURL() && config.IsReadOnly() {
+		return true
+	}
+	return config.Get().Servers[serverURL].RO
+}
+
+// observeClock records into s how far the server's clock is from the local one, as of its first response.
+func observeClock(s *authStatus) api.ClientOption {
+	var once sync.Once
+	return api.WithServerClock(func(server, local time.Time) {
+		once.Do(func() {
+			seconds := int64(server.Sub(local).Round(time.Second) / time.Second)
+			s.ClockSkewSeconds = &seconds
+		})
+	})
+}
+
 func renderAuthStatusHuman(f *cmdutil.Factory, results []authStatus) error {
 	p := f.Printer
 
@@ -253,8 +445,8 @@ func renderAuthStatusHuman(f *cmdutil.Factory, results []authStatus) error {
 
 func renderOneStatus(f *cmdutil.Factory, p *output.Printer, s authStatus) {
 	suffix := ""
-	if s.IsDefault {
-		suffix = " " + output.Bold(output.Green("(default)"))
+	if s.Active {
+		suffix = " " + output.Bold(output.Green("(active)"))
 	}
 
 	switch s.AuthMethod {
@@ -267,11 +459,12 @@ func renderOneStatus(f *cmdutil.Factory, p *output.Printer, s authStatus) {
 	switch {
 	case s.Status == "guest":
 		_, _ = fmt.Fprintf(p.Out, "%s Guest access to %s%s\n", output.Green(output.Sym().Check), output.Cyan(s.Server), suffix)
+		_, _ = fmt.Fprintf(p.Out, "  Auth: %s\n", output.Faint("Guest, read-only"))
 		renderServerInfo(p, s)
 
 	case s.Status == "authenticated" && s.AuthMethod == "build":
 		_, _ = fmt.Fprintf(p.Out, "%s Connected to %s\n", output.Green(output.Sym().Check), output.Cyan(s.Server))
-		_, _ = fmt.Fprintf(p.Out, "  Auth: %s\n", output.Faint("Build-level credentials"))
+		_, _ = fmt.Fprintf(p.Out, "  Auth: %s\n", output.Faint(authModeLabel("Build-level credentials", s.ReadOnly)))
 		_, _ = fmt.Fprintf(p.Out, "  Scope: %s\n", output.Faint("Build-level access"))
 		renderServerInfo(p, s)
 
@@ -279,6 +472,7 @@ func renderOneStatus(f *cmdutil.Factory, p *output.Printer, s authStatus) {
 		_, _ = fmt.Fprintf(p.Out, "%s Logged in to %s%s\n", output.Green(output.Sym().Check), output.Cyan(s.Server), suffix)
 		_, _ = fmt.Fprintf(p.Out, "  %s %s (%s) %s %s\n",
 			output.Faint("User:"), s.User.Name, s.User.Username, output.Faint(output.Sym().Sep), output.Faint(tokenSourceLabel(s.TokenSource)))
+		_, _ = fmt.Fprintf(p.Out, "  Auth: %s\n", output.Faint(authModeLabel("Access token", s.ReadOnly)))
 		renderTokenExpiry(p, s.TokenExpiry)
 		renderServerInfo(p, s)
 
@@ -290,6 +484,20 @@ func renderOneStatus(f *cmdutil.Factory, p *output.Printer, s authStatus) {
 		_, _ = fmt.Fprintf(p.Out, "%s Server: %s%s\n", output.Red(output.Sym().Cross), s.Server, suffix)
 		_, _ = fmt.Fprintf(p.Out, "  %s\n", s.Error)
 	}
+	renderTLS(p, s.TLS)
+}
+
+// renderTLS shows the certificate settings that differ from the platform's verification.
+func renderTLS(p *output.Printer, t *tlsStatus) {
+	if t == nil {
+		return
+	}
+	if t.CACert != "" {
+		_, _ = fmt.Fprintf(p.Out, "  TLS: %s\n", output.Faint("trusts CA certificates in "+t.CACert))
+	}
+	if t.InsecureSkipVerify {
+		_, _ = fmt.Fprintf(p.Out, "  %s TLS certificate verification is off (insecure_skip_verify)\n", output.Yellow("!"))
+	}
 }
 
 func renderServerInfo(p *output.Printer, s authStatus) {
@@ -304,6 +512,11 @@ func renderServerInfo(p *output.Printer, s authStatus) {
 	} else {
 		_, _ = fmt.Fprintf(p.Out, "  %s %s\n", output.Green(output.Sym().Check), output.Faint("API compatible"))
 	}
+	if s.ClockSkewSeconds != nil {
+		if skew := time.Duration(*s.ClockSkewSeconds) * time.Second; timeref.Significant(skew) {
+			_, _ = fmt.Fprintf(p.Out, "  %s Local clock is %s; relative times follow the server's clock\n", output.Yellow("!"), cmdutil.ClockSkewText(skew))
+		}
+	}
 }
 
 func renderTokenExpiry(p *output.Printer, expiry string) {
@@ -322,6 +535,7 @@ func renderTokenExpiry(p *output.Printer, expiry string) {
 	case remaining <= 3*24*time.Hour:
 		_, _ = fmt.Fprintf(p.Out, "  %s Token expires %s (on %s)\n",
 			output.Yellow("!"), output.Yellow(humanize.Time(t)), t.Local().Format("Jan 2, 2006"))
+		_, _ = fmt.Fprintf(p.Out, "  Run %s to replace it\n", output.Cyan("teamcity auth token rotate"))
 	default:
 		_, _ = fmt.Fprintf(p.Out, "  Token expires: %s\n", t.Local().Format("Jan 2, 2006"))
 	}
@@ -348,10 +562,20 @@ func renderCredentialsDiagnostic(ctx context.Context, p *output.Printer, s authS
 	}
 }
 
+// authModeLabel describes how the CLI authenticates, noting when writes are blocked.
+func authModeLabel(method string, readOnly bool) string {
+	if readOnly {
+		return method + ", read-only"
+	}
+	return method
+}
+
 func tokenSourceLabel(source string) string {
 	switch source {
 	case "env":
 		return "environment variable"
+	case "command":
+		return "token command"
 	case "keyring":
 		return "system keyring"
 	case "config":
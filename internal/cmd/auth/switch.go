@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/version"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+func newAuthSwitchCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch [server-url]",
+		Short: "Switch the default server",
+		Long: `Make another configured server the default and show which user you act
+as there.
+
+Without an argument, pick the server from a list. The argument may be a
+server URL, with or without its scheme, or a server's context label.
+
+To use another server for a single command instead, pass the global
+--server flag.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  teamcity auth switch
+  teamcity auth switch https://teamcity-staging.example.com
+  teamcity auth switch prod`,
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.ConfiguredServers()),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runAuthSwitch(f, target)
+		},
+	}
+
+	return cmd
+}
+
+func runAuthSwitch(f *cmdutil.Factory, target string) error {
+	p := f.Printer
+	cfg := config.Get()
+	if len(cfg.Servers) == 0 {
+		return api.Validation("no TeamCity servers configured", "Run 'teamcity auth login' to add one")
+	}
+
+	if target == "" {
+		if !f.IsInteractive() {
+			return api.Validation("server URL is required when not running interactively", "Pass it: teamcity auth switch <server-url>")
+		}
+		urls := config.SortedServerURLs(cfg)
+		options := make([]huh.Option[string], len(urls))
+		for i, u := range urls {
+			sc := cfg.Servers[u]
+			label := u
+			if sc.Context != "" {
+				label += " " + output.Faint("["+sc.Context+"]")
+			}
+			user := sc.User
+			if sc.Guest {
+				user = "guest"
+			}
+			if user != "" {
+				label += " " + output.Faint("as "+user)
+			}
+			if u == cfg.DefaultServer {
+				label += " " + output.Faint("(current)")
+			}
+			options[i] = huh.NewOption(label, u)
+		}
+		if err := cmdutil.Select(p, "Switch to server", options, &target); err != nil {
+			return err
+		}
+	}
+
+	serverURL, err := config.ResolveServer(target)
+	if err != nil {
+		return api.Validation(err.Error(), "Run 'teamcity auth status --all' to list the configured servers")
+	}
+	sc, ok := cfg.Servers[serverURL]
+	if !ok {
+		return api.Validation(fmt.Sprintf("%s is not a configured server", serverURL), "Log in to it first: teamcity auth login -s "+serverURL)
+	}
+	if err := config.SetField("default_server", serverURL, ""); err != nil {
+		return err
+	}
+	p.Success("Switched to %s", output.Cyan(serverURL))
+
+	switch token, _, _ := config.GetTokenForServer(serverURL); {
+	case sc.Guest:
+		p.Info("Acting as %s", output.Faint("guest, read-only"))
+	case token == "":
+		p.Warn("No token stored for %s; run 'teamcity auth login -s %s'", serverURL, serverURL)
+	default:
+		client := api.NewClient(serverURL, token,
+			f.TLSOption(serverURL),
+			api.WithDebugFunc(p.Debug),
+			api.WithVersion(version.String()),
+		).WithContext(f.Context())
+		user, err := client.GetCurrentUser()
+		if err != nil {
+			p.Warn("Could not check the stored token: %s", friendlyError(err, serverURL))
+		} else {
+			p.Info("Acting as %s (%s)", user.Name, user.Username)
+		}
+	}
+
+	if envURL := os.Getenv(config.EnvServerURL); envURL != "" {
+		p.Warn("TEAMCITY_URL is set, so commands still use %s until it is unset", config.NormalizeURL(envURL))
+	}
+	return nil
+}
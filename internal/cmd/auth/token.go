@@ -0,0 +1,485 @@
+package auth
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/spf13/cobra"
+)
+
+func newAuthTokenCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage your access tokens",
+		Long: `Create, list, rotate, and revoke access tokens for your TeamCity user, so
+that automation can be bootstrapped without a trip to the profile page.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newAuthTokenCreateCmd(f))
+	cmd.AddCommand(newAuthTokenListCmd(f))
+	cmd.AddCommand(newAuthTokenRevokeCmd(f))
+	cmd.AddCommand(newAuthTokenRotateCmd(f))
+
+	return cmd
+}
+
+// tokenAuthOptions lets a token command authenticate with a username and password instead of the stored token.
+type tokenAuthOptions struct {
+	serverURL     string
+	username      string
+	passwordStdin bool
+}
+
+func addTokenAuthFlags(cmd *cobra.Command, opts *tokenAuthOptions) {
+	cmd.Flags().StringVarP(&opts.serverURL, "server", "s", "", "TeamCity server URL (default: the current server)")
+	cmd.Flags().StringVarP(&opts.username, "username", "u", "", "Authenticate as this user instead of with the stored token")
+	cmd.Flags().BoolVar(&opts.passwordStdin, "password-stdin", false, "Read the password for --username from stdin")
+	_ = cmd.RegisterFlagCompletionFunc("server", completion.ConfiguredServers())
+}
+
+// client returns the server URL and a client for it: basic auth with --username, the usual credentials otherwise.
+func (o *tokenAuthOptions) client(f *cmdutil.Factory) (string, api.ClientInterface, error) {
+	if o.passwordStdin && o.username == "" {
+		return "", nil, api.Validation("--password-stdin requires --username", "Pass --username <user>, or drop --password-stdin to use the stored token")
+	}
+	if o.username == "" {
+		if o.serverURL != "" && config.NormalizeURL(o.serverURL) != config.GetServerURL() {
+			return "", nil, api.Validation(
+				"--server without --username needs a stored token for that server",
+				"Pass --username and --password-stdin, or log in with 'teamcity auth login -s "+o.serverURL+"'",
+			)
+		}
+		if config.IsGuestAuth() {
+			return "", nil, api.Validation(
+				"guest access cannot manage access tokens",
+				"Pass --username and --password-stdin, or log in with a token via 'teamcity auth login'",
+			)
+		}
+		client, err := f.Client()
+		return config.GetServerURL(), client, err
+	}
+
+	serverURL := config.NormalizeURL(cmp.Or(o.serverURL, config.GetServerURL()))
+	if serverURL == "" {
+		return "", nil, api.RequiredFlag("server")
+	}
+	if !o.passwordStdin {
+		return "", nil, api.Validation("--username requires --password-stdin", "Pipe the password in, e.g. 'echo \"$PASSWORD\" | teamcity auth token create --username admin --password-stdin ...'")
+	}
+	password, err := readPasswordStdin(f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return serverURL, f.BasicAuthClient(serverURL, o.username, password), nil
+}
+
+// tokenError explains the errors of servers where token management is disabled or unavailable.
+func tokenError(err error, serverURL string) error {
+	tip := "Manage tokens in the TeamCity UI instead: " + serverURL + "/profile.html?item=accessTokens"
+	if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+		return api.Validation("this TeamCity server does not support managing access tokens over REST", tip)
+	}
+	if _, ok := errors.AsType[*api.PermissionError](err); ok || strings.Contains(strings.ToLower(err.Error()), "disabled") {
+		return api.Validation("access token management is disabled for your user or on this server: "+err.Error(), "Ask a TeamCity administrator to allow token management. "+tip)
+	}
+	return err
+}
+
+type tokenCreateOptions struct {
+	auth            tokenAuthOptions
+	name            string
+	expires         string
+	scopes          []string
+	save            bool
+	insecureStorage bool
+	json            bool
+}
+
+func newAuthTokenCreateCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenCreateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an access token",
+		Long: `Create an access token for your TeamCity user.
+
+The token value is printed once: TeamCity stores only a hash, so it cannot
+be shown again. Use --save to store it as the credential for the server
+right away, in the system keyring unless --insecure-storage is set.
+
+To bootstrap a machine with no token yet, authenticate this one call with
+--username and a password read from stdin (--password-stdin).
+
+Each --scope limits the token to one permission, as PERMISSION for every
+project or PERMISSION:PROJECT for one; without --scope the token has all
+of your permissions.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity auth token create --name ci-bot --expires 90d
+  teamcity auth token create --name ci-bot --scope run_build:Falcon --scope view_project:Falcon
+  echo "$TC_PASSWORD" | teamcity auth token create --name laptop -s https://tc.example.com -u admin --password-stdin --save`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuthTokenCreate(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.name, "name", "n", "", "Token name (required)")
+	cmd.Flags().StringVar(&opts.expires, "expires", "", "Lifetime or expiry date, e.g. 90d, 12h, or 2026-12-31 (default: never)")
+	cmd.Flags().StringArrayVar(&opts.scopes, "scope", nil, "Limit the token to PERMISSION or PERMISSION:PROJECT (can be repeated)")
+	cmd.Flags().BoolVar(&opts.save, "save", false, "Store the token as the credential for the server")
+	cmd.Flags().BoolVar(&opts.insecureStorage, "insecure-storage", false, "With --save, store the token in plain text instead of the system keyring")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	addTokenAuthFlags(cmd, &opts.auth)
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func runAuthTokenCreate(f *cmdutil.Factory, opts *tokenCreateOptions) error {
+	req := api.Token{Name: strings.TrimSpace(opts.name)}
+	if req.Name == "" {
+		return api.RequiredFlag("name")
+	}
+	expiry, err := api.ParseUserExpiry(opts.expires)
+	if err != nil {
+		return api.Validation(err.Error(), "Use a lifetime like 90d or a date like 2026-12-31")
+	}
+	req.ExpirationTime = expiry
+	if req.PermissionRestrictions, err = parseTokenScopes(opts.scopes); err != nil {
+		return err
+	}
+	if opts.insecureStorage && !opts.save {
+		return api.Validation("--insecure-storage requires --save", "")
+	}
+
+	serverURL, client, err := opts.auth.client(f)
+	if err != nil {
+		return err
+	}
+	token, err := client.CreateAccessToken(f.Context(), req)
+	if err != nil {
+		return tokenError(err, serverURL)
+	}
+
+	p := f.Printer
+	if opts.json {
+		if err := p.PrintJSON(token); err != nil {
+			return err
+		}
+	} else if token.Value != "" {
+		_, _ = fmt.Fprintln(p.Out, token.Value)
+	}
+	if f.IsDryRun() {
+		return nil
+	}
+	p.Warn("Copy the token now: TeamCity cannot show %q again", token.Name)
+
+	if !opts.save {
+		return nil
+	}
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		return fmt.Errorf("token created, but failed to look up its user to save it: %w", err)
+	}
+	insecureFallback, err := config.SetServerWithKeyring(serverURL, token.Value, user.Username, token.Name, tokenExpiry(token.ExpirationTime), opts.insecureStorage)
+	if err != nil {
+		return fmt.Errorf("token created, but failed to save it: %w", err)
+	}
+	if insecureFallback {
+		p.Warn("Token saved for %s in plain text at %s", output.Cyan(serverURL), config.ConfigPath())
+	} else {
+		p.Success("Token saved for %s in system keyring", output.Cyan(serverURL))
+	}
+	return nil
+}
+
+// parseTokenScopes turns PERMISSION and PERMISSION:PROJECT scopes into the token's permission restrictions.
+func parseTokenScopes(scopes []string) (*api.PermissionRestrictions, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	restrictions := &api.PermissionRestrictions{}
+	for _, s := range scopes {
+		permission, project, _ := strings.Cut(strings.TrimSpace(s), ":")
+		if permission == "" {
+			return nil, api.Validation(fmt.Sprintf("invalid --scope %q", s), "Use PERMISSION or PERMISSION:PROJECT, e.g. run_build:Falcon")
+		}
+		r := api.PermissionRestriction{IsGlobalScope: project == "", Permission: &api.Permission{ID: permission}}
+		if project != "" {
+			r.Project = &api.Project{ID: project}
+		}
+		restrictions.PermissionRestriction = append(restrictions.PermissionRestriction, r)
+	}
+	return restrictions, nil
+}
+
+// tokenExpiry converts a TeamCity expiration time to the RFC 3339 form the config stores.
+func tokenExpiry(expirationTime string) string {
+	t, err := api.ParseTeamCityTime(expirationTime)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type tokenListOptions struct {
+	auth tokenAuthOptions
+	json bool
+}
+
+func newAuthTokenListCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your access tokens",
+		Long: `List the access tokens of your TeamCity user with when they were created,
+when they expire, and what they are limited to. Token values are never shown.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity auth token list
+  teamcity auth token list --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuthTokenList(f, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	addTokenAuthFlags(cmd, &opts.auth)
+
+	return cmd
+}
+
+func runAuthTokenList(f *cmdutil.Factory, opts *tokenListOptions) error {
+	serverURL, client, err := opts.auth.client(f)
+	if err != nil {
+		return err
+	}
+	tokens, err := client.ListAccessTokens(f.Context())
+	if err != nil {
+		return tokenError(err, serverURL)
+	}
+	if tokens.Token == nil {
+		tokens.Token = []api.Token{}
+	}
+
+	p := f.Printer
+	if opts.json {
+		return p.PrintJSON(tokens.Token)
+	}
+	if len(tokens.Token) == 0 {
+		p.Empty("No access tokens", "Create one with 'teamcity auth token create --name <name>'")
+		return nil
+	}
+
+	headers := []string{"NAME", "CREATED", "EXPIRES", "SCOPE"}
+	var rows [][]string
+	for _, t := range tokens.Token {
+		created := "-"
+		if c, err := api.ParseTeamCityTime(t.CreationTime); err == nil {
+			created = output.RelativeTime(c)
+		}
+		expires := output.Faint("never")
+		if e, err := api.ParseTeamCityTime(t.ExpirationTime); err == nil {
+			expires = e.Local().Format("Jan 2, 2006")
+			if e.Before(timeref.Now()) {
+				expires = output.Red(expires + " (expired)")
+			}
+		}
+		rows = append(rows, []string{t.Name, created, expires, tokenScope(t.PermissionRestrictions)})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0, 3)
+	p.PrintTable(headers, rows)
+	return nil
+}
+
+// tokenScope renders a token's restrictions the way --scope takes them.
+func tokenScope(r *api.PermissionRestrictions) string {
+	if r == nil || len(r.PermissionRestriction) == 0 {
+		return output.Faint("all your permissions")
+	}
+	scopes := make([]string, 0, len(r.PermissionRestriction))
+	for _, pr := range r.PermissionRestriction {
+		if pr.Permission == nil {
+			continue
+		}
+		s := pr.Permission.ID
+		if !pr.IsGlobalScope && pr.Project != nil {
+			s += ":" + pr.Project.ID
+		}
+		scopes = append(scopes, s)
+	}
+	return strings.Join(scopes, ", ")
+}
+
+type tokenRevokeOptions struct {
+	auth tokenAuthOptions
+	yes  bool
+}
+
+func newAuthTokenRevokeCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenRevokeOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "revoke <name>",
+		Aliases: []string{"delete"},
+		Short:   "Revoke an access token",
+		Long: `Revoke one of your access tokens. Anything still using it is rejected
+from then on.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  teamcity auth token revoke ci-bot
+  teamcity auth token revoke ci-bot --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuthTokenRevoke(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	addTokenAuthFlags(cmd, &opts.auth)
+
+	return cmd
+}
+
+func runAuthTokenRevoke(f *cmdutil.Factory, name string, opts *tokenRevokeOptions) error {
+	serverURL, client, err := opts.auth.client(f)
+	if err != nil {
+		return err
+	}
+
+	if !opts.yes && !f.IsDryRun() && f.IsInteractive() {
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("Revoke access token %q?", name), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			f.Printer.Info("Canceled")
+			return nil
+		}
+	}
+
+	if err := client.DeleteAccessToken(f.Context(), name); err != nil {
+		if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+			return api.Validation(fmt.Sprintf("no access token named %q", name), "List your tokens with 'teamcity auth token list'")
+		}
+		return tokenError(err, serverURL)
+	}
+	f.Printer.Success("Revoked access token %q", name)
+	return nil
+}
+
+type tokenRotateOptions struct {
+	expires string
+}
+
+func newAuthTokenRotateCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenRotateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rotate [name]",
+		Short: "Replace the stored access token with a new one",
+		Long: `Replace the access token stored for the current server before it expires.
+
+Creates a new token with the old one's scope, stores it in place of the old
+token (in the keyring or the config file, wherever the old one was, keeping
+the server's other settings), and then revokes the old token.
+
+name is the old token's name. It defaults to the name recorded when the CLI
+stored the token ('auth login --username', 'auth token create --save', or
+an earlier rotation), and otherwise to tc-cli-<hostname>, as 'auth login
+--username' names the tokens it creates. The new token takes the same name
+with the lowest free -N suffix, so rotations alternate between two names.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  teamcity auth token rotate
+  teamcity auth token rotate laptop --expires 30d`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := cmp.Or(config.GetTokenName(), cliTokenName())
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return runAuthTokenRotate(f, name, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.expires, "expires", defaultPasswordTokenExpiry, "Lifetime or expiry date of the new token, e.g. 90d or 2026-12-31")
+
+	return cmd
+}
+
+func runAuthTokenRotate(f *cmdutil.Factory, name string, opts *tokenRotateOptions) error {
+	expiry, err := api.ParseUserExpiry(opts.expires)
+	if err != nil {
+		return api.Validation(err.Error(), "Use a lifetime like 90d or a date like 2026-12-31")
+	}
+	serverURL := config.GetServerURL()
+	if serverURL == "" {
+		return api.Validation("no server to rotate the token of", "Log in first with 'teamcity auth login'")
+	}
+	if config.IsGuestAuth() {
+		return api.Validation("guest access has no token to rotate", "Log in with a token via 'teamcity auth login'")
+	}
+	if _, source, _ := config.GetTokenWithSource(); source == "env" {
+		return api.Validation("the token comes from TEAMCITY_TOKEN, not the stored config",
+			"Create a new token with 'teamcity auth token create' and update TEAMCITY_TOKEN where it is set")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	ctx := f.Context()
+	tokens, err := client.ListAccessTokens(ctx)
+	if err != nil {
+		return tokenError(err, serverURL)
+	}
+	i := slices.IndexFunc(tokens.Token, func(t api.Token) bool { return t.Name == name })
+	if i < 0 {
+		return api.Validation(fmt.Sprintf("no access token named %q", name),
+			"List your tokens with 'teamcity auth token list' and pass the stored one: teamcity auth token rotate <name>")
+	}
+	old := tokens.Token[i]
+
+	created, err := client.CreateAccessToken(ctx, api.Token{
+		Name:                   freeTokenName(tokens.Token, name),
+		ExpirationTime:         expiry,
+		PermissionRestrictions: old.PermissionRestrictions,
+	})
+	if err != nil {
+		return tokenError(err, serverURL)
+	}
+
+	p := f.Printer
+	if !f.IsDryRun() {
+		insecureFallback, err := config.ReplaceToken(serverURL, created.Value, created.Name, tokenExpiry(created.ExpirationTime))
+		if err != nil {
+			// The new token is lost unless shown now; the old one still works.
+			_, _ = fmt.Fprintln(p.Out, created.Value)
+			return fmt.Errorf("created token %q, but failed to store it; %q still works: %w", created.Name, name, err)
+		}
+		if insecureFallback {
+			p.Warn("Token %q stored in plain text at %s", created.Name, config.ConfigPath())
+		} else {
+			p.Success("Token %q stored in system keyring", created.Name)
+		}
+	}
+
+	if err := client.DeleteAccessToken(ctx, name); err != nil {
+		return fmt.Errorf("stored token %q, but failed to revoke %q; revoke it with 'teamcity auth token revoke %s': %w", created.Name, name, name, err)
+	}
+	p.Success("Rotated access token %q to %q", name, created.Name)
+	if t, err := api.ParseTeamCityTime(created.ExpirationTime); err == nil {
+		p.Info("Token expires: %s", output.Yellow(t.Local().Format("Jan 2, 2006")))
+	}
+	return nil
+}
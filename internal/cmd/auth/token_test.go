@@ -0,0 +1,253 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+)
+
+func TestAuthTokenCreate(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var got api.Token
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		cmdtest.JSON(w, api.Token{Name: got.Name, Value: "eyJ0eXAiOiAiVENWMiJ9.secret", ExpirationTime: got.ExpirationTime})
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "create", "--name", "ci-bot", "--expires", "90d",
+		"--scope", "run_build:Falcon", "--scope", "view_agent")
+
+	assert.Equal(t, 1, strings.Count(out, "eyJ0eXAiOiAiVENWMiJ9.secret"), "the value is printed exactly once")
+	assert.Contains(t, out, `TeamCity cannot show "ci-bot" again`)
+	assert.Equal(t, "ci-bot", got.Name)
+	assert.NotEmpty(t, got.ExpirationTime)
+	require.NotNil(t, got.PermissionRestrictions)
+	scopes := got.PermissionRestrictions.PermissionRestriction
+	require.Len(t, scopes, 2)
+	assert.Equal(t, "run_build", scopes[0].Permission.ID)
+	assert.Equal(t, "Falcon", scopes[0].Project.ID)
+	assert.False(t, scopes[0].IsGlobalScope)
+	assert.True(t, scopes[1].IsGlobalScope)
+	assert.Nil(t, scopes[1].Project)
+}
+
+func TestAuthTokenCreateBasicAuthSave(t *testing.T) {
+	gokeyring.MockInit()
+	ts := cmdtest.SetupMockClient(t)
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	t.Setenv("TEAMCITY_TOKEN", "")
+
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "s3cret" {
+			cmdtest.Error(w, http.StatusUnauthorized, "bad credentials")
+			return
+		}
+		cmdtest.JSON(w, api.Token{Name: "laptop", Value: "new-token-value", ExpirationTime: "20991231T000000+0000"})
+	})
+	ts.Factory.IOStreams.In = strings.NewReader("s3cret\n")
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "create", "--name", "laptop",
+		"--username", "admin", "--password-stdin", "--save")
+
+	assert.Contains(t, out, "new-token-value")
+	assert.Contains(t, out, "Token saved for")
+	token, source, err := config.GetTokenForServer(ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "new-token-value", token)
+	assert.Equal(t, "keyring", source)
+	assert.Equal(t, "2099-12-31T00:00:00Z", config.Get().Servers[ts.URL].TokenExpiry)
+	assert.Equal(t, "laptop", config.Get().Servers[ts.URL].TokenName, "rotate defaults to the saved token")
+}
+
+func TestAuthTokenCreateDisabled(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusForbidden, "Access tokens are disabled")
+	})
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "auth", "token", "create", "--name", "ci-bot")
+	assert.Contains(t, err.Error(), "access token management is disabled")
+	var verr *api.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, verr.Tip, "/profile.html?item=accessTokens")
+}
+
+func TestAuthTokenCreateInvalid(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	for name, tc := range map[string]struct {
+		args []string
+		want string
+	}{
+		"missing name":             {[]string{}, `"name" not set`},
+		"bad scope":                {[]string{"--name", "x", "--scope", ":Falcon"}, `invalid --scope ":Falcon"`},
+		"past expiry":              {[]string{"--name", "x", "--expires", "2001-01-01"}, "expiry must be in the future"},
+		"password without user":    {[]string{"--name", "x", "--password-stdin"}, "--password-stdin requires --username"},
+		"user without password":    {[]string{"--name", "x", "--username", "admin"}, "--username requires --password-stdin"},
+		"insecure without save":    {[]string{"--name", "x", "--insecure-storage"}, "--insecure-storage requires --save"},
+		"other server, no account": {[]string{"--name", "x", "--server", "https://other.example.com"}, "needs a stored token for that server"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			args := append([]string{"auth", "token", "create"}, tc.args...)
+			cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, tc.want, args...)
+		})
+	}
+}
+
+func TestAuthTokenList(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TokenList{Count: 2, Token: []api.Token{
+			{Name: "ci-bot", CreationTime: "20260101T000000+0000", ExpirationTime: "20990101T000000+0000",
+				PermissionRestrictions: &api.PermissionRestrictions{PermissionRestriction: []api.PermissionRestriction{
+					{Project: &api.Project{ID: "Falcon"}, Permission: &api.Permission{ID: "run_build"}},
+				}}},
+			{Name: "old", CreationTime: "20200101T000000+0000", ExpirationTime: "20210101T000000+0000"},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "list")
+	assert.Regexp(t, `NAME\s+CREATED\s+EXPIRES\s+SCOPE`, out)
+	assert.Regexp(t, `ci-bot\s+.*Jan 1, 2099\s+run_build:Falcon`, out)
+	assert.Regexp(t, `old\s+.*\(expired\)\s+all your permissions`, out)
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "list", "--json")
+	var tokens []api.Token
+	require.NoError(t, json.Unmarshal([]byte(out), &tokens))
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "ci-bot", tokens[0].Name)
+}
+
+func TestAuthTokenRevoke(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var deleted string
+	ts.Handle("DELETE /app/rest/users/current/tokens/", func(w http.ResponseWriter, r *http.Request) {
+		deleted = strings.TrimPrefix(r.URL.Path, "/app/rest/users/current/tokens/")
+		if deleted == "missing" {
+			cmdtest.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "revoke", "ci-bot", "--yes")
+	assert.Equal(t, "ci-bot", deleted)
+	assert.Contains(t, out, `Revoked access token "ci-bot"`)
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `no access token named "missing"`, "auth", "token", "revoke", "missing", "--yes")
+}
+
+func TestAuthLoginWithPassword(t *testing.T) {
+	gokeyring.MockInit()
+	ts := cmdtest.SetupMockClient(t)
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TEAMCITY_URL", "")
+	t.Setenv("TEAMCITY_TOKEN", "")
+	host, _ := os.Hostname()
+
+	var created api.Token
+	ts.Handle("GET /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TokenList{})
+	})
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "s3cret" {
+			cmdtest.Error(w, http.StatusUnauthorized, "bad credentials")
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		cmdtest.JSON(w, api.Token{Name: created.Name, Value: "password-token", ExpirationTime: "20991231T000000+0000"})
+	})
+	ts.Factory.IOStreams.In = strings.NewReader("s3cret\n")
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "login", "-s", ts.URL, "--username", "admin", "--password-stdin", "--expires", "30d")
+
+	assert.Contains(t, out, "Logged in to")
+	assert.True(t, strings.HasPrefix(created.Name, "tc-cli"), created.Name)
+	if host != "" {
+		assert.NotEqual(t, "tc-cli", created.Name, "the name includes the hostname")
+	}
+	assert.NotEmpty(t, created.ExpirationTime)
+	token, source, err := config.GetTokenForServer(ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "password-token", token)
+	assert.Equal(t, "keyring", source)
+	assert.NotContains(t, config.Get().Servers[ts.URL].Token, "s3cret")
+	assert.Equal(t, created.Name, config.Get().Servers[ts.URL].TokenName)
+}
+
+func TestAuthLoginWithPasswordTokensDisabled(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusForbidden, "Access tokens are disabled")
+	})
+	ts.Factory.IOStreams.In = strings.NewReader("s3cret\n")
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "auth", "login", "-s", ts.URL, "--username", "admin", "--password-stdin")
+	assert.Contains(t, err.Error(), "access token management is disabled")
+}
+
+func TestAuthTokenRotate(t *testing.T) {
+	gokeyring.MockInit()
+	ts := cmdtest.SetupMockClient(t)
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	t.Setenv("TEAMCITY_URL", "")
+	t.Setenv("TEAMCITY_TOKEN", "")
+	_, err := config.SetServerWithKeyring(ts.URL, "old-token", "admin", "", "2026-01-01T00:00:00Z", false)
+	require.NoError(t, err)
+	require.NoError(t, config.SetField("ro", "true", ts.URL))
+
+	scope := &api.PermissionRestrictions{PermissionRestriction: []api.PermissionRestriction{{IsGlobalScope: true, Permission: &api.Permission{ID: "view_project"}}}}
+	ts.Handle("GET /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TokenList{Count: 2, Token: []api.Token{{Name: "laptop", PermissionRestrictions: scope}, {Name: "laptop-2"}}})
+	})
+	var created api.Token
+	ts.Handle("POST /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		cmdtest.JSON(w, api.Token{Name: created.Name, Value: "new-token", ExpirationTime: "20991231T000000+0000"})
+	})
+	var revoked string
+	ts.Handle("DELETE /app/rest/users/current/tokens/", func(w http.ResponseWriter, r *http.Request) {
+		revoked = strings.TrimPrefix(r.URL.Path, "/app/rest/users/current/tokens/")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "rotate", "laptop")
+
+	assert.Equal(t, "laptop-3", created.Name, "the lowest free suffix")
+	assert.Equal(t, scope, created.PermissionRestrictions, "the old token's scope carries over")
+	assert.Equal(t, "laptop", revoked)
+	assert.Contains(t, out, `Rotated access token "laptop" to "laptop-3"`)
+	token, source, err := config.GetTokenForServer(ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", token)
+	assert.Equal(t, "keyring", source)
+	sc := config.Get().Servers[ts.URL]
+	assert.True(t, sc.RO, "other settings are kept")
+	assert.Equal(t, "2099-12-31T00:00:00Z", sc.TokenExpiry)
+	assert.Equal(t, "laptop-3", sc.TokenName)
+
+	// Without a name, rotate picks up the token it stored last time.
+	ts.Handle("GET /app/rest/users/current/tokens", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TokenList{Count: 2, Token: []api.Token{{Name: "laptop-2"}, {Name: "laptop-3"}}})
+	})
+	out = cmdtest.CaptureOutput(t, ts.Factory, "auth", "token", "rotate")
+	assert.Equal(t, "laptop-3", revoked)
+	assert.Contains(t, out, `Rotated access token "laptop-3" to "laptop"`)
+	assert.Equal(t, "laptop", config.Get().Servers[ts.URL].TokenName)
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `no access token named "missing"`, "auth", "token", "rotate", "missing")
+}
@@ -0,0 +1,252 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/buildkite/shellwords"
+	"github.com/spf13/cobra"
+)
+
+type batchOptions struct {
+	jsonInput       bool
+	continueOnError bool
+	concurrency     int
+}
+
+// NewCmd returns the batch command. newRoot builds a fresh command tree for every entry, so flag values
+// never leak from one command into the next.
+func NewCmd(f *cmdutil.Factory, newRoot func(*cmdutil.Factory) *cobra.Command) *cobra.Command {
+	opts := &batchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run many commands from stdin in one process",
+		Long: `Read commands from stdin and run them in one process, sharing a single
+authenticated client, and write one JSON result per command to stdout.
+
+Each input line is one command, with or without the leading 'teamcity',
+quoted as in a POSIX shell; blank lines and lines starting with # are skipped. With
+--json-input, stdin is instead a JSON array whose items are command strings
+or argument arrays.
+
+Each result is a JSON object on its own line, in input order:
+
+  {"cmd":"run tag 123 x","ok":true,"output":"..."}
+  {"cmd":"run view 999 --json","ok":false,"exit_code":1,"error":{"code":"not_found","message":"..."}}
+
+When a command prints a JSON document, it is embedded as "data" instead of
+"output". Prompts are disabled, as with --no-input, and aliases are not
+expanded.
+
+The batch stops at the first failing command unless --continue-on-error is
+set, and exits 1 if any command failed. --concurrency runs up to N commands
+at once; use it only for commands that do not depend on each other.`,
+		Args: cobra.NoArgs,
+		Example: `  printf 'run tag 123 nightly\nrun pin 123\n' | teamcity batch
+  teamcity batch --continue-on-error < commands.txt
+  echo '["run view 123 --json", ["run", "comment", "123", "Deployed to prod"]]' | teamcity batch --json-input
+  teamcity batch --concurrency 4 --continue-on-error < cancels.txt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBatch(f, newRoot, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.jsonInput, "json-input", false, "Read a JSON array of commands instead of lines")
+	cmd.Flags().BoolVar(&opts.continueOnError, "continue-on-error", false, "Keep running after a command fails")
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", 1, "Number of commands to run at once")
+
+	return cmd
+}
+
+// entry is one parsed input command; err reports input that could not be parsed into arguments.
+type entry struct {
+	cmd  string
+	args []string
+	err  error
+}
+
+// result is the JSON object written for each command.
+type result struct {
+	Cmd      string                  `json:"cmd"`
+	OK       bool                    `json:"ok"`
+	Output   string                  `json:"output,omitempty"`
+	Data     json.RawMessage         `json:"data,omitempty"`
+	Stderr   string                  `json:"stderr,omitempty"`
+	ExitCode int                     `json:"exit_code,omitempty"`
+	Error    *output.JSONErrorDetail `json:"error,omitempty"`
+}
+
+// parseLines reads one shell-quoted command per line, skipping blanks and # comments.
+func parseLines(r io.Reader) ([]entry, error) {
+	var entries []entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args, err := shellwords.Split(line)
+		entries = append(entries, entry{cmd: line, args: args, err: err})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read commands: %w", err)
+	}
+	return entries, nil
+}
+
+// parseJSON reads a JSON array whose items are command strings or argument arrays.
+func parseJSON(r io.Reader) ([]entry, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, api.Validation(
+			fmt.Sprintf("invalid --json-input: %v", err),
+			`Pass a JSON array, e.g. ["run view 123", ["run", "tag", "123", "nightly"]]`,
+		)
+	}
+	entries := make([]entry, len(items))
+	for i, item := range items {
+		var line string
+		if err := json.Unmarshal(item, &line); err == nil {
+			args, err := shellwords.Split(line)
+			entries[i] = entry{cmd: line, args: args, err: err}
+			continue
+		}
+		var args []string
+		if err := json.Unmarshal(item, &args); err != nil {
+			entries[i] = entry{cmd: string(item), err: errors.New("item must be a string or an array of strings")}
+			continue
+		}
+		quoted := make([]string, len(args))
+		for j, a := range args {
+			quoted[j] = shellwords.QuotePosix(a)
+		}
+		entries[i] = entry{cmd: strings.Join(quoted, " "), args: args}
+	}
+	return entries, nil
+}
+
+func runBatch(f *cmdutil.Factory, newRoot func(*cmdutil.Factory) *cobra.Command, opts *batchOptions) error {
+	if opts.concurrency < 1 {
+		return api.Validation(fmt.Sprintf("--concurrency must be at least 1, got %d", opts.concurrency), "")
+	}
+
+	parse := parseLines
+	if opts.jsonInput {
+		parse = parseJSON
+	}
+	entries, err := parse(f.IOStreams.In)
+	if err != nil {
+		return err
+	}
+
+	// One client for the whole batch: authentication and server checks happen once.
+	clientFunc := sync.OnceValues(f.ClientFunc)
+	output.NoColor = true
+
+	slots := make([]chan result, len(entries))
+	for i := range slots {
+		slots[i] = make(chan result, 1)
+	}
+	var failed atomic.Bool
+	go func() {
+		sem := make(chan struct{}, opts.concurrency)
+		for i, e := range entries {
+			sem <- struct{}{}
+			if failed.Load() && !opts.continueOnError {
+				for _, s := range slots[i:] {
+					close(s)
+				}
+				return
+			}
+			go func() {
+				defer func() { <-sem }()
+				r := runEntry(f, clientFunc, newRoot, e)
+				if !r.OK {
+					failed.Store(true)
+				}
+				slots[i] <- r
+			}()
+		}
+	}()
+
+	enc := json.NewEncoder(f.Printer.Out)
+	ran := 0
+	for _, slot := range slots {
+		r, ok := <-slot
+		if !ok {
+			break
+		}
+		ran++
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	if skipped := len(entries) - ran; skipped > 0 {
+		f.Printer.Warn("Stopped after a failed command; %d not run (use --continue-on-error to run all)", skipped)
+	}
+	if failed.Load() {
+		return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+	}
+	return nil
+}
+
+// runEntry executes one command on a fresh command tree whose Factory writes to buffers.
+func runEntry(f *cmdutil.Factory, clientFunc func() (api.ClientInterface, error), newRoot func(*cmdutil.Factory) *cobra.Command, e entry) result {
+	r := result{Cmd: e.cmd}
+	if len(e.args) > 0 && e.args[0] == "teamcity" {
+		e.args = e.args[1:]
+	}
+	if e.err == nil && len(e.args) > 0 && e.args[0] == "batch" {
+		e.err = errors.New("batch cannot run itself")
+	}
+	if e.err != nil {
+		r.ExitCode = cmdutil.ExitFailure
+		r.Error = &output.JSONErrorDetail{Code: output.ErrCodeValidation, Message: e.err.Error()}
+		return r
+	}
+
+	var stdout, stderr bytes.Buffer
+	child := f.Child(&cmdutil.IOStreams{In: strings.NewReader(""), Out: &stdout, ErrOut: &stderr})
+	child.ClientFunc = clientFunc
+	root := newRoot(child)
+	root.SetArgs(e.args)
+	root.SetIn(child.IOStreams.In)
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetContext(child.Context())
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 && (out[0] == '{' || out[0] == '[') && json.Valid(out) {
+		r.Data = out
+	} else {
+		r.Output = stdout.String()
+	}
+	r.Stderr = stderr.String()
+	if err == nil {
+		r.OK = true
+		return r
+	}
+	r.ExitCode = cmdutil.ExitFailure
+	if exitErr, ok := errors.AsType[*cmdutil.ExitError](err); ok {
+		r.ExitCode = exitErr.Code
+		return r
+	}
+	code, message, suggestion := output.ClassifyError(err)
+	r.Error = &output.JSONErrorDetail{Code: code, Message: message, Suggestion: suggestion}
+	return r
+}
@@ -0,0 +1,171 @@
+package batch_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchResult struct {
+	Cmd      string                  `json:"cmd"`
+	OK       bool                    `json:"ok"`
+	Output   string                  `json:"output"`
+	Data     json.RawMessage         `json:"data"`
+	ExitCode int                     `json:"exit_code"`
+	Error    *output.JSONErrorDetail `json:"error"`
+}
+
+// runBatch feeds stdin to teamcity batch and returns the decoded results, stderr, and the command error.
+func runBatch(t *testing.T, f *cmdutil.Factory, stdin string, args ...string) ([]batchResult, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	f.IOStreams = &cmdutil.IOStreams{In: strings.NewReader(stdin), Out: &stdout, ErrOut: &stderr}
+	f.Printer = &output.Printer{Out: &stdout, ErrOut: &stderr}
+
+	root := cmd.NewCommand(f)
+	root.SetArgs(append([]string{"batch"}, args...))
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SilenceErrors, root.SilenceUsage = true, true
+	err := root.Execute()
+
+	var results []batchResult
+	dec := json.NewDecoder(&stdout)
+	for dec.More() {
+		var r batchResult
+		require.NoError(t, dec.Decode(&r), "stdout must be NDJSON")
+		results = append(results, r)
+	}
+	return results, stderr.String(), err
+}
+
+func TestBatchMixed(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	stdin := `# tag and inspect a run
+run tag 123 nightly
+
+run view 123 --json
+run view 999999999
+run comment 123 "Deployed to prod"
+`
+	results, stderr, err := runBatch(T, ts.Factory, stdin, "--continue-on-error")
+
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(T, err, &exitErr)
+	assert.Equal(T, cmdutil.ExitFailure, exitErr.Code)
+	assert.Empty(T, stderr)
+
+	require.Len(T, results, 4)
+	assert.Equal(T, "run tag 123 nightly", results[0].Cmd)
+	assert.True(T, results[0].OK)
+	assert.Contains(T, results[0].Output, "nightly")
+
+	assert.True(T, results[1].OK)
+	var build api.Build
+	require.NoError(T, json.Unmarshal(results[1].Data, &build), "JSON output is embedded as data")
+	assert.Equal(T, "TestProject_Build", build.BuildTypeID)
+
+	assert.False(T, results[2].OK)
+	assert.Equal(T, cmdutil.ExitFailure, results[2].ExitCode)
+	require.NotNil(T, results[2].Error)
+	assert.Equal(T, output.ErrCodeNotFound, results[2].Error.Code)
+
+	assert.Equal(T, `run comment 123 "Deployed to prod"`, results[3].Cmd)
+	assert.True(T, results[3].OK)
+}
+
+func TestBatchStopsOnError(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	results, stderr, err := runBatch(T, ts.Factory, "run view 999999999\nrun tag 123 nightly\nrun pin 123\n")
+
+	require.Error(T, err)
+	require.Len(T, results, 1)
+	assert.False(T, results[0].OK)
+	assert.Contains(T, stderr, "2 not run")
+}
+
+func TestBatchJSONInput(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	var comment atomic.Value
+	ts.Handle("PUT /app/rest/builds/id:123/comment", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r.Body)
+		comment.Store(buf.String())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	stdin := `["run tag 123 nightly", ["teamcity", "run", "comment", "123", "it's done"], 42]`
+	results, _, err := runBatch(T, ts.Factory, stdin, "--json-input", "--continue-on-error")
+
+	require.Error(T, err)
+	require.Len(T, results, 3)
+	assert.True(T, results[0].OK)
+	assert.True(T, results[1].OK, "leading teamcity is accepted: %+v", results[1])
+	assert.True(T, strings.HasPrefix(results[1].Cmd, "teamcity run comment 123 "), results[1].Cmd)
+	assert.Equal(T, "it's done", comment.Load())
+	assert.False(T, results[2].OK)
+	require.NotNil(T, results[2].Error)
+	assert.Equal(T, output.ErrCodeValidation, results[2].Error.Code)
+}
+
+func TestBatchConcurrencyKeepsOrder(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	var lines []string
+	for _, id := range []string{"1", "2", "3", "4", "5", "6"} {
+		lines = append(lines, "run tag "+id+" nightly")
+	}
+	results, _, err := runBatch(T, ts.Factory, strings.Join(lines, "\n"), "--concurrency", "3")
+
+	require.NoError(T, err)
+	require.Len(T, results, len(lines))
+	for i, r := range results {
+		assert.Equal(T, lines[i], r.Cmd)
+		assert.True(T, r.OK)
+	}
+}
+
+func TestBatchLineFlags(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	results, _, err := runBatch(T, ts.Factory, "run tag 123 nightly --quiet\nrun tag 123 nightly\n")
+	require.NoError(T, err)
+	require.Len(T, results, 2)
+	assert.Empty(T, results[0].Output, "--quiet on a line silences that line")
+	assert.Contains(T, results[1].Output, "nightly")
+
+	results, _, err = runBatch(T, ts.Factory, "run tag 123 nightly\n", "--quiet")
+	require.NoError(T, err)
+	require.Len(T, results, 1)
+	assert.Empty(T, results[0].Output, "lines inherit --quiet from batch")
+}
+
+func TestBatchErrors(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	results, _, err := runBatch(T, ts.Factory, "batch\nrun tag 123 'unterminated\n", "--continue-on-error")
+	require.Error(T, err)
+	require.Len(T, results, 2)
+	assert.Contains(T, results[0].Error.Message, "batch cannot run itself")
+	assert.False(T, results[1].OK)
+
+	_, _, err = runBatch(T, ts.Factory, "", "--concurrency", "0")
+	assert.ErrorContains(T, err, "--concurrency must be at least 1")
+
+	_, _, err = runBatch(T, ts.Factory, "run tag 1 x", "--json-input")
+	assert.ErrorContains(T, err, "invalid --json-input")
+}
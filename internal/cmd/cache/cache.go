@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the API response cache",
+		Long: `Manage the on-disk cache of API responses.
+
+With --cache <duration> (or the default_cache config key), commands answer
+API reads from responses cached within that long instead of asking the
+server again, which speeds up completion and repeated listings on a slow
+server. Responses are cached per server and user, in the cache directory
+under the config directory. Who you are, tokens, secure values, logs, and
+artifacts are never cached, and any change a command makes (starting a
+run, pausing a job, 'teamcity api -X POST', ...) drops the server's cached
+responses.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newCacheClearCmd(f))
+
+	return cmd
+}
+
+func newCacheClearCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached API response",
+		Args:  cobra.NoArgs,
+		Example: `  teamcity cache clear
+  teamcity job list --cache 5m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := config.CacheDir()
+			if err != nil {
+				return err
+			}
+			n, err := api.NewResponseCache(dir, 0, nil).Clear()
+			if err != nil {
+				return fmt.Errorf("failed to clear the response cache: %w", err)
+			}
+			if n == 0 {
+				f.Printer.Info("The response cache is empty")
+				return nil
+			}
+			f.Printer.Success("Removed %s", english.Plural(n, "cached response", ""))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheClear(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := config.CacheDir()
+	require.NoError(t, err)
+	for _, name := range []string{"a/x/1.json", "a/x/2.json", "b/y/3.json"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	}
+
+	f := cmdutil.NewFactory()
+	out := cmdtest.CaptureOutput(t, f, "cache", "clear")
+	assert.Contains(t, out, "Removed 3 cached responses")
+	assert.NoDirExists(t, dir)
+
+	out = cmdtest.CaptureOutput(t, f, "cache", "clear")
+	assert.Contains(t, out, "The response cache is empty")
+}
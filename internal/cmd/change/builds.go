@@ -0,0 +1,126 @@
+package change
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type changeBuildsOptions struct {
+	job   string
+	limit int
+	json  bool
+}
+
+// changeBuilds is one change with the runs that include it.
+type changeBuilds struct {
+	Change api.Change  `json:"change"`
+	Builds []api.Build `json:"builds"`
+}
+
+func newChangeBuildsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &changeBuildsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "builds <change-id|commit-sha>",
+		Short: "List the runs that include a change",
+		Long: `List the runs whose changes include a commit, on any branch, newest first.
+Use it to answer whether a commit made it into a release build:
+--job narrows both the changes and the runs to one job.
+
+When the commit is known in several VCS roots, the runs of each of its
+changes are listed. With --json, prints an array of {change, builds}.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  teamcity change builds 3f2a9c1
+  teamcity change builds 3f2a9c1 --job Falcon_Release
+  teamcity change builds @head --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangeBuilds(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Only runs of this job")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 30, "Maximum number of runs per change (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+func runChangeBuilds(f *cmdutil.Factory, ref string, opts *changeBuildsOptions) error {
+	if opts.limit < 0 {
+		return api.Validation("--limit must not be negative", "Use --limit 0 to list every run")
+	}
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	changes, err := resolveChanges(f, client, ref, opts.job)
+	if err != nil {
+		return err
+	}
+
+	results := make([]changeBuilds, 0, len(changes))
+	anyTruncated := false
+	for _, c := range changes {
+		builds, truncated, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+			Change:      c.ID,
+			BuildTypeID: opts.job,
+			Limit:       opts.limit,
+			// change:(id:N) is selective already, and a commit older than the recent runs must still be found.
+			DeepLookup: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list runs of change %d: %w", c.ID, err)
+		}
+		anyTruncated = anyTruncated || truncated
+		results = append(results, changeBuilds{Change: c, Builds: builds.Builds})
+	}
+
+	if opts.json {
+		return f.Printer.PrintJSON(results)
+	}
+
+	p := f.Printer
+	for i, r := range results {
+		if i > 0 {
+			_, _ = fmt.Fprintln(p.Out)
+		}
+		if len(results) > 1 {
+			_, _ = fmt.Fprintf(p.Out, "%s %s %s\n", output.Yellow(shortSHA(r.Change.Version)),
+				output.Faint("in"), cmp.Or(vcsRootName(r.Change), "change "+strconv.Itoa(r.Change.ID)))
+		}
+		if len(r.Builds) == 0 {
+			where := "any run"
+			if opts.job != "" {
+				where = "any run of " + opts.job
+			}
+			p.Info("Change %d (%s) is not in %s yet", r.Change.ID, shortSHA(r.Change.Version), where)
+			continue
+		}
+		rows := make([][]string, 0, len(r.Builds))
+		for _, b := range r.Builds {
+			when := ""
+			if t, err := api.ParseTeamCityTime(cmp.Or(b.FinishDate, b.StartDate, b.QueuedDate)); err == nil {
+				when = output.RelativeTime(t)
+			}
+			rows = append(rows, []string{
+				output.StatusIcon(b.Status, b.State, b.StatusText),
+				strconv.Itoa(b.ID),
+				cmdutil.JobName(b.BuildType, b.BuildTypeID),
+				cmdutil.RunNumber(b.Number),
+				b.BranchName,
+				when,
+			})
+		}
+		p.PrintTable([]string{"STATUS", "ID", "JOB", "NUMBER", "BRANCH", "WHEN"}, rows)
+	}
+	cmdutil.WarnListTruncated(f, anyTruncated, opts.limit)
+	return nil
+}
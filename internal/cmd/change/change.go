@@ -0,0 +1,95 @@
+package change
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "change",
+		Short: "View VCS changes and the runs that include them",
+		Long: `View the commits TeamCity has seen, and find the runs that include them.
+
+A change is a commit as TeamCity knows it. Refer to one by its change ID or
+by its commit SHA; a short SHA is expanded from the local git repository,
+and @head is the current HEAD. A commit fetched by several VCS roots is a
+separate change in each of them, and the commands show all of them.
+
+To list the changes in a run, use teamcity run changes.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newChangeViewCmd(f))
+	cmd.AddCommand(newChangeBuildsCmd(f))
+
+	return cmd
+}
+
+// resolveChanges returns the changes ref names: a change ID, or else a commit SHA, narrowed to job's VCS roots when set.
+func resolveChanges(f *cmdutil.Factory, client api.ClientInterface, ref, job string) ([]api.Change, error) {
+	if id, err := strconv.Atoi(ref); err == nil && id > 0 {
+		changes, err := client.GetChanges(f.Context(), api.ChangesOptions{ID: id, BuildTypeID: job})
+		if err == nil && len(changes.Change) > 0 {
+			return changes.Change, nil
+		}
+		if _, notFound := errors.AsType[*api.NotFoundError](err); err != nil && !notFound {
+			return nil, err
+		}
+	}
+
+	sha, err := resolveSHA(ref)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := client.GetChanges(f.Context(), api.ChangesOptions{Version: sha, BuildTypeID: job})
+	if err != nil {
+		return nil, err
+	}
+	if len(changes.Change) == 0 {
+		tip := "Check the SHA, or that TeamCity has already fetched the commit"
+		if job != "" {
+			tip = "Check that " + job + " uses the repository of the commit, or drop --job"
+		}
+		return nil, api.Validation("no change matches "+ref, tip)
+	}
+	return changes.Change, nil
+}
+
+// resolveSHA expands @head and short SHAs through the local git repository; outside one, ref is used as given.
+func resolveSHA(ref string) (string, error) {
+	if strings.EqualFold(ref, "@head") {
+		sha, err := git.HeadRevision()
+		if err != nil {
+			return "", api.Validation("failed to resolve revision 'HEAD'", "Ensure you are in a git repository")
+		}
+		return sha, nil
+	}
+	if len(ref) < 40 && git.IsRepo() {
+		if sha, err := git.ResolveRevision(ref); err == nil {
+			return sha, nil
+		}
+	}
+	return ref, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func vcsRootName(c api.Change) string {
+	if c.VcsRootInstance == nil {
+		return ""
+	}
+	return c.VcsRootInstance.VcsRootID
+}
@@ -0,0 +1,107 @@
+package change_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+const testSHA = "3f2a9c1e5b7d9f0a1c3e5b7d9f0a1c3e5b7d9f0a"
+
+// setupChangeServer serves testSHA as two changes, one per VCS root, and change 4711 by ID. Runs include change 10
+// in Falcon_Build and Falcon_Release; change 11 is in none.
+func setupChangeServer(t *testing.T) *cmdtest.TestServer {
+	ts := cmdtest.SetupMockClient(t)
+	commit := func(id int, root string) api.Change {
+		return api.Change{
+			ID: id, Version: testSHA, Username: "alice", Date: "20260301T120000+0000",
+			Comment: "Fix flaky upload\n\nRetry on 503.", WebURL: "https://example/change/" + root,
+			VcsRootInstance: &api.VcsRootInstanceRef{VcsRootID: root},
+			Files:           &api.Files{File: []api.FileChange{{File: "upload.go", ChangeType: "edited"}, {File: "retry.go", ChangeType: "added"}}},
+		}
+	}
+	ts.Handle("GET /app/rest/changes", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		switch {
+		case locator == "id:4711":
+			cmdtest.JSON(w, api.ChangeList{Count: 1, Change: []api.Change{{ID: 4711, Version: "aaaa", Username: "bob", Comment: "Bump"}}})
+		case strings.HasPrefix(locator, "version:"+testSHA+",buildType:"):
+			cmdtest.JSON(w, api.ChangeList{Count: 1, Change: []api.Change{commit(10, "Falcon_GitHub")}})
+		case locator == "version:"+testSHA:
+			cmdtest.JSON(w, api.ChangeList{Count: 2, Change: []api.Change{commit(10, "Falcon_GitHub"), commit(11, "Falcon_Mirror")}})
+		default:
+			cmdtest.JSON(w, api.ChangeList{})
+		}
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		assert.NotContains(t, locator, "lookupLimit", "a change's runs are looked up through all history")
+		if !strings.Contains(locator, "change:(id:10)") {
+			cmdtest.JSON(w, api.BuildList{})
+			return
+		}
+		builds := []api.Build{
+			{ID: 502, Number: "2024.3", Status: "SUCCESS", State: "finished", BuildTypeID: "Falcon_Release", BranchName: "release/2024.3"},
+			{ID: 501, Number: "88", Status: "FAILURE", State: "finished", BuildTypeID: "Falcon_Build", BranchName: "main"},
+		}
+		if strings.Contains(locator, "buildType:Falcon_Release") {
+			builds = builds[:1]
+		}
+		cmdtest.JSON(w, api.BuildList{Count: len(builds), Builds: builds})
+	})
+	return ts
+}
+
+func TestChangeView(t *testing.T) {
+	ts := setupChangeServer(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "change", "view", testSHA)
+	assert.Contains(t, out, "3f2a9c1 is 2 changes, one per VCS root")
+	assert.Contains(t, out, "Fix flaky upload\nCommit: "+testSHA+"\nID: 10\nAuthor: alice\n")
+	assert.Contains(t, out, "VCS root: Falcon_Mirror")
+	assert.Contains(t, out, "  Retry on 503.\n")
+	assert.Contains(t, out, "Files (2)\n  M  upload.go\n  A  retry.go\n")
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "change", "view", testSHA, "--job", "Falcon_Build", "--json")
+	var changes []api.Change
+	require.NoError(t, json.Unmarshal([]byte(out), &changes))
+	require.Len(t, changes, 1)
+	assert.Equal(t, 10, changes[0].ID)
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "change", "view", "4711")
+	assert.Contains(t, out, "Bump\nCommit: aaaa\nID: 4711\n")
+}
+
+func TestChangeViewNotFound(t *testing.T) {
+	ts := setupChangeServer(t)
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "no change matches 0000000000000000000000000000000000000000",
+		"change", "view", "0000000000000000000000000000000000000000")
+}
+
+func TestChangeBuilds(t *testing.T) {
+	ts := setupChangeServer(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "change", "builds", testSHA)
+	assert.Contains(t, out, "3f2a9c1 in Falcon_GitHub")
+	assert.Contains(t, out, "502")
+	assert.Contains(t, out, "release/2024.3")
+	assert.Contains(t, out, "3f2a9c1 in Falcon_Mirror")
+	assert.Contains(t, out, "Change 11 (3f2a9c1) is not in any run yet")
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "change", "builds", testSHA, "--job", "Falcon_Release", "--json")
+	var results []struct {
+		Change api.Change  `json:"change"`
+		Builds []api.Build `json:"builds"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &results))
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Builds, 1)
+	assert.Equal(t, "2024.3", results[0].Builds[0].Number)
+}
@@ -0,0 +1,131 @@
+package change
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type changeViewOptions struct {
+	job string
+	cmdutil.ViewOptions
+}
+
+func newChangeViewCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &changeViewOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "view <change-id|commit-sha>",
+		Short: "View a change: author, date, comment, and files",
+		Long: `View a change with its author, date, comment, VCS root, and changed files.
+
+When the commit is known in several VCS roots, each of its changes is shown;
+--job narrows them to the VCS roots of one job. With --json, prints an array
+of changes.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  teamcity change view 4711
+  teamcity change view 3f2a9c1
+  teamcity change view @head --job Falcon_Build
+  teamcity change view 3f2a9c1 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangeView(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Only changes in the VCS roots of this job")
+	cmdutil.AddViewFlags(cmd, &opts.ViewOptions)
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+func runChangeView(f *cmdutil.Factory, ref string, opts *changeViewOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	changes, err := resolveChanges(f, client, ref, opts.job)
+	if err != nil {
+		return err
+	}
+
+	if done, err := opts.EmitWebURL(f.Printer, changes[0].WebURL); done {
+		return err
+	}
+	if opts.JSON {
+		return f.Printer.PrintJSON(changes)
+	}
+
+	p := f.Printer
+	if len(changes) > 1 {
+		p.Info("%s is %d changes, one per VCS root", shortSHA(changes[0].Version), len(changes))
+		_, _ = fmt.Fprintln(p.Out)
+	}
+	for i, c := range changes {
+		if i > 0 {
+			_, _ = fmt.Fprintln(p.Out)
+		}
+		printChange(p, c)
+	}
+	return nil
+}
+
+func printChange(p *output.Printer, c api.Change) {
+	comment := strings.TrimSpace(c.Comment)
+	title, body, _ := strings.Cut(comment, "\n")
+	p.PrintViewHeader(cmp.Or(title, shortSHA(c.Version)), c.WebURL, func() {
+		p.PrintField("Commit", c.Version)
+		p.PrintField("ID", strconv.Itoa(c.ID))
+		p.PrintField("Author", c.Username)
+		if t, err := api.ParseTeamCityTime(c.Date); err == nil {
+			p.PrintField("Date", t.Local().Format("2006-01-02 15:04")+" "+output.Faint("("+output.RelativeTime(t)+")"))
+		}
+		if root := vcsRootName(c); root != "" {
+			p.PrintField("VCS root", root)
+		}
+		if c.ParentRevisions != nil && len(c.ParentRevisions.Item) > 1 {
+			p.PrintField("Merge of", strings.Join(shortSHAs(c.ParentRevisions.Item), ", "))
+		}
+		if body = strings.TrimSpace(body); body != "" {
+			_, _ = fmt.Fprintln(p.Out)
+			for line := range strings.Lines(body) {
+				_, _ = fmt.Fprintf(p.Out, "  %s", line)
+			}
+			_, _ = fmt.Fprintln(p.Out)
+		}
+		if c.Files == nil || len(c.Files.File) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(p.Out, "\n%s (%d)\n", output.Bold("Files"), len(c.Files.File))
+		for _, file := range c.Files.File {
+			_, _ = fmt.Fprintf(p.Out, "  %s  %s\n", fileChangeType(file.ChangeType), file.File)
+		}
+	})
+}
+
+func fileChangeType(changeType string) string {
+	switch changeType {
+	case "added":
+		return output.Green("A")
+	case "removed":
+		return output.Red("D")
+	case "edited":
+		return output.Yellow("M")
+	}
+	return "M"
+}
+
+func shortSHAs(shas []string) []string {
+	out := make([]string, len(shas))
+	for i, sha := range shas {
+		out[i] = shortSHA(sha)
+	}
+	return out
+}
@@ -2,11 +2,18 @@ package cmd_test
 
 import (
 	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmd"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
 	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,14 +26,59 @@ func TestConfig(T *testing.T) {
 	assert.NotEmpty(T, config.GetToken(), "GetToken()")
 }
 
+var limitedListCommands = [][]string{
+	{"project", "list"},
+	{"run", "list"},
+	{"job", "list"},
+	{"agent", "list"},
+	{"queue", "list"},
+	{"pipeline", "list"},
+	{"project", "vcs", "list"},
+}
+
 func TestListLimitValidation(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
 
-	cmdtest.RunCmdWithFactoryExpectErr(T, f, "--limit must not be negative", "project", "list", "--limit", "-1")
-	cmdtest.RunCmdWithFactoryExpectErr(T, f, "--limit must not be negative", "run", "list", "--limit", "-1")
-	cmdtest.RunCmdWithFactoryExpectErr(T, f, "--limit must not be negative", "job", "list", "--limit", "-2")
-	cmdtest.RunCmdWithFactoryExpectErr(T, f, "--limit must not be negative", "agent", "list", "--limit", "-5")
+	for _, args := range append(limitedListCommands, []string{"test", "flaky", "--job", "Falcon_Build"}, []string{"run", "tests", "1"}) {
+		T.Run(strings.Join(args, " "), func(T *testing.T) {
+			cmdtest.RunCmdWithFactoryExpectErr(T, f, "--limit must not be negative, got -3", append(args, "--limit", "-3")...)
+		})
+	}
+}
+
+func TestListLimitSoftCap(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		assert.NotContains(T, r.URL.Query().Get("locator"), "count:0", "--limit 0 must not ask the server for zero items")
+		cmdtest.JSON(w, api.BuildTypeList{})
+	})
+
+	tests := []struct {
+		name     string
+		env      string
+		limit    string
+		wantWarn bool
+	}{
+		{"under default cap", "", "1000", false},
+		{"above default cap", "", "1001", true},
+		{"all is not capped", "", "0", false},
+		{"custom cap", "50", "51", true},
+		{"cap disabled", "0", "100000", false},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(T *testing.T) {
+			T.Setenv(config.EnvLimitWarn, tc.env)
+			for _, args := range limitedListCommands {
+				out := cmdtest.CaptureOutput(T, ts.CloneFactory(), append(args, "--limit", tc.limit)...)
+				if tc.wantWarn {
+					assert.Contains(T, out, "--limit "+tc.limit+" is above", strings.Join(args, " "))
+				} else {
+					assert.NotContains(T, out, "is above", strings.Join(args, " "))
+				}
+			}
+		})
+	}
 }
 
 func TestHelpCommands(T *testing.T) {
@@ -80,6 +132,35 @@ func TestGlobalFlags(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "--no-color", "project", "list", "--limit", "1")
 }
 
+func TestColorOutputFormats(T *testing.T) {
+	// What InitOutput resolves for --color always, which NewCommand leaves out.
+	old := output.NoColor
+	T.Cleanup(func() { output.NoColor = old })
+	output.NoColor = false
+	ts := cmdtest.SetupMockClient(T)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--limit", "1")
+	assert.Contains(T, out, "\x1b[", "tables are colored")
+
+	for _, format := range []string{"--json", "--plain"} {
+		out = cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--limit", "1", format)
+		assert.NotContains(T, out, "\x1b", "%s output never carries escapes", format)
+	}
+}
+
+func TestGlobalTimeout(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	start := time.Now()
+	err := cmdtest.CaptureErr(T, ts.Factory, "--timeout", "100ms", "run", "list")
+	assert.ErrorIs(T, err, context.DeadlineExceeded, "the request in flight is canceled")
+	assert.True(T, ts.Factory.TimedOut())
+	assert.Less(T, time.Since(start), 5*time.Second)
+}
+
 func TestGlobalFlagMutex(T *testing.T) {
 	T.Parallel()
 
@@ -91,6 +172,7 @@ func TestGlobalFlagMutex(T *testing.T) {
 		{"verbose-debug coexist (aliases)", []string{"--verbose", "--debug", "completion", "bash"}, false},
 		{"quiet conflicts with verbose", []string{"--quiet", "--verbose", "completion", "bash"}, true},
 		{"quiet conflicts with debug", []string{"--quiet", "--debug", "completion", "bash"}, true},
+		{"color conflicts with no-color", []string{"--color", "always", "--no-color", "completion", "bash"}, true},
 	}
 
 	for _, tc := range cases {
@@ -179,3 +261,58 @@ func TestInvalidIDs(T *testing.T) {
 		})
 	}
 }
+
+func TestDryRunSendsNoMutations(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
+	var mu sync.Mutex
+	var mutations []string
+	inner := ts.Config.Handler
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			mu.Lock()
+			mutations = append(mutations, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+		inner.ServeHTTP(w, r)
+	})
+
+	commands := [][]string{
+		{"run", "start", "TestProject_Build", "--branch", "main"},
+		{"run", "cancel", "1", "--yes"},
+		{"run", "pin", "1"},
+		{"run", "unpin", "1"},
+		{"run", "tag", "1", "release"},
+		{"run", "comment", "1", "hello"},
+		{"job", "pause", "TestProject_Build"},
+		{"job", "param", "set", "TestProject_Build", "KEY", "value"},
+		{"job", "param", "delete", "TestProject_Build", "KEY"},
+		{"project", "param", "set", "TestProject", "KEY", "value"},
+		{"project", "create", "Dry", "--id", "DryProject"},
+		{"queue", "remove", "100", "--yes"},
+		{"queue", "top", "100"},
+		{"queue", "approve", "100"},
+		{"agent", "enable", "1"},
+		{"agent", "disable", "1"},
+		{"agent", "reboot", "1", "--yes"},
+		{"api", "/app/rest/builds/id:1/pin", "-X", "PUT", "-f", "comment=x"},
+	}
+
+	for _, args := range commands {
+		T.Run(strings.Join(args[:2], " "), func(t *testing.T) {
+			out := cmdtest.CaptureOutput(t, ts.Factory, append([]string{"--dry-run"}, args...)...)
+			assert.Contains(t, out, "[dry-run]")
+		})
+	}
+
+	T.Run("TC_DRY_RUN", func(t *testing.T) {
+		t.Setenv("TC_DRY_RUN", "1")
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "pin", "1", "--comment", "keep")
+		assert.Contains(t, out, "PUT /app/rest/builds/id:1/pin  keep")
+		assert.NotContains(t, out, "Pinned")
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(T, mutations)
+}
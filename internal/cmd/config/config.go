@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/url"
 	"os"
 	"slices"
 	"strings"
@@ -34,6 +35,7 @@ at runtime.`,
 	cmd.AddCommand(newListCmd(f))
 	cmd.AddCommand(newGetCmd(f))
 	cmd.AddCommand(newSetCmd(f))
+	cmd.AddCommand(newDoctorCmd(f))
 
 	return cmd
 }
@@ -59,15 +61,24 @@ func newListCmd(f *cmdutil.Factory) *cobra.Command {
 
 type configJSON struct {
 	DefaultServer string                `json:"default_server"`
+	DateFormat    string                `json:"date_format,omitempty"`
+	DefaultCache  string                `json:"default_cache,omitempty"`
 	Servers       map[string]serverJSON `json:"servers"`
 	Aliases       map[string]string     `json:"aliases"`
 	Environment   map[string]string     `json:"environment,omitempty"`
 }
 
 type serverJSON struct {
-	Guest       bool   `json:"guest"`
-	RO          bool   `json:"ro"`
-	TokenExpiry string `json:"token_expiry,omitempty"`
+	Guest         bool              `json:"guest"`
+	RO            bool              `json:"ro"`
+	TokenExpiry   string            `json:"token_expiry,omitempty"`
+	TokenCommand  string            `json:"token_command,omitempty"`
+	AllowVCSEdits bool              `json:"allow_vcs_edits,omitempty"`
+	Context       string            `json:"context,omitempty"`
+	CommitLinks   map[string]string `json:"commit_links,omitempty"`
+	NotifyWebhook string            `json:"notify_webhook,omitempty"`
+	CACert        string            `json:"ca_cert,omitempty"`
+	InsecureSkip  bool              `json:"insecure_skip_verify,omitempty"`
 }
 
 func runList(f *cmdutil.Factory, jsonOutput bool) error {
@@ -85,6 +96,12 @@ func runList(f *cmdutil.Factory, jsonOutput bool) error {
 	} else {
 		_, _ = fmt.Fprintf(p.Out, "default_server=\n")
 	}
+	if c.DateFormat != "" {
+		_, _ = fmt.Fprintf(p.Out, "date_format=%s\n", c.DateFormat)
+	}
+	if c.DefaultCache != "" {
+		_, _ = fmt.Fprintf(p.Out, "default_cache=%s\n", c.DefaultCache)
+	}
 
 	urls := cfg.SortedServerURLs(c)
 	for _, serverURL := range urls {
@@ -99,6 +116,27 @@ func runList(f *cmdutil.Factory, jsonOutput bool) error {
 		if sc.TokenExpiry != "" {
 			_, _ = fmt.Fprintf(p.Out, "  token_expiry=%s\n", sc.TokenExpiry)
 		}
+		if sc.TokenCommand != "" {
+			_, _ = fmt.Fprintf(p.Out, "  token_command=%s\n", sc.TokenCommand)
+		}
+		if sc.AllowVCSEdits {
+			_, _ = fmt.Fprintf(p.Out, "  allow_vcs_edits=%t\n", sc.AllowVCSEdits)
+		}
+		if sc.Context != "" {
+			_, _ = fmt.Fprintf(p.Out, "  context=%s\n", sc.Context)
+		}
+		if sc.NotifyWebhook != "" {
+			_, _ = fmt.Fprintf(p.Out, "  notify_webhook=%s\n", maskWebhook(sc.NotifyWebhook))
+		}
+		if sc.CACert != "" {
+			_, _ = fmt.Fprintf(p.Out, "  ca_cert=%s\n", sc.CACert)
+		}
+		if sc.InsecureSkipVerify {
+			_, _ = fmt.Fprintf(p.Out, "  insecure_skip_verify=%t\n", sc.InsecureSkipVerify)
+		}
+		for _, id := range slices.Sorted(maps.Keys(sc.CommitLinks)) {
+			_, _ = fmt.Fprintf(p.Out, "  commit_link.%s=%s\n", id, sc.CommitLinks[id])
+		}
 	}
 
 	if aliases := cfg.GetAllAliases(); len(aliases) > 0 {
@@ -112,11 +150,18 @@ func runList(f *cmdutil.Factory, jsonOutput bool) error {
 
 func printListJSON(p *output.Printer, c *cfg.Config) error {
 	servers := map[string]serverJSON{}
-	for url, sc := range c.Servers {
-		servers[url] = serverJSON{
-			Guest:       sc.Guest,
-			RO:          sc.RO,
-			TokenExpiry: sc.TokenExpiry,
+	for serverURL, sc := range c.Servers {
+		servers[serverURL] = serverJSON{
+			Guest:         sc.Guest,
+			RO:            sc.RO,
+			TokenExpiry:   sc.TokenExpiry,
+			TokenCommand:  sc.TokenCommand,
+			AllowVCSEdits: sc.AllowVCSEdits,
+			Context:       sc.Context,
+			CommitLinks:   sc.CommitLinks,
+			NotifyWebhook: maskWebhook(sc.NotifyWebhook),
+			CACert:        sc.CACert,
+			InsecureSkip:  sc.InsecureSkipVerify,
 		}
 	}
 	aliases := c.Aliases
@@ -126,6 +171,8 @@ func printListJSON(p *output.Printer, c *cfg.Config) error {
 	env := collectEnvOverrides()
 	out := configJSON{
 		DefaultServer: c.DefaultServer,
+		DateFormat:    c.DateFormat,
+		DefaultCache:  c.DefaultCache,
 		Servers:       servers,
 		Aliases:       aliases,
 	}
@@ -135,6 +182,19 @@ func printListJSON(p *output.Printer, c *cfg.Config) error {
 	return p.PrintJSON(out)
 }
 
+// maskWebhook hides all but the scheme and host of a webhook URL: chat webhooks carry their secret in the path.
+// 'config get notify_webhook' still prints it in full.
+func maskWebhook(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "****"
+	}
+	return u.Scheme + "://" + u.Host + "/****"
+}
+
 func printEnvOverrides(p *output.Printer) {
 	env := collectEnvOverrides()
 	if len(env) == 0 {
@@ -148,7 +208,7 @@ func printEnvOverrides(p *output.Printer) {
 
 func collectEnvOverrides() map[string]string {
 	env := map[string]string{}
-	for _, key := range []string{cfg.EnvServerURL, cfg.EnvToken, cfg.EnvGuestAuth, cfg.EnvReadOnly} {
+	for _, key := range []string{cfg.EnvServerURL, cfg.EnvToken, cfg.EnvTokenCommand, cfg.EnvGuestAuth, cfg.EnvReadOnly, cfg.EnvContext, cfg.EnvCACert, cfg.EnvInsecureSkipVerify} {
 		if v := os.Getenv(key); v != "" {
 			if key == cfg.EnvToken {
 				v = "****"
@@ -199,7 +259,12 @@ func newSetCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "set <key> [<value>]",
 		Short: "Set a configuration value",
-		Long:  "Set the value of a configuration key.\n\nValid keys: " + strings.Join(cfg.ValidKeys(), ", "),
+		Long: "Set the value of a configuration key.\n\nValid keys: " + strings.Join(cfg.ValidKeys(), ", ") +
+			"\n\ncommit_link.<vcs-root-id> sets a per-server commit URL template with a {sha} placeholder;\nset it to an empty string to remove it." +
+			"\n\ncontext labels every request to the server with an X-TC-CLI-Context header so\naudit logs can attribute automation; TC_CONTEXT overrides it. It must be a short\nname such as release-bot, never a secret." +
+			"\n\nnotify_webhook is a per-server URL that 'run watch --notify' posts each finished\nrun to as JSON." +
+			"\n\nca_cert is a PEM file of CA certificates trusted for the server on top of the\nsystem's; insecure_skip_verify turns certificate checks off entirely and warns\non every command. TEAMCITY_CA_CERT and TEAMCITY_INSECURE_SKIP_VERIFY override them." +
+			"\n\ndefault_cache caches API responses for this long, as with --cache, on every\ncommand that doesn't pass --cache itself; 0 turns it off.",
 		Example: `  # Switch default server (interactive picker)
   teamcity config set default_server
 
@@ -210,7 +275,22 @@ func newSetCmd(f *cmdutil.Factory) *cobra.Command {
   teamcity config set ro true --server tc.example.com
 
   # Enable guest auth for the default server
-  teamcity config set guest true`,
+  teamcity config set guest true
+
+  # Link commits of a VCS root in release notes (run changes --format markdown)
+  teamcity config set commit_link.Falcon_GitHub 'https://github.com/acme/falcon/commit/{sha}'
+
+  # Label this machine's requests in the server's audit logs
+  teamcity config set context release-bot
+
+  # Post finished runs watched with --notify to a chat webhook
+  teamcity config set notify_webhook https://hooks.slack.com/services/T000/B000/XXXX
+
+  # Trust a server whose certificate is signed by a private CA
+  teamcity config set ca_cert ~/certs/corp-root.pem --server tc.example.com
+
+  # Cache API responses for a minute on every command
+  teamcity config set default_cache 60s`,
 		Args: cobra.RangeArgs(1, 2),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			switch len(args) {
@@ -220,9 +300,12 @@ func newSetCmd(f *cmdutil.Factory) *cobra.Command {
 				if args[0] == "default_server" {
 					return completion.ConfiguredServers()(cmd, args, toComplete)
 				}
-				if args[0] == "guest" || args[0] == "ro" {
+				if args[0] == "guest" || args[0] == "ro" || args[0] == "allow_vcs_edits" || args[0] == "insecure_skip_verify" {
 					return completion.Fixed("true", "false")(cmd, args, toComplete)
 				}
+				if args[0] == "ca_cert" {
+					return nil, cobra.ShellCompDirectiveDefault
+				}
 			}
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
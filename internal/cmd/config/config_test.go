@@ -2,6 +2,8 @@ package config_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/JetBrains/teamcity-cli/internal/cmd"
@@ -89,6 +91,18 @@ func TestConfigListEnvOverrides(t *testing.T) {
 	assert.Contains(t, out, "TEAMCITY_TOKEN=****")
 }
 
+func TestConfigListMasksWebhook(t *testing.T) {
+	setupWithServer(t)
+	hook := "https://hooks.slack.com/services/T000/B000/XXXX"
+	capture(t, "config", "set", "notify_webhook", hook, "--server", "https://tc.example.com")
+
+	for _, out := range []string{capture(t, "config", "list"), capture(t, "config", "list", "--json")} {
+		assert.Contains(t, out, "https://hooks.slack.com/****")
+		assert.NotContains(t, out, "T000")
+	}
+	assert.Contains(t, capture(t, "config", "get", "notify_webhook", "--server", "https://tc.example.com"), hook)
+}
+
 func TestConfigGetDefaultServer(t *testing.T) {
 	setupWithServer(t)
 	out := capture(t, "config", "get", "default_server")
@@ -150,6 +164,29 @@ func TestConfigSetTokenExpiry(t *testing.T) {
 	assert.Contains(t, got, "2026-01-01T00:00:00Z")
 }
 
+func TestConfigSetCommitLink(t *testing.T) {
+	setupWithServer(t)
+	tpl := "https://github.com/acme/falcon/commit/{sha}"
+	capture(t, "config", "set", "commit_link.Falcon_GitHub", tpl, "--server", "https://tc.example.com")
+
+	got := capture(t, "config", "get", "commit_link.falcon_github", "--server", "https://tc.example.com")
+	assert.Contains(t, got, tpl)
+	assert.Contains(t, capture(t, "config", "list"), "commit_link.falcon_github="+tpl)
+
+	// Config keys are lowercased on read, so VCS root IDs match case-insensitively.
+	t.Setenv("TEAMCITY_URL", "https://tc.example.com")
+	assert.Equal(t, tpl, config.CommitLinkTemplate("FALCON_GITHUB"))
+
+	capture(t, "config", "set", "commit_link.Falcon_GitHub", "", "--server", "https://tc.example.com")
+	assert.Empty(t, config.CommitLinkTemplate("Falcon_GitHub"))
+}
+
+func TestConfigSetCommitLinkRequiresPlaceholder(t *testing.T) {
+	setupWithServer(t)
+	err := captureErr(t, "config", "set", "commit_link.Falcon_GitHub", "https://github.com/acme/falcon", "--server", "https://tc.example.com")
+	assert.Contains(t, err.Error(), "must contain {sha}")
+}
+
 func TestConfigListAliases(t *testing.T) {
 	setupWithServer(t)
 	require.NoError(t, config.AddAlias("rl", "run list"))
@@ -159,3 +196,29 @@ func TestConfigListAliases(t *testing.T) {
 	assert.Contains(t, out, "Aliases:")
 	assert.Contains(t, out, "2 configured")
 }
+
+func TestConfigDoctor(t *testing.T) {
+	setup(t)
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path := filepath.Join(dir, "tc", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("default_server: tc.example.com/\nservers:\n  tc.example.com/:\n    ro: true\n"), 0o600))
+	require.NoError(t, config.Init())
+
+	out := capture(t, "config", "doctor")
+	assert.Contains(t, out, "would rename tc.example.com/ to https://tc.example.com")
+	assert.Contains(t, out, "teamcity config doctor --migrate")
+
+	out = capture(t, "config", "doctor", "--migrate", "--dry-run")
+	assert.Contains(t, out, "would rename tc.example.com/ to https://tc.example.com")
+	assert.Equal(t, "tc.example.com/", config.Get().DefaultServer)
+
+	out = capture(t, "config", "doctor", "--migrate")
+	assert.Contains(t, out, "renamed tc.example.com/ to https://tc.example.com")
+	assert.Contains(t, out, "set default server tc.example.com/ to https://tc.example.com")
+	assert.Equal(t, "https://tc.example.com", config.Get().DefaultServer)
+	assert.True(t, config.Get().Servers["https://tc.example.com"].RO)
+
+	assert.Contains(t, capture(t, "config", "doctor"), "Config is up to date")
+}
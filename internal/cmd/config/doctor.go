@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	cfg "github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/version"
+	"github.com/spf13/cobra"
+)
+
+type doctorOptions struct {
+	migrate    bool
+	yes        bool
+	jsonOutput bool
+}
+
+func newDoctorCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &doctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the config file for legacy formats",
+		Long: `Check the config file for leftovers of older CLI versions and, with
+--migrate, fix them:
+
+- server entries whose URL differs only by a trailing slash or a missing
+  scheme are renamed, and duplicates merged into the one with a user set
+- tokens stored in plain text are moved into the system keyring; each is
+  verified against its server first, and the plain-text copy is removed
+  only once the keyring returns it back
+- the config file is made readable by its owner only (0600)
+
+Moving tokens asks for confirmation unless --yes is given. With --dry-run,
+--migrate only reports what it would change.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity config doctor
+  teamcity config doctor --migrate --dry-run
+  teamcity config doctor --migrate --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(f, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.migrate, "migrate", false, "Fix the problems found")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Move plaintext tokens into the keyring without asking")
+	cmd.Flags().BoolVar(&opts.jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runDoctor(f *cmdutil.Factory, opts *doctorOptions) error {
+	dryRun := !opts.migrate || f.IsDryRun()
+	moveTokens := opts.migrate
+	if tokens := cfg.PlaintextTokenServers(); opts.migrate && !dryRun && len(tokens) > 0 && !opts.yes {
+		moveTokens = false
+		if f.IsInteractive() {
+			if err := cmdutil.Confirm(fmt.Sprintf("Move %d plaintext token(s) into the system keyring?", len(tokens)), &moveTokens); err != nil {
+				return err
+			}
+		}
+	}
+
+	steps, err := cfg.Migrate(cfg.MigrateOptions{
+		DryRun:      dryRun,
+		MoveTokens:  moveTokens,
+		VerifyToken: verifyToken(f),
+	})
+	if err != nil {
+		return err
+	}
+
+	p := f.Printer
+	if opts.jsonOutput {
+		if steps == nil {
+			steps = []cfg.MigrationStep{}
+		}
+		return p.PrintJSON(steps)
+	}
+	if len(steps) == 0 {
+		p.Success("Config is up to date")
+		return nil
+	}
+	for _, s := range steps {
+		icon := output.Yellow("!")
+		switch {
+		case s.Done:
+			icon = output.Green(output.Sym().Check)
+		case dryRun && s.Kind != cfg.StepKeepToken:
+			icon = output.Faint("[dry-run]")
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s %s\n", icon, s.Detail)
+	}
+	if !opts.migrate {
+		_, _ = fmt.Fprintf(p.Out, "\nRun %s to fix\n", output.Cyan("teamcity config doctor --migrate"))
+	}
+	return nil
+}
+
+// verifyToken checks a stored token against its server and returns the username it belongs to.
+func verifyToken(f *cmdutil.Factory) func(serverURL, token string) (string, error) {
+	return func(serverURL, token string) (string, error) {
+		client := api.NewClient(serverURL, token, f.TLSOption(serverURL), api.WithDebugFunc(f.Printer.Debug), api.WithVersion(version.String())).WithContext(f.Context())
+		user, err := client.GetCurrentUser()
+		if err != nil {
+			return "", err
+		}
+		return user.Username, nil
+	}
+}
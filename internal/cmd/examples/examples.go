@@ -0,0 +1,309 @@
+package examples
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/buildkite/shellwords"
+	"github.com/spf13/cobra"
+)
+
+type examplesOptions struct {
+	run  bool
+	set  []string
+	yes  bool
+	json bool
+}
+
+// NewCmd returns the examples command. newRoot builds a fresh command tree for every step run with --run,
+// so flag values never leak from one step into the next.
+func NewCmd(f *cmdutil.Factory, newRoot func(*cmdutil.Factory) *cobra.Command) *cobra.Command {
+	opts := &examplesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "examples [name]",
+		Short: "Show runnable recipes for common workflows",
+		Long: `Show short, annotated sequences of real commands for common workflows,
+such as gating on a release or finding the change that broke a job.
+
+Without a name, lists the available recipes. With a name, prints the
+recipe's commands with <placeholders> for the values you supply.
+
+--run walks through the recipe: it asks for each placeholder (or takes it
+from --set name=value), shows each command, and runs it once you confirm.
+Steps go through the normal client, so --dry-run prints the API calls
+instead of sending them. A failing step stops the walk-through.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names := make([]cobra.Completion, len(recipes))
+			for i, r := range recipes {
+				names[i] = cobra.CompletionWithDesc(r.Name, r.Title)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		Example: `  teamcity examples
+  teamcity examples release-gate
+  teamcity examples release-gate --run
+  teamcity examples investigate-failure --run --set run=12345 --yes
+  teamcity examples drain-pool --run --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if opts.run || len(opts.set) > 0 || opts.yes {
+					return api.Validation("--run, --set, and --yes need a recipe name", "Run 'teamcity examples' to list the recipes")
+				}
+				return listRecipes(f, opts)
+			}
+			r, err := findRecipe(args[0])
+			if err != nil {
+				return err
+			}
+			if !opts.run {
+				if len(opts.set) > 0 || opts.yes {
+					return api.Validation("--set and --yes only apply with --run", "Add --run to run the recipe")
+				}
+				return showRecipe(f, r, opts)
+			}
+			if opts.json {
+				return api.MutuallyExclusive("run", "json")
+			}
+			return runRecipe(f, newRoot, r, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.run, "run", false, "Walk through the recipe, running each step after confirmation")
+	cmd.Flags().StringArrayVar(&opts.set, "set", nil, "Value for a placeholder as name=value instead of prompting (repeatable)")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "With --run, run every step without asking")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func findRecipe(name string) (*recipe, error) {
+	i := slices.IndexFunc(recipes, func(r recipe) bool { return r.Name == name })
+	if i < 0 {
+		return nil, api.Validation(
+			fmt.Sprintf("unknown recipe %q", name),
+			"Run 'teamcity examples' to list the recipes",
+		)
+	}
+	return &recipes[i], nil
+}
+
+func listRecipes(f *cmdutil.Factory, opts *examplesOptions) error {
+	p := f.Printer
+	if opts.json {
+		type recipeJSON struct {
+			Name  string `json:"name"`
+			Title string `json:"title"`
+		}
+		list := make([]recipeJSON, len(recipes))
+		for i, r := range recipes {
+			list[i] = recipeJSON{Name: r.Name, Title: r.Title}
+		}
+		return p.PrintJSON(list)
+	}
+
+	rows := make([][]string, len(recipes))
+	for i, r := range recipes {
+		rows[i] = []string{r.Name, r.Title}
+	}
+	p.PrintTable([]string{"NAME", "DESCRIPTION"}, rows)
+	_, _ = fmt.Fprintln(p.Out)
+	p.Tip("Run 'teamcity examples <name>' to see a recipe, and add --run to walk through it")
+	return nil
+}
+
+func showRecipe(f *cmdutil.Factory, r *recipe, opts *examplesOptions) error {
+	p := f.Printer
+	if opts.json {
+		return p.PrintJSON(recipeJSON(r))
+	}
+
+	_, _ = fmt.Fprintln(p.Out, output.Cyan(r.Title))
+	_, _ = fmt.Fprintln(p.Out, r.Summary)
+	for _, s := range r.Steps {
+		_, _ = fmt.Fprintln(p.Out)
+		_, _ = fmt.Fprintln(p.Out, "  "+output.Faint("# "+s.Note))
+		_, _ = fmt.Fprintln(p.Out, "  "+commandLine(s.Args))
+	}
+	if len(r.Params) > 0 {
+		_, _ = fmt.Fprintln(p.Out)
+		_, _ = fmt.Fprintln(p.Out, "Placeholders:")
+		width := 0
+		for _, prm := range r.Params {
+			width = max(width, len(prm.Name)+2)
+		}
+		for _, prm := range r.Params {
+			_, _ = fmt.Fprintf(p.Out, "  %-*s  %s\n", width, "<"+prm.Name+">", prm.Prompt)
+		}
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	p.Tip("Run 'teamcity examples %s --run' to fill in the placeholders and run each step", r.Name)
+	return nil
+}
+
+// recipeJSON is the --json form of a recipe: its commands as they would be typed, placeholders included.
+func recipeJSON(r *recipe) any {
+	type stepJSON struct {
+		Note    string `json:"note"`
+		Command string `json:"command"`
+	}
+	type paramJSON struct {
+		Name   string `json:"name"`
+		Prompt string `json:"prompt"`
+	}
+	steps := make([]stepJSON, len(r.Steps))
+	for i, s := range r.Steps {
+		steps[i] = stepJSON{Note: s.Note, Command: commandLine(s.Args)}
+	}
+	params := make([]paramJSON, len(r.Params))
+	for i, prm := range r.Params {
+		params[i] = paramJSON{Name: prm.Name, Prompt: prm.Prompt}
+	}
+	return struct {
+		Name         string      `json:"name"`
+		Title        string      `json:"title"`
+		Summary      string      `json:"summary"`
+		Placeholders []paramJSON `json:"placeholders"`
+		Steps        []stepJSON  `json:"steps"`
+	}{r.Name, r.Title, r.Summary, params, steps}
+}
+
+// commandLine renders args as a teamcity command line, quoting arguments that are not a bare placeholder.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if isPlaceholder(a) {
+			quoted[i] = a
+		} else {
+			quoted[i] = shellwords.QuotePosix(a)
+		}
+	}
+	return "teamcity " + strings.Join(quoted, " ")
+}
+
+func isPlaceholder(s string) bool {
+	return len(s) > 2 && s[0] == '<' && s[len(s)-1] == '>' && !strings.ContainsAny(s[1:len(s)-1], "<> ")
+}
+
+// substitute replaces every <name> in args with its value.
+func substitute(args []string, values map[string]string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		for name, v := range values {
+			a = strings.ReplaceAll(a, "<"+name+">", v)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// placeholderValues collects a value for every placeholder of r from --set, prompting for the rest.
+func placeholderValues(f *cmdutil.Factory, r *recipe, set []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, kv := range set {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set %q, must be name=value", kv)
+		}
+		if !slices.ContainsFunc(r.Params, func(prm param) bool { return prm.Name == name }) {
+			return nil, api.Validation(
+				fmt.Sprintf("recipe %s has no placeholder <%s>", r.Name, name),
+				fmt.Sprintf("Run 'teamcity examples %s' to see its placeholders", r.Name),
+			)
+		}
+		values[name] = value
+	}
+
+	var missing []string
+	for _, prm := range r.Params {
+		if _, ok := values[prm.Name]; !ok {
+			missing = append(missing, prm.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+	if !f.IsInteractive() {
+		return nil, api.Validation(
+			"no value for "+placeholderList(missing),
+			fmt.Sprintf("Pass --set %s=<value> for each placeholder, or run in an interactive terminal", missing[0]),
+		)
+	}
+	for _, prm := range r.Params {
+		if !slices.Contains(missing, prm.Name) {
+			continue
+		}
+		var v string
+		if err := cmdutil.PromptString(f.Printer, prm.Prompt, "<"+prm.Name+">", &v); err != nil {
+			return nil, err
+		}
+		values[prm.Name] = strings.TrimSpace(v)
+	}
+	return values, nil
+}
+
+func placeholderList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "<" + n + ">"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func runRecipe(f *cmdutil.Factory, newRoot func(*cmdutil.Factory) *cobra.Command, r *recipe, opts *examplesOptions) error {
+	p := f.Printer
+	if !opts.yes && !f.IsInteractive() {
+		return api.Validation("--run asks before each step", "Pass --yes to run every step without asking")
+	}
+	values, err := placeholderValues(f, r, opts.set)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(p.Out, output.Cyan(r.Title))
+	for i, s := range r.Steps {
+		args := substitute(s.Args, values)
+		_, _ = fmt.Fprintln(p.Out)
+		_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint(fmt.Sprintf("Step %d/%d:", i+1, len(r.Steps))), s.Note)
+		_, _ = fmt.Fprintln(p.Out, "  "+commandLine(args))
+
+		if !opts.yes {
+			run := true
+			if err := cmdutil.Confirm("Run this step?", &run); err != nil {
+				return err
+			}
+			if !run {
+				p.Info("Skipped")
+				continue
+			}
+		}
+		if err := runStep(f, newRoot, args); err != nil {
+			return fmt.Errorf("step %d (%s) failed: %w", i+1, commandLine(args), err)
+		}
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	p.Success("Finished %s", r.Name)
+	return nil
+}
+
+// runStep executes one step on a fresh command tree that shares f's client, output, and global flags.
+func runStep(f *cmdutil.Factory, newRoot func(*cmdutil.Factory) *cobra.Command, args []string) error {
+	child := f.Child(&cmdutil.IOStreams{In: f.IOStreams.In, Out: f.Printer.Out, ErrOut: f.Printer.ErrOut})
+	child.NoInput = f.NoInput // steps may prompt when we may
+	root := newRoot(child)
+	root.SetArgs(args)
+	root.SetIn(child.IOStreams.In)
+	root.SetOut(child.IOStreams.Out)
+	root.SetErr(child.IOStreams.ErrOut)
+	root.SetContext(f.Context())
+	root.SilenceErrors, root.SilenceUsage = true, true
+	return root.Execute()
+}
@@ -0,0 +1,97 @@
+package examples_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/examples"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExamplesList(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "examples")
+	for name := range examples.SampleArgs() {
+		assert.Contains(T, got, name)
+	}
+	assert.Contains(T, got, "teamcity examples <name>")
+}
+
+func TestExamplesShow(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "examples", "release-gate")
+	assert.Contains(T, got, "Trigger a release and gate on its result")
+	assert.Contains(T, got, "# Queue the release and wait for it")
+	assert.Contains(T, got, "teamcity run start <job> --branch <branch> --watch --timeout 1h")
+	assert.Contains(T, got, "<branch>  Branch to release")
+	assert.Contains(T, got, "teamcity examples release-gate --run")
+
+	var recipe struct {
+		Placeholders []struct{ Name string }
+		Steps        []struct{ Command string }
+	}
+	require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "examples", "release-gate", "--json")), &recipe))
+	assert.Len(T, recipe.Placeholders, 2)
+	assert.Equal(T, "teamcity queue list --job <job>", recipe.Steps[0].Command)
+}
+
+func TestExamplesErrors(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `unknown recipe "nope"`, "examples", "nope")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "need a recipe name", "examples", "--run")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "only apply with --run", "examples", "release-gate", "--set", "job=X")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "has no placeholder <nope>", "examples", "release-gate", "--run", "--yes", "--set", "nope=1")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "no value for <branch>", "examples", "release-gate", "--run", "--yes", "--set", "job=X")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "asks before each step", "examples", "release-gate", "--run")
+}
+
+func TestExamplesRunEveryRecipe(T *testing.T) {
+	for name, set := range examples.SampleArgs() {
+		T.Run(name, func(T *testing.T) {
+			ts := cmdtest.SetupMockClient(T)
+			// Runs queued by a recipe finish at once, so watching them returns.
+			ts.Handle("GET /app/rest/builds/id:100", func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.JSON(w, api.Build{ID: 100, Number: "100", Status: "SUCCESS", State: "finished", BuildTypeID: "TestProject_Build"})
+			})
+
+			args := append([]string{"examples", name, "--run", "--yes"}, set...)
+			got := cmdtest.CaptureOutput(T, ts.Factory, args...)
+			assert.Contains(T, got, "Step 1/")
+			assert.Contains(T, got, "Finished "+name)
+			for line := range strings.Lines(got) {
+				if strings.HasPrefix(line, "  teamcity ") {
+					assert.NotContains(T, line, "<", "every placeholder is filled in")
+				}
+			}
+		})
+	}
+}
+
+func TestExamplesRunStopsOnFailure(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "step 1 (teamcity run view 999999999) failed",
+		"examples", "investigate-failure", "--run", "--yes", "--set", "run=999999999")
+}
+
+func TestExamplesRunDryRun(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var disabled atomic.Bool
+	ts.Handle("PUT /app/rest/agents/id:1/enabledInfo", func(w http.ResponseWriter, r *http.Request) {
+		disabled.Store(true)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "examples", "drain-pool", "--run", "--yes", "--dry-run", "--set", "pool=0", "--set", "agent=1")
+	assert.Contains(T, got, "Finished drain-pool")
+	assert.False(T, disabled.Load(), "--dry-run reaches the steps")
+}
@@ -0,0 +1,14 @@
+package examples
+
+// SampleArgs returns, per recipe, the --set flags that fill every placeholder with its sample value.
+func SampleArgs() map[string][]string {
+	args := make(map[string][]string, len(recipes))
+	for _, r := range recipes {
+		set := []string{}
+		for _, prm := range r.Params {
+			set = append(set, "--set", prm.Name+"="+prm.Sample)
+		}
+		args[r.Name] = set
+	}
+	return args
+}
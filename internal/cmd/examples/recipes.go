@@ -0,0 +1,99 @@
+package examples
+
+// recipe is a named scenario: a short, annotated sequence of real commands whose arguments may hold
+// <placeholder>s. Recipes are data rather than prose so tests can run every step against the mock server.
+type recipe struct {
+	Name    string
+	Title   string
+	Summary string
+	Params  []param
+	Steps   []step
+}
+
+// param is a placeholder the steps of a recipe use; Sample is the value tests run the recipe with.
+type param struct {
+	Name   string
+	Prompt string
+	Sample string
+}
+
+// step is one command of a recipe, without the leading "teamcity".
+type step struct {
+	Note string
+	Args []string
+}
+
+var recipes = []recipe{
+	{
+		Name:    "release-gate",
+		Title:   "Trigger a release and gate on its result",
+		Summary: "Queue the release job, wait for it to finish, and stop when it fails; the watch exits non-zero, so a script can gate on it.",
+		Params: []param{
+			{Name: "job", Prompt: "Release job ID", Sample: "TestProject_Build"},
+			{Name: "branch", Prompt: "Branch to release", Sample: "main"},
+		},
+		Steps: []step{
+			{Note: "Check that no release of the job is already waiting", Args: []string{"queue", "list", "--job", "<job>"}},
+			{Note: "Queue the release and wait for it; a failed run stops here", Args: []string{"run", "start", "<job>", "--branch", "<branch>", "--watch", "--timeout", "1h"}},
+			{Note: "List what the release produced", Args: []string{"run", "artifacts", "--job", "<job>"}},
+		},
+	},
+	{
+		Name:    "investigate-failure",
+		Title:   "Find out why a run failed",
+		Summary: "Start from the run's summary, then read the failure, the failed tests, and the changes it picked up.",
+		Params: []param{
+			{Name: "run", Prompt: "ID of the failed run", Sample: "1"},
+		},
+		Steps: []step{
+			{Note: "See the status, agent, and trigger", Args: []string{"run", "view", "<run>"}},
+			{Note: "Read the build problems and the failed tests' output", Args: []string{"run", "log", "<run>", "--failed"}},
+			{Note: "List the failed tests", Args: []string{"run", "tests", "<run>", "--failed"}},
+			{Note: "List the commits the run built", Args: []string{"run", "changes", "<run>", "--no-files"}},
+		},
+	},
+	{
+		Name:    "bisect-failure",
+		Title:   "Find the change that broke a job",
+		Summary: "Locate the first failed run after a green one, then compare the two to narrow down the culprit.",
+		Params: []param{
+			{Name: "job", Prompt: "Job ID", Sample: "TestProject_Build"},
+			{Name: "good", Prompt: "ID of the last successful run", Sample: "1"},
+			{Name: "run", Prompt: "ID of the first failed run", Sample: "2"},
+		},
+		Steps: []step{
+			{Note: "Find the first failed run after the last successful one", Args: []string{"run", "bisect", "--job", "<job>"}},
+			{Note: "Compare the two runs: status, tests, parameters, and changes", Args: []string{"run", "diff", "<good>", "<run>"}},
+			{Note: "List the commits only the failed run has", Args: []string{"run", "changes", "<run>", "--no-files"}},
+			{Note: "Read its failure summary", Args: []string{"run", "log", "<run>", "--failed"}},
+		},
+	},
+	{
+		Name:    "drain-pool",
+		Title:   "Drain an agent pool for maintenance",
+		Summary: "Stop the pool's agents from taking new runs while their current runs finish.",
+		Params: []param{
+			{Name: "pool", Prompt: "Pool ID", Sample: "0"},
+			{Name: "agent", Prompt: "Agent name or ID to disable", Sample: "1"},
+		},
+		Steps: []step{
+			{Note: "See the pool's agents and the projects it serves", Args: []string{"pool", "view", "<pool>"}},
+			{Note: "List the agents still taking runs", Args: []string{"agent", "list", "--pool", "<pool>", "--enabled"}},
+			{Note: "Disable each agent; its current run finishes, new runs go elsewhere", Args: []string{"agent", "disable", "<agent>"}},
+			{Note: "Watch the queue for runs left without a compatible agent", Args: []string{"queue", "list"}},
+		},
+	},
+	{
+		Name:    "flaky-test",
+		Title:   "Track down a flaky test",
+		Summary: "Find the tests that flip between passing and failing, then follow one across runs.",
+		Params: []param{
+			{Name: "job", Prompt: "Job ID", Sample: "TestProject_Build"},
+			{Name: "test", Prompt: "Test name", Sample: "com.example.FooTest.testBar"},
+		},
+		Steps: []step{
+			{Note: "Rank the job's tests by how often they flip", Args: []string{"test", "flaky", "--job", "<job>"}},
+			{Note: "Follow one test across the job's runs", Args: []string{"run", "tests", "--job", "<job>", "--test", "<test>"}},
+		},
+	},
+}
@@ -0,0 +1,41 @@
+package examples
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var placeholderRe = regexp.MustCompile(`<([^<> ]+)>`)
+
+func TestRecipesDeclareTheirPlaceholders(T *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range recipes {
+		T.Run(r.Name, func(T *testing.T) {
+			assert.False(T, seen[r.Name], "duplicate recipe name")
+			seen[r.Name] = true
+			assert.NotEmpty(T, r.Title)
+			assert.NotEmpty(T, r.Summary)
+			assert.NotEmpty(T, r.Steps)
+
+			used := map[string]bool{}
+			for _, s := range r.Steps {
+				assert.NotEmpty(T, s.Note)
+				assert.NotEqual(T, "teamcity", s.Args[0], "steps leave out the leading teamcity")
+				for _, a := range s.Args {
+					for _, m := range placeholderRe.FindAllStringSubmatch(a, -1) {
+						used[m[1]] = true
+						assert.True(T, slices.ContainsFunc(r.Params, func(p param) bool { return p.Name == m[1] }), "<%s> is not declared", m[1])
+					}
+				}
+			}
+			for _, p := range r.Params {
+				assert.True(T, used[p.Name], "<%s> is declared but never used", p.Name)
+				assert.NotEmpty(T, p.Prompt)
+				assert.NotEmpty(T, p.Sample)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+// artifactUsageWorkers caps how many runs have their artifact trees listed at once.
+const artifactUsageWorkers = 8
+
+// ArtifactUsage is the artifact storage of a job's recent runs, newest first. Sizes are in bytes.
+type ArtifactUsage struct {
+	Job       string             `json:"job"`
+	Runs      []RunArtifactUsage `json:"runs"`
+	TotalSize int64              `json:"totalSize"`
+	// Change is the newest run's size minus the oldest run's.
+	Change int64 `json:"change"`
+}
+
+// RunArtifactUsage is one run's artifact total. Change is the difference from the previous, older run in the
+// window; the oldest run has none.
+type RunArtifactUsage struct {
+	ID         int    `json:"id"`
+	Number     string `json:"number"`
+	Branch     string `json:"branch,omitempty"`
+	FinishDate string `json:"finishDate,omitempty"`
+	Files      int    `json:"files"`
+	Size       int64  `json:"size"`
+	Change     *int64 `json:"change,omitempty"`
+}
+
+type jobArtifactUsageOptions struct {
+	last   int
+	branch string
+	json   bool
+}
+
+func newJobArtifactUsageCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &jobArtifactUsageOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "artifact-usage [job-id]",
+		Short: "Show how much artifact storage a job's recent runs use",
+		Long: `Sum the artifact sizes of a job's last finished runs.
+
+Lists every artifact of each run, in all subdirectories, and prints the
+per-run totals newest first with the change from the run before, followed
+by the grand total and how the size moved across the window. Runs are
+listed a few at a time to spare the server.
+
+With no argument, uses the linked default job from teamcity.toml.`,
+		Example: `  teamcity job artifact-usage Falcon_Build
+  teamcity job artifact-usage Falcon_Build --last 50 --branch main
+  teamcity job artifact-usage Falcon_Build --json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.Jobs(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
+			if err != nil {
+				return err
+			}
+			return runJobArtifactUsage(f, jobID, opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.last, "last", "n", 20, "Number of finished runs to sum")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Only consider runs on this branch")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runJobArtifactUsage(f *cmdutil.Factory, jobID string, opts *jobArtifactUsageOptions) error {
+	if opts.last < 1 {
+		return api.Validation(fmt.Sprintf("--last must be at least 1, got %d", opts.last), "Pass the number of recent runs to sum, e.g. --last 20")
+	}
+
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	builds, _, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+		BuildTypeID: jobID,
+		Branch:      opts.branch,
+		State:       "finished",
+		Limit:       opts.last,
+		Fields:      []string{"id", "number", "branchName", "finishDate"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	usage, err := collectArtifactUsage(f, client, builds.Builds)
+	if err != nil {
+		return err
+	}
+	usage.Job = jobID
+
+	if opts.json {
+		return p.PrintJSON(usage)
+	}
+
+	if len(usage.Runs) == 0 {
+		p.Empty(fmt.Sprintf("No finished runs of %s", jobID), "Check the job ID, or drop --branch")
+		return nil
+	}
+
+	headers := []string{"RUN", "BRANCH", "FINISHED", "FILES", "SIZE", "CHANGE"}
+	rows := make([][]string, 0, len(usage.Runs))
+	for _, r := range usage.Runs {
+		finished := "-"
+		if t, err := api.ParseTeamCityTime(r.FinishDate); err == nil {
+			finished = output.RelativeTime(t)
+		}
+		branch, change := "-", "-"
+		if r.Branch != "" {
+			branch = r.Branch
+		}
+		if r.Change != nil {
+			change = formatSizeChange(*r.Change)
+		}
+		rows = append(rows, []string{
+			"#" + r.Number,
+			branch,
+			finished,
+			strconv.Itoa(r.Files),
+			humanize.IBytes(uint64(r.Size)),
+			change,
+		})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1)
+	p.PrintTable(headers, rows)
+
+	runs := len(usage.Runs)
+	_, _ = fmt.Fprintf(p.Out, "\nTotal: %s across %s (%s per run on average)\n",
+		humanize.IBytes(uint64(usage.TotalSize)), english.Plural(runs, "run", ""), humanize.IBytes(uint64(usage.TotalSize/int64(runs))))
+	if runs > 1 {
+		newest, oldest := usage.Runs[0], usage.Runs[runs-1]
+		trend := formatSizeChange(usage.Change)
+		if oldest.Size > 0 {
+			trend += fmt.Sprintf(" (%+.0f%%)", float64(usage.Change)*100/float64(oldest.Size))
+		}
+		_, _ = fmt.Fprintf(p.Out, "Trend: %s from #%s to #%s\n", trend, oldest.Number, newest.Number)
+	}
+	return nil
+}
+
+// collectArtifactUsage lists each run's artifacts with at most artifactUsageWorkers runs in flight (fewer once the
+// server throttles), reporting progress on a terminal. runs are newest first.
+func collectArtifactUsage(f *cmdutil.Factory, client api.ClientInterface, runs []api.Build) (*ArtifactUsage, error) {
+	usage := &ArtifactUsage{Runs: make([]RunArtifactUsage, len(runs))}
+	errs := make([]error, len(runs))
+
+	showProgress := !f.Printer.Quiet && output.IsTerminal()
+	var mu sync.Mutex
+	done := 0
+
+	limiter := f.NewLimiter(artifactUsageWorkers)
+	var wg sync.WaitGroup
+	for i, b := range runs {
+		wg.Go(func() {
+			limiter.Acquire()
+			defer limiter.Release()
+
+			files, size, err := cmdutil.FetchAllArtifacts(f.Context(), client, strconv.Itoa(b.ID), "")
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to list artifacts of run %d: %w", b.ID, err)
+			}
+			usage.Runs[i] = RunArtifactUsage{ID: b.ID, Number: b.Number, Branch: b.BranchName, FinishDate: b.FinishDate, Files: len(files), Size: size}
+
+			if showProgress {
+				mu.Lock()
+				done++
+				_, _ = fmt.Fprintf(f.Printer.ErrOut, "\rListing artifacts... %d/%d", done, len(runs))
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if showProgress && len(runs) > 0 {
+		output.ClearLine(f.Printer.ErrOut)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range usage.Runs {
+		usage.TotalSize += usage.Runs[i].Size
+		if i+1 < len(usage.Runs) {
+			change := usage.Runs[i].Size - usage.Runs[i+1].Size
+			usage.Runs[i].Change = &change
+		}
+	}
+	if n := len(usage.Runs); n > 0 {
+		usage.Change = usage.Runs[0].Size - usage.Runs[n-1].Size
+	}
+	return usage, nil
+}
+
+// formatSizeChange formats a size difference with its sign, e.g. +12 MiB or -3.0 KiB.
+func formatSizeChange(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + humanize.IBytes(uint64(delta))
+	case delta < 0:
+		return "-" + humanize.IBytes(uint64(-delta))
+	}
+	return "0 B"
+}
@@ -1,7 +1,9 @@
 package job
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
@@ -21,20 +23,34 @@ func newJobCreateCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &jobCreateOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "create <name>",
+		Use:   "create [project-id] <name>",
 		Short: "Create a job",
 		Long: `Create a new job (build configuration) in a project.
 
 If --id is omitted, TeamCity derives the job ID from the name.
-The parent project is taken from --project, the TEAMCITY_PROJECT
-environment variable, or the linked project (see 'teamcity link').`,
-		Example: `  teamcity job create Build --project MyProject
+The parent project is the first argument when two are given, or else
+is taken from --project, the TEAMCITY_PROJECT environment variable, or
+the linked project (see 'teamcity link').
+
+The new job's ID and web URL are printed, or the job itself with --json.
+A taken ID, or a name already used in the project, is an error. Add
+build steps with 'teamcity job step add'.`,
+		Example: `  teamcity job create MyProject Build
+  teamcity job create Build --project MyProject
   teamcity job create Build --project MyProject --id MyProject_Build
   teamcity job create Build --project MyProject --template MyTemplate
   teamcity job create Build --project MyProject --json`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Projects()),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runJobCreate(f, args[0], opts)
+			name := args[len(args)-1]
+			if len(args) == 2 {
+				if opts.project != "" {
+					return api.MutuallyExclusive("project-id", "project")
+				}
+				opts.project = args[0]
+			}
+			return runJobCreate(f, name, opts)
 		},
 	}
 
@@ -45,7 +61,7 @@ environment variable, or the linked project (see 'teamcity link').`,
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser after creation")
 	cmd.MarkFlagsMutuallyExclusive("json", "web")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -71,6 +87,12 @@ func runJobCreate(f *cmdutil.Factory, name string, opts *jobCreateOptions) error
 
 	job, err := client.CreateBuildType(projectID, req)
 	if err != nil {
+		// The server's refusal of a taken ID or name is hard to read; say which it was when that is the cause.
+		if _, ok := errors.AsType[*api.HTTPError](err); ok {
+			if cerr := checkNewJob(client, projectID, opts.id, name); cerr != nil {
+				return cerr
+			}
+		}
 		return fmt.Errorf("failed to create job: %w", err)
 	}
 
@@ -97,3 +119,33 @@ func runJobCreate(f *cmdutil.Factory, name string, opts *jobCreateOptions) error
 
 	return nil
 }
+
+// checkNewJob rejects a new job whose ID is taken or whose name is already used in projectID.
+func checkNewJob(client api.ClientInterface, projectID, id, name string) error {
+	if id != "" {
+		_, err := client.GetBuildType(id)
+		if err == nil {
+			return api.Validation(
+				fmt.Sprintf("job ID %q already exists", id),
+				"Choose another --id, or omit it to let TeamCity derive one from the name",
+			)
+		}
+		if _, ok := errors.AsType[*api.NotFoundError](err); !ok {
+			return err
+		}
+	}
+
+	jobs, _, err := client.GetBuildTypes(api.BuildTypesOptions{Project: projectID, Limit: 10000})
+	if err != nil {
+		return fmt.Errorf("failed to check project %s: %w", projectID, err)
+	}
+	for _, j := range jobs.BuildTypes {
+		if j.ProjectID == projectID && strings.EqualFold(j.Name, name) {
+			return api.Validation(
+				fmt.Sprintf("project %s already has a job named %q (id: %s)", projectID, j.Name, j.ID),
+				"Choose another name, or a different project",
+			)
+		}
+	}
+	return nil
+}
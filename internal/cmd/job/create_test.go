@@ -101,3 +101,37 @@ func TestJobCreateServerError(T *testing.T) {
 
 	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "failed to create job", "job", "create", "Build", "--project", "MyProject")
 }
+
+func TestJobCreateProjectArg(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	var captured []byte
+	handleBuildTypeCreate(ts, &captured)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "create", "MyProject", "Build")
+	assert.Contains(T, out, "https://tc.example.com/buildConfiguration/MyProject_Build")
+
+	var payload struct {
+		Name    string `json:"name"`
+		Project struct {
+			ID string `json:"id"`
+		} `json:"project"`
+	}
+	require.NoError(T, json.Unmarshal(captured, &payload))
+	assert.Equal(T, "Build", payload.Name)
+	assert.Equal(T, "MyProject", payload.Project.ID)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "cannot specify both", "job", "create", "MyProject", "Build", "--project", "Other")
+}
+
+func TestJobCreateTaken(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("POST /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusBadRequest, `The build configuration / template ID "TestProject_Build" is already used by another configuration or template`)
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `job ID "TestProject_Build" already exists`,
+		"job", "create", "TestProject", "Other", "--id", "TestProject_Build")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `project TestProject already has a job named "Build"`,
+		"job", "create", "TestProject", "build")
+}
@@ -28,6 +28,9 @@ See: https://www.jetbrains.com/help/teamcity/creating-and-editing-build-configur
 	cmd.AddCommand(newJobListCmd(f))
 	cmd.AddCommand(newJobViewCmd(f))
 	cmd.AddCommand(newJobTreeCmd(f))
+	cmd.AddCommand(newJobTokensCmd(f))
+	cmd.AddCommand(newJobArtifactUsageCmd(f))
+	cmd.AddCommand(newJobPruneBranchesCmd(f))
 	cmd.AddCommand(newJobPauseCmd(f))
 	cmd.AddCommand(newJobResumeCmd(f))
 	cmd.AddCommand(newJobStepCmd(f))
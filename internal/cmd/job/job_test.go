@@ -1,16 +1,26 @@
 package job_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/job"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 )
 
 const testJob = "TestProject_Build"
@@ -22,6 +32,9 @@ func TestJobList(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "job", "list", "--limit", "5")
 	cmdtest.RunCmdWithFactory(T, f, "job", "list", "--project", "TestProject")
 	cmdtest.RunCmdWithFactory(T, f, "job", "list", "--json", "--limit", "2")
+
+	got := cmdtest.CaptureOutput(T, f, "job", "list", "--template", "{{.ProjectID}}/{{.Name}}")
+	assert.Equal(T, "TestProject/Build\n", got)
 }
 
 // TestJobListLimitZero guards the post-filter bug where `--limit 0` sliced the result to [:0] instead of fetching all.
@@ -44,6 +57,201 @@ func TestJobListLimitZero(T *testing.T) {
 	assert.Equal(T, 3, list.Count)
 }
 
+// handlePagedJobs serves n jobs a locator page at a time, following count/start like TeamCity, and counts requests.
+func handlePagedJobs(ts *cmdtest.TestServer, n int, requests *int) {
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		var count, start int
+		for part := range strings.SplitSeq(r.URL.Query().Get("locator"), ",") {
+			if v, ok := strings.CutPrefix(part, "count:"); ok {
+				count, _ = strconv.Atoi(v)
+			}
+			if v, ok := strings.CutPrefix(part, "start:"); ok {
+				start, _ = strconv.Atoi(v)
+			}
+		}
+		page := api.BuildTypeList{}
+		for i := start; i < min(start+count, n); i++ {
+			page.BuildTypes = append(page.BuildTypes, api.BuildType{ID: fmt.Sprintf("P_Job%d", i), Name: fmt.Sprintf("Job %d", i), ProjectID: "P"})
+		}
+		page.Count = len(page.BuildTypes)
+		if start+count < n {
+			page.NextHref = fmt.Sprintf("/app/rest/buildTypes?locator=count:%d,start:%d", count, start+count)
+		}
+		cmdtest.JSON(w, page)
+	})
+}
+
+func TestJobListPaged(T *testing.T) {
+	T.Run("table streams every page and ends with a count", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		requests := 0
+		handlePagedJobs(ts, 5, &requests)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "list", "--limit", "0", "--page-size", "2")
+		assert.Equal(t, 3, requests)
+		assert.Regexp(t, `ID\s+NAME\s+PROJECT\s+STATUS`, out)
+		for i := range 5 {
+			assert.Contains(t, out, fmt.Sprintf("P_Job%d", i))
+		}
+		assert.Contains(t, out, "5 jobs")
+	})
+
+	T.Run("json merges the pages into one list", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		requests := 0
+		handlePagedJobs(ts, 5, &requests)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "list", "--limit", "0", "--page-size", "2", "--json")
+		var list api.BuildTypeList
+		require.NoError(t, json.Unmarshal([]byte(out), &list))
+		assert.Equal(t, 5, list.Count)
+		require.Len(t, list.BuildTypes, 5)
+		assert.Equal(t, "P_Job4", list.BuildTypes[4].ID)
+	})
+
+	T.Run("limit stops paging", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		requests := 0
+		handlePagedJobs(ts, 5, &requests)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "list", "--limit", "3", "--page-size", "2", "--plain", "--no-header")
+		assert.Equal(t, 2, requests)
+		assert.Equal(t, 3, strings.Count(out, "P_Job"))
+		assert.NotContains(t, out, "3 jobs", "plain output has no footer")
+	})
+
+	T.Run("page size must be positive", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "--page-size must be positive", "job", "list", "--page-size", "0")
+	})
+}
+
+func TestJobListJSONPageFails(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("locator"), "start:") {
+			cmdtest.Error(w, http.StatusInternalServerError, "database is down")
+			return
+		}
+		cmdtest.JSON(w, api.BuildTypeList{Count: 2, NextHref: "/app/rest/buildTypes?locator=count:2,start:2", BuildTypes: []api.BuildType{
+			{ID: "P_A", Name: "A", ProjectName: "P"},
+			{ID: "P_B", Name: "B", ProjectName: "P"},
+		}})
+	})
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "job", "list", "--limit", "0", "--page-size", "2", "--sort", "none", "--json")
+	assert.Contains(T, err.Error(), "database is down")
+	var list api.BuildTypeList
+	require.NoError(T, json.NewDecoder(ts.Factory.Printer.Out.(*bytes.Buffer)).Decode(&list), "the pages written before the failure stay valid JSON")
+	assert.Len(T, list.BuildTypes, 2)
+}
+
+func TestJobListInterrupted(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ctx, cancel := context.WithCancel(T.Context())
+	defer cancel()
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("locator"), "start:") {
+			cancel() // Ctrl-C while the second page is in flight
+			<-r.Context().Done()
+			return
+		}
+		cmdtest.JSON(w, api.BuildTypeList{Count: 2, NextHref: "/app/rest/buildTypes?locator=count:2,start:2", BuildTypes: []api.BuildType{
+			{ID: "P_B", Name: "B", ProjectName: "P"},
+			{ID: "P_A", Name: "A", ProjectName: "P"},
+		}})
+	})
+	f := ts.Factory
+	f.SetContext(ctx)
+	f.ClientFunc = func() (api.ClientInterface, error) {
+		return api.NewClient(ts.URL, "test-token").WithContext(f.Context()), nil
+	}
+
+	err := cmdtest.CaptureErr(T, f, "job", "list", "--limit", "0", "--page-size", "2", "--plain", "--no-header")
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	require.True(T, ok, "an interrupted list exits as cancelled: %v", err)
+	assert.Equal(T, cmdutil.ExitCancelled, exitErr.Code)
+	out := f.Printer.Out.(*bytes.Buffer).String()
+	assert.Regexp(T, `(?s)P_A.*P_B`, out, "the sorted list shows the jobs that arrived")
+	assert.Contains(T, out, "Interrupted - showing the 2 jobs fetched so far")
+}
+
+func TestJobListSort(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 3, NextHref: "/app/rest/buildTypes?locator=start:3", BuildTypes: []api.BuildType{
+			{ID: "P_Deploy", Name: "deploy", ProjectName: "P"},
+			{ID: "P_build", Name: "Build", ProjectName: "P", Paused: true},
+			{ID: "A_Test", Name: "test", ProjectName: "A"},
+		}})
+	})
+	ids := func(out string) string {
+		var got []string
+		for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 && strings.Contains(fields[0], "_") {
+				got = append(got, fields[0])
+			}
+		}
+		return strings.Join(got, " ")
+	}
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "-n", "3", "--plain", "--no-header")
+	assert.Equal(T, "A_Test P_build P_Deploy", ids(out), "jobs sort by ID by default")
+	assert.Contains(T, out, "Showing only the first 3 results")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "-n", "3", "--plain", "--no-header", "--sort", "status,name", "--desc")
+	assert.Equal(T, "P_build A_Test P_Deploy", ids(out))
+	assert.Contains(T, out, "Sorted only the first 3 results fetched - use --limit 0 to fetch and sort all")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "-n", "3", "--plain", "--no-header", "--sort", "none")
+	assert.Equal(T, "P_Deploy P_build A_Test", ids(out), "--sort none keeps the server's order")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "-n", "3", "--json=id,name", "--sort", "name")
+	var list api.BuildTypeList
+	require.NoError(T, json.Unmarshal([]byte(out[:strings.LastIndex(out, "}")+1]), &list))
+	require.Len(T, list.BuildTypes, 3)
+	assert.Equal(T, []string{"P_build", "P_Deploy", "A_Test"}, []string{list.BuildTypes[0].ID, list.BuildTypes[1].ID, list.BuildTypes[2].ID})
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `unknown --sort column "age"`, "job", "list", "--sort", "age")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--sort project needs the projectName field in --json", "job", "list", "--json=id", "--sort", "project")
+}
+
+func TestJobListWithStatus(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	var requests atomic.Int32
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Contains(T, r.URL.Query().Get("fields"), "builds($locator(count:1),build(", "the last runs come with the jobs")
+		cmdtest.JSON(w, api.BuildTypeList{Count: 3, BuildTypes: []api.BuildType{
+			{ID: "P_Build", Name: "Build", ProjectName: "P", Builds: &api.BuildList{Count: 1, Builds: []api.Build{
+				{ID: 7, Number: "42", State: "finished", Status: "FAILURE", StatusText: "Tests failed: 2", FinishDate: "20260101T120000+0000"},
+			}}},
+			{ID: "P_Deploy", Name: "Deploy", ProjectName: "P", Paused: true, Builds: &api.BuildList{Count: 1, Builds: []api.Build{
+				{ID: 5, Number: "9", State: "finished", Status: "SUCCESS"},
+			}}},
+			{ID: "P_New", Name: "New", ProjectName: "P"},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "--with-status", "--plain")
+	assert.Regexp(T, `ID\s+NAME\s+PROJECT\s+STATUS\s+LAST RUN\s+FINISHED`, out)
+	assert.Regexp(T, `P_Build\s+Build\s+P\s+\S+ Failed\s+#42\s+Jan 01`, out)
+	assert.Regexp(T, `P_Deploy\s+Deploy\s+P\s+\S+ Paused\s+#9\s+-`, out)
+	assert.Regexp(T, `P_New\s+New\s+P\s+No runs\s+-\s+-`, out)
+	assert.EqualValues(T, 1, requests.Load(), "one request, not one per job")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "list", "--with-status", "--json=id")
+	var list api.BuildTypeList
+	require.NoError(T, json.Unmarshal([]byte(out), &list))
+	require.Len(T, list.BuildTypes, 3)
+	require.NotNil(T, list.BuildTypes[0].LastRun)
+	assert.Equal(T, "FAILURE", list.BuildTypes[0].LastRun.Status)
+	assert.True(T, list.BuildTypes[1].Paused)
+	assert.Nil(T, list.BuildTypes[2].LastRun)
+	assert.NotContains(T, out, `"builds"`)
+}
+
 func TestJobView(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
@@ -52,6 +260,59 @@ func TestJobView(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "job", "view", testJob, "--json")
 }
 
+func TestJobViewFull(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	props := func(kv ...string) *api.PropertyList {
+		var list api.PropertyList
+		for i := 0; i+1 < len(kv); i += 2 {
+			list.Property = append(list.Property, api.Property{Name: kv[i], Value: kv[i+1]})
+		}
+		return &list
+	}
+	ts.Handle("GET /app/rest/buildTypes/id:"+testJob, func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(T, r.URL.Query().Get("fields"), "triggers(")
+		cmdtest.JSON(w, api.BuildTypeDetail{
+			BuildType: api.BuildType{ID: testJob, Name: "Build", ProjectID: "TestProject", WebURL: "https://example/job",
+				VcsRootEntries: &api.VcsRootEntries{Count: 1, VcsRootEntry: []api.VcsRootEntry{
+					{ID: "Falcon_GitHub", CheckoutRules: "+:src\n+:docs\n", VcsRoot: &api.VcsRoot{ID: "Falcon_GitHub", Name: "Falcon GitHub"}},
+				}}},
+			Steps: &api.BuildStepList{Count: 2, Step: []api.BuildStep{
+				{ID: "RUNNER_1", Name: "Compile", Type: "gradle-runner"},
+				{ID: "RUNNER_2", Name: "Publish", Type: "simpleRunner", Disabled: true},
+			}},
+			Triggers: &api.BuildTriggerList{Count: 3, Trigger: []api.BuildTrigger{
+				{ID: "T1", Type: "vcsTrigger", Properties: props("branchFilter", "+:main\n+:release/*")},
+				{ID: "T2", Type: "schedulingTrigger", Disabled: true, Properties: props("schedulingPolicy", "daily", "hour", "2", "minute", "0")},
+				{ID: "T3", Type: "buildDependencyTrigger", Properties: props("dependsOn", "Falcon_Lib", "afterSuccessfulBuildOnly", "true")},
+			}},
+			SnapshotDependencies: &api.SnapshotDependencyList{Count: 1, SnapshotDependency: []api.SnapshotDependency{
+				{ID: "Falcon_Lib", SourceBuildType: &api.BuildType{ID: "Falcon_Lib", Name: "Lib"}},
+			}},
+			ArtifactDependencies: &api.ArtifactDependencyList{Count: 1, ArtifactDependency: []api.ArtifactDependency{
+				{ID: "ARTIFACT_DEPENDENCY_1", SourceBuildType: &api.BuildType{ID: "Falcon_Lib"}, Properties: props("pathRules", "lib.jar")},
+			}},
+			Settings: &api.SettingsList{Count: 1, Property: []api.Setting{{Name: "buildNumberPattern", Value: "1.%build.counter%"}}},
+		})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "view", testJob, "--full")
+	for _, want := range []string{
+		"Steps (2)\n  1. Compile  gradle-runner  enabled\n  2. Publish  simpleRunner  disabled\n",
+		"Triggers (3)\n  VCS  branches: +:main, +:release/*\n  Schedule  daily at 02:00  disabled\n  Finish build  when Falcon_Lib succeeds\n",
+		"VCS roots (1)\n  Falcon_GitHub  Falcon GitHub  checkout rules: +:src, +:docs\n",
+		"Dependencies (2)\n  snapshot   Falcon_Lib  Lib\n  artifacts  Falcon_Lib  lib.jar\n",
+	} {
+		assert.Contains(T, out, want)
+	}
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "view", testJob, "--full", "--json")
+	var got api.BuildTypeDetail
+	require.NoError(T, json.Unmarshal([]byte(out), &got))
+	assert.Equal(T, testJob, got.ID)
+	assert.Len(T, got.Triggers.Trigger, 3)
+	assert.Equal(T, "1.%build.counter%", got.Settings.Property[0].Value)
+}
+
 func TestJobViewWeb(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
@@ -62,16 +323,134 @@ func TestJobViewWeb(T *testing.T) {
 	}
 }
 
+func TestJobViewCopy(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var copied []string
+	orig := cmdutil.CopyToClipboard
+	T.Cleanup(func() { cmdutil.CopyToClipboard = orig })
+	cmdutil.CopyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "view", testJob, "--copy")
+	assert.Contains(T, out, "Copied "+ts.URL+"/viewType.html?buildTypeId="+testJob+" to clipboard")
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "job", "view", testJob, "--copy=id", "--json")
+
+	assert.Equal(T, []string{ts.URL + "/viewType.html?buildTypeId=" + testJob, testJob}, copied)
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `invalid --copy value "name"`, "job", "view", testJob, "--copy=name")
+}
+
 func TestJobPauseResume(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
+	paused := false
+	var puts []string
+	ts.Handle("GET /app/rest/buildTypes/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildType{ID: testJob, Name: "Build", ProjectID: "TestProject", Paused: paused})
+	})
+	ts.Handle("PUT /app/rest/buildTypes/id:", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		puts = append(puts, string(body))
+		paused = string(body) == "true"
+		w.WriteHeader(http.StatusOK)
+	})
 
 	cmdtest.RunCmdWithFactory(T, f, "job", "pause", testJob)
+	assert.Contains(T, cmdtest.CaptureOutput(T, f, "job", "pause", testJob), "Job TestProject_Build is already paused")
 	cmdtest.RunCmdWithFactory(T, f, "job", "resume", testJob)
+	assert.Contains(T, cmdtest.CaptureOutput(T, f, "job", "resume", testJob), "Job TestProject_Build is already active")
+	assert.Equal(T, []string{"true", "false"}, puts)
+}
+
+func TestJobPauseVCSManaged(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 3, BuildTypes: []api.BuildType{
+			{ID: "Falcon_Build", ProjectID: "Falcon"},
+			{ID: "Falcon_Test", ProjectID: "Falcon"},
+			{ID: "Falcon_Deploy", ProjectID: "Falcon_Prod"},
+		}})
+	})
+	lookups := map[string]int{}
+	for _, project := range []string{"Falcon", "Falcon_Prod"} {
+		ts.Handle("GET /app/rest/projects/"+project+"/versionedSettings/config", func(w http.ResponseWriter, r *http.Request) {
+			lookups[project]++
+			if project == "Falcon_Prod" {
+				cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", BuildSettingsMode: "useFromVCS"})
+				return
+			}
+			cmdtest.Error(w, http.StatusNotFound, "Versioned settings are not configured for this project")
+		})
+	}
+	var puts atomic.Int32
+	ts.Handle("PUT /app/rest/buildTypes/id:", func(w http.ResponseWriter, r *http.Request) {
+		puts.Add(1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "project Falcon_Prod takes its settings from VCS",
+		"job", "pause", "--project", "Falcon", "--yes")
+	assert.Zero(T, puts.Load(), "a blocked change must not reach the server")
+	assert.Equal(T, map[string]int{"Falcon": 1, "Falcon_Prod": 1}, lookups, "versioned settings are looked up once per project")
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "job", "pause", "--project", "Falcon", "--yes", "--force-vcs-managed")
+	assert.Equal(T, int32(3), puts.Load())
+}
+
+func TestJobPauseBulk(T *testing.T) {
+	setup := func(t *testing.T) (*cmdtest.TestServer, *[]string) {
+		ts := cmdtest.SetupMockClient(t)
+		ts.ServeServerSettings("TestProject")
+		ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+			assert.Contains(t, r.URL.Query().Get("locator"), "affectedProject:Falcon")
+			cmdtest.JSON(w, api.BuildTypeList{Count: 5, BuildTypes: []api.BuildType{
+				{ID: "Falcon_Build", Name: "Build", ProjectID: "Falcon"},
+				{ID: "Falcon_Test", Name: "Test", ProjectID: "Falcon"},
+				{ID: "Falcon_Lint", Name: "Lint", ProjectID: "Falcon", Paused: true},
+				{ID: "Falcon_Deploy", Name: "Deploy", ProjectID: "Falcon_Prod"},
+				{ID: "Falcon_Docs", Name: "Docs", ProjectID: "Falcon"},
+			}})
+		})
+		var paused []string
+		ts.Handle("PUT /app/rest/buildTypes/id:", func(w http.ResponseWriter, r *http.Request) {
+			paused = append(paused, cmdtest.ExtractID(strings.TrimSuffix(r.URL.Path, "/paused"), "id:"))
+			w.WriteHeader(http.StatusOK)
+		})
+		return ts, &paused
+	}
+
+	T.Run("project over the threshold needs --yes", func(t *testing.T) {
+		ts, paused := setup(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "changing 4 jobs needs confirmation", "job", "pause", "--project", "Falcon")
+		assert.Empty(t, *paused)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "pause", "--project", "Falcon", "--yes")
+		assert.Contains(t, out, "Falcon_Lint")
+		assert.Contains(t, out, "already paused")
+		assert.Contains(t, out, "active → paused")
+		assert.Contains(t, out, "Paused job Falcon_Deploy")
+		assert.Equal(t, []string{"Falcon_Build", "Falcon_Test", "Falcon_Deploy", "Falcon_Docs"}, *paused)
+	})
+
+	T.Run("several IDs", func(t *testing.T) {
+		ts, paused := setup(t)
+		cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "pause", testJob, "TestProject_Build", "TestProject_Other")
+		assert.Equal(t, []string{testJob, "TestProject_Other"}, *paused, "each job once, no confirmation up to three")
+	})
+
+	T.Run("nothing to resume", func(t *testing.T) {
+		ts, paused := setup(t)
+		ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.BuildTypeList{Count: 1, BuildTypes: []api.BuildType{{ID: "Falcon_Build", ProjectID: "Falcon"}}})
+		})
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "resume", "--project", "Falcon", testJob)
+		assert.Contains(t, out, "All 2 jobs are already active")
+		assert.Empty(t, *paused)
+	})
 }
 
 func TestJobParam(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	paramName := "TC_CLI_JOB_TEST"
@@ -115,3 +494,211 @@ func TestJobTreeWithDeps(T *testing.T) {
 
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "job", "tree", "Deploy")
 }
+
+// handleTokenJob serves Falcon_Build in Falcon (child of _Root) referring to three tokens: one in _Root, one
+// deleted, and, when denied, one the server will not check.
+func handleTokenJob(ts *cmdtest.TestServer, denied bool) {
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildType{ID: "Falcon_Build", ProjectID: "Falcon"})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build/parameters", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ParameterList{Property: []api.Parameter{
+			{Name: "env.DEPLOY_KEY", Value: "credentialsJSON:aaa-111"},
+			{Name: "env.PLAIN", Value: "hello"},
+		}})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build/settings", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.SettingsList{Property: []api.Setting{{Name: "buildNumberPattern", Value: "%build.counter%"}}})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build/steps", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildStepList{Step: []api.BuildStep{{Name: "Push", Type: "simpleRunner", Properties: api.PropertyList{
+			Property: []api.Property{{Name: "script.content", Value: "login -p credentialsJSON:bbb-222 && push credentialsJSON:aaa-111"}},
+		}}}})
+	})
+	ts.Handle("GET /app/rest/projects/id:Falcon", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Project{ID: "Falcon", ParentProjectID: "_Root"})
+	})
+	ts.Handle("GET /app/rest/projects/id:_Root", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Project{ID: "_Root"})
+	})
+	ts.Handle("GET /app/rest/projects/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case denied:
+			cmdtest.Error(w, http.StatusForbidden, "You do not have VIEW_SERVER_SETTINGS permission")
+		case strings.HasSuffix(r.URL.Path, "/_Root/secure/values/aaa-111"):
+			_, _ = w.Write([]byte("s3cret-value"))
+		default:
+			cmdtest.Error(w, http.StatusNotFound, "No secure value found")
+		}
+	})
+}
+
+func TestJobTokens(T *testing.T) {
+	T.Run("reports valid and dangling references", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		handleTokenJob(ts, false)
+
+		err := cmdtest.CaptureErr(t, ts.Factory, "job", "tokens", "Falcon_Build")
+		var exitErr *cmdutil.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, cmdutil.ExitFailure, exitErr.Code)
+		out := ts.Factory.Printer.Out.(*bytes.Buffer).String()
+		assert.Regexp(t, `credentialsJSON:aaa-111\s+valid \(_Root\)\s+parameter env.DEPLOY_KEY, step "Push"`, out)
+		assert.Regexp(t, `credentialsJSON:bbb-222\s+dangling\s+step "Push" \(script.content\)`, out)
+		assert.Contains(t, out, "1 of 2 tokens no longer resolve")
+		assert.NotContains(t, out, "s3cret-value")
+	})
+
+	T.Run("json", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		handleTokenJob(ts, true)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "tokens", "Falcon_Build", "--json")
+		var refs []job.TokenReference
+		require.NoError(t, json.Unmarshal([]byte(out), &refs))
+		require.Len(t, refs, 2)
+		assert.Equal(t, "credentialsJSON:aaa-111", refs[0].Token)
+		assert.Equal(t, "unknown", refs[0].Status)
+		assert.Equal(t, []string{"parameter env.DEPLOY_KEY", `step "Push" (script.content)`}, refs[0].UsedIn)
+	})
+
+	T.Run("fix needs a terminal", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "--fix-interactive needs an interactive terminal",
+			"job", "tokens", "Falcon_Build", "--fix-interactive")
+	})
+}
+
+// handlePruneJob serves Falcon_Build with a default branch, an active one, and inactive branches whose runs are
+// old, recent, or old but pinned; the paths of tag and delete requests are appended to mutations.
+func handlePruneJob(ts *cmdtest.TestServer, mutations *[]string) {
+	old, recent := "20250110T080607+0000", api.FormatTeamCityTime(time.Now().Add(-24*time.Hour))
+	runs := map[string][]api.Build{
+		"old-ui":     {{ID: 11, State: "finished", FinishDate: old}, {ID: 12, State: "finished", FinishDate: old, Pinned: true}},
+		"recent-fix": {{ID: 21, State: "finished", FinishDate: old}, {ID: 22, State: "finished", FinishDate: recent}},
+		"stale-pins": {{ID: 31, State: "finished", FinishDate: old, Pinned: true}},
+	}
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildType{ID: "Falcon_Build", ProjectID: "Falcon"})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build/branches", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BranchList{Branch: []api.Branch{
+			{Name: "<default>", Default: true},
+			{Name: "wip", Active: true},
+			{Name: "old-ui"}, {Name: "recent-fix"}, {Name: "stale-pins"},
+		}})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		for branch, builds := range runs {
+			if strings.Contains(r.URL.Query().Get("locator"), "branch:"+branch+",") {
+				cmdtest.JSON(w, api.BuildList{Count: len(builds), Builds: builds})
+				return
+			}
+		}
+		cmdtest.Error(w, http.StatusBadRequest, "unexpected locator "+r.URL.Query().Get("locator"))
+	})
+	record := func(w http.ResponseWriter, r *http.Request) {
+		*mutations = append(*mutations, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	ts.Handle("POST /app/rest/builds/id:", record)
+	ts.Handle("DELETE /app/rest/builds/id:", record)
+}
+
+func TestJobPruneBranches(T *testing.T) {
+	T.Run("tags stale runs by default", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		var mutations []string
+		handlePruneJob(ts, &mutations)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "prune-branches", "Falcon_Build")
+		assert.Equal(t, []string{"POST /app/rest/builds/id:11/tags"}, mutations)
+		assert.Regexp(t, `<default>\s+-\s+keep default branch`, out)
+		assert.Regexp(t, `wip\s+-\s+keep active`, out)
+		assert.Regexp(t, `old-ui\s+.*tag 1 runs \(keep 1 pinned\)`, out)
+		assert.Regexp(t, `recent-fix\s+.*keep recent runs`, out)
+		assert.Regexp(t, `stale-pins\s+.*keep only pinned runs`, out)
+		assert.Contains(t, out, "Tagged 1 runs prune-candidate on 1 stale branches")
+	})
+
+	T.Run("delete needs --yes without a terminal", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		var mutations []string
+		handlePruneJob(ts, &mutations)
+
+		err := cmdtest.CaptureErr(t, ts.Factory, "job", "prune-branches", "Falcon_Build", "--delete")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--delete needs confirmation")
+		assert.Empty(t, mutations)
+	})
+
+	T.Run("delete json", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		var mutations []string
+		handlePruneJob(ts, &mutations)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "prune-branches", "Falcon_Build", "--delete", "--yes", "--older-than", "2025-06-01", "--json")
+		assert.Equal(t, []string{"DELETE /app/rest/builds/id:11"}, mutations)
+		var result job.BranchPruneResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.Equal(t, "delete", result.Action)
+		require.Len(t, result.Branches, 5)
+		assert.Equal(t, job.BranchPruneDecision{Branch: "old-ui", LastRun: "20250110T080607+0000", Prune: true, Runs: []int{11}, Pinned: 1}, result.Branches[2])
+	})
+}
+
+func TestJobArtifactUsage(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	content := &api.Content{Href: "/download"}
+	var builds atomic.Value
+	builds.Store("")
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		builds.Store(r.URL.Query().Get("locator"))
+		cmdtest.JSON(w, api.BuildList{Count: 3, Builds: []api.Build{
+			{ID: 103, Number: "12", BranchName: "main"},
+			{ID: 102, Number: "11", BranchName: "main"},
+			{ID: 101, Number: "10", BranchName: "main"},
+		}})
+	})
+	// Each run has a top-level file and a lib/ directory; run 103 grew lib/ by 1 MiB.
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		id, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/app/rest/builds/id:"), "/artifacts/children")
+		switch path {
+		case "":
+			cmdtest.JSON(w, api.Artifacts{Count: 2, File: []api.Artifact{{Name: "app.jar", Size: 4 << 20, Content: content}, {Name: "lib"}}})
+		case "/lib":
+			size := int64(1 << 20)
+			if id == "103" {
+				size = 2 << 20
+			}
+			cmdtest.JSON(w, api.Artifacts{Count: 1, File: []api.Artifact{{Name: "deps.jar", Size: size, Content: content}}})
+		default:
+			cmdtest.Error(w, http.StatusNotFound, "not found")
+		}
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "artifact-usage", "Falcon_Build", "--last", "3", "--json")
+	var usage job.ArtifactUsage
+	require.NoError(T, json.Unmarshal([]byte(out), &usage))
+	assert.Contains(T, builds.Load(), "buildType:Falcon_Build")
+	assert.Contains(T, builds.Load(), "state:finished")
+	assert.Contains(T, builds.Load(), "count:3")
+	assert.Equal(T, "Falcon_Build", usage.Job)
+	require.Len(T, usage.Runs, 3)
+	assert.Equal(T, []int{103, 102, 101}, []int{usage.Runs[0].ID, usage.Runs[1].ID, usage.Runs[2].ID}, "newest first")
+	assert.Equal(T, int64(6<<20), usage.Runs[0].Size)
+	assert.Equal(T, 2, usage.Runs[0].Files)
+	require.NotNil(T, usage.Runs[0].Change)
+	assert.Equal(T, int64(1<<20), *usage.Runs[0].Change)
+	assert.Equal(T, int64(0), *usage.Runs[1].Change)
+	assert.Nil(T, usage.Runs[2].Change, "the oldest run has nothing to compare with")
+	assert.Equal(T, int64(16<<20), usage.TotalSize)
+	assert.Equal(T, int64(1<<20), usage.Change)
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "job", "artifact-usage", "Falcon_Build", "--last", "3")
+	assert.Contains(T, out, "+1.0 MiB")
+	assert.Contains(T, out, "Total: 16 MiB across 3 runs")
+	assert.Contains(T, out, "Trend: +1.0 MiB (+20%) from #10 to #12")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--last must be at least 1", "job", "artifact-usage", "Falcon_Build", "--last", "0")
+}
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"cmp"
+	"errors"
 	"slices"
 	"strings"
 
@@ -12,8 +14,9 @@ import (
 )
 
 type jobListOptions struct {
-	project string
-	all     bool
+	project    string
+	all        bool
+	withStatus bool
 	cmdutil.ListFlags
 }
 
@@ -26,30 +29,67 @@ func newJobListCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `List jobs across all projects or in a specific project.
 
 Jobs backed by pipelines are hidden by default; pass --all to include
-them alongside classic build configurations.`,
+them alongside classic build configurations.
+
+Jobs are sorted by ID. Use --sort to order by other columns, or
+--sort none to keep the server's order and print rows as each page
+arrives.
+
+--with-status adds each job's latest finished run on its default branch:
+its result, number, and when it finished, and shows paused jobs as such.
+It comes with the job list in the same request; in --json it is the
+lastRun of each job.`,
 		Aliases: []string{"ls"},
 		Example: `  teamcity job list
   teamcity job list --project Falcon
+  teamcity job list --with-status
   teamcity job list --json
   teamcity job list --json=id,name,webUrl
+  teamcity job list --sort project,name
   teamcity job list --plain
-  teamcity job list --plain --no-header`,
+  teamcity job list --plain --no-header
+  teamcity job list --template '{{.ID}}: {{.WebURL}}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.project = f.ResolveProject(opts.project)
-			return cmdutil.RunList(f, cmd, &opts.ListFlags, &api.BuildTypeFields, opts.fetch)
+			headers := []string{"ID", "NAME", "PROJECT", "STATUS"}
+			if opts.withStatus {
+				headers = append(headers, "LAST RUN", "FINISHED")
+			}
+			return cmdutil.RunStreamList(f, cmd, &opts.ListFlags, &api.BuildTypeFields, cmdutil.StreamedList{
+				JSONKey:  "buildType",
+				Headers:  headers,
+				FlexCols: []int{0, 1, 2},
+				Noun:     "job",
+				EmptyMsg: "No jobs found",
+				EmptyTip: output.TipNoJobs,
+			}, jobSortColumns, opts.fetch)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Filter by project ID")
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Include pipelines")
+	cmd.Flags().BoolVar(&opts.withStatus, "with-status", false, "Show each job's latest run and whether it is paused")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 30)
+	cmdutil.AddTemplateFlag(cmd, &opts.Template)
+	cmdutil.AddPageSizeFlag(cmd, &opts.ListFlags)
+	cmdutil.AddSortFlags(cmd, &opts.SortFlags, jobSortColumns, "id")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
 
-func (opts *jobListOptions) fetch(client api.ClientInterface, fields []string) (*cmdutil.ListResult, error) {
+var jobSortColumns = []cmdutil.SortColumn[api.BuildType]{
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b api.BuildType) int { return cmdutil.CompareFold(a.ID, b.ID) }},
+	{Name: "name", Fields: []string{"name"}, Compare: func(a, b api.BuildType) int { return cmdutil.CompareFold(a.Name, b.Name) }},
+	{Name: "project", Fields: []string{"projectName"}, Compare: func(a, b api.BuildType) int { return cmdutil.CompareFold(a.ProjectName, b.ProjectName) }},
+	{Name: "status", Fields: []string{"paused"}, Compare: func(a, b api.BuildType) int { return cmdutil.CompareBools(a.Paused, b.Paused) }},
+}
+
+// errJobListFull stops paging once --limit jobs have been shown.
+var errJobListFull = errors.New("job list full")
+
+func (opts *jobListOptions) fetch(client api.ClientInterface, fields []string, emit func([]api.BuildType, [][]string) error) (bool, error) {
 	pipelineProjectIDs := map[string]bool{}
 	if !opts.all && client.SupportsFeature("pipelines") {
 		if pipelines, _, err := client.GetPipelines(api.PipelinesOptions{Limit: 10000}); err == nil {
@@ -68,36 +108,54 @@ func (opts *jobListOptions) fetch(client api.ClientInterface, fields []string) (
 	if len(pipelineProjectIDs) > 0 && len(fields) > 0 && !slices.Contains(fields, "projectId") {
 		fetchFields = append(slices.Clone(fields), "projectId")
 	}
-
-	jobs, truncated, err := client.GetBuildTypes(api.BuildTypesOptions{
-		Project: opts.project,
-		Limit:   limit,
-		Fields:  fetchFields,
-	})
-	if err != nil {
-		return nil, err
+	if opts.withStatus && len(fetchFields) > 0 && !slices.Contains(fetchFields, "paused") {
+		fetchFields = append(slices.Clone(fetchFields), "paused")
 	}
 
-	if len(pipelineProjectIDs) > 0 {
-		filtered := jobs.BuildTypes[:0]
-		for _, j := range jobs.BuildTypes {
-			if !isPipelineOwned(j.ProjectID, pipelineProjectIDs) {
-				filtered = append(filtered, j)
+	shown, full := 0, false
+	_, truncated, err := client.GetBuildTypes(api.BuildTypesOptions{
+		Project:  opts.project,
+		Limit:    limit,
+		Fields:   fetchFields,
+		PageSize: opts.PageSize,
+		LastRun:  opts.withStatus,
+		OnPage: func(page []api.BuildType) error {
+			jobs := page
+			if len(pipelineProjectIDs) > 0 {
+				jobs = []api.BuildType{}
+				for _, j := range page {
+					if !isPipelineOwned(j.ProjectID, pipelineProjectIDs) {
+						jobs = append(jobs, j)
+					}
+				}
 			}
-		}
-		jobs.BuildTypes = filtered
-		jobs.Count = len(filtered)
-	}
-	if opts.Limit > 0 && len(jobs.BuildTypes) > opts.Limit {
-		jobs.BuildTypes = jobs.BuildTypes[:opts.Limit]
-		jobs.Count = opts.Limit
-		truncated = true
+			if opts.Limit > 0 && shown+len(jobs) > opts.Limit {
+				jobs, full = jobs[:opts.Limit-shown], true
+			}
+			shown += len(jobs)
+			if err := emit(jobs, jobListRows(jobs, opts.withStatus)); err != nil {
+				return err
+			}
+			if full {
+				return errJobListFull
+			}
+			return nil
+		},
+	})
+	if errors.Is(err, errJobListFull) {
+		return true, nil
 	}
+	return truncated, err
+}
 
-	headers := []string{"ID", "NAME", "PROJECT", "STATUS"}
+func jobListRows(jobs []api.BuildType, withStatus bool) [][]string {
 	var rows [][]string
+	for _, j := range jobs {
+		if withStatus {
+			rows = append(rows, jobStatusRow(j))
+			continue
+		}
 
-	for _, j := range jobs.BuildTypes {
 		status := output.Green("Active")
 		if j.Paused {
 			status = output.Faint("Paused")
@@ -110,68 +168,105 @@ func (opts *jobListOptions) fetch(client api.ClientInterface, fields []string) (
 			status,
 		})
 	}
+	return rows
+}
 
-	return &cmdutil.ListResult{
-		JSON:      jobs,
-		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: []int{0, 1, 2}},
-		EmptyMsg:  "No jobs found",
-		EmptyTip:  output.TipNoJobs,
-		Truncated: truncated,
-	}, nil
+// jobStatusRow is a --with-status row: a paused job shows as paused, any other job as its latest run's result.
+func jobStatusRow(j api.BuildType) []string {
+	status, number, finished := output.Faint("No runs"), "-", "-"
+	if r := j.LastRun; r != nil {
+		status = output.StatusIcon(r.Status, r.State, r.StatusText) + " " + output.StatusText(r.Status, r.State, r.StatusText)
+		number = "#" + r.Number
+		if t, err := api.ParseTeamCityTime(r.FinishDate); err == nil {
+			finished = output.RelativeTime(t)
+		}
+	}
+	if j.Paused {
+		status = output.Faint(output.Sym().Paused + " Paused")
+	}
+	return []string{j.ID, j.Name, j.ProjectName, status, number, finished}
 }
 
 func newJobViewCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &cmdutil.ViewOptions{}
+	copyOpts := &cmdutil.CopyOptions{}
+	var full bool
 	cmd := &cobra.Command{
-		Use:               "view [job-id]",
-		Short:             "View job details",
-		Long:              "View details of a TeamCity build configuration. With no argument, uses the linked default job from teamcity.toml.",
+		Use:   "view [job-id]",
+		Short: "View job details",
+		Long: `View details of a TeamCity build configuration. With no argument, uses the linked default job from teamcity.toml.
+
+--full adds the job's build steps, triggers with their schedules and branch
+filters, VCS roots with checkout rules, and snapshot and artifact
+dependencies. With --json, --full prints all of them, settings included.`,
 		Aliases:           []string{"show"},
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Example: `  teamcity job view Falcon_Build
   teamcity job view Falcon_Build --web
+  teamcity job view Falcon_Build --copy=id
+  teamcity job view Falcon_Build --full
   teamcity job view              # uses linked default job (see 'teamcity link')`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := copyOpts.Validate(); err != nil {
+				return err
+			}
 			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
 			if err != nil {
 				return err
 			}
-			return runJobView(f, jobID, opts)
+			return runJobView(f, jobID, opts, copyOpts, full)
 		},
 	}
 	cmdutil.AddViewFlags(cmd, opts)
+	cmd.Flags().BoolVar(&full, "full", false, "Add build steps, triggers, VCS roots, and dependencies")
+	cmdutil.AddCopyFlag(cmd, copyOpts)
 	return cmd
 }
 
-func runJobView(f *cmdutil.Factory, jobID string, opts *cmdutil.ViewOptions) error {
+func runJobView(f *cmdutil.Factory, jobID string, opts *cmdutil.ViewOptions, copyOpts *cmdutil.CopyOptions, full bool) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	buildType, err := client.GetBuildType(jobID)
-	if err != nil {
+	var buildType *api.BuildType
+	var detail *api.BuildTypeDetail
+	if full {
+		if detail, err = client.GetBuildTypeDetail(jobID); err != nil {
+			return err
+		}
+		buildType = &detail.BuildType
+	} else if buildType, err = client.GetBuildType(jobID); err != nil {
 		return err
 	}
+	copyOpts.Copy(f.Printer, buildType.WebURL, buildType.ID)
 
 	if done, err := opts.EmitWebURL(f.Printer, buildType.WebURL); done {
 		return err
 	}
 
 	if opts.JSON {
+		if detail != nil {
+			return f.Printer.PrintJSON(detail)
+		}
 		return f.Printer.PrintJSON(buildType)
 	}
 
 	f.Printer.PrintViewHeader(buildType.Name, buildType.WebURL, func() {
 		f.Printer.PrintField("ID", buildType.ID)
-		f.Printer.PrintField("Project", buildType.ProjectName+" ("+buildType.ProjectID+")")
+		if buildType.ProjectID != "" {
+			f.Printer.PrintField("Project", cmp.Or(buildType.ProjectName, buildType.ProjectID)+" ("+buildType.ProjectID+")")
+		}
 
 		status := output.Green("Active")
 		if buildType.Paused {
 			status = output.Faint("Paused")
 		}
 		f.Printer.PrintField("Status", status)
+		if detail != nil {
+			printJobSections(f.Printer, detail)
+		}
 	})
 
 	return nil
@@ -0,0 +1,294 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// pruneCandidateTag marks the runs of a stale branch when prune-branches only tags them.
+const pruneCandidateTag = "prune-candidate"
+
+// pruneRunFields are the run fields prune-branches needs to date a branch and pick its runs.
+var pruneRunFields = []string{"id", "number", "state", "pinned", "queuedDate", "startDate", "finishDate"}
+
+// BranchPruneDecision is what prune-branches does with one branch. Runs lists the runs it prunes; Pinned counts
+// runs it keeps because they are pinned.
+type BranchPruneDecision struct {
+	Branch  string `json:"branch"`
+	LastRun string `json:"lastRun,omitempty"`
+	Prune   bool   `json:"prune"`
+	Reason  string `json:"reason,omitempty"`
+	Runs    []int  `json:"runs,omitempty"`
+	Pinned  int    `json:"pinned,omitempty"`
+	Failed  []int  `json:"failed,omitempty"`
+}
+
+// BranchPruneResult is the --json output; under --dry-run, the runs listed are those that would have been pruned.
+type BranchPruneResult struct {
+	Job      string                `json:"job"`
+	Action   string                `json:"action"`
+	Cutoff   string                `json:"cutoff"`
+	DryRun   bool                  `json:"dryRun,omitempty"`
+	Branches []BranchPruneDecision `json:"branches"`
+}
+
+type jobPruneBranchesOptions struct {
+	olderThan string
+	tagOnly   bool
+	delete    bool
+	yes       bool
+	json      bool
+}
+
+func newJobPruneBranchesCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &jobPruneBranchesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "prune-branches [job-id]",
+		Short: "Tag or delete the runs of stale branches",
+		Long: `Find a job's branches that have had no run for longer than --older-than and that
+the server no longer considers active, and tag their runs prune-candidate.
+With --delete, the runs are deleted instead, along with their artifacts and logs.
+
+The default branch and pinned runs are never touched, nor are runs still queued
+or running. The decision for every branch is printed first, then you are asked
+to confirm. Deleting without a terminal requires --yes; combine with --dry-run
+to see the requests without changing anything.
+With no argument, uses the linked default job from teamcity.toml.`,
+		Example: `  teamcity job prune-branches Falcon_Build
+  teamcity job prune-branches Falcon_Build --older-than 30d --dry-run
+  teamcity job prune-branches Falcon_Build --delete --yes
+  teamcity run list --job Falcon_Build --tag prune-candidate`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.Jobs(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
+			if err != nil {
+				return err
+			}
+			return runJobPruneBranches(f, jobID, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.olderThan, "older-than", "90d", "Prune branches whose last run is older than this (e.g., 30d, 12w, 2026-01-01)")
+	cmd.Flags().BoolVar(&opts.tagOnly, "tag-only", false, "Tag the runs "+pruneCandidateTag+" instead of deleting them (default)")
+	cmd.Flags().BoolVar(&opts.delete, "delete", false, "Delete the runs instead of tagging them")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.MarkFlagsMutuallyExclusive("tag-only", "delete")
+
+	return cmd
+}
+
+func runJobPruneBranches(f *cmdutil.Factory, jobID string, opts *jobPruneBranchesOptions) error {
+	since, err := api.ParseUserDate(opts.olderThan)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+	cutoff, err := api.ParseTeamCityTime(since)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+	if opts.delete && !opts.yes && !f.IsDryRun() && !f.IsInteractive() {
+		return api.Validation("--delete needs confirmation, and there is no terminal to ask in",
+			"Add --yes to delete without asking, or --dry-run to preview")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	p := f.Printer
+	ctx := f.Context()
+
+	job, err := client.GetBuildType(jobID)
+	if err != nil {
+		return err
+	}
+	branches, err := client.GetBuildTypeBranches(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get branches: %w", err)
+	}
+
+	result := BranchPruneResult{Job: job.ID, Action: "tag", Cutoff: since, DryRun: f.IsDryRun(), Branches: []BranchPruneDecision{}}
+	if opts.delete {
+		result.Action = "delete"
+	}
+	runs := 0
+	for _, b := range branches.Branch {
+		d := BranchPruneDecision{Branch: b.Name}
+		switch {
+		case b.Default || b.Unspecified:
+			d.Reason = "default branch"
+		case b.Active:
+			d.Reason = "active"
+		default:
+			builds, _, err := client.GetBuilds(ctx, api.BuildsOptions{BuildTypeID: job.ID, Branch: b.Name, Fields: pruneRunFields})
+			if err != nil {
+				return fmt.Errorf("failed to get runs of branch %s: %w", b.Name, err)
+			}
+			decideBranchPrune(&d, builds.Builds, cutoff)
+		}
+		runs += len(d.Runs)
+		result.Branches = append(result.Branches, d)
+	}
+
+	if !opts.json {
+		printBranchPrunePlan(p, result)
+	}
+	if runs == 0 {
+		if opts.json {
+			return p.PrintJSON(result)
+		}
+		p.Info("No stale branches with runs to prune in %s", job.ID)
+		return nil
+	}
+
+	if !opts.yes && !f.IsDryRun() && f.IsInteractive() {
+		prompt := fmt.Sprintf("Tag %d runs %s?", runs, pruneCandidateTag)
+		if opts.delete {
+			prompt = fmt.Sprintf("Delete %d runs with their artifacts and logs? This cannot be undone.", runs)
+		}
+		var confirm bool
+		if err := cmdutil.Confirm(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			p.Info("Canceled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for i := range result.Branches {
+		d := &result.Branches[i]
+		for _, id := range d.Runs {
+			ref := strconv.Itoa(id)
+			var err error
+			if opts.delete {
+				err = client.DeleteBuild(ctx, ref)
+				if _, gone := errors.AsType[*api.NotFoundError](err); gone {
+					err = nil
+				}
+			} else {
+				err = client.AddBuildTags(ref, []string{pruneCandidateTag})
+			}
+			if err != nil {
+				d.Failed = append(d.Failed, id)
+				failed++
+				if !opts.json {
+					p.Warn("failed to %s run %d: %v", result.Action, id, err)
+				}
+			}
+		}
+	}
+
+	if opts.json {
+		if err := p.PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		verb := "Tagged %d runs " + pruneCandidateTag
+		if opts.delete {
+			verb = "Deleted %d runs"
+		}
+		p.Success(verb+" on %d stale branches", runs-failed, pruneBranchCount(result))
+		if !opts.delete {
+			p.Tip("Review them with 'teamcity run list --job %s --tag %s', then re-run with --delete", job.ID, pruneCandidateTag)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d runs", result.Action, failed, runs)
+	}
+	return nil
+}
+
+// decideBranchPrune fills in d for an inactive branch: it is pruned when its latest run started before cutoff.
+// Runs still queued or running are left alone, and pinned runs are counted but kept.
+func decideBranchPrune(d *BranchPruneDecision, builds []api.Build, cutoff time.Time) {
+	var last time.Time
+	for _, b := range builds {
+		if t, ok := runDate(b); ok && t.After(last) {
+			last, d.LastRun = t, api.FormatTeamCityTime(t)
+		}
+	}
+	switch {
+	case len(builds) == 0:
+		d.Reason = "no runs"
+		return
+	case !last.Before(cutoff):
+		d.Reason = "recent runs"
+		return
+	}
+
+	for _, b := range builds {
+		switch {
+		case b.Pinned:
+			d.Pinned++
+		case b.State == "finished":
+			d.Runs = append(d.Runs, b.ID)
+		}
+	}
+	if len(d.Runs) == 0 {
+		d.Reason = "only pinned runs"
+		return
+	}
+	d.Prune = true
+}
+
+// runDate is when a run last did something: its finish, start, or queue date, whichever is known first.
+func runDate(b api.Build) (time.Time, bool) {
+	for _, s := range []string{b.FinishDate, b.StartDate, b.QueuedDate} {
+		if t, err := api.ParseTeamCityTime(s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func pruneBranchCount(result BranchPruneResult) int {
+	n := 0
+	for _, d := range result.Branches {
+		if d.Prune {
+			n++
+		}
+	}
+	return n
+}
+
+// printBranchPrunePlan lists every branch of the job with its last run and what prune-branches does with it.
+func printBranchPrunePlan(p *output.Printer, result BranchPruneResult) {
+	headers := []string{"BRANCH", "LAST RUN", "ACTION"}
+	var rows [][]string
+	for _, d := range result.Branches {
+		last := "-"
+		if t, err := api.ParseTeamCityTime(d.LastRun); err == nil {
+			last = output.RelativeTime(t)
+		}
+		action := output.Green("keep") + " " + output.Faint(d.Reason)
+		if d.Prune {
+			action = fmt.Sprintf("%s %d runs", result.Action, len(d.Runs))
+			if result.Action == "delete" {
+				action = output.Red(action)
+			} else {
+				action = output.Yellow(action)
+			}
+			if d.Pinned > 0 {
+				action += " " + output.Faint(fmt.Sprintf("(keep %d pinned)", d.Pinned))
+			}
+		}
+		rows = append(rows, []string{d.Branch, last, action})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0, 2)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
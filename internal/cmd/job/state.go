@@ -2,12 +2,18 @@ package job
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
+// jobStateConfirmAbove is how many jobs pause or resume changes without asking first.
+const jobStateConfirmAbove = 3
+
 type jobStateAction struct {
 	use    string
 	short  string
@@ -17,35 +23,176 @@ type jobStateAction struct {
 }
 
 var jobStateActions = map[string]jobStateAction{
-	"pause":  {"pause", "Pause a job", "Pause a job to prevent new runs from being triggered.", "Paused", true},
-	"resume": {"resume", "Resume a paused job", "Resume a paused job to allow new runs.", "Resumed", false},
+	"pause":  {"pause", "Pause jobs", "Pause jobs to prevent new runs from being triggered.", "Paused", true},
+	"resume": {"resume", "Resume paused jobs", "Resume paused jobs to allow new runs.", "Resumed", false},
+}
+
+type jobStateOptions struct {
+	project         string
+	yes             bool
+	forceVCSManaged bool
 }
 
 func newJobStateCmd(f *cmdutil.Factory, a jobStateAction) *cobra.Command {
-	return &cobra.Command{
-		Use:               a.use + " [job-id]",
-		Short:             a.short,
-		Long:              a.long + " With no argument, uses the linked default job from teamcity.toml.",
-		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+	opts := &jobStateOptions{}
+	cmd := &cobra.Command{
+		Use:   a.use + " [job-id]...",
+		Short: a.short,
+		Long: a.long + fmt.Sprintf(`
+
+Pass several job IDs, or --project to %s every job of a project and its
+subprojects. Jobs that are already %s are left alone. The jobs that change
+are listed first, and more than %d need confirmation (--yes skips it);
+combine with --dry-run to see them without changing anything.
+With no argument, uses the linked default job from teamcity.toml.`, a.use, stateName(a.paused), jobStateConfirmAbove),
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completion.Jobs(),
 		Example: fmt.Sprintf(`  teamcity job %s Falcon_Build
-  teamcity job %s                # uses linked default job (see 'teamcity link')`, a.use, a.use),
+  teamcity job %s Falcon_Build Falcon_Deploy
+  teamcity job %s --project Falcon --dry-run
+  teamcity job %s                # uses linked default job (see 'teamcity link')`, a.use, a.use, a.use, a.use),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
-			if err != nil {
-				return err
-			}
-			client, err := f.Client()
-			if err != nil {
-				return err
+			jobIDs := args
+			if len(args) == 0 && opts.project == "" {
+				jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
+				if err != nil {
+					return err
+				}
+				jobIDs = []string{jobID}
 			}
-			if err := client.SetBuildTypePaused(jobID, a.paused); err != nil {
+			return runJobState(f, a, jobIDs, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.project, "project", "p", "", fmt.Sprintf("Also %s every job of this project and its subprojects", a.use))
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt")
+	cmdutil.AddForceVCSManagedFlag(cmd, &opts.forceVCSManaged)
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
+	return cmd
+}
+
+func runJobState(f *cmdutil.Factory, a jobStateAction, jobIDs []string, opts *jobStateOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	p := f.Printer
+
+	jobs, err := jobStateTargets(client, jobIDs, opts.project)
+	if err != nil {
+		return err
+	}
+	var changes []api.BuildType
+	for _, job := range jobs {
+		if job.Paused != a.paused {
+			changes = append(changes, job)
+		}
+	}
+	if len(changes) == 0 {
+		if len(jobs) == 1 {
+			p.Info("Job %s is already %s", jobs[0].ID, stateName(a.paused))
+		} else {
+			p.Info("All %d jobs are already %s", len(jobs), stateName(a.paused))
+		}
+		return nil
+	}
+	guarded := map[string]bool{}
+	for _, job := range changes {
+		if job.ProjectID == "" || guarded[job.ProjectID] {
+			continue
+		}
+		guarded[job.ProjectID] = true
+		if err := f.GuardVCSManagedProject(client, job.ProjectID, opts.forceVCSManaged); err != nil {
+			return err
+		}
+	}
+
+	if len(jobs) > 1 {
+		printJobStatePlan(p, a, jobs)
+	}
+	if len(changes) > jobStateConfirmAbove && !opts.yes && !f.IsDryRun() {
+		if !f.IsInteractive() {
+			return api.Validation(fmt.Sprintf("changing %d jobs needs confirmation, and there is no terminal to ask in", len(changes)),
+				"Add --yes to go ahead without asking, or --dry-run to preview")
+		}
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("%s %d jobs?", strings.ToUpper(a.use[:1])+a.use[1:], len(changes)), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			p.Info("Canceled")
+			return nil
+		}
+	}
+
+	var failed int
+	for _, job := range changes {
+		if err := client.SetBuildTypePaused(job.ID, a.paused); err != nil {
+			if len(changes) == 1 {
 				return fmt.Errorf("failed to %s job: %w", a.use, err)
 			}
-			f.Printer.Success("%s job %s", a.verb, jobID)
-			return nil
-		},
+			p.Warn("failed to %s job %s: %v", a.use, job.ID, err)
+			failed++
+			continue
+		}
+		p.Success("%s job %s", a.verb, job.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d jobs", a.use, failed, len(changes))
+	}
+	return nil
+}
+
+// jobStateTargets returns the jobs named by jobIDs and, with project set, every job under it, each once.
+func jobStateTargets(client api.ClientInterface, jobIDs []string, project string) ([]api.BuildType, error) {
+	var jobs []api.BuildType
+	seen := map[string]bool{}
+	for _, id := range jobIDs {
+		job, err := client.GetBuildType(id)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[job.ID] {
+			seen[job.ID] = true
+			jobs = append(jobs, *job)
+		}
+	}
+	if project != "" {
+		list, _, err := client.GetBuildTypes(api.BuildTypesOptions{Project: project, Fields: []string{"id", "name", "projectId", "paused"}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs of project %s: %w", project, err)
+		}
+		if len(list.BuildTypes) == 0 {
+			return nil, api.Validation(fmt.Sprintf("project %s has no jobs", project), "List projects with 'teamcity project list'")
+		}
+		for _, job := range list.BuildTypes {
+			if !seen[job.ID] {
+				seen[job.ID] = true
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+// printJobStatePlan lists every job with the state it moves to, or that it already has.
+func printJobStatePlan(p *output.Printer, a jobStateAction, jobs []api.BuildType) {
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		change := output.Faint("already " + stateName(a.paused))
+		if job.Paused != a.paused {
+			change = stateName(job.Paused) + " " + output.Sym().Arrow + " " + output.Yellow(stateName(a.paused))
+		}
+		rows = append(rows, []string{job.ID, job.Name, change})
+	}
+	p.PrintTable([]string{"JOB", "NAME", "STATE"}, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
+
+func stateName(paused bool) string {
+	if paused {
+		return "paused"
 	}
+	return "active"
 }
 
 func newJobPauseCmd(f *cmdutil.Factory) *cobra.Command {
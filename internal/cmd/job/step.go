@@ -2,6 +2,8 @@ package job
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
@@ -52,7 +54,7 @@ func newJobStepListCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:               "list [job-id]",
 		Short:             "List job build steps",
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.LinkedJobs()),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Jobs()),
 		Example: `  teamcity job step list MyBuild
   teamcity job step list                 # uses linked job (see 'teamcity link')
   teamcity job step list MyBuild --json
@@ -121,7 +123,7 @@ func newJobStepViewCmd(f *cmdutil.Factory) *cobra.Command {
 		Short:             "View build step details",
 		Aliases:           []string{"show"},
 		Args:              cobra.RangeArgs(1, 2),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.LinkedJobs()),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Jobs()),
 		Example: `  teamcity job step view MyBuild RUNNER_1
   teamcity job step view RUNNER_1        # uses linked job
   teamcity job step view MyBuild RUNNER_1 --json
@@ -182,14 +184,18 @@ type jobStepAddOptions struct {
 	stepType string
 	name     string
 	params   []string
+	script   string
+	file     string
 	json     bool
+
+	forceVCSManaged bool
 }
 
 func newJobStepAddCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &jobStepAddOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "add [job-id] --type <runner-id>",
+		Use:   "add [job-id] {--type <runner-id> | --script <script> | --file <path>}",
 		Short: "Add a build step to a job",
 		Long: `Add a build step to a job (build configuration).
 
@@ -198,10 +204,16 @@ from the display name in the UI: Command Line is "simpleRunner", Gradle
 is "gradle-runner", Maven is "Maven2", and so on. Find a runner's ID by
 inspecting an existing step with 'teamcity job step view', or from the
 TeamCity documentation. Repeat --param key=value for each step setting;
-the available keys depend on the runner type.`,
+the available keys depend on the runner type.
+
+--script adds a Command Line step that runs the given script, and --file
+one that runs the script in a file ("-" for stdin); --type may then be
+left out. The new step's ID and the job's build steps page are printed.`,
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.LinkedJobs()),
-		Example: `  teamcity job step add MyBuild --type simpleRunner --name "Run Tests" --param use.custom.script=true --param script.content="./gradlew test"
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Jobs()),
+		Example: `  teamcity job step add MyBuild --script "./gradlew test" --name "Run Tests"
+  teamcity job step add MyBuild --file ci/deploy.sh --name Deploy
+  teamcity job step add MyBuild --type simpleRunner --name "Run Tests" --param use.custom.script=true --param script.content="./gradlew test"
   teamcity job step add MyBuild --type gradle-runner --name Build --param gradle.tasks=build
   teamcity job step add --type simpleRunner --param use.custom.script=true --param script.content="make"   # uses linked job`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -216,8 +228,12 @@ the available keys depend on the runner type.`,
 	cmd.Flags().StringVar(&opts.stepType, "type", "", "Runner type ID (e.g. simpleRunner, gradle-runner, Maven2; see 'teamcity job step view')")
 	cmd.Flags().StringVar(&opts.name, "name", "", "Step name")
 	cmd.Flags().StringArrayVar(&opts.params, "param", nil, "Step parameter as key=value (repeatable)")
+	cmd.Flags().StringVar(&opts.script, "script", "", "Script for a Command Line (simpleRunner) step")
+	cmd.Flags().StringVar(&opts.file, "file", "", "Read the script of a Command Line step from a file (- for stdin)")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
-	_ = cmd.MarkFlagRequired("type")
+	cmdutil.AddForceVCSManagedFlag(cmd, &opts.forceVCSManaged)
+	cmd.MarkFlagsMutuallyExclusive("script", "file")
+	_ = cmd.MarkFlagFilename("file")
 
 	return cmd
 }
@@ -227,12 +243,34 @@ func runJobStepAdd(f *cmdutil.Factory, jobID string, opts *jobStepAddOptions) er
 	if err != nil {
 		return err
 	}
+	script, err := stepScript(f, opts)
+	if err != nil {
+		return err
+	}
+	if script != "" {
+		if opts.stepType == "" {
+			opts.stepType = scriptRunnerType
+		} else if opts.stepType != scriptRunnerType {
+			return api.Validation(
+				fmt.Sprintf("--script and --file add a %s step, not %s", scriptRunnerType, opts.stepType),
+				"Drop --type, or set the runner's script parameter with --param",
+			)
+		}
+		props = append([]api.Property{{Name: "use.custom.script", Value: "true"}, {Name: "script.content", Value: script}}, props...)
+	}
+	if opts.stepType == "" {
+		return api.Validation("--type is required", "Pass --type <runner-id>, or --script or --file for a Command Line step")
+	}
 
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	if err := f.GuardVCSManagedJob(client, jobID, opts.forceVCSManaged); err != nil {
+		return err
+	}
+
 	step, err := client.CreateBuildStep(jobID, api.BuildStep{
 		Name:       opts.name,
 		Type:       opts.stepType,
@@ -251,9 +289,41 @@ func runJobStepAdd(f *cmdutil.Factory, jobID string, opts *jobStepAddOptions) er
 		name = step.Type
 	}
 	f.Printer.Success("Added step %q (id: %s) to job %s", name, step.ID, jobID)
+	_, _ = fmt.Fprintf(f.Printer.Out, "  %s\n", client.ServerURL()+"/admin/editBuildRunners.html?id=buildType:"+jobID)
 	return nil
 }
 
+// scriptRunnerType is the runner of the Command Line steps that --script and --file add.
+const scriptRunnerType = "simpleRunner"
+
+// stepScript returns the script of --script or --file, or "" when neither is given.
+func stepScript(f *cmdutil.Factory, opts *jobStepAddOptions) (string, error) {
+	switch {
+	case opts.file == "-":
+		data, err := io.ReadAll(f.IOStreams.In)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return nonEmptyScript(string(data), "stdin")
+	case opts.file != "":
+		data, err := os.ReadFile(opts.file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read script: %w", err)
+		}
+		return nonEmptyScript(string(data), opts.file)
+	case opts.script != "":
+		return opts.script, nil
+	}
+	return "", nil
+}
+
+func nonEmptyScript(script, source string) (string, error) {
+	if strings.TrimSpace(script) == "" {
+		return "", api.Validation(fmt.Sprintf("the script in %s is empty", source), "Write the step's commands to the file first")
+	}
+	return script, nil
+}
+
 func parseStepParams(params []string) ([]api.Property, error) {
 	var props []api.Property
 	for _, p := range params {
@@ -270,12 +340,14 @@ func parseStepParams(params []string) ([]api.Property, error) {
 }
 
 func newJobStepDeleteCmd(f *cmdutil.Factory) *cobra.Command {
+	var forceVCSManaged bool
+
 	cmd := &cobra.Command{
 		Use:               "delete [job-id] <step-id>",
 		Short:             "Delete a build step",
 		Aliases:           []string{"remove", "rm"},
 		Args:              cobra.RangeArgs(1, 2),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.LinkedJobs()),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Jobs()),
 		Example: `  teamcity job step delete MyBuild RUNNER_1
   teamcity job step delete RUNNER_1      # uses linked job`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -283,19 +355,24 @@ func newJobStepDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runJobStepDelete(f, jobID, rest[0])
+			return runJobStepDelete(f, jobID, rest[0], forceVCSManaged)
 		},
 	}
 
+	cmdutil.AddForceVCSManagedFlag(cmd, &forceVCSManaged)
 	return cmd
 }
 
-func runJobStepDelete(f *cmdutil.Factory, jobID, stepID string) error {
+func runJobStepDelete(f *cmdutil.Factory, jobID, stepID string, forceVCSManaged bool) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	if err := f.GuardVCSManagedJob(client, jobID, forceVCSManaged); err != nil {
+		return err
+	}
+
 	if err := client.DeleteBuildStep(jobID, stepID); err != nil {
 		return fmt.Errorf("failed to delete build step: %w", err)
 	}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -43,6 +45,7 @@ func TestJobStepViewWeb(t *testing.T) {
 
 func TestJobStepAdd(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
 
 	var captured []byte
 	ts.Handle("POST /app/rest/buildTypes/id:TestProject_Build/steps", func(w http.ResponseWriter, r *http.Request) {
@@ -64,6 +67,40 @@ func TestJobStepAdd(T *testing.T) {
 	assert.Equal(T, "./gradlew test", payload.Properties.Property[0].Value)
 }
 
+func TestJobStepAddScript(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
+
+	var captured api.BuildStep
+	ts.Handle("POST /app/rest/buildTypes/id:TestProject_Build/steps", func(w http.ResponseWriter, r *http.Request) {
+		captured = api.BuildStep{}
+		require.NoError(T, json.NewDecoder(r.Body).Decode(&captured))
+		cmdtest.JSON(w, api.BuildStep{ID: "RUNNER_3", Name: captured.Name, Type: captured.Type})
+	})
+	props := func() map[string]string {
+		m := map[string]string{}
+		for _, p := range captured.Properties.Property {
+			m[p.Name] = p.Value
+		}
+		return m
+	}
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "step", "add", testJob, "--script", "./gradlew test", "--name", "Test")
+	assert.Contains(T, out, "RUNNER_3")
+	assert.Contains(T, out, ts.URL+"/admin/editBuildRunners.html?id=buildType:"+testJob)
+	assert.Equal(T, "simpleRunner", captured.Type)
+	assert.Equal(T, map[string]string{"use.custom.script": "true", "script.content": "./gradlew test"}, props())
+
+	path := filepath.Join(T.TempDir(), "deploy.sh")
+	require.NoError(T, os.WriteFile(path, []byte("set -e\n./deploy.sh\n"), 0o644))
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "job", "step", "add", testJob, "--file", path, "--type", "simpleRunner")
+	assert.Equal(T, "set -e\n./deploy.sh\n", props()["script.content"])
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "not gradle-runner", "job", "step", "add", testJob, "--script", "make", "--type", "gradle-runner")
+	require.NoError(T, os.WriteFile(path, []byte("\n"), 0o644))
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "is empty", "job", "step", "add", testJob, "--file", path)
+}
+
 func TestJobStepAddRequiresType(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
@@ -79,6 +116,7 @@ func TestJobStepAddInvalidParam(T *testing.T) {
 
 func TestJobStepDelete(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
 
 	out := cmdtest.CaptureOutput(T, ts.Factory, "job", "step", "delete", testJob, "RUNNER_1")
 	assert.Contains(T, out, "Deleted step RUNNER_1")
@@ -0,0 +1,298 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	tokenValid    = "valid"
+	tokenDangling = "dangling"
+	tokenUnknown  = "unknown"
+)
+
+// maxProjectDepth guards the walk up a job's project chain against a cyclic parent reference.
+const maxProjectDepth = 64
+
+var credentialsRefPattern = regexp.MustCompile(`credentialsJSON:[\w-]+`)
+
+// TokenReference is one secure token a job refers to. Project is where the token resolves; it is empty unless
+// Status is "valid".
+type TokenReference struct {
+	Token   string   `json:"token"`
+	Status  string   `json:"status"`
+	Project string   `json:"project,omitempty"`
+	UsedIn  []string `json:"usedIn"`
+}
+
+type jobTokensOptions struct {
+	json           bool
+	fixInteractive bool
+}
+
+func newJobTokensCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &jobTokensOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "tokens [job-id]",
+		Aliases: []string{"token"},
+		Short:   "Check the secure tokens a job refers to",
+		Long: `List the credentialsJSON secure token references in a job's parameters, settings, and build steps,
+and check that each still resolves in the job's project or one of its parents.
+
+A dangling reference points at a token that was deleted or belongs to another project; builds using it fail.
+With --fix-interactive, you are prompted for each dangling token's value and a replacement token is created
+in the job's project; update the job's settings to use it.
+
+Checking a token requires the System Administrator role; without it references show as unknown. Values of
+password parameters are hidden by the server, so references stored in them cannot be found.
+Exits with status 1 when a reference is dangling. With no argument, uses the linked default job from teamcity.toml.`,
+		Example: `  teamcity job tokens Falcon_Build
+  teamcity job tokens Falcon_Build --json
+  teamcity job tokens Falcon_Build --fix-interactive`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.Jobs(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
+			if err != nil {
+				return err
+			}
+			return runJobTokens(f, jobID, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.fixInteractive, "fix-interactive", false, "Prompt to re-create dangling tokens in the job's project")
+	cmd.MarkFlagsMutuallyExclusive("json", "fix-interactive")
+
+	return cmd
+}
+
+func runJobTokens(f *cmdutil.Factory, jobID string, opts *jobTokensOptions) error {
+	if opts.fixInteractive && !f.IsInteractive() {
+		return api.Validation("--fix-interactive needs an interactive terminal",
+			"Re-create a token with 'teamcity project token put <project-id>' and update the job to use it")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	job, err := client.GetBuildType(jobID)
+	if err != nil {
+		return err
+	}
+
+	refs, err := findTokenReferences(client, job.ID)
+	if err != nil {
+		return err
+	}
+
+	chain, err := projectChain(client, job.ProjectID)
+	if err != nil {
+		return err
+	}
+	for i := range refs {
+		if err := resolveTokenReference(client, chain, &refs[i]); err != nil {
+			return err
+		}
+	}
+
+	dangling := 0
+	for _, r := range refs {
+		if r.Status == tokenDangling {
+			dangling++
+		}
+	}
+
+	if opts.json {
+		if err := f.Printer.PrintJSON(refs); err != nil {
+			return err
+		}
+	} else {
+		printTokenReferences(f.Printer, job.ID, refs, dangling)
+	}
+
+	if dangling == 0 {
+		return nil
+	}
+	if opts.fixInteractive {
+		return fixDanglingTokens(f, client, job.ProjectID, refs)
+	}
+	if !opts.json {
+		f.Printer.Tip("Re-create dangling tokens with 'teamcity job tokens %s --fix-interactive'", job.ID)
+	}
+	return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+}
+
+// findTokenReferences scans a job's parameters (values and specs), settings, and build step properties for
+// credentialsJSON references, in order of first use.
+func findTokenReferences(client api.ClientInterface, jobID string) ([]TokenReference, error) {
+	var refs []TokenReference
+	index := map[string]int{}
+	scan := func(text, where string) {
+		for _, token := range credentialsRefPattern.FindAllString(text, -1) {
+			i, ok := index[token]
+			if !ok {
+				i = len(refs)
+				index[token] = i
+				refs = append(refs, TokenReference{Token: token})
+			}
+			if u := refs[i].UsedIn; len(u) == 0 || u[len(u)-1] != where {
+				refs[i].UsedIn = append(refs[i].UsedIn, where)
+			}
+		}
+	}
+
+	params, err := client.GetBuildTypeParameters(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parameters: %w", err)
+	}
+	for _, p := range params.Property {
+		where := "parameter " + p.Name
+		if p.Inherited {
+			where += " (inherited)"
+		}
+		scan(p.Value, where)
+		if p.Type != nil {
+			scan(p.Type.RawValue, where)
+		}
+	}
+
+	settings, err := client.GetBuildTypeSettings(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	for _, s := range settings.Property {
+		scan(s.Value, "setting "+s.Name)
+	}
+
+	steps, err := client.GetBuildSteps(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build steps: %w", err)
+	}
+	for _, step := range steps.Step {
+		for _, prop := range step.Properties.Property {
+			scan(prop.Value, fmt.Sprintf("step %q (%s)", step.Name, prop.Name))
+		}
+	}
+
+	return refs, nil
+}
+
+// projectChain returns projectID followed by its ancestors up to the root: the projects whose tokens a job can use.
+func projectChain(client api.ClientInterface, projectID string) ([]string, error) {
+	var chain []string
+	for id := projectID; id != "" && len(chain) < maxProjectDepth; {
+		chain = append(chain, id)
+		p, err := client.GetProject(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project %s: %w", id, err)
+		}
+		id = p.ParentProjectID
+	}
+	return chain, nil
+}
+
+// resolveTokenReference looks the token up in each project of chain, nearest first, without printing its value.
+func resolveTokenReference(client api.ClientInterface, chain []string, ref *TokenReference) error {
+	token := strings.TrimPrefix(ref.Token, "credentialsJSON:")
+	for _, projectID := range chain {
+		_, err := client.GetSecureValue(projectID, token)
+		if err == nil {
+			ref.Status, ref.Project = tokenValid, projectID
+			return nil
+		}
+		if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+			continue
+		}
+		if _, ok := errors.AsType[*api.PermissionError](err); ok {
+			ref.Status = tokenUnknown
+			return nil
+		}
+		return fmt.Errorf("failed to check %s in project %s: %w", ref.Token, projectID, err)
+	}
+	ref.Status = tokenDangling
+	return nil
+}
+
+func printTokenReferences(p *output.Printer, jobID string, refs []TokenReference, dangling int) {
+	if len(refs) == 0 {
+		p.Empty(fmt.Sprintf("No secure token references found in %s", jobID), "")
+		return
+	}
+
+	headers := []string{"TOKEN", "STATUS", "USED IN"}
+	var rows [][]string
+	unknown := 0
+	for _, r := range refs {
+		status := output.Green(tokenValid) + " " + output.Faint("("+r.Project+")")
+		switch r.Status {
+		case tokenDangling:
+			status = output.Red(tokenDangling)
+		case tokenUnknown:
+			status = output.Faint(tokenUnknown)
+			unknown++
+		}
+		rows = append(rows, []string{r.Token, status, strings.Join(r.UsedIn, ", ")})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0, 2)
+	p.PrintTable(headers, rows)
+
+	if unknown > 0 {
+		p.Warn("Could not check %d of %d tokens: checking needs the System Administrator role", unknown, len(refs))
+	}
+	if dangling > 0 {
+		_, _ = fmt.Fprintln(p.Out)
+		_, _ = fmt.Fprintf(p.Out, "%s %d of %d tokens no longer resolve\n", output.Red(output.Sym().Cross), dangling, len(refs))
+	}
+}
+
+// fixDanglingTokens offers to re-create each dangling token in projectID from a value the user enters, as
+// 'project token put' does, and says where to use the new token. Settings are not rewritten: they often live in
+// version control.
+func fixDanglingTokens(f *cmdutil.Factory, client api.ClientInterface, projectID string, refs []TokenReference) error {
+	remaining := 0
+	for _, r := range refs {
+		if r.Status != tokenDangling {
+			continue
+		}
+		_, _ = fmt.Fprintln(f.Printer.Out)
+		recreate := false
+		if err := cmdutil.Confirm(fmt.Sprintf("Re-create %s (used in %s)?", r.Token, strings.Join(r.UsedIn, ", ")), &recreate); err != nil {
+			return err
+		}
+		if !recreate {
+			remaining++
+			continue
+		}
+
+		var value string
+		if err := cmdutil.PromptSecret("Enter the secure value", &value); err != nil {
+			return fmt.Errorf("failed to read value: %w", err)
+		}
+		if value == "" {
+			return errors.New("value cannot be empty")
+		}
+		token, err := client.CreateSecureToken(projectID, value)
+		if err != nil {
+			return fmt.Errorf("failed to create secure token: %w", err)
+		}
+		f.Printer.Success("Created %s in %s", token, projectID)
+		f.Printer.Info("  Replace %s with it in %s", r.Token, strings.Join(r.UsedIn, ", "))
+	}
+
+	if remaining > 0 {
+		return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+	}
+	return nil
+}
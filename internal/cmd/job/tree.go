@@ -44,7 +44,7 @@ func newJobTreeCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "tree [job-id]",
 		Short:             "Display snapshot dependency tree",
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Long:              "Display the snapshot dependency tree for a build configuration. With no argument, uses the linked default job from teamcity.toml.",
 		Example: `  teamcity job tree MyProject_Build
   teamcity job tree                          # uses linked default job
@@ -0,0 +1,198 @@
+package job
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+// printJobSections prints the steps, triggers, VCS roots and dependencies of job view --full.
+func printJobSections(p *output.Printer, d *api.BuildTypeDetail) {
+	section := func(title string, count int, empty string) bool {
+		if count == 0 {
+			_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint(empty))
+			return false
+		}
+		_, _ = fmt.Fprintf(p.Out, "\n%s (%d)\n", output.Bold(title), count)
+		return true
+	}
+
+	var steps []api.BuildStep
+	if d.Steps != nil {
+		steps = d.Steps.Step
+	}
+	if section("Steps", len(steps), "No build steps") {
+		for i, s := range steps {
+			_, _ = fmt.Fprintf(p.Out, "  %d. %s  %s  %s\n", i+1, cmp.Or(s.Name, s.ID), output.Faint(s.Type), stepStatus(s.Disabled))
+		}
+	}
+
+	var triggers []api.BuildTrigger
+	if d.Triggers != nil {
+		triggers = d.Triggers.Trigger
+	}
+	if section("Triggers", len(triggers), "No triggers: runs start only by hand or from other jobs") {
+		for _, t := range triggers {
+			kind, detail := triggerSummary(t)
+			line := "  " + kind
+			if detail != "" {
+				line += "  " + detail
+			}
+			if t.Disabled {
+				line += "  " + output.Faint("disabled")
+			}
+			_, _ = fmt.Fprintln(p.Out, line)
+		}
+	}
+
+	var roots []api.VcsRootEntry
+	if d.VcsRootEntries != nil {
+		roots = d.VcsRootEntries.VcsRootEntry
+	}
+	if section("VCS roots", len(roots), "No VCS roots") {
+		for _, e := range roots {
+			id, name := e.ID, ""
+			if e.VcsRoot != nil {
+				id, name = cmp.Or(e.VcsRoot.ID, e.ID), e.VcsRoot.Name
+			}
+			line := "  " + id
+			if name != "" && name != id {
+				line += "  " + name
+			}
+			if rules := joinLines(e.CheckoutRules); rules != "" {
+				line += "  " + output.Faint("checkout rules: ") + rules
+			}
+			_, _ = fmt.Fprintln(p.Out, line)
+		}
+	}
+
+	var snapshot []api.SnapshotDependency
+	if d.SnapshotDependencies != nil {
+		snapshot = d.SnapshotDependencies.SnapshotDependency
+	}
+	var artifact []api.ArtifactDependency
+	if d.ArtifactDependencies != nil {
+		artifact = d.ArtifactDependencies.ArtifactDependency
+	}
+	if section("Dependencies", len(snapshot)+len(artifact), "No dependencies") {
+		for _, dep := range snapshot {
+			_, _ = fmt.Fprintf(p.Out, "  %s  %s\n", output.Faint("snapshot "), dependencySource(dep.SourceBuildType))
+		}
+		for _, dep := range artifact {
+			line := fmt.Sprintf("  %s  %s", output.Faint("artifacts"), dependencySource(dep.SourceBuildType))
+			if rules := joinLines(propertyValue(dep.Properties, "pathRules")); rules != "" {
+				line += "  " + rules
+			}
+			if dep.Disabled {
+				line += "  " + output.Faint("disabled")
+			}
+			_, _ = fmt.Fprintln(p.Out, line)
+		}
+	}
+}
+
+func dependencySource(bt *api.BuildType) string {
+	if bt == nil {
+		return "?"
+	}
+	if bt.Name != "" && bt.Name != bt.ID {
+		return bt.ID + "  " + bt.Name
+	}
+	return bt.ID
+}
+
+// triggerSummary describes a trigger in a word and one line: what fires it, and which branches it watches.
+func triggerSummary(t api.BuildTrigger) (kind, detail string) {
+	prop := func(name string) string { return propertyValue(t.Properties, name) }
+	var parts []string
+	switch t.Type {
+	case "vcsTrigger":
+		kind = "VCS"
+		if rules := joinLines(prop("triggerRules")); rules != "" {
+			parts = append(parts, "rules: "+rules)
+		}
+		if prop("quietPeriodMode") == "USE_CUSTOM" && prop("quietPeriod") != "" {
+			parts = append(parts, "quiet period "+prop("quietPeriod")+"s")
+		}
+	case "schedulingTrigger":
+		kind = "Schedule"
+		parts = append(parts, scheduleSummary(prop))
+		if prop("triggerBuildWithPendingChangesOnly") == "true" {
+			parts = append(parts, "only with pending changes")
+		}
+	case "buildDependencyTrigger":
+		kind = "Finish build"
+		when := "finishes"
+		if prop("afterSuccessfulBuildOnly") == "true" {
+			when = "succeeds"
+		}
+		parts = append(parts, "when "+cmp.Or(prop("dependsOn"), "?")+" "+when)
+	default:
+		kind = t.Type
+	}
+	if branches := joinLines(prop("branchFilter")); branches != "" {
+		parts = append(parts, "branches: "+branches)
+	}
+	return kind, strings.Join(parts, ", ")
+}
+
+// scheduleSummary reads a schedulingTrigger's policy: daily, weekly, or a cron expression.
+func scheduleSummary(prop func(string) string) string {
+	at := clockTime(prop("hour"), prop("minute"))
+	var s string
+	switch prop("schedulingPolicy") {
+	case "daily":
+		s = "daily at " + at
+	case "weekly":
+		s = "weekly on " + prop("dayOfWeek") + " at " + at
+	case "cron":
+		fields := []string{prop("cronExpression_sec"), prop("cronExpression_min"), prop("cronExpression_hour"),
+			prop("cronExpression_dm"), prop("cronExpression_month"), prop("cronExpression_dw")}
+		if year := prop("cronExpression_year"); year != "" && year != "*" {
+			fields = append(fields, year)
+		}
+		s = "cron " + strings.Join(fields, " ")
+	default:
+		s = cmp.Or(prop("schedulingPolicy"), "scheduled")
+	}
+	if tz := prop("timezone"); tz != "" && tz != "SERVER" {
+		s += " " + tz
+	}
+	return s
+}
+
+func clockTime(hour, minute string) string {
+	h, err1 := strconv.Atoi(hour)
+	m, err2 := strconv.Atoi(cmp.Or(minute, "0"))
+	if err1 != nil || err2 != nil {
+		return hour + ":" + minute
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+func propertyValue(props *api.PropertyList, name string) string {
+	if props == nil {
+		return ""
+	}
+	for _, p := range props.Property {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// joinLines puts the non-empty lines of a multi-line setting, such as a branch filter, on one line.
+func joinLines(s string) string {
+	var lines []string
+	for line := range strings.Lines(s) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, ", ")
+}
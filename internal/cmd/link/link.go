@@ -154,9 +154,9 @@ Resolution cascade (highest to lowest):
 	cmd.Flags().BoolVarP(&auto, "auto", "a", false, "Auto-discover the binding from git remotes; mutually exclusive with --project/--job/--jobs")
 
 	_ = cmd.RegisterFlagCompletionFunc("server", completion.ConfiguredServers())
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
-	_ = cmd.RegisterFlagCompletionFunc("job", completion.LinkedJobs())
-	_ = cmd.RegisterFlagCompletionFunc("jobs", completion.LinkedJobs())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+	_ = cmd.RegisterFlagCompletionFunc("jobs", completion.Jobs())
 	_ = cmd.RegisterFlagCompletionFunc("scope", completion.LinkScopes())
 
 	return cmd
@@ -374,11 +374,7 @@ func summarizeSimplifications(items []string) string {
 
 // resolveSchema fetches the cached server schema, falling back to the embedded one when offline.
 func resolveSchema(client api.ClientInterface) []byte {
-	c, ok := client.(*api.Client)
-	if !ok {
-		return pipelineschema.Bytes
-	}
-	schema, _, _, err := cmdutil.FetchOrCachePipelineSchema(c, false)
+	schema, _, _, err := cmdutil.FetchOrCachePipelineSchema(client, false)
 	if err != nil {
 		return pipelineschema.Bytes
 	}
@@ -1,6 +1,7 @@
 package param
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -18,6 +19,8 @@ type ParamAPI struct {
 	Get    func(client api.ClientInterface, id, name string) (*api.Parameter, error)
 	Set    func(client api.ClientInterface, id, name, value string, secure bool) error
 	Delete func(client api.ClientInterface, id, name string) error
+	// Parent returns the project the resource inherits parameters from, or "" for the root project.
+	Parent func(client api.ClientInterface, id string) (string, error)
 }
 
 var ProjectParamAPI = ParamAPI{
@@ -29,6 +32,13 @@ var ProjectParamAPI = ParamAPI{
 		return c.SetProjectParameter(id, name, value, secure)
 	},
 	Delete: func(c api.ClientInterface, id, name string) error { return c.DeleteProjectParameter(id, name) },
+	Parent: func(c api.ClientInterface, id string) (string, error) {
+		project, err := c.GetProject(id)
+		if err != nil {
+			return "", err
+		}
+		return project.ParentProjectID, nil
+	},
 }
 
 var JobParamAPI = ParamAPI{
@@ -42,18 +52,26 @@ var JobParamAPI = ParamAPI{
 		return c.SetBuildTypeParameter(id, name, value, secure)
 	},
 	Delete: func(c api.ClientInterface, id, name string) error { return c.DeleteBuildTypeParameter(id, name) },
+	Parent: func(c api.ClientInterface, id string) (string, error) {
+		job, err := c.GetBuildType(id)
+		if err != nil {
+			return "", err
+		}
+		return job.ProjectID, nil
+	},
 }
 
 // NewCmd creates the param command group for a resource (project or job), using resolveID as the linked default.
 func NewCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver) *cobra.Command {
-	idComplete := completion.LinkedProjects()
+	idComplete := completion.Projects()
 	if resource == "job" {
-		idComplete = completion.LinkedJobs()
+		idComplete = completion.Jobs()
 	}
 	cmd := &cobra.Command{
 		Use:   "param",
 		Short: fmt.Sprintf("Manage %s parameters", resource),
-		Long: fmt.Sprintf(`List, get, set, and delete %s parameters.
+		Long: fmt.Sprintf(`List, get, set, and delete %s parameters, or export them to a YAML
+file and import them from one.
 
 Parameters are typed key-value pairs attached to a %s. They drive
 build behavior, can reference other parameters, and may be marked
@@ -71,6 +89,8 @@ See: https://www.jetbrains.com/help/teamcity/configuring-build-parameters.html`,
 	cmd.AddCommand(newParamGetCmd(f, resource, paramAPI, resolveID, idComplete))
 	cmd.AddCommand(newParamSetCmd(f, resource, paramAPI, resolveID, idComplete))
 	cmd.AddCommand(newParamDeleteCmd(f, resource, paramAPI, resolveID, idComplete))
+	cmd.AddCommand(newParamExportCmd(f, resource, paramAPI, resolveID, idComplete))
+	cmd.AddCommand(newParamImportCmd(f, resource, paramAPI, resolveID, idComplete))
 
 	return cmd
 }
@@ -160,31 +180,54 @@ func runParamList(f *cmdutil.Factory, resource, id string, opts *cmdutil.ListOpt
 	return nil
 }
 
+type paramGetOptions struct {
+	json          bool
+	showEffective bool
+}
+
 func newParamGetCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
-	var jsonOutput bool
+	opts := &paramGetOptions{}
 
 	cmd := &cobra.Command{
-		Use:               fmt.Sprintf("get [%s-id] <name>", resource),
-		Short:             fmt.Sprintf("Get a %s parameter value", resource),
+		Use:   fmt.Sprintf("get [%s-id] <name>", resource),
+		Short: fmt.Sprintf("Get a %s parameter value", resource),
+		Long: fmt.Sprintf(`Print the value a %s uses for a parameter, whether set on the %s itself
+or inherited from a parent project or template.
+
+--show-effective also tells where the value comes from: set on the %s,
+set on it overriding a value from its parent project, or inherited.`, resource, resource, resource),
 		Args:              cobra.RangeArgs(1, 2),
 		ValidArgsFunction: cmdutil.CompleteOwnerID(idComplete),
 		Example: fmt.Sprintf(`  teamcity %s param get MyID MY_PARAM
   teamcity %s param get MY_PARAM         # uses linked %s
-  teamcity %s param get MyID VERSION`, resource, resource, resource, resource),
+  teamcity %s param get MyID VERSION --show-effective`, resource, resource, resource, resource),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, rest, err := cmdutil.ResolveOwnerID(resource, args, 1, resolveID)
 			if err != nil {
 				return err
 			}
-			return runParamGet(f, id, rest[0], jsonOutput, paramAPI)
+			return runParamGet(f, resource, id, rest[0], opts, paramAPI)
 		},
 	}
 
-	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.showEffective, "show-effective", false, "Also show whether the value is set here, overrides a parent value, or is inherited")
 	return cmd
 }
 
-func runParamGet(f *cmdutil.Factory, id, name string, jsonOutput bool, paramAPI ParamAPI) error {
+// effectiveParam is the --show-effective --json form of a parameter.
+type effectiveParam struct {
+	*api.Parameter
+	Overrides *overriddenParam `json:"overrides,omitempty"`
+}
+
+// overriddenParam is the parent project's value that a parameter set on its owner shadows.
+type overriddenParam struct {
+	Project string `json:"project"`
+	Value   string `json:"value"`
+}
+
+func runParamGet(f *cmdutil.Factory, resource, id, name string, opts *paramGetOptions, paramAPI ParamAPI) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
@@ -195,21 +238,80 @@ func runParamGet(f *cmdutil.Factory, id, name string, jsonOutput bool, paramAPI
 		return err
 	}
 
-	if jsonOutput {
-		return f.Printer.PrintJSON(param)
+	if !opts.showEffective {
+		if opts.json {
+			return f.Printer.PrintJSON(param)
+		}
+		_, _ = fmt.Fprintln(f.Printer.Out, displayValue(param))
+		return nil
+	}
+
+	var overrides *overriddenParam
+	if !param.Inherited {
+		parent, inherited, err := parentParam(client, paramAPI, id, name)
+		if err != nil {
+			return err
+		}
+		if inherited != nil {
+			overrides = &overriddenParam{Project: parent, Value: inherited.Value}
+		}
 	}
 
-	value := param.Value
-	if param.Type != nil && param.Type.RawValue == "password" {
-		value = "********"
+	if opts.json {
+		return f.Printer.PrintJSON(effectiveParam{Parameter: param, Overrides: overrides})
 	}
 
-	_, _ = fmt.Fprintln(f.Printer.Out, value)
+	p := f.Printer
+	_, _ = fmt.Fprintln(p.Out, displayValue(param))
+	switch {
+	case param.Inherited:
+		_, _ = fmt.Fprintln(p.Out, output.Faint(fmt.Sprintf("Inherited; not set on %s %s itself", resource, id)))
+	case overrides != nil:
+		shadowed := overrides.Value
+		if param.IsPassword() {
+			shadowed = "********"
+		}
+		_, _ = fmt.Fprintln(p.Out, output.Faint(fmt.Sprintf("Set on %s %s, overriding %q from project %s", resource, id, shadowed, overrides.Project)))
+	default:
+		_, _ = fmt.Fprintln(p.Out, output.Faint(fmt.Sprintf("Set on %s %s", resource, id)))
+	}
 	return nil
 }
 
+// displayValue returns the parameter's value, masked for password parameters.
+func displayValue(param *api.Parameter) string {
+	if param.IsPassword() {
+		return "********"
+	}
+	return param.Value
+}
+
+// parentParam returns the parent project of the resource and the value it would pass down for name,
+// or nil when the parent has none (or the resource is the root project).
+func parentParam(client api.ClientInterface, paramAPI ParamAPI, id, name string) (string, *api.Parameter, error) {
+	parent, err := paramAPI.Parent(client, id)
+	if err != nil || parent == "" {
+		return "", nil, err
+	}
+	param, err := ProjectParamAPI.Get(client, parent, name)
+	if isMissingParam(err) {
+		return parent, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return parent, param, nil
+}
+
+// isMissingParam reports whether err is a 404 for the parameter itself rather than for its owner.
+func isMissingParam(err error) bool {
+	nf, ok := errors.AsType[*api.NotFoundError](err)
+	return ok && nf.Resource == ""
+}
+
 type paramSetOptions struct {
-	secure bool
+	secure          bool
+	forceVCSManaged bool
 }
 
 func newParamSetCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
@@ -234,21 +336,26 @@ Omit the <%s-id> when this repo is linked via 'teamcity link'.`, resource, resou
 			if err != nil {
 				return err
 			}
-			return runParamSet(f, id, rest[0], rest[1], opts, paramAPI)
+			return runParamSet(f, resource, id, rest[0], rest[1], opts, paramAPI)
 		},
 	}
 
 	cmd.Flags().BoolVar(&opts.secure, "secure", false, "Mark as secure/password parameter")
+	cmdutil.AddForceVCSManagedFlag(cmd, &opts.forceVCSManaged)
 
 	return cmd
 }
 
-func runParamSet(f *cmdutil.Factory, id, name, value string, opts *paramSetOptions, paramAPI ParamAPI) error {
+func runParamSet(f *cmdutil.Factory, resource, id, name, value string, opts *paramSetOptions, paramAPI ParamAPI) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	if err := guardVCSManaged(f, client, resource, id, opts.forceVCSManaged); err != nil {
+		return err
+	}
+
 	if err := paramAPI.Set(client, id, name, value, opts.secure); err != nil {
 		return fmt.Errorf("failed to set parameter: %w", err)
 	}
@@ -258,9 +365,17 @@ func runParamSet(f *cmdutil.Factory, id, name, value string, opts *paramSetOptio
 }
 
 func newParamDeleteCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
+	var forceVCSManaged bool
+
 	cmd := &cobra.Command{
-		Use:               fmt.Sprintf("delete [%s-id] <name>", resource),
-		Short:             fmt.Sprintf("Delete a %s parameter", resource),
+		Use:   fmt.Sprintf("delete [%s-id] <name>", resource),
+		Short: fmt.Sprintf("Delete a %s parameter", resource),
+		Long: fmt.Sprintf(`Delete a parameter set on the %s itself.
+
+If the %s overrides a value from its parent project, deleting the override
+makes the %s inherit that value again; the new effective value is printed.
+A parameter the %s only inherits cannot be deleted here; delete it in the
+project that defines it.`, resource, resource, resource, resource),
 		Args:              cobra.RangeArgs(1, 2),
 		ValidArgsFunction: cmdutil.CompleteOwnerID(idComplete),
 		Example: fmt.Sprintf(`  teamcity %s param delete MyID MY_PARAM
@@ -270,23 +385,65 @@ func newParamDeleteCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, r
 			if err != nil {
 				return err
 			}
-			return runParamDelete(f, id, rest[0], paramAPI)
+			return runParamDelete(f, resource, id, rest[0], forceVCSManaged, paramAPI)
 		},
 	}
 
+	cmdutil.AddForceVCSManagedFlag(cmd, &forceVCSManaged)
 	return cmd
 }
 
-func runParamDelete(f *cmdutil.Factory, id, name string, paramAPI ParamAPI) error {
+func runParamDelete(f *cmdutil.Factory, resource, id, name string, forceVCSManaged bool, paramAPI ParamAPI) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	if err := guardVCSManaged(f, client, resource, id, forceVCSManaged); err != nil {
+		return err
+	}
+
+	// Check first: some servers answer a delete of a missing or inherited parameter with success.
+	param, err := paramAPI.Get(client, id, name)
+	if isMissingParam(err) {
+		return api.Validation(
+			fmt.Sprintf("%s %s has no parameter %s", resource, id, name),
+			fmt.Sprintf("Run 'teamcity %s param list %s' to see its parameters", resource, id),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	parent, inherited, err := parentParam(client, paramAPI, id, name)
+	if err != nil {
+		return err
+	}
+	if param.Inherited {
+		tip := "Delete it in the project that defines it"
+		if parent != "" {
+			tip = fmt.Sprintf("Delete it in the project that defines it, e.g. 'teamcity project param delete %s %s'", parent, name)
+		}
+		return api.Validation(fmt.Sprintf("parameter %s is inherited by %s %s, not set on it", name, resource, id), tip)
+	}
+
 	if err := paramAPI.Delete(client, id, name); err != nil {
 		return fmt.Errorf("failed to delete parameter: %w", err)
 	}
 
-	f.Printer.Success("Deleted parameter %s", name)
+	p := f.Printer
+	p.Success("Deleted parameter %s", name)
+	if inherited != nil {
+		p.Info("%s %s now inherits %q from project %s", resource, id, displayValue(inherited), parent)
+	} else {
+		p.Info("%s is no longer set for %s %s", name, resource, id)
+	}
 	return nil
 }
+
+// guardVCSManaged checks the owning project's versioned settings before a parameter edit.
+func guardVCSManaged(f *cmdutil.Factory, client api.ClientInterface, resource, id string, force bool) error {
+	if resource == "job" {
+		return f.GuardVCSManagedJob(client, id, force)
+	}
+	return f.GuardVCSManagedProject(client, id, force)
+}
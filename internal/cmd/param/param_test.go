@@ -1,10 +1,18 @@
 package param_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParamListProject(t *testing.T) {
@@ -24,6 +32,7 @@ func TestParamGetProject(t *testing.T) {
 
 func TestParamSetProject(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	cmdtest.RunCmdWithFactory(t, f, "project", "param", "set", "TestProject", "myParam", "myValue")
@@ -32,6 +41,7 @@ func TestParamSetProject(t *testing.T) {
 
 func TestParamDeleteProject(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	cmdtest.RunCmdWithFactory(t, f, "project", "param", "delete", "TestProject", "myParam")
@@ -54,6 +64,7 @@ func TestParamGetJob(t *testing.T) {
 
 func TestParamSetJob(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	cmdtest.RunCmdWithFactory(t, f, "job", "param", "set", "TestProject_Build", "myParam", "myValue")
@@ -61,6 +72,7 @@ func TestParamSetJob(t *testing.T) {
 
 func TestParamDeleteJob(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	cmdtest.RunCmdWithFactory(t, f, "job", "param", "delete", "TestProject_Build", "myParam")
@@ -121,3 +133,291 @@ func TestParamSetMissingArgs(t *testing.T) {
 
 	cmdtest.RunCmdWithFactoryExpectErr(t, f, "accepts between 2 and 3 arg(s)", "project", "param", "set", "name")
 }
+
+func TestParamSetVCSManaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  http.HandlerFunc
+		blocked bool
+	}{
+		{
+			name: "synchronized from VCS",
+			config: func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", BuildSettingsMode: "useFromVCS"})
+			},
+			blocked: true,
+		},
+		{
+			name: "synchronization disabled",
+			config: func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "disabled", BuildSettingsMode: "useFromVCS"})
+			},
+		},
+		{
+			name: "not configured",
+			config: func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.Error(w, http.StatusNotFound, "Versioned settings are not configured for this project")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := cmdtest.SetupMockClient(t)
+			var writes atomic.Int32
+			ts.Handle("GET /app/rest/projects/VcsProject/versionedSettings/config", tc.config)
+			ts.Handle("PUT /app/rest/projects/id:VcsProject/parameters/", func(w http.ResponseWriter, r *http.Request) {
+				writes.Add(1)
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			if !tc.blocked {
+				out := cmdtest.CaptureOutput(t, ts.Factory, "project", "param", "set", "VcsProject", "KEY", "value")
+				assert.NotContains(t, out, "from VCS")
+				assert.Equal(t, int32(1), writes.Load())
+				return
+			}
+
+			err := cmdtest.CaptureErr(t, ts.Factory, "project", "param", "set", "VcsProject", "KEY", "value")
+			assert.Contains(t, err.Error(), "takes its settings from VCS")
+			assert.Zero(t, writes.Load(), "a blocked edit must not reach the server")
+
+			out := cmdtest.CaptureOutput(t, ts.Factory, "project", "param", "set", "VcsProject", "KEY", "value", "--force-vcs-managed")
+			assert.Contains(t, out, "may be overwritten on the next sync")
+			assert.Equal(t, int32(1), writes.Load())
+		})
+	}
+}
+
+func TestParamVCSManagedJobCachesLookup(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var lookups atomic.Int32
+	ts.Handle("GET /app/rest/projects/TestProject/versionedSettings/config", func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", BuildSettingsMode: "useFromVCS"})
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "project TestProject takes its settings from VCS",
+		"job", "param", "delete", "TestProject_Build", "KEY")
+	cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "param", "set", "TestProject_Build", "KEY", "value", "--force-vcs-managed")
+	cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "param", "delete", "TestProject_Build", "KEY", "--force-vcs-managed")
+	assert.Equal(t, int32(1), lookups.Load(), "versioned settings are looked up once per project")
+}
+
+// setupInheritanceServer serves job TestProject_Build in project TestProject with three parameters:
+// OWN set only on the job, OVERRIDE set on both, and SHARED set only on the project.
+func setupInheritanceServer(t *testing.T) (*cmdtest.TestServer, *atomic.Int32) {
+	t.Helper()
+	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
+	jobParams := map[string]api.Parameter{
+		"OWN":      {Name: "OWN", Value: "job-only"},
+		"OVERRIDE": {Name: "OVERRIDE", Value: "job-value"},
+		"SHARED":   {Name: "SHARED", Value: "project-value", Inherited: true},
+	}
+	projectParams := map[string]api.Parameter{
+		"OVERRIDE": {Name: "OVERRIDE", Value: "project-value"},
+		"SHARED":   {Name: "SHARED", Value: "project-value"},
+	}
+	serve := func(params map[string]api.Parameter) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			p, ok := params[name]
+			if !ok {
+				cmdtest.Error(w, http.StatusNotFound, "No parameter with name '"+name+"' is found.")
+				return
+			}
+			cmdtest.JSON(w, p)
+		}
+	}
+	ts.Handle("GET /app/rest/buildTypes/id:TestProject_Build/parameters/", serve(jobParams))
+	ts.Handle("GET /app/rest/projects/id:TestProject/parameters/", serve(projectParams))
+
+	var deletes atomic.Int32
+	ts.Handle("DELETE /app/rest/buildTypes/id:TestProject_Build/parameters/", func(w http.ResponseWriter, r *http.Request) {
+		deletes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return ts, &deletes
+}
+
+func TestParamDeleteInheritance(t *testing.T) {
+	t.Run("own", func(t *testing.T) {
+		ts, deletes := setupInheritanceServer(t)
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "delete", "TestProject_Build", "OWN")
+		assert.Contains(t, out, "Deleted parameter OWN")
+		assert.Contains(t, out, "OWN is no longer set for job TestProject_Build")
+		assert.Equal(t, int32(1), deletes.Load())
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		ts, deletes := setupInheritanceServer(t)
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "delete", "TestProject_Build", "OVERRIDE")
+		assert.Contains(t, out, "Deleted parameter OVERRIDE")
+		assert.Contains(t, out, `job TestProject_Build now inherits "project-value" from project TestProject`)
+		assert.Equal(t, int32(1), deletes.Load())
+	})
+
+	t.Run("inherited only", func(t *testing.T) {
+		ts, deletes := setupInheritanceServer(t)
+		err := cmdtest.CaptureErr(t, ts.Factory, "job", "param", "delete", "TestProject_Build", "SHARED")
+		assert.Contains(t, err.Error(), "parameter SHARED is inherited by job TestProject_Build, not set on it")
+		var ve *api.ValidationError
+		if assert.ErrorAs(t, err, &ve) {
+			assert.Contains(t, ve.Tip, "teamcity project param delete TestProject SHARED")
+		}
+		assert.Zero(t, deletes.Load(), "an inherited parameter must not be deleted")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		ts, deletes := setupInheritanceServer(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "job TestProject_Build has no parameter NOPE",
+			"job", "param", "delete", "TestProject_Build", "NOPE")
+		assert.Zero(t, deletes.Load())
+	})
+}
+
+func TestParamGetShowEffective(t *testing.T) {
+	ts, _ := setupInheritanceServer(t)
+
+	tests := map[string]string{
+		"OWN":      "Set on job TestProject_Build\n",
+		"OVERRIDE": `Set on job TestProject_Build, overriding "project-value" from project TestProject`,
+		"SHARED":   "Inherited; not set on job TestProject_Build itself",
+	}
+	for name, want := range tests {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "get", "TestProject_Build", name, "--show-effective")
+		assert.Contains(t, out, want, name)
+	}
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "get", "TestProject_Build", "OVERRIDE", "--show-effective", "--json")
+	assert.JSONEq(t, `{"name":"OVERRIDE","value":"job-value","overrides":{"project":"TestProject","value":"project-value"}}`, out)
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "get", "TestProject_Build", "OWN")
+	assert.Equal(t, "job-only\n", out, "without --show-effective only the value is printed")
+}
+
+// setupTransferServer serves TestProject_Build with an own plain, own secure and inherited parameter, recording
+// every PUT and DELETE of a parameter as "METHOD name value".
+func setupTransferServer(t *testing.T) (*cmdtest.TestServer, *[]string) {
+	t.Helper()
+	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
+	ts.Handle("GET /app/rest/buildTypes/id:TestProject_Build/parameters", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ParameterList{Count: 4, Property: []api.Parameter{
+			{Name: "env.REGION", Value: "eu"},
+			{Name: "OLD", Value: "gone"},
+			{Name: "TOKEN", Type: &api.ParameterType{RawValue: "password"}},
+			{Name: "SHARED", Value: "from-project", Inherited: true},
+		}})
+	})
+	var calls []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		var p api.Parameter
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		if p.IsPassword() {
+			p.Value += " (secure)"
+		}
+		calls = append(calls, strings.TrimSpace(r.Method+" "+name+" "+p.Value))
+		w.WriteHeader(http.StatusNoContent)
+	}
+	ts.Handle("PUT /app/rest/buildTypes/id:TestProject_Build/parameters/", record)
+	ts.Handle("DELETE /app/rest/buildTypes/id:TestProject_Build/parameters/", record)
+	return ts, &calls
+}
+
+func TestParamExport(t *testing.T) {
+	ts, _ := setupTransferServer(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "export", "TestProject_Build")
+	assert.Equal(t, cmdtest.Dedent(`
+		# Parameters of job TestProject_Build. Apply with: teamcity job param import <job-id> -f <file>
+		parameters:
+		  - name: env.REGION
+		    value: eu
+		  - name: OLD
+		    value: gone
+		  - name: TOKEN
+		    secure: true
+	`), out)
+
+	file := filepath.Join(t.TempDir(), "params.yml")
+	out = cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "export", "TestProject_Build", "-o", file)
+	assert.Contains(t, out, "Exported 3 parameters of job TestProject_Build to "+file)
+	assert.Contains(t, out, "1 secure parameters have no value in the file")
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: TOKEN\n    secure: true\n")
+}
+
+func TestParamImport(t *testing.T) {
+	writeFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "params.yml")
+		require.NoError(t, os.WriteFile(path, []byte(cmdtest.Dedent(content)), 0o600))
+		return path
+	}
+	file := `
+		parameters:
+		  - name: env.REGION
+		    value: eu
+		  - name: env.ZONE
+		    value: b
+		  - name: TOKEN
+		    secure: true
+		  - name: DEPLOY_KEY
+		    secure: true
+		    valueFrom: env:TC_TEST_DEPLOY_KEY
+	`
+	t.Setenv("TC_TEST_DEPLOY_KEY", "s3cret")
+
+	t.Run("adds and updates", func(t *testing.T) {
+		ts, calls := setupTransferServer(t)
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "import", "TestProject_Build", "-f", writeFile(t, file), "--yes")
+		assert.Contains(t, out, "Leaving secure parameter TOKEN as it is")
+		assert.NotContains(t, out, "s3cret", "secure values are masked in the plan")
+		assert.Contains(t, out, "Applied 2 changes to job TestProject_Build")
+		assert.Equal(t, []string{"PUT DEPLOY_KEY s3cret (secure)", "PUT env.ZONE b"}, *calls)
+	})
+
+	t.Run("prune deletes what the file leaves out", func(t *testing.T) {
+		ts, calls := setupTransferServer(t)
+		cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "param", "import", "TestProject_Build", "-f", writeFile(t, file), "--yes", "--prune")
+		assert.Equal(t, []string{"PUT DEPLOY_KEY s3cret (secure)", "DELETE OLD", "PUT env.ZONE b"}, *calls,
+			"inherited parameters are never pruned")
+	})
+
+	t.Run("nothing to do", func(t *testing.T) {
+		ts, calls := setupTransferServer(t)
+		out := cmdtest.CaptureOutput(t, ts.Factory, "job", "param", "import", "TestProject_Build", "-f",
+			writeFile(t, "parameters:\n  - name: env.REGION\n    value: eu\n"), "--yes")
+		assert.Contains(t, out, "already match the file")
+		assert.Empty(t, *calls)
+	})
+
+	errorCases := map[string]struct {
+		content, want string
+	}{
+		"unset env":           {"parameters:\n  - name: K\n    valueFrom: env:TC_TEST_UNSET_VAR\n", "takes its value from TC_TEST_UNSET_VAR, which is not set"},
+		"unknown valueFrom":   {"parameters:\n  - name: K\n    valueFrom: vault:x\n", `unsupported valueFrom "vault:x"`},
+		"unknown field":       {"parameters:\n  - name: K\n    vale: x\n", "field vale not found"},
+		"duplicate":           {"parameters:\n  - name: K\n    value: a\n  - name: K\n    value: b\n", "parameter K is listed twice"},
+		"new secure no value": {"parameters:\n  - name: NEW\n    secure: true\n", "secure parameter NEW has no value"},
+		"no value":            {"parameters:\n  - name: K\n", "parameter K has no value"},
+	}
+	for name, tc := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			ts, calls := setupTransferServer(t)
+			cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, tc.want,
+				"job", "param", "import", "TestProject_Build", "-f", writeFile(t, tc.content), "--yes")
+			assert.Empty(t, *calls)
+		})
+	}
+
+	t.Run("needs --yes without a terminal", func(t *testing.T) {
+		ts, calls := setupTransferServer(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "import needs confirmation",
+			"job", "param", "import", "TestProject_Build", "-f", writeFile(t, file))
+		assert.Empty(t, *calls)
+	})
+}
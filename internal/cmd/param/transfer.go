@@ -0,0 +1,362 @@
+package param
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// paramFile is the YAML file param export writes and param import reads.
+type paramFile struct {
+	Parameters []paramEntry `yaml:"parameters"`
+}
+
+// paramEntry is one parameter of a paramFile. Value is nil when the file gives none, as export does for secure
+// parameters; ValueFrom takes the value from elsewhere instead, for now only env:VAR.
+type paramEntry struct {
+	Name      string  `yaml:"name"`
+	Value     *string `yaml:"value,omitempty"`
+	ValueFrom string  `yaml:"valueFrom,omitempty"`
+	Secure    bool    `yaml:"secure,omitempty"`
+}
+
+func newParamExportCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
+	var outFile string
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("export [%s-id]", resource),
+		Short: fmt.Sprintf("Export %s parameters to a YAML file", resource),
+		Long: fmt.Sprintf(`Write the parameters set on the %s itself to a YAML file that
+'teamcity %s param import' reads; inherited parameters are left out.
+
+Secure parameters are marked secure: true without their values, which the
+server never returns. Before importing, give each one a value, preferably
+with valueFrom: env:VAR so the secret never lands on disk.`, resource, resource),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: idComplete,
+		Example: fmt.Sprintf(`  teamcity %s param export MyID -o params.yml
+  teamcity %s param export MyID > params.yml`, resource, resource),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _, err := cmdutil.ResolveOwnerID(resource, args, 0, resolveID)
+			if err != nil {
+				return err
+			}
+			return runParamExport(f, resource, id, outFile, paramAPI)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFile, "output", "o", "", "Write to this file instead of stdout")
+	_ = cmd.MarkFlagFilename("output", "yml", "yaml")
+	return cmd
+}
+
+func runParamExport(f *cmdutil.Factory, resource, id, outFile string, paramAPI ParamAPI) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	params, err := paramAPI.List(client, id)
+	if err != nil {
+		return err
+	}
+
+	file := paramFile{Parameters: []paramEntry{}}
+	secure := 0
+	for _, param := range params.Property {
+		if param.Inherited {
+			continue
+		}
+		entry := paramEntry{Name: param.Name}
+		if param.IsPassword() {
+			entry.Secure = true
+			secure++
+		} else {
+			entry.Value = &param.Value
+		}
+		file.Parameters = append(file.Parameters, entry)
+	}
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "# Parameters of %s %s. Apply with: teamcity %s param import <%s-id> -f <file>\n", resource, id, resource, resource)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("failed to encode parameters: %w", err)
+	}
+
+	p := f.Printer
+	if outFile == "" {
+		_, err := p.Out.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(outFile, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outFile, err)
+	}
+	p.Success("Exported %d parameters of %s %s to %s", len(file.Parameters), resource, id, outFile)
+	if secure > 0 {
+		p.Tip("%d secure parameters have no value in the file; give them one with valueFrom: env:VAR before importing", secure)
+	}
+	return nil
+}
+
+type paramImportOptions struct {
+	file            string
+	prune           bool
+	yes             bool
+	forceVCSManaged bool
+}
+
+func newParamImportCmd(f *cmdutil.Factory, resource string, paramAPI ParamAPI, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
+	opts := &paramImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("import [%s-id] -f <file>", resource),
+		Short: fmt.Sprintf("Apply a YAML file of parameters to a %s", resource),
+		Long: fmt.Sprintf(`Set the parameters of a YAML file, as written by 'teamcity %s param export',
+on the %s: parameters missing from the %s are added, and those with another
+value are updated. With --prune, parameters set on the %s but not in the
+file are deleted too.
+
+A parameter's value is its value field, or comes from valueFrom: env:VAR,
+the environment variable VAR, so secrets never land on disk. A secure
+parameter with neither is left as it is, which needs it to be set as
+secure on the %s already: secure parameters exported from elsewhere come
+without values, so give them one before importing them somewhere new.
+
+The changes are printed first, then you are asked to confirm. Without a
+terminal, as when the file is piped in, --yes is required.`, resource, resource, resource, resource, resource),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: idComplete,
+		Example: fmt.Sprintf(`  teamcity %s param import MyID -f params.yml
+  teamcity %s param import MyID -f params.yml --prune --yes
+  teamcity %s param export OldID | teamcity %s param import NewID -f - --yes`, resource, resource, resource, resource),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _, err := cmdutil.ResolveOwnerID(resource, args, 0, resolveID)
+			if err != nil {
+				return err
+			}
+			return runParamImport(f, resource, id, opts, paramAPI)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "YAML file to import (use - for stdin)")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "Delete parameters set on the "+resource+" that the file does not list")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmdutil.AddForceVCSManagedFlag(cmd, &opts.forceVCSManaged)
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagFilename("file", "yml", "yaml")
+	return cmd
+}
+
+// paramChange is one step of an import: add, update or delete a parameter.
+type paramChange struct {
+	action string
+	name   string
+	value  string
+	secure bool
+}
+
+func runParamImport(f *cmdutil.Factory, resource, id string, opts *paramImportOptions, paramAPI ParamAPI) error {
+	entries, err := readParamFile(f, opts.file)
+	if err != nil {
+		return err
+	}
+	if !opts.yes && !f.IsDryRun() && !f.IsInteractive() {
+		return api.Validation("import needs confirmation, and there is no terminal to ask in",
+			"Add --yes to apply without asking, or --dry-run to preview")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	if err := guardVCSManaged(f, client, resource, id, opts.forceVCSManaged); err != nil {
+		return err
+	}
+	params, err := paramAPI.List(client, id)
+	if err != nil {
+		return err
+	}
+
+	changes, kept, err := planParamImport(entries, params.Property, opts.prune)
+	if err != nil {
+		return err
+	}
+
+	p := f.Printer
+	for _, name := range kept {
+		p.Info("Leaving secure parameter %s as it is: the file gives it no value", name)
+	}
+	if len(changes) == 0 {
+		p.Info("The parameters of %s %s already match the file", resource, id)
+		return nil
+	}
+	printParamPlan(p, changes)
+
+	if !opts.yes && !f.IsDryRun() {
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("Apply %d changes to %s %s?", len(changes), resource, id), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			p.Info("Canceled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, c := range changes {
+		var err error
+		if c.action == "delete" {
+			err = paramAPI.Delete(client, id, c.name)
+		} else {
+			err = paramAPI.Set(client, id, c.name, c.value, c.secure)
+		}
+		if err != nil {
+			failed++
+			p.Warn("failed to %s parameter %s: %v", c.action, c.name, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d of %d changes", failed, len(changes))
+	}
+	p.Success("Applied %d changes to %s %s", len(changes), resource, id)
+	return nil
+}
+
+// readParamFile reads and checks a parameter file, resolving each valueFrom into Value.
+func readParamFile(f *cmdutil.Factory, path string) ([]paramEntry, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(f.IOStreams.In)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var file paramFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, api.Validation(fmt.Sprintf("invalid parameter file %s: %v", path, err),
+			"Write one with 'teamcity param export' to see the format")
+	}
+
+	seen := map[string]bool{}
+	for i := range file.Parameters {
+		e := &file.Parameters[i]
+		switch {
+		case e.Name == "":
+			return nil, api.Validation(fmt.Sprintf("parameter %d in %s has no name", i+1, path), "")
+		case seen[e.Name]:
+			return nil, api.Validation(fmt.Sprintf("parameter %s is listed twice in %s", e.Name, path), "")
+		case e.Value != nil && e.ValueFrom != "":
+			return nil, api.Validation(fmt.Sprintf("parameter %s has both value and valueFrom", e.Name), "Keep one of them")
+		}
+		seen[e.Name] = true
+		if e.ValueFrom == "" {
+			continue
+		}
+		name, ok := strings.CutPrefix(e.ValueFrom, "env:")
+		if !ok || name == "" {
+			return nil, api.Validation(fmt.Sprintf("parameter %s: unsupported valueFrom %q", e.Name, e.ValueFrom),
+				"Use valueFrom: env:VAR to read the value from the environment variable VAR")
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, api.Validation(fmt.Sprintf("parameter %s takes its value from %s, which is not set", e.Name, name),
+				fmt.Sprintf("Set %s before importing", name))
+		}
+		e.Value = &value
+	}
+	return file.Parameters, nil
+}
+
+// planParamImport compares the file's entries with the owner's parameters and returns the changes to make, and the
+// secure parameters left alone because the file has no value for them. A secure value can't be read back, so one
+// with a value in the file is always set again.
+func planParamImport(entries []paramEntry, current []api.Parameter, prune bool) ([]paramChange, []string, error) {
+	own := map[string]api.Parameter{}
+	for _, param := range current {
+		if !param.Inherited {
+			own[param.Name] = param
+		}
+	}
+
+	var changes []paramChange
+	var kept []string
+	for _, e := range entries {
+		have, exists := own[e.Name]
+		if e.Value == nil {
+			if !e.Secure {
+				return nil, nil, api.Validation(fmt.Sprintf("parameter %s has no value", e.Name), "Give it a value, or valueFrom: env:VAR")
+			}
+			if !exists || !have.IsPassword() {
+				return nil, nil, api.Validation(fmt.Sprintf("secure parameter %s has no value, and is not set as secure yet", e.Name),
+					"Give it one with valueFrom: env:VAR")
+			}
+			kept = append(kept, e.Name)
+			continue
+		}
+		c := paramChange{action: "update", name: e.Name, value: *e.Value, secure: e.Secure}
+		switch {
+		case !exists:
+			c.action = "add"
+		case !e.Secure && !have.IsPassword() && have.Value == *e.Value:
+			continue
+		}
+		changes = append(changes, c)
+	}
+
+	if prune {
+		listed := map[string]bool{}
+		for _, e := range entries {
+			listed[e.Name] = true
+		}
+		for _, param := range current {
+			if !param.Inherited && !listed[param.Name] {
+				changes = append(changes, paramChange{action: "delete", name: param.Name, secure: param.IsPassword()})
+			}
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b paramChange) int { return cmp.Compare(a.name, b.name) })
+	return changes, kept, nil
+}
+
+// printParamPlan lists the changes an import makes, values of secure parameters masked.
+func printParamPlan(p *output.Printer, changes []paramChange) {
+	headers := []string{"NAME", "ACTION", "VALUE"}
+	rows := make([][]string, len(changes))
+	for i, c := range changes {
+		value := c.value
+		if c.secure {
+			value = "********"
+		}
+		action := output.Green(c.action)
+		switch c.action {
+		case "update":
+			action = output.Yellow(c.action)
+		case "delete":
+			action, value = output.Red(c.action), ""
+		}
+		rows[i] = []string{c.name, action, value}
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0, 2)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
@@ -40,7 +40,7 @@ func newPipelineCreateCmd(f *cmdutil.Factory) *cobra.Command {
 	_ = cmd.MarkFlagRequired("project")
 	_ = cmd.MarkFlagFilename("file", "yml", "yaml")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -20,7 +20,7 @@ func newPipelineDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:               "delete <pipeline-id>",
 		Short:             "Delete a pipeline",
 		Args:              cobra.ExactArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Example: `  teamcity pipeline delete CLI_MyPipeline
   teamcity pipeline delete CLI_MyPipeline --yes`,
 		RunE: func(cmd *cobra.Command, args []string) error {
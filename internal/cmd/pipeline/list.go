@@ -35,7 +35,7 @@ func newPipelineListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Filter by project ID")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 30)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
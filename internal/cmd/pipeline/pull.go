@@ -22,7 +22,7 @@ func newPipelinePullCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:               "pull <pipeline-id>",
 		Short:             "Download pipeline YAML",
 		Args:              cobra.ExactArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Example: `  teamcity pipeline pull CLI_CiCd
   teamcity pipeline pull CLI_CiCd -o .teamcity.yml
   teamcity pipeline pull CLI_CiCd > pipeline.yml`,
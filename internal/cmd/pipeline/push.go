@@ -18,7 +18,7 @@ func newPipelinePushCmd(f *cmdutil.Factory) *cobra.Command {
 		Args:  cobra.RangeArgs(1, 2),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
-				return completion.LinkedJobs()(cmd, args, toComplete)
+				return completion.Jobs()(cmd, args, toComplete)
 			}
 			return []string{"yml", "yaml"}, cobra.ShellCompDirectiveFilterFileExt
 		},
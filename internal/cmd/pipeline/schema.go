@@ -1,10 +1,8 @@
 package pipeline
 
 import (
-	"errors"
 	"fmt"
 
-	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/spf13/cobra"
 )
@@ -29,12 +27,7 @@ a warning is written to stderr; pass --refresh to require a live server fetch.`,
 			if err != nil {
 				return err
 			}
-			c, ok := client.(*api.Client)
-			if !ok {
-				return errors.New("schema requires a real API client")
-			}
-
-			data, _, fallback, err := cmdutil.FetchOrCachePipelineSchema(c, refresh)
+			data, _, fallback, err := cmdutil.FetchOrCachePipelineSchema(client, refresh)
 			if err != nil {
 				return err
 			}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/output"
@@ -125,12 +124,7 @@ func loadSchema(f *cmdutil.Factory, opts *validateOptions) ([]byte, bool, error)
 		return pipelineschema.Bytes, false, nil
 	}
 
-	c, ok := client.(*api.Client)
-	if !ok {
-		return nil, false, errors.New("schema caching requires a real API client")
-	}
-
-	data, fromCache, _, err := cmdutil.FetchOrCachePipelineSchema(c, opts.refreshSchema)
+	data, fromCache, _, err := cmdutil.FetchOrCachePipelineSchema(client, opts.refreshSchema)
 	return data, fromCache, err
 }
 
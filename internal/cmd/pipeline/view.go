@@ -17,7 +17,7 @@ func newPipelineViewCmd(f *cmdutil.Factory) *cobra.Command {
 		Short:             "View pipeline details",
 		Aliases:           []string{"show"},
 		Args:              cobra.ExactArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Example: `  teamcity pipeline view CLI_CiCd
   teamcity pipeline view CLI_CiCd --web
   teamcity pipeline view CLI_CiCd --json`,
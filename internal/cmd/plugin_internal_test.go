@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPluginPath puts plugins that echo their name and arguments on PATH, each exiting with its first argument.
+func setupPluginPath(t *testing.T, names ...string) *cmdutil.Factory {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fixture plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		script := "#!/bin/sh\necho \"" + name + ":$*\"\nexit \"${1:-0}\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tc-"+name), []byte(script), 0o755))
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv(update.EnvNoUpdateCheck, "1")
+	t.Setenv("DO_NOT_TRACK", "1")
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	config.ResetForTest()
+
+	var out bytes.Buffer
+	f := cmdutil.NewFactory()
+	f.IOStreams = &cmdutil.IOStreams{In: strings.NewReader(""), Out: &out, ErrOut: &out}
+	f.Printer = &output.Printer{Out: &out, ErrOut: &out}
+	return f
+}
+
+func pluginOutput(f *cmdutil.Factory) string {
+	return f.IOStreams.Out.(*bytes.Buffer).String()
+}
+
+func TestPluginDispatch(t *testing.T) {
+	f := setupPluginPath(t, "deploy")
+
+	executed, err := executeRoot(context.Background(), f, []string{"deploy", "0", "--env", "prod"})
+	require.NoError(t, err)
+	assert.Nil(t, executed)
+	assert.Equal(t, "deploy:0 --env prod\n", pluginOutput(f))
+
+	_, err = executeRoot(context.Background(), f, []string{"deploy", "7"})
+	exitErr, ok := err.(*cmdutil.ExitError)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, 7, exitErr.Code)
+}
+
+func TestPluginDoesNotShadowCommands(t *testing.T) {
+	f := setupPluginPath(t, "version", "help", "rl", "completion")
+	require.NoError(t, config.AddAlias("rl", "version --offline"))
+
+	for _, args := range [][]string{{"version", "--offline"}, {"rl"}, {"help"}, {"completion", "bash"}} {
+		_, err := executeRoot(context.Background(), f, args)
+		require.NoError(t, err, "%v", args)
+	}
+	assert.NotContains(t, pluginOutput(f), "version:")
+	assert.NotContains(t, pluginOutput(f), "rl:")
+	assert.NotContains(t, pluginOutput(f), "help:")
+	assert.NotContains(t, pluginOutput(f), "completion:")
+
+	_, err := executeRoot(context.Background(), f, []string{"nonexistent"})
+	assert.ErrorContains(t, err, `unknown command "nonexistent"`)
+}
@@ -0,0 +1,106 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "List external command plugins",
+		Long: `Extend the CLI with your own commands.
+
+Any executable on PATH named tc-<name> runs as 'teamcity <name>', with the
+remaining arguments passed through and its exit code returned as the CLI's.
+Built-in commands and aliases always win over a plugin of the same name.
+
+A plugin gets the resolved server in TC_PLUGIN_SERVER_URL, where the token
+comes from in TC_PLUGIN_TOKEN_SOURCE, the config file in TC_PLUGIN_CONFIG, and
+the CLI executable in TC_PLUGIN_CLI. The token itself is passed in
+TC_PLUGIN_TOKEN only when TC_PLUGIN_PASS_TOKEN=1 is set.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newPluginsListCmd(f))
+
+	return cmd
+}
+
+// pluginEntry is a plugin as listed; ShadowedBy names the built-in command or alias that runs instead of it.
+type pluginEntry struct {
+	plugin.Plugin
+	ShadowedBy string `json:"shadowedBy,omitempty"`
+}
+
+func newPluginsListCmd(f *cmdutil.Factory) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List plugins found on PATH",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		Example: `  teamcity plugins list
+  teamcity plugins list --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var entries []pluginEntry
+			for _, p := range plugin.Discover() {
+				entries = append(entries, pluginEntry{Plugin: p, ShadowedBy: shadowingCommand(cmd.Root(), p.Name)})
+			}
+
+			if jsonOutput {
+				if entries == nil {
+					entries = []pluginEntry{}
+				}
+				return f.Printer.PrintJSON(entries)
+			}
+			if len(entries) == 0 {
+				_, _ = fmt.Fprintf(f.Printer.Out, "No plugins found. Put an executable named %s<name> on PATH to add one.\n", plugin.Prefix)
+				return nil
+			}
+
+			headers := []string{"NAME", "PATH", "NOTE"}
+			var rows [][]string
+			for _, e := range entries {
+				var notes []string
+				if e.ShadowedBy != "" {
+					notes = append(notes, output.Yellow("shadowed by "+e.ShadowedBy))
+				}
+				for _, path := range e.Shadowed {
+					notes = append(notes, output.Faint("hides "+path))
+				}
+				rows = append(rows, []string{e.Name, e.Path, strings.Join(notes, "; ")})
+			}
+			f.Printer.PrintTable(headers, rows)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// shadowingCommand describes the built-in command or alias named name, or returns "" when there is none.
+func shadowingCommand(root *cobra.Command, name string) string {
+	for _, c := range root.Commands() {
+		if c.Name() != name && !c.HasAlias(name) {
+			continue
+		}
+		if c.Annotations["is_alias"] == "true" {
+			return "alias"
+		}
+		return "built-in command"
+	}
+	if name == "help" || name == "completion" {
+		return "built-in command"
+	}
+	return ""
+}
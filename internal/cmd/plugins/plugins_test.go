@@ -0,0 +1,60 @@
+package plugins_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/cmd"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runPluginsList(t *testing.T, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	f := cmdutil.NewFactory()
+	f.Printer = &output.Printer{Out: &out, ErrOut: &out}
+	root := cmd.NewCommand(f)
+	root.SetArgs(append([]string{"plugins", "list"}, args...))
+	require.NoError(t, root.Execute())
+	return out.String()
+}
+
+func TestPluginsList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fixture plugins rely on execute bits")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"tc-deploy", "tc-version"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0o755))
+	}
+	t.Setenv("PATH", dir)
+
+	var entries []struct {
+		Name       string `json:"name"`
+		Path       string `json:"path"`
+		ShadowedBy string `json:"shadowedBy"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(runPluginsList(t, "--json")), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "deploy", entries[0].Name)
+	assert.Equal(t, filepath.Join(dir, "tc-deploy"), entries[0].Path)
+	assert.Empty(t, entries[0].ShadowedBy)
+	assert.Equal(t, "built-in command", entries[1].ShadowedBy)
+
+	out := runPluginsList(t)
+	assert.Contains(t, out, "deploy")
+	assert.Contains(t, out, "shadowed by built-in command")
+}
+
+func TestPluginsListEmpty(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	assert.Contains(t, runPluginsList(t), "No plugins found")
+	assert.JSONEq(t, "[]", runPluginsList(t, "--json"))
+}
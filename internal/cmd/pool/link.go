@@ -47,7 +47,7 @@ func newPoolProjectCmd(f *cmdutil.Factory, a poolProjectAction) *cobra.Command {
 		Example: fmt.Sprintf("  teamcity pool %s 1 MyProject", a.use),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 1 {
-				return completion.LinkedProjects()(cmd, args, toComplete)
+				return completion.Projects()(cmd, args, toComplete)
 			}
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
@@ -9,7 +9,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pool",
 		Short: "Manage agent pools",
-		Long: `List agent pools and manage project assignments.
+		Long: `List agent pools, show their capacity, and manage project assignments.
 
 Agent pools group build agents and bind them to specific projects,
 so a project's builds only run on approved agents. Use these commands
@@ -24,6 +24,7 @@ See: https://www.jetbrains.com/help/teamcity/configuring-agent-pools.html`,
 	cmd.AddCommand(newPoolViewCmd(f))
 	cmd.AddCommand(newPoolLinkCmd(f))
 	cmd.AddCommand(newPoolUnlinkCmd(f))
+	cmd.AddCommand(newPoolQuotaCmd(f))
 
 	return cmd
 }
@@ -1,11 +1,14 @@
 package pool_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
 )
 
@@ -49,3 +52,39 @@ func TestPoolLinkUnlink(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "pool", "link", "1", "TestProject")
 	cmdtest.RunCmdWithFactory(T, f, "pool", "unlink", "1", "TestProject")
 }
+
+func TestPoolQuota(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/server/licensingData", func(w http.ResponseWriter, _ *http.Request) {
+		cmdtest.JSON(w, api.LicensingData{MaxAgents: 3, AgentsLeft: 1})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "pool", "quota")
+	assert.Regexp(T, `0\s+Default\s+2\s+1\s+2\s+unlimited`, out)
+	assert.Regexp(T, `1\s+Linux Agents\s+0\s+0\s+0\s+10`, out)
+	assert.Contains(T, out, "2 of 3 agent licenses used, 1 left")
+	assert.Contains(T, out, "close to the license limit: 1 of 3 left")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "pool", "quota", "--json")
+	var report struct {
+		Pools    []map[string]any `json:"pools"`
+		Licenses map[string]any   `json:"licenses"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(out), &report))
+	require.Len(T, report.Pools, 2)
+	assert.EqualValues(T, 2, report.Pools[0]["agents"])
+	assert.Equal(T, map[string]any{"unlimited": false, "max": 3.0, "used": 2.0, "left": 1.0, "exceeded": false, "nearLimit": true}, report.Licenses)
+}
+
+func TestPoolQuotaWithoutLicensingAccess(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/server/licensingData", func(w http.ResponseWriter, _ *http.Request) {
+		cmdtest.Error(w, http.StatusForbidden, "You do not have enough permissions")
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "pool", "quota")
+	assert.Contains(T, out, "Licenses: not available")
+	assert.NotContains(T, out, "license limit")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--interval must be at least 1 second", "pool", "quota", "--watch", "--interval", "0")
+}
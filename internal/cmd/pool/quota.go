@@ -0,0 +1,244 @@
+package pool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type poolQuotaOptions struct {
+	json     bool
+	watch    bool
+	interval int
+}
+
+func newPoolQuotaCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &poolQuotaOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Show pool capacity and agent license usage",
+		Long: `Show, per pool, how many agents are assigned, connected, and authorized
+against the pool's agent limit, followed by the server's agent licenses
+and how many are left.
+
+A warning is printed when authorized agents are within 10% (at least one)
+of the license limit. --watch refreshes the report every --interval
+seconds; with --json each refresh is printed as one line.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity pool quota
+  teamcity pool quota --json
+  teamcity pool quota --watch --interval 30`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPoolQuota(f, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Refresh until interrupted")
+	cmd.Flags().IntVarP(&opts.interval, "interval", "i", 10, "Refresh interval in seconds with --watch")
+
+	return cmd
+}
+
+// poolQuota is the agent usage of one pool. MaxAgents is 0 for pools without a limit.
+type poolQuota struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Agents     int    `json:"agents"`
+	Connected  int    `json:"connected"`
+	Authorized int    `json:"authorized"`
+	MaxAgents  int    `json:"maxAgents"`
+}
+
+// licenseQuota is the server's agent license usage. Max and Left are 0 for unlimited licenses.
+type licenseQuota struct {
+	Unlimited bool `json:"unlimited"`
+	Max       int  `json:"max,omitempty"`
+	Used      int  `json:"used"`
+	Left      int  `json:"left"`
+	Exceeded  bool `json:"exceeded"`
+	NearLimit bool `json:"nearLimit"`
+}
+
+type quotaReport struct {
+	Pools    []poolQuota   `json:"pools"`
+	Licenses *licenseQuota `json:"licenses,omitempty"`
+}
+
+func runPoolQuota(f *cmdutil.Factory, opts *poolQuotaOptions) error {
+	if opts.interval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second, got %d", opts.interval)
+	}
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx := f.Context()
+	for {
+		report, err := fetchQuota(client)
+		if err != nil {
+			return err
+		}
+		if err := printQuota(f.Printer, report, opts); err != nil {
+			return err
+		}
+		if !opts.watch {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Duration(opts.interval) * time.Second):
+		}
+		if !opts.json {
+			_, _ = fmt.Fprintln(f.Printer.Out)
+		}
+	}
+}
+
+// fetchQuota fetches pools, agents, and licensing data concurrently and counts agents per pool.
+// Licensing data needs permissions not every user has; without it the report leaves licenses out.
+func fetchQuota(client api.ClientInterface) (*quotaReport, error) {
+	var (
+		wg        sync.WaitGroup
+		pools     *api.PoolList
+		agents    *api.AgentList
+		licensing *api.LicensingData
+		poolsErr  error
+		agentsErr error
+	)
+	wg.Go(func() { pools, poolsErr = client.GetAgentPools(nil) })
+	wg.Go(func() {
+		agents, _, agentsErr = client.GetAgents(api.AgentsOptions{Fields: []string{"id", "connected", "authorized", "pool.id"}})
+	})
+	wg.Go(func() { licensing, _ = client.GetLicensingData() })
+	wg.Wait()
+
+	if poolsErr != nil {
+		return nil, fmt.Errorf("failed to list agent pools: %w", poolsErr)
+	}
+	if agentsErr != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", agentsErr)
+	}
+
+	report := &quotaReport{Pools: make([]poolQuota, len(pools.Pools))}
+	index := make(map[int]int, len(pools.Pools))
+	for i, p := range pools.Pools {
+		report.Pools[i] = poolQuota{ID: p.ID, Name: p.Name, MaxAgents: max(p.MaxAgents, 0)}
+		index[p.ID] = i
+	}
+	authorized := 0
+	for _, a := range agents.Agents {
+		if a.Authorized {
+			authorized++
+		}
+		if a.Pool == nil {
+			continue
+		}
+		i, ok := index[a.Pool.ID]
+		if !ok {
+			continue
+		}
+		q := &report.Pools[i]
+		q.Agents++
+		if a.Connected {
+			q.Connected++
+		}
+		if a.Authorized {
+			q.Authorized++
+		}
+	}
+
+	if licensing != nil {
+		report.Licenses = licenseUsage(licensing, authorized)
+	}
+	return report, nil
+}
+
+// licenseUsage derives license usage from licensing data, counting authorized agents when the server
+// does not report how many licenses are left.
+func licenseUsage(d *api.LicensingData, authorized int) *licenseQuota {
+	if d.UnlimitedAgents {
+		return &licenseQuota{Unlimited: true, Used: authorized}
+	}
+	left := d.AgentsLeft
+	if left < 0 {
+		left = max(d.MaxAgents-authorized, 0)
+	}
+	used := d.MaxAgents - left
+	if d.LicenseUseExceeded {
+		used = max(used, authorized)
+	}
+	return &licenseQuota{
+		Max:       d.MaxAgents,
+		Used:      used,
+		Left:      left,
+		Exceeded:  d.LicenseUseExceeded,
+		NearLimit: d.LicenseUseExceeded || left <= max(1, d.MaxAgents/10),
+	}
+}
+
+func printQuota(p *output.Printer, report *quotaReport, opts *poolQuotaOptions) error {
+	if opts.json {
+		if !opts.watch {
+			return p.PrintJSON(report)
+		}
+		line, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(p.Out, string(line))
+		return nil
+	}
+
+	if opts.watch {
+		_, _ = fmt.Fprintln(p.Out, output.Faint(time.Now().Format("15:04:05")))
+	}
+	headers := []string{"ID", "POOL", "AGENTS", "CONNECTED", "AUTHORIZED", "MAX AGENTS"}
+	rows := make([][]string, len(report.Pools))
+	for i, q := range report.Pools {
+		limit := output.Faint("unlimited")
+		if q.MaxAgents > 0 {
+			limit = strconv.Itoa(q.MaxAgents)
+			if q.Agents >= q.MaxAgents {
+				limit = output.Yellow(limit + " (full)")
+			}
+		}
+		rows[i] = []string{
+			strconv.Itoa(q.ID),
+			q.Name,
+			strconv.Itoa(q.Agents),
+			strconv.Itoa(q.Connected),
+			strconv.Itoa(q.Authorized),
+			limit,
+		}
+	}
+	p.PrintTable(headers, rows)
+
+	l := report.Licenses
+	switch {
+	case l == nil:
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("Licenses:"), output.Faint("not available (requires permission to view server licenses)"))
+	case l.Unlimited:
+		_, _ = fmt.Fprintf(p.Out, "\n%s %d authorized agents, unlimited\n", output.Faint("Licenses:"), l.Used)
+	default:
+		_, _ = fmt.Fprintf(p.Out, "\n%s %d of %d agent licenses used, %d left\n", output.Faint("Licenses:"), l.Used, l.Max, l.Left)
+		switch {
+		case l.Exceeded:
+			p.Warn("Agent license limit exceeded: %d agents are authorized for %d licenses", l.Used, l.Max)
+		case l.NearLimit:
+			p.Warn("Authorized agents are close to the license limit: %d of %d left", l.Left, l.Max)
+		}
+	}
+	return nil
+}
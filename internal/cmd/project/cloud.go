@@ -71,7 +71,7 @@ func newCloudProfileListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Filter by project ID")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -205,7 +205,7 @@ func newCloudImageListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.profile, "profile", "", "Filter by cloud profile")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -382,7 +382,7 @@ func newCloudInstanceListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.image, "image", "", "Filter by cloud image")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -406,10 +406,7 @@ func (opts *cloudInstanceListOptions) fetch(client api.ClientInterface, fields [
 		if inst.Image != nil {
 			imageName = inst.Image.Name
 		}
-		agentName := ""
-		if inst.Agent != nil {
-			agentName = inst.Agent.Name
-		}
+		agentName := cmdutil.AgentName(inst.Agent)
 		started := ""
 		if inst.StartDate != "" {
 			if t, err := api.ParseTeamCityTime(inst.StartDate); err == nil {
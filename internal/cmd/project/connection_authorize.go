@@ -53,7 +53,7 @@ Connection types that don't have a per-user OAuth flow (Docker, AWS) error out.`
 
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Project ID")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -88,7 +88,7 @@ automatically. Use --no-manifest to enter existing credentials manually.`,
 	cmd.Flags().BoolVar(&opts.noManifest, "no-manifest", false, "Skip the manifest flow; collect credentials manually")
 	cmd.Flags().BoolVar(&opts.noAuthorize, "no-authorize", false, "Skip the post-create authorize prompt")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 	_ = cmd.MarkFlagFilename("private-key-file", "pem")
 
 	return cmd
@@ -370,7 +370,7 @@ over a personal account.`,
 	cmd.Flags().StringVar(&opts.password, "password", "", "Registry password (prefer --stdin)")
 	cmd.Flags().BoolVar(&opts.stdin, "stdin", false, "Read password from stdin")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -34,7 +34,7 @@ The id is the one shown in the first column of 'connection list'.`,
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Project ID")
 	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Skip confirmation")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -0,0 +1,235 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+type projectCopyOptions struct {
+	id             string
+	parent         string
+	params         []string
+	copyAssociated bool
+	json           bool
+}
+
+func newProjectCopyCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &projectCopyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "copy <source-id> <new-name>",
+		Short: "Copy a project",
+		Long: `Copy a project with its subprojects, jobs, and parameters under a new name.
+
+If --id is omitted, TeamCity derives the new project ID from the name.
+If --parent is omitted, the copy is created next to the source project.
+Each --param is then set on the new project, overriding the copied value.
+Build history is never copied.
+
+Use --dry-run to see the projects and jobs that would be copied.`,
+		Example: `  teamcity project copy Falcon "Falcon Staging"
+  teamcity project copy Falcon "Falcon Staging" --id FalconStaging --parent Sandbox
+  teamcity project copy Falcon "Falcon Staging" --param env.DEPLOY_TARGET=staging
+  teamcity project copy Falcon "Falcon Staging" --dry-run`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.Projects(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectCopy(f, args[0], args[1], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.id, "id", "", "Explicit ID of the new project (default: auto-generated from name)")
+	cmd.Flags().StringVarP(&opts.parent, "parent", "p", "", "Parent project ID (default: the source's parent)")
+	cmd.Flags().StringArrayVar(&opts.params, "param", nil, "Set a parameter on the new project as key=value (repeatable)")
+	cmd.Flags().BoolVar(&opts.copyAssociated, "copy-associated-settings", true, "Also copy VCS roots, templates, and other settings the source uses from its parent projects")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("parent", completion.Projects())
+
+	return cmd
+}
+
+// projectParam is one --param override.
+type projectParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// projectCopyResult is the --json output of project copy.
+type projectCopyResult struct {
+	Source     string          `json:"source"`
+	Project    *api.Project    `json:"project"`
+	Projects   int             `json:"projects"`
+	Jobs       int             `json:"jobs"`
+	Parameters []projectParam  `json:"parameters"`
+	Tree       ProjectTreeNode `json:"tree"`
+	DryRun     bool            `json:"dryRun,omitempty"`
+}
+
+func runProjectCopy(f *cmdutil.Factory, sourceID, name string, opts *projectCopyOptions) error {
+	params, err := parseProjectParams(opts.params)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	source, err := client.GetProject(sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get source project: %w", err)
+	}
+	parent := opts.parent
+	if parent == "" {
+		parent = source.ParentProjectID
+	}
+	if err := checkNewProject(client, parent, opts.id, name); err != nil {
+		return err
+	}
+
+	project, err := client.CopyProject(source.ID, api.CopyProjectRequest{
+		ID:                        opts.id,
+		Name:                      name,
+		ParentProject:             &api.ProjectRef{ID: parent},
+		CopyAllAssociatedSettings: opts.copyAssociated,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy project: %w", err)
+	}
+
+	for _, p := range params {
+		if err := client.SetProjectParameter(project.ID, p.Name, p.Value, false); err != nil {
+			return fmt.Errorf("copied project %s, but failed to set parameter %s: %w", project.ID, p.Name, err)
+		}
+	}
+
+	// Under --dry-run nothing was created, so summarize the source instead.
+	dryRun := f.IsDryRun()
+	treeID, treeName := project.ID, project.Name
+	if dryRun {
+		treeID, treeName = source.ID, name
+	}
+	tree, projects, jobs, err := projectSubtree(client, treeID, treeName)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		return f.Printer.PrintJSON(projectCopyResult{
+			Source:     source.ID,
+			Project:    project,
+			Projects:   projects,
+			Jobs:       jobs,
+			Parameters: params,
+			Tree:       tree,
+			DryRun:     dryRun,
+		})
+	}
+
+	summary := english.Plural(projects, "project", "") + " and " + english.Plural(jobs, "job", "")
+	if dryRun {
+		// The dry-run client quiets the printer, but this preview is the point of --dry-run.
+		_, _ = fmt.Fprintf(f.Printer.Out, "Would copy %q (%s) to %q: %s\n", source.Name, source.ID, name, summary)
+	} else {
+		f.Printer.Success("Copied %q (%s) to %q (id: %s): %s", source.Name, source.ID, project.Name, project.ID, summary)
+	}
+	f.Printer.PrintTree(tree.toDisplayNode())
+	for _, p := range params {
+		_, _ = fmt.Fprintf(f.Printer.Out, "  %s %s=%s\n", output.Faint("param"), p.Name, p.Value)
+	}
+	if project.WebURL != "" {
+		_, _ = fmt.Fprintf(f.Printer.Out, "  %s\n", project.WebURL)
+	}
+	return nil
+}
+
+func parseProjectParams(params []string) ([]projectParam, error) {
+	parsed := []projectParam{}
+	for _, p := range params {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return nil, api.Validation(
+				fmt.Sprintf("invalid --param %q", p),
+				"Use key=value, for example --param env.DEPLOY_TARGET=staging",
+			)
+		}
+		parsed = append(parsed, projectParam{Name: key, Value: value})
+	}
+	return parsed, nil
+}
+
+// checkNewProject rejects a new project whose ID is taken or whose name clashes with a sibling under parent.
+func checkNewProject(client api.ClientInterface, parent, id, name string) error {
+	if id != "" {
+		_, err := client.GetProject(id)
+		if err == nil {
+			return api.Validation(
+				fmt.Sprintf("project ID %q already exists", id),
+				"Choose another --id, or omit it to let TeamCity derive one from the name",
+			)
+		}
+		if _, ok := errors.AsType[*api.NotFoundError](err); !ok {
+			return err
+		}
+	}
+
+	siblings, _, err := client.GetProjects(api.ProjectsOptions{Parent: parent, Limit: 10000})
+	if err != nil {
+		return fmt.Errorf("failed to check parent project %s: %w", parent, err)
+	}
+	for _, p := range siblings.Projects {
+		if p.ParentProjectID == parent && strings.EqualFold(p.Name, name) {
+			return api.Validation(
+				fmt.Sprintf("project %s already has a subproject named %q (id: %s)", parent, p.Name, p.ID),
+				"Choose another name, or a different --parent",
+			)
+		}
+	}
+	return nil
+}
+
+// projectSubtree builds the tree under rootID and counts its projects (including the root) and jobs.
+func projectSubtree(client api.ClientInterface, rootID, rootName string) (ProjectTreeNode, int, int, error) {
+	all, _, err := client.GetProjects(api.ProjectsOptions{Limit: 10000})
+	if err != nil {
+		return ProjectTreeNode{}, 0, 0, err
+	}
+	children := map[string][]api.Project{}
+	for _, p := range all.Projects {
+		if p.ParentProjectID != "" {
+			children[p.ParentProjectID] = append(children[p.ParentProjectID], p)
+		}
+	}
+
+	buildTypes, _, err := client.GetBuildTypes(api.BuildTypesOptions{Project: rootID, Limit: 10000})
+	if err != nil {
+		return ProjectTreeNode{}, 0, 0, err
+	}
+	jobs := map[string][]api.BuildType{}
+	for _, bt := range buildTypes.BuildTypes {
+		jobs[bt.ProjectID] = append(jobs[bt.ProjectID], bt)
+	}
+
+	tree := buildProjectTreeData(children, jobs, nil, nil, nil, rootID, rootName, 0)
+	projects, jobCount := 0, 0
+	var count func(n ProjectTreeNode)
+	count = func(n ProjectTreeNode) {
+		projects++
+		jobCount += len(n.Jobs)
+		for _, c := range n.Children {
+			count(c)
+		}
+	}
+	count(tree)
+	return tree, projects, jobCount, nil
+}
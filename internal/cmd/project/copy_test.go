@@ -0,0 +1,144 @@
+package project_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installCopyHandlers serves a Falcon project with one subproject and two jobs, and records copy and parameter requests.
+func installCopyHandlers(ts *cmdtest.TestServer) (copies *[]map[string]any, params *[]string) {
+	var mu sync.Mutex
+	copies, params = &[]map[string]any{}, &[]string{}
+
+	ts.Handle("GET /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProjectList{Count: 4, Projects: []api.Project{
+			{ID: "_Root", Name: "Root project"},
+			{ID: "Falcon", Name: "Falcon", ParentProjectID: "_Root"},
+			{ID: "Falcon_Deploy", Name: "Deploy", ParentProjectID: "Falcon"},
+			{ID: "Sandbox", Name: "Sandbox", ParentProjectID: "_Root"},
+		}})
+	})
+	ts.Handle("GET /app/rest/projects/id:Falcon", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Project{ID: "Falcon", Name: "Falcon", ParentProjectID: "_Root"})
+	})
+	ts.Handle("GET /app/rest/projects/id:FalconStaging", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusNotFound, "No project found by locator 'id:FalconStaging'")
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 2, BuildTypes: []api.BuildType{
+			{ID: "Falcon_Build", Name: "Build", ProjectID: "Falcon"},
+			{ID: "Falcon_Deploy_Prod", Name: "Prod", ProjectID: "Falcon_Deploy"},
+		}})
+	})
+	ts.Handle("POST /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		*copies = append(*copies, body)
+		mu.Unlock()
+		cmdtest.JSON(w, api.Project{ID: "FalconStaging", Name: "Falcon Staging", ParentProjectID: "_Root"})
+	})
+	ts.Handle("PUT /app/rest/projects/id:FalconStaging/parameters/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		*params = append(*params, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+	return copies, params
+}
+
+func TestProjectCopy(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	copies, params := installCopyHandlers(ts)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "copy", "Falcon", "Falcon Staging",
+		"--id", "FalconStaging", "--param", "env.TARGET=staging")
+
+	require.Len(T, *copies, 1)
+	body := (*copies)[0]
+	assert.Equal(T, map[string]any{"id": "Falcon"}, body["sourceProject"])
+	assert.Equal(T, map[string]any{"id": "_Root"}, body["parentProject"], "defaults to the source's parent")
+	assert.Equal(T, "FalconStaging", body["id"])
+	assert.Equal(T, true, body["copyAllAssociatedSettings"])
+
+	require.Len(T, *params, 1)
+	assert.Contains(T, (*params)[0], `"value":"staging"`)
+
+	assert.Contains(T, out, `Copied "Falcon" (Falcon) to "Falcon Staging" (id: FalconStaging)`)
+	assert.Contains(T, out, "param env.TARGET=staging")
+}
+
+func TestProjectCopyOptions(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	copies, _ := installCopyHandlers(ts)
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "project", "copy", "Falcon", "Falcon Staging",
+		"--parent", "Sandbox", "--copy-associated-settings=false")
+
+	require.Len(T, *copies, 1)
+	assert.Equal(T, map[string]any{"id": "Sandbox"}, (*copies)[0]["parentProject"])
+	assert.Equal(T, false, (*copies)[0]["copyAllAssociatedSettings"])
+	assert.NotContains(T, (*copies)[0], "id")
+}
+
+func TestProjectCopyCollisions(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	copies, _ := installCopyHandlers(ts)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `project ID "Sandbox" already exists`,
+		"project", "copy", "Falcon", "Falcon Staging", "--id", "Sandbox")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `project _Root already has a subproject named "Sandbox" (id: Sandbox)`,
+		"project", "copy", "Falcon", "sandbox")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "invalid --param",
+		"project", "copy", "Falcon", "Falcon Staging", "--param", "TARGET")
+
+	assert.Empty(T, *copies, "nothing is copied when the target collides")
+}
+
+func TestProjectCopyDryRun(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	copies, params := installCopyHandlers(ts)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "copy", "Falcon", "Falcon Staging",
+		"--id", "FalconStaging", "--param", "env.TARGET=staging", "--dry-run")
+
+	assert.Empty(T, *copies)
+	assert.Empty(T, *params)
+	assert.Contains(T, out, "Would send POST /app/rest/projects")
+	assert.Contains(T, out, `Would copy "Falcon" (Falcon) to "Falcon Staging": 2 projects and 2 jobs`)
+	assert.Contains(T, out, "Deploy")
+	assert.Contains(T, out, "Prod")
+}
+
+func TestProjectCopyJSON(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	installCopyHandlers(ts)
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 1, BuildTypes: []api.BuildType{
+			{ID: "FalconStaging_Build", Name: "Build", ProjectID: "FalconStaging"},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "copy", "Falcon", "Falcon Staging", "--id", "FalconStaging", "--json")
+
+	var result struct {
+		Source   string
+		Project  api.Project
+		Projects int
+		Jobs     int
+	}
+	require.NoError(T, json.Unmarshal([]byte(out), &result))
+	assert.Equal(T, "Falcon", result.Source)
+	assert.Equal(T, "FalconStaging", result.Project.ID)
+	assert.Equal(T, 1, result.Projects)
+	assert.Equal(T, 1, result.Jobs)
+}
@@ -1,6 +1,8 @@
 package project
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -10,10 +12,11 @@ import (
 )
 
 type projectCreateOptions struct {
-	id     string
-	parent string
-	json   bool
-	web    bool
+	id          string
+	parent      string
+	description string
+	json        bool
+	web         bool
 }
 
 func newProjectCreateCmd(f *cmdutil.Factory) *cobra.Command {
@@ -25,11 +28,14 @@ func newProjectCreateCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `Create a new TeamCity project.
 
 If --id is omitted, TeamCity derives the project ID from the name.
-If --parent is omitted, the project is created under the Root project.`,
+If --parent is omitted, the project is created under the Root project.
+The new project's ID and web URL are printed, or the project itself with
+--json. A taken ID, or a name already used by a sibling project, is an error.`,
 		Example: `  teamcity project create MyProject
   teamcity project create MyProject --id MyProject
   teamcity project create MyProject --parent ParentProject
   teamcity project create MyProject --id MyProject --parent ParentProject
+  teamcity project create MyProject --description "Services of the payments team"
   teamcity project create MyProject --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,11 +45,12 @@ If --parent is omitted, the project is created under the Root project.`,
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Explicit project ID (default: auto-generated from name)")
 	cmd.Flags().StringVarP(&opts.parent, "parent", "p", "", "Parent project ID (default: _Root)")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Project description")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser after creation")
 	cmd.MarkFlagsMutuallyExclusive("json", "web")
 
-	_ = cmd.RegisterFlagCompletionFunc("parent", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("parent", completion.Projects())
 
 	return cmd
 }
@@ -64,9 +71,22 @@ func runProjectCreate(f *cmdutil.Factory, name string, opts *projectCreateOption
 
 	project, err := client.CreateProject(req)
 	if err != nil {
+		parent := cmp.Or(opts.parent, "_Root")
+		if _, ok := errors.AsType[*api.HTTPError](err); ok {
+			if cerr := checkNewProject(client, parent, opts.id, name); cerr != nil {
+				return cerr
+			}
+		}
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
+	if opts.description != "" {
+		if err := client.SetProjectField(project.ID, "description", opts.description); err != nil {
+			return fmt.Errorf("created project %s, but failed to set its description: %w", project.ID, err)
+		}
+		project.Description = opts.description
+	}
+
 	if opts.web {
 		cmdutil.OpenURLOrWarn(f.Printer, project.WebURL)
 	}
@@ -66,3 +66,43 @@ func TestProjectCreateServerError(T *testing.T) {
 
 	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "failed to create project", "project", "create", "MyProject")
 }
+
+func TestProjectCreateDescription(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	var description string
+	ts.Handle("PUT /app/rest/projects/id:MyProject/description", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		description = string(body)
+		cmdtest.Text(w, description)
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "create", "MyProject", "--description", "Payments services", "--json")
+	assert.Equal(T, "Payments services", description)
+	assert.Contains(T, out, `"description": "Payments services"`)
+}
+
+func TestProjectCreateDescription_dryRun(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	var writes int
+	ts.Handle("POST /app/rest/projects", func(w http.ResponseWriter, r *http.Request) { writes++ })
+	ts.Handle("PUT /app/rest/projects/id:MyProject/description", func(w http.ResponseWriter, r *http.Request) { writes++ })
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "create", "My Project", "--id", "MyProject", "--description", "Payments services", "--dry-run")
+	assert.Zero(T, writes, "--dry-run sends no write")
+	assert.Contains(T, out, "[dry-run] Would send PUT /app/rest/projects/id:MyProject/description  Payments services")
+	assert.NotContains(T, out, "failed to set its description")
+}
+
+func TestProjectCreateTaken(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("POST /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusBadRequest, `Project ID "TestProject" is already used by another project`)
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `project ID "TestProject" already exists`,
+		"project", "create", "Another", "--id", "TestProject")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `project _Root already has a subproject named "Test Project"`,
+		"project", "create", "test project")
+}
@@ -41,7 +41,9 @@ See: https://www.jetbrains.com/help/teamcity/creating-and-editing-projects.html`
 	cmd.AddCommand(newProjectListCmd(f))
 	cmd.AddCommand(newProjectViewCmd(f))
 	cmd.AddCommand(newProjectCreateCmd(f))
+	cmd.AddCommand(newProjectCopyCmd(f))
 	cmd.AddCommand(newProjectTreeCmd(f))
+	cmd.AddCommand(newProjectReportCmd(f))
 	cmd.AddCommand(newProjectTokenCmd(f))
 	cmd.AddCommand(newProjectSettingsCmd(f))
 	cmd.AddCommand(newCloudCmd(f))
@@ -62,88 +64,107 @@ func newProjectListCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &projectListOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List projects",
+		Use:   "list",
+		Short: "List projects",
+		Long: `List projects, sorted by ID.
+
+Use --sort to order by other columns, or --sort none to keep the
+server's order and print rows as each page arrives.`,
 		Aliases: []string{"ls"},
 		Example: `  teamcity project list
   teamcity project list --parent Falcon
   teamcity project list --json
   teamcity project list --json=id,name,webUrl
+  teamcity project list --sort parent,name
   teamcity project list --plain
-  teamcity project list --plain --no-header`,
+  teamcity project list --plain --no-header
+  teamcity project list --template '{{.ID}} {{.ParentProjectID}}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return cmdutil.RunList(f, cmd, &opts.ListFlags, &api.ProjectFields, opts.fetch)
+			return cmdutil.RunStreamList(f, cmd, &opts.ListFlags, &api.ProjectFields, cmdutil.StreamedList{
+				JSONKey:  "project",
+				Headers:  []string{"ID", "NAME", "PARENT"},
+				FlexCols: []int{0, 1, 2},
+				Noun:     "project",
+				EmptyMsg: "No projects found",
+				EmptyTip: output.TipNoProjects,
+			}, projectSortColumns, opts.fetch)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.parent, "parent", "p", "", "Filter by parent project ID")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
+	cmdutil.AddTemplateFlag(cmd, &opts.Template)
+	cmdutil.AddPageSizeFlag(cmd, &opts.ListFlags)
+	cmdutil.AddSortFlags(cmd, &opts.SortFlags, projectSortColumns, "id")
 
-	_ = cmd.RegisterFlagCompletionFunc("parent", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("parent", completion.Projects())
 
 	return cmd
 }
 
-func (opts *projectListOptions) fetch(client api.ClientInterface, fields []string) (*cmdutil.ListResult, error) {
-	projects, truncated, err := client.GetProjects(api.ProjectsOptions{
-		Parent: opts.parent,
-		Limit:  opts.Limit,
-		Fields: fields,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	headers := []string{"ID", "NAME", "PARENT"}
-	var rows [][]string
-
-	for _, p := range projects.Projects {
-		parent := "-"
-		if p.ParentProjectID != "" {
-			parent = p.ParentProjectID
-		}
-
-		rows = append(rows, []string{
-			p.ID,
-			p.Name,
-			parent,
-		})
-	}
+var projectSortColumns = []cmdutil.SortColumn[api.Project]{
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b api.Project) int { return cmdutil.CompareFold(a.ID, b.ID) }},
+	{Name: "name", Fields: []string{"name"}, Compare: func(a, b api.Project) int { return cmdutil.CompareFold(a.Name, b.Name) }},
+	{Name: "parent", Fields: []string{"parentProjectId"}, Compare: func(a, b api.Project) int { return cmdutil.CompareFold(a.ParentProjectID, b.ParentProjectID) }},
+}
 
-	return &cmdutil.ListResult{
-		JSON:      projects,
-		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: []int{0, 1, 2}},
-		EmptyMsg:  "No projects found",
-		EmptyTip:  output.TipNoProjects,
-		Truncated: truncated,
-	}, nil
+func (opts *projectListOptions) fetch(client api.ClientInterface, fields []string, emit func([]api.Project, [][]string) error) (bool, error) {
+	_, truncated, err := client.GetProjects(api.ProjectsOptions{
+		Parent:   opts.parent,
+		Limit:    opts.Limit,
+		Fields:   fields,
+		PageSize: opts.PageSize,
+		OnPage: func(projects []api.Project) error {
+			var rows [][]string
+			for _, p := range projects {
+				parent := "-"
+				if p.ParentProjectID != "" {
+					parent = p.ParentProjectID
+				}
+
+				rows = append(rows, []string{
+					p.ID,
+					p.Name,
+					parent,
+				})
+			}
+			return emit(projects, rows)
+		},
+	})
+	return truncated, err
 }
 
 func newProjectViewCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &cmdutil.ViewOptions{}
+	copyOpts := &cmdutil.CopyOptions{}
 	cmd := &cobra.Command{
 		Use:               "view [project-id]",
 		Short:             "View project details",
 		Long:              `View details of a TeamCity project. With no argument, uses the linked project from teamcity.toml.`,
 		Aliases:           []string{"show"},
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completion.LinkedProjects(),
+		ValidArgsFunction: completion.Projects(),
 		Example: `  teamcity project view Falcon
   teamcity project view Falcon --web
+  teamcity project view Falcon --copy
   teamcity project view              # uses linked project (see 'teamcity link')`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := copyOpts.Validate(); err != nil {
+				return err
+			}
 			projectID, _, err := cmdutil.ResolveOwnerID("project", args, 0, f.ResolveProject)
 			if err != nil {
 				return err
 			}
-			return runProjectView(f, projectID, opts)
+			return runProjectView(f, projectID, opts, copyOpts)
 		},
 	}
 	cmdutil.AddViewFlags(cmd, opts)
+	cmdutil.AddCopyFlag(cmd, copyOpts)
 	return cmd
 }
 
-func runProjectView(f *cmdutil.Factory, projectID string, opts *cmdutil.ViewOptions) error {
+func runProjectView(f *cmdutil.Factory, projectID string, opts *cmdutil.ViewOptions, copyOpts *cmdutil.CopyOptions) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
@@ -153,6 +174,7 @@ func runProjectView(f *cmdutil.Factory, projectID string, opts *cmdutil.ViewOpti
 	if err != nil {
 		return err
 	}
+	copyOpts.Copy(f.Printer, project.WebURL, project.ID)
 
 	if done, err := opts.EmitWebURL(f.Printer, project.WebURL); done {
 		return err
@@ -206,7 +228,7 @@ func newProjectTokenPutCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "put <project-id> [value]",
 		Short:             "Store a secret and get a secure token",
-		ValidArgsFunction: completion.LinkedProjects(),
+		ValidArgsFunction: completion.Projects(),
 		Long: `Store a sensitive value and get a secure token reference.
 
 The returned token can be used in versioned settings configuration files
@@ -290,7 +312,7 @@ func newProjectTokenGetCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "get <project-id> <token>",
 		Short:             "Get the value of a secure token",
-		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.LinkedProjects()),
+		ValidArgsFunction: cmdutil.CompleteOwnerID(completion.Projects()),
 		Long: `Retrieve the original value for a secure token.
 
 This operation requires VIEW_SERVER_SETTINGS permission,
@@ -358,7 +380,7 @@ func newProjectTreeCmd(f *cmdutil.Factory) *cobra.Command {
   teamcity project tree --depth 2
   teamcity project tree --json`,
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completion.LinkedProjects(),
+		ValidArgsFunction: completion.Projects(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			explicit := ""
 			if len(args) > 0 {
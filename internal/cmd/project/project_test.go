@@ -13,6 +13,7 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmd"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,9 @@ func TestProjectList(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "project", "list", "--limit", "5")
 	cmdtest.RunCmdWithFactory(T, f, "project", "list", "--parent", "_Root", "--limit", "3")
 	cmdtest.RunCmdWithFactory(T, f, "project", "list", "--json", "--limit", "2")
+
+	got := cmdtest.CaptureOutput(T, f, "project", "list", "--template", "{{.ID}} <- {{.ParentProjectID}}")
+	assert.Equal(T, "_Root <- \nTestProject <- _Root\n", got, "a template prints only what it renders, without the count footer")
 }
 
 func TestProjectView(T *testing.T) {
@@ -37,8 +41,22 @@ func TestProjectView(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "project", "view", testProject, "--json")
 }
 
+func TestProjectViewCopy(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var copied []string
+	orig := cmdutil.CopyToClipboard
+	T.Cleanup(func() { cmdutil.CopyToClipboard = orig })
+	cmdutil.CopyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "project", "view", testProject, "--copy")
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "project", "view", testProject, "--copy=id")
+
+	assert.Equal(T, []string{ts.URL + "/project.html?projectId=" + testProject, testProject}, copied)
+}
+
 func TestProjectParam(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
+	ts.ServeServerSettings("TestProject")
 	f := ts.Factory
 
 	paramName := "TC_CLI_CMD_TEST"
@@ -114,6 +132,52 @@ func TestProjectSettingsStatusSyncing(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "project", "settings", "status", "SyncingProject")
 }
 
+// handleSettingsSequence serves each status in turn on WatchProject, repeating the last one.
+func handleSettingsSequence(ts *cmdtest.TestServer, statuses ...api.VersionedSettingsStatus) {
+	var calls atomic.Int32
+	ts.Handle("GET /app/rest/projects/WatchProject/versionedSettings/status", func(w http.ResponseWriter, r *http.Request) {
+		i := min(int(calls.Add(1))-1, len(statuses)-1)
+		cmdtest.JSON(w, statuses[i])
+	})
+}
+
+func TestProjectSettingsWatch(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleSettingsSequence(ts,
+		api.VersionedSettingsStatus{Type: "info", Message: "Loaded settings from VCS revision 9f8e7d6"},
+		api.VersionedSettingsStatus{Type: "info", Message: "Running DSL..."},
+		api.VersionedSettingsStatus{Type: "info", Message: "Settings loaded from VCS revision 1a2b3c4d"},
+	)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "settings", "watch", "WatchProject", "-i", "1", "--target-revision", "1a2b3c4")
+	assert.Contains(T, out, "waiting for 1a2b3c4\n")
+	assert.Contains(T, out, "running DSL\n")
+	assert.Contains(T, out, "synchronized\n")
+	assert.Contains(T, out, "1a2b3c4d")
+	assert.Less(T, strings.Index(out, "running DSL"), strings.Index(out, "synchronized"))
+}
+
+func TestProjectSettingsWatchFailure(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleSettingsSequence(ts,
+		api.VersionedSettingsStatus{Type: "error", Message: "Compilation error in revision 1a2b3c4: Unresolved reference: foo"},
+	)
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "project", "settings", "watch", "WatchProject")
+	assert.Contains(T, err.Error(), "settings sync failed at revision 1a2b3c4")
+	assert.Contains(T, err.Error(), "Unresolved reference: foo")
+}
+
+func TestProjectSettingsWatchTimeout(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleSettingsSequence(ts, api.VersionedSettingsStatus{Type: "info", Message: "Resolving Maven dependencies..."})
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "project", "settings", "watch", "WatchProject", "--timeout", "10ms")
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(T, err, &exitErr)
+	assert.Equal(T, cmdutil.ExitTimeout, exitErr.Code)
+}
+
 // TestProjectSettingsStatusParallelFanOut regresses F18/S3: config+status endpoints fetch concurrently, ~delay instead of ~2×delay.
 func TestProjectSettingsStatusParallelFanOut(T *testing.T) {
 	const delay = 300 * time.Millisecond
@@ -217,7 +281,7 @@ func runListSplit(t *testing.T, ts *cmdtest.TestServer, args ...string) (stdout,
 	t.Helper()
 	var out, errBuf bytes.Buffer
 	f := ts.CloneFactory()
-	// NewCommand strips PersistentPreRun (and thus InitOutput), so mirror the
+	// NewCommand skips InitOutput in PersistentPreRun, so mirror the
 	// --quiet → Printer.Quiet wiring that production does at runtime.
 	f.Printer = &output.Printer{Out: &out, ErrOut: &errBuf, Quiet: slices.Contains(args, "--quiet") || slices.Contains(args, "-q")}
 	rootCmd := cmd.NewCommand(f)
@@ -230,7 +294,7 @@ func runListSplit(t *testing.T, ts *cmdtest.TestServer, args ...string) (stdout,
 
 func handleTruncatedProjects(ts *cmdtest.TestServer) {
 	ts.Handle("GET /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Query().Get("locator"), "count:1000") {
+		if strings.Contains(r.URL.Query().Get("locator"), "count:100") { // an unbounded list fetches --page-size at a time
 			cmdtest.JSON(w, map[string]any{"count": 3, "project": []map[string]string{
 				{"id": "P1", "name": "P1"}, {"id": "P2", "name": "P2"}, {"id": "P3", "name": "P3"},
 			}})
@@ -244,6 +308,31 @@ func handleTruncatedProjects(ts *cmdtest.TestServer) {
 	})
 }
 
+func TestProjectListPaged(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	var starts []string
+	ts.Handle("GET /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		starts = append(starts, locator)
+		if strings.Contains(locator, "start:2") {
+			cmdtest.JSON(w, map[string]any{"count": 1, "project": []map[string]string{{"id": "P3", "name": "P3", "parentProjectId": "P1"}}})
+			return
+		}
+		cmdtest.JSON(w, map[string]any{
+			"count":    2,
+			"nextHref": "/app/rest/projects?locator=count:2,start:2",
+			"project":  []map[string]string{{"id": "P1", "name": "P1"}, {"id": "P2", "name": "P2"}},
+		})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "project", "list", "--limit", "0", "--page-size", "2")
+	require.Len(T, starts, 2)
+	assert.Contains(T, starts[0], "count:2")
+	assert.Regexp(T, `P1\s+P1\s+-`, out)
+	assert.Regexp(T, `P3\s+P3\s+P1`, out)
+	assert.Contains(T, out, "3 projects")
+}
+
 const truncationHint = "use --limit 0 to fetch all"
 
 func TestProjectListTruncationHint(T *testing.T) {
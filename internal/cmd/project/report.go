@@ -0,0 +1,300 @@
+package project
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// reportWorkers bounds how many projects a recursive report fetches at once.
+const reportWorkers = 8
+
+const projectAdminRole = "PROJECT_ADMIN"
+
+// ProjectReport is one project's permissions and ownership summary. A nil Roles, VersionedSettings, or
+// SecureParameters means the server would not show it to the current user.
+type ProjectReport struct {
+	ID                  string                   `json:"id"`
+	Name                string                   `json:"name"`
+	ParentProjectID     string                   `json:"parentProjectId,omitempty"`
+	Roles               []api.RoleHolder         `json:"roles"`
+	VersionedSettings   *VersionedSettingsReport `json:"versionedSettings"`
+	BuildConfigurations int                      `json:"buildConfigurations"`
+	SecureParameters    *int                     `json:"secureParameters"`
+}
+
+// VersionedSettingsReport says whether a project's settings are stored in version control, and where.
+type VersionedSettingsReport struct {
+	Enabled    bool   `json:"enabled"`
+	Format     string `json:"format,omitempty"`
+	VcsRootID  string `json:"vcsRootId,omitempty"`
+	Repository string `json:"repository,omitempty"`
+}
+
+type projectReportOptions struct {
+	recursive bool
+	json      bool
+}
+
+func newProjectReportCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &projectReportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "report [project-id]",
+		Short: "Summarize who administers a project and how it is configured",
+		Long: `Summarize permissions and ownership of a project for security reviews.
+
+For each project the report lists:
+- Users and groups assigned Project Administrator, and those holding other roles
+- Whether versioned settings are enabled, and from which repository
+- The number of build configurations
+- The number of secure (password) parameters
+
+Only roles assigned directly in the project are listed; roles inherited from parent projects or granted server-wide are not. Roles show as unknown when you may not view them. With no argument, uses the linked project from teamcity.toml.`,
+		Example: `  teamcity project report Falcon
+  teamcity project report Falcon --recursive
+  teamcity project report Falcon --recursive --json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.Projects(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectID, _, err := cmdutil.ResolveOwnerID("project", args, 0, f.ResolveProject)
+			if err != nil {
+				return err
+			}
+			return runProjectReport(f, projectID, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.recursive, "recursive", "r", false, "Include all subprojects")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runProjectReport(f *cmdutil.Factory, projectID string, opts *projectReportOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	projects, err := reportProjects(client, projectID, opts.recursive)
+	if err != nil {
+		return err
+	}
+
+	jobs, _, err := client.GetBuildTypes(api.BuildTypesOptions{Project: projects[0].ID, Fields: []string{"id", "projectId"}})
+	if err != nil {
+		return err
+	}
+	jobCounts := map[string]int{}
+	for _, bt := range jobs.BuildTypes {
+		jobCounts[bt.ProjectID]++
+	}
+
+	ids := make([]string, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	holders, err := client.GetProjectRoleHolders(f.Context(), ids...)
+	switch {
+	case isPermissionDenied(err):
+		holders = nil
+	case err != nil:
+		return fmt.Errorf("failed to get project roles: %w", err)
+	}
+
+	reports := make([]ProjectReport, len(projects))
+	errs := make([]error, len(projects))
+	limiter := f.NewLimiter(reportWorkers)
+	var wg sync.WaitGroup
+	for i, p := range projects {
+		wg.Go(func() {
+			limiter.Acquire()
+			defer limiter.Release()
+			reports[i], errs[i] = buildProjectReport(client, p)
+			reports[i].BuildConfigurations = jobCounts[p.ID]
+			if holders != nil {
+				reports[i].Roles = holders[p.ID]
+				if reports[i].Roles == nil {
+					reports[i].Roles = []api.RoleHolder{}
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if opts.json {
+		return f.Printer.PrintJSON(reports)
+	}
+	printProjectReports(f.Printer, reports)
+	return nil
+}
+
+// reportProjects returns the root project followed, with recursive, by every subproject depth-first in name order.
+func reportProjects(client api.ClientInterface, rootID string, recursive bool) ([]api.Project, error) {
+	root, err := client.GetProject(rootID)
+	if err != nil {
+		return nil, err
+	}
+	if !recursive {
+		return []api.Project{*root}, nil
+	}
+
+	all, _, err := client.GetProjects(api.ProjectsOptions{Limit: 10000})
+	if err != nil {
+		return nil, err
+	}
+	children := map[string][]api.Project{}
+	for _, p := range all.Projects {
+		if p.ParentProjectID != "" {
+			children[p.ParentProjectID] = append(children[p.ParentProjectID], p)
+		}
+	}
+
+	projects := []api.Project{*root}
+	var walk func(id string)
+	walk = func(id string) {
+		kids := children[id]
+		slices.SortFunc(kids, func(a, b api.Project) int { return strings.Compare(a.Name, b.Name) })
+		for _, kid := range kids {
+			projects = append(projects, kid)
+			walk(kid.ID)
+		}
+	}
+	walk(root.ID)
+	return projects, nil
+}
+
+// buildProjectReport fetches one project's versioned settings and secure parameters; its roles come from a
+// single lookup for all projects. A section the server refuses to show is left nil rather than failing the report.
+func buildProjectReport(client api.ClientInterface, p api.Project) (ProjectReport, error) {
+	report := ProjectReport{ID: p.ID, Name: p.Name, ParentProjectID: p.ParentProjectID}
+
+	cfg, err := client.GetVersionedSettingsConfig(p.ID)
+	switch {
+	case isPermissionDenied(err):
+	case isNotFound(err):
+		report.VersionedSettings = &VersionedSettingsReport{}
+	case err != nil:
+		return report, fmt.Errorf("failed to get versioned settings of project %s: %w", p.ID, err)
+	default:
+		report.VersionedSettings = versionedSettingsReport(client, cfg)
+	}
+
+	params, err := client.GetProjectParameters(p.ID)
+	switch {
+	case isPermissionDenied(err):
+	case err != nil:
+		return report, fmt.Errorf("failed to get parameters of project %s: %w", p.ID, err)
+	default:
+		secure := 0
+		for _, param := range params.Property {
+			if !param.Inherited && (param.IsPassword() || strings.Contains(param.Value, "credentialsJSON:")) {
+				secure++
+			}
+		}
+		report.SecureParameters = &secure
+	}
+
+	return report, nil
+}
+
+func isPermissionDenied(err error) bool {
+	_, ok := errors.AsType[*api.PermissionError](err)
+	return ok
+}
+
+func isNotFound(err error) bool {
+	_, ok := errors.AsType[*api.NotFoundError](err)
+	return ok
+}
+
+// versionedSettingsReport resolves the settings' VCS root to its repository URL, falling back to the root's ID.
+func versionedSettingsReport(client api.ClientInterface, cfg *api.VersionedSettingsConfig) *VersionedSettingsReport {
+	if cfg.SynchronizationMode != "enabled" {
+		return &VersionedSettingsReport{}
+	}
+	report := &VersionedSettingsReport{Enabled: true, Format: cfg.Format, VcsRootID: cfg.VcsRootID}
+	if cfg.VcsRootID == "" {
+		return report
+	}
+	if root, err := client.GetVcsRoot(cfg.VcsRootID); err == nil && root.Properties != nil {
+		for _, prop := range root.Properties.Property {
+			if prop.Name == "url" {
+				report.Repository = prop.Value
+			}
+		}
+	}
+	return report
+}
+
+func printProjectReports(p *output.Printer, reports []ProjectReport) {
+	headers := []string{"PROJECT", "ADMINISTRATORS", "OTHER ROLES", "VERSIONED SETTINGS", "JOBS", "SECURE"}
+	var rows [][]string
+	for _, r := range reports {
+		admins, others := formatRoleHolders(r.Roles)
+		secure := output.Faint("unknown")
+		if r.SecureParameters != nil {
+			secure = strconv.Itoa(*r.SecureParameters)
+		}
+		rows = append(rows, []string{
+			r.ID,
+			admins,
+			others,
+			formatVersionedSettingsReport(r.VersionedSettings),
+			strconv.Itoa(r.BuildConfigurations),
+			secure,
+		})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1, 2, 3)
+	p.PrintTable(headers, rows)
+}
+
+// formatRoleHolders renders Project Administrators as a name list and other roles as "ROLE: names" groups.
+func formatRoleHolders(holders []api.RoleHolder) (admins, others string) {
+	if holders == nil {
+		unknown := output.Faint("unknown")
+		return unknown, unknown
+	}
+	byRole := map[string][]string{}
+	for _, h := range holders {
+		name := h.ID
+		if h.Kind == "group" {
+			name += " (group)"
+		}
+		for _, role := range h.Roles {
+			byRole[role] = append(byRole[role], name)
+		}
+	}
+	admins = cmp.Or(strings.Join(byRole[projectAdminRole], ", "), "-")
+	delete(byRole, projectAdminRole)
+	var groups []string
+	for _, role := range slices.Sorted(maps.Keys(byRole)) {
+		groups = append(groups, role+": "+strings.Join(byRole[role], ", "))
+	}
+	return admins, cmp.Or(strings.Join(groups, "; "), "-")
+}
+
+func formatVersionedSettingsReport(vs *VersionedSettingsReport) string {
+	switch {
+	case vs == nil:
+		return output.Faint("unknown")
+	case !vs.Enabled:
+		return "disabled"
+	}
+	return strings.TrimSpace(cmp.Or(vs.Format, "enabled") + " from " + cmp.Or(vs.Repository, vs.VcsRootID, "-"))
+}
@@ -0,0 +1,128 @@
+package project_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/project"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+// setupProjectReport serves Falcon, with versioned settings from a Git repo, and its subprojects Falcon_Web and
+// Falcon_Api, without versioned settings.
+func setupProjectReport(t *testing.T) *cmdtest.TestServer {
+	t.Helper()
+	ts := cmdtest.SetupMockClient(t)
+	projects := []api.Project{
+		{ID: "_Root", Name: "Root project"},
+		{ID: "Falcon", Name: "Falcon", ParentProjectID: "_Root"},
+		{ID: "Falcon_Web", Name: "Web", ParentProjectID: "Falcon"},
+		{ID: "Falcon_Api", Name: "Api", ParentProjectID: "Falcon"},
+		{ID: "Other", Name: "Other", ParentProjectID: "_Root"},
+	}
+	ts.Handle("GET /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProjectList{Count: len(projects), Projects: projects})
+	})
+	ts.Handle("GET /app/rest/projects/id:", func(w http.ResponseWriter, r *http.Request) {
+		id := cmdtest.ExtractID(r.URL.Path, "id:")
+		if strings.HasSuffix(r.URL.Path, "/parameters") {
+			params := []api.Parameter{{Name: "env.TOKEN", Value: "", Type: &api.ParameterType{RawValue: "password display='hidden'"}}}
+			if id == "Falcon" {
+				params = append(params,
+					api.Parameter{Name: "deploy.key", Value: "credentialsJSON:1b2c"},
+					api.Parameter{Name: "inherited.secret", Inherited: true, Type: &api.ParameterType{RawValue: "password"}})
+			}
+			cmdtest.JSON(w, api.ParameterList{Count: len(params), Property: params})
+			return
+		}
+		for _, p := range projects {
+			if p.ID == id {
+				cmdtest.JSON(w, p)
+				return
+			}
+		}
+		cmdtest.Error(w, http.StatusNotFound, "no project")
+	})
+	ts.Handle("GET /app/rest/projects/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/app/rest/projects/Falcon/versionedSettings/config") {
+			cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", Format: "kotlin", VcsRootID: "Falcon_Settings"})
+			return
+		}
+		cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "disabled"})
+	})
+	ts.Handle("GET /app/rest/vcs-roots/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.VcsRoot{ID: "Falcon_Settings", Properties: &api.PropertyList{Property: []api.Property{{Name: "url", Value: "https://git.example.com/falcon-settings.git"}}}})
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 3, BuildTypes: []api.BuildType{
+			{ID: "Falcon_Build", ProjectID: "Falcon"}, {ID: "Falcon_Web_Build", ProjectID: "Falcon_Web"}, {ID: "Falcon_Web_Test", ProjectID: "Falcon_Web"},
+		}})
+	})
+	ts.Handle("GET /app/rest/users", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		if locator != "" && !strings.Contains(locator, "p:Falcon)") {
+			_, _ = w.Write([]byte(`{"user":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"user":[
+			{"username":"jane","roles":{"role":[{"roleId":"PROJECT_ADMIN","scope":"p:Falcon"},{"roleId":"PROJECT_VIEWER","scope":"p:Other"}]}},
+			{"username":"bob","roles":{"role":[{"roleId":"PROJECT_DEVELOPER","scope":"p:Falcon"},{"roleId":"PROJECT_DEVELOPER","scope":"p:Falcon_Web"}]}}]}`))
+	})
+	ts.Handle("GET /app/rest/userGroups", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"group":[{"key":"OPS","name":"Operations","roles":{"role":[{"roleId":"PROJECT_ADMIN","scope":"p:Falcon"}]}}]}`))
+	})
+	return ts
+}
+
+func TestProjectReport(t *testing.T) {
+	ts := setupProjectReport(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "project", "report", "Falcon")
+	assert.Regexp(t, `PROJECT\s+ADMINISTRATORS\s+OTHER ROLES\s+VERSIONED SETTINGS\s+JOBS\s+SECURE`, out)
+	assert.Regexp(t, `Falcon\s+jane, OPS \(group\)\s+PROJECT_DEVELOPER: bob\s+kotlin from https://git.example.com/falcon-settings\S*\s+1\s+2`, out)
+	assert.NotContains(t, out, "Falcon_Web", "subprojects need --recursive")
+}
+
+func TestProjectReportRecursive(t *testing.T) {
+	ts := setupProjectReport(t)
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "project", "report", "Falcon", "--recursive")
+	assert.Regexp(t, `Falcon_Api\s+-\s+-\s+disabled\s+0\s+1`, out)
+	assert.Regexp(t, `Falcon_Web\s+-\s+PROJECT_DEVELOPER: bob\s+disabled\s+2\s+1`, out)
+	assert.Less(t, strings.Index(out, "Falcon_Api"), strings.Index(out, "Falcon_Web"), "subprojects are listed by name")
+	assert.NotContains(t, out, "Other")
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "project", "report", "Falcon", "--recursive", "--json")
+	var reports []project.ProjectReport
+	require.NoError(t, json.Unmarshal([]byte(out), &reports))
+	require.Len(t, reports, 3)
+	assert.Equal(t, "Falcon", reports[0].ID)
+	assert.Len(t, reports[0].Roles, 3)
+	assert.True(t, reports[0].VersionedSettings.Enabled)
+	assert.Equal(t, 2, *reports[0].SecureParameters)
+	assert.NotNil(t, reports[1].Roles, "no roles is an empty list")
+}
+
+func TestProjectReportRolesDenied(t *testing.T) {
+	ts := setupProjectReport(t)
+	users := 0
+	ts.Handle("GET /app/rest/users", func(w http.ResponseWriter, r *http.Request) {
+		users++
+		cmdtest.Error(w, http.StatusForbidden, "You do not have enough permissions to view users")
+	})
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "project", "report", "Falcon", "--recursive")
+	assert.Regexp(t, `Falcon_Web\s+unknown\s+unknown\s+disabled\s+2\s+1`, out, "a denied roles lookup degrades to unknown")
+	assert.Equal(t, 1, users, "one users request covers every project")
+
+	out = cmdtest.CaptureOutput(t, ts.Factory, "project", "report", "Falcon", "--json")
+	var reports []project.ProjectReport
+	require.NoError(t, json.Unmarshal([]byte(out), &reports))
+	assert.Nil(t, reports[0].Roles, "unknown roles are null")
+}
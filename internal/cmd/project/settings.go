@@ -36,6 +36,7 @@ See: https://www.jetbrains.com/help/teamcity/storing-project-settings-in-version
 	}
 
 	cmd.AddCommand(newProjectSettingsStatusCmd(f))
+	cmd.AddCommand(newProjectSettingsWatchCmd(f))
 	cmd.AddCommand(newProjectSettingsExportCmd(f))
 	cmd.AddCommand(newProjectSettingsValidateCmd(f))
 
@@ -52,7 +53,7 @@ func newProjectSettingsStatusCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "status <project-id>",
 		Short:             "Show versioned settings sync status",
-		ValidArgsFunction: completion.LinkedProjects(),
+		ValidArgsFunction: completion.Projects(),
 		Long: `Show the synchronization status of versioned settings for a project.
 
 Displays:
@@ -196,7 +197,7 @@ func newProjectSettingsExportCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "export <project-id>",
 		Short:             "Export project settings as Kotlin DSL or XML",
-		ValidArgsFunction: completion.LinkedProjects(),
+		ValidArgsFunction: completion.Projects(),
 		Long: `Export project settings as a ZIP archive containing Kotlin DSL or XML configuration.
 
 The exported archive can be used to:
@@ -311,14 +312,24 @@ func getSyncingStatus(message string) string {
 	if strings.Contains(lowerMsg, "waiting for update") {
 		return "waiting for VCS"
 	}
+	if strings.Contains(lowerMsg, "checking for changes") {
+		return "checking for changes"
+	}
+	if strings.Contains(lowerMsg, "compiling") {
+		return "compiling DSL"
+	}
+	if strings.Contains(lowerMsg, "applying") || strings.Contains(lowerMsg, "updating project settings") {
+		return "applying settings"
+	}
 
 	return ""
 }
 
 type projectSettingsValidateOptions struct {
-	verbose bool
-	json    bool
-	path    string
+	verbose       bool
+	json          bool
+	againstServer bool
+	path          string
 }
 
 func newProjectSettingsValidateCmd(f *cmdutil.Factory) *cobra.Command {
@@ -333,10 +344,18 @@ Auto-detects .teamcity directory in the current directory or parents.
 Requires Maven (mvn) or uses mvnw wrapper if present in the DSL directory.
 
 Optional [path] must be a filesystem path to a .teamcity directory.
-This command does not accept TeamCity project IDs and has no --dir flag.`,
+This command does not accept TeamCity project IDs and has no --dir flag.
+
+With --against-server, the generated configs are then cross-checked against the server you are
+logged in to: referenced VCS roots and templates must exist there (or be defined in the DSL),
+credentialsJSON tokens must resolve in the owning project or one of its parents, and agent
+requirements should name a parameter that at least one connected agent reports. Missing entities
+and dangling tokens are errors and exit with status 1; anything that could not be checked, and
+requirements no connected agent satisfies, are warnings.`,
 		Example: `  teamcity project settings validate
   teamcity project settings validate ./path/to/.teamcity
-  teamcity project settings validate --verbose`,
+  teamcity project settings validate --verbose
+  teamcity project settings validate --against-server --json`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		ValidArgsFunction: func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
@@ -352,13 +371,16 @@ This command does not accept TeamCity project IDs and has no --dir flag.`,
 
 	cmd.Flags().BoolVar(&opts.verbose, "verbose", false, "Show full Maven output")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.againstServer, "against-server", false, "Cross-check the generated configs against the server")
 
 	return cmd
 }
 
 type validateResultJSON struct {
-	Valid bool   `json:"valid"`
-	Path  string `json:"path"`
+	Valid    bool              `json:"valid"`
+	Path     string            `json:"path"`
+	Server   string            `json:"server,omitempty"`
+	Findings []settingsFinding `json:"findings,omitempty"`
 }
 
 func runProjectSettingsValidate(f *cmdutil.Factory, opts *projectSettingsValidateOptions) error {
@@ -387,6 +409,14 @@ func runProjectSettingsValidate(f *cmdutil.Factory, opts *projectSettingsValidat
 		return err
 	}
 
+	// Resolve the client first so a missing login fails before the Maven run, not after it.
+	var client api.ClientInterface
+	if opts.againstServer {
+		if client, err = f.Client(); err != nil {
+			return err
+		}
+	}
+
 	p := f.Printer
 	if !p.Quiet && !opts.json {
 		_, _ = fmt.Fprintf(p.Out, "Validating %s\n", output.Faint(dslDir))
@@ -428,8 +458,28 @@ func runProjectSettingsValidate(f *cmdutil.Factory, opts *projectSettingsValidat
 		return errors.New("validation failed")
 	}
 
+	result := validateResultJSON{Valid: true, Path: dslDir}
+	if opts.againstServer {
+		configs, err := parseGeneratedConfigs(filepath.Join(dslDir, "target", "generated-configs"))
+		if err != nil {
+			return err
+		}
+		if result.Findings, err = checkAgainstServer(f.Context(), client, configs); err != nil {
+			return err
+		}
+		result.Server = client.ServerURL()
+		errs, _ := countFindings(result.Findings)
+		result.Valid = errs == 0
+	}
+
 	if opts.json {
-		return f.Printer.PrintJSON(validateResultJSON{Valid: true, Path: dslDir})
+		if err := f.Printer.PrintJSON(result); err != nil {
+			return err
+		}
+		if !result.Valid {
+			return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+		}
+		return nil
 	}
 
 	_, _ = fmt.Fprintf(p.Out, "%s Configuration valid\n", output.Green(output.Sym().Check))
@@ -441,6 +491,13 @@ func runProjectSettingsValidate(f *cmdutil.Factory, opts *projectSettingsValidat
 		_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(stats))
 	}
 
+	if opts.againstServer {
+		printSettingsFindings(p, result.Server, result.Findings)
+		if !result.Valid {
+			return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
+		}
+	}
+
 	return nil
 }
 
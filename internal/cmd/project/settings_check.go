@@ -0,0 +1,433 @@
+package project
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+const (
+	findingError   = "error"
+	findingWarning = "warning"
+
+	checkVcsRoot     = "vcs-root"
+	checkTemplate    = "template"
+	checkToken       = "token"
+	checkRequirement = "requirement"
+)
+
+const (
+	// agentPropertyWorkers caps concurrent agent property requests during the requirement check.
+	agentPropertyWorkers = 8
+	// maxProjectDepth guards the walk up a project chain against a cyclic parent reference.
+	maxProjectDepth = 64
+)
+
+var credentialsTokenPattern = regexp.MustCompile(`credentialsJSON:[\w-]+`)
+
+// settingsFinding is one problem the server cross-check found in the generated configs.
+// File is relative to target/generated-configs; it is empty for findings that are not about one file.
+type settingsFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// configRef is an ID a generated config file refers to, with the project that owns the file.
+type configRef struct {
+	ID      string
+	Project string
+	File    string
+	Line    int
+}
+
+func (r configRef) finding(severity, check, format string, args ...any) settingsFinding {
+	return settingsFinding{Severity: severity, Check: check, File: r.File, Line: r.Line, Message: fmt.Sprintf(format, args...)}
+}
+
+// generatedConfigs indexes what the XML under target/generated-configs defines and what it refers to.
+type generatedConfigs struct {
+	parents      map[string]string // project ID → parent project ID
+	vcsRoots     map[string]bool
+	templates    map[string]bool
+	vcsRootRefs  []configRef
+	templateRefs []configRef
+	tokenRefs    []configRef
+	requirements []configRef
+}
+
+// parseGeneratedConfigs reads every project directory under configsDir; each is named after its project ID.
+func parseGeneratedConfigs(configsDir string) (*generatedConfigs, error) {
+	entries, err := os.ReadDir(configsDir)
+	if err != nil {
+		return nil, fmt.Errorf("no generated configs: %w", err)
+	}
+	g := &generatedConfigs{parents: map[string]string{}, vcsRoots: map[string]bool{}, templates: map[string]bool{}}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		projectID := e.Name()
+		g.parents[projectID] = ""
+		err := filepath.WalkDir(filepath.Join(configsDir, projectID), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".xml" {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			rel, _ := filepath.Rel(configsDir, path)
+			if err := g.scanFile(f, projectID, filepath.ToSlash(rel)); err != nil {
+				return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// scanFile records what one config file defines and refers to. The file's root element says what it is:
+// project-config.xml holds a <project>, and buildTypes/ and vcsRoots/ hold one <build-type>, <template>,
+// or <vcs-root> each, named after its ID.
+func (g *generatedConfigs) scanFile(r io.Reader, projectID, file string) error {
+	id := strings.TrimSuffix(filepath.Base(file), ".xml")
+	d := xml.NewDecoder(r)
+	var stack []string
+	scanTokens := func(text string, line int) {
+		for _, token := range credentialsTokenPattern.FindAllString(text, -1) {
+			g.tokenRefs = append(g.tokenRefs, configRef{ID: token, Project: projectID, File: file, Line: line})
+		}
+	}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := d.InputPos()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			ref := func(attr string) configRef {
+				return configRef{ID: xmlAttr(t, attr), Project: projectID, File: file, Line: line}
+			}
+			if len(stack) == 0 {
+				switch t.Name.Local {
+				case "project":
+					g.parents[projectID] = xmlAttr(t, "parent-id")
+				case "template":
+					g.templates[id] = true
+				case "vcs-root":
+					g.vcsRoots[id] = true
+				}
+			}
+			switch {
+			case t.Name.Local == "settings" && xmlAttr(t, "ref") != "":
+				for tmpl := range strings.SplitSeq(xmlAttr(t, "ref"), ",") {
+					r := ref("ref")
+					r.ID = strings.TrimSpace(tmpl)
+					g.templateRefs = append(g.templateRefs, r)
+				}
+			case t.Name.Local == "vcs-entry-ref" && xmlAttr(t, "root-id") != "":
+				g.vcsRootRefs = append(g.vcsRootRefs, ref("root-id"))
+			case len(stack) > 0 && stack[len(stack)-1] == "requirements" && t.Name.Local != "not-exists":
+				// A name built from a parameter reference is only known once the build starts.
+				if name := xmlAttr(t, "name"); name != "" && !strings.Contains(name, "%") {
+					g.requirements = append(g.requirements, ref("name"))
+				}
+			}
+			for _, a := range t.Attr {
+				scanTokens(a.Value, line)
+			}
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			scanTokens(string(t), line)
+		}
+	}
+}
+
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// checkAgainstServer cross-checks the references in g with the server: VCS roots and templates must exist
+// there unless the configs define them, secure tokens must resolve in the owning project or a parent, and
+// agent requirements should name a parameter that a connected agent reports. Errors come first.
+func checkAgainstServer(ctx context.Context, client api.ClientInterface, g *generatedConfigs) ([]settingsFinding, error) {
+	var findings []settingsFinding
+	for _, check := range []func(context.Context, api.ClientInterface) ([]settingsFinding, error){
+		g.checkVcsRoots, g.checkTemplates, g.checkTokens, g.checkRequirements,
+	} {
+		f, err := check(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, f...)
+	}
+	slices.SortStableFunc(findings, func(a, b settingsFinding) int {
+		return cmp.Compare(severityRank(a.Severity), severityRank(b.Severity))
+	})
+	return findings, nil
+}
+
+func severityRank(severity string) int {
+	if severity == findingError {
+		return 0
+	}
+	return 1
+}
+
+func (g *generatedConfigs) checkVcsRoots(_ context.Context, client api.ClientInterface) ([]settingsFinding, error) {
+	return checkExistence(g.vcsRootRefs, g.vcsRoots, checkVcsRoot, "VCS root", func(id string) error {
+		_, err := client.GetVcsRoot(id)
+		return err
+	})
+}
+
+func (g *generatedConfigs) checkTemplates(_ context.Context, client api.ClientInterface) ([]settingsFinding, error) {
+	return checkExistence(g.templateRefs, g.templates, checkTemplate, "template", func(id string) error {
+		_, err := client.GetTemplate(id)
+		return err
+	})
+}
+
+// checkExistence looks up each referenced ID that local does not define, once per ID.
+func checkExistence(refs []configRef, local map[string]bool, check, noun string, get func(string) error) ([]settingsFinding, error) {
+	var findings []settingsFinding
+	status := map[string]string{}
+	for _, r := range refs {
+		if local[r.ID] {
+			continue
+		}
+		s, ok := status[r.ID]
+		if !ok {
+			switch err := get(r.ID); {
+			case err == nil:
+			case isNotFound(err):
+				s = findingError
+			case isPermissionDenied(err):
+				s = findingWarning
+			default:
+				return nil, fmt.Errorf("failed to check %s %s: %w", noun, r.ID, err)
+			}
+			status[r.ID] = s
+		}
+		switch s {
+		case findingError:
+			findings = append(findings, r.finding(findingError, check, "%s %s does not exist on the server", noun, r.ID))
+		case findingWarning:
+			findings = append(findings, r.finding(findingWarning, check, "could not check %s %s: permission denied", noun, r.ID))
+		}
+	}
+	return findings, nil
+}
+
+func (g *generatedConfigs) checkTokens(_ context.Context, client api.ClientInterface) ([]settingsFinding, error) {
+	var findings []settingsFinding
+	chains := map[string][]string{}
+	type tokenKey struct{ token, project string }
+	status := map[tokenKey]string{}
+	for _, r := range g.tokenRefs {
+		key := tokenKey{r.ID, r.Project}
+		s, ok := status[key]
+		if !ok {
+			chain, found := chains[r.Project]
+			if !found {
+				var err error
+				if chain, err = g.projectChain(client, r.Project); err != nil {
+					return nil, err
+				}
+				chains[r.Project] = chain
+			}
+			var err error
+			if s, err = resolveSecureToken(client, chain, strings.TrimPrefix(r.ID, "credentialsJSON:")); err != nil {
+				return nil, err
+			}
+			status[key] = s
+		}
+		switch s {
+		case findingError:
+			findings = append(findings, r.finding(findingError, checkToken, "%s does not resolve in project %s or its parents", r.ID, r.Project))
+		case findingWarning:
+			findings = append(findings, r.finding(findingWarning, checkToken, "could not check %s: checking tokens needs the System Administrator role", r.ID))
+		}
+	}
+	return findings, nil
+}
+
+// projectChain returns projectID followed by its ancestors, taking parents from the generated configs
+// and then from the server. A project missing on both ends the chain.
+func (g *generatedConfigs) projectChain(client api.ClientInterface, projectID string) ([]string, error) {
+	var chain []string
+	for id := projectID; id != "" && len(chain) < maxProjectDepth; {
+		chain = append(chain, id)
+		if parent, ok := g.parents[id]; ok && parent != "" {
+			id = parent
+			continue
+		}
+		p, err := client.GetProject(id)
+		switch {
+		case err == nil:
+			id = p.ParentProjectID
+		case isNotFound(err):
+			id = ""
+		default:
+			return nil, fmt.Errorf("failed to get project %s: %w", id, err)
+		}
+	}
+	return chain, nil
+}
+
+// resolveSecureToken looks token up in each project of chain, nearest first, without printing its value.
+// It returns "" when the token resolves, an error severity when it resolves nowhere, and a warning severity
+// when the server does not let the user check.
+func resolveSecureToken(client api.ClientInterface, chain []string, token string) (string, error) {
+	for _, projectID := range chain {
+		_, err := client.GetSecureValue(projectID, token)
+		switch {
+		case err == nil:
+			return "", nil
+		case isNotFound(err):
+			continue
+		case isPermissionDenied(err):
+			return findingWarning, nil
+		default:
+			return "", fmt.Errorf("failed to check credentialsJSON:%s in project %s: %w", token, projectID, err)
+		}
+	}
+	return findingError, nil
+}
+
+func (g *generatedConfigs) checkRequirements(ctx context.Context, client api.ClientInterface) ([]settingsFinding, error) {
+	if len(g.requirements) == 0 {
+		return nil, nil
+	}
+	agents, _, err := client.GetAgents(api.AgentsOptions{Authorized: true, Connected: true, Fields: []string{"id", "name"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	if len(agents.Agents) == 0 {
+		return []settingsFinding{{
+			Severity: findingWarning,
+			Check:    checkRequirement,
+			Message:  "no connected agents to check " + countNoun(len(g.requirements), "agent requirement") + " against",
+		}}, nil
+	}
+
+	reported, err := agentParameterNames(ctx, client, agents.Agents)
+	if err != nil {
+		return nil, err
+	}
+	var findings []settingsFinding
+	for _, r := range g.requirements {
+		if !reported[r.ID] {
+			findings = append(findings, r.finding(findingWarning, checkRequirement, "no connected agent reports parameter %s", r.ID))
+		}
+	}
+	return findings, nil
+}
+
+// agentParameterNames returns the union of the parameter names the agents report.
+func agentParameterNames(ctx context.Context, client api.ClientInterface, agents []api.Agent) (map[string]bool, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		names    = map[string]bool{}
+		sem      = make(chan struct{}, agentPropertyWorkers)
+	)
+	for _, a := range agents {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			props, err := client.GetAgentProperties(ctx, a.ID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get parameters of agent %s: %w", a.Name, err)
+				}
+				return
+			}
+			for _, p := range props.Property {
+				names[p.Name] = true
+			}
+		})
+	}
+	wg.Wait()
+	return names, firstErr
+}
+
+// countFindings returns how many findings are errors and how many are warnings.
+func countFindings(findings []settingsFinding) (errs, warnings int) {
+	for _, f := range findings {
+		if f.Severity == findingError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	return errs, warnings
+}
+
+func printSettingsFindings(p *output.Printer, serverURL string, findings []settingsFinding) {
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintf(p.Out, "Checked against %s\n", serverURL)
+	errs, warnings := countFindings(findings)
+	if len(findings) == 0 {
+		_, _ = fmt.Fprintf(p.Out, "%s All references resolve on the server\n", output.Green(output.Sym().Check))
+		return
+	}
+	for _, f := range findings {
+		mark := output.Yellow("!")
+		if f.Severity == findingError {
+			mark = output.Red(output.Sym().Cross)
+		}
+		where := ""
+		if f.File != "" {
+			where = f.File
+			if f.Line > 0 {
+				where += fmt.Sprintf(":%d", f.Line)
+			}
+			where = output.Faint(where) + " "
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s %s%s\n", mark, where, f.Message)
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintf(p.Out, "%s, %s\n", countNoun(errs, "error"), countNoun(warnings, "warning"))
+}
+
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,216 @@
+package project
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedConfigsFixture writes the shape mvn teamcity-configs:generate leaves in target/generated-configs:
+// MyApp (child of _Root) defines a VCS root and a template, and MyApp_Sub is a child of MyApp.
+func generatedConfigsFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"MyApp/project-config.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" parent-id="_Root" uuid="0f5c">
+  <name>My App</name>
+  <parameters>
+    <param name="deploy.token" value="credentialsJSON:1111-aaaa" spec="password display='hidden'" />
+  </parameters>
+</project>`,
+		"MyApp/vcsRoots/MyApp_Main.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<vcs-root type="jetbrains.git">
+  <name>main</name>
+  <param name="secure:password" value="credentialsJSON:2222-bbbb" />
+</vcs-root>`,
+		"MyApp/buildTypes/MyApp_Base.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<template>
+  <name>Base</name>
+  <settings>
+    <requirements>
+      <exists id="RQ_1" name="env.JAVA_HOME" />
+    </requirements>
+  </settings>
+</template>`,
+		"MyApp/buildTypes/MyApp_Build.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<build-type uuid="a1b2">
+  <name>Build</name>
+  <settings ref="MyApp_Base">
+    <parameters>
+      <param name="registry.password" value="credentialsJSON:3333-dead" />
+    </parameters>
+    <vcs-settings>
+      <vcs-entry-ref root-id="MyApp_Main" />
+      <vcs-entry-ref root-id="Shared_Repo" />
+      <vcs-entry-ref root-id="Gone_Repo" />
+    </vcs-settings>
+    <requirements>
+      <exists id="RQ_2" name="env.JAVA_HOME" />
+      <equals id="RQ_3" name="teamcity.agent.jvm.os.name" value="Linux" />
+      <exists id="RQ_4" name="env.DOCKER_HOST" />
+      <not-exists id="RQ_5" name="env.CI_SKIP" />
+      <equals id="RQ_6" name="%target.param%" value="x" />
+    </requirements>
+  </settings>
+</build-type>`,
+		"MyApp/buildTypes/MyApp_Deploy.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<build-type uuid="c3d4">
+  <name>Deploy</name>
+  <settings ref="Missing_Template" />
+</build-type>`,
+		"MyApp_Sub/project-config.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<project parent-id="MyApp" uuid="77aa">
+  <name>Sub</name>
+  <project-extensions>
+    <extension id="PROJECT_EXT_1" type="OAuthProvider">
+      <parameters>
+        <param name="secure:clientSecret" value="credentialsJSON:1111-aaaa" />
+      </parameters>
+    </extension>
+  </project-extensions>
+</project>`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestParseGeneratedConfigs(t *testing.T) {
+	t.Parallel()
+
+	g, err := parseGeneratedConfigs(generatedConfigsFixture(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"MyApp": "_Root", "MyApp_Sub": "MyApp"}, g.parents)
+	assert.Equal(t, map[string]bool{"MyApp_Main": true}, g.vcsRoots)
+	assert.Equal(t, map[string]bool{"MyApp_Base": true}, g.templates)
+
+	ids := func(refs []configRef) []string {
+		var out []string
+		for _, r := range refs {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"MyApp_Main", "Shared_Repo", "Gone_Repo"}, ids(g.vcsRootRefs))
+	assert.ElementsMatch(t, []string{"MyApp_Base", "Missing_Template"}, ids(g.templateRefs))
+	assert.ElementsMatch(t, []string{"credentialsJSON:1111-aaaa", "credentialsJSON:2222-bbbb", "credentialsJSON:3333-dead", "credentialsJSON:1111-aaaa"}, ids(g.tokenRefs))
+	assert.ElementsMatch(t, []string{"env.JAVA_HOME", "env.JAVA_HOME", "teamcity.agent.jvm.os.name", "env.DOCKER_HOST"}, ids(g.requirements),
+		"not-exists and parameter-reference requirements are skipped")
+
+	gone := g.vcsRootRefs[2]
+	assert.Equal(t, "MyApp/buildTypes/MyApp_Build.xml", gone.File)
+	assert.Equal(t, 11, gone.Line)
+	assert.Equal(t, "MyApp", gone.Project)
+
+	_, err = parseGeneratedConfigs(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+// settingsCheckServer knows VCS root Shared_Repo, keeps token 1111-aaaa in MyApp and 2222-bbbb in
+// _Root, and has two connected agents that report env.JAVA_HOME and teamcity.agent.jvm.os.name.
+func settingsCheckServer(t *testing.T) *api.Client {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path := r.URL.Path; {
+		case path == "/app/rest/vcs-roots/id:Shared_Repo":
+			json.NewEncoder(w).Encode(api.VcsRoot{ID: "Shared_Repo"})
+		case path == "/app/rest/projects/id:_Root":
+			json.NewEncoder(w).Encode(api.Project{ID: "_Root"})
+		case path == "/app/rest/projects/MyApp/secure/values/1111-aaaa",
+			path == "/app/rest/projects/_Root/secure/values/2222-bbbb":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("s3cret"))
+		case path == "/app/rest/agents":
+			json.NewEncoder(w).Encode(api.AgentList{Count: 2, Agents: []api.Agent{{ID: 1, Name: "linux-1"}, {ID: 2, Name: "linux-2"}}})
+		case path == "/app/rest/agents/id:1":
+			json.NewEncoder(w).Encode(map[string]any{"properties": api.PropertyList{Property: []api.Property{{Name: "env.JAVA_HOME", Value: "/opt/jdk"}}}})
+		case path == "/app/rest/agents/id:2":
+			json.NewEncoder(w).Encode(map[string]any{"properties": api.PropertyList{Property: []api.Property{{Name: "teamcity.agent.jvm.os.name", Value: "Linux"}}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]string{{"message": "not found: " + path}}})
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return api.NewClient(ts.URL, "test")
+}
+
+func TestCheckAgainstServer(t *testing.T) {
+	t.Parallel()
+
+	g, err := parseGeneratedConfigs(generatedConfigsFixture(t))
+	require.NoError(t, err)
+
+	findings, err := checkAgainstServer(t.Context(), settingsCheckServer(t), g)
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, strings.Join([]string{f.Severity, f.Check, f.File, f.Message}, " | "))
+	}
+	assert.Equal(t, []string{
+		"error | vcs-root | MyApp/buildTypes/MyApp_Build.xml | VCS root Gone_Repo does not exist on the server",
+		"error | template | MyApp/buildTypes/MyApp_Deploy.xml | template Missing_Template does not exist on the server",
+		"error | token | MyApp/buildTypes/MyApp_Build.xml | credentialsJSON:3333-dead does not resolve in project MyApp or its parents",
+		"warning | requirement | MyApp/buildTypes/MyApp_Build.xml | no connected agent reports parameter env.DOCKER_HOST",
+	}, got)
+
+	errs, warnings := countFindings(findings)
+	assert.Equal(t, 3, errs)
+	assert.Equal(t, 1, warnings)
+}
+
+func TestCheckAgainstServerWithoutAgents(t *testing.T) {
+	t.Parallel()
+
+	g := &generatedConfigs{requirements: []configRef{{ID: "env.JAVA_HOME"}}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.AgentList{})
+	}))
+	t.Cleanup(ts.Close)
+
+	findings, err := checkAgainstServer(t.Context(), api.NewClient(ts.URL, "test"), g)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, findingWarning, findings[0].Severity)
+	assert.Equal(t, "no connected agents to check 1 agent requirement against", findings[0].Message)
+}
+
+func TestCheckAgainstServerTokenPermission(t *testing.T) {
+	t.Parallel()
+
+	g := &generatedConfigs{
+		parents:   map[string]string{"MyApp": ""},
+		tokenRefs: []configRef{{ID: "credentialsJSON:1111-aaaa", Project: "MyApp", File: "MyApp/project-config.xml", Line: 4}},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/secure/values/") {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.Project{ID: "MyApp"})
+	}))
+	t.Cleanup(ts.Close)
+
+	findings, err := checkAgainstServer(t.Context(), api.NewClient(ts.URL, "test"), g)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, findingWarning, findings[0].Severity)
+	assert.Contains(t, findings[0].Message, "System Administrator")
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"testing"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +35,36 @@ func TestParseKotlinErrors(T *testing.T) {
 		})
 	}
 }
+
+func TestWatchSettingsState(T *testing.T) {
+	T.Parallel()
+
+	const sha = "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d"
+	tests := []struct {
+		name      string
+		status    api.VersionedSettingsStatus
+		target    string
+		wantLabel string
+		wantPhase settingsSyncPhase
+		wantRev   string
+	}{
+		{"loading", api.VersionedSettingsStatus{Type: "info", Message: "Loading project settings from VCS..."}, "", "loading from VCS", settingsSyncing, ""},
+		{"compiling", api.VersionedSettingsStatus{Type: "info", Message: "Compiling Kotlin DSL scripts"}, "", "compiling DSL", settingsSyncing, ""},
+		{"synced", api.VersionedSettingsStatus{Type: "info", Message: "Settings were loaded from VCS revision 1a2b3c4d5e6f"}, "", "synchronized", settingsSynced, "1a2b3c4d5e6f"},
+		{"warning", api.VersionedSettingsStatus{Type: "warning", Message: "DSL outdated"}, "", "synchronized with warnings", settingsSynced, ""},
+		{"error beats progress text", api.VersionedSettingsStatus{Type: "error", Message: "Compiling failed at revision: 9f8e7d6"}, "", "error", settingsFailed, "9f8e7d6"},
+		{"target applied", api.VersionedSettingsStatus{Type: "info", Message: "Loaded from revision 1a2b3c4"}, sha, "synchronized", settingsSynced, "1a2b3c4"},
+		{"older revision synced", api.VersionedSettingsStatus{Type: "info", Message: "Loaded from revision 9f8e7d6"}, sha, "waiting for 1a2b3c4", settingsSyncing, "9f8e7d6"},
+		{"older revision failed", api.VersionedSettingsStatus{Type: "error", Message: "Failed at revision 9f8e7d6"}, sha, "waiting for 1a2b3c4", settingsSyncing, "9f8e7d6"},
+		{"failure without revision", api.VersionedSettingsStatus{Type: "error", Message: "boom"}, sha, "error", settingsFailed, ""},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			label, phase, rev := watchSettingsState(&tc.status, tc.target)
+			assert.Equal(t, tc.wantLabel, label)
+			assert.Equal(t, tc.wantPhase, phase)
+			assert.Equal(t, tc.wantRev, rev)
+		})
+	}
+}
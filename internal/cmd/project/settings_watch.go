@@ -0,0 +1,205 @@
+package project
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/git"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type projectSettingsWatchOptions struct {
+	interval       int
+	timeout        time.Duration
+	targetRevision string
+}
+
+func newProjectSettingsWatchCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &projectSettingsWatchOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "watch <project-id>",
+		Short:             "Follow a versioned settings sync until it finishes",
+		ValidArgsFunction: completion.Projects(),
+		Long: `Poll the versioned settings status of a project and print each state
+change (loading from VCS, resolving dependencies, running DSL, ...) with a
+timestamp until the sync finishes.
+
+Exits 0 once settings are synchronized, 1 when the sync fails (printing the
+server's message and the offending commit, if reported), and 124 on --timeout.
+
+A sync that has not started yet looks finished. After pushing, pass
+--target-revision to wait until that commit has been applied; earlier
+revisions, including failed ones, are treated as still pending.`,
+		Example: `  teamcity project settings watch MyProject
+  teamcity project settings watch MyProject --target-revision @head
+  teamcity project settings watch MyProject --target-revision 1a2b3c4 --timeout 10m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectSettingsWatch(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.interval, "interval", "i", 3, "Refresh interval in seconds")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Timeout duration (e.g., 10m, 1h)")
+	cmd.Flags().StringVar(&opts.targetRevision, "target-revision", "", "Wait until this commit SHA (or '@head') has been applied")
+
+	_ = cmd.RegisterFlagCompletionFunc("target-revision", completion.AtHead())
+
+	return cmd
+}
+
+// settingsSyncPhase is where a versioned settings sync stands.
+type settingsSyncPhase int
+
+const (
+	settingsSyncing settingsSyncPhase = iota
+	settingsSynced
+	settingsFailed
+)
+
+// settingsRevisionPattern finds the commit a status message refers to, e.g. "... from VCS revision 1a2b3c4 ...".
+var settingsRevisionPattern = regexp.MustCompile(`(?i)\brevision:?\s+([0-9a-f]{7,40})\b`)
+
+// settingsRevision returns the commit SHA mentioned in a status message, or "".
+func settingsRevision(message string) string {
+	if m := settingsRevisionPattern.FindStringSubmatch(message); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return ""
+}
+
+// sameRevision reports whether two SHAs name the same commit, allowing either to be abbreviated.
+func sameRevision(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// settingsSyncState classifies status into a label and phase. Errors win over in-progress messages,
+// so a failure is never mistaken for a sync step; anything else not in progress counts as synchronized.
+func settingsSyncState(status *api.VersionedSettingsStatus) (string, settingsSyncPhase) {
+	if status.Type == "error" {
+		return "error", settingsFailed
+	}
+	if label := getSyncingStatus(status.Message); label != "" {
+		return label, settingsSyncing
+	}
+	if status.Type == "warning" {
+		return "synchronized with warnings", settingsSynced
+	}
+	return "synchronized", settingsSynced
+}
+
+// watchSettingsState applies --target-revision: until the target is reported, a finished sync
+// (successful or not) belongs to an earlier commit and the target is still pending.
+func watchSettingsState(status *api.VersionedSettingsStatus, target string) (label string, phase settingsSyncPhase, revision string) {
+	label, phase = settingsSyncState(status)
+	revision = settingsRevision(status.Message)
+	if target == "" || phase == settingsSyncing || sameRevision(revision, target) {
+		return label, phase, revision
+	}
+	if phase == settingsFailed && revision == "" {
+		return label, phase, revision
+	}
+	return "waiting for " + shortRevision(target), settingsSyncing, revision
+}
+
+func shortRevision(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func settingsPhaseIcon(phase settingsSyncPhase) string {
+	switch phase {
+	case settingsSynced:
+		return output.Green(output.Sym().Check)
+	case settingsFailed:
+		return output.Red(output.Sym().Cross)
+	default:
+		return output.Cyan(output.Sym().Recycle)
+	}
+}
+
+func runProjectSettingsWatch(f *cmdutil.Factory, projectID string, opts *projectSettingsWatchOptions) error {
+	if opts.interval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second, got %d", opts.interval)
+	}
+	target := opts.targetRevision
+	if strings.EqualFold(target, "@head") {
+		if !git.IsRepo() {
+			return errors.New("--target-revision @head requires a git repository")
+		}
+		head, err := git.HeadRevision()
+		if err != nil {
+			return err
+		}
+		target = head
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx := f.Context()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	p := f.Printer
+	var lastLabel string
+	for {
+		status, err := client.GetVersionedSettingsStatus(projectID)
+		if err != nil {
+			return fmt.Errorf("failed to get versioned settings status: %w", err)
+		}
+		label, phase, revision := watchSettingsState(status, target)
+		if label != lastLabel {
+			_, _ = fmt.Fprintf(p.Out, "%s %s %s\n", output.Faint(time.Now().Format("15:04:05")), settingsPhaseIcon(phase), label)
+			lastLabel = label
+		}
+
+		switch phase {
+		case settingsSynced:
+			if revision != "" {
+				_, _ = fmt.Fprintf(p.Out, "%-12s %s\n", output.Faint("Revision"), revision)
+			}
+			if status.Type == "warning" && status.Message != "" {
+				_, _ = fmt.Fprintf(p.Out, "%-12s %s\n", output.Faint("Message"), status.Message)
+			}
+			return nil
+		case settingsFailed:
+			msg := cmp.Or(status.Message, "no details reported by the server")
+			if revision != "" {
+				return fmt.Errorf("settings sync failed at revision %s: %s", revision, msg)
+			}
+			return fmt.Errorf("settings sync failed: %s", msg)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				_, _ = fmt.Fprintf(p.Out, "%s Timeout exceeded\n", output.Red(output.Sym().Cross))
+				return &cmdutil.ExitError{Code: cmdutil.ExitTimeout}
+			}
+			return nil
+		case <-time.After(time.Duration(opts.interval) * time.Second):
+		}
+	}
+}
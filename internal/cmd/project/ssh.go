@@ -72,7 +72,7 @@ func newSSHListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
 	cmdutil.AddWebFlags(cmd, &opts.ViewOptions)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -137,7 +137,7 @@ func newSSHUploadCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Project ID")
 	cmd.Flags().StringVar(&opts.name, "name", "", "Key name (default: filename)")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -203,7 +203,7 @@ func newSSHGenerateCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.keyType, "type", "ed25519", "Key type: ed25519 or rsa")
 
 	_ = cmd.RegisterFlagCompletionFunc("type", completion.SSHKeyTypes())
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -260,7 +260,7 @@ func newSSHDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Project ID")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
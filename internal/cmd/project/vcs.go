@@ -78,7 +78,7 @@ func newVcsListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 100)
 	cmdutil.AddWebFlags(cmd, &opts.ViewOptions)
 
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -316,7 +316,7 @@ Tests the connection before creating unless --no-test is specified.`,
 	cmd.Flags().BoolVar(&opts.noTest, "no-test", false, "Skip connection test before creating")
 
 	_ = cmd.RegisterFlagCompletionFunc("auth", completion.VCSAuthMethods())
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
@@ -21,10 +21,6 @@ var queueActions = map[string]queueAction{
 		"Move a queued run to the top of the queue, giving it highest priority.",
 		"Moved run %s to top of queue",
 		func(c api.ClientInterface, id string) error { return c.MoveQueuedBuildToTop(id) }},
-	"approve": {"approve", "Approve a queued run",
-		"Approve a queued run that requires manual approval before it can run.",
-		"Approved run %s",
-		func(c api.ClientInterface, id string) error { return c.ApproveQueuedBuild(id) }},
 }
 
 func newQueueActionCmd(f *cmdutil.Factory, a queueAction) *cobra.Command {
@@ -51,6 +47,3 @@ func newQueueActionCmd(f *cmdutil.Factory, a queueAction) *cobra.Command {
 func newQueueTopCmd(f *cmdutil.Factory) *cobra.Command {
 	return newQueueActionCmd(f, queueActions["top"])
 }
-func newQueueApproveCmd(f *cmdutil.Factory) *cobra.Command {
-	return newQueueActionCmd(f, queueActions["approve"])
-}
@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func newQueueApproveCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <id>",
+		Short: "Approve a queued run",
+		Long: `Approve a queued run that requires manual approval before it can run.
+
+The run's approval state is checked first, so a run that needs no
+approval, is already approved, timed out or was canceled waiting, or
+that you are not allowed to approve is reported instead of failing at the
+server.`,
+		Args:    cobra.ExactArgs(1),
+		Example: "  teamcity queue approve 12345",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQueueApprove(f, args[0])
+		},
+	}
+}
+
+func runQueueApprove(f *cmdutil.Factory, runID string) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	info, err := client.GetQueuedBuildApprovalInfo(runID)
+	if err != nil {
+		if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+			return api.Validation(
+				fmt.Sprintf("run %s is not waiting for approval", runID),
+				"It may have started already, been removed, or its job has no approval feature; check with: teamcity queue list",
+			)
+		}
+		return fmt.Errorf("failed to get approval state of run %s: %w", runID, err)
+	}
+	switch {
+	case info.Status == "approved":
+		f.Printer.Info("Run %s is already approved", runID)
+		return nil
+	case info.Status == "timedOut":
+		return api.Validation(
+			fmt.Sprintf("approval of run %s timed out", runID),
+			"A run whose approval timed out can no longer be approved; start it again with: teamcity run restart "+runID,
+		)
+	case info.Status == "canceled":
+		return api.Validation(
+			fmt.Sprintf("approval of run %s was canceled", runID),
+			"The run was canceled while waiting; start it again with: teamcity run restart "+runID,
+		)
+	case !info.CanBeApprovedByCurrentUser:
+		return api.Validation(
+			fmt.Sprintf("you cannot approve run %s", runID),
+			"Approval is limited to the users or groups set in the job's approval feature; ask one of them",
+		)
+	}
+
+	if err := client.ApproveQueuedBuild(runID); err != nil {
+		return fmt.Errorf("failed to approve run %s: %w", runID, err)
+	}
+	f.Printer.Success("Approved run %s", runID)
+	return nil
+}
@@ -0,0 +1,219 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type queueDrainOptions struct {
+	job       string
+	project   string
+	olderThan string
+	yes       bool
+	json      bool
+}
+
+func newQueueDrainCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &queueDrainOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Remove queued runs, keeping those running builds depend on",
+		Long: `Remove matching runs from the queue so no new work starts, while
+builds that are already running finish normally.
+
+A running composite build's parts wait in the queue until an agent picks
+them up; removing one would fail the running build. drain keeps every
+queued run that a running build depends on, directly or through other
+queued runs, and reports why it was kept.
+
+Combine with --dry-run to see what would be removed without changing the
+queue. Without a terminal to confirm in, pass --yes.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity queue drain --project Falcon
+  teamcity queue drain --job Falcon_Build --older-than 1h
+  teamcity queue drain --project Falcon --dry-run
+  teamcity queue drain --yes --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQueueDrain(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Only drain runs of this job")
+	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Only drain runs of jobs in this project and its subprojects")
+	cmd.Flags().StringVar(&opts.olderThan, "older-than", "", "Only drain runs queued longer than this (e.g., 30m, 1h, 2d)")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
+
+	return cmd
+}
+
+// drainEntry is one queued run in the drain report.
+type drainEntry struct {
+	ID             int    `json:"id"`
+	BuildTypeID    string `json:"buildTypeId"`
+	BranchName     string `json:"branchName,omitempty"`
+	QueuedDate     string `json:"queuedDate,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	RunningBuildID int    `json:"runningBuildId,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// drainResult is the --json output; under --dry-run, removed lists what would have been removed.
+type drainResult struct {
+	DryRun  bool         `json:"dryRun,omitempty"`
+	Removed []drainEntry `json:"removed"`
+	Kept    []drainEntry `json:"kept"`
+	Failed  []drainEntry `json:"failed,omitempty"`
+}
+
+func runQueueDrain(f *cmdutil.Factory, opts *queueDrainOptions) error {
+	var cutoff time.Time
+	if opts.olderThan != "" {
+		since, err := api.ParseUserDate(opts.olderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+		if cutoff, err = api.ParseTeamCityTime(since); err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+	}
+	if !opts.yes && !f.IsDryRun() && !f.IsInteractive() {
+		return api.Validation("drain needs confirmation, and there is no terminal to ask in",
+			"Add --yes to remove the queued runs without asking, or --dry-run to preview")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	p := f.Printer
+
+	queue, _, err := client.GetBuildQueue(api.QueueOptions{BuildTypeID: opts.job, Project: opts.project})
+	if err != nil {
+		return fmt.Errorf("failed to get build queue: %w", err)
+	}
+	holds, err := client.GetQueueDependencyHolds(f.Context())
+	if err != nil {
+		return fmt.Errorf("failed to check running builds: %w", err)
+	}
+
+	result := drainResult{DryRun: f.IsDryRun(), Removed: []drainEntry{}, Kept: []drainEntry{}}
+	for _, q := range queue.Builds {
+		if !cutoff.IsZero() && !queuedBefore(q, cutoff) {
+			continue
+		}
+		e := drainEntry{ID: q.ID, BuildTypeID: q.BuildTypeID, BranchName: q.BranchName, QueuedDate: q.QueuedDate}
+		if r, ok := holds[q.ID]; ok {
+			e.Reason = fmt.Sprintf("needed by running %d (%s)", r.ID, r.BuildTypeID)
+			e.RunningBuildID = r.ID
+			result.Kept = append(result.Kept, e)
+			continue
+		}
+		result.Removed = append(result.Removed, e)
+	}
+
+	if len(result.Removed)+len(result.Kept) == 0 {
+		if opts.json {
+			return p.PrintJSON(result)
+		}
+		p.Info("No queued runs to drain")
+		return nil
+	}
+
+	if !opts.json {
+		printDrainPlan(p, result)
+	}
+
+	if len(result.Removed) > 0 && !opts.yes && !f.IsDryRun() {
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("Remove %d of %d matching queued runs?", len(result.Removed), len(result.Removed)+len(result.Kept)), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			p.Info("Canceled")
+			return nil
+		}
+	}
+
+	removed := result.Removed[:0]
+	for _, e := range result.Removed {
+		err := client.RemoveFromQueue(strconv.Itoa(e.ID))
+		switch {
+		case err == nil:
+			removed = append(removed, e)
+		case isNotFound(err):
+			// The run started or was removed since the queue was read; nothing left to drain.
+			e.Reason = "no longer queued"
+			result.Kept = append(result.Kept, e)
+		default:
+			e.Error = err.Error()
+			result.Failed = append(result.Failed, e)
+		}
+	}
+	result.Removed = removed
+
+	if opts.json {
+		if err := p.PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, e := range result.Failed {
+			p.Warn("failed to remove #%d from queue: %s", e.ID, e.Error)
+		}
+		p.Success("Drained queue: removed %d, kept %d", len(result.Removed), len(result.Kept))
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("failed to remove %d of %d queued runs", len(result.Failed), len(result.Failed)+len(result.Removed))
+	}
+	return nil
+}
+
+// printDrainPlan lists every matching queued run with whether drain removes or keeps it.
+func printDrainPlan(p *output.Printer, result drainResult) {
+	headers := []string{"ID", "JOB", "BRANCH", "QUEUED", "ACTION"}
+	var rows [][]string
+	row := func(e drainEntry, action string) {
+		branch := e.BranchName
+		if branch == "" {
+			branch = "<default>"
+		}
+		queued := "-"
+		if t, err := api.ParseTeamCityTime(e.QueuedDate); err == nil {
+			queued = output.RelativeTime(t)
+		}
+		rows = append(rows, []string{strconv.Itoa(e.ID), e.BuildTypeID, branch, queued, action})
+	}
+	for _, e := range result.Removed {
+		row(e, output.Red("remove"))
+	}
+	for _, e := range result.Kept {
+		row(e, output.Green("keep")+" "+output.Faint(e.Reason))
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1, 2)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
+
+// queuedBefore reports whether q entered the queue before cutoff; runs with no parsable date are left alone.
+func queuedBefore(q api.QueuedBuild, cutoff time.Time) bool {
+	t, err := api.ParseTeamCityTime(q.QueuedDate)
+	return err == nil && t.Before(cutoff)
+}
+
+func isNotFound(err error) bool {
+	_, ok := errors.AsType[*api.NotFoundError](err)
+	return ok
+}
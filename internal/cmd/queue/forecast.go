@@ -0,0 +1,404 @@
+package queue
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/spf13/cobra"
+)
+
+// forecastHistory is how many finished runs of a job its average duration is taken from.
+const forecastHistory = 10
+
+// forecastWorkers bounds how many per-run and per-job lookups the forecast makes at once.
+const forecastWorkers = 8
+
+type queueForecastOptions struct {
+	job   string
+	limit int
+	json  bool
+}
+
+func newQueueForecastCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &queueForecastOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Estimate which agent each queued run lands on, and when",
+		Long: `Estimate which agent each queued run will likely start on, and when.
+
+The forecast replays the queue in order: each run goes to the compatible
+agent that frees up first. An agent running a build is free once that
+build's expected duration has passed, taken from the average of the job's
+last 10 finished runs (or the build's progress when the job has no
+history); a forecast run then keeps the agent busy for its own job's
+average.
+
+This is an estimate. It does not know about runs triggered later, agents
+connecting or disconnecting, or runs that wait for their dependencies, and
+a run whose job has no duration history leaves the time its agent frees
+up unknown.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity queue forecast
+  teamcity queue forecast --job Falcon_Build
+  teamcity queue forecast --limit 10 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQueueForecast(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Only show runs of this job; runs ahead of them still take agents")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 30, "Number of runs from the front of the queue to forecast (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+// simAgent is an agent in the forecast: free after FreeIn, or at an unknown time when Known is false.
+type simAgent struct {
+	ID     int
+	Name   string
+	FreeIn time.Duration
+	Known  bool
+}
+
+// simRun is a queued run in the forecast; Duration is zero when its job has no history.
+type simRun struct {
+	ID         int
+	Compatible []int
+	Duration   time.Duration
+}
+
+// simSlot is the forecast for one queued run: the agent it lands on (0 when none is compatible),
+// and when it starts, unknown when Known is false.
+type simSlot struct {
+	AgentID  int
+	StartsIn time.Duration
+	Known    bool
+}
+
+// simulate assigns runs in queue order, each to the compatible agent that frees up first; ties go
+// to the agent listed first. agents is left unchanged.
+func simulate(runs []simRun, agents []simAgent) []simSlot {
+	state := slices.Clone(agents)
+	index := make(map[int]int, len(state))
+	for i, a := range state {
+		index[a.ID] = i
+	}
+
+	slots := make([]simSlot, len(runs))
+	for i, r := range runs {
+		best := -1
+		for _, id := range r.Compatible {
+			j, ok := index[id]
+			if !ok {
+				continue
+			}
+			switch {
+			case best < 0:
+				best = j
+			case state[j].Known && !state[best].Known,
+				state[j].Known == state[best].Known && state[j].FreeIn < state[best].FreeIn,
+				state[j].Known == state[best].Known && state[j].FreeIn == state[best].FreeIn && j < best:
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		a := &state[best]
+		slots[i] = simSlot{AgentID: a.ID, StartsIn: a.FreeIn, Known: a.Known}
+		if r.Duration > 0 {
+			a.FreeIn += r.Duration
+		} else {
+			a.Known = false
+		}
+	}
+	return slots
+}
+
+// remaining estimates how long a running build has left: the job's average minus the time it has
+// run, or what its progress implies when the job has no history. A build past its average is due now.
+func remaining(elapsed, average time.Duration, percent int) (time.Duration, bool) {
+	switch {
+	case average > 0:
+		return max(average-elapsed, 0), true
+	case percent > 0 && percent < 100:
+		return elapsed * time.Duration(100-percent) / time.Duration(percent), true
+	case percent >= 100:
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// averageDuration is the mean duration of the runs with both a start and a finish date.
+func averageDuration(builds []api.Build) time.Duration {
+	var total time.Duration
+	n := 0
+	for _, b := range builds {
+		start, err1 := api.ParseTeamCityTime(b.StartDate)
+		finish, err2 := api.ParseTeamCityTime(b.FinishDate)
+		if err1 != nil || err2 != nil || !finish.After(start) {
+			continue
+		}
+		total += finish.Sub(start)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / time.Duration(n)
+}
+
+// forecastAgentRef names the agent a queued run is expected on.
+type forecastAgentRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// forecastEntry is one queued run in the forecast. StartsInSeconds and EstimatedStart are
+// estimates, and are missing when the start time is unknown.
+type forecastEntry struct {
+	Position        int               `json:"position"`
+	ID              int               `json:"id"`
+	BuildTypeID     string            `json:"buildTypeId"`
+	BranchName      string            `json:"branchName,omitempty"`
+	Agent           *forecastAgentRef `json:"agent,omitempty"`
+	StartsInSeconds *int64            `json:"startsInSeconds,omitempty"`
+	EstimatedStart  string            `json:"estimatedStart,omitempty"`
+	Note            string            `json:"note,omitempty"`
+}
+
+// forecastResult is the --json output; estimate is always true, as a reminder to callers.
+type forecastResult struct {
+	Estimate  bool            `json:"estimate"`
+	Runs      []forecastEntry `json:"runs"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+func runQueueForecast(f *cmdutil.Factory, opts *queueForecastOptions) error {
+	if err := cmdutil.CheckLimit(f, opts.limit); err != nil {
+		return err
+	}
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	ctx := f.Context()
+
+	queue, truncated, err := client.GetBuildQueue(api.QueueOptions{Limit: opts.limit})
+	if err != nil {
+		return fmt.Errorf("failed to get build queue: %w", err)
+	}
+	result := forecastResult{Estimate: true, Runs: []forecastEntry{}, Truncated: truncated}
+	if len(queue.Builds) == 0 {
+		if opts.json {
+			return f.Printer.PrintJSON(result)
+		}
+		f.Printer.Empty("No runs in queue", "Nothing is queued; 'teamcity run list --status running' shows what is running")
+		return nil
+	}
+
+	var running *api.BuildList
+	compatible := make([]*api.AgentList, len(queue.Builds))
+	errs := make([]error, len(queue.Builds)+1)
+	sem := make(chan struct{}, forecastWorkers)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		running, _, errs[len(queue.Builds)] = client.GetBuilds(ctx, api.BuildsOptions{
+			State:  "running",
+			Limit:  500,
+			Fields: []string{"id", "buildTypeId", "startDate", "percentageComplete", "agent.id", "agent.name"},
+		})
+	})
+	for i, q := range queue.Builds {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			compatible[i], errs[i] = client.GetBuildCompatibleAgents(q.ID)
+		})
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if i < len(queue.Builds) {
+			return fmt.Errorf("failed to get compatible agents of queued run %d: %w", queue.Builds[i].ID, err)
+		}
+		return fmt.Errorf("failed to get running builds: %w", err)
+	}
+
+	var jobs []string
+	for _, q := range queue.Builds {
+		jobs = append(jobs, q.BuildTypeID)
+	}
+	for _, b := range running.Builds {
+		jobs = append(jobs, b.BuildTypeID)
+	}
+	slices.Sort(jobs)
+	jobs = slices.Compact(jobs)
+	averages, err := jobAverages(f, client, jobs)
+	if err != nil {
+		return err
+	}
+
+	now := timeref.Now()
+	busy := map[int]api.Build{}
+	for _, b := range running.Builds {
+		if b.Agent != nil {
+			busy[b.Agent.ID] = b
+		}
+	}
+	var agents []simAgent
+	seen := map[int]bool{}
+	runs := make([]simRun, len(queue.Builds))
+	for i, q := range queue.Builds {
+		runs[i] = simRun{ID: q.ID, Duration: averages[q.BuildTypeID]}
+		for _, a := range compatible[i].Agents {
+			if !a.Connected || !a.Enabled || !a.Authorized {
+				continue
+			}
+			runs[i].Compatible = append(runs[i].Compatible, a.ID)
+			if seen[a.ID] {
+				continue
+			}
+			seen[a.ID] = true
+			sa := simAgent{ID: a.ID, Name: a.Name, Known: true}
+			if b, ok := busy[a.ID]; ok {
+				elapsed := time.Duration(0)
+				if start, err := api.ParseTeamCityTime(b.StartDate); err == nil {
+					elapsed = now.Sub(start)
+				}
+				sa.FreeIn, sa.Known = remaining(elapsed, averages[b.BuildTypeID], b.PercentageComplete)
+			}
+			agents = append(agents, sa)
+		}
+	}
+	slices.SortStableFunc(agents, func(a, b simAgent) int { return cmp.Compare(a.Name, b.Name) })
+	names := map[int]string{}
+	for _, a := range agents {
+		names[a.ID] = a.Name
+	}
+
+	for i, slot := range simulate(runs, agents) {
+		q := queue.Builds[i]
+		if opts.job != "" && q.BuildTypeID != opts.job {
+			continue
+		}
+		e := forecastEntry{Position: i + 1, ID: q.ID, BuildTypeID: q.BuildTypeID, BranchName: q.BranchName}
+		switch {
+		case slot.AgentID == 0:
+			e.Note = "no compatible agent connected"
+		case !slot.Known:
+			e.Agent = &forecastAgentRef{ID: slot.AgentID, Name: names[slot.AgentID]}
+			e.Note = "start unknown: no duration history for a run ahead of it"
+		default:
+			e.Agent = &forecastAgentRef{ID: slot.AgentID, Name: names[slot.AgentID]}
+			seconds := int64(slot.StartsIn / time.Second)
+			e.StartsInSeconds = &seconds
+			e.EstimatedStart = api.FormatTeamCityTime(now.Add(slot.StartsIn).UTC())
+		}
+		result.Runs = append(result.Runs, e)
+	}
+
+	if opts.json {
+		return f.Printer.PrintJSON(result)
+	}
+	printForecast(f.Printer, result, opts)
+	return nil
+}
+
+// jobAverages fetches each job's recent finished runs, at most forecastWorkers at a time, and returns
+// their average durations; jobs without history are left out.
+func jobAverages(f *cmdutil.Factory, client api.ClientInterface, jobs []string) (map[string]time.Duration, error) {
+	history := make([]*api.BuildList, len(jobs))
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, forecastWorkers)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			history[i], _, errs[i] = client.GetBuilds(f.Context(), api.BuildsOptions{
+				BuildTypeID: job,
+				State:       "finished",
+				Limit:       forecastHistory,
+				Fields:      []string{"id", "startDate", "finishDate"},
+			})
+		})
+	}
+	wg.Wait()
+
+	averages := map[string]time.Duration{}
+	for i, job := range jobs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to get recent runs of %s: %w", job, errs[i])
+		}
+		if d := averageDuration(history[i].Builds); d > 0 {
+			averages[job] = d
+		}
+	}
+	return averages, nil
+}
+
+func printForecast(p *output.Printer, result forecastResult, opts *queueForecastOptions) {
+	if len(result.Runs) == 0 {
+		tip := ""
+		if result.Truncated {
+			tip = "Raise --limit to look further down the queue"
+		}
+		p.Empty("No queued runs of "+opts.job, tip)
+		return
+	}
+
+	headers := []string{"#", "ID", "JOB", "BRANCH", "LIKELY AGENT", "ESTIMATED START"}
+	rows := make([][]string, len(result.Runs))
+	for i, e := range result.Runs {
+		branch := cmp.Or(e.BranchName, "<default>")
+		agent, start := "-", output.Faint("unknown")
+		if e.Agent != nil {
+			agent = e.Agent.Name
+		}
+		switch {
+		case e.Agent == nil:
+			start = output.Red("no compatible agent")
+		case e.StartsInSeconds != nil:
+			start = startsInText(time.Duration(*e.StartsInSeconds) * time.Second)
+		}
+		rows[i] = []string{strconv.Itoa(e.Position), strconv.Itoa(e.ID), e.BuildTypeID, branch, agent, start}
+	}
+	output.AutoSizeColumns(headers, rows, 2, 2, 3)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintln(p.Out, output.Faint(fmt.Sprintf(
+		"Estimates: queue order, first free compatible agent, average of each job's last %d runs", forecastHistory)))
+	if result.Truncated {
+		p.Tip("Only the first %d queued runs were forecast; raise --limit to see more", opts.limit)
+	}
+}
+
+// startsInText phrases an estimated wait, rounded to the minute since the estimate is rough.
+func startsInText(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d <= 0:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("in ~%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("in ~%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(T *testing.T) {
+	T.Parallel()
+	idle := func(id int) simAgent { return simAgent{ID: id, Name: fmt.Sprintf("agent%d", id), Known: true} }
+	busy := func(id int, d time.Duration) simAgent {
+		a := idle(id)
+		a.FreeIn = d
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		runs   []simRun
+		agents []simAgent
+		want   []simSlot
+	}{
+		{
+			name:   "idle agent takes the first run now",
+			runs:   []simRun{{ID: 10, Compatible: []int{1}, Duration: 5 * time.Minute}},
+			agents: []simAgent{idle(1)},
+			want:   []simSlot{{AgentID: 1, StartsIn: 0, Known: true}},
+		},
+		{
+			name: "runs queue up behind each other on one agent",
+			runs: []simRun{
+				{ID: 10, Compatible: []int{1}, Duration: 5 * time.Minute},
+				{ID: 11, Compatible: []int{1}, Duration: 3 * time.Minute},
+				{ID: 12, Compatible: []int{1}, Duration: time.Minute},
+			},
+			agents: []simAgent{busy(1, 2*time.Minute)},
+			want: []simSlot{
+				{AgentID: 1, StartsIn: 2 * time.Minute, Known: true},
+				{AgentID: 1, StartsIn: 7 * time.Minute, Known: true},
+				{AgentID: 1, StartsIn: 10 * time.Minute, Known: true},
+			},
+		},
+		{
+			name: "each run goes to the agent free first",
+			runs: []simRun{
+				{ID: 10, Compatible: []int{1, 2}, Duration: 10 * time.Minute},
+				{ID: 11, Compatible: []int{1, 2}, Duration: 10 * time.Minute},
+				{ID: 12, Compatible: []int{1, 2}, Duration: 10 * time.Minute},
+			},
+			agents: []simAgent{busy(1, 4*time.Minute), busy(2, time.Minute)},
+			want: []simSlot{
+				{AgentID: 2, StartsIn: time.Minute, Known: true},
+				{AgentID: 1, StartsIn: 4 * time.Minute, Known: true},
+				{AgentID: 2, StartsIn: 11 * time.Minute, Known: true},
+			},
+		},
+		{
+			name:   "ties go to the agent listed first",
+			runs:   []simRun{{ID: 10, Compatible: []int{2, 1}, Duration: time.Minute}},
+			agents: []simAgent{idle(1), idle(2)},
+			want:   []simSlot{{AgentID: 1, StartsIn: 0, Known: true}},
+		},
+		{
+			name: "incompatible agents are never picked",
+			runs: []simRun{
+				{ID: 10, Compatible: []int{2}, Duration: time.Minute},
+				{ID: 11, Compatible: []int{3}, Duration: time.Minute},
+			},
+			agents: []simAgent{idle(1), busy(2, 30*time.Minute)},
+			want: []simSlot{
+				{AgentID: 2, StartsIn: 30 * time.Minute, Known: true},
+				{},
+			},
+		},
+		{
+			name: "a run without history makes its agent's next free time unknown",
+			runs: []simRun{
+				{ID: 10, Compatible: []int{1}},
+				{ID: 11, Compatible: []int{1, 2}, Duration: time.Minute},
+				{ID: 12, Compatible: []int{1}, Duration: time.Minute},
+			},
+			agents: []simAgent{idle(1), busy(2, time.Hour)},
+			want: []simSlot{
+				{AgentID: 1, StartsIn: 0, Known: true},
+				{AgentID: 2, StartsIn: time.Hour, Known: true},
+				{AgentID: 1, Known: false},
+			},
+		},
+		{
+			name:   "an agent with an unknown finish is used only when nothing else fits",
+			runs:   []simRun{{ID: 10, Compatible: []int{1}, Duration: time.Minute}},
+			agents: []simAgent{{ID: 1, Name: "agent1"}},
+			want:   []simSlot{{AgentID: 1, Known: false}},
+		},
+	}
+
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			before := append([]simAgent(nil), tc.agents...)
+			assert.Equal(t, tc.want, simulate(tc.runs, tc.agents))
+			assert.Equal(t, before, tc.agents, "agents must not change")
+		})
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	t.Parallel()
+
+	d, ok := remaining(4*time.Minute, 10*time.Minute, 90)
+	assert.True(t, ok)
+	assert.Equal(t, 6*time.Minute, d, "the job's average wins over progress")
+
+	d, ok = remaining(15*time.Minute, 10*time.Minute, 0)
+	assert.True(t, ok)
+	assert.Zero(t, d, "a build past its average is due now")
+
+	d, ok = remaining(3*time.Minute, 0, 25)
+	assert.True(t, ok)
+	assert.Equal(t, 9*time.Minute, d)
+
+	_, ok = remaining(3*time.Minute, 0, 0)
+	assert.False(t, ok)
+}
+
+func TestAverageDuration(t *testing.T) {
+	t.Parallel()
+	builds := []api.Build{
+		{StartDate: "20260301T120000+0000", FinishDate: "20260301T121000+0000"},
+		{StartDate: "20260301T130000+0000", FinishDate: "20260301T132000+0000"},
+		{StartDate: "20260301T140000+0000"},
+		{StartDate: "bad", FinishDate: "20260301T150000+0000"},
+	}
+	assert.Equal(t, 15*time.Minute, averageDuration(builds))
+	assert.Zero(t, averageDuration(nil))
+}
+
+func TestStartsInText(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "now", startsInText(20*time.Second))
+	assert.Equal(t, "in ~1m", startsInText(50*time.Second))
+	assert.Equal(t, "in ~45m", startsInText(45*time.Minute))
+	assert.Equal(t, "in ~2h 5m", startsInText(2*time.Hour+5*time.Minute))
+}
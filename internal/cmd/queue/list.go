@@ -1,6 +1,8 @@
 package queue
 
 import (
+	"cmp"
+	"slices"
 	"strconv"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -21,11 +23,18 @@ func newQueueListCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &queueListOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List queued runs",
+		Use:   "list",
+		Short: "List queued runs",
+		Long: `List queued runs in queue order, the order they are due to start in.
+
+POS is a run's place in the whole queue, the position 'teamcity queue move'
+takes; it is left out with --job, which lists only part of the queue. Use
+--sort to order by a column instead; --desc alone lists the queue back to
+front. With --plain --no-header, the first column is the run ID.`,
 		Aliases: []string{"ls"},
 		Example: `  teamcity queue list
   teamcity queue list --job Falcon_Build
+  teamcity queue list --sort job,queued
   teamcity queue list --json
   teamcity queue list --json=id,state,webUrl
   teamcity queue list --plain
@@ -40,20 +49,35 @@ func newQueueListCmd(f *cmdutil.Factory) *cobra.Command {
 			if done, err := opts.EmitListWebURL(f.Printer, config.ResolveServerURL(), "/queue.html"); done {
 				return err
 			}
+			if err := cmdutil.ValidateSort(&opts.SortFlags, queueSortColumns); err != nil {
+				return err
+			}
 			return cmdutil.RunList(f, cmd, &opts.ListFlags, &api.QueuedBuildFields, opts.fetch)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Filter by job ID")
 	cmdutil.AddListFlags(cmd, &opts.ListFlags, 30)
+	cmdutil.AddSortFlags(cmd, &opts.SortFlags, queueSortColumns, cmdutil.SortNone)
 	cmdutil.AddWebFlags(cmd, &opts.ViewOptions)
 
-	_ = cmd.RegisterFlagCompletionFunc("job", completion.LinkedJobs())
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
 
 	return cmd
 }
 
+var queueSortColumns = []cmdutil.SortColumn[api.QueuedBuild]{
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b api.QueuedBuild) int { return cmp.Compare(a.ID, b.ID) }},
+	{Name: "job", Fields: []string{"buildTypeId"}, Compare: func(a, b api.QueuedBuild) int { return cmdutil.CompareFold(a.BuildTypeID, b.BuildTypeID) }},
+	{Name: "branch", Fields: []string{"branchName"}, Compare: func(a, b api.QueuedBuild) int { return cmdutil.CompareFold(a.BranchName, b.BranchName) }},
+	{Name: "state", Fields: []string{"state"}, Compare: func(a, b api.QueuedBuild) int { return cmdutil.CompareFold(a.State, b.State) }},
+	{Name: "queued", Fields: []string{"queuedDate"}, Compare: func(a, b api.QueuedBuild) int { return cmdutil.CompareTimes(a.QueuedDate, b.QueuedDate) }},
+}
+
 func (opts *queueListOptions) fetch(client api.ClientInterface, fields []string) (*cmdutil.ListResult, error) {
+	if err := cmdutil.ValidateSortFields(&opts.SortFlags, queueSortColumns, fields); err != nil {
+		return nil, err
+	}
 	queue, truncated, err := client.GetBuildQueue(api.QueueOptions{
 		BuildTypeID: opts.job,
 		Limit:       opts.Limit,
@@ -62,8 +86,22 @@ func (opts *queueListOptions) fetch(client api.ClientInterface, fields []string)
 	if err != nil {
 		return nil, err
 	}
+	positions := make(map[int]int, len(queue.Builds))
+	for i, r := range queue.Builds {
+		positions[r.ID] = i + 1
+	}
+	if err := cmdutil.SortItems(queue.Builds, &opts.SortFlags, queueSortColumns); err != nil {
+		return nil, err
+	}
 
-	headers := []string{"ID", "JOB", "BRANCH", "STATE", "WAIT REASON"}
+	// Filtered by job, the list is a slice of the queue and the index is no position in it.
+	showPos := opts.job == ""
+	headers := []string{"ID", "POS", "JOB", "BRANCH", "STATE", "QUEUED", "WAIT REASON"}
+	flexCols := []int{2, 3, 6}
+	if !showPos {
+		headers = slices.Delete(headers, 1, 2)
+		flexCols = []int{1, 2, 5}
+	}
 	var rows [][]string
 
 	for _, r := range queue.Builds {
@@ -77,18 +115,27 @@ func (opts *queueListOptions) fetch(client api.ClientInterface, fields []string)
 			waitReason = "-"
 		}
 
-		rows = append(rows, []string{
-			strconv.Itoa(r.ID),
-			r.BuildTypeID,
+		queued := "-"
+		if t, err := api.ParseTeamCityTime(r.QueuedDate); err == nil {
+			queued = output.RelativeTime(t)
+		}
+
+		row := []string{strconv.Itoa(r.ID)}
+		if showPos {
+			row = append(row, strconv.Itoa(positions[r.ID]))
+		}
+		rows = append(rows, append(row,
+			cmp.Or(r.BuildTypeID, "-"),
 			branch,
 			r.State,
+			queued,
 			waitReason,
-		})
+		))
 	}
 
 	return &cmdutil.ListResult{
 		JSON:      queue,
-		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: []int{1, 2, 4}},
+		Table:     cmdutil.ListTable{Headers: headers, Rows: rows, FlexCols: flexCols},
 		EmptyMsg:  "No runs in queue",
 		EmptyTip:  output.TipNoQueue,
 		Truncated: truncated,
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func newQueueMoveCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "move <id> <position>",
+		Short: "Move a run to a position in the queue",
+		Long: `Move a queued run to a position in the queue, counting from 1 at the top.
+
+'teamcity queue list' shows each run's position in the POS column.
+Position 1 is the same as 'teamcity queue top'.`,
+		Args: cobra.ExactArgs(2),
+		Example: `  teamcity queue move 12345 3
+  teamcity queue move 12345 1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQueueMove(f, args[0], args[1])
+		},
+	}
+}
+
+func runQueueMove(f *cmdutil.Factory, runID, position string) error {
+	pos, err := strconv.Atoi(position)
+	if err != nil || pos < 1 {
+		return api.Validation(
+			fmt.Sprintf("invalid position %q", position),
+			"Pass a position counting from 1 at the top of the queue, e.g. teamcity queue move "+runID+" 3",
+		)
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	// The server counts positions from 0.
+	if err := client.SetQueuedBuildPosition(runID, pos-1); err != nil {
+		return fmt.Errorf("failed to move run: %w", err)
+	}
+	f.Printer.Success("Moved run %s to position %d in the queue", runID, pos)
+	return nil
+}
@@ -23,7 +23,10 @@ See: https://www.jetbrains.com/help/teamcity/build-queue.html`,
 	cmd.AddCommand(newQueueListCmd(f))
 	cmd.AddCommand(newQueueRemoveCmd(f))
 	cmd.AddCommand(newQueueTopCmd(f))
+	cmd.AddCommand(newQueueMoveCmd(f))
 	cmd.AddCommand(newQueueApproveCmd(f))
+	cmd.AddCommand(newQueueDrainCmd(f))
+	cmd.AddCommand(newQueueForecastCmd(f))
 
 	return cmd
 }
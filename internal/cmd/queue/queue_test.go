@@ -1,11 +1,16 @@
 package queue_test
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
@@ -54,6 +59,27 @@ func TestQueueList_waitReason(t *testing.T) {
 	assert.Contains(t, got, "WAIT REASON")
 }
 
+func TestQueueList_positions(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	queued := api.FormatTeamCityTime(time.Now().Add(-3 * time.Hour))
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: 2, Builds: []api.QueuedBuild{
+			{ID: 201, BuildTypeID: "Falcon_Test", State: "queued", QueuedDate: queued},
+			{ID: 200, BuildTypeID: "Falcon_Build", State: "queued"},
+		}})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "list", "--sort", "id", "--plain", "--no-header")
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"200", "2", "Falcon_Build", "<default>", "queued", "-", "-"}, strings.Fields(lines[0]),
+		"positions are the queue order, whatever the sort")
+	assert.Equal(t, []string{"201", "1", "Falcon_Test", "<default>", "queued", "3h", "ago", "-"}, strings.Fields(lines[1]))
+
+	got = cmdtest.CaptureOutput(t, ts.Factory, "queue", "list", "--job", "Falcon_Test", "--plain")
+	assert.NotContains(t, got, "POS", "a filtered list has no queue positions")
+}
+
 func TestQueueListWeb(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 
@@ -81,3 +107,277 @@ func TestQueueTop(T *testing.T) {
 
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "queue", "top", "100")
 }
+
+func TestQueueMove(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var body string
+	ts.Handle("PUT /app/rest/buildQueue/order/100", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "move", "100", "3")
+	assert.Contains(t, got, "Moved run 100 to position 3 in the queue")
+	assert.Equal(t, "2", body, "the server counts from 0")
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `invalid position "0"`, "queue", "move", "100", "0")
+}
+
+func TestQueueApprove(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		info    api.ApprovalInfo
+		want    string
+		wantErr string
+	}{
+		{name: "waiting", info: api.ApprovalInfo{Status: "waitingForApproval", CanBeApprovedByCurrentUser: true}, want: "Approved run 100"},
+		{name: "already approved", info: api.ApprovalInfo{Status: "approved"}, want: "Run 100 is already approved"},
+		{name: "not allowed", info: api.ApprovalInfo{Status: "waitingForApproval"}, wantErr: "you cannot approve run 100"},
+		{name: "timed out", info: api.ApprovalInfo{Status: "timedOut", CanBeApprovedByCurrentUser: true}, wantErr: "approval of run 100 timed out"},
+		{name: "canceled", info: api.ApprovalInfo{Status: "canceled"}, wantErr: "approval of run 100 was canceled"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := cmdtest.SetupMockClient(t)
+			approved := false
+			ts.Handle("GET /app/rest/buildQueue/id:100/approval", func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.JSON(w, tc.info)
+			})
+			ts.Handle("PUT /app/rest/buildQueue/id:100/approval/status", func(w http.ResponseWriter, r *http.Request) {
+				approved = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			if tc.wantErr != "" {
+				cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, tc.wantErr, "queue", "approve", "100")
+				assert.False(t, approved)
+				return
+			}
+			assert.Contains(t, cmdtest.CaptureOutput(t, ts.Factory, "queue", "approve", "100"), tc.want)
+			assert.Equal(t, tc.info.Status != "approved", approved)
+		})
+	}
+}
+
+// installDrainHandlers queues 200-202 and runs composite 50, which waits on queued 201 and, through it, 202.
+func installDrainHandlers(ts *cmdtest.TestServer) *[]string {
+	old := api.FormatTeamCityTime(time.Now().Add(-2 * time.Hour))
+	recent := api.FormatTeamCityTime(time.Now().Add(-5 * time.Minute))
+	deps := func(id int) *api.BuildList { return &api.BuildList{Count: 1, Builds: []api.Build{{ID: id}}} }
+
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: 3, Builds: []api.QueuedBuild{
+			{ID: 200, BuildTypeID: "Falcon_Build", State: "queued", QueuedDate: recent},
+			{ID: 201, BuildTypeID: "Falcon_Test", State: "queued", QueuedDate: old, SnapshotDependencies: deps(202)},
+			{ID: 202, BuildTypeID: "Falcon_Compile", State: "queued", QueuedDate: old},
+		}})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildList{Count: 1, Builds: []api.Build{
+			{ID: 50, BuildTypeID: "Falcon_Composite", State: "running", SnapshotDependencies: deps(201)},
+		}})
+	})
+	var removed []string
+	ts.Handle("DELETE /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+		removed = append(removed, strings.TrimPrefix(r.URL.Path, "/app/rest/buildQueue/id:"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return &removed
+}
+
+func TestQueueDrain(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	removed := installDrainHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "drain", "--yes")
+	assert.Equal(t, []string{"200"}, *removed)
+	assert.Regexp(t, `200\s+Falcon_Build.*remove`, got)
+	assert.Regexp(t, `201\s+Falcon_Test.*keep needed by running 50 \(Falcon_Composite\)`, got)
+	assert.Regexp(t, `202\s+Falcon_Compile.*keep needed by running 50`, got)
+	assert.Contains(t, got, "Drained queue: removed 1, kept 2")
+}
+
+func TestQueueDrain_olderThan(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	removed := installDrainHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "drain", "--older-than", "1h", "--yes")
+	assert.Empty(t, *removed)
+	assert.NotContains(t, got, "Falcon_Build")
+	assert.Contains(t, got, "Drained queue: removed 0, kept 2")
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "invalid --older-than", "queue", "drain", "--older-than", "soon")
+}
+
+func TestQueueDrain_dryRun(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	removed := installDrainHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "drain", "--dry-run")
+	assert.Empty(t, *removed)
+	assert.Regexp(t, `200\s+Falcon_Build.*remove`, got)
+	assert.Contains(t, got, "[dry-run] Would send DELETE /app/rest/buildQueue/id:200")
+	assert.NotContains(t, got, "id:201")
+	assert.NotContains(t, got, "Drained queue")
+}
+
+func TestQueueDrain_JSON(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installDrainHandlers(ts)
+
+	var result struct {
+		Removed []struct{ ID int }
+		Kept    []struct {
+			ID             int
+			Reason         string
+			RunningBuildID int `json:"runningBuildId"`
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "queue", "drain", "--yes", "--json")), &result))
+	require.Len(t, result.Removed, 1)
+	assert.Equal(t, 200, result.Removed[0].ID)
+	require.Len(t, result.Kept, 2)
+	assert.Equal(t, 50, result.Kept[0].RunningBuildID)
+}
+
+func TestQueueDrain_nonInteractive(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	removed := installDrainHandlers(ts)
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "drain needs confirmation, and there is no terminal to ask in", "queue", "drain")
+	assert.Empty(t, *removed, "nothing is removed without --yes")
+}
+
+func TestQueueDrain_empty(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "drain", "--yes")
+	assert.Equal(t, "No queued runs to drain\n", got)
+}
+
+// installForecastHandlers serves a queue of three runs: 300 and 301 fit agents 1 and 2, 302 fits none.
+// Agent 1 is halfway through a 20-minute Falcon_Build; agent 2 is idle.
+func installForecastHandlers(ts *cmdtest.TestServer) {
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: 3, Builds: []api.QueuedBuild{
+			{ID: 300, BuildTypeID: "Falcon_Build", BranchName: "main"},
+			{ID: 301, BuildTypeID: "Falcon_Test"},
+			{ID: 302, BuildTypeID: "Falcon_Windows"},
+		}})
+	})
+	ts.Handle("GET /app/rest/agents", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("locator"), "id:302") {
+			cmdtest.JSON(w, api.AgentList{Agents: []api.Agent{{ID: 3, Name: "win-1", Connected: false, Enabled: true, Authorized: true}}})
+			return
+		}
+		cmdtest.JSON(w, api.AgentList{Count: 2, Agents: []api.Agent{
+			{ID: 1, Name: "linux-1", Connected: true, Enabled: true, Authorized: true},
+			{ID: 2, Name: "linux-2", Connected: true, Enabled: true, Authorized: true},
+		}})
+	})
+	started := api.FormatTeamCityTime(time.Now().Add(-10 * time.Minute).UTC())
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		switch {
+		case strings.Contains(locator, "state:running"):
+			cmdtest.JSON(w, api.BuildList{Builds: []api.Build{
+				{ID: 90, BuildTypeID: "Falcon_Build", StartDate: started, Agent: &api.Agent{ID: 1, Name: "linux-1"}},
+			}})
+		case strings.Contains(locator, "buildType:Falcon_Build"):
+			cmdtest.JSON(w, api.BuildList{Builds: []api.Build{
+				{ID: 80, StartDate: "20260301T120000+0000", FinishDate: "20260301T122000+0000"},
+			}})
+		case strings.Contains(locator, "buildType:Falcon_Test"):
+			cmdtest.JSON(w, api.BuildList{Builds: []api.Build{
+				{ID: 81, StartDate: "20260301T120000+0000", FinishDate: "20260301T123000+0000"},
+			}})
+		default:
+			cmdtest.JSON(w, api.BuildList{})
+		}
+	})
+}
+
+func TestQueueForecast(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installForecastHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "forecast")
+	assert.Regexp(t, `1\s+300\s+Falcon_Build\s+main\s+linux-2\s+now`, got)
+	assert.Regexp(t, `2\s+301\s+Falcon_Test\s+<default>\s+linux-1\s+in ~10m`, got)
+	assert.Regexp(t, `3\s+302\s+Falcon_Windows\s+<default>\s+-\s+no compatible agent`, got)
+	assert.Contains(t, got, "Estimates:")
+
+	got = cmdtest.CaptureOutput(t, ts.Factory, "queue", "forecast", "--job", "Falcon_Test")
+	assert.Contains(t, got, "301")
+	assert.NotContains(t, got, "Falcon_Build")
+}
+
+func TestQueueForecast_JSON(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installForecastHandlers(ts)
+
+	var result struct {
+		Estimate bool
+		Runs     []struct {
+			Position        int
+			ID              int
+			Agent           *struct{ Name string }
+			StartsInSeconds *int64
+			EstimatedStart  string
+			Note            string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "queue", "forecast", "--json")), &result))
+	assert.True(t, result.Estimate)
+	require.Len(t, result.Runs, 3)
+	assert.Equal(t, "linux-2", result.Runs[0].Agent.Name)
+	require.NotNil(t, result.Runs[1].StartsInSeconds)
+	assert.InDelta(t, 600, *result.Runs[1].StartsInSeconds, 5)
+	assert.NotEmpty(t, result.Runs[1].EstimatedStart)
+	assert.Nil(t, result.Runs[2].Agent)
+	assert.Equal(t, "no compatible agent connected", result.Runs[2].Note)
+}
+
+func TestQueueForecast_boundedLookups(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	builds := make([]api.QueuedBuild, 40)
+	for i := range builds {
+		builds[i] = api.QueuedBuild{ID: 400 + i, BuildTypeID: "Falcon_Build"}
+	}
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: len(builds), Builds: builds})
+	})
+	var inFlight, peak atomic.Int32
+	ts.Handle("GET /app/rest/agents", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		cmdtest.JSON(w, api.AgentList{Agents: []api.Agent{{ID: 1, Name: "linux-1", Connected: true, Enabled: true, Authorized: true}}})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildList{})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "forecast", "--limit", "0")
+	assert.Contains(t, got, "439")
+	assert.LessOrEqual(t, peak.Load(), int32(8), "compatible-agent lookups must run through a bounded pool")
+}
+
+func TestQueueForecast_negativeLimit(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "--limit must not be negative", "queue", "forecast", "--limit", "-1")
+}
+
+func TestQueueForecast_empty(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "queue", "forecast")
+	assert.Contains(t, got, "No runs in queue")
+}
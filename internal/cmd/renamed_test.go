@@ -0,0 +1,78 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRenamedJobView runs 'job view Falcon_Build' against a server where that ID is gone and a search by
+// its name finds candidates, returning the error after rename suggestions.
+func runRenamedJobView(t *testing.T, candidates ...string) error {
+	t.Helper()
+	ts := cmdtest.NewTestServer(t)
+	ts.Handle("GET /app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Server{VersionMajor: 2025, VersionMinor: 7, BuildNumber: "197398"})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:Falcon_Build", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusNotFound, "No build types found by locator 'Falcon_Build'.")
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("locator"), "name:(value:Build,ignoreCase:true)")
+		list := api.BuildTypeList{}
+		for _, id := range candidates {
+			list.BuildTypes = append(list.BuildTypes, api.BuildType{ID: id, Name: "Build"})
+		}
+		cmdtest.JSON(w, list)
+	})
+	config.SetUserForServer(ts.URL, "admin")
+
+	var buf bytes.Buffer
+	ts.Factory.Printer = &output.Printer{Out: &buf, ErrOut: &buf}
+	root := cmd.NewCommand(ts.Factory)
+	root.SetArgs([]string{"job", "view", "Falcon_Build"})
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	err := root.Execute()
+	require.Error(t, err)
+	return cmdutil.SuggestRenamedJob(ts.Factory, err)
+}
+
+func TestSuggestRenamedJob(T *testing.T) {
+	T.Run("no candidates", func(T *testing.T) {
+		err := runRenamedJobView(T)
+		_, ok := errors.AsType[*cmdutil.RenamedJobError](err)
+		assert.False(T, ok)
+		assert.Contains(T, err.Error(), `job "Falcon_Build" not found`)
+	})
+
+	T.Run("one candidate", func(T *testing.T) {
+		err := runRenamedJobView(T, "Falcon_Backend_Build")
+		renamed, ok := errors.AsType[*cmdutil.RenamedJobError](err)
+		require.True(T, ok)
+		assert.Equal(T, "Falcon_Backend_Build", renamed.NewID())
+		assert.Equal(T, "job 'Falcon_Build' was not found; did you mean 'Falcon_Backend_Build' (renamed)?", err.Error())
+		assert.Contains(T, output.RenderError(err).Error(), "--follow-renames")
+		_, ok = errors.AsType[*api.NotFoundError](err)
+		assert.True(T, ok, "still a not-found error")
+	})
+
+	T.Run("many candidates", func(T *testing.T) {
+		err := runRenamedJobView(T, "Falcon_Backend_Build", "Hawk_Build")
+		renamed, ok := errors.AsType[*cmdutil.RenamedJobError](err)
+		require.True(T, ok)
+		assert.Empty(T, renamed.NewID())
+		assert.Contains(T, err.Error(), `job "Falcon_Build" not found`)
+		assert.Contains(T, output.RenderError(err).Error(), "one of: Falcon_Backend_Build, Hawk_Build")
+	})
+}
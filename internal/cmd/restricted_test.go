@@ -0,0 +1,144 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmd"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupSparseServer serves every entity with only its ID, as a server does when per-project permissions
+// hide the rest: no job, trigger, agent, branch, dates, or nested lists.
+func setupSparseServer(t *testing.T) *cmdtest.TestServer {
+	t.Helper()
+	ts := cmdtest.NewTestServer(t)
+
+	ts.Handle("GET /", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, struct{}{})
+	})
+	ts.Handle("GET /app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Server{VersionMajor: 2025, VersionMinor: 7, BuildNumber: "197398"})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{{ID: 1}, {ID: 2}}})
+	})
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1})
+	})
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: 1, Builds: []api.QueuedBuild{{ID: 3}}})
+	})
+	ts.Handle("GET /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.QueuedBuild{ID: 3})
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 1, BuildTypes: []api.BuildType{{ID: "Falcon_Build"}}})
+	})
+	ts.Handle("GET /app/rest/buildTypes/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildType{ID: "Falcon_Build"})
+	})
+	ts.Handle("GET /app/rest/projects", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProjectList{Count: 1, Projects: []api.Project{{ID: "Falcon"}}})
+	})
+	ts.Handle("GET /app/rest/projects/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Project{ID: "Falcon"})
+	})
+	ts.Handle("GET /app/rest/agents", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.AgentList{Count: 1, Agents: []api.Agent{{ID: 1}}})
+	})
+	ts.Handle("GET /app/rest/agents/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Agent{ID: 1})
+	})
+	ts.Handle("GET /app/rest/agents/id:1/compatibleBuildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 1, BuildTypes: []api.BuildType{{ID: "Falcon_Build"}}})
+	})
+	ts.Handle("GET /app/rest/agentPools", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.PoolList{Count: 1, Pools: []api.Pool{{ID: 1}}})
+	})
+	ts.Handle("GET /app/rest/agentPools/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Pool{ID: 1})
+	})
+	ts.Handle("GET /app/rest/changes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ChangeList{Count: 1, Change: []api.Change{{ID: 1}}})
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TestOccurrences{Count: 1, TestOccurrence: []api.TestOccurrence{{ID: "1"}}})
+	})
+	ts.Handle("GET /app/rest/problemOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProblemOccurrences{Count: 1, ProblemOccurrence: []api.ProblemOccurrence{{ID: "1"}}})
+	})
+	ts.Handle("GET /app/rest/vcs-roots", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.VcsRootList{Count: 1, VcsRoot: []api.VcsRoot{{ID: "Falcon_Git"}}})
+	})
+	ts.Handle("GET /app/rest/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.PipelineList{Count: 1, Pipelines: []api.Pipeline{{ID: "Falcon_CI"}}})
+	})
+
+	config.SetUserForServer(ts.URL, "admin")
+	return ts
+}
+
+// sparseCommands are the list and view commands rendered against setupSparseServer.
+var sparseCommands = [][]string{
+	{"run", "list"},
+	{"run", "list", "--plain"},
+	{"run", "view", "1"},
+	{"run", "view", "3"},
+	{"run", "changes", "1"},
+	{"run", "tests", "1"},
+	{"run", "tree", "1"},
+	{"run", "diff", "1", "2"},
+	{"run", "params", "1"},
+	{"run", "artifacts", "1"},
+	{"queue", "list"},
+	{"job", "list"},
+	{"job", "view", "Falcon_Build"},
+	{"project", "list"},
+	{"project", "view", "Falcon"},
+	{"project", "vcs", "list"},
+	{"agent", "list"},
+	{"agent", "view", "1"},
+	{"agent", "jobs", "1"},
+	{"pool", "list"},
+	{"pool", "view", "1"},
+	{"pipeline", "list"},
+}
+
+func TestRenderSparseResponses(T *testing.T) {
+	ts := setupSparseServer(T)
+
+	for _, args := range sparseCommands {
+		T.Run(strings.Join(args, " "), func(T *testing.T) {
+			var buf bytes.Buffer
+			ts.Factory.Printer = &output.Printer{Out: &buf, ErrOut: &buf}
+			root := cmd.NewCommand(ts.Factory)
+			root.SetArgs(args)
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic: %v", r)
+					}
+				}()
+				return root.Execute()
+			}()
+
+			// Some commands rightly refuse to work without the hidden fields; they must not crash.
+			if err != nil {
+				assert.NotContains(T, err.Error(), "panic", buf.String())
+			}
+			assert.NotContains(T, buf.String(), "<nil>")
+			assert.NotContains(T, buf.String(), "%!")
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -12,20 +13,30 @@ import (
 	"github.com/JetBrains/teamcity-cli/internal/cmd/alias"
 	apicmd "github.com/JetBrains/teamcity-cli/internal/cmd/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/auth"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/batch"
+	cachecmd "github.com/JetBrains/teamcity-cli/internal/cmd/cache"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/change"
 	configcmd "github.com/JetBrains/teamcity-cli/internal/cmd/config"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/examples"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/job"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/link"
 	migratecmd "github.com/JetBrains/teamcity-cli/internal/cmd/migrate"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/pipeline"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/plugins"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/pool"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/project"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/queue"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/run"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/skill"
+	"github.com/JetBrains/teamcity-cli/internal/cmd/tag"
+	testcmd "github.com/JetBrains/teamcity-cli/internal/cmd/test"
 	updatecmd "github.com/JetBrains/teamcity-cli/internal/cmd/update"
+	versioncmd "github.com/JetBrains/teamcity-cli/internal/cmd/version"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/plugin"
 	"github.com/JetBrains/teamcity-cli/internal/update"
 	"github.com/JetBrains/teamcity-cli/internal/version"
 	"github.com/spf13/cobra"
@@ -61,6 +72,7 @@ Report issues:  https://jb.gg/tc/issues`,
 			_, _ = fmt.Fprintln(out, "  run start <job>         Trigger a new run")
 			_, _ = fmt.Fprintln(out, "  run view <id>           View run details")
 			_, _ = fmt.Fprintln(out, "  job list                List jobs")
+			_, _ = fmt.Fprintln(out, "  examples                Runnable recipes for common workflows")
 			_, _ = fmt.Fprintln(out)
 			_, _ = fmt.Fprintln(out, output.Faint("Run 'teamcity -h' for full command list, or 'teamcity <command> -h' for details"))
 		},
@@ -76,17 +88,33 @@ Report issues:  https://jb.gg/tc/issues`,
 		&cobra.Group{ID: "misc", Title: "ADDITIONAL COMMANDS"},
 	)
 
-	cmd.PersistentFlags().BoolVar(&f.NoColor, "no-color", false, "Disable colored output")
+	cmd.PersistentFlags().StringVar(&f.Color, "color", cmdutil.ColorAuto, "Colorize output: auto (when stdout is a terminal), always, or never")
+	cmd.PersistentFlags().BoolVar(&f.NoColor, "no-color", false, "Disable colored output (same as --color never)")
 	cmd.PersistentFlags().BoolVarP(&f.Quiet, "quiet", "q", false, "Suppress non-essential output")
 	cmd.PersistentFlags().BoolVarP(&f.Verbose, "verbose", "V", false, "Show detailed output including debug info")
 	cmd.PersistentFlags().BoolVar(&f.Verbose, "debug", false, "Alias for --verbose")
 	cmd.PersistentFlags().BoolVar(&f.NoInput, "no-input", false, "Disable interactive prompts")
+	cmd.PersistentFlags().BoolVar(&f.DryRun, "dry-run", false, "Print mutating API calls instead of sending them (or set TC_DRY_RUN=1)")
+	cmd.PersistentFlags().Float64Var(&f.MaxRPS, "max-rps", 0, "Cap API requests per second, 0 for unlimited (or set TEAMCITY_MAX_RPS)")
+	cmd.PersistentFlags().DurationVar(&f.Timeout, "timeout", 0, "Give up on the command, and any API request in flight, after this long (e.g. 30s, 5m)")
+	cmd.PersistentFlags().IntVar(&f.Retries, "retries", int(api.ReadRetry.MaxRetries), "Retry failed read requests this many times on 429, 5xx, or network errors, 0 to disable (or set TEAMCITY_RETRIES)")
+	cmd.PersistentFlags().DurationVar(&f.Cache, "cache", 0, "Answer API reads from responses cached within this long (e.g. 60s), 0 to always ask the server; see 'teamcity cache'")
+	cmd.PersistentFlags().StringVar(&f.Server, "server", "", "Use this configured server, by URL or context label, instead of the default for this command")
+	cmd.PersistentFlags().BoolVar(&f.FollowRenames, "follow-renames", false, "Continue with a job's new ID when a job reference was renamed")
+	cmd.PersistentFlags().StringVar(&f.RecordPath, "record", "", "Record sanitized API traffic to a cassette file for tests (or set TC_RECORD)")
+	_ = cmd.PersistentFlags().MarkHidden("record")
 
 	cmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
 	cmd.MarkFlagsMutuallyExclusive("quiet", "debug")
+	cmd.MarkFlagsMutuallyExclusive("color", "no-color")
 
-	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
-		f.InitOutput()
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := f.InitOutput(); err != nil {
+			return err
+		}
+		if err := applyRequestFlags(cmd, f); err != nil {
+			return err
+		}
 		output.StartSpinner(f.Quiet)
 		if jsonOutputEnabled(cmd) {
 			f.JSONOutput = true
@@ -95,20 +123,29 @@ Report issues:  https://jb.gg/tc/issues`,
 			f.UpdateNotice = update.CheckInBackground(f.Context(), f.Printer.ErrOut, f.Quiet)
 		}
 		setupAnalytics(f)
+		return nil
 	}
+	_ = cmd.RegisterFlagCompletionFunc("server", completion.ConfiguredServers())
+	_ = cmd.RegisterFlagCompletionFunc("color", completion.Fixed(cmdutil.ColorAuto, cmdutil.ColorAlways, cmdutil.ColorNever))
 
-	addGrouped(cmd, "core", run.NewCmd(f), job.NewCmd(f), project.NewCmd(f), pipeline.NewCmd(f), migratecmd.NewCmd(f))
+	addGrouped(cmd, "core", run.NewCmd(f), job.NewCmd(f), project.NewCmd(f), pipeline.NewCmd(f), testcmd.NewCmd(f), tag.NewCmd(f), change.NewCmd(f), migratecmd.NewCmd(f))
 	addGrouped(cmd, "infra", queue.NewCmd(f), agent.NewCmd(f), pool.NewCmd(f))
 	addGrouped(cmd, "config",
 		auth.NewCmd(f),
 		configcmd.NewCmd(f),
+		cachecmd.NewCmd(f),
 		link.NewCmd(f),
 		alias.NewCmd(f),
+		plugins.NewCmd(f),
 		apicmd.NewCmd(f),
+		batch.NewCmd(f, NewCommand),
 		skill.NewCmd(f),
 		updatecmd.NewCmd(f),
+		versioncmd.NewCmd(f),
 	)
 
+	addGrouped(cmd, "misc", examples.NewCmd(f, NewCommand))
+
 	cmd.SetHelpCommandGroupID("misc")
 	cmd.SetCompletionCommandGroupID("misc")
 
@@ -119,19 +156,31 @@ func Execute(ctx context.Context) error {
 	f := cmdutil.NewFactory()
 	f.StartTime = time.Now()
 	f.SetContext(ctx)
-	rootCmd := buildRootCmd(f)
-	rootCmd.SetContext(ctx)
-
-	alias.RegisterAliases(rootCmd, f)
-	rootCmd.SilenceErrors = true
-	rootCmd.SilenceUsage = true
-	executedCmd, err := rootCmd.ExecuteC()
+	defer f.StopTimeout()
+	completion.UseServer(f.Client)
+	executedCmd, err := executeRoot(ctx, f, os.Args[1:])
+	if err != nil && ctx.Err() == nil {
+		err = cmdutil.SuggestRenamedJob(f, err)
+		if renamed, ok := errors.AsType[*cmdutil.RenamedJobError](err); ok && f.FollowRenames && renamed.NewID() != "" {
+			output.StopSpinner()
+			f.Printer.Warn("Job %s was renamed to %s; continuing with %s", renamed.ID, renamed.NewID(), renamed.NewID())
+			f.FollowRename(renamed.ID, renamed.NewID())
+			executedCmd, err = executeRoot(ctx, f, cmdutil.RenameJobArgs(os.Args[1:], renamed.ID, renamed.NewID()))
+		}
+	}
+	if err != nil && f.TimedOut() {
+		err = api.Validation(fmt.Sprintf("timed out after %s", f.Timeout), "Raise --timeout, or drop it to let the command finish")
+	}
 	output.StopSpinner()
+	if r := f.Recorder(); r != nil && r.Err() != nil {
+		f.Printer.Warn("Could not write the API recording: %v", r.Err())
+	}
 	if f.UpdateNotice != nil {
 		f.UpdateNotice()
 	}
 	defer trackAndFlushAnalytics(f, executedCmd, err)
-	if err != nil && ctx.Err() != nil {
+	if err != nil && (ctx.Err() != nil || output.IsClosedSink(err)) {
+		// Interrupted, or the reader of stdout went away (a pager quit, | head): neither is a failure.
 		return nil
 	}
 	if !f.JSONOutput && executedCmd != nil && jsonOutputEnabled(executedCmd) {
@@ -153,6 +202,48 @@ func Execute(ctx context.Context) error {
 	return err
 }
 
+// executeRoot builds a root command on f and runs it with args.
+func executeRoot(ctx context.Context, f *cmdutil.Factory, args []string) (*cobra.Command, error) {
+	rootCmd := buildRootCmd(f)
+	rootCmd.SetContext(ctx)
+	rootCmd.SetArgs(args)
+
+	alias.RegisterAliases(rootCmd, f)
+	if p, ok := findPlugin(rootCmd, args); ok {
+		return nil, runPlugin(f, p, args[1:])
+	}
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	return rootCmd.ExecuteC()
+}
+
+// findPlugin returns the tc-<name> plugin that args invoke: their first word must name neither a built-in
+// command nor an alias. Global flags before the name are not supported, as in git.
+func findPlugin(rootCmd *cobra.Command, args []string) (plugin.Plugin, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") ||
+		args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd {
+		return plugin.Plugin{}, false
+	}
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd(args...)
+	if c, _, err := rootCmd.Find(args[:1]); err == nil && c != rootCmd {
+		return plugin.Plugin{}, false
+	}
+	return plugin.Lookup(args[0])
+}
+
+// runPlugin runs p on the CLI's own streams and turns its exit status into the CLI's.
+func runPlugin(f *cmdutil.Factory, p plugin.Plugin, args []string) error {
+	code, err := plugin.Run(p, args, plugin.Env(), f.IOStreams.In, f.IOStreams.Out, f.IOStreams.ErrOut)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return &cmdutil.ExitError{Code: code}
+	}
+	return nil
+}
+
 func tryAutoReauth(f *cmdutil.Factory) {
 	if !f.IsInteractive() {
 		return
@@ -189,16 +280,35 @@ func jsonOutputEnabled(cmd *cobra.Command) bool {
 	return f != nil && f.Changed && f.Value.String() != "false"
 }
 
-// NewCommand builds a root command for tests and doc generation.
+// applyRequestFlags applies the global flags that shape API requests, --server, --retries, --cache and --timeout, to f.
+// Commands with a --server flag of their own shadow the global one, which then stays empty.
+// A Child keeps the --retries and --cache it inherited unless its own command line sets them.
+func applyRequestFlags(cmd *cobra.Command, f *cmdutil.Factory) error {
+	if err := f.ApplyServer(); err != nil {
+		return api.Validation(err.Error(), "Pass a server URL or the context label of a configured server")
+	}
+	f.RetriesSet = f.RetriesSet || cmd.Flags().Changed("retries")
+	f.CacheSet = f.CacheSet || cmd.Flags().Changed("cache")
+	cmd.SetContext(f.ApplyTimeout())
+	return nil
+}
+
+// NewCommand builds a root command for tests, doc generation, and the
+// in-process commands batch and examples run on a Child factory.
 // Pass nil for f to get a fresh production factory. PersistentPreRun is
-// stripped so tests and doc walks don't spawn the update-check goroutine
-// or race on output globals. Aliases are not registered — callers that
-// need them invoke alias.RegisterAliases themselves.
+// reduced to applyRequestFlags and the printer's --quiet and --verbose so
+// tests and doc walks don't spawn the update-check goroutine or race on
+// output globals. Aliases are not registered — callers that need them
+// invoke alias.RegisterAliases themselves.
 func NewCommand(f *cmdutil.Factory) *cobra.Command {
 	if f == nil {
 		f = cmdutil.NewFactory()
 	}
-	cmd := buildRootCmd(f)
-	cmd.PersistentPreRun = nil
+	var cmd *cobra.Command
+	f.KeepFlags(func() { cmd = buildRootCmd(f) })
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		f.Printer.Quiet, f.Printer.Verbose = f.Quiet, f.Verbose
+		return applyRequestFlags(cmd, f)
+	}
 	return cmd
 }
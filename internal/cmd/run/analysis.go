@@ -1,21 +1,30 @@
 package run
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/dustin/go-humanize/english"
 	"github.com/spf13/cobra"
 )
 
 type runChangesOptions struct {
-	noFiles bool
-	json    bool
+	job          string
+	noFiles      bool
+	json         bool
+	format       string
+	linkTemplate string
+	groupBy      string
+	noMerges     bool
 }
 
 func newRunChangesCmd(f *cmdutil.Factory) *cobra.Command {
@@ -24,10 +33,18 @@ func newRunChangesCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "changes <id>",
 		Short: "Show VCS changes",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show the VCS changes (commits) included in a run.
+
+--format markdown renders the commits as a Markdown bullet list that can be
+pasted into release notes. Short SHAs link to the VCS web UI when a link
+template is known: pass --link-template, or set one per VCS root with
+  teamcity config set commit_link.<vcs-root-id> 'https://github.com/org/repo/commit/{sha}'`,
+		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run changes 12345
   teamcity run changes 12345 --no-files
-  teamcity run changes 12345 --json`,
+  teamcity run changes 12345 --json
+  teamcity run changes 12345 --format markdown --no-merges
+  teamcity run changes 12345 --format markdown --group-by author --link-template 'https://github.com/org/repo/commit/{sha}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRunChanges(f, args[0], opts)
 		},
@@ -35,26 +52,75 @@ func newRunChangesCmd(f *cmdutil.Factory) *cobra.Command {
 
 	cmd.Flags().BoolVar(&opts.noFiles, "no-files", false, "Hide file list, show commits only")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&opts.format, "format", "text", "Output format: text, markdown")
+	cmd.Flags().StringVar(&opts.linkTemplate, "link-template", "", "Commit URL template for markdown, with a {sha} placeholder")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "Group markdown commits: author")
+	cmd.Flags().BoolVar(&opts.noMerges, "no-merges", false, "Exclude merge commits")
+	addRunJobFlag(cmd, &opts.job)
+	cmd.MarkFlagsMutuallyExclusive("json", "format")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.Fixed("text", "markdown"))
+	_ = cmd.RegisterFlagCompletionFunc("group-by", completion.Fixed("author"))
 
 	return cmd
 }
 
+func (o *runChangesOptions) validate() error {
+	if o.format != "text" && o.format != "markdown" {
+		return fmt.Errorf("invalid format %q, must be one of: text, markdown", o.format)
+	}
+	if o.groupBy != "" && o.groupBy != "author" {
+		return fmt.Errorf("invalid group-by %q, must be one of: author", o.groupBy)
+	}
+	if o.format != "markdown" && (o.groupBy != "" || o.linkTemplate != "") {
+		return api.Validation("--group-by and --link-template only apply to --format markdown", "add --format markdown")
+	}
+	if o.linkTemplate != "" {
+		return validateLinkTemplate(o.linkTemplate)
+	}
+	return nil
+}
+
 func runRunChanges(f *cmdutil.Factory, runID string, opts *runChangesOptions) error {
-	p := f.Printer
+	if err := opts.validate(); err != nil {
+		return err
+	}
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	changes, err := client.GetBuildChanges(f.Context(), runID)
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+	return showRunChanges(f, client, runID, opts)
+}
+
+// showRunChanges renders the VCS changes of a run read from src.
+func showRunChanges(f *cmdutil.Factory, src runSource, runID string, opts *runChangesOptions) error {
+	p := f.Printer
+	changes, err := src.GetBuildChanges(f.Context(), runID)
 	if err != nil {
 		return fmt.Errorf("failed to get changes: %w", err)
 	}
+	if opts.noMerges {
+		changes.Change = withoutMerges(changes.Change)
+		changes.Count = len(changes.Change)
+	}
 
 	if opts.json {
 		return p.PrintJSON(changes)
 	}
 
+	if opts.format == "markdown" {
+		// Stays silent for an empty run so the output can go straight into a release body.
+		writeChangesMarkdown(p.Out, changes.Change, func(c api.Change) string {
+			return commitLinkTemplate(c, opts.linkTemplate)
+		}, opts.groupBy == "author")
+		return nil
+	}
+
 	if changes.Count == 0 {
 		p.Info("No changes in this run")
 		return nil
@@ -120,13 +186,17 @@ func runRunChanges(f *cmdutil.Factory, runID string, opts *runChangesOptions) er
 }
 
 type runTestsOptions struct {
-	failed bool
-	muted  bool
-	json   bool
-	limit  int
-	job    string
-	test   string
-	web    bool
+	failed       bool
+	newFailures  bool
+	muted        bool
+	json         bool
+	limit        int
+	job          string
+	test         string
+	web          bool
+	mergeBatches bool
+	links        bool
+	artifacts    testArtifactsOptions
 }
 
 func newRunTestsCmd(f *cmdutil.Factory) *cobra.Command {
@@ -138,14 +208,28 @@ func newRunTestsCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `Show test results from a run.
 
 You can specify a run ID directly, or use --job to get the latest run's tests.
+With both, a run number such as #482 is looked up among the job's runs.
 
 Pass --test NAME to follow one test across builds instead of a single run:
   --job X --test NAME    that test's history in job X
-  --test NAME            that test's history server-wide`,
+  --test NAME            that test's history server-wide
+
+Runs of parallel-tests or matrix jobs fan out into batch builds; pass
+--merge-batches to collect the tests of every batch into one list.
+
+With --failed, --download-artifacts fetches the artifacts a test framework
+attached to each failed test, such as screenshots or videos, into one
+folder per test. --artifact-template locates them: {test} is replaced by
+the test name, sanitized by --sanitize rules (REGEXP=REPLACEMENT, applied
+in order; by default every run of characters other than letters, digits,
+'.', '_' and '-' becomes '_'). Tests without matching artifacts are
+reported but do not fail the command.
+
+--new-failures separates fresh regressions from long-broken tests: it
+compares the run with the job's previous finished run on the same branch
+and lists only the tests that failed now but not then, with a count of
+those still failing. In the first run on a branch, every failure is new.`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 && cmd.Flags().Changed("job") {
-				return api.MutuallyExclusive("id", "job")
-			}
 			// --test is a cross-build query; a single build has no history.
 			if len(args) > 0 && cmd.Flags().Changed("test") {
 				return api.Validation("a run ID and --test cannot be combined", "use --job JOB --test NAME for a job's history, or --test NAME alone for server-wide")
@@ -155,7 +239,13 @@ Pass --test NAME to follow one test across builds instead of a single run:
 		Example: `  teamcity run tests 12345
   teamcity run tests 12345 --failed
   teamcity run tests --job Falcon_Build
-  teamcity run tests --job Falcon_Build --test com.acme.FooTest.bar`,
+  teamcity run tests --job Falcon_Build --test com.acme.FooTest.bar
+  teamcity run tests 12345 --merge-batches --failed
+  teamcity run tests 12345 --failed --links
+  teamcity run tests 12345 --new-failures
+  teamcity run tests --job Falcon_Build --new-failures --json
+  teamcity run tests 12345 --failed --download-artifacts
+  teamcity run tests 12345 --failed --download-artifacts=./failures --artifact-template 'screenshots/{test}*'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var runID string
 			if len(args) > 0 {
@@ -167,26 +257,58 @@ Pass --test NAME to follow one test across builds instead of a single run:
 			if runID == "" && opts.job == "" && opts.test == "" {
 				opts.job = f.ResolveDefaultJob("")
 			}
+			if opts.newFailures {
+				opts.failed = true
+			}
+			if opts.artifacts.dir != "" {
+				if !opts.failed {
+					return api.Validation("--download-artifacts requires --failed", "add --failed")
+				}
+				if err := opts.artifacts.validate(); err != nil {
+					return err
+				}
+			}
 			return runRunTests(f, runID, opts)
 		},
 	}
 
 	cmd.Flags().BoolVar(&opts.failed, "failed", false, "Show only failed tests, excluding muted")
+	cmd.Flags().BoolVar(&opts.newFailures, "new-failures", false, "Show only tests that failed here but not in the previous run on the same branch")
 	cmd.Flags().BoolVar(&opts.muted, "muted", false, "Show only muted failed tests")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
-	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 0, "Maximum number of items")
-	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 0, "Maximum number of items (0 for all)")
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest, or look up a run number in it")
 	cmd.Flags().StringVar(&opts.test, "test", "", "Follow one test across builds (history) instead of a single run")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the run's tests in browser")
+	cmd.Flags().BoolVar(&opts.mergeBatches, "merge-batches", false, "Merge tests from all batches of a parallel-tests or matrix run")
+	cmd.Flags().BoolVar(&opts.links, "links", false, "Show a web link for each test (adds webUrl to --json)")
+	cmd.Flags().StringVar(&opts.artifacts.dir, "download-artifacts", "", "Download each failed test's artifacts into per-test folders under this directory (default test-artifacts)")
+	cmd.Flags().Lookup("download-artifacts").NoOptDefVal = "test-artifacts"
+	cmd.Flags().StringVar(&opts.artifacts.template, "artifact-template", "{test}*", "Artifact path pattern of a test's artifacts; {test} is the sanitized test name")
+	cmd.Flags().StringArrayVar(&opts.artifacts.sanitize, "sanitize", nil, "Test name replacement rule REGEXP=REPLACEMENT for {test} (repeatable)")
 	cmd.MarkFlagsMutuallyExclusive("failed", "muted")
+	cmd.MarkFlagsMutuallyExclusive("new-failures", "muted")
+	cmd.MarkFlagsMutuallyExclusive("new-failures", "test")
+	cmd.MarkFlagsMutuallyExclusive("new-failures", "merge-batches")
+	cmd.MarkFlagsMutuallyExclusive("new-failures", "download-artifacts")
 	cmd.MarkFlagsMutuallyExclusive("json", "web")
 	cmd.MarkFlagsMutuallyExclusive("test", "web") // history spans builds — no single page
+	cmd.MarkFlagsMutuallyExclusive("test", "merge-batches")
+	cmd.MarkFlagsMutuallyExclusive("test", "links")
+	cmd.MarkFlagsMutuallyExclusive("web", "links")
+	cmd.MarkFlagsMutuallyExclusive("download-artifacts", "json")
+	cmd.MarkFlagsMutuallyExclusive("download-artifacts", "web")
+	cmd.MarkFlagsMutuallyExclusive("download-artifacts", "test")
+	_ = cmd.MarkFlagDirname("download-artifacts")
 
 	return cmd
 }
 
 func runRunTests(f *cmdutil.Factory, runID string, opts *runTestsOptions) error {
 	p := f.Printer
+	if err := cmdutil.CheckLimit(f, opts.limit); err != nil {
+		return err
+	}
 	client, err := f.Client()
 	if err != nil {
 		return err
@@ -196,7 +318,7 @@ func runRunTests(f *cmdutil.Factory, runID string, opts *runTestsOptions) error
 		return runTestHistory(f, client, opts)
 	}
 
-	resolvedID, _, err := resolveRunID(f.Context(), client, runID, opts.job, "")
+	resolvedID, _, err := resolveRunID(f, client, runID, opts.job, "")
 	if err != nil {
 		return err
 	}
@@ -217,17 +339,50 @@ func runRunTests(f *cmdutil.Factory, runID string, opts *runTestsOptions) error
 		"is_from_job": opts.job != "",
 	})
 
-	tests, err := client.GetBuildTests(f.Context(), runID, api.BuildTestsOptions{
+	if opts.newFailures {
+		return runNewFailures(f, client, build, opts)
+	}
+
+	tests, err := showRunTests(f, client, build, opts)
+	if err != nil || tests == nil || opts.artifacts.dir == "" {
+		return err
+	}
+	return downloadTestArtifacts(f, client, runID, tests.TestOccurrence, &opts.artifacts)
+}
+
+// showRunTests renders the tests of build read from src. It returns the tests it listed, or nil when
+// there were none or they were printed as JSON.
+func showRunTests(f *cmdutil.Factory, src runSource, build *api.Build, opts *runTestsOptions) (*api.TestOccurrences, error) {
+	p := f.Printer
+	testOpts := api.BuildTestsOptions{
 		FailedOnly: opts.failed,
 		MutedOnly:  opts.muted,
 		Limit:      opts.limit,
-	})
+	}
+	var batches []api.BuildBatch
+	if opts.mergeBatches {
+		var err error
+		if batches, err = src.GetBuildBatches(f.Context(), build); err != nil {
+			return nil, fmt.Errorf("failed to get batches: %w", err)
+		}
+	}
+
+	var tests *api.TestOccurrences
+	var err error
+	if len(batches) > 0 {
+		tests, err = getBatchTests(f, src, batches, testOpts)
+	} else {
+		tests, err = src.GetBuildTests(f.Context(), strconv.Itoa(build.ID), testOpts)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get tests: %w", err)
+		return nil, fmt.Errorf("failed to get tests: %w", err)
 	}
 
 	if opts.json {
-		return p.PrintJSON(tests)
+		if opts.links {
+			return nil, p.PrintJSON(testsWithLinks(tests, build.WebURL))
+		}
+		return nil, p.PrintJSON(tests)
 	}
 
 	if tests.Count == 0 {
@@ -239,27 +394,110 @@ func runRunTests(f *cmdutil.Factory, runID string, opts *runTestsOptions) error
 		default:
 			p.Info("No tests in this run")
 		}
-		return nil
+		if !opts.mergeBatches {
+			if batches, _ := src.GetBuildBatches(f.Context(), build); len(batches) > 0 {
+				p.Tip("This run fanned out into %d batches; use --merge-batches to include their tests", len(batches))
+			}
+		}
+		return nil, nil
 	}
 
+	batchLabels := make(map[int]string, len(batches))
+	for _, b := range batches {
+		batchLabels[b.Build.ID] = b.Label
+	}
 	for _, t := range tests.TestOccurrence {
+		name := t.Name
+		if t.Build != nil && batchLabels[t.Build.ID] != "" {
+			name += " " + output.Faint("("+batchLabels[t.Build.ID]+")")
+		}
 		switch t.Status {
 		case "FAILURE":
 			if t.Muted {
-				_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint(output.Sym().Skip), t.Name)
+				_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint(output.Sym().Skip), name)
 			} else {
-				_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Red(output.Sym().Cross), t.Name)
+				_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Red(output.Sym().Cross), name)
 			}
 		case "SUCCESS":
-			_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Green(output.Sym().Check), t.Name)
+			_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Green(output.Sym().Check), name)
 		default:
-			_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint(output.Sym().Neutral), t.Name)
+			_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Faint(output.Sym().Neutral), name)
+		}
+		if opts.links {
+			_, _ = fmt.Fprintf(p.Out, "    %s\n", output.Faint(testWebURL(t, build.WebURL)))
 		}
 	}
 
 	_, _ = fmt.Fprintf(p.Out, "\nTESTS: %s\n", output.TestCountsSummary(tests))
-	_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), runTestsBrowserURL(build.WebURL, opts))
-	return nil
+	if build.WebURL != "" {
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), runTestsBrowserURL(build.WebURL, opts))
+	}
+	return tests, nil
+}
+
+// batchWorkers caps concurrent per-batch test fetches; wide matrices can have dozens of batches.
+const batchWorkers = 8
+
+// testWebURL links to t on its own run's page: the batch build for merged batches, otherwise runWebURL.
+func testWebURL(t api.TestOccurrence, runWebURL string) string {
+	if t.Build != nil && t.Build.WebURL != "" {
+		return cmdutil.TestOccurrenceURL(t.Build.WebURL, t)
+	}
+	return cmdutil.TestOccurrenceURL(runWebURL, t)
+}
+
+// linkedTestOccurrence is a test occurrence with its web link, for run tests --links --json.
+type linkedTestOccurrence struct {
+	api.TestOccurrence
+	WebURL string `json:"webUrl"`
+}
+
+// testsWithLinks returns tests with a webUrl added to every occurrence, keeping the counts of api.TestOccurrences.
+func testsWithLinks(tests *api.TestOccurrences, runWebURL string) any {
+	linked := make([]linkedTestOccurrence, len(tests.TestOccurrence))
+	for i, t := range tests.TestOccurrence {
+		linked[i] = linkedTestOccurrence{TestOccurrence: t, WebURL: testWebURL(t, runWebURL)}
+	}
+	return struct {
+		*api.TestOccurrences
+		TestOccurrence []linkedTestOccurrence `json:"testOccurrence"`
+	}{tests, linked}
+}
+
+// getBatchTests fetches each batch's tests concurrently and merges them, tagging every occurrence with its batch build.
+func getBatchTests(f *cmdutil.Factory, src runSource, batches []api.BuildBatch, opts api.BuildTestsOptions) (*api.TestOccurrences, error) {
+	results := make([]*api.TestOccurrences, len(batches))
+	errs := make([]error, len(batches))
+	limiter := f.NewLimiter(batchWorkers)
+	var wg sync.WaitGroup
+	for i, b := range batches {
+		wg.Go(func() {
+			limiter.Acquire()
+			defer limiter.Release()
+			results[i], errs[i] = src.GetBuildTests(f.Context(), strconv.Itoa(b.Build.ID), opts)
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	merged := &api.TestOccurrences{TestOccurrence: []api.TestOccurrence{}}
+	for i, r := range results {
+		merged.Count += r.Count
+		merged.Passed += r.Passed
+		merged.Failed += r.Failed
+		merged.Ignored += r.Ignored
+		merged.Muted += r.Muted
+		for _, t := range r.TestOccurrence {
+			t.Build = batches[i].Build
+			merged.TestOccurrence = append(merged.TestOccurrence, t)
+		}
+	}
+	if opts.Limit > 0 && len(merged.TestOccurrence) > opts.Limit {
+		merged.TestOccurrence = merged.TestOccurrence[:opts.Limit]
+	}
+	return merged, nil
 }
 
 // runTestHistory shows one test across builds: scoped to a job (buildType+test) or server-wide (test alone).
@@ -0,0 +1,211 @@
+package run
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+type runApproveOptions struct {
+	job    string
+	latest bool
+	yes    bool
+}
+
+func newRunApproveCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runApproveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Approve the queued run of a job",
+		Long: `Find the queued run of a job that is waiting for approval, show who
+requested it with its branch and parameters, and approve it. Values of
+password parameters are masked.
+
+When several runs of the job are waiting, pick one interactively, or pass
+--latest to approve the most recently queued one. To approve a run by ID,
+use 'teamcity queue approve <id>'.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity run approve --job Falcon_Release
+  teamcity run approve --job Falcon_Release --latest --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunApprove(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Job whose queued run to approve")
+	cmd.Flags().BoolVar(&opts.latest, "latest", false, "Approve the most recently queued run when several are waiting")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+// approvalQueueFields are the queue fields shown before approving, including the run's custom parameters
+// and their types, so password values can be masked.
+var approvalQueueFields = append(slices.Clone(api.QueuedBuildFields.Default),
+	"triggered.date", "properties.property.name", "properties.property.value", "properties.property.type.rawValue")
+
+// pendingApproval is a queued run together with its approval state.
+type pendingApproval struct {
+	build    api.QueuedBuild
+	approval *api.ApprovalInfo
+}
+
+// findPendingApprovals returns the queued runs of jobID and, separately, those waiting for approval, newest first.
+// Runs without approval info (jobs without an approval feature 404) are not waiting; other errors are returned.
+func findPendingApprovals(client api.ClientInterface, jobID string) ([]api.QueuedBuild, []pendingApproval, error) {
+	queue, _, err := client.GetBuildQueue(api.QueueOptions{BuildTypeID: jobID, Fields: approvalQueueFields})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list queued runs of %s: %w", jobID, err)
+	}
+	var waiting []pendingApproval
+	for _, b := range queue.Builds {
+		info, err := client.GetQueuedBuildApprovalInfo(strconv.Itoa(b.ID))
+		if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get approval state of run %d: %w", b.ID, err)
+		}
+		if info.Status != "waitingForApproval" {
+			continue
+		}
+		waiting = append(waiting, pendingApproval{build: b, approval: info})
+	}
+	slices.SortFunc(waiting, func(a, b pendingApproval) int { return cmp.Compare(b.build.ID, a.build.ID) })
+	return queue.Builds, waiting, nil
+}
+
+func approvalLabel(b api.QueuedBuild) string {
+	label := fmt.Sprintf("%d  %s", b.ID, cmp.Or(b.BranchName, "<default>"))
+	if by := queuedRequester(b); by != "" {
+		label += "  " + output.Sym().Sep + " " + by
+	}
+	return label
+}
+
+// queuedRequester names who queued b: the user when known, otherwise the trigger type.
+func queuedRequester(b api.QueuedBuild) string {
+	return cmdutil.TriggeredBy(b.Triggered)
+}
+
+func runRunApprove(f *cmdutil.Factory, opts *runApproveOptions) error {
+	jobID := f.ResolveDefaultJob(opts.job)
+	if jobID == "" {
+		return api.Validation("--job is required", "Pass the job whose run to approve, e.g. --job MyProject_Release")
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	queued, waiting, err := findPendingApprovals(client, jobID)
+	if err != nil {
+		return err
+	}
+
+	var pick pendingApproval
+	switch {
+	case len(waiting) == 0 && len(queued) == 0:
+		return api.Validation(
+			fmt.Sprintf("no queued runs of %s", jobID),
+			"The run may have been approved and started already; check with: teamcity run list --job "+jobID,
+		)
+	case len(waiting) == 0:
+		return api.Validation(
+			fmt.Sprintf("none of the %d queued runs of %s is waiting for approval", len(queued), jobID),
+			"They may be approved already; check with: teamcity queue list --job "+jobID,
+		)
+	case len(waiting) == 1 || opts.latest:
+		pick = waiting[0]
+	case f.IsInteractive():
+		options := make([]huh.Option[int], len(waiting))
+		for i, w := range waiting {
+			options[i] = huh.NewOption(approvalLabel(w.build), i)
+		}
+		var selected int
+		if err := cmdutil.Select(f.Printer, "Select run to approve", options, &selected); err != nil {
+			return err
+		}
+		pick = waiting[selected]
+	default:
+		lines := make([]string, len(waiting))
+		for i, w := range waiting {
+			lines[i] = "  " + approvalLabel(w.build)
+		}
+		return api.Validation(
+			fmt.Sprintf("%d runs of %s are waiting for approval:\n%s", len(waiting), jobID, strings.Join(lines, "\n")),
+			"Pass --latest to approve the newest, or approve one by ID: teamcity queue approve <id>",
+		)
+	}
+
+	b := pick.build
+	if !pick.approval.CanBeApprovedByCurrentUser {
+		return api.Validation(
+			fmt.Sprintf("you cannot approve run %d of %s", b.ID, jobID),
+			"Approval is limited to the users or groups set in the job's approval feature; ask one of them",
+		)
+	}
+
+	printApprovalDetails(f.Printer, b)
+	if !opts.yes && f.IsInteractive() {
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("Approve run %d?", b.ID), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			f.Printer.Info("Canceled")
+			return nil
+		}
+	}
+
+	id := strconv.Itoa(b.ID)
+	if err := client.ApproveQueuedBuild(id); err != nil {
+		if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+			return fmt.Errorf("run %d is no longer queued; it was started or removed", b.ID)
+		}
+		return fmt.Errorf("failed to approve run %d: %w", b.ID, err)
+	}
+	f.Printer.Success("Approved run %d of %s", b.ID, jobID)
+	if b.WebURL != "" {
+		f.Printer.Info("  URL: %s", b.WebURL)
+	}
+	return nil
+}
+
+// printApprovalDetails shows what is about to be approved: job, requester, branch, and custom parameters,
+// with password values masked.
+func printApprovalDetails(p *output.Printer, b api.QueuedBuild) {
+	job := b.BuildTypeID
+	if name := cmdutil.JobName(b.BuildType, b.BuildTypeID); name != b.BuildTypeID {
+		job = fmt.Sprintf("%s (%s)", name, b.BuildTypeID)
+	}
+	_, _ = fmt.Fprintf(p.Out, "%-12s %d\n", output.Faint("Run"), b.ID)
+	_, _ = fmt.Fprintf(p.Out, "%-12s %s\n", output.Faint("Job"), job)
+	if by := queuedRequester(b); by != "" {
+		_, _ = fmt.Fprintf(p.Out, "%-12s %s\n", output.Faint("Requested by"), by)
+	}
+	_, _ = fmt.Fprintf(p.Out, "%-12s %s\n", output.Faint("Branch"), cmp.Or(b.BranchName, "<default>"))
+	if b.Properties != nil && len(b.Properties.Property) > 0 {
+		_, _ = fmt.Fprintln(p.Out, output.Faint("Parameters"))
+		for _, prop := range b.Properties.Property {
+			value := prop.Value
+			if prop.IsPassword() {
+				value = maskedValue
+			}
+			_, _ = fmt.Fprintf(p.Out, "  %s = %s\n", prop.Name, value)
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package run
 
 import (
-	"context"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
@@ -14,9 +14,13 @@ import (
 )
 
 type runArtifactsOptions struct {
-	job  string
-	path string
-	json bool
+	job     string
+	path    string
+	minSize string
+	maxSize string
+	json    bool
+	tree    bool
+	plain   bool
 }
 
 func newRunArtifactsCmd(f *cmdutil.Factory) *cobra.Command {
@@ -27,16 +31,22 @@ func newRunArtifactsCmd(f *cmdutil.Factory) *cobra.Command {
 		Short: "List artifacts",
 		Long: `List artifacts from a run without downloading them.
 
-Shows artifact names and sizes. Use teamcity run download to download artifacts.`,
-		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 && cmd.Flags().Changed("job") {
-				return api.MutuallyExclusive("id", "job")
-			}
-			return cobra.MaximumNArgs(1)(cmd, args)
-		},
+Shows artifact names and sizes. Use teamcity run download to download artifacts.
+
+--tree shows every subdirectory as a tree, with sizes and modification
+times. --plain prints the full path of every file, one per line, to pipe
+into other commands.
+
+--min-size and --max-size list only files within the size range, searching
+every subdirectory. Sizes accept units: KB, MB, and GB are powers of 1000,
+KiB, MiB, and GiB powers of 1024.`,
+		Args: cobra.MaximumNArgs(1),
 		Example: `  teamcity run artifacts 12345
   teamcity run artifacts 12345 --json
   teamcity run artifacts 12345 --path html_reports/coverage
+  teamcity run artifacts 12345 --tree
+  teamcity run artifacts 12345 --plain | grep '\.log$' | xargs -I{} teamcity run download 12345 -a {}
+  teamcity run artifacts 12345 --min-size 100MB
   teamcity run artifacts --job MyBuild`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var runID string
@@ -50,29 +60,44 @@ Shows artifact names and sizes. Use teamcity run download to download artifacts.
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest")
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest, or look up a run number in it")
 	cmd.Flags().StringVarP(&opts.path, "path", "p", "", "Browse artifacts under this subdirectory")
+	cmd.Flags().StringVar(&opts.minSize, "min-size", "", "Only list files at least this large (e.g., 100MB, 1.5GiB)")
+	cmd.Flags().StringVar(&opts.maxSize, "max-size", "", "Only list files at most this large (e.g., 500KB)")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.tree, "tree", false, "Show every subdirectory as a tree with sizes and modification times")
+	cmd.Flags().BoolVar(&opts.plain, "plain", false, "Print the full path of every file, one per line")
+
+	cmd.MarkFlagsMutuallyExclusive("json", "tree", "plain")
 
 	return cmd
 }
 
 func runRunArtifacts(f *cmdutil.Factory, runID string, opts *runArtifactsOptions) error {
+	sizes, err := cmdutil.ParseSizeRange(opts.minSize, opts.maxSize)
+	if err != nil {
+		return err
+	}
+
 	p := f.Printer
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	resolvedID, latest, err := resolveRunID(f.Context(), client, runID, opts.job, "finished")
+	resolvedID, latest, err := resolveRunID(f, client, runID, opts.job, "finished")
 	if err != nil {
 		return err
 	}
 	runID = resolvedID
-	if latest != nil {
+	if latest != nil && !opts.json && !opts.plain {
 		p.Info("Listing artifacts for run %s  #%s", runID, latest.Number)
 	}
 
+	if sizes.IsSet() || opts.tree || opts.plain {
+		return listAllArtifacts(f, client, runID, opts, sizes)
+	}
+
 	artifacts, err := client.GetArtifacts(f.Context(), runID, opts.path)
 	if err != nil {
 		return fmt.Errorf("failed to get artifacts: %w", err)
@@ -122,70 +147,142 @@ func runRunArtifacts(f *cmdutil.Factory, runID string, opts *runArtifactsOptions
 	return nil
 }
 
-// Shared artifact helpers used by both artifacts and download commands.
+// listAllArtifacts lists every file under --path, in any subdirectory, whose size is within sizes.
+func listAllArtifacts(f *cmdutil.Factory, client api.ClientInterface, runID string, opts *runArtifactsOptions, sizes cmdutil.SizeRange) error {
+	p := f.Printer
+	all, _, err := cmdutil.FetchAllArtifacts(f.Context(), client, runID, opts.path)
+	if err != nil {
+		return fmt.Errorf("failed to get artifacts: %w", err)
+	}
 
-func flattenArtifacts(artifacts []api.Artifact, prefix string) ([]api.Artifact, int64) {
-	var result []api.Artifact
+	matched := []api.Artifact{}
 	var totalSize int64
-	for _, a := range artifacts {
-		name := a.Name
-		if prefix != "" {
-			name = prefix + "/" + a.Name
-		}
-		if a.Children != nil && len(a.Children.File) > 0 {
-			nested, size := flattenArtifacts(a.Children.File, name)
-			result = append(result, nested...)
-			totalSize += size
-		} else {
-			result = append(result, api.Artifact{Name: name, Size: a.Size, Content: a.Content, Children: a.Children})
+	for _, a := range all {
+		if sizes.Contains(a.Size) {
+			matched = append(matched, a)
 			totalSize += a.Size
 		}
 	}
-	return result, totalSize
-}
 
-const maxArtifactDepth = 20
+	if opts.json {
+		return p.PrintJSON(api.Artifacts{Count: len(matched), File: matched})
+	}
+	if opts.plain {
+		for _, a := range matched {
+			_, _ = fmt.Fprintln(p.Out, a.Name)
+		}
+		return nil
+	}
 
-func fetchAllArtifacts(ctx context.Context, client api.ClientInterface, runID, basePath string) ([]api.Artifact, int64, error) {
-	return fetchArtifactsRecursive(ctx, client, runID, basePath, 0)
+	switch {
+	case len(all) == 0:
+		p.Empty("No artifacts found for this run", output.TipNoArtifactsFor(runID))
+		return nil
+	case len(matched) == 0:
+		p.Empty(fmt.Sprintf("No artifacts in the size range among %s", english.Plural(len(all), "file", "")), "Widen the range with --min-size or --max-size")
+		return nil
+	case opts.tree:
+		p.PrintTree(artifactTree(matched, opts.path, totalSize))
+		return nil
+	}
+
+	nameWidth := 4 // "NAME"
+	for _, a := range matched {
+		nameWidth = max(nameWidth, len(a.Name))
+	}
+
+	_, _ = fmt.Fprintf(p.Out, "ARTIFACTS (%d of %d %s, %s total)\n\n", len(matched), len(all), english.PluralWord(len(all), "file", "files"), humanize.IBytes(uint64(totalSize)))
+	_, _ = fmt.Fprintf(p.Out, "%-*s  %10s\n", nameWidth, "NAME", "SIZE")
+	for _, a := range matched {
+		_, _ = fmt.Fprintf(p.Out, "%-*s  %s\n", nameWidth, a.Name, output.Faint(fmt.Sprintf("%10s", humanize.IBytes(uint64(a.Size)))))
+	}
+
+	_, _ = fmt.Fprintf(p.Out, "\nDownload one: teamcity run download %s -a \"<name>\"\n", runID)
+	return nil
 }
 
-func fetchArtifactsRecursive(ctx context.Context, client api.ClientInterface, runID, basePath string, depth int) ([]api.Artifact, int64, error) {
-	if depth > maxArtifactDepth {
-		return nil, 0, fmt.Errorf("artifact tree exceeds maximum depth (%d)", maxArtifactDepth)
+// artifactTree nests files, named by their full paths, under their directories below base. Each directory is
+// labeled with its file count and total size, each file with its size and modification time.
+func artifactTree(files []api.Artifact, base string, totalSize int64) output.TreeNode {
+	type dir struct {
+		name  string
+		dirs  []*dir
+		files []api.Artifact
+		count int
+		size  int64
+	}
+	root := &dir{}
+	for _, a := range files {
+		rel := strings.TrimPrefix(strings.TrimPrefix(a.Name, base), "/")
+		d := root
+		d.count++
+		d.size += a.Size
+		parts := strings.Split(rel, "/")
+		for _, name := range parts[:len(parts)-1] {
+			var next *dir
+			for _, sub := range d.dirs {
+				if sub.name == name {
+					next = sub
+					break
+				}
+			}
+			if next == nil {
+				next = &dir{name: name}
+				d.dirs = append(d.dirs, next)
+			}
+			d = next
+			d.count++
+			d.size += a.Size
+		}
+		a.Name = parts[len(parts)-1]
+		d.files = append(d.files, a)
 	}
 
-	select {
-	case <-ctx.Done():
-		return nil, 0, ctx.Err()
-	default:
+	var build func(d *dir) []output.TreeNode
+	build = func(d *dir) []output.TreeNode {
+		var nodes []output.TreeNode
+		for _, sub := range d.dirs {
+			label := sub.name + "/  " + output.Faint(fmt.Sprintf("%s, %s", english.Plural(sub.count, "file", ""), humanize.IBytes(uint64(sub.size))))
+			nodes = append(nodes, output.TreeNode{Label: label, Children: build(sub)})
+		}
+		for _, a := range d.files {
+			details := humanize.IBytes(uint64(a.Size))
+			if t, err := api.ParseTeamCityTime(a.ModTime); err == nil {
+				details += ", " + output.RelativeTime(t)
+			}
+			nodes = append(nodes, output.TreeNode{Label: a.Name + "  " + output.Faint(details)})
+		}
+		return nodes
 	}
 
-	artifacts, err := client.GetArtifacts(ctx, runID, basePath)
-	if err != nil {
-		return nil, 0, err
+	label := "ARTIFACTS"
+	if base != "" {
+		label += " in " + base
 	}
+	label += fmt.Sprintf(" (%s, %s total)", english.Plural(root.count, "file", ""), humanize.IBytes(uint64(totalSize)))
+	return output.TreeNode{Label: label, Children: build(root)}
+}
+
+// Shared artifact helpers used by both artifacts and download commands.
 
+func flattenArtifacts(artifacts []api.Artifact, prefix string) ([]api.Artifact, int64) {
 	var result []api.Artifact
 	var totalSize int64
-	for _, a := range artifacts.File {
+	for _, a := range artifacts {
 		name := a.Name
-		if basePath != "" {
-			name = basePath + "/" + a.Name
+		if prefix != "" {
+			name = prefix + "/" + a.Name
 		}
-		if a.Content != nil {
-			result = append(result, api.Artifact{Name: name, Size: a.Size, Content: a.Content})
-			totalSize += a.Size
-		} else {
-			nested, size, err := fetchArtifactsRecursive(ctx, client, runID, name, depth+1)
-			if err != nil {
-				return nil, 0, err
-			}
+		if a.Children != nil && len(a.Children.File) > 0 {
+			nested, size := flattenArtifacts(a.Children.File, name)
 			result = append(result, nested...)
 			totalSize += size
+		} else {
+			result = append(result, api.Artifact{Name: name, Size: a.Size, Content: a.Content, Children: a.Children})
+			totalSize += a.Size
 		}
 	}
-	return result, totalSize, nil
+	return result, totalSize
 }
 
 func filterArtifacts(artifacts []api.Artifact, pattern string) ([]api.Artifact, int64, error) {
@@ -0,0 +1,408 @@
+package run
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+// bisectTestLimit caps the failed tests listed for the first bad run.
+const bisectTestLimit = 10
+
+type runBisectOptions struct {
+	job     string
+	branch  string
+	since   string
+	maxRuns int
+	json    bool
+}
+
+// bisectRun is a run as reported by run bisect.
+type bisectRun struct {
+	ID         int    `json:"id"`
+	Number     string `json:"number,omitempty"`
+	Status     string `json:"status,omitempty"`
+	StatusText string `json:"statusText,omitempty"`
+	FinishDate string `json:"finishDate,omitempty"`
+	WebURL     string `json:"webUrl,omitempty"`
+}
+
+// bisectBoundary is a flip between two consecutive decisive runs: "broke" from success to failure,
+// "fixed" the other way. Skipped holds the canceled, unknown, and personal runs between them.
+type bisectBoundary struct {
+	Kind    string      `json:"kind"`
+	From    bisectRun   `json:"from"`
+	To      bisectRun   `json:"to"`
+	Skipped []bisectRun `json:"skipped,omitempty"`
+}
+
+// bisectFailedTests summarizes the failed tests of the first bad run.
+type bisectFailedTests struct {
+	Failed int                  `json:"failed"`
+	Tests  []api.TestOccurrence `json:"tests"`
+}
+
+// bisectResult is the outcome of run bisect. FirstBad is nil when no run in the window failed;
+// LastGood is nil when the job was already failing when the window starts (BrokeBeforeWindow).
+type bisectResult struct {
+	Job               string             `json:"job"`
+	Branch            string             `json:"branch,omitempty"`
+	Since             string             `json:"since"`
+	Runs              int                `json:"runs"`
+	Succeeded         int                `json:"succeeded"`
+	Failed            int                `json:"failed"`
+	Truncated         bool               `json:"truncated,omitempty"`
+	FirstBad          *bisectRun         `json:"firstBad,omitempty"`
+	LastGood          *bisectRun         `json:"lastGood,omitempty"`
+	Skipped           []bisectRun        `json:"skipped,omitempty"`
+	BrokeBeforeWindow bool               `json:"brokeBeforeWindow,omitempty"`
+	Changes           []api.Change       `json:"changes,omitempty"`
+	FailedTests       *bisectFailedTests `json:"failedTests,omitempty"`
+	Boundaries        []bisectBoundary   `json:"boundaries"`
+}
+
+func newRunBisectCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runBisectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bisect",
+		Short: "Find the first failed run after the last successful one",
+		Long: `Find where a job turned red.
+
+Fetches the job's finished runs in the window, oldest first, and finds
+each point where a successful run is followed by a failed one. The most
+recent such boundary is reported: its first bad run with the changes it
+picked up and its failed tests, and the last good run before it.
+
+Canceled runs, runs with an unknown result, and personal runs are
+skipped; the changes of runs skipped between the last good and the first
+bad run are included, since any of them may be the culprit. Every
+boundary, including fixes, is listed when the result flipped more than
+once.
+
+If every decisive run in the window failed, the job broke earlier: the
+oldest failed run is reported and --since should be widened.
+
+With no --job, uses the linked default job from teamcity.toml.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity run bisect --job Falcon_Build --branch main
+  teamcity run bisect --job Falcon_Build --since 30d
+  teamcity run bisect --job Falcon_Build --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.job = f.ResolveDefaultJob(opts.job)
+			if opts.job == "" {
+				return api.Validation(
+					"job id is required",
+					"Pass --job <id> or run 'teamcity link' to bind this repository to a job",
+				)
+			}
+			return runRunBisect(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Job ID to bisect")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Only consider runs on this branch")
+	cmd.Flags().StringVar(&opts.since, "since", "14d", "Consider runs finished after this time (e.g., 24h, 14d, 2026-01-01)")
+	cmd.Flags().IntVar(&opts.maxRuns, "max-runs", 500, "Maximum number of runs to scan, newest first")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+func runRunBisect(f *cmdutil.Factory, opts *runBisectOptions) error {
+	if opts.maxRuns < 1 {
+		return fmt.Errorf("--max-runs must be at least 1, got %d", opts.maxRuns)
+	}
+	sinceDate, err := api.ParseUserDate(opts.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	builds, truncated, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+		BuildTypeID: opts.job,
+		Branch:      opts.branch,
+		State:       "finished",
+		SinceDate:   sinceDate,
+		Limit:       opts.maxRuns,
+		Fields:      []string{"id", "number", "status", "statusText", "personal", "finishDate", "webUrl"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+	if builds.Count == 0 {
+		return api.Validation(
+			fmt.Sprintf("no finished runs of %s%s since %s", opts.job, onBranch(opts.branch), opts.since),
+			"Widen the window with --since, or check the job ID and --branch",
+		)
+	}
+
+	runs := slices.Clone(builds.Builds)
+	slices.SortFunc(runs, func(a, b api.Build) int { return cmp.Compare(a.ID, b.ID) })
+
+	result := bisect(runs)
+	result.Job, result.Branch, result.Since, result.Truncated = opts.job, opts.branch, opts.since, truncated
+	if result.Succeeded+result.Failed == 0 {
+		return api.Validation(
+			fmt.Sprintf("none of the %d finished runs of %s%s since %s succeeded or failed", result.Runs, opts.job, onBranch(opts.branch), opts.since),
+			"Canceled and personal runs are skipped; widen the window with --since",
+		)
+	}
+
+	if result.FirstBad != nil {
+		if err := fetchBisectDetails(f, client, result); err != nil {
+			return err
+		}
+	}
+
+	if opts.json {
+		return p.PrintJSON(result)
+	}
+	printBisect(p, result, opts.maxRuns)
+	return nil
+}
+
+func onBranch(branch string) string {
+	if branch == "" {
+		return ""
+	}
+	return " on " + branch
+}
+
+// bisectOutcome classifies a run as good or bad; runs that are neither are skipped.
+func bisectOutcome(b api.Build) (good, bad bool) {
+	if b.Personal {
+		return false, false
+	}
+	switch strings.ToUpper(b.Status) {
+	case "SUCCESS":
+		return true, false
+	case "FAILURE", "ERROR":
+		return false, true
+	}
+	return false, false
+}
+
+func toBisectRun(b api.Build) bisectRun {
+	return bisectRun{ID: b.ID, Number: b.Number, Status: b.Status, StatusText: b.StatusText, FinishDate: b.FinishDate, WebURL: b.WebURL}
+}
+
+// bisect finds every flip between decisive runs in runs (oldest first) and picks the most recent
+// success-to-failure boundary. Without one, the oldest failed run is reported as broken before the window.
+func bisect(runs []api.Build) *bisectResult {
+	result := &bisectResult{Runs: len(runs), Boundaries: []bisectBoundary{}}
+
+	var last *api.Build
+	lastGood := false
+	var skipped []bisectRun
+	var firstBad *api.Build
+	for i := range runs {
+		good, bad := bisectOutcome(runs[i])
+		if !good && !bad {
+			if last != nil {
+				skipped = append(skipped, toBisectRun(runs[i]))
+			}
+			continue
+		}
+		if good {
+			result.Succeeded++
+		} else {
+			result.Failed++
+			if firstBad == nil {
+				firstBad = &runs[i]
+			}
+		}
+		if last != nil && good != lastGood {
+			kind := "fixed"
+			if bad {
+				kind = "broke"
+			}
+			result.Boundaries = append(result.Boundaries, bisectBoundary{
+				Kind:    kind,
+				From:    toBisectRun(*last),
+				To:      toBisectRun(runs[i]),
+				Skipped: skipped,
+			})
+		}
+		last, lastGood, skipped = &runs[i], good, nil
+	}
+
+	for i := len(result.Boundaries) - 1; i >= 0; i-- {
+		if b := result.Boundaries[i]; b.Kind == "broke" {
+			result.LastGood, result.FirstBad, result.Skipped = &b.From, &b.To, b.Skipped
+			return result
+		}
+	}
+	if firstBad != nil {
+		// No success precedes any failure: the job was already red when the window starts.
+		bad := toBisectRun(*firstBad)
+		result.FirstBad, result.BrokeBeforeWindow = &bad, true
+	}
+	return result
+}
+
+// fetchBisectDetails loads the changes of the first bad run and the runs skipped before it, and the
+// first bad run's failed tests, concurrently.
+func fetchBisectDetails(f *cmdutil.Factory, client api.ClientInterface, result *bisectResult) error {
+	// Newest first, matching the order of changes within a run.
+	ids := []int{result.FirstBad.ID}
+	for _, s := range slices.Backward(result.Skipped) {
+		ids = append(ids, s.ID)
+	}
+
+	ctx := f.Context()
+	changes := make([]*api.ChangeList, len(ids))
+	errs := make([]error, len(ids)+2)
+	var tests, summary *api.TestOccurrences
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Go(func() { changes[i], errs[i] = client.GetBuildChanges(ctx, strconv.Itoa(id)) })
+	}
+	firstBad := strconv.Itoa(result.FirstBad.ID)
+	wg.Go(func() {
+		tests, errs[len(ids)] = client.GetBuildTests(ctx, firstBad, api.BuildTestsOptions{FailedOnly: true, Limit: bisectTestLimit})
+	})
+	wg.Go(func() { summary, errs[len(ids)+1] = client.GetBuildTestSummary(firstBad) })
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if i < len(ids) {
+			return fmt.Errorf("failed to get changes of run %d: %w", ids[i], err)
+		}
+		return fmt.Errorf("failed to get tests of run %d: %w", result.FirstBad.ID, err)
+	}
+
+	seen := map[int]bool{}
+	for _, cl := range changes {
+		if cl == nil {
+			continue
+		}
+		for _, c := range cl.Change {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			result.Changes = append(result.Changes, c)
+		}
+	}
+
+	failed := &bisectFailedTests{Tests: []api.TestOccurrence{}}
+	if tests != nil {
+		failed.Tests = tests.TestOccurrence
+		failed.Failed = len(tests.TestOccurrence)
+	}
+	if summary != nil {
+		failed.Failed = max(failed.Failed, summary.Failed)
+	}
+	result.FailedTests = failed
+	return nil
+}
+
+func printBisect(p *output.Printer, r *bisectResult, maxRuns int) {
+	_, _ = fmt.Fprintf(p.Out, "%s %s%s: %d finished runs since %s\n",
+		output.Faint("Bisecting"), output.Cyan(r.Job), onBranch(r.Branch), r.Runs, r.Since)
+	if r.Truncated {
+		p.Warn("Only the latest %d runs were scanned; raise --max-runs or narrow --since to cover the whole window", maxRuns)
+	}
+
+	if r.FirstBad == nil {
+		_, _ = fmt.Fprintln(p.Out)
+		p.Success("No failed runs in the window: all %d decisive runs succeeded", r.Succeeded)
+		return
+	}
+
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintf(p.Out, "%s  %s\n", output.Bold("First bad run:"), bisectRunLine(*r.FirstBad))
+	if r.LastGood != nil {
+		_, _ = fmt.Fprintf(p.Out, "%s  %s\n", output.Bold("Last good run:"), bisectRunLine(*r.LastGood))
+	}
+	if len(r.Skipped) > 0 {
+		ids := make([]string, len(r.Skipped))
+		for i, s := range r.Skipped {
+			ids[i] = strconv.Itoa(s.ID)
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s        %s\n", output.Faint("Skipped:"), strings.Join(ids, ", "))
+		p.Warn("%s between them had no usable result; their changes are included below", english.Plural(len(r.Skipped), "run", ""))
+	}
+	if r.BrokeBeforeWindow {
+		p.Warn("Every decisive run since %s failed; the job broke before the window", r.Since)
+	}
+
+	sectionHeader(p, fmt.Sprintf("CHANGES (%d)", len(r.Changes)))
+	if len(r.Changes) == 0 {
+		_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint("No changes; the failure may come from the environment or a dependency"))
+	}
+	for _, c := range r.Changes {
+		_, _ = fmt.Fprintf(p.Out, "  %s  %s  %s\n", output.Yellow(shortSHA(c.Version)), output.Faint(shortUsername(c.Username)), firstLine(c.Comment))
+	}
+
+	if ft := r.FailedTests; ft != nil && ft.Failed > 0 {
+		sectionHeader(p, fmt.Sprintf("FAILED TESTS (%d)", ft.Failed))
+		for _, t := range ft.Tests {
+			line := fmt.Sprintf("  %s %s", output.Red(output.Sym().Cross), t.Name)
+			if t.NewFailure {
+				line += output.Faint(" (new)")
+			}
+			_, _ = fmt.Fprintln(p.Out, line)
+		}
+		if ft.Failed > len(ft.Tests) {
+			_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(fmt.Sprintf("... and %d more", ft.Failed-len(ft.Tests))))
+		}
+	}
+
+	if len(r.Boundaries) > 1 || (len(r.Boundaries) == 1 && r.BrokeBeforeWindow) {
+		sectionHeader(p, "FLIPS")
+		headers := []string{"KIND", "FROM", "TO", "SKIPPED"}
+		rows := make([][]string, len(r.Boundaries))
+		for i, b := range r.Boundaries {
+			kind := output.Green(b.Kind)
+			if b.Kind == "broke" {
+				kind = output.Red(b.Kind)
+			}
+			rows[i] = []string{kind, bisectRunRef(b.From), bisectRunRef(b.To), strconv.Itoa(len(b.Skipped))}
+		}
+		p.PrintTable(headers, rows)
+	}
+
+	_, _ = fmt.Fprintln(p.Out)
+	if r.BrokeBeforeWindow {
+		p.Tip("Widen the window with --since to find the last good run")
+		return
+	}
+	p.Tip("Compare the two runs with 'teamcity run diff %d %d'", r.LastGood.ID, r.FirstBad.ID)
+}
+
+func bisectRunRef(r bisectRun) string {
+	return fmt.Sprintf("%d  %s", r.ID, cmdutil.RunNumber(r.Number))
+}
+
+func bisectRunLine(r bisectRun) string {
+	line := output.StatusIcon(r.Status, "finished", r.StatusText) + " " + bisectRunRef(r)
+	if r.StatusText != "" {
+		line += output.Faint("  " + truncate(r.StatusText, 80))
+	}
+	if r.WebURL != "" {
+		line += "\n" + strings.Repeat(" ", 16) + output.Faint(r.WebURL)
+	}
+	return line
+}
@@ -0,0 +1,97 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bisectRuns builds runs with IDs 1..n from outcomes: G success, B failure, E error, C canceled, P personal failure.
+func bisectRuns(outcomes string) []api.Build {
+	runs := make([]api.Build, len(outcomes))
+	for i, o := range outcomes {
+		b := api.Build{ID: i + 1}
+		switch o {
+		case 'G':
+			b.Status = "SUCCESS"
+		case 'B':
+			b.Status = "FAILURE"
+		case 'E':
+			b.Status = "ERROR"
+		case 'C':
+			b.Status, b.StatusText = "UNKNOWN", "Canceled"
+		case 'P':
+			b.Status, b.Personal = "FAILURE", true
+		}
+		runs[i] = b
+	}
+	return runs
+}
+
+func skippedIDs(runs []bisectRun) []int {
+	ids := []int{}
+	for _, r := range runs {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestBisect(t *testing.T) {
+	tests := []struct {
+		name       string
+		outcomes   string
+		firstBad   int // 0 when no run failed
+		lastGood   int // 0 when the job broke before the window
+		skipped    []int
+		before     bool
+		boundaries []string
+	}{
+		{name: "single break", outcomes: "GGBB", firstBad: 3, lastGood: 2, skipped: []int{}, boundaries: []string{"broke"}},
+		{name: "no red", outcomes: "GGG", boundaries: []string{}},
+		{name: "no green", outcomes: "BBB", firstBad: 1, skipped: []int{}, before: true, boundaries: []string{}},
+		{name: "error counts as failure", outcomes: "GE", firstBad: 2, lastGood: 1, skipped: []int{}, boundaries: []string{"broke"}},
+		{name: "canceled and personal runs in the gap", outcomes: "GCPB", firstBad: 4, lastGood: 1, skipped: []int{2, 3}, boundaries: []string{"broke"}},
+		{name: "skipped runs before the first decisive run", outcomes: "CGB", firstBad: 3, lastGood: 2, skipped: []int{}, boundaries: []string{"broke"}},
+		{name: "skipped runs between equal results", outcomes: "GCGB", firstBad: 4, lastGood: 3, skipped: []int{}, boundaries: []string{"broke"}},
+		{name: "latest of several breaks", outcomes: "GBGGCB", firstBad: 6, lastGood: 4, skipped: []int{5}, boundaries: []string{"broke", "fixed", "broke"}},
+		{name: "red then fixed", outcomes: "BBG", firstBad: 1, skipped: []int{}, before: true, boundaries: []string{"fixed"}},
+		{name: "only skipped runs", outcomes: "CP", boundaries: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bisect(bisectRuns(tc.outcomes))
+
+			kinds := []string{}
+			for _, b := range r.Boundaries {
+				kinds = append(kinds, b.Kind)
+			}
+			assert.Equal(t, tc.boundaries, kinds)
+			assert.Equal(t, tc.before, r.BrokeBeforeWindow)
+
+			if tc.firstBad == 0 {
+				assert.Nil(t, r.FirstBad)
+				assert.Nil(t, r.LastGood)
+				return
+			}
+			require.NotNil(t, r.FirstBad)
+			assert.Equal(t, tc.firstBad, r.FirstBad.ID)
+			assert.Equal(t, tc.skipped, skippedIDs(r.Skipped))
+			if tc.lastGood == 0 {
+				assert.Nil(t, r.LastGood)
+			} else {
+				require.NotNil(t, r.LastGood)
+				assert.Equal(t, tc.lastGood, r.LastGood.ID)
+			}
+		})
+	}
+}
+
+func TestBisectCounts(t *testing.T) {
+	r := bisect(bisectRuns("GCBPGE"))
+	assert.Equal(t, 6, r.Runs)
+	assert.Equal(t, 2, r.Succeeded)
+	assert.Equal(t, 2, r.Failed)
+}
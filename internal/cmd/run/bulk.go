@@ -0,0 +1,231 @@
+package run
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+// defaultBulkLimit caps how many runs filters select, so a loose filter can't pin a job's whole history.
+const defaultBulkLimit = 100
+
+// runSelector holds the flags with which pin, unpin and tag act on every run that matches, instead of a run ID.
+type runSelector struct {
+	branch  string
+	status  string
+	since   string
+	until   string
+	project string
+	limit   int
+	yes     bool
+}
+
+// selectorFlags are the flags that switch a command from one run ID to the runs that match.
+var selectorFlags = []string{"branch", "status", "since", "until", "project", "limit"}
+
+func (s *runSelector) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&s.branch, "branch", "b", "", "Act on runs of this branch (or '@this' for current git branch)")
+	cmd.Flags().StringVar(&s.status, "status", "", "Act on runs with this status (success, failure, error, unknown, canceled)")
+	cmd.Flags().StringVar(&s.since, "since", "", "Act on runs finished after this time (e.g., 14d, 2026-01-21)")
+	cmd.Flags().StringVar(&s.until, "until", "", "Act on runs finished before this time (e.g., 12h, 2026-01-22)")
+	cmd.Flags().StringVarP(&s.project, "project", "p", "", "Act on runs of jobs in this project")
+	cmd.Flags().IntVarP(&s.limit, "limit", "n", defaultBulkLimit, "Maximum number of matching runs to act on")
+	cmd.Flags().BoolVarP(&s.yes, "yes", "y", false, "Skip the confirmation prompt for matching runs")
+	if job := cmd.Flags().Lookup("job"); job != nil {
+		job.Usage = "Job to look up a run number in, or to pick runs from with the filters"
+	}
+
+	_ = cmd.RegisterFlagCompletionFunc("status", completion.RunStatuses())
+	_ = cmd.RegisterFlagCompletionFunc("branch", completion.Branches())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
+}
+
+// bulkHelp explains the bulk forms in the help of pin, unpin and tag.
+const bulkHelp = `To act on many runs at once, pass - to read run IDs from stdin, one
+per line, or pick runs with --branch, --status, --since, --until,
+--project or --limit instead of a run ID, narrowed by --job. Runs picked
+by filters are listed and confirmed first; --yes skips the prompt, and
+is required when there is no terminal to ask in. Each run is reported as it is done, and if any fail the
+command names them and exits non-zero.`
+
+// args checks the arguments: single when the command is for one run, filtered when filters pick the runs.
+func (s *runSelector) args(single, filtered cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if s.active(cmd) {
+			return filtered(cmd, args)
+		}
+		return single(cmd, args)
+	}
+}
+
+// bulk reports whether the command acts on many runs: read from stdin, or picked by filters.
+func (s *runSelector) bulk(cmd *cobra.Command, args []string) bool {
+	return s.active(cmd) || len(args) > 0 && args[0] == "-"
+}
+
+// active reports whether filters pick the runs, in which case the command takes no run ID.
+func (s *runSelector) active(cmd *cobra.Command) bool {
+	for _, name := range selectorFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the runs of job that match the filters, newest first.
+func (s *runSelector) find(f *cmdutil.Factory, client api.ClientInterface, job string) ([]api.Build, error) {
+	if s.limit <= 0 {
+		return nil, api.Validation("--limit must be positive", fmt.Sprintf("Pass --limit %d, or narrow the filters", defaultBulkLimit))
+	}
+	request, err := resolveRunListRequest(client, &runListOptions{
+		job:     job,
+		branch:  s.branch,
+		status:  s.status,
+		since:   s.since,
+		until:   s.until,
+		project: s.project,
+		limit:   s.limit,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	runs, truncated, err := client.GetBuilds(f.Context(), request.builds)
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		f.Printer.Warn("More than %d runs match; acting on the newest %d. Raise --limit or narrow the filters to include the rest", s.limit, s.limit)
+	}
+	return runs.Builds, nil
+}
+
+// readRunIDs reads run IDs, one per line, for a run argument of "-". Blank lines are skipped.
+func readRunIDs(in io.Reader) ([]api.Build, error) {
+	var runs []api.Build
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		id, err := strconv.Atoi(text)
+		if err != nil || id <= 0 {
+			return nil, api.Validation(
+				fmt.Sprintf("stdin line %d: %q is not a run ID", line, text),
+				"Pass one run ID per line, such as the ID column of 'teamcity run list --plain --no-header'",
+			)
+		}
+		runs = append(runs, api.Build{ID: id})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run IDs: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil, api.Validation("no run IDs on stdin", "Pass one run ID per line")
+	}
+	return runs, nil
+}
+
+// bulkRuns returns the runs a bulk pin, unpin or tag acts on, and whether they were picked by filters (and so
+// should be confirmed), or nil when the command is for a single run.
+func bulkRuns(f *cmdutil.Factory, cmd *cobra.Command, client api.ClientInterface, sel *runSelector, job string, args []string) ([]api.Build, bool, error) {
+	fromStdin := len(args) > 0 && args[0] == "-"
+	switch {
+	case fromStdin && sel.active(cmd):
+		return nil, false, api.Validation("cannot read run IDs from stdin and select runs with filters at once", "Use either '-' or the filter flags")
+	case fromStdin && job != "":
+		return nil, false, api.MutuallyExclusive("-", "job")
+	case fromStdin:
+		runs, err := readRunIDs(f.IOStreams.In)
+		return runs, false, err
+	case sel.active(cmd):
+		runs, err := sel.find(f, client, job)
+		return runs, true, err
+	}
+	return nil, false, nil
+}
+
+// bulkAction names what a bulk command does, for the prompt, progress, and summary: "Pin", "pin" and "Pinned".
+type bulkAction struct {
+	title string
+	verb  string
+	done  string
+}
+
+// runBulk does act to each run in turn, reporting each, and fails at the end naming the runs it failed on. Runs
+// picked by filters are listed first and confirmed; without a terminal to ask in, that takes --yes.
+func runBulk(f *cmdutil.Factory, runs []api.Build, confirm, yes bool, action bulkAction, act func(api.Build) error) error {
+	p := f.Printer
+	if len(runs) == 0 {
+		p.Empty("No runs match", "Check the filters with 'teamcity run list'")
+		return nil
+	}
+	if confirm {
+		printBulkPlan(p, runs)
+		if !yes && !f.IsDryRun() {
+			if !f.IsInteractive() {
+				return api.Validation(fmt.Sprintf("changing %s needs confirmation, and there is no terminal to ask in", english.Plural(len(runs), "run", "")),
+					"Add --yes to go ahead without asking, or --dry-run to preview")
+			}
+			var ok bool
+			if err := cmdutil.Confirm(fmt.Sprintf("%s %s?", action.title, english.Plural(len(runs), "run", "")), &ok); err != nil {
+				return err
+			}
+			if !ok {
+				p.Info("Canceled")
+				return nil
+			}
+		}
+	}
+
+	var failed []string
+	for _, r := range runs {
+		if err := act(r); err != nil {
+			p.Warn("Failed to %s #%d: %v", action.verb, r.ID, err)
+			failed = append(failed, fmt.Sprintf("#%d", r.ID))
+			continue
+		}
+		p.Success("%s #%d", action.done, r.ID)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to %s %d of %s: %s", action.verb, len(failed), english.Plural(len(runs), "run", ""), strings.Join(failed, ", "))
+	}
+	if len(runs) > 1 {
+		p.Success("%s %s", action.done, english.Plural(len(runs), "run", ""))
+	}
+	return nil
+}
+
+// printBulkPlan lists the runs filters picked, before they are acted on.
+func printBulkPlan(p *output.Printer, runs []api.Build) {
+	headers := []string{"ID", "JOB", "BRANCH", "STATUS", "FINISHED"}
+	rows := make([][]string, 0, len(runs))
+	for _, r := range runs {
+		finished := "-"
+		if t, err := api.ParseTeamCityTime(r.FinishDate); err == nil {
+			finished = output.RelativeTime(t)
+		}
+		rows = append(rows, []string{
+			strings.TrimSpace(fmt.Sprintf("%d  %s", r.ID, cmdutil.RunNumber(r.Number))),
+			cmp.Or(r.BuildTypeID, "-"),
+			cmp.Or(r.BranchName, "-"),
+			output.PlainStatusText(r.Status, r.State, r.StatusText),
+			finished,
+		})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1, 2)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
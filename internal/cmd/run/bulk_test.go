@@ -0,0 +1,92 @@
+package run_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPinBulkFilters(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var locator string
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator = r.URL.Query().Get("locator")
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{
+			{ID: 11, Number: "41", BuildTypeID: "Falcon_Build", BranchName: "release/2024.3", Status: "SUCCESS", State: "finished"},
+			{ID: 12, Number: "42", BuildTypeID: "Falcon_Build", BranchName: "release/2024.3", Status: "SUCCESS", State: "finished"},
+		}})
+	})
+	var mu sync.Mutex
+	pinned := map[string]string{}
+	ts.Handle("PUT /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		pinned[r.URL.Path] = string(body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "changing 2 runs needs confirmation",
+		"run", "pin", "--job", "Falcon_Build", "--status", "success")
+	assert.Empty(T, pinned)
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "pin", "--job", "Falcon_Build", "--branch", "release/2024.3",
+		"--status", "success", "--since", "14d", "--comment", "2024.3 RC", "--yes")
+	assert.Contains(T, locator, "buildType:Falcon_Build")
+	assert.Contains(T, locator, "branch:release/2024.3")
+	assert.Contains(T, locator, "status:SUCCESS")
+	assert.Contains(T, locator, "sinceDate:")
+	assert.Equal(T, map[string]string{
+		"/app/rest/builds/id:11/pin": "2024.3 RC",
+		"/app/rest/builds/id:12/pin": "2024.3 RC",
+	}, pinned)
+	assert.Contains(T, out, "Pinned #11")
+	assert.Contains(T, out, "Pinned #12")
+	assert.Contains(T, out, "Pinned 2 runs")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "unknown command", "run", "pin", "12345", "--status", "success")
+}
+
+func TestRunTagBulkStdin(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var mu sync.Mutex
+	tagged := map[string][]string{}
+	ts.Handle("POST /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "id:13/") {
+			cmdtest.Error(w, http.StatusForbidden, "You do not have enough permissions to tag this build")
+			return
+		}
+		var tags api.TagList
+		_ = json.NewDecoder(r.Body).Decode(&tags)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, t := range tags.Tag {
+			tagged[r.URL.Path] = append(tagged[r.URL.Path], t.Name)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ts.Factory.IOStreams.In = strings.NewReader("11\n\n12\n13\n")
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "tag", "-", "release-2024.3")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "failed to tag 1 of 3 runs: #13")
+	assert.Equal(T, map[string][]string{
+		"/app/rest/builds/id:11/tags": {"release-2024.3"},
+		"/app/rest/builds/id:12/tags": {"release-2024.3"},
+	}, tagged)
+
+	ts.Factory.IOStreams.In = strings.NewReader("11\nFalcon_Build\n")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `stdin line 2: "Falcon_Build" is not a run ID`, "run", "tag", "-", "qa")
+
+	ts.Factory.IOStreams.In = strings.NewReader("11\n")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "cannot read run IDs from stdin and select runs with filters",
+		"run", "tag", "-", "qa", "--status", "success")
+}
@@ -8,8 +8,11 @@ import (
 )
 
 type runCancelOptions struct {
-	comment string
-	yes     bool
+	job       string
+	comment   string
+	yes       bool
+	withChain bool
+	force     bool
 }
 
 func newRunCancelCmd(f *cmdutil.Factory) *cobra.Command {
@@ -22,11 +25,20 @@ func newRunCancelCmd(f *cmdutil.Factory) *cobra.Command {
 
 Prompts for confirmation when run interactively without --yes or
 --comment. The cancellation comment is stored on the run and shown
-in the TeamCity UI.`,
+in the TeamCity UI.
+
+With --with-chain, also cancels the queued and running snapshot
+dependencies of the run, newest first, so a canceled composite run
+doesn't leave its parts holding agents. Dependencies that another
+queued or running build still needs are kept, with the reason shown.
+The whole plan is listed for confirmation first; --force skips it, and is
+needed when there is no terminal to confirm in.`,
 		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run cancel 12345
   teamcity run cancel 12345 --comment "Canceling for hotfix"
-  teamcity run cancel 12345 --yes`,
+  teamcity run cancel 12345 --yes
+  teamcity run cancel 12345 --with-chain
+  teamcity run cancel 12345 --with-chain --force`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRunCancel(f, args[0], opts)
 		},
@@ -34,6 +46,9 @@ in the TeamCity UI.`,
 
 	cmd.Flags().StringVarP(&opts.comment, "comment", "m", "", "Comment for cancellation")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.withChain, "with-chain", false, "Also cancel the run's queued and running snapshot dependencies")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "With --with-chain, cancel the chain without confirmation")
+	addRunJobFlag(cmd, &opts.job)
 
 	return cmd
 }
@@ -44,6 +59,20 @@ func runRunCancel(f *cmdutil.Factory, runID string, opts *runCancelOptions) erro
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
+	comment := opts.comment
+	if comment == "" {
+		comment = "Canceled via teamcity CLI"
+	}
+
+	if opts.withChain {
+		return runCancelChain(f, client, runID, comment, opts)
+	}
+
 	needsConfirmation := !opts.yes && opts.comment == "" && f.IsInteractive()
 
 	if needsConfirmation {
@@ -57,11 +86,6 @@ func runRunCancel(f *cmdutil.Factory, runID string, opts *runCancelOptions) erro
 		}
 	}
 
-	comment := opts.comment
-	if comment == "" {
-		comment = "Canceled via teamcity CLI"
-	}
-
 	if err := client.CancelBuild(runID, comment); err != nil {
 		return err
 	}
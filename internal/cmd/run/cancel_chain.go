@@ -0,0 +1,167 @@
+package run
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+)
+
+// chainPlan is what cancel --with-chain does: the runs it cancels, newest first, and the dependencies it keeps.
+type chainPlan struct {
+	Cancel []api.Build
+	Kept   []keptDependency
+}
+
+// keptDependency is a chain dependency left alone because a build outside the cancellation still needs it.
+type keptDependency struct {
+	Build  api.Build
+	Reason string
+}
+
+func runCancelChain(f *cmdutil.Factory, client api.ClientInterface, runID, comment string, opts *runCancelOptions) error {
+	p := f.Printer
+	root, err := client.GetBuild(f.Context(), runID)
+	if err != nil {
+		return err
+	}
+	if root.State == "finished" {
+		return api.Validation(fmt.Sprintf("run #%s has already finished", runID), "Only queued and running runs can be canceled")
+	}
+
+	plan, err := planCancelChain(f, client, *root)
+	if err != nil {
+		return fmt.Errorf("failed to discover the dependency chain of #%s: %w", runID, err)
+	}
+	printChainPlan(p, plan)
+
+	if !opts.yes && !opts.force && !f.IsDryRun() {
+		if !f.IsInteractive() {
+			return api.Validation(fmt.Sprintf("canceling %s needs confirmation, and there is no terminal to ask in", english.Plural(len(plan.Cancel), "run", "")),
+				"Add --force to cancel the chain without asking, or --dry-run to preview")
+		}
+		var confirm bool
+		if err := cmdutil.Confirm(fmt.Sprintf("Cancel %s?", english.Plural(len(plan.Cancel), "run", "")), &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			p.Info("Canceled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, b := range plan.Cancel {
+		job := cmdutil.JobName(b.BuildType, b.BuildTypeID)
+		if err := client.CancelBuild(strconv.Itoa(b.ID), comment); err != nil {
+			failed++
+			p.Warn("failed to cancel #%d (%s): %s", b.ID, job, err)
+			continue
+		}
+		if b.State == "queued" {
+			p.Success("Removed #%d (%s) from queue", b.ID, job)
+		} else {
+			p.Success("Canceled #%d (%s)", b.ID, job)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to cancel %d of %d runs", failed, len(plan.Cancel))
+	}
+	return nil
+}
+
+// planCancelChain walks root's snapshot dependencies to every queued or running build of its chain, then keeps
+// each dependency that a queued or running build outside the cancellation also depends on. Keeping one keeps
+// what it depends on too, so the check repeats until nothing more is kept.
+func planCancelChain(f *cmdutil.Factory, client api.ClientInterface, root api.Build) (chainPlan, error) {
+	chain := map[int]api.Build{}
+	pending := []int{root.ID}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		deps, err := client.GetBuildSnapshotDependencies(strconv.Itoa(id))
+		if err != nil {
+			return chainPlan{}, err
+		}
+		for _, d := range deps.Builds {
+			if _, seen := chain[d.ID]; seen || d.ID == root.ID || d.State == "finished" {
+				continue
+			}
+			chain[d.ID] = d
+			pending = append(pending, d.ID)
+		}
+	}
+
+	ids := slices.Sorted(maps.Keys(chain))
+	dependents := make(map[int][]api.Build, len(chain))
+	for _, id := range ids {
+		builds, err := client.GetBuildDependents(f.Context(), strconv.Itoa(id))
+		if err != nil {
+			return chainPlan{}, err
+		}
+		dependents[id] = builds
+	}
+
+	canceled := map[int]bool{root.ID: true}
+	for _, id := range ids {
+		canceled[id] = true
+	}
+	reasons := map[int]string{}
+	for changed := true; changed; {
+		changed = false
+		for _, id := range ids {
+			if !canceled[id] {
+				continue
+			}
+			for _, d := range dependents[id] {
+				if d.State == "finished" || canceled[d.ID] {
+					continue
+				}
+				canceled[id] = false
+				reasons[id] = fmt.Sprintf("also needed by %s %d (%s)", d.State, d.ID, cmdutil.JobName(d.BuildType, d.BuildTypeID))
+				changed = true
+				break
+			}
+		}
+	}
+
+	plan := chainPlan{Cancel: []api.Build{root}}
+	for _, id := range ids {
+		if canceled[id] {
+			plan.Cancel = append(plan.Cancel, chain[id])
+		} else {
+			plan.Kept = append(plan.Kept, keptDependency{Build: chain[id], Reason: reasons[id]})
+		}
+	}
+	newestFirst := func(a, b api.Build) int { return cmp.Compare(b.ID, a.ID) }
+	slices.SortFunc(plan.Cancel, newestFirst)
+	slices.SortFunc(plan.Kept, func(a, b keptDependency) int { return newestFirst(a.Build, b.Build) })
+	return plan, nil
+}
+
+// printChainPlan lists every build of the chain with whether cancel removes, cancels, or keeps it.
+func printChainPlan(p *output.Printer, plan chainPlan) {
+	headers := []string{"ID", "JOB", "STATE", "ACTION"}
+	var rows [][]string
+	for _, b := range plan.Cancel {
+		action := "cancel"
+		if b.State == "queued" {
+			action = "remove"
+		}
+		rows = append(rows, []string{strconv.Itoa(b.ID), cmdutil.JobName(b.BuildType, b.BuildTypeID), b.State, output.Red(action)})
+	}
+	for _, k := range plan.Kept {
+		b := k.Build
+		rows = append(rows, []string{strconv.Itoa(b.ID), cmdutil.JobName(b.BuildType, b.BuildTypeID), b.State, output.Green("keep") + " " + output.Faint(k.Reason)})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 1, 3)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+}
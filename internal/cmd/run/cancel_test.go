@@ -0,0 +1,140 @@
+package run_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+// setupCancelChain serves composite run 100 and its chain:
+//
+//	100 running  Deploy    -> 101, 102, 103
+//	101 queued   Test      -> 104, 105
+//	102 running  Compile
+//	103 finished Lint
+//	104 queued   Package   -> 106 (also a dependency of queued 200, another composite)
+//	105 queued   Docs
+//	106 queued   Fetch
+//
+// It returns the IDs cancel removed or canceled, in order.
+func setupCancelChain(t *testing.T) (*cmdtest.TestServer, *[]string) {
+	t.Helper()
+	build := func(id int, state, job string) api.Build {
+		return api.Build{ID: id, State: state, BuildTypeID: job, BuildType: &api.BuildType{ID: job, Name: job}}
+	}
+	builds := map[string]api.Build{
+		"100": build(100, "running", "Deploy"),
+		"101": build(101, "queued", "Test"),
+		"102": build(102, "running", "Compile"),
+		"103": build(103, "finished", "Lint"),
+		"104": build(104, "queued", "Package"),
+		"105": build(105, "queued", "Docs"),
+		"106": build(106, "queued", "Fetch"),
+		"200": build(200, "queued", "Release"),
+	}
+	dependencies := map[string][]string{
+		"100": {"101", "102", "103"},
+		"101": {"104", "105"},
+		"104": {"106"},
+		"200": {"104"},
+	}
+
+	list := func(ids []string) api.BuildList {
+		out := api.BuildList{Builds: []api.Build{}}
+		for _, id := range ids {
+			out.Builds = append(out.Builds, builds[id])
+		}
+		out.Count = len(out.Builds)
+		return out
+	}
+
+	var mu sync.Mutex
+	var canceled []string
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		for id, deps := range dependencies {
+			if strings.Contains(locator, "snapshotDependency:(to:(id:"+id+")") {
+				cmdtest.JSON(w, list(deps))
+				return
+			}
+		}
+		for id := range builds {
+			if strings.Contains(locator, "snapshotDependency:(from:(id:"+id+")") {
+				var dependents []string
+				for parent, deps := range dependencies {
+					for _, d := range deps {
+						if d == id {
+							dependents = append(dependents, parent)
+						}
+					}
+				}
+				cmdtest.JSON(w, list(dependents))
+				return
+			}
+		}
+		cmdtest.JSON(w, list(nil))
+	})
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, builds[cmdtest.ExtractID(r.URL.Path, "id:")])
+	})
+	record := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		canceled = append(canceled, cmdtest.ExtractID(r.URL.Path, "id:"))
+		w.WriteHeader(http.StatusOK)
+	}
+	ts.Handle("POST /app/rest/builds/id:", record)
+	ts.Handle("DELETE /app/rest/buildQueue/id:", record)
+	return ts, &canceled
+}
+
+func TestRunCancelWithChain(t *testing.T) {
+	ts, canceled := setupCancelChain(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "cancel", "100", "--with-chain", "--force")
+
+	assert.Equal(t, []string{"105", "102", "101", "100"}, *canceled, "the chain is canceled newest first, shared builds kept")
+	assert.Regexp(t, `105\s+Docs\s+queued\s+remove`, got)
+	assert.Regexp(t, `102\s+Compile\s+running\s+cancel`, got)
+	assert.Regexp(t, `104\s+Package\s+queued\s+keep also needed by queued 200 \(Release\)`, got)
+	assert.Regexp(t, `106\s+Fetch\s+queued\s+keep also needed by queued 104 \(Package\)`, got, "dependencies of a kept build are kept too")
+	assert.NotContains(t, got, "Lint", "finished dependencies are not part of the plan")
+	assert.Contains(t, got, "Removed #105 (Docs) from queue")
+	assert.Contains(t, got, "Canceled #100 (Deploy)")
+}
+
+func TestRunCancelWithChainFailures(t *testing.T) {
+	ts, canceled := setupCancelChain(t)
+	ts.Handle("DELETE /app/rest/buildQueue/id:105", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.Error(w, http.StatusForbidden, "no permission")
+	})
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "run", "cancel", "100", "--with-chain", "--force")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to cancel 1 of 4 runs")
+	assert.Equal(t, []string{"102", "101", "100"}, *canceled, "one failure does not stop the rest")
+}
+
+func TestRunCancelWithChainNonInteractive(t *testing.T) {
+	ts, canceled := setupCancelChain(t)
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "run", "cancel", "100", "--with-chain")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "canceling 4 runs needs confirmation, and there is no terminal to ask in")
+	assert.Empty(t, *canceled, "nothing is canceled without --force")
+	assert.Contains(t, ts.Factory.Printer.Out.(*bytes.Buffer).String(), "Deploy", "the plan is still shown")
+}
+
+func TestRunCancelWithChainFinished(t *testing.T) {
+	ts, _ := setupCancelChain(t)
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "run #103 has already finished", "run", "cancel", "103", "--with-chain", "--force")
+}
@@ -2,12 +2,22 @@ package run_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +25,7 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmd"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 )
@@ -38,17 +49,97 @@ func TestRunList(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "run", "list", "--json", "--limit", "2")
 }
 
+func TestRunListTemplate(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var fields string
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{
+			{ID: 2, Status: "FAILURE", State: "finished", BranchName: "main"},
+			{ID: 1, Status: "SUCCESS", State: "finished", BranchName: "feature/a-long-name"},
+		}})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--template", "{{.ID}} {{.Status}} {{truncate 9 .BranchName}}", "--sort", "none")
+	assert.Equal(T, "2 FAILURE main\n1 SUCCESS featur...\n", got)
+	assert.Contains(T, fields, "waitReason", "templates can use any field --json offers")
+
+	fields = ""
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "invalid --template: unclosed action", "run", "list", "--template", "{{.ID")
+	assert.Empty(T, fields, "a broken template fails before any request")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "none of the others can be", "run", "list", "--template", "{{.ID}}", "--json")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "none of the others can be", "run", "list", "--template", "{{.ID}}", "--plain")
+}
+
+func TestRunViewTemplate(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "view", testBuildID, "--template", "{{.ID}} {{.BuildTypeID}}")
+	assert.Equal(T, "1 "+testJob+"\n", got)
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "can't evaluate field Nope", "run", "view", testBuildID, "--template", "{{.Nope}}")
+}
+
 func TestRunListBackwardsDateRange(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
 	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "is more recent than", "run", "list", "--since", "2020-01-01", "--until", "2019-01-01")
 }
 
+func TestRunListDateRange(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	var locator string
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator = r.URL.Query().Get("locator")
+		cmdtest.JSON(w, api.BuildList{Count: 1, Builds: []api.Build{{ID: 7, BuildTypeID: "Falcon_Build", State: "finished", Status: "FAILURE"}}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--job", "Falcon_Build", "--status", "failure", "--branch", "main",
+		"--since", "2025-01-01T00:00", "--until", "2025-01-08", "--json=id,status")
+	assert.Contains(T, locator, "sinceDate:20250101T000000+0000")
+	assert.Contains(T, locator, "untilDate:20250108T000000+0000")
+	assert.Contains(T, locator, "status:FAILURE")
+	assert.Contains(T, locator, "branch:main")
+	assert.Contains(T, out, `"id": 7`)
+
+	cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--job", "Falcon_Build", "--since", "30m", "--limit", "1")
+	since, _, _ := strings.Cut(strings.SplitN(locator, "sinceDate:", 2)[1], ",")
+	sinceTime, err := api.ParseTeamCityTime(since)
+	require.NoError(T, err)
+	assert.WithinDuration(T, time.Now().Add(-30*time.Minute), sinceTime, time.Minute)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "invalid --since date", "run", "list", "--since", "last tuesday")
+}
+
+func TestRunListDurationFilter(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, map[string]any{"count": 3, "build": []map[string]any{
+			{"id": 1, "buildTypeId": "Quick", "state": "finished", "startDate": "20260101T100000+0000", "finishDate": "20260101T100200+0000"},
+			{"id": 2, "buildTypeId": "Slow", "state": "finished", "startDate": "20260101T100000+0000", "finishDate": "20260101T110000+0000"},
+			{"id": 3, "buildTypeId": "Stuck", "state": "running", "startDate": "20260101T100000+0000"},
+		}})
+	})
+
+	stdout, _ := runListSplit(T, ts, "run", "list", "--longer-than", "30m", "--plain", "--no-header")
+	assert.Contains(T, stdout, "Slow")
+	assert.NotContains(T, stdout, "Quick")
+	assert.NotContains(T, stdout, "Stuck", "running runs need --include-running")
+
+	stdout, _ = runListSplit(T, ts, "run", "list", "--longer-than", "30m", "--include-running", "--json=id")
+	var list api.BuildList
+	require.NoError(T, json.Unmarshal([]byte(stdout), &list))
+	require.Equal(T, 2, list.Count)
+	assert.Equal(T, []int{3, 2}, []int{list.Builds[0].ID, list.Builds[1].ID}, "running runs sort first by age")
+	assert.Empty(T, list.Builds[0].StartDate, "dates fetched for the filter are not printed")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "invalid --shorter-than", "run", "list", "--shorter-than", "quick")
+}
+
 func runListSplit(t *testing.T, ts *cmdtest.TestServer, args ...string) (stdout, stderr string) {
 	t.Helper()
 	var out, errBuf bytes.Buffer
 	f := ts.CloneFactory()
-	// NewCommand strips PersistentPreRun (and thus InitOutput), so mirror the
+	// NewCommand skips InitOutput in PersistentPreRun, so mirror the
 	// --quiet → Printer.Quiet wiring that production does at runtime.
 	f.Printer = &output.Printer{Out: &out, ErrOut: &errBuf, Quiet: slices.Contains(args, "--quiet") || slices.Contains(args, "-q")}
 	rootCmd := cmd.NewCommand(f)
@@ -119,6 +210,43 @@ func TestRunListTruncationHint(T *testing.T) {
 	})
 }
 
+func TestRunListInterrupted(T *testing.T) {
+	for _, args := range [][]string{{"run", "list", "--limit", "0"}, {"run", "list", "--limit", "0", "--json"}} {
+		T.Run(strings.Join(args[2:], " "), func(t *testing.T) {
+			ts := cmdtest.NewTestServer(t)
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Query().Get("locator"), "start:") {
+					cancel() // Ctrl-C while the second page is in flight
+					<-r.Context().Done()
+					return
+				}
+				cmdtest.JSON(w, map[string]any{
+					"count":    2,
+					"nextHref": "/app/rest/builds?locator=count:2,start:2",
+					"build":    []map[string]any{{"id": 101, "buildTypeId": "B"}, {"id": 102, "buildTypeId": "B"}},
+				})
+			})
+
+			var out, errBuf bytes.Buffer
+			f := ts.CloneFactory()
+			f.SetContext(ctx)
+			f.Printer = &output.Printer{Out: &out, ErrOut: &errBuf}
+			rootCmd := cmd.NewCommand(f)
+			rootCmd.SetArgs(args)
+			rootCmd.SilenceErrors, rootCmd.SilenceUsage = true, true
+			exitErr, ok := errors.AsType[*cmdutil.ExitError](rootCmd.Execute())
+			require.True(t, ok, "an interrupted list exits as cancelled")
+			assert.Equal(t, cmdutil.ExitCancelled, exitErr.Code)
+
+			assert.Contains(t, out.String(), "101")
+			assert.Contains(t, out.String(), "102")
+			assert.Contains(t, errBuf.String(), "Interrupted - showing the 2 runs fetched so far")
+		})
+	}
+}
+
 func TestRunListWeb(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 
@@ -143,12 +271,194 @@ func TestRunView(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "run", "view", testBuildID, "--json")
 }
 
+func TestRunViewCopy(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var copied []string
+	orig := cmdutil.CopyToClipboard
+	T.Cleanup(func() { cmdutil.CopyToClipboard = orig })
+	cmdutil.CopyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "view", testBuildID, "--copy")
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "view", testBuildID, "--copy=id", "--json")
+
+	assert.Equal(T, []string{ts.URL + "/viewLog.html?buildId=1", "1"}, copied)
+}
+
+// setupBuildNumberServer serves run 12345, which is #482 of Falcon_Build; no run has ID 482.
+func setupBuildNumberServer(t *testing.T) *cmdtest.TestServer {
+	t.Helper()
+	ts := cmdtest.NewTestServer(t)
+
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		if cmdtest.ExtractID(r.URL.Path, "/app/rest/builds/id:") != "12345" {
+			cmdtest.Error(w, http.StatusNotFound, "No build found by locator 'id:482'")
+			return
+		}
+		cmdtest.JSON(w, api.Build{ID: 12345, Number: "482", BuildTypeID: "Falcon_Build", State: "finished", Status: "SUCCESS"})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		if !strings.Contains(locator, "number:482") || !strings.Contains(locator, "buildType:Falcon_Build") {
+			cmdtest.JSON(w, api.BuildList{})
+			return
+		}
+		cmdtest.JSON(w, api.BuildList{Count: 1, Builds: []api.Build{{ID: 12345, Number: "482", BuildTypeID: "Falcon_Build"}}})
+	})
+
+	config.SetUserForServer(ts.URL, "admin")
+	return ts
+}
+
+func TestRunViewBuildNumberFallback(T *testing.T) {
+	ts := setupBuildNumberServer(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "view", "482", "--job", "Falcon_Build", "--json")
+	assert.Contains(T, got, "No run has ID 482; using #482 of Falcon_Build (ID 12345)")
+	assert.Contains(T, got, `"id": 12345`)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "view", "#482", "--job", "Falcon_Build", "--json")
+	assert.NotContains(T, got, "No run has ID")
+	assert.Contains(T, got, `"id": 12345`)
+
+	T.Setenv("TEAMCITY_JOB", "Falcon_Build")
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "view", "482")
+	assert.Contains(T, got, "using #482 of Falcon_Build", "TEAMCITY_JOB is a job context too")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "no run has ID 483, and job Falcon_Build has no run #483", "run", "view", "483")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "job Falcon_Build has no run #483", "run", "view", "#483")
+}
+
+func TestRunViewCanceled(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 12345, Number: "482", BuildTypeID: "Falcon_Build", State: "finished", Status: "UNKNOWN",
+			CanceledInfo: &api.CanceledInfo{User: &api.User{Username: "jdoe"}, Text: "Superseded by #483"}})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "view", "12345")
+	assert.Contains(T, got, "Canceled by jdoe")
+	assert.Contains(T, got, "Comment: Superseded by #483")
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "view", "12345", "--json")
+	assert.Contains(T, got, `"canceledInfo"`)
+}
+
+func TestRunViewBuildNumberWithoutJob(T *testing.T) {
+	ts := setupBuildNumberServer(T)
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "view", "482")
+	_, msg, tip := output.ClassifyError(err)
+	assert.Equal(T, `run "482" not found`, msg)
+	assert.Equal(T, "If 482 is a build number rather than an ID, use '#482 --job <id>'", tip)
+}
+
+func TestRunViewWebURL(T *testing.T) {
+	ts := setupBuildNumberServer(T)
+
+	for _, ref := range []string{
+		ts.URL + "/viewLog.html?buildId=12345&buildTypeId=Falcon_Build",
+		ts.URL + "/buildConfiguration/Falcon_Build/12345?buildTab=tests",
+	} {
+		got := cmdtest.CaptureOutput(T, ts.Factory, "run", "view", ref, "--json")
+		assert.Contains(T, got, `"id": 12345`, ref)
+	}
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "pin", ts.URL+"/build/12345", "--dry-run")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "does not link to a run", "run", "view", ts.URL+"/project/Falcon")
+}
+
 func TestRunStart(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "start", testJob, "--comment", "CLI test")
 }
 
+func TestRunStartWaitQueue(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var polls atomic.Int32
+	ts.Handle("GET /app/rest/builds/id:100", func(w http.ResponseWriter, r *http.Request) {
+		build := api.Build{ID: 100, Number: "100", BuildTypeID: testJob, State: "queued", WaitReason: "Waiting for compatible agent",
+			QueuedDate: "20260101T120000+0000"}
+		if polls.Add(1) > 1 {
+			build.State = "running"
+			build.StartDate = "20260101T120230+0000"
+			build.Agent = &api.Agent{Name: "Agent-Linux-01"}
+		}
+		cmdtest.JSON(w, build)
+	})
+	ts.Handle("GET /app/rest/buildQueue/id:100/approval", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ApprovalInfo{Status: "waitingForApproval", UserApprovalRule: &api.UserApprovalRule{
+			UserApprovals: &api.UserApprovals{UserApproval: []api.UserApproval{
+				{User: &api.User{Name: "Alice"}, Approved: true},
+				{User: &api.User{Username: "bob"}},
+			}},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--wait-queue", "--interval", "1")
+	assert.Contains(T, out, "Queued · Waiting for compatible agent")
+	assert.Contains(T, out, "Waiting for approval from bob")
+	assert.Contains(T, out, "Approver runs: teamcity queue approve 100")
+	assert.Contains(T, out, "Started on Agent-Linux-01 after 2m 30s in the queue")
+	assert.Equal(T, int32(2), polls.Load())
+
+	ts.Handle("GET /app/rest/builds/id:100", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 100, Number: "100", BuildTypeID: testJob, State: "finished", Status: "UNKNOWN",
+			CanceledInfo: &api.CanceledInfo{Text: "Removed from the queue"}})
+	})
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", testJob, "--wait-queue")
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(T, err, &exitErr)
+	assert.Equal(T, cmdutil.ExitCancelled, exitErr.Code, "a run removed from the queue ends the wait as canceled")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "[wait-queue follow-deps] are set none of the others can be",
+		"run", "start", testJob, "--wait-queue", "--follow-deps")
+}
+
+func TestRunStartLocalChangesBinary(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var uploaded string
+	ts.Handle("POST /uploadDiffChanges.html", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded = string(body)
+		_, _ = w.Write([]byte("4711"))
+	})
+	patch := cmdtest.Dedent(`
+		diff --git a/main.go b/main.go
+		--- a/main.go
+		+++ b/main.go
+		@@ -1 +1 @@
+		-package main
+		+package main // edited
+		diff --git a/logo.png b/logo.png
+		index 1b2c3d4..5e6f7a8 100644
+		Binary files a/logo.png and b/logo.png differ
+	`)
+	path := filepath.Join(T.TempDir(), "changes.patch")
+	require.NoError(T, os.WriteFile(path, []byte(patch), 0o644))
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--local-changes="+path, "--branch", "main", "--no-push")
+	assert.Contains(T, out, "The patch leaves out the contents of 1 binary file")
+	assert.Contains(T, out, ": logo.png")
+	assert.Contains(T, out, "Uploaded changes (ID: 4711)")
+	assert.Equal(T, patch, uploaded)
+}
+
+func TestRunStartCopy(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var copied []string
+	orig := cmdutil.CopyToClipboard
+	T.Cleanup(func() { cmdutil.CopyToClipboard = orig })
+	cmdutil.CopyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--copy=id")
+	assert.Contains(T, out, "Copied 100 to clipboard")
+	assert.Equal(T, []string{"100"}, copied)
+
+	cmdutil.CopyToClipboard = func(string) error { return errors.New("no clipboard available") }
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--copy")
+	assert.Contains(T, out, "could not copy to clipboard", "a failed copy must not fail the started run")
+}
+
 func TestRunStartWithOptions(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
@@ -223,6 +533,196 @@ func TestRunStartReuseDepsSendsIDs(T *testing.T) {
 	assert.Equal(T, 6917, captured.SnapshotDependencies.Build[1].ID)
 }
 
+// handleArtifactFrom registers an artifact dependency of testJob on Falcon_Lib and two runs: 12345 of Falcon_Lib and 777 of another job.
+func handleArtifactFrom(ts *cmdtest.TestServer) {
+	ts.Handle("GET /app/rest/buildTypes/id:"+testJob+"/artifact-dependencies", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ArtifactDependencyList{Count: 1, ArtifactDependency: []api.ArtifactDependency{
+			{ID: "ARTIFACT_DEPENDENCY_1", SourceBuildType: &api.BuildType{ID: "Falcon_Lib"},
+				Properties: &api.PropertyList{Property: []api.Property{{Name: "pathRules", Value: "lib.jar"}}}},
+		}})
+	})
+	ts.Handle("GET /app/rest/builds/id:12345", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 12345, Number: "88", Status: "SUCCESS", BuildTypeID: "Falcon_Lib"})
+	})
+	ts.Handle("GET /app/rest/builds/id:777", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 777, Number: "3", Status: "SUCCESS", BuildTypeID: "Other_Build"})
+	})
+}
+
+func TestRunStartArtifactFromDryRun(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleArtifactFrom(ts)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--artifact-from", "Falcon_Lib:12345", "--dry-run")
+	assert.Contains(T, got, "Artifacts from:")
+	assert.Contains(T, got, "Falcon_Lib  12345  #88")
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "--artifact-from", "Falcon_Lib:12345", "--dry-run", "--json")
+	var plan struct {
+		ArtifactFrom []struct {
+			Job    string `json:"job"`
+			RunID  int    `json:"run_id"`
+			Number string `json:"number"`
+		} `json:"artifact_from"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(got), &plan))
+	require.Len(T, plan.ArtifactFrom, 1)
+	assert.Equal(T, "Falcon_Lib", plan.ArtifactFrom[0].Job)
+	assert.Equal(T, 12345, plan.ArtifactFrom[0].RunID)
+}
+
+func TestRunStartArtifactFromSendsOverride(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleArtifactFrom(ts)
+	depRequests := 0
+	ts.Handle("GET /app/rest/buildTypes/id:"+testJob+"/artifact-dependencies", func(w http.ResponseWriter, r *http.Request) {
+		depRequests++
+		cmdtest.JSON(w, api.ArtifactDependencyList{Count: 1, ArtifactDependency: []api.ArtifactDependency{
+			{ID: "ARTIFACT_DEPENDENCY_1", SourceBuildType: &api.BuildType{ID: "Falcon_Lib"}},
+		}})
+	})
+
+	var captured api.TriggerBuildRequest
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(T, json.Unmarshal(body, &captured))
+		cmdtest.JSON(w, api.Build{ID: 999, BuildTypeID: testJob, WebURL: "https://example/build/999"})
+	})
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "start", testJob, "--artifact-from", "Falcon_Lib:12345")
+
+	require.NotNil(T, captured.CustomArtifactDependencies)
+	require.Len(T, captured.CustomArtifactDependencies.ArtifactDependency, 1)
+	assert.Contains(T, captured.CustomArtifactDependencies.ArtifactDependency[0].Properties.Property,
+		api.Property{Name: "revisionValue", Value: "12345"})
+	assert.Equal(T, 1, depRequests, "the job's artifact dependencies are fetched once")
+}
+
+func TestRunStartArtifactFromErrors(T *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{"malformed", "Falcon_Lib", "invalid --artifact-from"},
+		{"non-numeric run", "Falcon_Lib:abc", "invalid --artifact-from"},
+		{"no dependency", "Other_Build:777", "has no artifact dependency on Other_Build"},
+		{"wrong job", "Falcon_Lib:777", "run 777 belongs to Other_Build, not Falcon_Lib"},
+		{"missing run", "Falcon_Lib:404", "run 404 not found"},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(T *testing.T) {
+			ts := cmdtest.SetupMockClient(T)
+			handleArtifactFrom(ts)
+			ts.Handle("GET /app/rest/builds/id:404", func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.Error(w, http.StatusNotFound, "No build found by locator 'id:404'")
+			})
+			posted := false
+			ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+				posted = true
+				cmdtest.JSON(w, api.Build{ID: 999})
+			})
+
+			err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", testJob, "--artifact-from", tc.arg)
+			assert.Contains(T, err.Error(), tc.want)
+			assert.False(T, posted, "nothing may be queued when an override is invalid")
+		})
+	}
+}
+
+// handleRepoJobs serves two VCS roots: App_Git over ssh with App_Build and a paused App_Old, and App_Https over https
+// with App_Build and App_Deploy; Other_Git matches the path fragment but is a different host.
+func handleRepoJobs(ts *cmdtest.TestServer) {
+	root := func(id, url string) api.VcsRoot {
+		return api.VcsRoot{ID: id, Properties: &api.PropertyList{Property: []api.Property{{Name: "url", Value: url}}}}
+	}
+	ts.Handle("GET /app/rest/vcs-roots", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.VcsRootList{Count: 3, VcsRoot: []api.VcsRoot{
+			root("App_Git", "git@github.com:acme/app.git"),
+			root("App_Https", "https://github.com/acme/app"),
+			root("Other_Git", "git@gitlab.example.com:acme/app.git"),
+		}})
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		var bts []api.BuildType
+		switch loc := r.URL.Query().Get("locator"); {
+		case strings.Contains(loc, "id:App_Git"):
+			bts = []api.BuildType{{ID: "App_Build", Name: "Build", ProjectName: "App"}, {ID: "App_Old", Name: "Old", Paused: true}}
+		case strings.Contains(loc, "id:App_Https"):
+			bts = []api.BuildType{{ID: "App_Build", Name: "Build", ProjectName: "App"}, {ID: "App_Deploy", Name: "Deploy", ProjectName: "App"}}
+		case strings.Contains(loc, "id:Other_Git"):
+			bts = []api.BuildType{{ID: "Other_Build"}}
+		}
+		cmdtest.JSON(w, api.BuildTypeList{Count: len(bts), BuildTypes: bts})
+	})
+}
+
+func TestRunStartRepo(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleRepoJobs(ts)
+	var queued []string
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		var req api.TriggerBuildRequest
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(T, json.Unmarshal(body, &req))
+		queued = append(queued, req.BuildType.ID)
+		cmdtest.JSON(w, api.Build{ID: 1000 + len(queued), BuildTypeID: req.BuildType.ID})
+	})
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", "--repo", "https://github.com/acme/app.git")
+	assert.Contains(T, err.Error(), "2 jobs use repository")
+	assert.Contains(T, err.Error(), "App · Build (App_Build)")
+	assert.Contains(T, err.Error(), "App · Deploy (App_Deploy)")
+	assert.NotContains(T, err.Error(), "App_Old", "paused jobs are not candidates")
+	assert.NotContains(T, err.Error(), "Other_Build", "same path on another host must not match")
+	assert.Empty(T, queued)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", "--repo", "ssh://git@github.com/acme/app", "--all-matching", "--json")
+	var builds []api.Build
+	require.NoError(T, json.Unmarshal([]byte(got), &builds))
+	assert.Len(T, builds, 2)
+	assert.Equal(T, []string{"App_Build", "App_Deploy"}, queued)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "start", "--repo", "git@github.com:acme/app.git", "--all-matching", "--dry-run")
+	assert.Contains(T, got, "Would trigger runs for 2 jobs")
+	assert.Len(T, queued, 2, "dry run must not queue")
+}
+
+func TestRunStartRepoSingleMatch(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/vcs-roots", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(T, r.URL.Query().Get("locator"), "value:acme/lib")
+		cmdtest.JSON(w, api.VcsRootList{Count: 1, VcsRoot: []api.VcsRoot{{ID: "Lib_Git",
+			Properties: &api.PropertyList{Property: []api.Property{{Name: "url", Value: "https://github.com/acme/lib.git"}}}}}})
+	})
+	ts.Handle("GET /app/rest/buildTypes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildTypeList{Count: 1, BuildTypes: []api.BuildType{{ID: "Lib_Build"}}})
+	})
+	var queued string
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		var req api.TriggerBuildRequest
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(T, json.Unmarshal(body, &req))
+		queued = req.BuildType.ID
+		cmdtest.JSON(w, api.Build{ID: 1001, BuildTypeID: queued})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", "--repo", "git@github.com:acme/lib", "--branch", "main")
+	assert.Equal(T, "Lib_Build", queued)
+	assert.Contains(T, got, "Queued run 1001 for Lib_Build")
+}
+
+func TestRunStartRepoErrors(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	handleRepoJobs(ts)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "cannot specify both job-id argument and --repo", "run", "start", testJob, "--repo", "git@github.com:acme/app.git")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--all-matching requires --repo", "run", "start", testJob, "--all-matching")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "no jobs found for repository", "run", "start", "--repo", "git@github.com:acme/none.git")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "invalid repository URL", "run", "start", "--repo", "not a url")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "none of the others can be", "run", "start", "--repo", "git@github.com:acme/app.git", "--all-matching", "--watch")
+}
+
 func TestRunStartDryRunNonExistentJob(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", "NonExistentJob123456", "--dry-run")
@@ -262,12 +762,207 @@ func TestRunStartSettingsInvalid(T *testing.T) {
 	assert.Contains(T, err.Error(), "invalid --settings value")
 }
 
+func TestRunStartFile(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var captured api.TriggerBuildRequest
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(T, json.Unmarshal(body, &captured))
+		cmdtest.JSON(w, api.Build{ID: 999, BuildTypeID: testJob, WebURL: "https://example/build/999"})
+	})
+	path := filepath.Join(T.TempDir(), "run.yml")
+	require.NoError(T, os.WriteFile(path, []byte(cmdtest.Dedent(`
+		job: `+testJob+`
+		branch: release
+		params:
+		  version: "1.0"
+		  channel: beta
+		tags: [nightly]
+		clean: true
+	`)), 0o644))
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "start", "-f", path, "-P", "version=2.0", "--branch", "main")
+
+	assert.Equal(T, testJob, captured.BuildType.ID)
+	assert.Equal(T, "main", captured.BranchName)
+	require.NotNil(T, captured.Properties)
+	assert.ElementsMatch(T, []api.Property{{Name: "version", Value: "2.0"}, {Name: "channel", Value: "beta"}}, captured.Properties.Property)
+	require.NotNil(T, captured.Tags)
+	assert.Equal(T, "nightly", captured.Tags.Tag[0].Name)
+	require.NotNil(T, captured.TriggeringOptions)
+	assert.True(T, captured.TriggeringOptions.CleanSources)
+}
+
+func TestRunStartFileErrors(T *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", "job: " + testJob + "\nbranchs: main\n", `run.yml:2: unknown key "branchs"`},
+		{"wrong type", "job: " + testJob + "\npersonal: maybe\n", "run.yml:2: personal must be true or false"},
+		{"duplicate key", "job: " + testJob + "\ntop: true\ntop: false\n", "run.yml:3: top is set twice"},
+		{"bad settings", "job: " + testJob + "\n\nsettings: latest\n", "run.yml:3: settings:"},
+		{"not a map", "- " + testJob + "\n", "run.yml:1: expected a map"},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(T *testing.T) {
+			ts := cmdtest.SetupMockClient(T)
+			path := filepath.Join(T.TempDir(), "run.yml")
+			require.NoError(T, os.WriteFile(path, []byte(tc.content), 0o644))
+
+			err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", "-f", path)
+			assert.Contains(T, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestRunStartEmitFile(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	path := filepath.Join(T.TempDir(), "run.yml")
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "start", testJob, "--emit-file", path)
+	assert.Contains(T, err.Error(), "--emit-file requires --dry-run")
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "start", testJob, "-P", "version=2.0", "--tag", "release",
+		"--top", "--dry-run", "--emit-file", path)
+	assert.Contains(T, got, "Wrote run definition to "+path)
+	data, err := os.ReadFile(path)
+	require.NoError(T, err)
+	assert.Contains(T, string(data), "job: "+testJob)
+	assert.Contains(T, string(data), "params:\n  version: \"2.0\"")
+	assert.Contains(T, string(data), "top: true")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(T, err)
+		assert.Equal(T, os.FileMode(0o600), info.Mode().Perm(), "parameters may be credentials")
+	}
+
+	err = cmdtest.CaptureErr(T, ts.Factory, "run", "start", testJob, "--local-changes", "--dry-run", "--emit-file", path)
+	assert.Contains(T, err.Error(), "[emit-file local-changes] are set none of the others can be")
+
+	var captured api.TriggerBuildRequest
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(T, json.Unmarshal(body, &captured))
+		cmdtest.JSON(w, api.Build{ID: 999, BuildTypeID: testJob, WebURL: "https://example/build/999"})
+	})
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "start", "-f", path)
+	assert.Equal(T, testJob, captured.BuildType.ID)
+	assert.Equal(T, []api.Property{{Name: "version", Value: "2.0"}}, captured.Properties.Property)
+	assert.True(T, captured.TriggeringOptions.QueueAtTop)
+}
+
 func TestRunCancel(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
 	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "cancel", testBuildID, "--comment", "Test cleanup")
 }
 
+// handleApprovalQueue serves a queue of the given runs; waiting lists those pending approval and canApprove whether the user may approve them.
+func handleApprovalQueue(ts *cmdtest.TestServer, builds []api.QueuedBuild, waiting map[int]bool, canApprove bool) *[]string {
+	var approved []string
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: len(builds), Builds: builds})
+	})
+	ts.Handle("GET /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+		id := cmdtest.ExtractID(r.URL.Path, "id:")
+		status := "approved"
+		if n, _ := strconv.Atoi(id); waiting[n] {
+			status = "waitingForApproval"
+		}
+		cmdtest.JSON(w, api.ApprovalInfo{Status: status, CanBeApprovedByCurrentUser: canApprove})
+	})
+	ts.Handle("PUT /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+		approved = append(approved, cmdtest.ExtractID(r.URL.Path, "id:"))
+		w.WriteHeader(http.StatusOK)
+	})
+	return &approved
+}
+
+func TestRunApprove(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	approved := handleApprovalQueue(ts, []api.QueuedBuild{{
+		ID: 300, BuildTypeID: "Falcon_Release", BranchName: "release/2.1",
+		BuildType: &api.BuildType{ID: "Falcon_Release", Name: "Release"},
+		Triggered: &api.Triggered{Type: "user", User: &api.User{Name: "Jane Doe"}},
+		Properties: &api.ParameterList{Property: []api.Parameter{
+			{Name: "env.TARGET", Value: "prod"},
+			{Name: "env.DEPLOY_KEY", Value: "s3cr3t", Type: &api.ParameterType{RawValue: "password display='hidden'"}},
+		}},
+	}}, map[int]bool{300: true}, true)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "approve", "--job", "Falcon_Release")
+	assert.Contains(T, got, "Release (Falcon_Release)")
+	assert.Contains(T, got, "Requested by Jane Doe")
+	assert.Contains(T, got, "Branch       release/2.1")
+	assert.Contains(T, got, "env.TARGET = prod")
+	assert.Contains(T, got, "env.DEPLOY_KEY = ******")
+	assert.NotContains(T, got, "s3cr3t")
+	assert.Contains(T, got, "Approved run 300 of Falcon_Release")
+	assert.Equal(T, []string{"300"}, *approved)
+}
+
+func TestRunApproveSeveralWaiting(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	approved := handleApprovalQueue(ts, []api.QueuedBuild{
+		{ID: 300, BuildTypeID: "Falcon_Release"},
+		{ID: 301, BuildTypeID: "Falcon_Release"},
+		{ID: 302, BuildTypeID: "Falcon_Release"},
+	}, map[int]bool{300: true, 301: true}, true)
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "approve", "--job", "Falcon_Release")
+	assert.Contains(T, err.Error(), "2 runs of Falcon_Release are waiting for approval")
+	assert.Contains(T, err.Error(), "  301")
+	assert.Empty(T, *approved)
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "approve", "--job", "Falcon_Release", "--latest")
+	assert.Equal(T, []string{"301"}, *approved)
+}
+
+func TestRunApproveErrors(T *testing.T) {
+	T.Run("nothing queued", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		handleApprovalQueue(ts, nil, nil, true)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "no queued runs of Falcon_Release", "run", "approve", "--job", "Falcon_Release")
+	})
+	T.Run("already approved", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		handleApprovalQueue(ts, []api.QueuedBuild{{ID: 300}}, nil, true)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "none of the 1 queued runs of Falcon_Release is waiting for approval", "run", "approve", "--job", "Falcon_Release")
+	})
+	T.Run("not an approver", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		approved := handleApprovalQueue(ts, []api.QueuedBuild{{ID: 300}}, map[int]bool{300: true}, false)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "you cannot approve run 300", "run", "approve", "--job", "Falcon_Release")
+		assert.Empty(t, *approved)
+	})
+	T.Run("started meanwhile", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		handleApprovalQueue(ts, []api.QueuedBuild{{ID: 300}}, map[int]bool{300: true}, true)
+		ts.Handle("PUT /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.Error(w, http.StatusNotFound, "No queued build found by locator 'id:300'")
+		})
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "run 300 is no longer queued", "run", "approve", "--job", "Falcon_Release")
+	})
+	T.Run("approval state unreadable", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		handleApprovalQueue(ts, []api.QueuedBuild{{ID: 300}, {ID: 301}}, map[int]bool{301: true}, true)
+		ts.Handle("GET /app/rest/buildQueue/id:", func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "id:300/") {
+				cmdtest.Error(w, http.StatusNotFound, "No approval feature")
+				return
+			}
+			cmdtest.Error(w, http.StatusForbidden, "Access denied")
+		})
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "failed to get approval state of run 301", "run", "approve", "--job", "Falcon_Release")
+	})
+	T.Run("job required", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "--job is required", "run", "approve")
+	})
+}
+
 func TestRunLog(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
@@ -312,6 +1007,25 @@ func TestRunLogJSON(T *testing.T) {
 	assert.Contains(T, got, "Build started")
 }
 
+func TestRunLogJSONStreamsLargeLog(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	log := strings.Repeat("[12:00:00]i: step \"compile\" <main> → ok\n", 20000)
+	ts.Handle("GET /downloadBuildLog.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, log)
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--json")
+	var parsed struct {
+		RunID string `json:"run_id"`
+		Log   string `json:"log"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(got), &parsed))
+	assert.Equal(T, testBuildID, parsed.RunID)
+	assert.Equal(T, log, parsed.Log)
+	assert.True(T, strings.HasPrefix(got, "{\n  \"run_id\": \""+testBuildID+"\",\n  \"log\": \"[12:00:00]"), "indented like other JSON output")
+}
+
 func TestRunLogJSON_failed(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
@@ -330,6 +1044,83 @@ func TestRunLogJSON_failed(T *testing.T) {
 	assert.Contains(T, got, "FAILURE")
 }
 
+func TestRunLogFailedLinks(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1, Number: "1", Status: "FAILURE", State: "finished", WebURL: ts.URL + "/viewLog.html?buildId=1"})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--failed", "--links")
+	assert.Contains(T, got, "Compilation failed")
+	assert.Contains(T, got, "/viewLog.html?buildId=1&buildTab=overview&expandedProblem=1")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--links only applies with --failed", "run", "log", testBuildID, "--links")
+}
+
+func TestRunLogFailedDetails(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1, Number: "42", Status: "FAILURE", State: "finished", WebURL: ts.URL + "/viewLog.html?buildId=1"})
+	})
+	var fields string
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		parsed := &api.Test{ParsedTestName: &api.ParsedTestName{TestSuite: "shop-tests", TestPackage: "com.acme", TestClass: "CartTest", TestMethodName: "testCheckout"}}
+		cmdtest.JSON(w, api.TestOccurrences{Count: 3, TestOccurrence: []api.TestOccurrence{
+			{ID: "1", Name: "com.acme.CartTest.testCheckout", Duration: 1200, NewFailure: true, Details: "AssertionError: expected 3 but was 2\n\tat CartTest.java:42", Test: parsed},
+			{ID: "2", Name: "com.acme.UserTest.testLogin", Duration: 300, Details: "Timeout after 30s",
+				FirstFailed: &api.TestOccurrence{Build: &api.Build{Number: "40"}}},
+			{ID: "3", Name: "com.acme.CartTest.testCheckout", Duration: 1100, Details: "AssertionError: expected 3 but was 2\n\tat CartTest.java:42", Test: parsed},
+		}})
+	})
+	ts.Handle("GET /app/rest/problemOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProblemOccurrences{Count: 2, ProblemOccurrence: []api.ProblemOccurrence{
+			{ID: "1", Type: "TC_FAILED_TESTS", Identity: "failedTests", Details: "Tests failed: 2"},
+			{ID: "2", Type: "TC_EXIT_CODE", Identity: "exitCode", Details: "Process exited with code 1"},
+		}})
+	})
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--failed-details")
+	assert.Contains(T, fields, "details")
+	assert.Contains(T, fields, "parsedTestName")
+	assert.Contains(T, got, "Failed tests (2):")
+	assert.Contains(T, got, "com.acme.CartTest.testCheckout (2s, failed 2 times) (new)")
+	assert.Contains(T, got, "Suite: shop-tests  Class: com.acme.CartTest  Test: testCheckout")
+	assert.Equal(T, 1, strings.Count(got, "expected 3 but was 2"), "identical stack traces are printed once")
+	assert.Contains(T, got, "(failing since #40)")
+	assert.Contains(T, got, "Process exited with code 1")
+	assert.NotContains(T, got, "Tests failed: 2")
+	assert.Less(T, strings.Index(got, "Timeout after 30s"), strings.Index(got, "Problems (1):"))
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--failed-details", "--test", "login", "--json")
+	var parsed struct {
+		RunID string `json:"run_id"`
+		Tests []struct {
+			Name     string   `json:"name"`
+			Failures int      `json:"failures"`
+			Details  []string `json:"details"`
+		} `json:"failed_tests"`
+		Problems []api.ProblemOccurrence `json:"problems"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(got), &parsed))
+	require.Len(T, parsed.Tests, 1)
+	assert.Equal(T, "com.acme.UserTest.testLogin", parsed.Tests[0].Name)
+	assert.Equal(T, []string{"Timeout after 30s"}, parsed.Tests[0].Details)
+	require.Len(T, parsed.Problems, 1)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--failed-details", "--test", "nothing")
+	assert.Contains(T, got, `No failed tests match "nothing"`)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--test only applies with --failed-details", "run", "log", testBuildID, "--test", "x")
+}
+
+func TestRunLogLine(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--line only applies with --web", "run", "log", testBuildID, "--line", "240")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--line must not be negative", "run", "log", testBuildID, "--web", "--line", "-1")
+}
+
 func TestRunLogJSON_raw_mutually_exclusive(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	err := cmdtest.CaptureErr(T, ts.Factory, "run", "log", testBuildID, "--json", "--raw")
@@ -355,31 +1146,231 @@ func TestRunLogTail(T *testing.T) {
 	assert.Contains(T, got, `"messages"`)
 }
 
-func TestRunLogFollow(T *testing.T) {
-	ts := cmdtest.SetupMockClient(T)
-	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
-		cmdtest.JSON(w, api.Build{
-			ID:     1,
-			Number: "1",
-			Status: "SUCCESS",
-			State:  "finished",
-			WebURL: ts.URL + "/viewLog.html?buildId=1",
-		})
+func TestRunLogFollow(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID:     1,
+			Number: "1",
+			Status: "SUCCESS",
+			State:  "finished",
+			WebURL: ts.URL + "/viewLog.html?buildId=1",
+		})
+	})
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--follow")
+	assert.Contains(T, got, "Build started")
+	assert.Contains(T, got, "Build finished")
+}
+
+// closingWriter takes n writes, then fails as stdout does once a pager quits or | head has read enough.
+type closingWriter struct {
+	n      int
+	writes atomic.Int32
+}
+
+func (c *closingWriter) Write(b []byte) (int, error) {
+	if int(c.writes.Add(1)) > c.n {
+		return 0, &os.PathError{Op: "write", Path: "/dev/stdout", Err: syscall.EPIPE}
+	}
+	return len(b), nil
+}
+
+// runWithClosingStdout runs args with stdout closing after n writes, returning what went to stderr and the error.
+func runWithClosingStdout(t *testing.T, f *cmdutil.Factory, n int, args ...string) (*closingWriter, string, error) {
+	t.Helper()
+	out := &closingWriter{n: n}
+	var errOut bytes.Buffer
+	f.Printer = &output.Printer{Out: out, ErrOut: &errOut}
+	root := cmd.NewCommand(f)
+	root.SetArgs(args)
+	root.SetOut(out)
+	root.SetErr(&errOut)
+	err := root.Execute()
+	return out, errOut.String(), err
+}
+
+func TestRunLogReaderQuitsEarly(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /downloadBuildLog.html", func(w http.ResponseWriter, r *http.Request) {
+		for i := range 10000 {
+			_, _ = fmt.Fprintf(w, "[12:00:00] line %d\n", i)
+		}
+	})
+
+	for _, args := range [][]string{{}, {"--raw"}} {
+		out, stderr, err := runWithClosingStdout(T, ts.Factory, 5, append([]string{"run", "log", testBuildID}, args...)...)
+		require.NoError(T, err, "quitting the reader is not an error")
+		assert.Empty(T, stderr)
+		assert.LessOrEqual(T, int(out.writes.Load()), 6, "output stops at the first failed write")
+	}
+}
+
+func TestRunLogFollowReaderQuitsEarly(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1, Number: "1", State: "running"})
+	})
+	var polls atomic.Int32
+	ts.Handle("GET /app/messages", func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		cmdtest.JSON(w, api.BuildMessagesResponse{Messages: []api.BuildMessage{{ID: 1, Text: "Build started", Status: 1}}})
+	})
+
+	start := time.Now()
+	_, stderr, err := runWithClosingStdout(T, ts.Factory, 0, "run", "log", testBuildID, "--follow")
+	require.NoError(T, err)
+	assert.Empty(T, stderr)
+	assert.Less(T, time.Since(start), time.Second, "follow stops instead of polling for a reader that is gone")
+	assert.Equal(T, int32(1), polls.Load())
+}
+
+func TestRunArtifacts(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	f := ts.Factory
+
+	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID)
+	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID, "--json")
+	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", "--job", testJob)
+	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID, "--path", "logs", "--json")
+	cmdtest.RunCmdWithFactoryExpectErr(T, f, "failed to get artifacts", "run", "artifacts", testBuildID, "--path", "nonexistent")
+}
+
+func TestRunArtifactsSizeFilter(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	content := &api.Content{Href: "/download"}
+	ts.Handle("GET /app/rest/builds/id:7/artifacts/children", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/rest/builds/id:7/artifacts/children":
+			cmdtest.JSON(w, api.Artifacts{Count: 3, File: []api.Artifact{
+				{Name: "app.tar.gz", Size: 250 << 20, Content: content},
+				{Name: "report.html", Size: 40 << 10, Content: content},
+				{Name: "dist"},
+			}})
+		case "/app/rest/builds/id:7/artifacts/children/dist":
+			cmdtest.JSON(w, api.Artifacts{Count: 1, File: []api.Artifact{{Name: "image.iso", Size: 2 << 30, Content: content}}})
+		default:
+			cmdtest.Error(w, http.StatusNotFound, "not found")
+		}
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--min-size", "100MB", "--max-size", "1GiB")
+	assert.Contains(T, out, "ARTIFACTS (1 of 3 files, 250 MiB total)")
+	assert.Contains(T, out, "app.tar.gz")
+	assert.NotContains(T, out, "report.html")
+	assert.NotContains(T, out, "image.iso")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--min-size", "1GB", "--json")
+	var artifacts api.Artifacts
+	require.NoError(T, json.Unmarshal([]byte(out), &artifacts))
+	require.Equal(T, 1, artifacts.Count)
+	assert.Equal(T, "dist/image.iso", artifacts.File[0].Name, "subdirectories are searched")
+	assert.Equal(T, int64(2<<30), artifacts.File[0].Size)
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--min-size", "10GB")
+	assert.Contains(T, out, "No artifacts in the size range among 3 files")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `invalid --min-size value "lots"`, "run", "artifacts", "7", "--min-size", "lots")
+}
+
+func TestRunArtifactsTreeAndPlain(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	content := &api.Content{Href: "/download"}
+	modified := time.Now().Add(-3 * time.Hour).Format("20060102T150405-0700")
+	ts.Handle("GET /app/rest/builds/id:7/artifacts/children", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/rest/builds/id:7/artifacts/children":
+			cmdtest.JSON(w, api.Artifacts{Count: 3, File: []api.Artifact{
+				{Name: "app.jar", Size: 3 << 20, ModTime: modified, Content: content},
+				{Name: "reports"},
+			}})
+		case "/app/rest/builds/id:7/artifacts/children/reports":
+			cmdtest.JSON(w, api.Artifacts{Count: 2, File: []api.Artifact{
+				{Name: "index.html", Size: 2 << 10, ModTime: modified, Content: content},
+				{Name: "logs"},
+			}})
+		case "/app/rest/builds/id:7/artifacts/children/reports/logs":
+			cmdtest.JSON(w, api.Artifacts{Count: 1, File: []api.Artifact{{Name: "test.log", Size: 1 << 10, Content: content}}})
+		default:
+			cmdtest.Error(w, http.StatusNotFound, "not found")
+		}
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--tree")
+	assert.Contains(T, out, "ARTIFACTS (3 files, 3.0 MiB total)")
+	assert.Contains(T, out, "reports/  2 files, 3.0 KiB")
+	assert.Contains(T, out, "logs/  1 file, 1.0 KiB")
+	assert.Contains(T, out, "index.html  2.0 KiB, 3h ago")
+	assert.Less(T, strings.Index(out, "logs/"), strings.Index(out, "test.log"))
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--tree", "--path", "reports")
+	assert.Contains(T, out, "ARTIFACTS in reports (2 files, 3.0 KiB total)")
+	assert.NotContains(T, out, "app.jar")
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--plain")
+	assert.Equal(T, "app.jar\nreports/index.html\nreports/logs/test.log\n", out)
+
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "artifacts", "7", "--plain", "--max-size", "2KiB")
+	assert.Equal(T, "reports/index.html\nreports/logs/test.log\n", out)
+}
+
+func TestRunDownload(T *testing.T) {
+	ts := cmdtest.NewTestServer(T)
+	content := &api.Content{Href: "/download"}
+	ts.Handle("GET /app/rest/builds/id:7/artifacts/children", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/rest/builds/id:7/artifacts/children":
+			cmdtest.JSON(w, api.Artifacts{Count: 3, File: []api.Artifact{
+				{Name: "app.jar", Size: 7, Content: content},
+				{Name: "broken.bin", Size: 4, Content: content},
+				{Name: "logs"},
+			}})
+		case "/app/rest/builds/id:7/artifacts/children/logs":
+			cmdtest.JSON(w, api.Artifacts{Count: 2, File: []api.Artifact{
+				{Name: "build.log", Size: 5, Content: content},
+				{Name: "test.log", Size: 4, Content: content},
+			}})
+		default:
+			cmdtest.Error(w, http.StatusNotFound, "not found")
+		}
+	})
+	var inFlight, maxInFlight atomic.Int32
+	ts.Handle("GET /app/rest/builds/id:7/artifacts/content/", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for m := maxInFlight.Load(); n > m && !maxInFlight.CompareAndSwap(m, n); m = maxInFlight.Load() {
+		}
+		time.Sleep(50 * time.Millisecond)
+		switch strings.TrimPrefix(r.URL.Path, "/app/rest/builds/id:7/artifacts/content/") {
+		case "app.jar":
+			_, _ = io.WriteString(w, "jarjar!")
+		case "logs/build.log":
+			_, _ = io.WriteString(w, "build")
+		case "logs/test.log":
+			_, _ = io.WriteString(w, "test")
+		default:
+			cmdtest.Error(w, http.StatusInternalServerError, "disk error")
+		}
 	})
-	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "log", testBuildID, "--follow")
-	assert.Contains(T, got, "Build started")
-	assert.Contains(T, got, "Build finished")
-}
 
-func TestRunArtifacts(T *testing.T) {
-	ts := cmdtest.SetupMockClient(T)
-	f := ts.Factory
+	dir := T.TempDir()
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "download", "7", "-o", dir, "--parallel", "3")
+	assert.Contains(T, err.Error(), "downloaded 3 of 4 artifacts", "one failed file does not stop the others")
+	assert.GreaterOrEqual(T, maxInFlight.Load(), int32(2), "files download concurrently")
+	for name, want := range map[string]string{"app.jar": "jarjar!", "logs/build.log": "build", "logs/test.log": "test"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(T, err)
+		assert.Equal(T, want, string(data))
+	}
+	assert.NoFileExists(T, filepath.Join(dir, "broken.bin"))
 
-	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID)
-	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID, "--json")
-	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", "--job", testJob)
-	cmdtest.RunCmdWithFactory(T, f, "run", "artifacts", testBuildID, "--path", "logs", "--json")
-	cmdtest.RunCmdWithFactoryExpectErr(T, f, "failed to get artifacts", "run", "artifacts", testBuildID, "--path", "nonexistent")
+	dir = T.TempDir()
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "download", "7", "-o", dir, "--artifact", "logs/*.log", "--parallel", "1")
+	assert.Contains(T, out, "Downloading 2 files (9 B total)")
+	assert.Contains(T, out, "2 artifacts downloaded (9 B in")
+	assert.FileExists(T, filepath.Join(dir, "logs", "test.log"))
+	assert.NoFileExists(T, filepath.Join(dir, "app.jar"))
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--parallel must be at least 1", "run", "download", "7", "--parallel", "0")
 }
 
 func TestRunPinUnpin(T *testing.T) {
@@ -398,6 +1389,51 @@ func TestRunTagUntag(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "run", "untag", testBuildID, "cli-test-tag", "another-tag")
 }
 
+func TestRunTagAutoFromBranch(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1, Number: "1", BuildTypeID: "TestProject_Build", BranchName: "release/2024.3"})
+	})
+	var posted []string
+	ts.Handle("POST /app/rest/builds/id:1/tags", func(w http.ResponseWriter, r *http.Request) {
+		var tags api.TagList
+		_ = json.NewDecoder(r.Body).Decode(&tags)
+		for _, t := range tags.Tag {
+			posted = append(posted, t.Name)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "tag", testBuildID, "--auto-from-branch")
+	assert.Equal(T, []string{"release-2024.3"}, posted)
+	assert.Contains(T, out, "release-2024.3")
+
+	posted = nil
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "tag", testBuildID, "qa",
+		`--auto-from-branch=(\w+)/(\d+)\.(\d+)`, "--tag-template", "{1}-{2}-{3}")
+	assert.Equal(T, []string{"qa", "release-2024-3"}, posted)
+
+	posted = nil
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `branch "release/2024.3" does not match`,
+		"run", "tag", testBuildID, "--auto-from-branch=hotfix/(.+)")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--tag-template requires --auto-from-branch",
+		"run", "tag", testBuildID, "qa", "--tag-template", "{1}")
+	assert.Empty(T, posted)
+}
+
+func TestRunListTag(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	var locator string
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator = r.URL.Query().Get("locator")
+		cmdtest.JSON(w, api.BuildList{Count: 0, Builds: []api.Build{}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "list", "--job", "TestProject_Build", "--tag", "release-2024.3")
+	assert.Contains(T, locator, "tag:(condition:(value:release-2024.3,matchType:equals))")
+	assert.Contains(T, out, `No runs tagged "release-2024.3" found`)
+}
+
 func TestRunComment(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 	f := ts.Factory
@@ -416,6 +1452,56 @@ func TestRunChanges(T *testing.T) {
 	cmdtest.RunCmdWithFactory(T, f, "run", "changes", testBuildID, "--json")
 }
 
+func TestRunChangesMarkdown(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/changes", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ChangeList{
+			Count: 3,
+			Change: []api.Change{
+				{Version: "aaaaaaa1111", Username: "alice", Comment: "Merge branch 'main'", ParentRevisions: &api.Items{Item: []string{"p1", "p2"}}},
+				{Version: "bbbbbbb2222", Username: "bob", Comment: "Fix flaky test", VcsRootInstance: &api.VcsRootInstanceRef{VcsRootID: "Falcon_GitHub"}},
+				{Version: "ccccccc3333", Username: "alice", Comment: "Bump version", VcsRootInstance: &api.VcsRootInstanceRef{VcsRootID: "Other_Root"}},
+			},
+		})
+	})
+	cfg := config.Get()
+	cfg.Servers[ts.URL] = config.ServerConfig{CommitLinks: map[string]string{"falcon_github": "https://github.com/acme/falcon/commit/{sha}"}}
+	T.Cleanup(func() { delete(cfg.Servers, ts.URL) })
+
+	T.Run("config template per VCS root", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "changes", testBuildID, "--format", "markdown", "--no-merges")
+		assert.Equal(t, "- Fix flaky test by bob in [bbbbbbb](https://github.com/acme/falcon/commit/bbbbbbb2222)\n- Bump version by alice in ccccccc\n", out)
+	})
+
+	T.Run("flag template wins", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "changes", testBuildID, "--format", "markdown", "--group-by", "author", "--link-template", "https://git.example.com/c/{sha}")
+		assert.Contains(t, out, "### alice\n\n- Merge branch 'main' in [aaaaaaa](https://git.example.com/c/aaaaaaa1111)\n- Bump version in [ccccccc](https://git.example.com/c/ccccccc3333)\n")
+		assert.Contains(t, out, "### bob\n\n- Fix flaky test in [bbbbbbb](https://git.example.com/c/bbbbbbb2222)\n")
+	})
+
+	T.Run("no merges in json", func(t *testing.T) {
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "changes", testBuildID, "--json", "--no-merges")
+		assert.Contains(t, out, `"count": 2`)
+		assert.NotContains(t, out, "aaaaaaa1111")
+	})
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"bad format", []string{"--format", "html"}, `invalid format "html"`},
+		{"bad group", []string{"--format", "markdown", "--group-by", "date"}, `invalid group-by "date"`},
+		{"group without markdown", []string{"--group-by", "author"}, "only apply to --format markdown"},
+		{"template without sha", []string{"--format", "markdown", "--link-template", "https://x"}, "no {sha} placeholder"},
+		{"json and format", []string{"--json", "--format", "markdown"}, "none of the others can be"},
+	} {
+		T.Run(tc.name, func(t *testing.T) {
+			cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, tc.want, append([]string{"run", "changes", testBuildID}, tc.args...)...)
+		})
+	}
+}
+
 func TestRunTree(T *testing.T) {
 	ts := cmdtest.SetupMockClient(T)
 
@@ -506,6 +1592,181 @@ func TestRunTestsFailedAndMutedAreMutuallyExclusive(T *testing.T) {
 	assert.Contains(T, err.Error(), "muted")
 }
 
+func TestRunTestsLinks(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	installRunTestsFilterHandler(ts)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "tests", testBuildID, "--failed", "--links")
+	assert.Contains(T, got, "PlainFailure")
+	assert.Contains(T, got, "/viewLog.html?buildId=1&buildTab=tests&expandedTest=failed&name=PlainFailure")
+
+	var tests struct {
+		Failed         int
+		TestOccurrence []struct {
+			Name   string
+			WebURL string `json:"webUrl"`
+		}
+	}
+	require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "run", "tests", testBuildID, "--failed", "--links", "--json")), &tests))
+	assert.Equal(T, 1, tests.Failed)
+	require.Len(T, tests.TestOccurrence, 1)
+	assert.Contains(T, tests.TestOccurrence[0].WebURL, "expandedTest=failed")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "none of the others can be", "run", "tests", testBuildID, "--links", "--web")
+}
+
+// handleNewFailures serves run 20 of job B on main with tests A and B failing. With a previous run, #19 failed
+// B and C; without, run 20 is the branch's first.
+func handleNewFailures(ts *cmdtest.TestServer, previous bool) {
+	ts.Handle("GET /app/rest/builds/id:20", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 20, Number: "20", BuildTypeID: "B", BranchName: "main", State: "finished", Status: "FAILURE", StartDate: "20260101T120000+0000"})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		if !strings.Contains(locator, "branch:main") || !strings.Contains(locator, "untilDate:") {
+			http.Error(w, "unexpected locator "+locator, http.StatusBadRequest)
+			return
+		}
+		builds := []api.Build{{ID: 20, Number: "20"}}
+		if previous {
+			builds = append(builds, api.Build{ID: 19, Number: "19"})
+		}
+		cmdtest.JSON(w, api.BuildList{Count: len(builds), Builds: builds})
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		switch locator := r.URL.Query().Get("locator"); {
+		case strings.Contains(locator, "id:20"):
+			names = []string{"TestA", "TestB"}
+		case strings.Contains(locator, "id:19"):
+			names = []string{"TestB", "TestC"}
+		}
+		tests := api.TestOccurrences{Count: len(names), Failed: len(names)}
+		for _, name := range names {
+			tests.TestOccurrence = append(tests.TestOccurrence, api.TestOccurrence{Name: name, Status: "FAILURE"})
+		}
+		cmdtest.JSON(w, tests)
+	})
+}
+
+func TestRunTestsNewFailures(T *testing.T) {
+	T.Run("lists only what did not fail in the previous run", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		handleNewFailures(ts, true)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "20", "--new-failures")
+		assert.Contains(t, out, "New failures in #20 since #19 (1):")
+		assert.Contains(t, out, "TestA")
+		assert.NotContains(t, out, "TestC")
+		assert.Contains(t, out, "Still failing: 1 test that also failed in #19")
+
+		var got struct {
+			PreviousRunID int                  `json:"previous_run_id"`
+			NewFailures   []api.TestOccurrence `json:"new_failures"`
+			StillFailing  []api.TestOccurrence `json:"still_failing"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "20", "--new-failures", "--json")), &got))
+		assert.Equal(t, 19, got.PreviousRunID)
+		require.Len(t, got.NewFailures, 1)
+		assert.Equal(t, "TestA", got.NewFailures[0].Name)
+		require.Len(t, got.StillFailing, 1)
+		assert.Equal(t, "TestB", got.StillFailing[0].Name)
+	})
+
+	T.Run("keeps apart batches failing a test of the same name", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		handleNewFailures(ts, true)
+		ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+			batches := []string{"B_1"}
+			if strings.Contains(r.URL.Query().Get("locator"), "id:20") {
+				batches = []string{"B_1", "B_2"}
+			}
+			tests := api.TestOccurrences{Count: len(batches), Failed: len(batches)}
+			for _, batch := range batches {
+				tests.TestOccurrence = append(tests.TestOccurrence, api.TestOccurrence{
+					Name: "TestShared", Status: "FAILURE", Test: &api.Test{ID: "7"}, Build: &api.Build{BuildTypeID: batch},
+				})
+			}
+			cmdtest.JSON(w, tests)
+		})
+
+		var got struct {
+			NewFailures  []api.TestOccurrence `json:"new_failures"`
+			StillFailing []api.TestOccurrence `json:"still_failing"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "20", "--new-failures", "--json")), &got))
+		require.Len(t, got.NewFailures, 1)
+		assert.Equal(t, "B_2", got.NewFailures[0].Build.BuildTypeID)
+		require.Len(t, got.StillFailing, 1)
+		assert.Equal(t, "B_1", got.StillFailing[0].Build.BuildTypeID)
+	})
+
+	T.Run("every failure is new in the first run on a branch", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		handleNewFailures(ts, false)
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "20", "--new-failures")
+		assert.Contains(t, out, "every failure is new")
+		assert.Contains(t, out, "New failures in #20 (2):")
+		assert.NotContains(t, out, "Still failing")
+	})
+
+	T.Run("conflicts with --muted", func(t *testing.T) {
+		ts := cmdtest.NewTestServer(t)
+		cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "none of the others can be", "run", "tests", "20", "--new-failures", "--muted")
+	})
+}
+
+func TestRunProblems(T *testing.T) {
+	T.Run("lists problems and points at failed tests", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.Build{ID: 1, Number: "42", Status: "FAILURE", State: "finished", StatusText: "Exit code 1"})
+		})
+		ts.Handle("GET /app/rest/problemOccurrences", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.ProblemOccurrences{Count: 2, ProblemOccurrence: []api.ProblemOccurrence{
+				{ID: "1", Type: "TC_EXIT_CODE", Identity: "exitCode", Details: "Process exited with code 1\nStep: Build"},
+				{ID: "2", Type: "TC_FAILED_TESTS", Identity: "failedTests", Details: "Tests failed: 2"},
+			}})
+		})
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "problems", testBuildID)
+		assert.Contains(t, out, "Problems in #42 (2):")
+		assert.Contains(t, out, "TC_EXIT_CODE  exitCode")
+		assert.Contains(t, out, "    Step: Build")
+		assert.Contains(t, out, "teamcity run tests 1 --failed")
+
+		var got struct {
+			RunID    int                     `json:"run_id"`
+			Problems []api.ProblemOccurrence `json:"problems"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "run", "problems", testBuildID, "--json")), &got))
+		assert.Equal(t, 1, got.RunID)
+		require.Len(t, got.Problems, 2)
+		assert.Equal(t, "TC_EXIT_CODE", got.Problems[0].Type)
+
+		view := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", testBuildID)
+		assert.Contains(t, view, "Problems: Process exited with code 1; Tests failed: 2")
+		assert.Contains(t, view, "teamcity run problems 1")
+	})
+
+	T.Run("hints at tests and log when a failed run has no problems", func(t *testing.T) {
+		ts := cmdtest.SetupMockClient(t)
+		ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.Build{ID: 1, Number: "42", Status: "FAILURE", State: "finished"})
+		})
+		ts.Handle("GET /app/rest/problemOccurrences", func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.ProblemOccurrences{})
+		})
+
+		out := cmdtest.CaptureOutput(t, ts.Factory, "run", "problems", testBuildID)
+		assert.Contains(t, out, "No build problems in #42, but it failed")
+		assert.Contains(t, out, "teamcity run tests 1 --failed")
+		assert.Contains(t, out, "teamcity run log 1 --failed")
+		assert.NotContains(t, cmdtest.CaptureOutput(t, ts.Factory, "run", "view", testBuildID), "Problems:")
+	})
+}
+
 func installRunTestsFilterHandler(ts *cmdtest.TestServer) {
 	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
 		locator := r.URL.Query().Get("locator")
@@ -666,6 +1927,11 @@ func TestRunListFavoritesLocator(T *testing.T) {
 	assert.Contains(T, capturedQuery, "count%3A1")
 }
 
+func TestRunListFavoritesWithTag(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "[tag favorites] are set none of the others can be", "run", "list", "--favorites", "--tag", "release")
+}
+
 func TestRunList_plain(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "list", "--plain")
@@ -711,6 +1977,91 @@ func TestRunView_output(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestRunView_failedAndFull(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds/id:43", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/app/rest/builds/id:43/artifacts/children":
+			cmdtest.JSON(w, api.Artifacts{Count: 2, File: []api.Artifact{{Name: "app.jar", Size: 3 << 20, Content: &api.Content{}}, {Name: "report.html", Size: 1 << 20, Content: &api.Content{}}}})
+		case strings.Contains(r.URL.Query().Get("fields"), "comment"):
+			cmdtest.JSON(w, map[string]any{"comment": map[string]string{"text": "Flaky agent"}})
+		default:
+			cmdtest.JSON(w, api.Build{
+				ID:          43,
+				Number:      "8",
+				Status:      "FAILURE",
+				State:       "finished",
+				BuildTypeID: "TestProject_Build",
+				BuildType:   &api.BuildType{ID: "TestProject_Build", Name: "Build"},
+				QueuedDate:  "20240101T115800+0000",
+				StartDate:   "20240101T120000+0000",
+				FinishDate:  "20240101T120130+0000",
+				Triggered:   &api.Triggered{Type: "user", User: &api.User{Name: "Alice"}},
+			})
+		}
+	})
+	ts.Handle("GET /app/rest/problemOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.ProblemOccurrences{Count: 1, ProblemOccurrence: []api.ProblemOccurrence{
+			{ID: "1", Type: "TC_FAILED_TESTS", Identity: "failedTests", Details: "Tests failed: 6"},
+		}})
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		tests := api.TestOccurrences{}
+		for i := range 6 {
+			tests.TestOccurrence = append(tests.TestOccurrence, api.TestOccurrence{Name: fmt.Sprintf("Test%d", i+1), Status: "FAILURE"})
+		}
+		cmdtest.JSON(w, tests)
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		dep := api.Build{ID: 40, Number: "12", Status: "SUCCESS", State: "finished", BuildTypeID: "TestProject_Compile"}
+		if strings.Contains(locator, "artifactDependency") {
+			dep = api.Build{ID: 41, Number: "3", Status: "SUCCESS", State: "finished", BuildTypeID: "TestProject_Tools"}
+		}
+		cmdtest.JSON(w, api.BuildList{Count: 1, Builds: []api.Build{dep}})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "43")
+	want := cmdtest.Dedent(`
+		✗ Build 43  #8
+		Triggered by Alice · Jan 01 · Took 1m 30s · Waited 2m 0s in queue
+
+		Problems: Tests failed: 6  · teamcity run problems 43
+
+		Failed tests:
+		  ✗ Test1
+		  ✗ Test2
+		  ✗ Test3
+		  ✗ Test4
+		  ✗ Test5
+		  More: teamcity run tests 43 --failed
+	`)
+	assert.Equal(t, want, got)
+
+	got = cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "43", "--full")
+	assert.Contains(t, got, "Comment: Flaky agent")
+	assert.Contains(t, got, "Artifacts: 2 files, 4.0 MiB")
+	assert.Contains(t, got, "Snapshot dependencies:\n  ✓ TestProject_Compile 40  #12 · Success")
+	assert.Contains(t, got, "Artifact dependencies:\n  ✓ TestProject_Tools 41  #3 · Success")
+
+	var view struct {
+		QueueWait            int                 `json:"queueWaitSeconds"`
+		FailedTests          []string            `json:"failedTests"`
+		MoreFailedTests      bool                `json:"moreFailedTests"`
+		Comment              string              `json:"comment"`
+		Artifacts            struct{ Files int } `json:"artifacts"`
+		ArtifactDependencies api.BuildList       `json:"artifact-dependencies"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "43", "--full", "--json")), &view))
+	assert.Equal(t, 120, view.QueueWait)
+	assert.Len(t, view.FailedTests, 5)
+	assert.True(t, view.MoreFailedTests)
+	assert.Equal(t, "Flaky agent", view.Comment)
+	assert.Equal(t, 2, view.Artifacts.Files)
+	require.Len(t, view.ArtifactDependencies.Builds, 1)
+	assert.Equal(t, 41, view.ArtifactDependencies.Builds[0].ID)
+}
+
 func TestRunView_usedByOtherBuilds(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 	ts.Handle("GET /app/rest/builds/id:55", func(w http.ResponseWriter, r *http.Request) {
@@ -755,7 +2106,7 @@ func TestRunView_waitReason(t *testing.T) {
 	assert.Contains(t, got, "Incompatible agents")
 }
 
-func TestRunView_waitReason_nonCompatibility_skipsAgentQuery(t *testing.T) {
+func TestRunView_waitReason_nonCompatibility_showsAgentCountOnly(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 	ts.Handle("GET /app/rest/builds/id:65", func(w http.ResponseWriter, r *http.Request) {
 		cmdtest.JSON(w, api.Build{
@@ -768,10 +2119,83 @@ func TestRunView_waitReason_nonCompatibility_skipsAgentQuery(t *testing.T) {
 	})
 	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "65")
 	assert.Contains(t, got, "Wait reason: Build dependencies have not been built yet")
-	assert.NotContains(t, got, "Compatible agents")
+	// Only the queue section's count, not the per-agent breakdown.
+	assert.Contains(t, got, "Compatible agents: 2\n")
+	assert.NotContains(t, got, "Compatible agents (")
 	assert.NotContains(t, got, "Incompatible agents")
 }
 
+func TestRunView_queued(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds/id:80", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID: 80, State: "queued", BuildTypeID: "TestProject_Build",
+			BuildType:  &api.BuildType{ID: "TestProject_Build", Name: "Build"},
+			BranchName: "feature/x",
+			QueuedDate: api.FormatTeamCityTime(time.Now().Add(-10 * time.Minute)),
+			WebURL:     "https://ci.example.com/viewLog.html?buildId=80",
+			Triggered:  &api.Triggered{Type: "vcs"},
+			WaitReason: "Waiting for approval",
+		})
+	})
+	ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildQueue{Count: 3, Builds: []api.QueuedBuild{{ID: 79}, {ID: 80}, {ID: 81}}})
+	})
+	ts.Handle("GET /app/rest/buildQueue/id:80", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/approval") {
+			cmdtest.JSON(w, api.ApprovalInfo{Status: "waitingForApproval", CanBeApprovedByCurrentUser: true})
+			return
+		}
+		cmdtest.JSON(w, map[string]string{"startEstimate": api.FormatTeamCityTime(time.Now().Add(5 * time.Minute))})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "80")
+	assert.Contains(t, got, "◦ Build 80 · feature/x\nTriggered by vcs · Queued 10m ago\n")
+	assert.Contains(t, got, "Queue position: 2 of 3\n")
+	assert.Regexp(t, `Estimated start: in [45]m`, got)
+	assert.Contains(t, got, "Compatible agents: 2\n")
+	assert.Contains(t, got, "Approval: waiting for approval  · teamcity queue approve 80\n")
+	assert.Contains(t, got, "Wait reason: Waiting for approval")
+
+	out := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "80", "--json")
+	var view map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &view))
+	assert.Equal(t, "queued", view["state"])
+	assert.EqualValues(t, 2, view["queuePosition"])
+	assert.EqualValues(t, 3, view["queueLength"])
+	assert.EqualValues(t, 2, view["compatibleAgentsCount"])
+	assert.NotEmpty(t, view["startEstimate"])
+	assert.Equal(t, "waitingForApproval", view["approval"].(map[string]any)["status"])
+}
+
+func TestRunView_runningAndFinishedSkipQueueDetails(t *testing.T) {
+	for _, state := range []string{"running", "finished"} {
+		t.Run(state, func(t *testing.T) {
+			ts := cmdtest.SetupMockClient(t)
+			ts.Handle("GET /app/rest/builds/id:81", func(w http.ResponseWriter, r *http.Request) {
+				cmdtest.JSON(w, api.Build{
+					ID: 81, State: state, Status: "SUCCESS", BuildTypeID: "TestProject_Build",
+					BuildType: &api.BuildType{ID: "TestProject_Build", Name: "Build"},
+					StartDate: "20240101T120000+0000",
+					WebURL:    "https://ci.example.com/viewLog.html?buildId=81",
+					Triggered: &api.Triggered{Type: "user", User: &api.User{Name: "Alice"}},
+				})
+			})
+			ts.Handle("GET /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected queue request for a %s run: %s", state, r.URL)
+			})
+
+			got := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "81")
+			assert.NotContains(t, got, "Queue position")
+			assert.NotContains(t, got, "Compatible agents")
+
+			out := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "81", "--json")
+			assert.NotContains(t, out, "queuePosition")
+			assert.NotContains(t, out, "compatibleAgentsCount")
+		})
+	}
+}
+
 func TestRunView_compatibilityDetails(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
 	ts.Handle("GET /app/rest/builds/id:71", func(w http.ResponseWriter, r *http.Request) {
@@ -857,3 +2281,293 @@ func TestRunList_invalid_limit(t *testing.T) {
 	err := cmdtest.CaptureErr(t, ts.Factory, "run", "list", "--limit", "-1")
 	assert.Equal(t, "--limit must not be negative, got -1", err.Error())
 }
+
+// installBatchHandlers serves run 50 of a parallel-tests job with two batches: 51 (failed) and 52 (passed).
+func installBatchHandlers(ts *cmdtest.TestServer) {
+	ts.Handle("GET /app/rest/builds/id:50", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID: 50, Number: "50", Status: "FAILURE", State: "finished",
+			BuildTypeID: "TestProject_Tests",
+			BuildType:   &api.BuildType{ID: "TestProject_Tests", Name: "Tests"},
+			WebURL:      "https://ci.example.com/viewLog.html?buildId=50",
+		})
+	})
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("locator"), "to:(id:50)") {
+			cmdtest.JSON(w, api.BuildList{Builds: []api.Build{}})
+			return
+		}
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{
+			{
+				ID: 52, Number: "50", Status: "SUCCESS", State: "finished",
+				StartDate: "20240101T120000+0000", FinishDate: "20240101T120200+0000",
+				BuildType: &api.BuildType{ID: "TestProject_Tests_Batch2", Name: "Tests (batch 2)"},
+			},
+			{
+				ID: 51, Number: "50", Status: "FAILURE", State: "finished",
+				StartDate: "20240101T120000+0000", FinishDate: "20240101T120100+0000",
+				BuildType: &api.BuildType{ID: "TestProject_Tests_Batch1", Name: "Tests (batch 1)"},
+			},
+		}})
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		switch {
+		case strings.Contains(locator, "id:51"):
+			cmdtest.JSON(w, api.TestOccurrences{Count: 2, Passed: 1, Failed: 1, TestOccurrence: []api.TestOccurrence{
+				{ID: "a", Name: "BatchOnePasses", Status: "SUCCESS"},
+				{ID: "b", Name: "BatchOneFails", Status: "FAILURE"},
+			}})
+		case strings.Contains(locator, "id:52"):
+			cmdtest.JSON(w, api.TestOccurrences{Count: 1, Passed: 1, TestOccurrence: []api.TestOccurrence{
+				{ID: "c", Name: "BatchTwoPasses", Status: "SUCCESS"},
+			}})
+		default:
+			cmdtest.JSON(w, api.TestOccurrences{TestOccurrence: []api.TestOccurrence{}})
+		}
+	})
+	ts.Handle("GET /downloadBuildLog.html", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("log of build " + r.URL.Query().Get("buildId") + "\n"))
+	})
+}
+
+func TestRunView_batches(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installBatchHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "50")
+	assert.Contains(t, got, "Batches:")
+	assert.Regexp(t, `1\s+✗ Failed\s+batch 1\s+51\s+1m 0s`, got)
+	assert.Regexp(t, `2\s+✓ Success\s+batch 2\s+52\s+2m 0s`, got)
+
+	var view struct {
+		ID      int
+		Batches []api.BuildBatch
+	}
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "run", "view", "50", "--json")), &view))
+	assert.Equal(t, 50, view.ID)
+	require.Len(t, view.Batches, 2)
+	assert.Equal(t, 51, view.Batches[0].Build.ID)
+}
+
+func TestRunTests_mergeBatches(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installBatchHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "50")
+	assert.Contains(t, got, "No tests in this run")
+	assert.Contains(t, got, "--merge-batches")
+
+	got = cmdtest.CaptureOutput(t, ts.Factory, "run", "tests", "50", "--merge-batches")
+	assert.Contains(t, got, "BatchOneFails (batch 1)")
+	assert.Contains(t, got, "BatchTwoPasses (batch 2)")
+	assert.Contains(t, got, "TESTS: 2 passed, 1 failed")
+}
+
+func TestRunLog_batch(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	installBatchHandlers(ts)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "log", "50", "--batch", "2", "--raw")
+	assert.Contains(t, got, "log of build 52")
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "run", "log", "50", "--batch", "3")
+	assert.Contains(t, err.Error(), "run 50 has no batch 3")
+
+	err = cmdtest.CaptureErr(t, ts.Factory, "run", "log", testBuildID, "--batch", "1")
+	assert.Contains(t, err.Error(), "has no batches")
+}
+
+func TestRunBisect(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		assert.Contains(T, locator, "buildType:Falcon_Build")
+		assert.Contains(T, locator, "state:finished")
+		assert.Contains(T, locator, "branch:main")
+		// Newest first, as the server returns them.
+		cmdtest.JSON(w, api.BuildList{Count: 5, Builds: []api.Build{
+			{ID: 105, Number: "45", Status: "FAILURE", StatusText: "Tests failed: 2"},
+			{ID: 104, Number: "44", Status: "FAILURE", StatusText: "Tests failed: 2"},
+			{ID: 103, Number: "43", Status: "UNKNOWN", StatusText: "Canceled"},
+			{ID: 102, Number: "42", Status: "SUCCESS"},
+			{ID: 101, Number: "41", Status: "SUCCESS"},
+		}})
+	})
+	ts.Handle("GET /app/rest/changes", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.RawQuery, "id:104"):
+			cmdtest.JSON(w, api.ChangeList{Count: 1, Change: []api.Change{{ID: 7, Version: "bbbbbbbbbb", Username: "alice", Comment: "Tune cache"}}})
+		case strings.Contains(r.URL.RawQuery, "id:103"):
+			cmdtest.JSON(w, api.ChangeList{Count: 1, Change: []api.Change{{ID: 6, Version: "aaaaaaaaaa", Username: "bob", Comment: "Bump parser"}}})
+		default:
+			T.Errorf("unexpected changes request %s", r.URL.RawQuery)
+		}
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TestOccurrences{Count: 2, Failed: 2, TestOccurrence: []api.TestOccurrence{
+			{Name: "CacheTest.evicts", Status: "FAILURE", NewFailure: true},
+			{Name: "ParserTest.nested", Status: "FAILURE"},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "bisect", "--job", "Falcon_Build", "--branch", "main")
+	assert.Contains(T, out, "5 finished runs since 14d")
+	assert.Contains(T, out, "First bad run:  ✗ 104  #44")
+	assert.Contains(T, out, "Last good run:  ✓ 102  #42")
+	assert.Contains(T, out, "Skipped:        103")
+	assert.Contains(T, out, "CHANGES (2)")
+	assert.Contains(T, out, "bbbbbbb  alice  Tune cache")
+	assert.Contains(T, out, "aaaaaaa  bob  Bump parser")
+	assert.Contains(T, out, "FAILED TESTS (2)")
+	assert.Contains(T, out, "CacheTest.evicts (new)")
+	assert.Contains(T, out, "teamcity run diff 102 104")
+
+	var result struct {
+		FirstBad   struct{ ID int }
+		LastGood   struct{ ID int }
+		Changes    []api.Change
+		Boundaries []struct{ Kind string }
+	}
+	require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "run", "bisect", "--job", "Falcon_Build", "--branch", "main", "--json")), &result))
+	assert.Equal(T, 104, result.FirstBad.ID)
+	assert.Equal(T, 102, result.LastGood.ID)
+	assert.Len(T, result.Changes, 2)
+	assert.Len(T, result.Boundaries, 1)
+}
+
+func TestRunBisectNothingToFind(T *testing.T) {
+	builds := func(list ...api.Build) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			cmdtest.JSON(w, api.BuildList{Count: len(list), Builds: list})
+		}
+	}
+
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds", builds())
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "no finished runs of Falcon_Build since 14d", "run", "bisect", "--job", "Falcon_Build")
+
+	ts = cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds", builds(api.Build{ID: 2, Status: "SUCCESS"}, api.Build{ID: 1, Status: "SUCCESS"}))
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "bisect", "--job", "Falcon_Build")
+	assert.Contains(T, out, "No failed runs in the window: all 2 decisive runs succeeded")
+
+	ts = cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds", builds(api.Build{ID: 2, Status: "FAILURE"}, api.Build{ID: 1, Status: "FAILURE"}))
+	out = cmdtest.CaptureOutput(T, ts.Factory, "run", "bisect", "--job", "Falcon_Build", "--since", "3d")
+	assert.Contains(T, out, "First bad run:  ✗ 1")
+	assert.Contains(T, out, "Every decisive run since 3d failed")
+	assert.Contains(T, out, "Widen the window with --since")
+
+	ts = cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds", builds(api.Build{ID: 1, Status: "UNKNOWN", StatusText: "Canceled"}))
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "none of the 1 finished runs", "run", "bisect", "--job", "Falcon_Build")
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "job id is required", "run", "bisect")
+}
+
+func TestRunHistory(T *testing.T) {
+	T.Setenv("LC_ALL", "en_US.UTF-8")
+	ts := cmdtest.NewTestServer(T)
+	finished := time.Now().Add(-2 * time.Hour)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		assert.Contains(T, locator, "buildType:Falcon_Build")
+		assert.Contains(T, locator, "state:finished")
+		cmdtest.JSON(w, api.BuildList{Count: 2, Builds: []api.Build{
+			{ID: 2, Status: "FAILURE", StartDate: api.FormatTeamCityTime(finished.Add(-5 * time.Minute)), FinishDate: api.FormatTeamCityTime(finished)},
+			{ID: 1, Status: "SUCCESS", StartDate: api.FormatTeamCityTime(finished.Add(-15 * time.Minute)), FinishDate: api.FormatTeamCityTime(finished.Add(-time.Minute))},
+		}})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "history", "--job", "Falcon_Build", "--group-by", "week", "--since", "3w")
+	assert.Contains(T, out, "WEEK")
+	assert.Contains(T, out, "2 runs, 1 succeeded")
+	// en_US weeks start on Sunday and dates read month first.
+	week := finished.AddDate(0, 0, -int(finished.Weekday()))
+	assert.Contains(T, out, week.Format("01/02/2006"))
+
+	var result struct {
+		GroupBy   string `json:"groupBy"`
+		WeekStart string `json:"weekStart"`
+		Buckets   []struct {
+			Runs            int `json:"runs"`
+			Failed          int `json:"failed"`
+			AverageDuration int `json:"averageDurationSeconds"`
+		} `json:"buckets"`
+	}
+	require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "run", "history", "--job", "Falcon_Build", "--group-by", "week", "--week-start", "monday", "--json")), &result))
+	assert.Equal(T, "monday", result.WeekStart)
+	runs, failed, average := 0, 0, 0
+	for _, b := range result.Buckets {
+		if b.Runs > 0 {
+			runs, failed, average = b.Runs, b.Failed, b.AverageDuration
+		}
+	}
+	assert.Equal(T, 2, runs)
+	assert.Equal(T, 1, failed)
+	assert.Equal(T, (5*60+14*60)/2, average)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `invalid period "year"`, "run", "history", "--job", "Falcon_Build", "--group-by", "year")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `invalid week start "friday"`, "run", "history", "--job", "Falcon_Build", "--week-start", "friday")
+}
+
+func TestRunViewFromCassette(T *testing.T) {
+	f := cmdtest.NewCassetteFactory(T, "testdata/run_view.cassette.json")
+
+	out := cmdtest.CaptureOutput(T, f, "run", "view", "1")
+	assert.Contains(T, out, "TestProject_Build")
+	assert.Contains(T, out, "https://teamcity.test/viewLog.html?buildId=1", "the recorded server is replaced by the replay server")
+
+	out = cmdtest.CaptureOutput(T, f, "run", "view", "1", "--json")
+	assert.Contains(T, out, `"buildTypeId": "TestProject_Build"`)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, f, "cassette has no response for GET /app/rest/builds/id:2", "run", "view", "2")
+}
+
+func TestRunRestartReplaysTrigger(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	ts.Handle("GET /app/rest/builds/id:42", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(T, r.URL.Query().Get("fields"), "properties(property(name,value,type(rawValue)))")
+		cmdtest.JSON(w, api.RerunBuild{
+			Build: api.Build{ID: 42, Number: "17", BuildTypeID: testJob, BranchName: "feature", Personal: true,
+				Properties: &api.ParameterList{Property: []api.Parameter{
+					{Name: "env.TARGET", Value: "prod"},
+					{Name: "version", Value: "1.2"},
+					{Name: "DEPLOY_KEY", Type: &api.ParameterType{RawValue: "password display='hidden'"}},
+				}},
+				Tags:        &api.TagList{Tag: []api.Tag{{Name: "release"}}},
+				LastChanges: &api.ChangeList{Change: []api.Change{{ID: 7}, {ID: 8, Personal: true}}},
+			},
+			Comment: &api.BuildComment{Text: "hotfix"},
+			Revisions: &api.Revisions{Revision: []api.Revision{
+				{Version: "abc123", VcsBranchName: "refs/heads/feature", VcsRootInstance: &api.VcsRootInstanceRef{VcsRootID: "Root"}},
+			}},
+		})
+	})
+	var req api.TriggerBuildRequest
+	ts.Handle("POST /app/rest/buildQueue", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(T, json.NewDecoder(r.Body).Decode(&req))
+		cmdtest.JSON(w, api.Build{ID: 100, Number: "100", State: "queued", BuildTypeID: testJob})
+	})
+
+	out := cmdtest.CaptureOutput(T, ts.Factory, "run", "restart", "42", "-P", "env.TARGET=staging", "--top", "--clean")
+
+	assert.Contains(T, out, "Secure parameter DEPLOY_KEY is not replayed")
+	assert.Contains(T, out, "Parameters: env.TARGET, version")
+	assert.Equal(T, "feature", req.BranchName)
+	props := map[string]string{}
+	for _, p := range req.Properties.Property {
+		props[p.Name] = p.Value
+	}
+	assert.Equal(T, map[string]string{"env.TARGET": "staging", "version": "1.2"}, props, "--param overrides, secure values are not sent")
+	assert.Equal(T, "Restart of #17: hotfix", req.Comment.Text)
+	assert.Equal(T, []api.Tag{{Name: "release"}}, req.Tags.Tag)
+	assert.True(T, req.Personal)
+	assert.Equal(T, []api.PersonalChange{{ID: "8", Personal: true}}, req.LastChanges.Change)
+	assert.Equal(T, "abc123", req.Revisions.Revision[0].Version)
+	assert.Equal(T, "Root", req.Revisions.Revision[0].VcsRootInstance.VcsRootID)
+	require.NotNil(T, req.TriggeringOptions)
+	assert.True(T, req.TriggeringOptions.QueueAtTop)
+	assert.True(T, req.TriggeringOptions.CleanSources)
+}
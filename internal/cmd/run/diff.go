@@ -19,6 +19,7 @@ import (
 )
 
 type runDiffOptions struct {
+	job     string
 	json    bool
 	log     bool
 	web     bool
@@ -46,7 +47,8 @@ Pipe to external diff tools for advanced views:
   teamcity run diff 123 124 --log --no-color | diff-so-fancy
 
 If only one run ID is given, it is compared against the previous
-finished run of the same job.`,
+finished run of the same job. Runs of different jobs can be compared too;
+a warning notes it, and tests are matched by name.`,
 		Args: cobra.RangeArgs(1, 2),
 		Example: `  teamcity run diff 123 124
   teamcity run diff 456                # compare with previous run
@@ -63,6 +65,7 @@ finished run of the same job.`,
 	cmd.Flags().BoolVar(&opts.log, "log", false, "Compare logs with colored unified diff")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser")
 	cmd.Flags().IntVarP(&opts.context, "unified", "U", 3, "Number of context lines in log diff")
+	addRunJobFlag(cmd, &opts.job)
 
 	cmd.MarkFlagsMutuallyExclusive("json", "log")
 	cmd.MarkFlagsMutuallyExclusive("json", "web")
@@ -87,7 +90,13 @@ func runRunDiff(f *cmdutil.Factory, args []string, opts *runDiffOptions) error {
 		return err
 	}
 
-	id1, id2, err := resolveDiffBuildIDs(f.Context(), client, args)
+	refs := make([]string, len(args))
+	for i, arg := range args {
+		if refs[i], err = resolveRunRef(f, client, arg, opts.job); err != nil {
+			return err
+		}
+	}
+	id1, id2, err := resolveDiffBuildIDs(f.Context(), client, refs)
 	if err != nil {
 		return err
 	}
@@ -118,6 +127,9 @@ func runRunDiff(f *cmdutil.Factory, args []string, opts *runDiffOptions) error {
 	if err != nil {
 		return err
 	}
+	if d1.build.BuildTypeID != d2.build.BuildTypeID {
+		p.Warn("Runs are from different jobs (%s and %s); tests are matched by name", d1.build.BuildTypeID, d2.build.BuildTypeID)
+	}
 
 	if opts.json {
 		return p.PrintJSON(buildDiffJSON(d1, d2))
@@ -389,13 +401,13 @@ func renderDiffHeader(p *output.Printer, b1, b2 *api.Build) {
 	icon1 := output.StatusIcon(b1.Status, b1.State, b1.StatusText)
 	icon2 := output.StatusIcon(b2.Status, b2.State, b2.StatusText)
 
-	jobName := b1.BuildTypeID
-	if b1.BuildType != nil {
-		jobName = b1.BuildType.Name
+	jobName := cmp.Or(cmdutil.JobName(b1.BuildType, b1.BuildTypeID), "-")
+	if b2.BuildTypeID != b1.BuildTypeID {
+		jobName += " " + output.Sym().Arrow + " " + cmp.Or(cmdutil.JobName(b2.BuildType, b2.BuildTypeID), "-")
 	}
 
-	_, _ = fmt.Fprintf(p.Out, "COMPARING  %s %d  #%s  "+output.Sym().Arrow+"  %s %d  #%s\n",
-		icon1, b1.ID, b1.Number, icon2, b2.ID, b2.Number)
+	_, _ = fmt.Fprintf(p.Out, "COMPARING  %s %d  %s  "+output.Sym().Arrow+"  %s %d  %s\n",
+		icon1, b1.ID, cmdutil.RunNumber(b1.Number), icon2, b2.ID, cmdutil.RunNumber(b2.Number))
 
 	meta := output.Faint("Job: ") + output.Cyan(jobName)
 	if b1.BranchName != "" || b2.BranchName != "" {
@@ -498,7 +510,11 @@ func renderParamsDiff(p *output.Printer, params1, params2 *api.ParameterList) bo
 	}
 
 	sectionHeader(p, "PARAMETERS")
+	printParamDiffs(p, diffs)
+	return true
+}
 
+func printParamDiffs(p *output.Printer, diffs []paramDiff) {
 	for _, c := range diffs {
 		switch c.kind {
 		case "changed":
@@ -512,8 +528,6 @@ func renderParamsDiff(p *output.Printer, params1, params2 *api.ParameterList) bo
 				output.Red("-"), c.name, output.Red(c.old))
 		}
 	}
-
-	return true
 }
 
 func renderChangesDiff(p *output.Printer, c1, c2 *api.ChangeList) bool {
@@ -591,7 +605,7 @@ func buildDiffJSON(d1, d2 buildData) map[string]any {
 	b1, b2 := d1.build, d2.build
 
 	jsonBuild := func(b *api.Build) map[string]any {
-		m := map[string]any{"id": b.ID, "number": b.Number, "status": b.Status, "state": b.State}
+		m := map[string]any{"id": b.ID, "number": b.Number, "job": b.BuildTypeID, "status": b.Status, "state": b.State}
 		if a := agentName(b); a != "" {
 			m["agent"] = a
 		}
@@ -600,6 +614,9 @@ func buildDiffJSON(d1, d2 buildData) map[string]any {
 
 	diff := map[string]any{}
 
+	if b1.BuildTypeID != b2.BuildTypeID {
+		diff["job"] = map[string]any{"from": b1.BuildTypeID, "to": b2.BuildTypeID}
+	}
 	if b1.Status != b2.Status || b1.State != b2.State {
 		diff["status"] = map[string]any{"from": statusString(b1), "to": statusString(b2)}
 	}
@@ -613,11 +630,7 @@ func buildDiffJSON(d1, d2 buildData) map[string]any {
 	}
 
 	if diffs := computeParamDiffs(d1.params, d2.params); len(diffs) > 0 {
-		params := make([]map[string]any, len(diffs))
-		for i, d := range diffs {
-			params[i] = map[string]any{"name": d.name, "from": d.old, "to": d.new, "type": d.kind}
-		}
-		diff["parameters"] = params
+		diff["parameters"] = paramDiffsJSON(diffs)
 	}
 
 	if summaryChanged, newFail, fixed := computeTestDiffs(d1.tests, d2.tests, d1.testSummary, d2.testSummary); summaryChanged || len(newFail) > 0 || len(fixed) > 0 {
@@ -695,10 +708,7 @@ func buildDuration(b *api.Build) time.Duration {
 }
 
 func agentName(b *api.Build) string {
-	if b.Agent != nil {
-		return b.Agent.Name
-	}
-	return ""
+	return cmdutil.AgentName(b.Agent)
 }
 
 func statusTextDetail(b *api.Build) string {
@@ -721,10 +731,18 @@ func statusString(b *api.Build) string {
 	return strings.ToLower(b.Status)
 }
 
+func paramDiffsJSON(diffs []paramDiff) []map[string]any {
+	params := make([]map[string]any, len(diffs))
+	for i, d := range diffs {
+		params[i] = map[string]any{"name": d.name, "from": d.old, "to": d.new, "type": d.kind}
+	}
+	return params
+}
+
 func paramMap(pl *api.ParameterList) map[string]string {
 	m := make(map[string]string, len(pl.Property))
 	for _, p := range pl.Property {
-		if p.Type != nil && p.Type.RawValue == "password" {
+		if p.IsPassword() {
 			continue
 		}
 		m[p.Name] = p.Value
@@ -10,6 +10,7 @@ import (
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
 	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
 )
 
 func setupDiffServer(t *testing.T) *cmdtest.TestServer {
@@ -258,6 +259,36 @@ func TestRunDiffJSON(t *testing.T) {
 	assert.Contains(t, got, `"newFailures"`)
 }
 
+func TestRunDiffDifferentJobs(t *testing.T) {
+	ts := setupDiffServer(t)
+	ts.Handle("GET /app/rest/builds/id:3", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/resulting-properties") {
+			cmdtest.JSON(w, api.ParameterList{})
+			return
+		}
+		cmdtest.JSON(w, api.Build{
+			ID:          3,
+			Number:      "7",
+			Status:      "SUCCESS",
+			State:       "finished",
+			BuildTypeID: "TestProject_Nightly",
+			BuildType:   &api.BuildType{ID: "TestProject_Nightly", Name: "Nightly"},
+			StartDate:   "20240101T110000+0000",
+			FinishDate:  "20240101T110100+0000",
+		})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "run", "diff", "3", "2")
+	assert.Contains(t, got, "Runs are from different jobs (TestProject_Nightly and TestProject_Build)")
+	assert.Contains(t, got, "Nightly "+output.Sym().Arrow+" Build")
+	assert.Contains(t, got, "New failures", "a run without tests still diffs by test name")
+	assert.Contains(t, got, "TestLogin")
+
+	got = cmdtest.CaptureOutput(t, ts.Factory, "run", "diff", "3", "2", "--json")
+	assert.Contains(t, got, `"from": "TestProject_Nightly"`)
+	assert.Contains(t, got, `"newFailures"`)
+}
+
 func TestRunDiffSingleArg(t *testing.T) {
 	ts := setupDiffServer(t)
 
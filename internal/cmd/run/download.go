@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
@@ -17,10 +19,12 @@ import (
 )
 
 type runDownloadOptions struct {
+	job      string
 	output   string
 	path     string
 	artifact string
 	timeout  time.Duration
+	parallel int
 }
 
 func newRunDownloadCmd(f *cmdutil.Factory) *cobra.Command {
@@ -33,12 +37,17 @@ func newRunDownloadCmd(f *cmdutil.Factory) *cobra.Command {
 
 Filter by --artifact (glob) and --path (subdirectory within the run's
 artifact tree). Use --output to choose the local destination directory
-(defaults to the current directory).`,
+(defaults to the current directory). Artifacts in subdirectories are
+saved under matching local directories.
+
+Several files download at once (--parallel, default 4). A file that fails
+is reported and skipped without stopping the others.`,
 		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run download 12345
   teamcity run download 12345 --path build/assets
   teamcity run download 12345 -o ./artifacts
   teamcity run download 12345 --artifact "*.jar"
+  teamcity run download 12345 --artifact "logs/*.log" --parallel 8
   teamcity run download 12345 --path build/assets -a "*.js"
   teamcity run download 12345 --timeout 30m`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -48,8 +57,10 @@ artifact tree). Use --output to choose the local destination directory
 
 	cmd.Flags().StringVarP(&opts.output, "output", "o", ".", "Local directory to save artifacts to")
 	cmd.Flags().StringVarP(&opts.path, "path", "p", "", "Download artifacts under this subdirectory")
-	cmd.Flags().StringVarP(&opts.artifact, "artifact", "a", "", "Artifact name pattern to filter")
+	cmd.Flags().StringVarP(&opts.artifact, "artifact", "a", "", "Artifact pattern to filter, matched against the path or the file name")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 10*time.Minute, "Download timeout (e.g. 30m, 1h)")
+	cmd.Flags().IntVar(&opts.parallel, "parallel", 4, "Number of files to download at once")
+	addRunJobFlag(cmd, &opts.job)
 
 	_ = cmd.MarkFlagDirname("output")
 
@@ -57,12 +68,21 @@ artifact tree). Use --output to choose the local destination directory
 }
 
 func runRunDownload(f *cmdutil.Factory, runID string, opts *runDownloadOptions) error {
+	if opts.parallel < 1 {
+		return api.Validation(fmt.Sprintf("--parallel must be at least 1, got %d", opts.parallel), "Pass how many files to download at once, e.g. --parallel 4")
+	}
+
 	p := f.Printer
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
 	absOutput, err := filepath.Abs(opts.output)
 	if err != nil {
 		return fmt.Errorf("failed to resolve output path: %w", err)
@@ -75,7 +95,7 @@ func runRunDownload(f *cmdutil.Factory, runID string, opts *runDownloadOptions)
 	ctx, cancel := context.WithTimeout(f.Context(), opts.timeout)
 	defer cancel()
 
-	flatList, totalSize, err := fetchAllArtifacts(ctx, client, runID, opts.path)
+	flatList, totalSize, err := cmdutil.FetchAllArtifacts(ctx, client, runID, opts.path)
 	if err != nil {
 		return fmt.Errorf("failed to get artifacts: %w", err)
 	}
@@ -113,63 +133,159 @@ func runRunDownload(f *cmdutil.Factory, runID string, opts *runDownloadOptions)
 		humanize.IBytes(uint64(totalSize)), opts.output)
 	_, _ = fmt.Fprintf(p.Out, "%-*s  %10s\n", nameWidth, "NAME", "SIZE")
 
-	downloaded := 0
+	start := time.Now()
+	progress := newDownloadProgress(p.Out, nameWidth, len(flatList), totalSize, opts.parallel, output.IsTerminal() && !p.Quiet)
+	limiter := f.NewLimiter(opts.parallel)
+	var downloaded atomic.Int32
+	var downloadedSize atomic.Int64
+	var wg sync.WaitGroup
 	for _, artifact := range flatList {
-		rel, err := filepath.Rel(absOutput, filepath.Join(absOutput, artifact.Name))
-		if err != nil || !filepath.IsLocal(rel) {
-			_, _ = fmt.Fprintf(p.Out, "%-*s  %10s  %s path escapes output directory\n", nameWidth, artifact.Name, "", output.Red("   "+output.Sym().Cross))
-			continue
-		}
-		outputPath := filepath.Join(absOutput, rel)
-		size := humanize.IBytes(uint64(artifact.Size))
+		wg.Go(func() {
+			size := humanize.IBytes(uint64(artifact.Size))
+			rel, err := filepath.Rel(absOutput, filepath.Join(absOutput, artifact.Name))
+			if err != nil || !filepath.IsLocal(rel) {
+				progress.finish(fmt.Sprintf("%-*s  %10s  %s path escapes output directory", nameWidth, artifact.Name, "", output.Red("   "+output.Sym().Cross)))
+				return
+			}
 
-		if err := downloadArtifact(ctx, client, runID, artifact, outputPath, nameWidth, p.Quiet, p.Out); err != nil {
-			_, _ = fmt.Fprintf(p.Out, "%-*s  %10s  %s %v\n", nameWidth, artifact.Name, size, output.Red("   "+output.Sym().Cross), err)
-			continue
-		}
-		_, _ = fmt.Fprintf(p.Out, "%-*s  %10s  %s\n", nameWidth, artifact.Name, size, output.Green("   "+output.Sym().Check))
-		downloaded++
+			limiter.Acquire()
+			defer limiter.Release()
+			written, err := downloadArtifact(ctx, client, runID, artifact, filepath.Join(absOutput, rel), progress)
+			if err != nil {
+				progress.finish(fmt.Sprintf("%-*s  %10s  %s %v", nameWidth, artifact.Name, size, output.Red("   "+output.Sym().Cross), err))
+				return
+			}
+			downloaded.Add(1)
+			downloadedSize.Add(written)
+			progress.finish(fmt.Sprintf("%-*s  %10s  %s", nameWidth, artifact.Name, size, output.Green("   "+output.Sym().Check)))
+		})
 	}
+	wg.Wait()
 
-	if downloaded < len(flatList) {
-		return fmt.Errorf("downloaded %d of %d artifacts", downloaded, len(flatList))
+	if n := int(downloaded.Load()); n < len(flatList) {
+		return fmt.Errorf("downloaded %d of %d artifacts", n, len(flatList))
 	}
 
-	_, _ = fmt.Fprintf(p.Out, "\n%s %s downloaded\n", output.Green(output.Sym().Check), english.Plural(downloaded, "artifact", ""))
+	_, _ = fmt.Fprintf(p.Out, "\n%s %s downloaded (%s in %s)\n", output.Green(output.Sym().Check), english.Plural(len(flatList), "artifact", ""),
+		humanize.IBytes(uint64(downloadedSize.Load())), output.FormatDuration(time.Since(start)))
 	return nil
 }
 
-func downloadArtifact(ctx context.Context, client api.ClientInterface, runID string, artifact api.Artifact, outputPath string, nameWidth int, quiet bool, out io.Writer) error {
+// downloadArtifact saves one artifact to outputPath, reporting its bytes to progress when that is not nil, and
+// returns how many bytes it wrote. A partial file is removed.
+func downloadArtifact(ctx context.Context, client api.ClientInterface, runID string, artifact api.Artifact, outputPath string, progress *downloadProgress) (int64, error) {
 	if dir := filepath.Dir(outputPath); dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
+			return 0, err
 		}
 	}
 
 	f, err := os.Create(outputPath)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	var w io.Writer = f
-	if output.IsTerminal() && !quiet && artifact.Size > 0 {
-		pw := output.NewProgressWriter(f, out, artifact.Name, humanize.IBytes(uint64(artifact.Size)), artifact.Size, nameWidth)
-		w = pw
-		defer pw.Clear()
+	if progress != nil {
+		var done func()
+		w, done = progress.track(f, artifact)
+		defer done()
 	}
 
 	written, err := client.DownloadArtifactTo(ctx, runID, artifact.Name, w)
 	if err != nil {
 		_ = f.Close()
 		_ = os.Remove(outputPath)
-		return err
+		return 0, err
 	}
 
 	if artifact.Size > 0 && written != artifact.Size {
 		_ = f.Close()
 		_ = os.Remove(outputPath)
-		return fmt.Errorf("incomplete: got %d/%d bytes", written, artifact.Size)
+		return 0, fmt.Errorf("incomplete: got %d/%d bytes", written, artifact.Size)
 	}
 
-	return f.Close()
+	return written, f.Close()
+}
+
+// downloadProgress prints a download's result lines and, on a terminal, its progress: a bar for the current file
+// when files download one at a time, or one line totalling every file when several download at once.
+type downloadProgress struct {
+	out       io.Writer
+	nameWidth int
+	perFile   bool
+	overall   bool
+
+	mu         sync.Mutex
+	files      int
+	done       int
+	total      int64
+	written    int64
+	lastUpdate time.Time
+}
+
+func newDownloadProgress(out io.Writer, nameWidth, files int, total int64, parallel int, terminal bool) *downloadProgress {
+	return &downloadProgress{
+		out:       out,
+		nameWidth: nameWidth,
+		perFile:   terminal && parallel == 1,
+		overall:   terminal && parallel > 1,
+		files:     files,
+		total:     total,
+	}
+}
+
+// track wraps w to report what is written for artifact; call done once the download ends.
+func (d *downloadProgress) track(w io.Writer, artifact api.Artifact) (tracked io.Writer, done func()) {
+	switch {
+	case d.perFile && artifact.Size > 0:
+		pw := output.NewProgressWriter(w, d.out, artifact.Name, humanize.IBytes(uint64(artifact.Size)), artifact.Size, d.nameWidth)
+		return pw, pw.Clear
+	case d.overall:
+		return &progressCounter{w: w, progress: d}, func() {}
+	}
+	return w, func() {}
+}
+
+func (d *downloadProgress) add(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.written += int64(n)
+	if now := time.Now(); now.Sub(d.lastUpdate) >= 100*time.Millisecond {
+		d.lastUpdate = now
+		d.draw()
+	}
+}
+
+// finish prints a file's result line, keeping the overall progress line below it.
+func (d *downloadProgress) finish(line string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.done++
+	if d.overall {
+		output.ClearLine(d.out)
+	}
+	_, _ = fmt.Fprintln(d.out, line)
+	if d.overall && d.done < d.files {
+		d.draw()
+	}
+}
+
+func (d *downloadProgress) draw() {
+	pct := 100
+	if d.total > 0 {
+		pct = int(min(d.written*100/d.total, 100))
+	}
+	_, _ = fmt.Fprintf(d.out, "\r%d/%d files  %s of %s  %3d%%", d.done, d.files, humanize.IBytes(uint64(d.written)), humanize.IBytes(uint64(d.total)), pct)
+}
+
+type progressCounter struct {
+	w        io.Writer
+	progress *downloadProgress
+}
+
+func (c *progressCounter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.progress.add(n)
+	return n, err
 }
@@ -0,0 +1,236 @@
+package run
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/datebucket"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type runHistoryOptions struct {
+	job       string
+	branch    string
+	since     string
+	groupBy   string
+	weekStart string
+	maxRuns   int
+	json      bool
+}
+
+// historyBucket is one day, week, or month of a job's finished runs.
+type historyBucket struct {
+	Start           string `json:"start"`
+	Label           string `json:"label"`
+	Runs            int    `json:"runs"`
+	Succeeded       int    `json:"succeeded"`
+	Failed          int    `json:"failed"`
+	AverageDuration int    `json:"averageDurationSeconds,omitempty"`
+
+	duration time.Duration
+	timed    int
+}
+
+type historyResult struct {
+	Job       string          `json:"job"`
+	Branch    string          `json:"branch,omitempty"`
+	Since     string          `json:"since"`
+	GroupBy   string          `json:"groupBy"`
+	WeekStart string          `json:"weekStart,omitempty"`
+	Truncated bool            `json:"truncated,omitempty"`
+	Buckets   []historyBucket `json:"buckets"`
+}
+
+func newRunHistoryCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runHistoryOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Summarize a job's runs by day, week, or month",
+		Long: `Count a job's finished runs per day, week, or month, with how many succeeded
+and failed and how long they took on average. Periods without runs are shown
+too, so gaps stand out.
+
+Periods follow the local calendar and time zone. Weeks start on the day your
+locale (LC_ALL, LC_TIME, or LANG) starts them, Sunday or Monday; override it
+with --week-start. Weeks are labeled with their ISO 8601 week number. Dates and
+months use the date_format config key (iso, us, eu, or uk), or your locale's
+usual format when it is unset.
+
+With no --job, uses the linked default job from teamcity.toml.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity run history --job Falcon_Build
+  teamcity run history --job Falcon_Build --group-by week --since 12w
+  teamcity run history --job Falcon_Build --group-by week --week-start sunday
+  teamcity run history --job Falcon_Build --group-by month --since 1y --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.job = f.ResolveDefaultJob(opts.job)
+			if opts.job == "" {
+				return api.Validation(
+					"job id is required",
+					"Pass --job <id> or run 'teamcity link' to bind this repository to a job",
+				)
+			}
+			return runRunHistory(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Job ID to summarize")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Only count runs on this branch (or '@this' for current git branch)")
+	cmd.Flags().StringVar(&opts.since, "since", "30d", "Count runs finished after this time (e.g., 14d, 12w, 2026-01-01)")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", string(datebucket.Day), "Period to group runs by: "+strings.Join(datebucket.Periods, ", "))
+	cmd.Flags().StringVar(&opts.weekStart, "week-start", "", "First day of the week: sunday or monday (default: from locale)")
+	cmd.Flags().IntVar(&opts.maxRuns, "max-runs", 2000, "Maximum number of runs to count, newest first")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+	_ = cmd.RegisterFlagCompletionFunc("branch", completion.Branches())
+	_ = cmd.RegisterFlagCompletionFunc("group-by", completion.Fixed(datebucket.Periods...))
+	_ = cmd.RegisterFlagCompletionFunc("week-start", completion.Fixed(datebucket.WeekStarts...))
+
+	return cmd
+}
+
+func runRunHistory(f *cmdutil.Factory, opts *runHistoryOptions) error {
+	period, err := datebucket.ParsePeriod(opts.groupBy)
+	if err != nil {
+		return err
+	}
+	weekStart := datebucket.LocaleWeekStart(datebucket.Locale())
+	if opts.weekStart != "" {
+		if weekStart, err = datebucket.ParseWeekStart(opts.weekStart); err != nil {
+			return err
+		}
+	}
+	if opts.maxRuns < 1 {
+		return fmt.Errorf("--max-runs must be at least 1, got %d", opts.maxRuns)
+	}
+	sinceDate, err := api.ParseUserDate(opts.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+	since, err := api.ParseTeamCityTime(sinceDate)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+	branch, err := resolveBranchFlag(opts.branch)
+	if err != nil {
+		return err
+	}
+
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	builds, truncated, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+		BuildTypeID: opts.job,
+		Branch:      branch,
+		State:       "finished",
+		SinceDate:   sinceDate,
+		Limit:       opts.maxRuns,
+		Fields:      []string{"id", "status", "startDate", "finishDate"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	result := historyResult{Job: opts.job, Branch: branch, Since: opts.since, GroupBy: string(period), Truncated: truncated}
+	if period == datebucket.Week {
+		result.WeekStart = strings.ToLower(weekStart.String())
+	}
+	result.Buckets = groupRunHistory(builds.Builds, since.In(time.Local), time.Now(), period, weekStart, cmdutil.DateLayout())
+
+	if opts.json {
+		return p.PrintJSON(result)
+	}
+	printRunHistory(p, result, opts.maxRuns)
+	return nil
+}
+
+// groupRunHistory counts runs into every period from the one containing from to the one containing to,
+// on the calendar of from's location. Runs are placed by their finish date.
+func groupRunHistory(builds []api.Build, from, to time.Time, period datebucket.Period, weekStart time.Weekday, layout string) []historyBucket {
+	starts := datebucket.Range(from, to, period, weekStart)
+	buckets := make([]historyBucket, len(starts))
+	index := make(map[time.Time]int, len(starts))
+	for i, s := range starts {
+		buckets[i] = historyBucket{Start: s.Format(time.DateOnly), Label: datebucket.Label(s, period, layout)}
+		index[s] = i
+	}
+
+	for _, b := range builds {
+		finished, err := api.ParseTeamCityTime(b.FinishDate)
+		if err != nil {
+			continue
+		}
+		i, ok := index[datebucket.Start(finished.In(from.Location()), period, weekStart)]
+		if !ok {
+			continue
+		}
+		bucket := &buckets[i]
+		bucket.Runs++
+		switch strings.ToUpper(b.Status) {
+		case "SUCCESS":
+			bucket.Succeeded++
+		case "FAILURE", "ERROR":
+			bucket.Failed++
+		}
+		if started, err := api.ParseTeamCityTime(b.StartDate); err == nil && !finished.Before(started) {
+			bucket.duration += finished.Sub(started)
+			bucket.timed++
+		}
+	}
+
+	for i := range buckets {
+		if b := &buckets[i]; b.timed > 0 {
+			b.AverageDuration = int((b.duration / time.Duration(b.timed)).Seconds())
+		}
+	}
+	return buckets
+}
+
+func printRunHistory(p *output.Printer, result historyResult, maxRuns int) {
+	headers := []string{strings.ToUpper(result.GroupBy), "RUNS", "PASSED", "FAILED", "SUCCESS", "AVG DURATION"}
+	rows := make([][]string, 0, len(result.Buckets))
+	total, succeeded := 0, 0
+	for _, b := range result.Buckets {
+		total += b.Runs
+		succeeded += b.Succeeded
+		if b.Runs == 0 {
+			rows = append(rows, []string{b.Label, output.Faint("0"), output.Faint("-"), output.Faint("-"), output.Faint("-"), output.Faint("-")})
+			continue
+		}
+		failed := strconv.Itoa(b.Failed)
+		if b.Failed > 0 {
+			failed = output.Red(failed)
+		}
+		duration := "-"
+		if b.AverageDuration > 0 {
+			duration = output.FormatDuration(time.Duration(b.AverageDuration) * time.Second)
+		}
+		rows = append(rows, []string{
+			b.Label,
+			strconv.Itoa(b.Runs),
+			strconv.Itoa(b.Succeeded),
+			failed,
+			fmt.Sprintf("%d%%", b.Succeeded*100/b.Runs),
+			duration,
+		})
+	}
+
+	_, _ = fmt.Fprintf(p.Out, "%s %s%s since %s\n\n", output.Bold("Run history of"), output.Cyan(result.Job), onBranch(result.Branch), result.Since)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintf(p.Out, "%d runs, %d succeeded\n", total, succeeded)
+	if result.Truncated {
+		p.Tip("Only the newest %d runs were counted; raise --max-runs or narrow --since", maxRuns)
+	}
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/datebucket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyRun(status string, start time.Time, took time.Duration) api.Build {
+	return api.Build{Status: status, StartDate: api.FormatTeamCityTime(start), FinishDate: api.FormatTeamCityTime(start.Add(took))}
+}
+
+func TestGroupRunHistory(t *testing.T) {
+	from := time.Date(2025, 12, 27, 9, 0, 0, 0, time.UTC) // Saturday
+	to := time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC)     // Tuesday
+	runs := []api.Build{
+		historyRun("SUCCESS", time.Date(2025, 12, 27, 10, 0, 0, 0, time.UTC), 10*time.Minute),
+		// Sunday: the last day of a Monday week, the first of a Sunday week.
+		historyRun("FAILURE", time.Date(2026, 1, 4, 22, 0, 0, 0, time.UTC), 20*time.Minute),
+		historyRun("SUCCESS", time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), 30*time.Minute),
+		historyRun("UNKNOWN", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), time.Minute),
+		{Status: "SUCCESS", FinishDate: "garbage"},
+	}
+
+	monday := groupRunHistory(runs, from, to, datebucket.Week, time.Monday, "2006-01-02")
+	require.Len(t, monday, 3)
+	assert.Equal(t, historyBucket{Start: "2025-12-22", Label: "2025-W52 (2025-12-22)", Runs: 1, Succeeded: 1, AverageDuration: 600}, stripTotals(monday[0]))
+	assert.Equal(t, "2026-W01 (2025-12-29)", monday[1].Label)
+	assert.Equal(t, 1, monday[1].Failed)
+	assert.Equal(t, 2, monday[2].Runs)
+
+	sunday := groupRunHistory(runs, from, to, datebucket.Week, time.Sunday, "01/02/2006")
+	require.Len(t, sunday, 3)
+	assert.Equal(t, "2026-W01 (12/28/2025)", sunday[1].Label)
+	assert.Equal(t, 0, sunday[1].Runs)
+	assert.Equal(t, 3, sunday[2].Runs, "Sunday's run opens the Sunday week")
+	assert.Equal(t, 1, sunday[2].Failed)
+	assert.Equal(t, (20*60+30*60+60)/3, sunday[2].AverageDuration)
+
+	days := groupRunHistory(runs, from, to, datebucket.Day, time.Monday, "02.01.2006")
+	require.Len(t, days, 11, "every day of the window is listed, with or without runs")
+	assert.Equal(t, "27.12.2025", days[0].Label)
+	assert.Equal(t, 2, days[9].Runs)
+}
+
+// stripTotals drops the unexported running totals so buckets compare by their output fields.
+func stripTotals(b historyBucket) historyBucket {
+	b.duration, b.timed = 0, 0
+	return b
+}
@@ -1,6 +1,7 @@
 package run
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"slices"
@@ -20,20 +21,26 @@ var runListConfigCurrentUserFn = config.GetCurrentUser
 var runListAPICurrentUserFn = func(client api.ClientInterface) (*api.User, error) { return client.GetCurrentUser() } // used in tests
 
 type runListOptions struct {
-	job        string
-	branch     string
-	status     string
-	user       string
-	revision   string
-	favorites  bool
-	project    string
-	limit      int
-	since      string
-	until      string
-	jsonFields string
-	plain      bool
-	noHeader   bool
+	job            string
+	branch         string
+	status         string
+	user           string
+	revision       string
+	tag            string
+	favorites      bool
+	project        string
+	limit          int
+	since          string
+	until          string
+	longerThan     string
+	shorterThan    string
+	includeRunning bool
+	jsonFields     string
+	template       string
+	plain          bool
+	noHeader       bool
 	cmdutil.ViewOptions
+	cmdutil.SortFlags
 }
 
 func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
@@ -43,6 +50,12 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List recent runs",
+		Long: `List recent runs, newest first.
+
+Runs are sorted by age: running runs first, then finished runs by when
+they finished and queued runs by when they were queued. Use --sort to
+order by other columns. With a --limit, the most recent runs are fetched
+first and only those are sorted.`,
 		Example: `  teamcity run list
   teamcity run list --favorites
   teamcity run list --user @me --limit 1
@@ -52,10 +65,15 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
   teamcity run list --branch @this
   teamcity run list --revision abc1234
   teamcity run list --revision @head --job Falcon_Build
+  teamcity run list --job Falcon_Build --tag release-2024.3
   teamcity run list --since 24h
+  teamcity run list --job Falcon_Build --longer-than 30m
+  teamcity run list --shorter-than 2m --status success
+  teamcity run list --job Falcon_Build --sort duration --desc
   teamcity run list --json
   teamcity run list --json=id,status,webUrl
   teamcity run list --plain | grep failure
+  teamcity run list --template '{{.ID}} {{.Status}} {{.BranchName}}'
   teamcity run list --favorites --web`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRunList(f, cmd, opts)
@@ -67,30 +85,74 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.status, "status", "", "Filter by status (success, failure, running, queued, error, unknown)")
 	cmd.Flags().StringVarP(&opts.user, "user", "u", "", "Filter by user who triggered")
 	cmd.Flags().StringVar(&opts.revision, "revision", "", "Filter by VCS revision/commit SHA (or '@head' for current HEAD)")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "Filter by tag")
 	cmd.Flags().BoolVar(&opts.favorites, "favorites", false, "Show favorites for the current user")
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Filter by project ID")
 	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 30, "Maximum number of items (0 for all)")
-	cmd.Flags().StringVar(&opts.since, "since", "", "Finished after this time (e.g., 24h, 7d, 2026-01-21)")
-	cmd.Flags().StringVar(&opts.until, "until", "", "Finished before this time (e.g., 12h, 7d, 2026-01-22)")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Finished after this time (e.g., 30m, 7d, 2026-01-21, 2026-01-21T15:04)")
+	cmd.Flags().StringVar(&opts.until, "until", "", "Finished before this time (e.g., 12h, 7d, 2026-01-22, 2026-01-22T09:00)")
+	cmd.Flags().StringVar(&opts.longerThan, "longer-than", "", "Took longer than this (e.g., 10m, 1h30m)")
+	cmd.Flags().StringVar(&opts.shorterThan, "shorter-than", "", "Took less than this (e.g., 90s, 5m)")
+	cmd.Flags().BoolVar(&opts.includeRunning, "include-running", false, "Let duration filters match running runs by their elapsed time")
 	cmdutil.AddJSONFieldsFlag(cmd, &opts.jsonFields)
 	cmd.Flags().BoolVar(&opts.plain, "plain", false, "Output in plain text format for scripting")
 	cmd.Flags().BoolVar(&opts.noHeader, "no-header", false, "Omit header row (use with --plain)")
+	cmdutil.AddSortFlags(cmd, &opts.SortFlags, runSortColumns, "age")
 	cmdutil.AddWebFlags(cmd, &opts.ViewOptions)
+	cmdutil.AddTemplateFlag(cmd, &opts.template)
 
 	cmd.MarkFlagsMutuallyExclusive("json", "plain")
+	// Favorites are a private tag, and a locator takes one tag: dimension, so the two can't be combined.
+	cmd.MarkFlagsMutuallyExclusive("tag", "favorites")
 
 	_ = cmd.RegisterFlagCompletionFunc("status", completion.RunStatuses())
-	_ = cmd.RegisterFlagCompletionFunc("branch", completion.GitBranches())
+	_ = cmd.RegisterFlagCompletionFunc("branch", completion.Branches())
 	_ = cmd.RegisterFlagCompletionFunc("revision", completion.AtHead())
 	_ = cmd.RegisterFlagCompletionFunc("user", completion.AtMe())
-	_ = cmd.RegisterFlagCompletionFunc("job", completion.LinkedJobs())
-	_ = cmd.RegisterFlagCompletionFunc("project", completion.LinkedProjects())
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects())
 
 	return cmd
 }
 
+var runSortColumns = []cmdutil.SortColumn[api.Build]{
+	{Name: "status", Fields: []string{"status", "state"}, Compare: func(a, b api.Build) int {
+		return cmdutil.CompareFold(output.PlainStatusText(a.Status, a.State, a.StatusText), output.PlainStatusText(b.Status, b.State, b.StatusText))
+	}},
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b api.Build) int { return cmp.Compare(a.ID, b.ID) }},
+	{Name: "job", Fields: []string{"buildTypeId"}, Compare: func(a, b api.Build) int { return cmdutil.CompareFold(a.BuildTypeID, b.BuildTypeID) }},
+	{Name: "branch", Fields: []string{"branchName"}, Compare: func(a, b api.Build) int { return cmdutil.CompareFold(a.BranchName, b.BranchName) }},
+	{Name: "user", Fields: []string{"triggered.type", "triggered.user.name"}, Compare: func(a, b api.Build) int {
+		return cmdutil.CompareFold(cmdutil.TriggeredBy(a.Triggered), cmdutil.TriggeredBy(b.Triggered))
+	}},
+	{Name: "duration", Fields: []string{"startDate", "finishDate"}, Compare: func(a, b api.Build) int {
+		return cmp.Compare(runSortDuration(a), runSortDuration(b))
+	}},
+	{Name: "age", Fields: []string{"state", "finishDate", "queuedDate"}, Compare: compareRunAge},
+}
+
+// runSortDuration is the DURATION column as a number; runs that have not started sort before any that have.
+func runSortDuration(r api.Build) time.Duration {
+	if elapsed, _, ok := runDuration(r); ok {
+		return elapsed
+	}
+	return -1
+}
+
+// compareRunAge orders runs by the AGE column, youngest first: running runs, then the rest by when they
+// finished, or were queued if they have not.
+func compareRunAge(a, b api.Build) int {
+	if c := cmdutil.CompareBools(b.State == "running", a.State == "running"); c != 0 {
+		return c
+	}
+	return cmdutil.CompareTimes(cmp.Or(b.FinishDate, b.QueuedDate), cmp.Or(a.FinishDate, a.QueuedDate))
+}
+
 func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) error {
-	if err := cmdutil.ValidateLimit(opts.limit); err != nil {
+	if err := cmdutil.CheckLimit(f, opts.limit); err != nil {
+		return err
+	}
+	if err := cmdutil.ValidateSort(&opts.SortFlags, runSortColumns); err != nil {
 		return err
 	}
 	// --web validates the same query flags before navigating, so a bad value is reported rather than masked.
@@ -101,6 +163,9 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 		if _, _, err := resolveRunListDateRange(opts); err != nil {
 			return err
 		}
+		if _, err := resolveRunListDurationFilter(opts); err != nil {
+			return err
+		}
 		if done, err := opts.EmitListWebURL(f.Printer, config.ResolveServerURL(), resolveRunListWebPath(opts)); done {
 			return err
 		}
@@ -112,6 +177,17 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 	if showHelp {
 		return nil
 	}
+	if err := cmdutil.ValidateSortFields(&opts.SortFlags, runSortColumns, jsonResult.Fields); err != nil {
+		return err
+	}
+	tmpl, err := cmdutil.ParseTemplate(opts.template)
+	if err != nil {
+		return err
+	}
+	fields := jsonResult.Fields
+	if tmpl != nil {
+		fields = api.BuildFields.Available
+	}
 
 	client, err := f.Client()
 	if err != nil {
@@ -130,26 +206,52 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 		opts.job = f.ResolveDefaultJob("")
 	}
 
-	request, err := resolveRunListRequest(client, opts, jsonResult.Fields)
+	request, err := resolveRunListRequest(client, opts, fields)
 	if err != nil {
 		return err
 	}
 
-	runs, truncated, err := client.GetBuilds(f.Context(), request.builds)
-	if err != nil {
+	var runs *api.BuildList
+	var truncated bool
+	exhausted := 0
+	if request.duration.active() {
+		scan, err := getBuildsByDuration(f.Context(), client, request.builds, request.duration, opts.limit)
+		if err != nil {
+			return err
+		}
+		runs, truncated, exhausted = scan.runs, scan.truncated, scan.exhausted
+	} else {
+		runs, truncated, err = client.GetBuilds(f.Context(), request.builds)
+		if err != nil && (runs == nil || !cmdutil.Interrupted(f, err)) {
+			return err
+		}
+	}
+	// Interrupted while paging: show the runs that arrived, then say so.
+	interrupted := err != nil
+	if err := cmdutil.SortItems(runs.Builds, &opts.SortFlags, runSortColumns); err != nil {
 		return err
 	}
 
-	if jsonResult.Enabled {
-		if err := f.Printer.PrintJSON(runs); err != nil {
+	if jsonResult.Enabled || tmpl != nil {
+		if tmpl != nil {
+			err = tmpl.ExecuteEach(f.Printer.Out, runs.Builds)
+		} else {
+			err = f.Printer.PrintJSON(runs)
+		}
+		if err != nil {
 			return err
 		}
-		cmdutil.WarnListTruncated(f, truncated, opts.limit)
+		if interrupted {
+			return cmdutil.WarnInterrupted(f, runs.Count, "run")
+		}
+		cmdutil.WarnSortedListTruncated(f, &opts.SortFlags, truncated, opts.limit)
+		warnDurationScanExhausted(f, request.duration, runs.Count, exhausted)
 		return nil
 	}
 
 	if runs.Count == 0 {
 		f.Printer.Empty(request.emptyMsg, request.emptyTip)
+		warnDurationScanExhausted(f, request.duration, 0, exhausted)
 		return nil
 	}
 
@@ -168,28 +270,21 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 			runRef = strconv.Itoa(r.ID)
 		} else {
 			status = fmt.Sprintf("%s %s", output.StatusIcon(r.Status, r.State, r.StatusText), output.StatusText(r.Status, r.State, r.StatusText))
-			runRef = fmt.Sprintf("%d  #%s", r.ID, r.Number)
+			runRef = strings.TrimSpace(fmt.Sprintf("%d  %s", r.ID, cmdutil.RunNumber(r.Number)))
 		}
 
-		triggeredBy := "-"
-		if r.Triggered != nil && r.Triggered.User != nil {
-			triggeredBy = r.Triggered.User.Name
-		} else if r.Triggered != nil {
-			triggeredBy = r.Triggered.Type
-		}
+		triggeredBy := cmp.Or(cmdutil.TriggeredBy(r.Triggered), "-")
 
 		duration := "-"
 		age := "-"
 
-		if r.StartDate != "" {
-			startTime, _ := api.ParseTeamCityTime(r.StartDate)
-			if r.FinishDate != "" {
+		if elapsed, running, ok := runDuration(r); ok {
+			duration = output.FormatDuration(elapsed)
+			if running {
+				age = "now"
+			} else {
 				finishTime, _ := api.ParseTeamCityTime(r.FinishDate)
-				duration = output.FormatDuration(finishTime.Sub(startTime))
 				age = output.RelativeTime(finishTime)
-			} else {
-				duration = output.FormatDuration(time.Since(startTime))
-				age = "now"
 			}
 		} else if r.QueuedDate != "" {
 			queuedTime, _ := api.ParseTeamCityTime(r.QueuedDate)
@@ -204,7 +299,7 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 		rows = append(rows, []string{
 			status,
 			runRef,
-			r.BuildTypeID,
+			cmp.Or(r.BuildTypeID, "-"),
 			branch,
 			triggeredBy,
 			duration,
@@ -219,12 +314,26 @@ func runRunList(f *cmdutil.Factory, cmd *cobra.Command, opts *runListOptions) er
 		output.AutoSizeColumns(headers, rows, 2, 2, 3, 4)
 		p.PrintTable(headers, rows)
 	}
-	cmdutil.WarnListTruncated(f, truncated, opts.limit)
+	if interrupted {
+		return cmdutil.WarnInterrupted(f, runs.Count, "run")
+	}
+	cmdutil.WarnSortedListTruncated(f, &opts.SortFlags, truncated, opts.limit)
+	warnDurationScanExhausted(f, request.duration, runs.Count, exhausted)
 	return nil
 }
 
+// warnDurationScanExhausted notes on stderr that a duration filter ran out of runs to scan before filling --limit.
+func warnDurationScanExhausted(f *cmdutil.Factory, filter durationFilter, matched, scanned int) {
+	if scanned == 0 || f.Printer.Quiet {
+		return
+	}
+	_, _ = fmt.Fprintln(f.Printer.ErrOut)
+	f.Printer.Warn("Only %d of the %d most recent runs took %s - narrow with --job or --since, or use --limit 0 to scan all", matched, scanned, filter.describe())
+}
+
 type runListRequest struct {
 	builds   api.BuildsOptions
+	duration durationFilter
 	emptyMsg string
 	emptyTip string
 }
@@ -255,6 +364,11 @@ func resolveRunListRequest(client api.ClientInterface, opts *runListOptions, fie
 		return nil, err
 	}
 
+	duration, err := resolveRunListDurationFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	return &runListRequest{
 		builds: api.BuildsOptions{
 			BuildTypeID: opts.job,
@@ -264,12 +378,14 @@ func resolveRunListRequest(client api.ClientInterface, opts *runListOptions, fie
 			User:        user,
 			Project:     opts.project,
 			Revision:    revision,
+			Tag:         opts.tag,
 			Favorites:   opts.favorites,
 			Limit:       opts.limit,
 			SinceDate:   sinceDate,
 			UntilDate:   untilDate,
 			Fields:      fields,
 		},
+		duration: duration,
 		emptyMsg: resolveRunListEmptyMessage(opts),
 		emptyTip: resolveRunListEmptyTip(opts),
 	}, nil
@@ -350,8 +466,11 @@ func resolveRunListWebPath(opts *runListOptions) string {
 }
 
 func resolveRunListEmptyMessage(opts *runListOptions) string {
-	if opts.favorites {
+	switch {
+	case opts.favorites:
 		return "No favorite runs found"
+	case opts.tag != "":
+		return fmt.Sprintf("No runs tagged %q found", opts.tag)
 	}
 	return "No runs found"
 }
@@ -365,68 +484,146 @@ func resolveRunListEmptyTip(opts *runListOptions) string {
 
 func newRunViewCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &cmdutil.ViewOptions{}
+	watch := &runViewWatchOptions{}
+	copyOpts := &cmdutil.CopyOptions{}
+	var job, template string
+	var full bool
 	cmd := &cobra.Command{
 		Use:     "view <id>",
 		Aliases: []string{"show"},
 		Short:   "View details",
-		Args:    cobra.ExactArgs(1),
+		Long: `View the details of a run.
+
+The view shows how long the run waited in the queue and, for a failed run,
+its build problems and first few failed tests. --full adds its comment,
+artifact count and size, and the snapshot and artifact dependencies with
+their statuses; these take more requests. --json includes whatever the view
+fetched, and --template renders the same fields through a Go template.
+
+With --watch, the view refreshes every --interval seconds until the run
+finishes, adding the step it is on, its elapsed and estimated time, and the
+latest log lines. On a terminal the view is redrawn in place; otherwise each
+change is appended. The final result is printed at the end, and the exit
+code follows it as with 'run watch'.`,
+		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run view 12345
   teamcity run view 12345 --web
-  teamcity run view 12345 --json`,
+  teamcity run view 12345 --json
+  teamcity run view 12345 --full
+  teamcity run view 12345 --template '{{.Status}} {{.StatusText}} (queued {{duration .QueueWait}})'
+  teamcity run view 12345 --watch --interval 10
+  teamcity run view 12345 --copy`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRunView(f, args[0], opts)
+			if err := copyOpts.Validate(); err != nil {
+				return err
+			}
+			tmpl, err := cmdutil.ParseTemplate(template)
+			if err != nil {
+				return err
+			}
+			return runRunView(f, args[0], job, opts, full, tmpl, watch, copyOpts)
 		},
 	}
 	cmdutil.AddViewFlags(cmd, opts)
+	cmdutil.AddCopyFlag(cmd, copyOpts)
+	cmd.Flags().BoolVar(&watch.enabled, "watch", false, "Refresh the view until the run finishes")
+	cmd.Flags().IntVarP(&watch.interval, "interval", "i", 5, "Refresh interval in seconds, with --watch")
+	cmd.MarkFlagsMutuallyExclusive("watch", "json")
+	cmd.Flags().BoolVar(&full, "full", false, "Add the comment, artifacts and dependencies")
+	cmd.MarkFlagsMutuallyExclusive("watch", "web")
+	cmd.MarkFlagsMutuallyExclusive("watch", "full")
+	cmdutil.AddTemplateFlag(cmd, &template)
+	cmd.MarkFlagsMutuallyExclusive("watch", "template")
+	addRunJobFlag(cmd, &job)
 	return cmd
 }
 
-func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) error {
-	p := f.Printer
+func runRunView(f *cmdutil.Factory, runID, job string, opts *cmdutil.ViewOptions, full bool, tmpl *cmdutil.Template, watch *runViewWatchOptions, copyOpts *cmdutil.CopyOptions) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	build, err := client.GetBuild(f.Context(), runID)
+	runID, err = resolveRunRef(f, client, runID, job)
 	if err != nil {
 		return err
 	}
-
-	if done, err := opts.EmitWebURL(p, build.WebURL); done {
+	if watch.enabled {
+		if copyOpts.What != "" {
+			build, err := client.GetBuild(f.Context(), runID)
+			if err != nil {
+				return err
+			}
+			copyOpts.Copy(f.Printer, build.WebURL, strconv.Itoa(build.ID))
+		}
+		return runViewWatch(f, client, runID, watch)
+	}
+	build, err := renderRunView(f, f.Printer, client, runID, opts, full, tmpl)
+	if err != nil {
 		return err
 	}
+	copyOpts.Copy(f.Printer, build.WebURL, strconv.Itoa(build.ID))
+	return nil
+}
+
+// showRunView renders a run read from src. Queue details and agent compatibility are only looked up on a live server.
+func showRunView(f *cmdutil.Factory, src runSource, runID string, opts *cmdutil.ViewOptions) error {
+	_, err := renderRunView(f, f.Printer, src, runID, opts, false, nil)
+	return err
+}
+
+// renderRunView is showRunView writing to p, with the --full details when full is set, or through tmpl when it is
+// set; it returns the run as read, for callers that redraw it.
+func renderRunView(f *cmdutil.Factory, p *output.Printer, src runSource, runID string, opts *cmdutil.ViewOptions, full bool, tmpl *cmdutil.Template) (*api.Build, error) {
+	build, err := src.GetBuild(f.Context(), runID)
+	if err != nil {
+		return nil, err
+	}
 
-	if opts.JSON {
-		reused, _ := client.GetBuildUsedByOtherBuilds(strconv.Itoa(build.ID))
-		build.UsedByOtherBuilds = reused
-		return p.PrintJSON(build)
+	if done, err := opts.EmitWebURL(p, build.WebURL); done {
+		return build, err
 	}
 
-	reused, _ := client.GetBuildUsedByOtherBuilds(strconv.Itoa(build.ID))
+	reused, _ := src.GetBuildUsedByOtherBuilds(strconv.Itoa(build.ID))
 	build.UsedByOtherBuilds = reused
+	batches, _ := src.GetBuildBatches(f.Context(), build)
+
+	client, live := src.(api.ClientInterface)
+	var queued queuedRunInfo
+	if live && build.State == "queued" {
+		queued = fetchQueuedRunInfo(f.Context(), client, build)
+	}
+	details := fetchRunViewDetails(f.Context(), src, build, full)
+
+	if opts.JSON || tmpl != nil {
+		view := runViewJSON{Build: build, Batches: batches, queuedRunInfo: queued, runViewDetails: details}
+		if tmpl != nil {
+			return build, tmpl.Execute(p.Out, view)
+		}
+		return build, p.PrintJSON(view)
+	}
 
-	pipelineRun, _ := client.GetBuildPipelineRun(strconv.Itoa(build.ID))
+	pipelineRun, _ := src.GetBuildPipelineRun(strconv.Itoa(build.ID))
 
 	icon := output.StatusIcon(build.Status, build.State, build.StatusText)
 	jobName := build.BuildTypeID
 	if pipelineRun != nil && pipelineRun.Pipeline != nil && pipelineRun.Pipeline.Name != "" {
 		jobName = pipelineRun.Pipeline.Name + " " + output.Sym().Pipeline
-	} else if build.BuildType != nil {
-		jobName = build.BuildType.Name
+	} else {
+		jobName = cmdutil.JobName(build.BuildType, build.BuildTypeID)
 	}
 
-	_, _ = fmt.Fprintf(p.Out, "%s %s %d  #%s", icon, output.Cyan(jobName), build.ID, build.Number)
+	_, _ = fmt.Fprintf(p.Out, "%s %s %d", icon, output.Cyan(jobName), build.ID)
+	// Queued runs get their number when they start.
+	if build.Number != "" {
+		_, _ = fmt.Fprintf(p.Out, "  #%s", build.Number)
+	}
 	if build.BranchName != "" {
 		_, _ = fmt.Fprintf(p.Out, " "+output.Sym().Sep+" %s", build.BranchName)
 	}
 	_, _ = fmt.Fprintln(p.Out)
 
-	if build.Triggered != nil {
-		triggeredBy := build.Triggered.Type
-		if build.Triggered.User != nil {
-			triggeredBy = build.Triggered.User.Name
-		}
+	if triggeredBy := cmdutil.TriggeredBy(build.Triggered); triggeredBy != "" {
 		_, _ = fmt.Fprintf(p.Out, "Triggered by %s", triggeredBy)
 
 		if build.StartDate != "" {
@@ -438,6 +635,11 @@ func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) err
 				duration := finishTime.Sub(startTime)
 				_, _ = fmt.Fprintf(p.Out, " "+output.Sym().Sep+" Took %s", output.FormatDuration(duration))
 			}
+			if details.QueueWait > 0 {
+				_, _ = fmt.Fprintf(p.Out, " "+output.Sym().Sep+" Waited %s in queue", output.FormatDuration(time.Duration(details.QueueWait)*time.Second))
+			}
+		} else if queuedTime, err := api.ParseTeamCityTime(build.QueuedDate); err == nil {
+			_, _ = fmt.Fprintf(p.Out, " "+output.Sym().Sep+" Queued %s", output.RelativeTime(queuedTime))
 		}
 		_, _ = fmt.Fprintln(p.Out)
 	}
@@ -446,13 +648,35 @@ func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) err
 		_, _ = fmt.Fprintf(p.Out, "\n%s Results shared in build chain\n", output.Yellow(output.Sym().Recycle))
 	}
 
-	if build.StatusText != "" && build.StatusText != build.Status {
+	statusShown := build.StatusText != "" && build.StatusText != build.Status
+	if statusShown {
 		_, _ = fmt.Fprintf(p.Out, "\nStatus: %s\n", build.StatusText)
 	}
 
+	// The question a failed run raises is why; answer it under its status, so it takes no second command.
+	if len(details.Problems) > 0 && !statusShown {
+		_, _ = fmt.Fprintln(p.Out)
+	}
+	printFailureDetails(p, build, details)
+
+	if info := build.CanceledInfo; info != nil {
+		canceled := strings.TrimSpace("Canceled " + cmdutil.CanceledBy(info))
+		if at, err := api.ParseTeamCityTime(info.Timestamp); err == nil {
+			canceled += " " + output.Sym().Sep + " " + output.RelativeTime(at)
+		}
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Yellow(canceled))
+		if info.Text != "" {
+			_, _ = fmt.Fprintf(p.Out, "Comment: %s\n", info.Text)
+		}
+	}
+
+	if build.State == "queued" {
+		printQueuedRunInfo(p, build, queued)
+	}
+
 	if build.State == "queued" && build.WaitReason != "" {
 		_, _ = fmt.Fprintf(p.Out, "\nWait reason: %s\n", output.Yellow(build.WaitReason))
-		if waitReasonIsCompatibility(build.WaitReason) {
+		if live && waitReasonIsCompatibility(build.WaitReason) {
 			renderBuildCompatibility(p.Out, client, build)
 		}
 	}
@@ -461,8 +685,8 @@ func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) err
 		_, _ = fmt.Fprintf(p.Out, "\nProgress: %d%%\n", build.PercentageComplete)
 	}
 
-	if build.Agent != nil {
-		_, _ = fmt.Fprintf(p.Out, "\nAgent: %s", output.Faint(build.Agent.Name))
+	if agent := cmdutil.AgentName(build.Agent); agent != "" {
+		_, _ = fmt.Fprintf(p.Out, "\nAgent: %s", output.Faint(agent))
 		if build.State == "running" {
 			_, _ = fmt.Fprintf(p.Out, "  %s teamcity agent term %d", output.Faint(output.Sym().Sep), build.Agent.ID)
 		}
@@ -481,6 +705,8 @@ func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) err
 		_, _ = fmt.Fprintf(p.Out, "\nTags: %s\n", strings.Join(tagNames, ", "))
 	}
 
+	printFullDetails(p, build, details)
+
 	if pipelineRun != nil && pipelineRun.Jobs != nil && len(pipelineRun.Jobs.Job) > 0 {
 		maxIDLen := 0
 		for _, j := range pipelineRun.Jobs.Job {
@@ -499,7 +725,46 @@ func runRunView(f *cmdutil.Factory, runID string, opts *cmdutil.ViewOptions) err
 		}
 	}
 
-	_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), output.Green(build.WebURL))
+	if len(batches) > 0 {
+		_, _ = fmt.Fprintf(p.Out, "\n%s:\n", output.Cyan("Batches"))
+		printBatches(p, batches)
+	}
 
-	return nil
+	if build.WebURL != "" {
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), output.Green(build.WebURL))
+	}
+
+	return build, nil
+}
+
+// runViewJSON adds the batch builds of a parallel-tests or matrix run, the queue details of a queued run, and the
+// details run view fetched, to the build payload.
+type runViewJSON struct {
+	*api.Build
+	Batches []api.BuildBatch `json:"batches,omitempty"`
+	queuedRunInfo
+	runViewDetails
+}
+
+// printBatches renders one row per batch: number, status, label, run ID and duration.
+func printBatches(p *output.Printer, batches []api.BuildBatch) {
+	headers := []string{"BATCH", "STATUS", "LABEL", "ID", "DURATION"}
+	rows := make([][]string, 0, len(batches))
+	for _, b := range batches {
+		duration := "-"
+		if start, err := api.ParseTeamCityTime(b.Build.StartDate); err == nil {
+			if finish, err := api.ParseTeamCityTime(b.Build.FinishDate); err == nil {
+				duration = output.FormatDuration(finish.Sub(start))
+			}
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(b.Index),
+			output.StatusIcon(b.Build.Status, b.Build.State, b.Build.StatusText) + " " + output.StatusText(b.Build.Status, b.Build.State, b.Build.StatusText),
+			b.Label,
+			strconv.Itoa(b.Build.ID),
+			duration,
+		})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 2)
+	p.PrintTable(headers, rows)
 }
@@ -0,0 +1,168 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+)
+
+// durationScanLimit bounds how many recent runs a duration filter scans when --limit is finite.
+const durationScanLimit = 2000
+
+// durationFilter keeps runs by how long they took. TeamCity's build locator cannot express duration,
+// so the filter runs on the client over a fetched window of recent runs.
+type durationFilter struct {
+	longerThan     time.Duration
+	shorterThan    time.Duration
+	includeRunning bool
+}
+
+func (d durationFilter) active() bool {
+	return d.longerThan > 0 || d.shorterThan > 0
+}
+
+func (d durationFilter) match(b api.Build) bool {
+	elapsed, running, ok := runDuration(b)
+	if !ok || (running && !d.includeRunning) {
+		return false
+	}
+	if d.longerThan > 0 && elapsed <= d.longerThan {
+		return false
+	}
+	return d.shorterThan <= 0 || elapsed < d.shorterThan
+}
+
+// describe renders the filter for messages, e.g. "longer than 10m" or "between 5m and 1h".
+func (d durationFilter) describe() string {
+	switch {
+	case d.longerThan > 0 && d.shorterThan > 0:
+		return fmt.Sprintf("between %s and %s", output.FormatDuration(d.longerThan), output.FormatDuration(d.shorterThan))
+	case d.longerThan > 0:
+		return "longer than " + output.FormatDuration(d.longerThan)
+	default:
+		return "shorter than " + output.FormatDuration(d.shorterThan)
+	}
+}
+
+// runDuration is how long a run took, as the DURATION column shows it: finish minus start, or the time elapsed
+// so far for a run still going. ok is false for runs that never started.
+func runDuration(b api.Build) (elapsed time.Duration, running, ok bool) {
+	if b.StartDate == "" {
+		return 0, false, false
+	}
+	start, err := api.ParseTeamCityTime(b.StartDate)
+	if err != nil {
+		return 0, false, false
+	}
+	if b.FinishDate == "" {
+		return timeref.Since(start), true, true
+	}
+	finish, err := api.ParseTeamCityTime(b.FinishDate)
+	if err != nil {
+		return 0, false, false
+	}
+	return finish.Sub(start), false, true
+}
+
+func resolveRunListDurationFilter(opts *runListOptions) (durationFilter, error) {
+	filter := durationFilter{includeRunning: opts.includeRunning || strings.EqualFold(opts.status, "running")}
+	var err error
+	if opts.longerThan != "" {
+		if filter.longerThan, err = api.ParseUserDuration(opts.longerThan); err != nil {
+			return durationFilter{}, fmt.Errorf("invalid --longer-than: %w", err)
+		}
+	}
+	if opts.shorterThan != "" {
+		if filter.shorterThan, err = api.ParseUserDuration(opts.shorterThan); err != nil {
+			return durationFilter{}, fmt.Errorf("invalid --shorter-than: %w", err)
+		}
+	}
+	if filter.longerThan > 0 && filter.shorterThan > 0 && filter.longerThan >= filter.shorterThan {
+		return durationFilter{}, fmt.Errorf("--longer-than (%s) is not shorter than --shorter-than (%s), resulting in an empty range", opts.longerThan, opts.shorterThan)
+	}
+	if opts.includeRunning && !filter.active() {
+		return durationFilter{}, api.Validation("--include-running requires --longer-than or --shorter-than", "Running runs are listed by default; the flag only decides whether duration filters consider them")
+	}
+	return filter, nil
+}
+
+// durationScan is the outcome of getBuildsByDuration: the matches, up to limit, whether more matches were cut off,
+// and, when the scan window ran out before limit matches were found, how many runs it looked at.
+type durationScan struct {
+	runs      *api.BuildList
+	truncated bool
+	exhausted int
+}
+
+// getBuildsByDuration fetches recent runs and keeps those the filter matches. A finite limit starts with a window
+// of four times the limit and widens it up to durationScanLimit until enough runs match or history runs out.
+func getBuildsByDuration(ctx context.Context, client api.ClientInterface, req api.BuildsOptions, filter durationFilter, limit int) (durationScan, error) {
+	fields := req.Fields
+	if len(fields) > 0 {
+		req.Fields = slices.Clone(fields)
+		for _, f := range []string{"startDate", "finishDate"} {
+			if !slices.Contains(fields, f) {
+				req.Fields = append(req.Fields, f)
+			}
+		}
+	}
+
+	scanLimit := max(durationScanLimit, limit)
+	window := 0
+	if limit > 0 {
+		window = min(limit*4, scanLimit)
+	}
+	for {
+		req.Limit = window
+		runs, more, err := client.GetBuilds(ctx, req)
+		if err != nil {
+			return durationScan{}, err
+		}
+		matches := filterRunsByDuration(runs.Builds, filter)
+		if limit <= 0 || len(matches) >= limit || !more || window >= scanLimit {
+			scan := durationScan{runs: &api.BuildList{}}
+			if limit > 0 && len(matches) > limit {
+				matches, scan.truncated = matches[:limit], true
+			}
+			if limit > 0 && len(matches) < limit && more {
+				scan.exhausted = len(runs.Builds)
+			}
+			stripUnrequestedDates(matches, fields)
+			scan.runs.Builds, scan.runs.Count = matches, len(matches)
+			return scan, nil
+		}
+		window = min(window*4, scanLimit)
+	}
+}
+
+func filterRunsByDuration(builds []api.Build, filter durationFilter) []api.Build {
+	matches := []api.Build{}
+	for _, b := range builds {
+		if filter.match(b) {
+			matches = append(matches, b)
+		}
+	}
+	return matches
+}
+
+// stripUnrequestedDates drops the dates fetched only to filter on, so --json shows just the fields asked for.
+func stripUnrequestedDates(builds []api.Build, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	keepStart, keepFinish := slices.Contains(fields, "startDate"), slices.Contains(fields, "finishDate")
+	for i := range builds {
+		if !keepStart {
+			builds[i].StartDate = ""
+		}
+		if !keepFinish {
+			builds[i].FinishDate = ""
+		}
+	}
+}
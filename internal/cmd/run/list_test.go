@@ -1,7 +1,10 @@
 package run
 
 import (
+	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +24,18 @@ func TestResolveRunListRequestFavorites(T *testing.T) {
 	assert.Equal(T, 30, req.builds.Limit)
 }
 
+func TestResolveRunListRequestTag(T *testing.T) {
+	req, err := resolveRunListRequest(nil, &runListOptions{
+		job:   "Falcon_Build",
+		tag:   "release-2024.3",
+		limit: 30,
+	}, nil)
+	require.NoError(T, err)
+
+	assert.Equal(T, "release-2024.3", req.builds.Tag)
+	assert.Equal(T, `No runs tagged "release-2024.3" found`, req.emptyMsg)
+}
+
 func TestResolveRunListRequestAtMeUsesConfigUser(T *testing.T) {
 	oldConfigUser := runListConfigCurrentUserFn
 	T.Cleanup(func() {
@@ -106,3 +121,144 @@ func TestResolveRunListRequestRevisionAtHeadRequiresGitRepo(T *testing.T) {
 	require.Error(T, err)
 	assert.Contains(T, err.Error(), "git repository")
 }
+
+// durationBuild is a finished run that took d, or a running one started d ago when finish is false.
+func durationBuild(id int, d time.Duration, finished bool) api.Build {
+	start := time.Now().Add(-2 * time.Hour)
+	b := api.Build{ID: id, State: "finished", StartDate: api.FormatTeamCityTime(start)}
+	if !finished {
+		b.State = "running"
+		b.StartDate = api.FormatTeamCityTime(time.Now().Add(-d))
+		return b
+	}
+	b.FinishDate = api.FormatTeamCityTime(start.Add(d))
+	return b
+}
+
+func TestResolveRunListDurationFilter(T *testing.T) {
+	filter, err := resolveRunListDurationFilter(&runListOptions{longerThan: "5m", shorterThan: "1h30m"})
+	require.NoError(T, err)
+	assert.Equal(T, 5*time.Minute, filter.longerThan)
+	assert.Equal(T, 90*time.Minute, filter.shorterThan)
+	assert.False(T, filter.includeRunning)
+	assert.Equal(T, "between 5m 0s and 1h 30m", filter.describe())
+
+	filter, err = resolveRunListDurationFilter(&runListOptions{longerThan: "10m", status: "running"})
+	require.NoError(T, err)
+	assert.True(T, filter.includeRunning, "--status running implies --include-running")
+
+	filter, err = resolveRunListDurationFilter(&runListOptions{})
+	require.NoError(T, err)
+	assert.False(T, filter.active())
+
+	for name, tc := range map[string]struct {
+		opts runListOptions
+		want string
+	}{
+		"bad longer":        {runListOptions{longerThan: "soon"}, "invalid --longer-than"},
+		"bad shorter":       {runListOptions{shorterThan: "10"}, "invalid --shorter-than"},
+		"empty range":       {runListOptions{longerThan: "1h", shorterThan: "30m"}, "resulting in an empty range"},
+		"running no filter": {runListOptions{includeRunning: true}, "--include-running requires"},
+	} {
+		T.Run(name, func(t *testing.T) {
+			_, err := resolveRunListDurationFilter(&tc.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestFilterRunsByDuration(T *testing.T) {
+	builds := []api.Build{
+		durationBuild(1, 2*time.Minute, true),
+		durationBuild(2, 20*time.Minute, true),
+		durationBuild(3, 45*time.Minute, true),
+		durationBuild(4, 30*time.Minute, false),
+		{ID: 5, State: "queued"},
+	}
+	ids := func(filter durationFilter) []int {
+		var out []int
+		for _, b := range filterRunsByDuration(builds, filter) {
+			out = append(out, b.ID)
+		}
+		return out
+	}
+
+	assert.Equal(T, []int{2, 3}, ids(durationFilter{longerThan: 10 * time.Minute}))
+	assert.Equal(T, []int{2, 3, 4}, ids(durationFilter{longerThan: 10 * time.Minute, includeRunning: true}))
+	assert.Equal(T, []int{1}, ids(durationFilter{shorterThan: 10 * time.Minute}))
+	assert.Equal(T, []int{2, 4}, ids(durationFilter{longerThan: 10 * time.Minute, shorterThan: 40 * time.Minute, includeRunning: true}))
+	assert.Empty(T, ids(durationFilter{longerThan: time.Hour}))
+}
+
+type durationListClient struct {
+	api.ClientInterface
+	builds []api.Build
+	limits []int
+	fields []string
+}
+
+func (c *durationListClient) GetBuilds(_ context.Context, opts api.BuildsOptions) (*api.BuildList, bool, error) {
+	c.limits = append(c.limits, opts.Limit)
+	c.fields = opts.Fields
+	builds := c.builds
+	more := false
+	if opts.Limit > 0 && len(builds) > opts.Limit {
+		builds, more = builds[:opts.Limit], true
+	}
+	return &api.BuildList{Count: len(builds), Builds: slices.Clone(builds)}, more, nil
+}
+
+func TestGetBuildsByDuration(T *testing.T) {
+	// Every tenth run is slow, so 2 matches need 20 runs: a window of 8, then 32.
+	var history []api.Build
+	for i := range 100 {
+		d := time.Minute
+		if i%10 == 9 {
+			d = time.Hour
+		}
+		history = append(history, durationBuild(1000-i, d, true))
+	}
+	slow := durationFilter{longerThan: 30 * time.Minute}
+
+	T.Run("widens the window until enough runs match", func(t *testing.T) {
+		client := &durationListClient{builds: history}
+		scan, err := getBuildsByDuration(context.Background(), client, api.BuildsOptions{}, slow, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{8, 32}, client.limits)
+		require.Equal(t, 2, scan.runs.Count)
+		assert.Equal(t, []int{991, 981}, []int{scan.runs.Builds[0].ID, scan.runs.Builds[1].ID})
+		assert.True(t, scan.truncated, "a third match was cut off")
+		assert.Zero(t, scan.exhausted)
+	})
+
+	T.Run("reports an exhausted window", func(t *testing.T) {
+		client := &durationListClient{builds: slices.Concat(history, history, history, history, history, history, history, history, history, history,
+			history, history, history, history, history, history, history, history, history, history, history, history, history, history)}
+		scan, err := getBuildsByDuration(context.Background(), client, api.BuildsOptions{}, slow, 500)
+		require.NoError(t, err)
+		assert.Equal(t, []int{durationScanLimit}, client.limits)
+		assert.Equal(t, 200, scan.runs.Count)
+		assert.Equal(t, durationScanLimit, scan.exhausted)
+	})
+
+	T.Run("history running out is not exhaustion", func(t *testing.T) {
+		client := &durationListClient{builds: history}
+		scan, err := getBuildsByDuration(context.Background(), client, api.BuildsOptions{}, slow, 20)
+		require.NoError(t, err)
+		assert.Equal(t, []int{80, 320}, client.limits)
+		assert.Equal(t, 10, scan.runs.Count)
+		assert.Zero(t, scan.exhausted)
+	})
+
+	T.Run("fetches dates for the filter but returns only requested fields", func(t *testing.T) {
+		client := &durationListClient{builds: history}
+		scan, err := getBuildsByDuration(context.Background(), client, api.BuildsOptions{Fields: []string{"id"}}, slow, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, client.limits)
+		assert.Equal(t, []string{"id", "startDate", "finishDate"}, client.fields)
+		require.Equal(t, 10, scan.runs.Count)
+		assert.Empty(t, scan.runs.Builds[0].StartDate)
+		assert.Empty(t, scan.runs.Builds[0].FinishDate)
+	})
+}
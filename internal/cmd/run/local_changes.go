@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/git"
@@ -30,9 +31,29 @@ func (v *localChangesValue) Type() string {
 	return "string"
 }
 
+// loadLocalChanges returns the patch for --local-changes: "git" for the working tree, "staged" for the index, either
+// followed by a colon and comma-separated pathspecs to include only those files, "-" for stdin, or a patch file.
 func loadLocalChanges(source string, stdin io.Reader) ([]byte, error) {
-	switch source {
-	case "git":
+	kind, paths, hasPaths := strings.Cut(source, ":")
+	if kind != "git" && kind != "staged" {
+		kind, hasPaths = source, false
+	}
+	switch kind {
+	case "git", "staged":
+		var pathspecs []string
+		if hasPaths {
+			for p := range strings.SplitSeq(paths, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					pathspecs = append(pathspecs, p)
+				}
+			}
+			if len(pathspecs) == 0 {
+				return nil, api.Validation(
+					fmt.Sprintf("--local-changes %s names no paths", source),
+					fmt.Sprintf("List the paths to include after the colon, e.g. --local-changes %s:src/app,go.mod", kind),
+				)
+			}
+		}
 		if !isGitRepoFn() {
 			return nil, api.Validation(
 				"not a git repository",
@@ -48,7 +69,11 @@ func loadLocalChanges(source string, stdin io.Reader) ([]byte, error) {
 			)
 		}
 
-		patch, err := git.WorkingTreeDiffFrom(base)
+		diff := git.WorkingTreeDiffFrom
+		if kind == "staged" {
+			diff = git.StagedDiffFrom
+		}
+		patch, err := diff(base, pathspecs...)
 		if err != nil {
 			return nil, api.Validation(
 				"failed to generate git diff",
@@ -56,10 +81,7 @@ func loadLocalChanges(source string, stdin io.Reader) ([]byte, error) {
 			)
 		}
 		if len(patch) == 0 {
-			return nil, api.Validation(
-				"no local changes found",
-				"Make some changes to your files before running a personal build, or use --local-changes <path> to specify a diff file",
-			)
+			return nil, emptyLocalChangesError(kind, pathspecs)
 		}
 		return patch, nil
 	case "-":
@@ -94,3 +116,27 @@ func loadLocalChanges(source string, stdin io.Reader) ([]byte, error) {
 		return patch, nil
 	}
 }
+
+// emptyLocalChangesError refuses a personal build whose generated patch is empty, which would upload a change with nothing in it.
+func emptyLocalChangesError(kind string, pathspecs []string) error {
+	where := ""
+	if len(pathspecs) > 0 {
+		where = " in " + strings.Join(pathspecs, ", ")
+	}
+	if kind == "staged" {
+		return api.Validation(
+			"no staged changes found"+where,
+			"Stage the changes to test with git add, or use --local-changes git to include unstaged changes too",
+		)
+	}
+	if len(pathspecs) > 0 {
+		return api.Validation(
+			"no local changes found"+where,
+			"Check the paths, which are relative to the current directory, or drop them to include every change",
+		)
+	}
+	return api.Validation(
+		"no local changes found",
+		"Make some changes to your files before running a personal build, or use --local-changes <path> to specify a diff file",
+	)
+}
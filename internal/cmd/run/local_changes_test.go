@@ -166,6 +166,58 @@ func TestLoadLocalChanges(t *testing.T) {
 		assert.NotContains(t, p, "from_remote.txt")
 	})
 
+	t.Run("git source with paths", func(t *testing.T) {
+		dir := setupRepo(t)
+		t.Chdir(dir)
+		writeFile(t, dir, "a.txt", "a")
+		writeFile(t, dir, "b.txt", "b")
+		writeFile(t, dir, "c.txt", "c")
+		gitDo(t, dir, "add", ".")
+		gitDo(t, dir, "commit", "-m", "initial")
+		writeFile(t, dir, "a.txt", "a modified")
+		writeFile(t, dir, "b.txt", "b modified")
+		writeFile(t, dir, "c.txt", "c modified")
+
+		patch, err := loadLocalChanges("git:a.txt, c.txt", nil)
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "a modified")
+		assert.Contains(t, string(patch), "c modified")
+		assert.NotContains(t, string(patch), "b modified")
+
+		_, err = loadLocalChanges("git:d.txt", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no local changes found in d.txt")
+
+		_, err = loadLocalChanges("git:", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--local-changes git: names no paths")
+	})
+
+	t.Run("staged source", func(t *testing.T) {
+		dir := setupRepo(t)
+		t.Chdir(dir)
+		writeFile(t, dir, "a.txt", "a")
+		writeFile(t, dir, "b.txt", "b")
+		gitDo(t, dir, "add", ".")
+		gitDo(t, dir, "commit", "-m", "initial")
+		writeFile(t, dir, "a.txt", "a modified")
+
+		_, err := loadLocalChanges("staged", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no staged changes found")
+
+		gitDo(t, dir, "add", "a.txt")
+		writeFile(t, dir, "b.txt", "b modified")
+		patch, err := loadLocalChanges("staged", nil)
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "a modified")
+		assert.NotContains(t, string(patch), "b modified")
+
+		_, err = loadLocalChanges("staged:b.txt", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no staged changes found in b.txt")
+	})
+
 	t.Run("git source not in repo", func(t *testing.T) {
 		t.Chdir(t.TempDir())
 		_, err := loadLocalChanges("git", nil)
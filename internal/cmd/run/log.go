@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +27,12 @@ type runLogOptions struct {
 	json   bool
 	tail   int
 	follow bool
+	batch  int
+	line   int
+	links  bool
+
+	failedDetails bool
+	test          string
 }
 
 func newRunLogCmd(f *cmdutil.Factory) *cobra.Command {
@@ -37,28 +44,31 @@ func newRunLogCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `View the log output from a run.
 
 You can specify a run ID directly, or use --job to get the latest run's log.
+With both, a run number such as #482 is looked up among the job's runs.
 
 Use --tail to show the last N log messages via the structured messages API.
 Use --follow to stream logs from a running build until it completes.
+Use --failed for a short failure summary, or --failed-details for every
+failed test with its stack trace, followed by the build problems.
 Output is plain text and pipe-friendly (e.g., teamcity run log -f 123 | grep ERROR).
 
 For a full-screen interactive TUI, use "teamcity run watch --logs" instead.
 
 Pager: / search, n/N next/prev, g/G top/bottom, q quit.
 Use --raw to bypass the pager.`,
-		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 && cmd.Flags().Changed("job") {
-				return api.MutuallyExclusive("id", "job")
-			}
-			return cobra.MaximumNArgs(1)(cmd, args)
-		},
+		Args: cobra.MaximumNArgs(1),
 		Example: `  teamcity run log 12345
   teamcity run log 12345 --tail 50
   teamcity run log 12345 --follow
   teamcity run log 12345 --follow --tail 200
   teamcity run log 12345 --failed
+  teamcity run log 12345 --failed --links    # with deep links to each problem and failed test
+  teamcity run log 12345 --failed-details --test CartTest
   teamcity run log 12345 --json
-  teamcity run log --job Falcon_Build`,
+  teamcity run log 12345 --batch 3
+  teamcity run log 12345 --web --line 240    # open the log scrolled to line 240
+  teamcity run log --job Falcon_Build
+  teamcity run log '#482' --job Falcon_Build`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var runID string
 			if len(args) > 0 {
@@ -71,13 +81,18 @@ Use --raw to bypass the pager.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest")
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest, or look up a run number in it")
 	cmd.Flags().BoolVar(&opts.failed, "failed", false, "Show failure summary (problems and failed tests)")
 	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Show raw log without formatting")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
 	cmd.Flags().IntVar(&opts.tail, "tail", 0, "Show last N log messages")
 	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Stream log output until completion")
+	cmd.Flags().IntVar(&opts.batch, "batch", 0, "Show the log of this batch of a parallel-tests or matrix run")
+	cmd.Flags().IntVar(&opts.line, "line", 0, "With --web, open the log scrolled to this line")
+	cmd.Flags().BoolVar(&opts.links, "links", false, "With --failed, print a web link for each problem and failed test")
+	cmd.Flags().BoolVar(&opts.failedDetails, "failed-details", false, "Show every failed test with its stack trace, then the build problems")
+	cmd.Flags().StringVar(&opts.test, "test", "", "With --failed-details, only show failed tests whose name contains this text")
 
 	cmd.MarkFlagsMutuallyExclusive("json", "raw")
 	cmd.MarkFlagsMutuallyExclusive("json", "web")
@@ -85,15 +100,15 @@ Use --raw to bypass the pager.`,
 	cmd.MarkFlagsMutuallyExclusive("failed", "follow")
 	cmd.MarkFlagsMutuallyExclusive("web", "tail")
 	cmd.MarkFlagsMutuallyExclusive("web", "follow")
+	cmd.MarkFlagsMutuallyExclusive("failed-details", "failed")
+	cmd.MarkFlagsMutuallyExclusive("failed-details", "tail")
+	cmd.MarkFlagsMutuallyExclusive("failed-details", "follow")
+	cmd.MarkFlagsMutuallyExclusive("failed-details", "web")
+	cmd.MarkFlagsMutuallyExclusive("failed-details", "raw")
 
 	return cmd
 }
 
-type buildLogJSON struct {
-	RunID string `json:"run_id"`
-	Log   string `json:"log"`
-}
-
 type failureSummaryJSON struct {
 	RunID    string                  `json:"run_id"`
 	Number   string                  `json:"number"`
@@ -148,11 +163,13 @@ const (
 	msgStatusWarning = 2
 	msgStatusError   = 4
 
-	defaultFollowTail  = 100
-	followPollInterval = 2 * time.Second
-	followFetchWindow  = 500
+	defaultFollowTail = 100
+	followFetchWindow = 500
 )
 
+// followPollInterval is how often --follow checks for new messages; a variable so tests can poll faster.
+var followPollInterval = 2 * time.Second
+
 func formatMessage(msg api.BuildMessage, raw bool) string {
 	text := strings.TrimRight(msg.Text, "\r\n")
 	if text == "" {
@@ -193,6 +210,19 @@ func formatMessage(msg api.BuildMessage, raw bool) string {
 }
 
 func runRunLog(f *cmdutil.Factory, runID string, opts *runLogOptions) error {
+	if opts.line < 0 {
+		return fmt.Errorf("--line must not be negative, got %d", opts.line)
+	}
+	if opts.line > 0 && !opts.web {
+		return api.Validation("--line only applies with --web", "Add --web to open the log at that line in the browser")
+	}
+	if opts.links && !opts.failed {
+		return api.Validation("--links only applies with --failed", "Use 'teamcity run log <id> --failed --links', or 'teamcity run tests <id> --links' for test links")
+	}
+	if opts.test != "" && !opts.failedDetails {
+		return api.Validation("--test only applies with --failed-details", "Use 'teamcity run log <id> --failed-details --test <name>'")
+	}
+
 	client, err := f.Client()
 	if err != nil {
 		return err
@@ -202,7 +232,7 @@ func runRunLog(f *cmdutil.Factory, runID string, opts *runLogOptions) error {
 	if opts.follow {
 		state = "any"
 	}
-	resolvedID, latest, err := resolveRunID(f.Context(), client, runID, opts.job, state)
+	resolvedID, latest, err := resolveRunID(f, client, runID, opts.job, state)
 	if err != nil {
 		return err
 	}
@@ -210,18 +240,30 @@ func runRunLog(f *cmdutil.Factory, runID string, opts *runLogOptions) error {
 	if latest != nil && !opts.json {
 		f.Printer.Info("Showing log for #%s (%s)", runID, latest.Number)
 	}
+	if opts.batch != 0 {
+		if opts.batch < 0 {
+			return fmt.Errorf("--batch must be a positive number, got %d", opts.batch)
+		}
+		runID, err = resolveBatchRunID(f.Context(), client, runID, opts.batch)
+		if err != nil {
+			return err
+		}
+		if !opts.json {
+			f.Printer.Info("Showing log for batch %d (#%s)", opts.batch, runID)
+		}
+	}
 
 	if opts.web {
 		build, err := client.GetBuild(f.Context(), runID)
 		if err != nil {
 			return err
 		}
-		return browser.OpenURL(build.WebURL + "?buildTab=buildLog")
+		return browser.OpenURL(cmdutil.LogLineURL(build.WebURL, opts.line))
 	}
 
 	mode := analytics.LogModeFull
 	switch {
-	case opts.failed:
+	case opts.failed, opts.failedDetails:
 		mode = analytics.LogModeFailed
 	case opts.follow:
 		mode = analytics.LogModeFollow
@@ -234,7 +276,11 @@ func runRunLog(f *cmdutil.Factory, runID string, opts *runLogOptions) error {
 	})
 
 	if opts.failed {
-		return runLogFailed(f, client, runID, opts.json)
+		return runLogFailed(f, client, runID, opts.json, opts.links)
+	}
+
+	if opts.failedDetails {
+		return runLogFailedDetails(f, client, runID, opts.test, opts.json)
 	}
 
 	if opts.follow {
@@ -251,21 +297,38 @@ func runRunLog(f *cmdutil.Factory, runID string, opts *runLogOptions) error {
 	return runLogFull(f, client, runID, opts)
 }
 
-func runLogFull(f *cmdutil.Factory, client api.ClientInterface, runID string, opts *runLogOptions) error {
-	if opts.json {
-		log, err := client.GetBuildLog(f.Context(), runID)
-		if err != nil {
-			return fmt.Errorf("failed to get run log: %w", err)
+// resolveBatchRunID maps a parallel-tests or matrix run to the ID of its Nth batch build.
+func resolveBatchRunID(ctx context.Context, client api.ClientInterface, runID string, n int) (string, error) {
+	build, err := client.GetBuild(ctx, runID)
+	if err != nil {
+		return "", err
+	}
+	batches, err := client.GetBuildBatches(ctx, build)
+	if err != nil {
+		return "", fmt.Errorf("failed to get batches: %w", err)
+	}
+	if len(batches) == 0 {
+		return "", api.Validation(fmt.Sprintf("run %s has no batches", runID), "--batch applies to runs of parallel-tests or matrix jobs")
+	}
+	for _, b := range batches {
+		if b.Index == n {
+			return strconv.Itoa(b.Build.ID), nil
 		}
-		return f.Printer.PrintJSON(buildLogJSON{RunID: runID, Log: log})
 	}
+	return "", api.Validation(fmt.Sprintf("run %s has no batch %d", runID, n), fmt.Sprintf("Choose a batch between 1 and %d; 'teamcity run view %s' lists them", len(batches), runID))
+}
 
-	rc, err := client.GetBuildLogStream(f.Context(), runID)
+func runLogFull(f *cmdutil.Factory, src runSource, runID string, opts *runLogOptions) error {
+	rc, err := src.GetBuildLogStream(f.Context(), runID)
 	if err != nil {
 		return fmt.Errorf("failed to get run log: %w", err)
 	}
 	defer func() { _ = rc.Close() }()
 
+	if opts.json {
+		return streamLogJSON(f.Printer.Out, runID, rc)
+	}
+
 	br := bufio.NewReader(rc)
 	if _, err := br.Peek(1); err != nil {
 		if errors.Is(err, io.EOF) {
@@ -290,7 +353,10 @@ func runLogFull(f *cmdutil.Factory, client api.ClientInterface, runID string, op
 			if line != "" {
 				formatted := formatLogLine(strings.TrimSuffix(line, "\n"))
 				if formatted != "" {
-					_, _ = fmt.Fprintln(w, formatted)
+					if _, werr := fmt.Fprintln(w, formatted); werr != nil {
+						streamErr = werr
+						return
+					}
 				}
 			}
 			if err != nil {
@@ -301,12 +367,39 @@ func runLogFull(f *cmdutil.Factory, client api.ClientInterface, runID string, op
 			}
 		}
 	})
+	if output.IsClosedSink(streamErr) {
+		// The reader (a pager, | head) has what it wanted; closing the stream abandons the rest of the download.
+		return nil
+	}
 	if streamErr != nil {
 		return fmt.Errorf("failed to read run log: %w", streamErr)
 	}
 	return nil
 }
 
+// streamLogJSON writes {"run_id": ..., "log": ...} in PrintJSON's layout as the log downloads, so a log of any
+// size takes no more memory than a chunk of it.
+func streamLogJSON(w io.Writer, runID string, log io.Reader) error {
+	id, _ := json.Marshal(runID)
+	err := func() error {
+		if _, err := fmt.Fprintf(w, "{\n  \"run_id\": %s,\n  \"log\": ", id); err != nil {
+			return err
+		}
+		if err := output.WriteJSONString(w, log); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n}\n")
+		return err
+	}()
+	if output.IsClosedSink(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read run log: %w", err)
+	}
+	return nil
+}
+
 func runLogTail(f *cmdutil.Factory, client api.ClientInterface, runID string, opts *runLogOptions) error {
 	resp, err := client.GetBuildMessages(f.Context(), runID, api.BuildMessagesOptions{
 		Count:     -opts.tail,
@@ -344,11 +437,18 @@ func printMessages(f *cmdutil.Factory, runID string, messages []api.BuildMessage
 }
 
 func runLogFollow(f *cmdutil.Factory, client api.ClientInterface, runID string, opts *runLogOptions) (resErr error) {
-	p := f.Printer
-	ctx := f.Context()
+	// A reader that goes away (a pager quit, | head) cancels ctx, which stops the polling and any request in flight.
+	ctx, cancel := context.WithCancel(f.Context())
+	defer cancel()
+	sink := output.NewSinkWriter(f.Printer.Out, cancel)
+	p := f.Printer.WithOut(sink)
 
 	defer func() {
-		if ctx.Err() == nil {
+		if sink.Closed() {
+			resErr = nil
+			return
+		}
+		if f.Context().Err() == nil {
 			return
 		}
 		if !opts.json {
@@ -509,14 +609,7 @@ func printFollowMessage(w io.Writer, msg api.BuildMessage, showVerbose, raw, jso
 
 func buildFinishedResult(ctx context.Context, p *output.Printer, client api.ClientInterface, build *api.Build, jsonOut bool) error {
 	if jsonOut {
-		switch build.Status {
-		case "SUCCESS":
-			return nil
-		case "FAILURE":
-			return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
-		default:
-			return &cmdutil.ExitError{Code: cmdutil.ExitCancelled}
-		}
+		return cmdutil.BuildExitError(build)
 	}
 	_, _ = fmt.Fprintln(p.Out)
 	return cmdutil.BuildResultError(ctx, p, client, build, true)
@@ -530,7 +623,7 @@ func messageToJSON(msg api.BuildMessage) string {
 	return string(data)
 }
 
-func runLogFailed(f *cmdutil.Factory, client api.ClientInterface, runID string, jsonOut bool) error {
+func runLogFailed(f *cmdutil.Factory, client api.ClientInterface, runID string, jsonOut, links bool) error {
 	build, err := client.GetBuild(f.Context(), runID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch: %w", err)
@@ -560,6 +653,6 @@ func runLogFailed(f *cmdutil.Factory, client api.ClientInterface, runID string,
 		f.Printer.Success("Build %d  #%s succeeded", build.ID, build.Number)
 		return nil
 	}
-	cmdutil.PrintFailureSummary(f.Context(), f.Printer, client, runID, build.Number, build.WebURL, build.StatusText)
+	cmdutil.PrintFailureSummary(f.Context(), f.Printer, client, runID, build.Number, build.WebURL, build.StatusText, links)
 	return nil
 }
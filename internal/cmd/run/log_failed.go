@@ -0,0 +1,206 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+)
+
+// failedTestDetailFields adds the stack trace and the parsed test name to what --failed-details fetches.
+var failedTestDetailFields = []string{
+	"id", "name", "duration", "details", "newFailure", "firstFailed(build(id,number))",
+	"test(id,name,parsedTestName(testSuite,testPackage,testClass,testShortName,testMethodName))",
+}
+
+type failedDetailsJSON struct {
+	RunID    string                  `json:"run_id"`
+	Number   string                  `json:"number"`
+	Status   string                  `json:"status"`
+	WebURL   string                  `json:"web_url"`
+	Tests    []failedTestDetails     `json:"failed_tests"`
+	Problems []api.ProblemOccurrence `json:"problems"`
+}
+
+// failedTestDetails is one failed test with every failed occurrence of it in the run, such as retries, merged.
+// Duration is their total in milliseconds, and Details holds each distinct stack trace once.
+type failedTestDetails struct {
+	Name         string   `json:"name"`
+	Suite        string   `json:"suite,omitempty"`
+	Class        string   `json:"class,omitempty"`
+	Test         string   `json:"test,omitempty"`
+	Failures     int      `json:"failures"`
+	Duration     int      `json:"duration_ms"`
+	NewFailure   bool     `json:"new_failure"`
+	FailingSince string   `json:"failing_since,omitempty"`
+	Details      []string `json:"details"`
+}
+
+func runLogFailedDetails(f *cmdutil.Factory, client api.ClientInterface, runID, testFilter string, jsonOut bool) error {
+	ctx := f.Context()
+	build, err := client.GetBuild(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	id := strconv.Itoa(build.ID)
+
+	occurrences, err := client.ListTestOccurrences(ctx, api.TestOccurrenceQuery{
+		Build:  id,
+		Status: "failed",
+		Muted:  new(false),
+		Fields: failedTestDetailFields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get failed tests: %w", err)
+	}
+	tests := groupFailedTests(occurrences.TestOccurrence, testFilter)
+
+	problems := []api.ProblemOccurrence{}
+	if resp, err := client.GetBuildProblems(id); err != nil {
+		f.Printer.Debug("Failed to fetch problems: %v", err)
+	} else {
+		for _, prob := range resp.ProblemOccurrence {
+			// The failed tests are listed in full; their summary problem adds nothing.
+			if prob.Type != "TC_FAILED_TESTS" {
+				problems = append(problems, prob)
+			}
+		}
+	}
+
+	if jsonOut {
+		return f.Printer.PrintJSON(failedDetailsJSON{
+			RunID:    id,
+			Number:   build.Number,
+			Status:   build.Status,
+			WebURL:   build.WebURL,
+			Tests:    tests,
+			Problems: problems,
+		})
+	}
+
+	if testFilter != "" && len(tests) == 0 {
+		f.Printer.Empty(fmt.Sprintf("No failed tests match %q", testFilter), "Drop --test to see all failed tests and build problems")
+		return nil
+	}
+	if len(tests) == 0 && len(problems) == 0 {
+		switch {
+		case build.Status == "SUCCESS":
+			f.Printer.Success("Build %d  #%s succeeded", build.ID, build.Number)
+		default:
+			f.Printer.Empty(fmt.Sprintf("No failed tests or build problems in #%s", build.Number), fmt.Sprintf("Run 'teamcity run log %s' for the full log", id))
+		}
+		return nil
+	}
+
+	w := f.Printer.Out
+	if len(tests) > 0 {
+		_, _ = fmt.Fprintf(w, "Failed tests (%d):\n", len(tests))
+		for _, t := range tests {
+			printFailedTestDetails(w, t)
+		}
+	}
+	if len(problems) > 0 {
+		_, _ = fmt.Fprintf(w, "\nProblems (%d):\n", len(problems))
+		for _, prob := range problems {
+			detail := strings.TrimSpace(prob.Details)
+			if detail == "" {
+				detail = prob.Identity
+			}
+			first, rest, _ := strings.Cut(detail, "\n")
+			_, _ = fmt.Fprintf(w, "  %s %s\n", output.Red(output.Sym().Bullet), first)
+			for line := range strings.SplitSeq(rest, "\n") {
+				if line != "" {
+					_, _ = fmt.Fprintf(w, "    %s\n", output.Faint(line))
+				}
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\nView details: %s\n", build.WebURL)
+	return nil
+}
+
+// groupFailedTests merges the occurrences of each test, in the order the tests first failed, keeping those whose
+// name contains filter (case-insensitively).
+func groupFailedTests(occurrences []api.TestOccurrence, filter string) []failedTestDetails {
+	filter = strings.ToLower(filter)
+	tests := []failedTestDetails{}
+	index := map[string]int{}
+	for _, o := range occurrences {
+		if filter != "" && !strings.Contains(strings.ToLower(o.Name), filter) {
+			continue
+		}
+		i, seen := index[o.Name]
+		if !seen {
+			i = len(tests)
+			index[o.Name] = i
+			t := failedTestDetails{Name: o.Name, Details: []string{}}
+			if o.Test != nil && o.Test.ParsedTestName != nil {
+				parsed := o.Test.ParsedTestName
+				t.Suite = parsed.TestSuite
+				t.Class = strings.Trim(parsed.TestPackage+"."+parsed.TestClass, ".")
+				t.Test = parsed.TestMethodName
+				if t.Test == "" {
+					t.Test = parsed.TestShortName
+				}
+			}
+			tests = append(tests, t)
+		}
+		t := &tests[i]
+		t.Failures++
+		t.Duration += o.Duration
+		t.NewFailure = t.NewFailure || o.NewFailure
+		if t.FailingSince == "" && o.FirstFailed != nil && o.FirstFailed.Build != nil {
+			t.FailingSince = o.FirstFailed.Build.Number
+		}
+		if details := strings.TrimSpace(o.Details); details != "" && !slices.Contains(t.Details, details) {
+			t.Details = append(t.Details, details)
+		}
+	}
+	return tests
+}
+
+func printFailedTestDetails(w io.Writer, t failedTestDetails) {
+	line := fmt.Sprintf("\n%s %s", output.Red(output.Sym().Bullet), t.Name)
+	var notes []string
+	if t.Duration > 0 {
+		notes = append(notes, output.FormatDuration(time.Duration(t.Duration)*time.Millisecond))
+	}
+	if t.Failures > 1 {
+		notes = append(notes, "failed "+english.Plural(t.Failures, "time", ""))
+	}
+	if len(notes) > 0 {
+		line += " " + output.Faint("("+strings.Join(notes, ", ")+")")
+	}
+	if t.NewFailure {
+		line += " " + output.Yellow("(new)")
+	} else if t.FailingSince != "" {
+		line += " " + output.Faint(fmt.Sprintf("(failing since #%s)", t.FailingSince))
+	}
+	_, _ = fmt.Fprintln(w, line)
+
+	var parts []string
+	for _, p := range [][2]string{{"Suite", t.Suite}, {"Class", t.Class}, {"Test", t.Test}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+": "+p[1])
+		}
+	}
+	if len(parts) > 0 {
+		_, _ = fmt.Fprintf(w, "  %s\n", output.Faint(strings.Join(parts, "  ")))
+	}
+
+	for i, details := range t.Details {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		for dl := range strings.SplitSeq(details, "\n") {
+			_, _ = fmt.Fprintf(w, "    %s\n", dl)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+func TestRunLogFollowQueuedToFailure(t *testing.T) {
+	saved := followPollInterval
+	followPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { followPollInterval = saved })
+
+	var buildPolls, messagePolls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/app/rest/builds/id:5":
+			build := api.Build{ID: 5, Number: "9", BuildTypeID: "App_Build", State: "queued", WaitReason: "Waiting for an agent"}
+			switch n := buildPolls.Add(1); {
+			case n >= 5:
+				build.State, build.Status, build.StatusText = "finished", "FAILURE", "Tests failed"
+			case n >= 3:
+				build.State, build.Status = "running", "SUCCESS"
+			}
+			_ = json.NewEncoder(w).Encode(build)
+		case "/app/messages":
+			all := []api.BuildMessage{
+				{ID: 1, Text: "Step 1/2: compile"},
+				{ID: 2, Text: "Compiled 120 files"},
+				{ID: 3, Text: "Step 2/2: test"},
+				{ID: 4, Text: "3 tests failed", Status: msgStatusError},
+			}
+			n := min(int(messagePolls.Add(1))+1, len(all))
+			_ = json.NewEncoder(w).Encode(api.BuildMessagesResponse{Messages: all[:n]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	var out bytes.Buffer
+	f := &cmdutil.Factory{
+		Printer:    &output.Printer{Out: &out, ErrOut: &out},
+		ClientFunc: func() (api.ClientInterface, error) { return api.NewClient(ts.URL, "test-token"), nil },
+	}
+	client, _ := f.Client()
+
+	err := runLogFollow(f, client, "5", &runLogOptions{follow: true, raw: true})
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	if !ok || exitErr.Code != cmdutil.ExitFailure {
+		t.Fatalf("expected exit code %d for a failed run, got %v", cmdutil.ExitFailure, err)
+	}
+	got := out.String()
+	for _, want := range []string{"Build is queued", "Build started", "Step 1/2: compile", "Compiled 120 files", "Step 2/2: test"} {
+		if c := strings.Count(got, want); c != 1 {
+			t.Errorf("want %q printed once, got %d times:\n%s", want, c, got)
+		}
+	}
+	if !strings.Contains(got, "#9 failed: Tests failed") {
+		t.Errorf("missing the final result:\n%s", got)
+	}
+	if strings.Index(got, "Build started") > strings.Index(got, "Step 1/2: compile") {
+		t.Errorf("messages are printed only once the run starts:\n%s", got)
+	}
+}
@@ -3,35 +3,58 @@ package run
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
 	"github.com/spf13/cobra"
 )
 
 func newRunPinCmd(f *cmdutil.Factory) *cobra.Command {
-	var comment string
+	var comment, job string
+	sel := &runSelector{}
 	cmd := &cobra.Command{
-		Use:   "pin <id>",
+		Use:   "pin [<id> | -]",
 		Short: "Pin to prevent cleanup",
 		Long: `Pin a run to exclude it from cleanup by retention policies.
 
 Use --comment to record the reason (e.g. "release candidate"). A
 pinned run stays visible in the UI and can be unpinned with
-'teamcity run unpin'.`,
-		Args: cobra.ExactArgs(1),
+'teamcity run unpin'.
+
+` + bulkHelp,
+		Args: sel.args(cobra.ExactArgs(1), cobra.NoArgs),
 		Example: `  teamcity run pin 12345
-  teamcity run pin 12345 --comment "Release candidate"`,
+  teamcity run pin 12345 --comment "Release candidate"
+  teamcity run pin --job Falcon_Build --branch release/2024.3 --status success --since 14d --comment "2024.3 RC"
+  teamcity run list --job Falcon_Build --plain --no-header | awk '{print $2}' | teamcity run pin -`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := f.Client()
 			if err != nil {
 				return err
 			}
-			if err := client.PinBuild(args[0], comment); err != nil {
-				return fmt.Errorf("failed to pin run #%s: %w", args[0], err)
+			if sel.bulk(cmd, args) {
+				runs, confirm, err := bulkRuns(f, cmd, client, sel, job, args)
+				if err != nil {
+					return err
+				}
+				return runBulk(f, runs, confirm, sel.yes, bulkAction{"Pin", "pin", "Pinned"}, func(r api.Build) error {
+					return client.PinBuild(strconv.Itoa(r.ID), comment)
+				})
+			}
+			runID, err := resolveRunRef(f, client, args[0], job)
+			if err != nil {
+				return err
+			}
+			if err := client.PinBuild(runID, comment); err != nil {
+				return fmt.Errorf("failed to pin run #%s: %w", runID, err)
 			}
-			f.Printer.Success("Pinned #%s", args[0])
+			f.Printer.Success("Pinned #%s", runID)
 			if comment != "" {
 				f.Printer.Info("  Comment: %s", comment)
 			}
@@ -39,69 +62,161 @@ pinned run stays visible in the UI and can be unpinned with
 		},
 	}
 	cmd.Flags().StringVarP(&comment, "comment", "m", "", "Reason for pinning")
+	addRunJobFlag(cmd, &job)
+	sel.addFlags(cmd)
 	return cmd
 }
 
 func newRunUnpinCmd(f *cmdutil.Factory) *cobra.Command {
-	return &cobra.Command{
-		Use:   "unpin <id>",
+	var job string
+	sel := &runSelector{}
+	cmd := &cobra.Command{
+		Use:   "unpin [<id> | -]",
 		Short: "Unpin a run",
 		Long: `Remove the pin from a run, re-enabling cleanup by retention policies.
 
 The mirror of 'teamcity run pin'. A pinned run stays until it is
-unpinned; once unpinned, normal retention rules apply again.`,
-		Args:    cobra.ExactArgs(1),
-		Example: `  teamcity run unpin 12345`,
+unpinned; once unpinned, normal retention rules apply again.
+
+` + bulkHelp,
+		Args: sel.args(cobra.ExactArgs(1), cobra.NoArgs),
+		Example: `  teamcity run unpin 12345
+  teamcity run unpin --job Falcon_Build --branch release/2024.2 --until 90d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := f.Client()
 			if err != nil {
 				return err
 			}
-			if err := client.UnpinBuild(args[0]); err != nil {
-				return fmt.Errorf("failed to unpin run #%s: %w", args[0], err)
+			if sel.bulk(cmd, args) {
+				runs, confirm, err := bulkRuns(f, cmd, client, sel, job, args)
+				if err != nil {
+					return err
+				}
+				return runBulk(f, runs, confirm, sel.yes, bulkAction{"Unpin", "unpin", "Unpinned"}, func(r api.Build) error {
+					return client.UnpinBuild(strconv.Itoa(r.ID))
+				})
+			}
+			runID, err := resolveRunRef(f, client, args[0], job)
+			if err != nil {
+				return err
+			}
+			if err := client.UnpinBuild(runID); err != nil {
+				return fmt.Errorf("failed to unpin run #%s: %w", runID, err)
 			}
-			f.Printer.Success("Unpinned #%s", args[0])
+			f.Printer.Success("Unpinned #%s", runID)
 			return nil
 		},
 	}
+	addRunJobFlag(cmd, &job)
+	sel.addFlags(cmd)
+	return cmd
+}
+
+// The default --auto-from-branch convention turns release/2024.3 into release-2024.3.
+const (
+	defaultBranchTagPattern  = `release/(.+)`
+	defaultBranchTagTemplate = "release-{1}"
+)
+
+type runTagOptions struct {
+	job        string
+	fromBranch string
+	template   string
+	sel        runSelector
 }
 
 func newRunTagCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runTagOptions{}
 	cmd := &cobra.Command{
-		Use:   "tag <id> <tag>...",
+		Use:   "tag [<id> | -] [tag]...",
 		Short: "Add tags",
 		Long: `Add one or more tags to a run.
 
 Tags are free-form labels for categorization and filtering. Use
-'teamcity run list --tag <tag>' to find runs by tag.`,
-		Args: cobra.MinimumNArgs(2),
+'teamcity run list --tag <tag>' to find runs by tag, and
+'teamcity tag list' to see which tags a job's runs carry.
+
+With --auto-from-branch, the tag is derived from the run's branch: the
+pattern must match the whole branch name, and {N} in --tag-template is
+replaced by the pattern's Nth capture group ({0} is the whole branch).
+By default release/2024.3 is tagged release-2024.3. Pass a custom
+pattern as --auto-from-branch=<regex>.
+
+` + bulkHelp + ` With filters, every argument is a tag.`,
+		Args: opts.sel.args(cobra.MinimumNArgs(1), cobra.ArbitraryArgs),
 		Example: `  teamcity run tag 12345 release
-  teamcity run tag 12345 release v1.0 production`,
+  teamcity run tag 12345 release v1.0 production
+  teamcity run tag 12345 --auto-from-branch
+  teamcity run tag 12345 --auto-from-branch='hotfix/(\d+)' --tag-template 'hotfix-{1}'
+  teamcity run tag --job Falcon_Build --branch release/2024.3 --status success release-2024.3
+  teamcity run list --job Falcon_Build --plain --no-header | awk '{print $2}' | teamcity run tag - release-2024.3`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRunTag(f, args[0], args[1:])
+			if cmd.Flags().Changed("tag-template") && opts.fromBranch == "" {
+				return api.Validation("--tag-template requires --auto-from-branch", "Add --auto-from-branch, optionally with a pattern")
+			}
+			if opts.sel.bulk(cmd, args) {
+				return runRunTagBulk(f, cmd, args, opts)
+			}
+			return runRunTag(f, args[0], args[1:], opts)
 		},
 	}
+	cmd.Flags().StringVar(&opts.fromBranch, "auto-from-branch", "", "Derive a tag from the run's branch using this regex (default when set without a value: "+defaultBranchTagPattern+")")
+	cmd.Flags().Lookup("auto-from-branch").NoOptDefVal = defaultBranchTagPattern
+	cmd.Flags().StringVar(&opts.template, "tag-template", defaultBranchTagTemplate, "Tag built by --auto-from-branch; {N} is the pattern's Nth capture group")
+	addRunJobFlag(cmd, &opts.job)
+	opts.sel.addFlags(cmd)
 
 	return cmd
 }
 
-func runRunTag(f *cmdutil.Factory, runID string, tags []string) error {
+// tagRule returns the non-empty tags and the --auto-from-branch rule, if any, of a tag command.
+func tagRule(tags []string, opts *runTagOptions) ([]string, *branchTag, error) {
 	var filtered []string
 	for _, t := range tags {
 		if t != "" {
 			filtered = append(filtered, t)
 		}
 	}
-	if len(filtered) == 0 {
-		return errors.New("at least one non-empty tag is required")
+	if len(filtered) == 0 && opts.fromBranch == "" {
+		return nil, nil, errors.New("at least one non-empty tag is required")
+	}
+	if opts.fromBranch == "" {
+		return filtered, nil, nil
+	}
+	rule, err := newBranchTag(opts.fromBranch, opts.template)
+	return filtered, rule, err
+}
+
+func runRunTag(f *cmdutil.Factory, runID string, tags []string, opts *runTagOptions) error {
+	tags, rule, err := tagRule(tags, opts)
+	if err != nil {
+		return err
 	}
-	tags = filtered
 
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
+	if rule != nil {
+		build, err := client.GetBuild(f.Context(), runID)
+		if err != nil {
+			return err
+		}
+		tag, err := rule.expand(build.BranchName)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
 	if err := client.AddBuildTags(runID, tags); err != nil {
 		return fmt.Errorf("failed to add tags: %w", err)
 	}
@@ -111,7 +226,107 @@ func runRunTag(f *cmdutil.Factory, runID string, tags []string) error {
 	return nil
 }
 
+// runRunTagBulk tags every run read from stdin or matching the filters. With --auto-from-branch, each run gets
+// the tag of its own branch.
+func runRunTagBulk(f *cmdutil.Factory, cmd *cobra.Command, args []string, opts *runTagOptions) error {
+	tags := args
+	if len(args) > 0 && args[0] == "-" {
+		tags = args[1:]
+	}
+	tags, rule, err := tagRule(tags, opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	runs, confirm, err := bulkRuns(f, cmd, client, &opts.sel, opts.job, args)
+	if err != nil {
+		return err
+	}
+	return runBulk(f, runs, confirm, opts.sel.yes, bulkAction{"Tag", "tag", "Tagged"}, func(r api.Build) error {
+		runID := strconv.Itoa(r.ID)
+		runTags := tags
+		if rule != nil {
+			branch := r.BranchName
+			if r.BuildTypeID == "" {
+				build, err := client.GetBuild(f.Context(), runID)
+				if err != nil {
+					return err
+				}
+				branch = build.BranchName
+			}
+			tag, err := rule.expand(branch)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(runTags, tag) {
+				runTags = append(slices.Clip(runTags), tag)
+			}
+		}
+		return client.AddBuildTags(runID, runTags)
+	})
+}
+
+// templateGroup matches a {N} capture-group reference in a --tag-template.
+var templateGroup = regexp.MustCompile(`\{(\d+)\}`)
+
+// branchTag derives a tag from a branch name by the --auto-from-branch convention.
+type branchTag struct {
+	pattern  string
+	re       *regexp.Regexp
+	template string
+}
+
+// newBranchTag compiles pattern to match whole branch names and checks that template only uses groups it captures.
+func newBranchTag(pattern, template string) (*branchTag, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, api.Validation(
+			fmt.Sprintf("invalid --auto-from-branch pattern %q: %v", pattern, err),
+			"Use a regular expression with capture groups, for example 'release/(.+)'",
+		)
+	}
+	for _, m := range templateGroup.FindAllStringSubmatch(template, -1) {
+		if n, err := strconv.Atoi(m[1]); err != nil || n > re.NumSubexp() {
+			return nil, api.Validation(
+				fmt.Sprintf("--tag-template %q uses %s, but pattern %q has %s", template, m[0], pattern, english.Plural(re.NumSubexp(), "capture group", "")),
+				"Add a (group) to the pattern, or refer to an existing one",
+			)
+		}
+	}
+	return &branchTag{pattern: pattern, re: re, template: template}, nil
+}
+
+// expand returns the tag for branch, or an error when the branch doesn't match the pattern.
+func (b *branchTag) expand(branch string) (string, error) {
+	if branch == "" {
+		return "", api.Validation("run has no branch to derive a tag from", "Pass the tag explicitly instead of --auto-from-branch")
+	}
+	m := b.re.FindStringSubmatch(branch)
+	if m == nil {
+		return "", api.Validation(
+			fmt.Sprintf("branch %q does not match --auto-from-branch pattern %q", branch, b.pattern),
+			"The pattern must match the whole branch name",
+		)
+	}
+	tag := templateGroup.ReplaceAllStringFunc(b.template, func(ref string) string {
+		n, _ := strconv.Atoi(ref[1 : len(ref)-1])
+		return m[n]
+	})
+	if strings.TrimSpace(tag) == "" {
+		return "", api.Validation(
+			fmt.Sprintf("--tag-template %q produced an empty tag for branch %q", b.template, branch),
+			"Check which capture groups the template uses",
+		)
+	}
+	return tag, nil
+}
+
 func newRunUntagCmd(f *cmdutil.Factory) *cobra.Command {
+	var job string
 	cmd := &cobra.Command{
 		Use:   "untag <id> <tag>...",
 		Short: "Remove tags",
@@ -119,19 +334,25 @@ func newRunUntagCmd(f *cmdutil.Factory) *cobra.Command {
 		Example: `  teamcity run untag 12345 release
   teamcity run untag 12345 release v1.0`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRunUntag(f, args[0], args[1:])
+			return runRunUntag(f, args[0], job, args[1:])
 		},
 	}
+	addRunJobFlag(cmd, &job)
 
 	return cmd
 }
 
-func runRunUntag(f *cmdutil.Factory, runID string, tags []string) error {
+func runRunUntag(f *cmdutil.Factory, runID, job string, tags []string) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, job)
+	if err != nil {
+		return err
+	}
+
 	var failures []string
 	removed := 0
 	for _, tag := range tags {
@@ -159,6 +380,7 @@ func runRunUntag(f *cmdutil.Factory, runID string, tags []string) error {
 }
 
 type runCommentOptions struct {
+	job    string
 	delete bool
 	json   bool
 }
@@ -190,6 +412,7 @@ Use --delete to remove the comment.`,
 
 	cmd.Flags().BoolVar(&opts.delete, "delete", false, "Delete the comment")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	addRunJobFlag(cmd, &opts.job)
 
 	return cmd
 }
@@ -200,6 +423,11 @@ func runRunComment(f *cmdutil.Factory, runID string, comment string, opts *runCo
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
 	if opts.delete {
 		if err := client.DeleteBuildComment(runID); err != nil {
 			return fmt.Errorf("failed to delete comment: %w", err)
@@ -0,0 +1,61 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchTagExpand(T *testing.T) {
+	T.Parallel()
+
+	tests := []struct {
+		name     string
+		pattern  string
+		template string
+		branch   string
+		want     string
+		wantErr  string
+	}{
+		{"default convention", defaultBranchTagPattern, defaultBranchTagTemplate, "release/2024.3", "release-2024.3", ""},
+		{"several groups", `(\w+)/v(\d+)\.(\d+)`, "{1}-{2}x{3}", "hotfix/v3.14", "hotfix-3x14", ""},
+		{"whole branch", `.+`, "branch-{0}", "main", "branch-main", ""},
+		{"literal braces kept", `release/(.+)`, "{x}-{1}", "release/1", "{x}-1", ""},
+		{"pattern must match the whole branch", defaultBranchTagPattern, defaultBranchTagTemplate, "refs/heads/release/2024.3", "", "does not match"},
+		{"no match", defaultBranchTagPattern, defaultBranchTagTemplate, "main", "", `branch "main" does not match`},
+		{"no branch", defaultBranchTagPattern, defaultBranchTagTemplate, "", "", "no branch"},
+		{"empty result", `release/(.*)`, "{1}", "release/", "", "empty tag"},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rule, err := newBranchTag(tc.pattern, tc.template)
+			require.NoError(t, err)
+			got, err := rule.expand(tc.branch)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewBranchTagRejectsBadRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := newBranchTag(`release/(`, defaultBranchTagTemplate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --auto-from-branch pattern")
+
+	_, err = newBranchTag(`release/(.+)`, "release-{2}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "uses {2}, but pattern")
+	assert.Contains(t, err.Error(), "has 1 capture group")
+
+	_, err = newBranchTag(`release/(.+)`, "{99999999999999999999}")
+	require.Error(t, err)
+}
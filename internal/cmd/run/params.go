@@ -0,0 +1,289 @@
+package run
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/buildkite/shellwords"
+	"github.com/spf13/cobra"
+)
+
+// maskedValue replaces the value of password parameters.
+const maskedValue = "******"
+
+type runParamsOptions struct {
+	job     string
+	format  string
+	prefix  string
+	filter  string
+	compare string
+}
+
+func newRunParamsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runParamsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "params <id>",
+		Short: "Show the parameters a run used",
+		Long: `Show every parameter a run ran with, resolved, sorted by name.
+
+The SOURCE column marks parameters set when the run was triggered (run)
+and those TeamCity provides itself (predefined); the rest come from the
+job, its template, or its project. Values of password parameters are
+masked.
+
+--format env prints export statements that can be sourced into a shell to
+reproduce the run's environment locally: env.FOO becomes FOO, any other
+parameter is upper-cased with dots replaced by underscores, and --prefix
+is prepended to every name. --format properties prints a Java properties
+file.
+
+--compare shows what differs from another run, leaving out predefined
+and password parameters.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  teamcity run params 12345
+  teamcity run params 12345 --filter docker
+  eval "$(teamcity run params 12345 --format env --prefix BUILD_)"
+  teamcity run params 12345 --format properties > build.properties
+  teamcity run params 12345 --compare 12340`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunParams(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Output format: table, env, json, properties")
+	cmd.Flags().StringVar(&opts.prefix, "prefix", "", "Prefix for variable names with --format env")
+	cmd.Flags().StringVar(&opts.filter, "filter", "", "Only show parameters whose name contains this text (case-insensitive)")
+	cmd.Flags().StringVar(&opts.compare, "compare", "", "Show differences from another run")
+	addRunJobFlag(cmd, &opts.job)
+
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.Fixed("table", "env", "json", "properties"))
+
+	return cmd
+}
+
+func (o *runParamsOptions) validate() error {
+	if !slices.Contains([]string{"table", "env", "json", "properties"}, o.format) {
+		return api.Validation(fmt.Sprintf("invalid format %q", o.format), "Use --format table, env, json, or properties")
+	}
+	if o.prefix != "" && o.format != "env" {
+		return api.Validation("--prefix only applies to --format env", "add --format env")
+	}
+	if o.compare != "" && (o.format == "env" || o.format == "properties") {
+		return api.Validation("--compare supports --format table or json only", "drop --format, or use --format json")
+	}
+	return nil
+}
+
+// runParam is a resolved parameter of a run as printed by run params.
+type runParam struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Source   string `json:"source,omitempty"`
+	Password bool   `json:"password,omitempty"`
+}
+
+func runRunParams(f *cmdutil.Factory, runID string, opts *runParamsOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
+	build, err := fetchRunParams(f, client, runID)
+	if err != nil {
+		return err
+	}
+
+	if opts.compare != "" {
+		base, err := fetchRunParams(f, client, opts.compare)
+		if err != nil {
+			return err
+		}
+		return printRunParamsDiff(f.Printer, base, build, opts)
+	}
+
+	params := collectRunParams(build, opts.filter)
+	switch opts.format {
+	case "json":
+		return f.Printer.PrintJSON(params)
+	case "env":
+		printParamsEnv(f.Printer, params, opts.prefix)
+	case "properties":
+		printParamsProperties(f.Printer, params)
+	default:
+		if len(params) == 0 && opts.filter != "" {
+			f.Printer.Empty(fmt.Sprintf("No parameters of run %d match %q", build.ID, opts.filter), "")
+			return nil
+		}
+		if len(params) == 0 {
+			f.Printer.Empty(fmt.Sprintf("Run %d has no parameters", build.ID), "")
+			return nil
+		}
+		rows := make([][]string, len(params))
+		for i, prm := range params {
+			rows[i] = []string{prm.Name, prm.Value, output.Faint(prm.Source)}
+		}
+		f.Printer.PrintTable([]string{"NAME", "VALUE", "SOURCE"}, rows)
+	}
+	return nil
+}
+
+// fetchRunParams fetches the parameters of a run. A run that has not started has no resolved parameters
+// yet; those set when it was queued are used instead.
+func fetchRunParams(f *cmdutil.Factory, client api.ClientInterface, runID string) (*api.Build, error) {
+	build, err := client.GetBuildParameters(f.Context(), runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parameters of run %s: %w", runID, err)
+	}
+	if build.ResultingProperties == nil || len(build.ResultingProperties.Property) == 0 {
+		if build.State != "queued" {
+			return nil, fmt.Errorf("run %d has no resolved parameters", build.ID)
+		}
+		f.Printer.Warn("Run %d has not started yet; showing only the parameters it was queued with", build.ID)
+		build.ResultingProperties = cmp.Or(build.Properties, &api.ParameterList{})
+	}
+	return build, nil
+}
+
+// collectRunParams returns the resolved parameters of build whose name contains filter, sorted by name,
+// with password values masked.
+func collectRunParams(build *api.Build, filter string) []runParam {
+	triggered := map[string]bool{}
+	if build.Properties != nil {
+		for _, prm := range build.Properties.Property {
+			triggered[prm.Name] = true
+		}
+	}
+
+	params := []runParam{}
+	for _, prm := range build.ResultingProperties.Property {
+		if !matchesParamFilter(prm.Name, filter) {
+			continue
+		}
+		rp := runParam{Name: prm.Name, Value: prm.Value, Password: prm.IsPassword()}
+		switch {
+		case triggered[prm.Name]:
+			rp.Source = "run"
+		case isAutoParam(prm.Name):
+			rp.Source = "predefined"
+		}
+		if rp.Password {
+			rp.Value = maskedValue
+		}
+		params = append(params, rp)
+	}
+	slices.SortFunc(params, func(a, b runParam) int { return cmp.Compare(a.Name, b.Name) })
+	return params
+}
+
+func matchesParamFilter(name, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+func printRunParamsDiff(p *output.Printer, base, build *api.Build, opts *runParamsOptions) error {
+	var diffs []paramDiff
+	for _, d := range computeParamDiffs(base.ResultingProperties, build.ResultingProperties) {
+		if matchesParamFilter(d.name, opts.filter) {
+			diffs = append(diffs, d)
+		}
+	}
+
+	if opts.format == "json" {
+		return p.PrintJSON(paramDiffsJSON(diffs))
+	}
+	if len(diffs) == 0 {
+		p.Info("No parameter differences between run %d and run %d", base.ID, build.ID)
+		return nil
+	}
+	_, _ = fmt.Fprintf(p.Out, "Parameters of run %d compared to run %d\n\n", build.ID, base.ID)
+	printParamDiffs(p, diffs)
+	return nil
+}
+
+// printParamsEnv prints params as shell export statements. Password parameters are left out as comments.
+func printParamsEnv(p *output.Printer, params []runParam, prefix string) {
+	seen := map[string]string{}
+	for _, prm := range params {
+		name := prefix + envVarName(prm.Name)
+		switch {
+		case prm.Password:
+			_, _ = fmt.Fprintf(p.Out, "# %s: password parameter, value hidden\n", name)
+		case seen[name] != "":
+			_, _ = fmt.Fprintf(p.Out, "# %s: skipped %s, same name as %s\n", name, prm.Name, seen[name])
+		default:
+			seen[name] = prm.Name
+			_, _ = fmt.Fprintf(p.Out, "export %s=%s\n", name, shellwords.QuotePosix(prm.Value))
+		}
+	}
+}
+
+// envVarName turns a parameter name into an environment variable name: env.FOO is FOO, other
+// names are upper-cased with every character other than a letter or digit replaced by an underscore.
+func envVarName(param string) string {
+	if name, ok := strings.CutPrefix(param, "env."); ok {
+		param = name
+	} else {
+		param = strings.ToUpper(param)
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, param)
+	if name == "" || ('0' <= name[0] && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// printParamsProperties prints params as a Java properties file. Password parameters are left out as comments.
+func printParamsProperties(p *output.Printer, params []runParam) {
+	for _, prm := range params {
+		key := escapeProperty(prm.Name, true)
+		if prm.Password {
+			_, _ = fmt.Fprintf(p.Out, "# %s: password parameter, value hidden\n", key)
+			continue
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s=%s\n", key, escapeProperty(prm.Value, false))
+	}
+}
+
+// escapeProperty escapes s for a Java properties file. Keys also escape separators and spaces.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == ' ' && (key || i == 0):
+			b.WriteString(`\ `)
+		case key && strings.ContainsRune("=:#!", r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
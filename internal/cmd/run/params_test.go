@@ -0,0 +1,134 @@
+package run_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+)
+
+func setupParamsServer(t *testing.T) *cmdtest.TestServer {
+	t.Helper()
+	ts := cmdtest.NewTestServer(t)
+
+	ts.Handle("GET /app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Server{VersionMajor: 2025, VersionMinor: 7, BuildNumber: "197398"})
+	})
+	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID: 1, Number: "1", State: "finished",
+			Properties: &api.ParameterList{Property: []api.Parameter{{Name: "version", Value: "1.0.0"}}},
+			ResultingProperties: &api.ParameterList{Property: []api.Parameter{
+				{Name: "version", Value: "1.0.0"},
+				{Name: "env.JAVA_HOME", Value: "/usr/lib/jvm/java-17"},
+				{Name: "teamcity.build.id", Value: "1"},
+				{Name: "docker.tags", Value: "latest 1.0.0"},
+				{Name: "deploy.token", Value: "secret", Type: &api.ParameterType{RawValue: "password display='hidden'"}},
+			}},
+		})
+	})
+	ts.Handle("GET /app/rest/builds/id:2", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID: 2, Number: "2", State: "finished",
+			ResultingProperties: &api.ParameterList{Property: []api.Parameter{
+				{Name: "version", Value: "1.0.1"},
+				{Name: "env.JAVA_HOME", Value: "/usr/lib/jvm/java-17"},
+				{Name: "teamcity.build.id", Value: "2"},
+				{Name: "deploy.token", Value: "other", Type: &api.ParameterType{RawValue: "password"}},
+			}},
+		})
+	})
+	ts.Handle("GET /app/rest/builds/id:3", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{
+			ID: 3, State: "queued",
+			Properties: &api.ParameterList{Property: []api.Parameter{{Name: "version", Value: "2.0.0"}}},
+		})
+	})
+
+	config.SetUserForServer(ts.URL, "admin")
+	return ts
+}
+
+func TestRunParams(T *testing.T) {
+	ts := setupParamsServer(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "1")
+	assert.Regexp(T, `deploy\.token\s+\*{6}`, got)
+	assert.NotContains(T, got, "secret")
+	assert.Regexp(T, `version\s+1\.0\.0\s+run`, got)
+	assert.Regexp(T, `teamcity\.build\.id\s+1\s+predefined`, got)
+	assert.Less(T, strings.Index(got, "deploy.token"), strings.Index(got, "env.JAVA_HOME"), "sorted by name")
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "1", "--filter", "JAVA")
+	assert.Contains(T, got, "env.JAVA_HOME")
+	assert.NotContains(T, got, "version")
+}
+
+func TestRunParamsFormats(T *testing.T) {
+	ts := setupParamsServer(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "1", "--format", "env", "--prefix", "BUILD_")
+	assert.Equal(T, cmdtest.Dedent(`
+		# BUILD_DEPLOY_TOKEN: password parameter, value hidden
+		export BUILD_DOCKER_TAGS="latest 1.0.0"
+		export BUILD_JAVA_HOME=/usr/lib/jvm/java-17
+		export BUILD_TEAMCITY_BUILD_ID=1
+		export BUILD_VERSION=1.0.0
+	`), got)
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "1", "--format", "properties")
+	assert.Contains(T, got, "# deploy.token: password parameter, value hidden\n")
+	assert.Contains(T, got, "docker.tags=latest 1.0.0\n")
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "1", "--format", "json", "--filter", "token")
+	var params []map[string]any
+	require.NoError(T, json.Unmarshal([]byte(got), &params))
+	assert.Equal(T, []map[string]any{{"name": "deploy.token", "value": "******", "password": true}}, params)
+}
+
+func TestRunParamsCompare(T *testing.T) {
+	ts := setupParamsServer(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "2", "--compare", "1")
+	assert.Contains(T, got, "Parameters of run 2 compared to run 1")
+	assert.Contains(T, got, "version: 1.0.0")
+	assert.Contains(T, got, "- docker.tags: latest 1.0.0")
+	assert.NotContains(T, got, "teamcity.build.id", "predefined parameters are left out")
+	assert.NotContains(T, got, "deploy.token", "password parameters are left out")
+
+	got = cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "2", "--compare", "1", "--format", "json")
+	var diffs []map[string]any
+	require.NoError(T, json.Unmarshal([]byte(got), &diffs))
+	assert.Equal(T, []map[string]any{
+		{"name": "docker.tags", "from": "latest 1.0.0", "to": "", "type": "removed"},
+		{"name": "version", "from": "1.0.0", "to": "1.0.1", "type": "changed"},
+	}, diffs)
+}
+
+func TestRunParamsQueued(T *testing.T) {
+	ts := setupParamsServer(T)
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "params", "3", "--format", "env")
+	assert.Contains(T, got, "Run 3 has not started yet; showing only the parameters it was queued with")
+	assert.Contains(T, got, "export VERSION=2.0.0\n")
+}
+
+func TestRunParamsInvalidFlags(T *testing.T) {
+	ts := setupParamsServer(T)
+
+	err := cmdtest.CaptureErr(T, ts.Factory, "run", "params", "1", "--format", "yaml")
+	verr, ok := errors.AsType[*api.ValidationError](err)
+	require.True(T, ok, "got %v", err)
+	assert.Contains(T, verr.Msg, `invalid format "yaml"`)
+	assert.Contains(T, verr.Tip, "--format table, env, json, or properties")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--prefix only applies to --format env", "run", "params", "1", "--prefix", "X_")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--compare supports --format table or json only", "run", "params", "1", "--compare", "2", "--format", "env")
+}
@@ -0,0 +1,163 @@
+package run
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// failedTestsProblem is the problem TeamCity adds when tests fail; the tests themselves are in run tests.
+const failedTestsProblem = "TC_FAILED_TESTS"
+
+// problemSummaryMax caps how many problems run view names in its summary line.
+const problemSummaryMax = 3
+
+type runProblemsOptions struct {
+	job  string
+	json bool
+}
+
+// runProblemsJSON is the run problems --json output.
+type runProblemsJSON struct {
+	RunID    int                     `json:"run_id"`
+	Number   string                  `json:"number"`
+	Status   string                  `json:"status"`
+	WebURL   string                  `json:"web_url"`
+	Problems []api.ProblemOccurrence `json:"problems"`
+}
+
+func newRunProblemsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runProblemsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "problems [id]",
+		Short: "Show build problems",
+		Long: `Show the build problems of a run: what made it fail besides, or on top of,
+its failed tests, such as a non-zero exit code, a compilation error, a
+timeout or an error message from a build step.
+
+Each problem is listed with its type, identity and details. Problems that
+fail a run on their own are red; the summary of failed tests is yellow,
+since 'run tests --failed' lists the tests themselves.
+
+You can specify a run ID directly, or use --job to get the latest run's
+problems. With both, a run number such as #482 is looked up among the
+job's runs.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  teamcity run problems 12345
+  teamcity run problems --job Falcon_Build
+  teamcity run problems 12345 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var runID string
+			if len(args) > 0 {
+				runID = args[0]
+			}
+			if runID == "" && opts.job == "" {
+				opts.job = f.ResolveDefaultJob("")
+			}
+			return runRunProblems(f, runID, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Use this job's latest, or look up a run number in it")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runRunProblems(f *cmdutil.Factory, runID string, opts *runProblemsOptions) error {
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	runID, _, err = resolveRunID(f, client, runID, opts.job, "")
+	if err != nil {
+		return err
+	}
+	build, err := client.GetBuild(f.Context(), runID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	id := strconv.Itoa(build.ID)
+	resp, err := client.GetBuildProblems(id)
+	if err != nil {
+		return fmt.Errorf("failed to get build problems: %w", err)
+	}
+	problems := resp.ProblemOccurrence
+	if problems == nil {
+		problems = []api.ProblemOccurrence{}
+	}
+
+	if opts.json {
+		return p.PrintJSON(runProblemsJSON{
+			RunID:    build.ID,
+			Number:   build.Number,
+			Status:   build.Status,
+			WebURL:   build.WebURL,
+			Problems: problems,
+		})
+	}
+
+	if len(problems) == 0 {
+		if build.Status == "FAILURE" {
+			p.Empty(fmt.Sprintf("No build problems in #%s, but it failed", build.Number),
+				fmt.Sprintf("See the failed tests with 'teamcity run tests %s --failed', or the failing log with 'teamcity run log %s --failed'", id, id))
+			return nil
+		}
+		p.Success("No build problems in #%s", build.Number)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(p.Out, "Problems in #%s (%d):\n", build.Number, len(problems))
+	failedTests := false
+	for _, prob := range problems {
+		failedTests = failedTests || prob.Type == failedTestsProblem
+		color := output.Red
+		if prob.Type == failedTestsProblem {
+			color = output.Yellow
+		}
+		line := fmt.Sprintf("\n%s %s", color(output.Sym().Bullet), color(prob.Type))
+		if prob.Identity != "" && prob.Identity != prob.Type {
+			line += "  " + output.Faint(prob.Identity)
+		}
+		_, _ = fmt.Fprintln(p.Out, line)
+		for dl := range strings.SplitSeq(strings.TrimSpace(prob.Details), "\n") {
+			if dl != "" {
+				_, _ = fmt.Fprintf(p.Out, "    %s\n", dl)
+			}
+		}
+	}
+	if build.WebURL != "" {
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), build.WebURL)
+	}
+	if failedTests {
+		p.Tip("See the failed tests: teamcity run tests %s --failed", id)
+	}
+	return nil
+}
+
+// problemSummary is a one-line summary of problems: the first line of up to problemSummaryMax of them, then how
+// many more there are.
+func problemSummary(problems []api.ProblemOccurrence) string {
+	var parts []string
+	for _, prob := range problems[:min(len(problems), problemSummaryMax)] {
+		detail := strings.TrimSpace(prob.Details)
+		if detail == "" {
+			detail = prob.Type
+		}
+		first, _, _ := strings.Cut(detail, "\n")
+		parts = append(parts, first)
+	}
+	summary := strings.Join(parts, "; ")
+	if more := len(problems) - problemSummaryMax; more > 0 {
+		summary += fmt.Sprintf(" (+%d more)", more)
+	}
+	return summary
+}
@@ -0,0 +1,95 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+)
+
+// queuedRunInfo is what the queue knows about a queued run beyond the build itself; run view --json merges it into the build.
+type queuedRunInfo struct {
+	QueuePosition    int               `json:"queuePosition,omitempty"`
+	QueueLength      int               `json:"queueLength,omitempty"`
+	StartEstimate    string            `json:"startEstimate,omitempty"`
+	CompatibleAgents *int              `json:"compatibleAgentsCount,omitempty"`
+	Approval         *api.ApprovalInfo `json:"approval,omitempty"`
+}
+
+// fetchQueuedRunInfo collects the queue-only details of build; each lookup is best-effort and left empty on error.
+// Approval info 404s for jobs without an approval feature, which is the common case.
+func fetchQueuedRunInfo(ctx context.Context, client api.ClientInterface, build *api.Build) queuedRunInfo {
+	var info queuedRunInfo
+	if pos, length, err := client.GetQueuePosition(ctx, build.ID); err == nil {
+		info.QueuePosition, info.QueueLength = pos, length
+	}
+	if est, err := client.GetQueuedBuildStartEstimate(ctx, build.ID); err == nil {
+		info.StartEstimate = est
+	}
+	if agents, err := client.GetBuildCompatibleAgents(build.ID); err == nil {
+		info.CompatibleAgents = &agents.Count
+	}
+	if approval, err := client.GetQueuedBuildApprovalInfo(strconv.Itoa(build.ID)); err == nil && approval.Status != "" {
+		info.Approval = approval
+	}
+	return info
+}
+
+// positionText is the run's place in the queue, "3 of 12", or just "3" when the queue is too long to have been read to the end.
+func (info queuedRunInfo) positionText() string {
+	if info.QueueLength == 0 {
+		return strconv.Itoa(info.QueuePosition)
+	}
+	return fmt.Sprintf("%d of %d", info.QueuePosition, info.QueueLength)
+}
+
+// printQueuedRunInfo renders the queue section of run view. The compatible-agent count is skipped when the
+// wait reason already triggers the full compatibility breakdown.
+func printQueuedRunInfo(p *output.Printer, build *api.Build, info queuedRunInfo) {
+	_, _ = fmt.Fprintln(p.Out)
+	if info.QueuePosition > 0 {
+		_, _ = fmt.Fprintf(p.Out, "Queue position: %s\n", info.positionText())
+	}
+	if t, err := api.ParseTeamCityTime(info.StartEstimate); err == nil {
+		_, _ = fmt.Fprintf(p.Out, "Estimated start: %s\n", startEstimateText(t, timeref.Now()))
+	}
+	if info.CompatibleAgents != nil && !waitReasonIsCompatibility(build.WaitReason) {
+		count := strconv.Itoa(*info.CompatibleAgents)
+		if *info.CompatibleAgents == 0 {
+			count = output.Red(count)
+		}
+		_, _ = fmt.Fprintf(p.Out, "Compatible agents: %s\n", count)
+	}
+	if a := info.Approval; a != nil {
+		_, _ = fmt.Fprintf(p.Out, "Approval: %s", approvalStatusText(a.Status))
+		if a.Status == "waitingForApproval" && a.CanBeApprovedByCurrentUser {
+			_, _ = fmt.Fprintf(p.Out, "  %s teamcity queue approve %d", output.Faint(output.Sym().Sep), build.ID)
+		}
+		_, _ = fmt.Fprintln(p.Out)
+	}
+}
+
+// startEstimateText phrases an estimate relative to now; a past estimate means the run is due any moment.
+func startEstimateText(t, now time.Time) string {
+	if d := t.Sub(now); d > 0 {
+		return "in " + output.FormatDuration(d)
+	}
+	return "any moment"
+}
+
+func approvalStatusText(status string) string {
+	switch status {
+	case "waitingForApproval":
+		return output.Yellow("waiting for approval")
+	case "approved":
+		return output.Green("approved")
+	case "timedOut":
+		return output.Red("timed out")
+	default:
+		return status
+	}
+}
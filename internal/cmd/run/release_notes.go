@@ -0,0 +1,113 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+)
+
+// shaPlaceholder is replaced with the full commit SHA when a link template is expanded.
+const shaPlaceholder = "{sha}"
+
+// markdownEscaper escapes characters that would turn a commit subject into markup or raw HTML.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`)
+
+// isMergeChange reports whether c has more than one parent; servers that omit parents never report merges.
+func isMergeChange(c api.Change) bool {
+	return c.ParentRevisions != nil && len(c.ParentRevisions.Item) > 1
+}
+
+// withoutMerges drops merge commits, keeping the order of the rest.
+func withoutMerges(changes []api.Change) []api.Change {
+	return slices.DeleteFunc(slices.Clone(changes), isMergeChange)
+}
+
+// validateLinkTemplate rejects templates that would link every commit to the same page.
+func validateLinkTemplate(tpl string) error {
+	if !strings.Contains(tpl, shaPlaceholder) {
+		return api.Validation(
+			fmt.Sprintf("link template %q has no %s placeholder", tpl, shaPlaceholder),
+			"e.g. --link-template 'https://github.com/org/repo/commit/{sha}'",
+		)
+	}
+	return nil
+}
+
+// expandLinkTemplate substitutes sha into every {sha} placeholder of tpl.
+func expandLinkTemplate(tpl, sha string) string {
+	return strings.ReplaceAll(tpl, shaPlaceholder, url.PathEscape(sha))
+}
+
+// commitLinkTemplate picks the template for c: the --link-template override, else the commit_link.<vcs-root-id> config key.
+func commitLinkTemplate(c api.Change, override string) string {
+	if override != "" {
+		return override
+	}
+	if c.VcsRootInstance != nil {
+		return config.CommitLinkTemplate(c.VcsRootInstance.VcsRootID)
+	}
+	return ""
+}
+
+// markdownCommit renders one bullet; author is omitted when the list is already grouped by it.
+func markdownCommit(c api.Change, tpl string, withAuthor bool) string {
+	subject := strings.TrimSpace(firstLine(c.Comment))
+	if subject == "" {
+		subject = "(no message)"
+	}
+	ref := shortSHA(c.Version)
+	if tpl != "" && c.Version != "" {
+		ref = fmt.Sprintf("[%s](%s)", ref, expandLinkTemplate(tpl, c.Version))
+	}
+
+	var b strings.Builder
+	b.WriteString("- ")
+	b.WriteString(markdownEscaper.Replace(subject))
+	if withAuthor && c.Username != "" {
+		b.WriteString(" by ")
+		b.WriteString(markdownEscaper.Replace(c.Username))
+	}
+	if ref != "" {
+		b.WriteString(" in ")
+		b.WriteString(ref)
+	}
+	return b.String()
+}
+
+// writeChangesMarkdown renders changes as a Markdown bullet list ready for a release body.
+// With groupByAuthor, each author gets a heading, in order of their first commit in the list.
+func writeChangesMarkdown(w io.Writer, changes []api.Change, linkTemplate func(api.Change) string, groupByAuthor bool) {
+	if !groupByAuthor {
+		for _, c := range changes {
+			_, _ = fmt.Fprintln(w, markdownCommit(c, linkTemplate(c), true))
+		}
+		return
+	}
+
+	var authors []string
+	byAuthor := map[string][]api.Change{}
+	for _, c := range changes {
+		author := c.Username
+		if author == "" {
+			author = "unknown"
+		}
+		if _, ok := byAuthor[author]; !ok {
+			authors = append(authors, author)
+		}
+		byAuthor[author] = append(byAuthor[author], c)
+	}
+	for i, author := range authors {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "### %s\n\n", markdownEscaper.Replace(author))
+		for _, c := range byAuthor[author] {
+			_, _ = fmt.Fprintln(w, markdownCommit(c, linkTemplate(c), false))
+		}
+	}
+}
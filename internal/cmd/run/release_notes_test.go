@@ -0,0 +1,85 @@
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandLinkTemplate(t *testing.T) {
+	tests := []struct {
+		tpl, sha, want string
+	}{
+		{"https://github.com/org/repo/commit/{sha}", "0123456789abcdef", "https://github.com/org/repo/commit/0123456789abcdef"},
+		{"https://git.example.com/r?h={sha}&full={sha}", "abc", "https://git.example.com/r?h=abc&full=abc"},
+		{"https://svn.example.com/rev/{sha}", "r 12/3", "https://svn.example.com/rev/r%2012%2F3"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, expandLinkTemplate(tc.tpl, tc.sha))
+	}
+}
+
+func TestValidateLinkTemplate(t *testing.T) {
+	assert.NoError(t, validateLinkTemplate("https://github.com/org/repo/commit/{sha}"))
+	assert.ErrorContains(t, validateLinkTemplate("https://github.com/org/repo/commit/"), "no {sha} placeholder")
+}
+
+func TestWithoutMerges(t *testing.T) {
+	changes := []api.Change{
+		{Version: "m1", ParentRevisions: &api.Items{Item: []string{"p1", "p2"}}},
+		{Version: "c1", ParentRevisions: &api.Items{Item: []string{"p1"}}},
+		{Version: "c2"},
+	}
+	got := withoutMerges(changes)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "c1", got[0].Version)
+	assert.Equal(t, "c2", got[1].Version)
+	assert.Len(t, changes, 3, "input must not be modified")
+}
+
+func TestWriteChangesMarkdown(t *testing.T) {
+	changes := []api.Change{
+		{Version: "1111111aaaa", Username: "alice", Comment: "Fix *login* redirect\n\nLong body"},
+		{Version: "2222222bbbb", Username: "bob", Comment: "Add <b>bold</b> flag"},
+		{Version: "3333333cccc", Username: "alice", Comment: ""},
+	}
+	link := func(c api.Change) string {
+		if c.Username == "bob" {
+			return ""
+		}
+		return "https://github.com/org/repo/commit/{sha}"
+	}
+
+	render := func(groupByAuthor bool) string {
+		var b strings.Builder
+		writeChangesMarkdown(&b, changes, link, groupByAuthor)
+		return b.String()
+	}
+
+	t.Run("flat", func(t *testing.T) {
+		assert.Equal(t, `- Fix \*login\* redirect by alice in [1111111](https://github.com/org/repo/commit/1111111aaaa)
+- Add \<b\>bold\</b\> flag by bob in 2222222
+- (no message) by alice in [3333333](https://github.com/org/repo/commit/3333333cccc)
+`, render(false))
+	})
+
+	t.Run("grouped by author", func(t *testing.T) {
+		assert.Equal(t, `### alice
+
+- Fix \*login\* redirect in [1111111](https://github.com/org/repo/commit/1111111aaaa)
+- (no message) in [3333333](https://github.com/org/repo/commit/3333333cccc)
+
+### bob
+
+- Add \<b\>bold\</b\> flag in 2222222
+`, render(true))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var b strings.Builder
+		writeChangesMarkdown(&b, nil, link, true)
+		assert.Empty(t, b.String())
+	})
+}
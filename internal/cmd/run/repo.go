@@ -0,0 +1,183 @@
+package run
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/git"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/charmbracelet/huh"
+)
+
+// repoRootFields fetches each root's properties so its url can be compared locally.
+var repoRootFields = []string{"id", "name", "properties.property.name", "properties.property.value"}
+
+// findRepoJobs returns the unpaused jobs attached to a VCS root fetching repoURL, sorted by ID.
+// The server narrows roots by the org/repo path; ssh and https forms are then compared via git.CanonicalURL.
+func findRepoJobs(client api.ClientInterface, repoURL string) ([]api.BuildType, error) {
+	canonical := git.CanonicalURL(repoURL)
+	if canonical == "" {
+		return nil, api.Validation(
+			fmt.Sprintf("invalid repository URL %q", repoURL),
+			"Use an ssh or https git URL, e.g. --repo git@github.com:org/app.git",
+		)
+	}
+
+	roots, _, err := client.GetVcsRoots(api.VcsRootsOptions{URL: git.RepoPath(repoURL), Limit: 0, Fields: repoRootFields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VCS roots: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var jobs []api.BuildType
+	for _, root := range roots.VcsRoot {
+		if !vcsRootFetches(root, canonical) {
+			continue
+		}
+		list, _, err := client.GetBuildTypes(api.BuildTypesOptions{VcsRoot: root.ID, Limit: 0})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs using VCS root %s: %w", root.ID, err)
+		}
+		for _, bt := range list.BuildTypes {
+			if bt.Paused || seen[bt.ID] {
+				continue
+			}
+			seen[bt.ID] = true
+			jobs = append(jobs, bt)
+		}
+	}
+	slices.SortFunc(jobs, func(a, b api.BuildType) int { return cmp.Compare(a.ID, b.ID) })
+	return jobs, nil
+}
+
+// vcsRootFetches reports whether root's url property has the given canonical form.
+func vcsRootFetches(root api.VcsRoot, canonical string) bool {
+	if root.Properties == nil {
+		return false
+	}
+	for _, p := range root.Properties.Property {
+		if p.Name == "url" && git.CanonicalURL(p.Value) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// repoJobLabel renders a job as "Project · Name (ID)" for the picker and the ambiguity error.
+func repoJobLabel(bt api.BuildType) string {
+	label := fmt.Sprintf("%s (%s)", bt.Name, bt.ID)
+	if bt.ProjectName != "" {
+		label = bt.ProjectName + " " + output.Sym().Sep + " " + label
+	}
+	return label
+}
+
+// runRunStartRepo starts the job(s) attached to opts.repo: the single match, every match with --all-matching,
+// or a picked one when interactive; otherwise an ambiguous URL is an error listing the candidates.
+func runRunStartRepo(f *cmdutil.Factory, opts *runStartOptions) error {
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	jobs, err := findRepoJobs(client, opts.repo)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(jobs) == 0:
+		return api.Validation(
+			fmt.Sprintf("no jobs found for repository %s", opts.repo),
+			"Check the VCS root URLs with: teamcity project vcs list",
+		)
+	case len(jobs) == 1:
+		return runRunStart(f, jobs[0].ID, opts)
+	case opts.allMatching:
+		ids := make([]string, len(jobs))
+		for i, bt := range jobs {
+			ids[i] = bt.ID
+		}
+		return runRunStartAll(f, ids, opts)
+	case f.IsInteractive() && !opts.json:
+		options := make([]huh.Option[string], len(jobs))
+		for i, bt := range jobs {
+			options[i] = huh.NewOption(repoJobLabel(bt), bt.ID)
+		}
+		var selected string
+		if err := cmdutil.Select(f.Printer, "Select job", options, &selected); err != nil {
+			return err
+		}
+		return runRunStart(f, selected, opts)
+	default:
+		lines := make([]string, len(jobs))
+		for i, bt := range jobs {
+			lines[i] = "  " + repoJobLabel(bt)
+		}
+		return api.Validation(
+			fmt.Sprintf("%d jobs use repository %s:\n%s", len(jobs), opts.repo, strings.Join(lines, "\n")),
+			"Pass one of the job IDs instead of --repo, or add --all-matching to start them all",
+		)
+	}
+}
+
+// runRunStartAll queues a run of every job in jobIDs with the same options. A failure to queue one job
+// does not stop the rest; the failures are reported together at the end.
+func runRunStartAll(f *cmdutil.Factory, jobIDs []string, opts *runStartOptions) error {
+	p := f.Printer
+	freezeSettings, artifactPins, err := opts.prepare(f)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		if opts.json {
+			return p.PrintJSON(struct {
+				DryRun   bool     `json:"dry_run"`
+				Jobs     []string `json:"jobs"`
+				Branch   string   `json:"branch,omitempty"`
+				Revision string   `json:"revision,omitempty"`
+			}{DryRun: true, Jobs: jobIDs, Branch: opts.branch, Revision: opts.revision})
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s Would trigger runs for %d jobs\n", output.Faint("[dry-run]"), len(jobIDs))
+		for _, id := range jobIDs {
+			_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Cyan(id))
+		}
+		if opts.branch != "" {
+			_, _ = fmt.Fprintf(p.Out, "  Branch: %s\n", opts.branch)
+		}
+		if opts.revision != "" {
+			_, _ = fmt.Fprintf(p.Out, "  Revision: %s\n", opts.revision)
+		}
+		return nil
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	builds := []*api.Build{}
+	var errs []error
+	for _, id := range jobIDs {
+		build, err := queueRun(f, client, id, opts, freezeSettings, artifactPins)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		builds = append(builds, build)
+		if !opts.json {
+			printQueuedRun(p, build, id)
+			p.Info("  URL: %s", build.WebURL)
+		}
+	}
+	if opts.json {
+		if err := p.PrintJSON(builds); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -2,6 +2,10 @@ package run
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
@@ -10,7 +14,11 @@ import (
 
 type runRestartOptions struct {
 	watchFlags
-	web bool
+	job          string
+	web          bool
+	params       map[string]string
+	cleanSources bool
+	queueAtTop   bool
 }
 
 func newRunRestartCmd(f *cmdutil.Factory) *cobra.Command {
@@ -19,42 +27,64 @@ func newRunRestartCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "restart <id>",
 		Short: "Restart a run",
-		Long: `Re-queue a run with the same job, branch, revision, and parameters.
+		Long: `Re-queue a run with the same job, branch, revisions, and parameters.
 
-The new run is a fresh build (new ID, new number) that reuses the
-source configuration from the original. Use --watch to stream the
-restarted run until it completes.`,
+The new run is a fresh build (new ID, new number) that replays how the
+original was triggered: the parameters set for it, its tags, its personal
+changes, and the revision of each VCS root. Its comment is carried over
+after "Restart of #N". Secure parameter values cannot be read back, so
+the job's own values apply unless you pass them with --param.
+
+Use --param to change a value while restarting, and --watch to stream
+the restarted run until it completes.`,
 		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run restart 12345
-  teamcity run restart 12345 --watch`,
+  teamcity run restart 12345 --watch
+  teamcity run restart 12345 -P env.DEPLOY_TARGET=staging --top`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRunRestart(f, args[0], opts)
 		},
 	}
 
 	opts.addToCmd(cmd)
+	cmd.Flags().StringToStringVarP(&opts.params, "param", "P", nil, "Override a parameter of the original run (key=value, repeatable)")
+	cmd.Flags().BoolVar(&opts.cleanSources, "clean", false, "Clean sources before start")
+	cmd.Flags().BoolVar(&opts.queueAtTop, "top", false, "Add to top of queue")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser")
+	addRunJobFlag(cmd, &opts.job)
 
 	return cmd
 }
 
 func runRunRestart(f *cmdutil.Factory, runID string, opts *runRestartOptions) error {
 	p := f.Printer
-	opts.resolve()
+	if err := opts.resolve(); err != nil {
+		return err
+	}
 
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	originalBuild, err := client.GetBuild(f.Context(), runID)
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+
+	originalBuild, err := client.GetBuildForRerun(f.Context(), runID)
 	if err != nil {
 		return fmt.Errorf("failed to get run: %w", err)
 	}
 
-	newBuild, err := client.RunBuild(originalBuild.BuildTypeID, api.RunBuildOptions{
-		Branch: originalBuild.BranchName,
-	})
+	runOpts := rerunOptions(originalBuild, opts.params)
+	runOpts.CleanSources = opts.cleanSources
+	runOpts.QueueAtTop = opts.queueAtTop
+	for _, name := range skippedSecureParams(originalBuild, opts.params) {
+		p.Warn("Secure parameter %s is not replayed; the job's value applies (pass it with --param to set it)", name)
+	}
+
+	newBuild, err := client.RunBuild(originalBuild.BuildTypeID, runOpts)
 	if err != nil {
 		return fmt.Errorf("failed to trigger run: %w", err)
 	}
@@ -64,7 +94,70 @@ func runRunRestart(f *cmdutil.Factory, runID string, opts *runRestartOptions) er
 	if originalBuild.BranchName != "" {
 		_, _ = fmt.Fprintf(p.Out, "  Branch: %s\n", originalBuild.BranchName)
 	}
+	if len(runOpts.Params) > 0 {
+		_, _ = fmt.Fprintf(p.Out, "  Parameters: %s\n", strings.Join(slices.Sorted(maps.Keys(runOpts.Params)), ", "))
+	}
+	if runOpts.Personal {
+		_, _ = fmt.Fprintln(p.Out, "  Personal: yes")
+	}
 	p.Info("  URL: %s", newBuild.WebURL)
 
 	return afterQueue(f, newBuild, opts.web, &opts.watchFlags)
 }
+
+// rerunOptions triggers build's job the way build was: same branch, revisions, parameters set at trigger time
+// (overridden by overrides), tags, and personal change, with its comment after "Restart of #N". Secure
+// parameters are left out unless overridden, since the server never returns their values.
+func rerunOptions(build *api.RerunBuild, overrides map[string]string) api.RunBuildOptions {
+	opts := api.RunBuildOptions{
+		Branch:   build.BranchName,
+		Params:   map[string]string{},
+		Personal: build.Personal,
+		Comment:  "Restart of #" + build.Number,
+	}
+	if build.Number == "" {
+		opts.Comment = fmt.Sprintf("Restart of %d", build.ID)
+	}
+	if build.Properties != nil {
+		for _, prop := range build.Properties.Property {
+			if !prop.IsPassword() {
+				opts.Params[prop.Name] = prop.Value
+			}
+		}
+	}
+	maps.Copy(opts.Params, overrides)
+	if build.Comment != nil && build.Comment.Text != "" {
+		opts.Comment += ": " + build.Comment.Text
+	}
+	if build.Tags != nil {
+		for _, t := range build.Tags.Tag {
+			opts.Tags = append(opts.Tags, t.Name)
+		}
+	}
+	if build.LastChanges != nil {
+		for _, c := range build.LastChanges.Change {
+			if c.Personal {
+				opts.PersonalChangeID = strconv.Itoa(c.ID)
+				break
+			}
+		}
+	}
+	if build.Revisions != nil {
+		opts.Revisions = build.Revisions.Revision
+	}
+	return opts
+}
+
+// skippedSecureParams names the secure parameters of build that rerunOptions leaves out.
+func skippedSecureParams(build *api.RerunBuild, overrides map[string]string) []string {
+	if build.Properties == nil {
+		return nil
+	}
+	var names []string
+	for _, prop := range build.Properties.Property {
+		if _, ok := overrides[prop.Name]; prop.IsPassword() && !ok {
+			names = append(names, prop.Name)
+		}
+	}
+	return names
+}
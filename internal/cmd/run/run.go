@@ -1,12 +1,14 @@
 package run
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/spf13/cobra"
 )
 
@@ -46,6 +48,7 @@ See: https://www.jetbrains.com/help/teamcity/build-results-page.html`,
 		newRunViewCmd(f),
 		newRunStartCmd(f),
 		newRunCancelCmd(f),
+		newRunApproveCmd(f),
 		newRunWatchCmd(f),
 		newRunRestartCmd(f),
 		newRunDiffCmd(f),
@@ -55,6 +58,8 @@ See: https://www.jetbrains.com/help/teamcity/build-results-page.html`,
 		newRunArtifactsCmd(f),
 		newRunDownloadCmd(f),
 		newRunLogCmd(f),
+		newRunSnapshotCmd(f),
+		newRunShowSnapshotCmd(f),
 	)
 	addInGroup("metadata",
 		newRunPinCmd(f),
@@ -66,18 +71,26 @@ See: https://www.jetbrains.com/help/teamcity/build-results-page.html`,
 	addInGroup("analysis",
 		newRunChangesCmd(f),
 		newRunTestsCmd(f),
+		newRunBisectCmd(f),
+		newRunHistoryCmd(f),
+		newRunParamsCmd(f),
+		newRunProblemsCmd(f),
 	)
 
 	cmdutil.AliasAwareHelp(cmd, "run", "build")
 	return cmd
 }
 
-// resolveRunID returns runID when set, else looks up the latest run of jobID (constrained by state).
-// The build is also returned so callers can show "#<num>" details. Either runID or jobID must be set;
-// otherwise we return a Validation error pointing at the link path.
-func resolveRunID(ctx context.Context, client api.ClientInterface, runID, jobID, state string) (string, *api.Build, error) {
+// resolveRunID resolves runID with resolveRunRef when set, else looks up the latest run of jobID (constrained
+// by state). The latest build is also returned so callers can show "#<num>" details. Either runID or jobID
+// must be set; otherwise we return a Validation error pointing at the link path.
+func resolveRunID(f *cmdutil.Factory, client api.ClientInterface, runID, jobID, state string) (string, *api.Build, error) {
+	if runID != "" {
+		id, err := resolveRunRef(f, client, runID, jobID)
+		return id, nil, err
+	}
 	if jobID != "" {
-		runs, _, err := client.GetBuilds(ctx, api.BuildsOptions{
+		runs, _, err := client.GetBuilds(f.Context(), api.BuildsOptions{
 			BuildTypeID: jobID,
 			State:       state,
 			Limit:       1,
@@ -94,11 +107,62 @@ func resolveRunID(ctx context.Context, client api.ClientInterface, runID, jobID,
 		b := &runs.Builds[0]
 		return strconv.Itoa(b.ID), b, nil
 	}
-	if runID == "" {
-		return "", nil, api.Validation(
-			"run ID required",
-			"Pass <id>, use --job to get the latest run, or run 'teamcity link' to bind a default job",
+	return "", nil, api.Validation(
+		"run ID required",
+		"Pass <id>, use --job to get the latest run, or run 'teamcity link' to bind a default job",
+	)
+}
+
+// resolveRunRef resolves a run reference from the command line to a run ID. A TeamCity web URL yields the
+// ID in it. With a job context (jobID, else the default job), "#<n>" is looked up among that job's runs, and
+// so is a bare number no run has as its ID, since users often paste the build number the UI shows.
+func resolveRunRef(f *cmdutil.Factory, client api.ClientInterface, ref, jobID string) (string, error) {
+	if _, isURL := api.BuildIDFromURL(ref); isURL {
+		return client.ResolveBuildID(f.Context(), ref)
+	}
+	number, isNumber := strings.CutPrefix(ref, "#")
+	bare := !isNumber && ref != "" && strings.Trim(ref, "0123456789") == ""
+	if !isNumber && !bare {
+		return ref, nil
+	}
+	job := f.ResolveDefaultJob(jobID)
+	if job == "" {
+		return ref, nil
+	}
+	if bare {
+		_, err := client.GetBuild(f.Context(), ref)
+		if _, notFound := errors.AsType[*api.NotFoundError](err); !notFound {
+			// The run exists, or the command reports the failure when it fetches the run itself.
+			return ref, nil
+		}
+		number = ref
+	}
+
+	runs, _, err := client.GetBuilds(f.Context(), api.BuildsOptions{BuildTypeID: job, Number: number, Limit: 1, DeepLookup: true})
+	if err != nil {
+		return "", err
+	}
+	if len(runs.Builds) == 0 {
+		if bare {
+			return "", api.Validation(
+				fmt.Sprintf("no run has ID %s, and job %s has no run #%s", ref, job, number),
+				fmt.Sprintf("Check the number with 'teamcity run list --job %s'", job),
+			)
+		}
+		return "", api.Validation(
+			fmt.Sprintf("job %s has no run #%s", job, number),
+			fmt.Sprintf("Check the number with 'teamcity run list --job %s'", job),
 		)
 	}
-	return runID, nil, nil
+	id := strconv.Itoa(runs.Builds[0].ID)
+	if bare {
+		f.Printer.Warn("No run has ID %s; using #%s of %s (ID %s)", ref, number, job, id)
+	}
+	return id, nil
+}
+
+// addRunJobFlag adds --job, the job a run number given in place of a run ID is looked up in.
+func addRunJobFlag(cmd *cobra.Command, job *string) {
+	cmd.Flags().StringVarP(job, "job", "j", "", "Job to look up a run number in")
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
 }
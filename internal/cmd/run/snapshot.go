@@ -0,0 +1,508 @@
+package run
+
+import (
+	"archive/tar"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/version"
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+// runSource is where the run view, tests, changes, and log renderers read a run from: the live server,
+// which api.ClientInterface serves, or a snapshot file, which runSnapshot serves.
+type runSource interface {
+	GetBuild(ctx context.Context, ref string) (*api.Build, error)
+	GetBuildUsedByOtherBuilds(id string) (bool, error)
+	GetBuildBatches(ctx context.Context, build *api.Build) ([]api.BuildBatch, error)
+	GetBuildPipelineRun(buildID string) (*api.PipelineRun, error)
+	GetBuildTests(ctx context.Context, buildID string, opts api.BuildTestsOptions) (*api.TestOccurrences, error)
+	GetBuildProblems(buildID string) (*api.ProblemOccurrences, error)
+	GetBuildChanges(ctx context.Context, buildID string) (*api.ChangeList, error)
+	GetBuildLog(ctx context.Context, buildID string) (string, error)
+	GetBuildLogStream(ctx context.Context, buildID string) (io.ReadCloser, error)
+}
+
+// snapshotFormat is the version of the snapshot layout written by run snapshot. Bump it when a change
+// would make older CLIs misread a snapshot; they refuse snapshots newer than the format they know.
+const snapshotFormat = 1
+
+// A snapshot file is a gzip-compressed tar archive holding these entries; artifacts go under artifacts/.
+const (
+	snapshotDataEntry     = "snapshot.json"
+	snapshotLogEntry      = "run.log"
+	snapshotArtifactsPath = "artifacts/"
+)
+
+// snapshotManifest describes a snapshot: its format, where and when it was taken, and what it holds.
+type snapshotManifest struct {
+	Format     int                `json:"format"`
+	RunID      int                `json:"runId"`
+	Server     string             `json:"server,omitempty"`
+	CLIVersion string             `json:"cliVersion,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	HasLog     bool               `json:"hasLog"`
+	Artifacts  []snapshotArtifact `json:"artifacts,omitempty"`
+}
+
+type snapshotArtifact struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// runSnapshot is a run archived by run snapshot. It serves runSource for that one run, ignoring the IDs asked for.
+type runSnapshot struct {
+	Manifest    snapshotManifest        `json:"manifest"`
+	Build       *api.Build              `json:"build"`
+	Batches     []api.BuildBatch        `json:"batches,omitempty"`
+	PipelineRun *api.PipelineRun        `json:"pipelineRun,omitempty"`
+	Tests       *api.TestOccurrences    `json:"tests"`
+	Problems    *api.ProblemOccurrences `json:"problems"`
+	Changes     *api.ChangeList         `json:"changes"`
+	Statistics  *api.PropertyList       `json:"statistics"`
+
+	log string
+}
+
+func (s *runSnapshot) GetBuild(context.Context, string) (*api.Build, error) {
+	build := *s.Build
+	return &build, nil
+}
+
+func (s *runSnapshot) GetBuildUsedByOtherBuilds(string) (bool, error) {
+	return s.Build.UsedByOtherBuilds, nil
+}
+
+func (s *runSnapshot) GetBuildBatches(context.Context, *api.Build) ([]api.BuildBatch, error) {
+	return s.Batches, nil
+}
+
+func (s *runSnapshot) GetBuildPipelineRun(string) (*api.PipelineRun, error) {
+	return s.PipelineRun, nil
+}
+
+// GetBuildTests filters the archived tests the way the server filters a run's tests.
+func (s *runSnapshot) GetBuildTests(_ context.Context, _ string, opts api.BuildTestsOptions) (*api.TestOccurrences, error) {
+	result := &api.TestOccurrences{TestOccurrence: []api.TestOccurrence{}}
+	if s.Tests == nil {
+		return result, nil
+	}
+	for _, t := range s.Tests.TestOccurrence {
+		if (opts.FailedOnly || opts.MutedOnly) && (t.Status != "FAILURE" || t.Muted != opts.MutedOnly) {
+			continue
+		}
+		switch {
+		case t.Muted:
+			result.Muted++
+		case t.Status == "SUCCESS":
+			result.Passed++
+		case t.Status == "FAILURE":
+			result.Failed++
+		default:
+			result.Ignored++
+		}
+		if opts.Limit <= 0 || len(result.TestOccurrence) < opts.Limit {
+			result.TestOccurrence = append(result.TestOccurrence, t)
+		}
+	}
+	result.Count = len(result.TestOccurrence)
+	return result, nil
+}
+
+func (s *runSnapshot) GetBuildProblems(string) (*api.ProblemOccurrences, error) {
+	if s.Problems == nil {
+		return &api.ProblemOccurrences{}, nil
+	}
+	return s.Problems, nil
+}
+
+func (s *runSnapshot) GetBuildChanges(context.Context, string) (*api.ChangeList, error) {
+	if s.Changes == nil {
+		return &api.ChangeList{}, nil
+	}
+	return s.Changes, nil
+}
+
+func (s *runSnapshot) GetBuildLog(context.Context, string) (string, error) {
+	if !s.Manifest.HasLog {
+		return "", api.Validation("the snapshot does not include the run log", "take the snapshot with 'teamcity run snapshot --log'")
+	}
+	return s.log, nil
+}
+
+func (s *runSnapshot) GetBuildLogStream(ctx context.Context, buildID string) (io.ReadCloser, error) {
+	log, err := s.GetBuildLog(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(log)), nil
+}
+
+type runSnapshotOptions struct {
+	output    string
+	log       bool
+	artifacts []string
+	job       string
+}
+
+func newRunSnapshotCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "snapshot <id>",
+		Short: "Save a run to a file for offline inspection",
+		Long: `Save a run to a single compressed file, so it can still be inspected after
+retention rules clean it up on the server.
+
+The snapshot holds the run's details, tests, build problems, VCS changes,
+and statistics. --log adds the full build log, and --artifact adds the
+artifacts matching a pattern. Read it back with 'teamcity run show-snapshot'.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  teamcity run snapshot 12345
+  teamcity run snapshot 12345 -o incident-42.tcsnap --log
+  teamcity run snapshot 12345 --log --artifact "*.xml" --artifact "reports/*"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunSnapshot(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "File to write (default run-<id>.tcsnap)")
+	cmd.Flags().BoolVar(&opts.log, "log", false, "Include the full build log")
+	cmd.Flags().StringArrayVarP(&opts.artifacts, "artifact", "a", nil, "Include artifacts matching this pattern (repeatable)")
+	addRunJobFlag(cmd, &opts.job)
+
+	_ = cmd.MarkFlagFilename("output", "tcsnap")
+
+	return cmd
+}
+
+func runRunSnapshot(f *cmdutil.Factory, runID string, opts *runSnapshotOptions) error {
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	ctx := f.Context()
+
+	runID, err = resolveRunRef(f, client, runID, opts.job)
+	if err != nil {
+		return err
+	}
+	snap, err := takeSnapshot(ctx, client, runID, opts.log)
+	if err != nil {
+		return err
+	}
+	build := snap.Build
+	if build.State != "finished" {
+		p.Warn("Run %d has not finished; the snapshot holds its state so far", build.ID)
+	}
+
+	var artifacts []api.Artifact
+	if len(opts.artifacts) > 0 {
+		all, _, err := cmdutil.FetchAllArtifacts(ctx, client, strconv.Itoa(build.ID), "")
+		if err != nil {
+			return fmt.Errorf("failed to get artifacts: %w", err)
+		}
+		seen := map[string]bool{}
+		for _, pattern := range opts.artifacts {
+			matches, _, err := filterArtifacts(all, pattern)
+			if err != nil {
+				return err
+			}
+			for _, a := range matches {
+				if !seen[a.Name] {
+					seen[a.Name] = true
+					artifacts = append(artifacts, a)
+					snap.Manifest.Artifacts = append(snap.Manifest.Artifacts, snapshotArtifact{Name: a.Name, Size: a.Size})
+				}
+			}
+		}
+		if len(artifacts) == 0 {
+			p.Warn("No artifacts match %s", strings.Join(opts.artifacts, ", "))
+		}
+	}
+
+	outPath := opts.output
+	if outPath == "" {
+		outPath = fmt.Sprintf("run-%d.tcsnap", build.ID)
+	}
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if err := writeSnapshot(ctx, file, snap, client, artifacts); err != nil {
+		_ = file.Close()
+		_ = os.Remove(outPath)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	size := ""
+	if info, err := os.Stat(outPath); err == nil {
+		size = " (" + humanize.IBytes(uint64(info.Size())) + ")"
+	}
+	p.Success("Saved run %d to %s%s", build.ID, outPath, size)
+	_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(snapshotContents(snap)))
+	return nil
+}
+
+// takeSnapshot fetches everything a snapshot holds except artifacts.
+func takeSnapshot(ctx context.Context, client api.ClientInterface, runID string, withLog bool) (*runSnapshot, error) {
+	build, err := client.GetBuild(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	id := strconv.Itoa(build.ID)
+	snap := &runSnapshot{
+		Manifest: snapshotManifest{
+			Format:     snapshotFormat,
+			RunID:      build.ID,
+			Server:     client.ServerURL(),
+			CLIVersion: version.String(),
+			CreatedAt:  time.Now().UTC().Truncate(time.Second),
+			HasLog:     withLog,
+		},
+		Build: build,
+	}
+
+	build.UsedByOtherBuilds, _ = client.GetBuildUsedByOtherBuilds(id)
+	snap.Batches, _ = client.GetBuildBatches(ctx, build)
+	snap.PipelineRun, _ = client.GetBuildPipelineRun(id)
+	if snap.Tests, err = client.GetBuildTests(ctx, id, api.BuildTestsOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get tests: %w", err)
+	}
+	if snap.Problems, err = client.GetBuildProblems(id); err != nil {
+		return nil, fmt.Errorf("failed to get build problems: %w", err)
+	}
+	if snap.Changes, err = client.GetBuildChanges(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	if snap.Statistics, err = client.GetBuildStatistics(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get statistics: %w", err)
+	}
+	if withLog {
+		if snap.log, err = client.GetBuildLog(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to get run log: %w", err)
+		}
+	}
+	return snap, nil
+}
+
+// writeSnapshot writes snap and the given artifacts of its run to w as a snapshot file.
+func writeSnapshot(ctx context.Context, w io.Writer, snap *runSnapshot, client api.ClientInterface, artifacts []api.Artifact) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := snap.Manifest.CreatedAt
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeSnapshotEntry(tw, snapshotDataEntry, data, modTime); err != nil {
+		return err
+	}
+	if snap.Manifest.HasLog {
+		if err := writeSnapshotEntry(tw, snapshotLogEntry, []byte(snap.log), modTime); err != nil {
+			return err
+		}
+	}
+
+	runID := strconv.Itoa(snap.Build.ID)
+	for _, a := range artifacts {
+		// Artifacts are buffered on disk because a tar header needs the exact size up front.
+		tmp, err := os.CreateTemp("", "tcsnap-*")
+		if err != nil {
+			return err
+		}
+		err = func() error {
+			defer func() { _ = tmp.Close(); _ = os.Remove(tmp.Name()) }()
+			size, err := client.DownloadArtifactTo(ctx, runID, a.Name, tmp)
+			if err != nil {
+				return fmt.Errorf("artifact %s: %w", a.Name, err)
+			}
+			if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			hdr := &tar.Header{Name: snapshotArtifactsPath + a.Name, Mode: 0644, Size: size, ModTime: modTime}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err = io.Copy(tw, tmp)
+			return err
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeSnapshotEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// loadSnapshot reads a snapshot file written by run snapshot. Artifacts stay in the file.
+func loadSnapshot(file string) (*runSnapshot, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fh.Close() }()
+
+	notSnapshot := api.Validation(fmt.Sprintf("%s is not a run snapshot", file), "create one with 'teamcity run snapshot <id>'")
+	gz, err := gzip.NewReader(fh)
+	if err != nil {
+		return nil, notSnapshot
+	}
+	tr := tar.NewReader(gz)
+
+	var snap *runSnapshot
+	var log string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		switch hdr.Name {
+		case snapshotDataEntry:
+			snap = &runSnapshot{}
+			if err := json.NewDecoder(tr).Decode(snap); err != nil {
+				return nil, fmt.Errorf("failed to read snapshot: %w", err)
+			}
+		case snapshotLogEntry:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read snapshot: %w", err)
+			}
+			log = string(data)
+		}
+	}
+
+	switch {
+	case snap == nil || snap.Manifest.Format == 0 || snap.Build == nil:
+		return nil, notSnapshot
+	case snap.Manifest.Format > snapshotFormat:
+		return nil, api.Validation(
+			fmt.Sprintf("%s has snapshot format %d; this version of teamcity reads up to format %d", file, snap.Manifest.Format, snapshotFormat),
+			"Update teamcity with 'teamcity update'")
+	}
+	snap.log = log
+	return snap, nil
+}
+
+// snapshotContents summarizes what a snapshot holds, for one line of output.
+func snapshotContents(snap *runSnapshot) string {
+	parts := []string{"details"}
+	if snap.Tests != nil {
+		parts = append(parts, english.Plural(len(snap.Tests.TestOccurrence), "test", ""))
+	}
+	if snap.Problems != nil {
+		parts = append(parts, english.Plural(len(snap.Problems.ProblemOccurrence), "problem", ""))
+	}
+	if snap.Changes != nil {
+		parts = append(parts, english.Plural(len(snap.Changes.Change), "change", ""))
+	}
+	if snap.Statistics != nil {
+		parts = append(parts, english.Plural(len(snap.Statistics.Property), "statistic", ""))
+	}
+	if snap.Manifest.HasLog {
+		parts = append(parts, "log")
+	}
+	if n := len(snap.Manifest.Artifacts); n > 0 {
+		parts = append(parts, english.Plural(n, "artifact", ""))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type runShowSnapshotOptions struct {
+	tests   bool
+	log     bool
+	changes bool
+	json    bool
+}
+
+func newRunShowSnapshotCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &runShowSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "show-snapshot <file>",
+		Short: "Show a run saved with run snapshot",
+		Long: `Show a run saved with 'teamcity run snapshot', formatted like the live
+commands: run view by default, or run tests, run log, or run changes with
+--tests, --log, or --changes. No server connection is needed.
+
+--json on its own prints the whole snapshot, including build problems and
+statistics; combined with --tests, --log, or --changes it prints what the
+live command prints with --json.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cobra.FixedCompletions([]string{"tcsnap"}, cobra.ShellCompDirectiveFilterFileExt),
+		Example: `  teamcity run show-snapshot run-12345.tcsnap
+  teamcity run show-snapshot run-12345.tcsnap --tests
+  teamcity run show-snapshot run-12345.tcsnap --log
+  teamcity run show-snapshot run-12345.tcsnap --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunShowSnapshot(f, args[0], opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.tests, "tests", false, "Show the run's tests")
+	cmd.Flags().BoolVar(&opts.log, "log", false, "Show the run's build log")
+	cmd.Flags().BoolVar(&opts.changes, "changes", false, "Show the run's VCS changes")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.MarkFlagsMutuallyExclusive("tests", "log", "changes")
+
+	return cmd
+}
+
+func runRunShowSnapshot(f *cmdutil.Factory, file string, opts *runShowSnapshotOptions) error {
+	p := f.Printer
+	snap, err := loadSnapshot(file)
+	if err != nil {
+		return err
+	}
+	runID := strconv.Itoa(snap.Build.ID)
+
+	switch {
+	case opts.tests:
+		_, err := showRunTests(f, snap, snap.Build, &runTestsOptions{json: opts.json})
+		return err
+	case opts.log:
+		return runLogFull(f, snap, runID, &runLogOptions{json: opts.json})
+	case opts.changes:
+		return showRunChanges(f, snap, runID, &runChangesOptions{json: opts.json, format: "text"})
+	case opts.json:
+		return p.PrintJSON(snap)
+	}
+
+	m := snap.Manifest
+	_, _ = fmt.Fprintf(p.Out, "%s\n\n", output.Faint(fmt.Sprintf("Snapshot taken %s from %s: %s",
+		output.RelativeTime(m.CreatedAt), cmp.Or(m.Server, "unknown server"), snapshotContents(snap))))
+	return showRunView(f, snap, runID, &cmdutil.ViewOptions{})
+}
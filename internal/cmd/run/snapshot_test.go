@@ -0,0 +1,141 @@
+package run_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+func TestRunSnapshotRoundTrip(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	content := &api.Content{Href: "/download"}
+	ts.Handle("GET /app/rest/builds/id:1/artifacts/children", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Artifacts{File: []api.Artifact{{Name: "build.jar", Size: 12, Content: content}, {Name: "logs"}}})
+	})
+	ts.Handle("GET /app/rest/builds/id:1/artifacts/children/logs", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Artifacts{File: []api.Artifact{{Name: "build.log", Size: 12, Content: content}, {Name: "test.log", Size: 12, Content: content}}})
+	})
+	file := filepath.Join(T.TempDir(), "run.tcsnap")
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "snapshot", testBuildID, "-o", file, "--log", "--artifact", "logs/*")
+	assert.Contains(T, got, "Saved run 1 to "+file)
+	assert.Contains(T, got, "details, 1 test, 1 problem, 1 change, 0 statistics, log, 2 artifacts")
+	assert.Equal(T, []string{"snapshot.json", "run.log", "artifacts/logs/build.log", "artifacts/logs/test.log"}, snapshotEntries(T, file))
+
+	modes := []struct {
+		live []string
+		flag []string
+	}{
+		{[]string{"tests", testBuildID}, []string{"--tests"}},
+		{[]string{"tests", testBuildID, "--json"}, []string{"--tests", "--json"}},
+		{[]string{"log", testBuildID}, []string{"--log"}},
+		{[]string{"log", testBuildID, "--json"}, []string{"--log", "--json"}},
+		{[]string{"changes", testBuildID}, []string{"--changes"}},
+		{[]string{"changes", testBuildID, "--json"}, []string{"--changes", "--json"}},
+	}
+	for _, m := range modes {
+		T.Run(strings.Join(m.flag, " "), func(T *testing.T) {
+			live := cmdtest.CaptureOutput(T, ts.Factory, append([]string{"run"}, m.live...)...)
+			snap := cmdtest.CaptureOutput(T, ts.Factory, append([]string{"run", "show-snapshot", file}, m.flag...)...)
+			assert.Equal(T, live, snap)
+		})
+	}
+
+	T.Run("view", func(T *testing.T) {
+		live := cmdtest.CaptureOutput(T, ts.Factory, "run", "view", testBuildID)
+		snap := cmdtest.CaptureOutput(T, ts.Factory, "run", "show-snapshot", file)
+		assert.Contains(T, snap, "Snapshot taken")
+		assert.True(T, strings.HasSuffix(snap, live), "snapshot view:\n%s\nlive view:\n%s", snap, live)
+	})
+
+	T.Run("json", func(T *testing.T) {
+		var snap struct {
+			Manifest struct {
+				Format    int  `json:"format"`
+				RunID     int  `json:"runId"`
+				HasLog    bool `json:"hasLog"`
+				Artifacts []struct {
+					Name string `json:"name"`
+				} `json:"artifacts"`
+			} `json:"manifest"`
+			Problems struct {
+				Count int `json:"count"`
+			} `json:"problems"`
+		}
+		require.NoError(T, json.Unmarshal([]byte(cmdtest.CaptureOutput(T, ts.Factory, "run", "show-snapshot", file, "--json")), &snap))
+		assert.Equal(T, 1, snap.Manifest.Format)
+		assert.Equal(T, 1, snap.Manifest.RunID)
+		assert.True(T, snap.Manifest.HasLog)
+		require.Len(T, snap.Manifest.Artifacts, 2)
+		assert.Equal(T, "logs/build.log", snap.Manifest.Artifacts[0].Name)
+		assert.Equal(T, 1, snap.Problems.Count)
+	})
+}
+
+func TestRunSnapshotWithoutLog(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	file := filepath.Join(T.TempDir(), "run.tcsnap")
+
+	cmdtest.RunCmdWithFactory(T, ts.Factory, "run", "snapshot", testBuildID, "-o", file)
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "the snapshot does not include the run log", "run", "show-snapshot", file, "--log")
+}
+
+func TestRunShowSnapshotRejects(T *testing.T) {
+	ts := cmdtest.SetupMockClient(T)
+	dir := T.TempDir()
+
+	notSnapshot := filepath.Join(dir, "notes.txt")
+	require.NoError(T, os.WriteFile(notSnapshot, []byte("not a snapshot"), 0644))
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "is not a run snapshot", "run", "show-snapshot", notSnapshot)
+
+	future := filepath.Join(dir, "future.tcsnap")
+	writeTestSnapshot(T, future, `{"manifest":{"format":99,"runId":1},"build":{"id":1}}`)
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "has snapshot format 99; this version of teamcity reads up to format 1", "run", "show-snapshot", future)
+}
+
+// writeTestSnapshot writes a snapshot file holding only the given snapshot.json.
+func writeTestSnapshot(t *testing.T, file, data string) {
+	t.Helper()
+	fh, err := os.Create(file)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(fh)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "snapshot.json", Mode: 0644, Size: int64(len(data))}))
+	_, err = tw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, fh.Close())
+}
+
+// snapshotEntries lists the entries of a snapshot file in order.
+func snapshotEntries(t *testing.T, file string) []string {
+	t.Helper()
+	fh, err := os.Open(file)
+	require.NoError(t, err)
+	defer func() { _ = fh.Close() }()
+	gz, err := gzip.NewReader(fh)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,37 +17,50 @@ import (
 	"github.com/JetBrains/teamcity-cli/internal/completion"
 	"github.com/JetBrains/teamcity-cli/internal/git"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
 	"github.com/spf13/cobra"
 )
 
 // watchFlags holds the shared watch-related flags used by run start, restart, and watch.
 type watchFlags struct {
-	watch    bool
-	interval int
-	timeout  time.Duration
+	watch      bool
+	waitQueue  bool
+	followDeps bool
+	interval   int
+	timeout    time.Duration
+	hooks      watchHooks
 }
 
 // addToCmd registers the shared watch flags on a cobra command.
 func (w *watchFlags) addToCmd(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&w.watch, "watch", false, "Watch until completion")
+	cmd.Flags().BoolVar(&w.waitQueue, "wait-queue", false, "Wait until the run leaves the queue, showing why it waits")
 	cmd.Flags().IntVarP(&w.interval, "interval", "i", 5, "Refresh interval in seconds when watching")
 	cmd.Flags().DurationVar(&w.timeout, "timeout", 0, "Timeout when watching (e.g., 30m, 1h); implies --watch")
+	cmd.Flags().BoolVar(&w.followDeps, "follow-deps", false, "Watch the run's whole snapshot-dependency chain until every run finishes; implies --watch")
+	w.hooks.addToCmd(cmd)
+	// The chain's root stays queued until its dependencies finish, so waiting for it to leave the queue first would
+	// hide the chain.
+	cmd.MarkFlagsMutuallyExclusive("wait-queue", "follow-deps")
 }
 
-// resolve ensures timeout implies watch and returns the runWatchOptions.
-func (w *watchFlags) resolve() {
-	if w.timeout > 0 {
+// resolve ensures timeout, --follow-deps, hooks and notifications imply watch, and checks the hook flags.
+func (w *watchFlags) resolve() error {
+	if w.timeout > 0 || w.followDeps || w.hooks.active() {
 		w.watch = true
 	}
+	return w.hooks.validate()
 }
 
 // watchOpts builds runWatchOptions from the shared flags with additional overrides.
 func (w *watchFlags) watchOpts(logs, json bool) *runWatchOptions {
 	return &runWatchOptions{
-		interval: w.interval,
-		timeout:  w.timeout,
-		logs:     logs,
-		json:     json,
+		interval:   w.interval,
+		timeout:    w.timeout,
+		logs:       logs,
+		json:       json,
+		followDeps: w.followDeps,
+		hooks:      w.hooks,
 	}
 }
 
@@ -92,10 +106,7 @@ func reuseDepRow(d reuseDep) (icon, summary string) {
 		return output.Faint("?"), output.Yellow(fmt.Sprintf("(lookup failed: %v)", d.err))
 	}
 	b := d.build
-	var btName string
-	if b.BuildType != nil {
-		btName = b.BuildType.Name
-	}
+	btName := cmdutil.JobName(b.BuildType, "")
 	parts := make([]string, 0, 3)
 	if b.Number != "" {
 		parts = append(parts, output.Cyan("#"+b.Number))
@@ -107,6 +118,106 @@ func reuseDepRow(d reuseDep) (icon, summary string) {
 	return output.StatusIcon(b.Status, b.State, b.StatusText), strings.Join(parts, "  ")
 }
 
+// parseArtifactFrom parses --artifact-from values of the form <job-id>:<run-id>.
+func parseArtifactFrom(values []string) ([]api.ArtifactBuild, error) {
+	pins := make([]api.ArtifactBuild, 0, len(values))
+	seen := map[string]bool{}
+	for _, v := range values {
+		jobID, runID, ok := strings.Cut(v, ":")
+		id, err := strconv.Atoi(runID)
+		if !ok || jobID == "" || err != nil || id <= 0 {
+			return nil, api.Validation(
+				fmt.Sprintf("invalid --artifact-from %q", v),
+				"Use <job-id>:<run-id>, for example --artifact-from Falcon_Lib:12345",
+			)
+		}
+		if seen[jobID] {
+			return nil, api.Validation(
+				fmt.Sprintf("--artifact-from given more than once for %s", jobID),
+				"Pin each upstream job to a single run",
+			)
+		}
+		seen[jobID] = true
+		pins = append(pins, api.ArtifactBuild{BuildTypeID: jobID, BuildID: id})
+	}
+	return pins, nil
+}
+
+// resolveArtifactFrom checks that each pinned run exists and belongs to its stated job, and that jobID has an artifact dependency on that job; it returns the pinned runs in order, and jobID's artifact dependencies for RunBuild to pin.
+func resolveArtifactFrom(ctx context.Context, client api.ClientInterface, jobID string, pins []api.ArtifactBuild) ([]*api.Build, *api.ArtifactDependencyList, error) {
+	if len(pins) == 0 {
+		return nil, nil, nil
+	}
+	deps, err := client.GetArtifactDependencies(jobID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get artifact dependencies of %s: %w", jobID, err)
+	}
+	var sources []string
+	for _, dep := range deps.ArtifactDependency {
+		if dep.SourceBuildType != nil && !slices.Contains(sources, dep.SourceBuildType.ID) {
+			sources = append(sources, dep.SourceBuildType.ID)
+		}
+	}
+
+	builds := make([]*api.Build, len(pins))
+	for i, pin := range pins {
+		if !slices.Contains(sources, pin.BuildTypeID) {
+			tip := fmt.Sprintf("%s has no artifact dependencies", jobID)
+			if len(sources) > 0 {
+				tip = fmt.Sprintf("%s takes artifacts from: %s", jobID, strings.Join(sources, ", "))
+			}
+			return nil, nil, api.Validation(fmt.Sprintf("job %s has no artifact dependency on %s", jobID, pin.BuildTypeID), tip)
+		}
+		b, err := client.GetBuild(ctx, strconv.Itoa(pin.BuildID))
+		if err != nil {
+			if _, ok := errors.AsType[*api.NotFoundError](err); ok {
+				return nil, nil, api.Validation(
+					fmt.Sprintf("run %d not found", pin.BuildID),
+					fmt.Sprintf("List runs of %s with: teamcity run list --job %s", pin.BuildTypeID, pin.BuildTypeID),
+				)
+			}
+			return nil, nil, err
+		}
+		if b.BuildTypeID != pin.BuildTypeID {
+			return nil, nil, api.Validation(
+				fmt.Sprintf("run %d belongs to %s, not %s", pin.BuildID, b.BuildTypeID, pin.BuildTypeID),
+				fmt.Sprintf("Use --artifact-from %s:%d, or pick a run of %s", b.BuildTypeID, pin.BuildID, pin.BuildTypeID),
+			)
+		}
+		builds[i] = b
+	}
+	return builds, deps, nil
+}
+
+// artifactFromJSON is the --dry-run --json view of one --artifact-from pin.
+type artifactFromJSON struct {
+	Job    string `json:"job"`
+	RunID  int    `json:"run_id"`
+	Number string `json:"number,omitempty"`
+}
+
+func artifactFromView(builds []*api.Build) []artifactFromJSON {
+	out := make([]artifactFromJSON, len(builds))
+	for i, b := range builds {
+		out[i] = artifactFromJSON{Job: b.BuildTypeID, RunID: b.ID, Number: b.Number}
+	}
+	return out
+}
+
+func printArtifactFrom(p *output.Printer, builds []*api.Build) {
+	if len(builds) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(p.Out, "  Artifacts from:")
+	for _, b := range builds {
+		ref := strconv.Itoa(b.ID)
+		if b.Number != "" {
+			ref = fmt.Sprintf("%d  %s", b.ID, output.Cyan("#"+b.Number))
+		}
+		_, _ = fmt.Fprintf(p.Out, "    %s  %s\n", b.BuildTypeID, ref)
+	}
+}
+
 func printQueuedRun(p *output.Printer, build *api.Build, context string) {
 	ref := fmt.Sprintf("%d  #%s", build.ID, build.Number)
 	if build.Number == "" {
@@ -119,6 +230,23 @@ func afterQueue(f *cmdutil.Factory, build *api.Build, web bool, wf *watchFlags)
 	if web {
 		cmdutil.OpenURLOrWarn(f.Printer, build.WebURL)
 	}
+	if wf.waitQueue {
+		_, _ = fmt.Fprintln(f.Printer.Out)
+		client, err := f.Client()
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		if build, err = waitForQueue(f, client, build, wf, false); err != nil || build.State == "queued" {
+			return err
+		}
+		if build.State == "finished" {
+			return cmdutil.BuildResultBrief(f.Printer, build)
+		}
+		if wf.timeout > 0 {
+			wf.timeout = max(wf.timeout-time.Since(start), time.Second)
+		}
+	}
 	if wf.watch {
 		_, _ = fmt.Fprintln(f.Printer.Out)
 		return doRunWatch(f, strconv.Itoa(build.ID), wf.watchOpts(true, false))
@@ -172,8 +300,14 @@ type runStartOptions struct {
 	agent             int
 	tags              []string
 	reuseDeps         []int
+	artifactFrom      []string
 	settings          string
+	repo              string
+	allMatching       bool
+	file              string
+	emitFile          string
 	watchFlags
+	copy   cmdutil.CopyOptions
 	web    bool
 	dryRun bool
 	json   bool
@@ -187,10 +321,38 @@ func newRunStartCmd(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:               "start [job-id]",
-		Short:             "Start a new run",
+		Use:   "start [job-id]",
+		Short: "Start a new run",
+		Long: `Start a new run of a job.
+
+Instead of a job ID, --repo takes a git repository URL and starts the job
+attached to a VCS root fetching it; ssh and https forms of the same
+repository match each other. When several jobs use the repository, pick one
+interactively, or pass --all-matching to start them all.
+
+--wait-queue stays with the run while it is queued, showing its position,
+why it waits and when the server expects it to start, and who can approve
+it when it needs approval. It returns once the run starts, or goes on
+watching it with --watch; --timeout covers the wait as well.
+
+--follow-deps watches the new run together with every run of its snapshot
+dependency chain and exits once all of them have finished, non-zero if any
+failed.
+
+--local-changes starts a personal build with changes that are not pushed
+yet: git takes every change in the working tree, staged only what is in the
+index (git diff --cached), and either can be followed by a colon and
+comma-separated paths to take only those files (git:src/app,go.mod). A
+patch with no changes is refused, and binary files in it are reported, as
+TeamCity can't apply them from an uploaded patch.
+
+-f reads the job and its settings from a YAML file whose keys are named
+after these flags (params, system, env and tags for -P, -S, -E and -t).
+Flags given alongside the file override its keys; for params, system and
+env, only the names they set. Write such a file from a working command line
+with --dry-run --emit-file.`,
 		Args:              cobra.MaximumNArgs(1),
-		ValidArgsFunction: completion.LinkedJobs(),
+		ValidArgsFunction: completion.Jobs(),
 		Example: `  teamcity run start Falcon_Build
   teamcity run start                              # uses linked default (see 'teamcity link')
   teamcity run start Falcon_Build --branch feature/test
@@ -199,13 +361,48 @@ func newRunStartCmd(f *cmdutil.Factory) *cobra.Command {
   teamcity run start Falcon_Build --comment "Release build" --tag release --tag v1.0
   teamcity run start Falcon_Build --clean --rebuild-deps --top
   teamcity run start Falcon_Build --reuse-deps 6946,6917  # reuse existing as snapshot dependencies
+  teamcity run start Falcon_Build --artifact-from Falcon_Lib:12345  # take Falcon_Lib's artifacts from run 12345
   teamcity run start Falcon_Build --local-changes # personal build with uncommitted Git changes
   teamcity run start Falcon_Build --local-changes changes.patch  # from file
+  teamcity run start Falcon_Build --local-changes=staged         # only what is staged
+  teamcity run start Falcon_Build --local-changes=git:src/app,go.mod  # only these paths
   teamcity run start Falcon_Build --revision abc123def --branch main
   teamcity run start Falcon_Build --revision @head --branch @this
   teamcity run start Falcon_Build --settings vcs    # load versioned settings from VCS
-  teamcity run start Falcon_Build --dry-run`,
+  teamcity run start Falcon_Build --dry-run
+  teamcity run start Falcon_Build --wait-queue    # wait until an agent picks it up
+  teamcity run start Falcon_Build --wait-queue --watch
+  teamcity run start Falcon_Build --follow-deps   # watch the whole build chain
+  teamcity run start Falcon_Build --copy          # copy the run's URL to the clipboard
+  teamcity run start -f release.yml -P version=2.1
+  teamcity run start Falcon_Build -P version=2.0 --tag release --dry-run --emit-file release.yml
+  teamcity run start --repo git@github.com:acme/falcon.git --branch main
+  teamcity run start --repo https://github.com/acme/falcon --all-matching`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.copy.Validate(); err != nil {
+				return err
+			}
+			if opts.emitFile != "" && !opts.dryRun && !f.IsDryRun() {
+				return api.Validation("--emit-file requires --dry-run", "Add --dry-run to write the file without starting the run")
+			}
+			if opts.file != "" {
+				fileJob, err := loadRunFile(f, cmd.Flags(), opts.file, opts)
+				if err != nil {
+					return err
+				}
+				if len(args) == 0 && fileJob != "" {
+					args = []string{fileJob}
+				}
+			}
+			if opts.repo != "" {
+				if len(args) > 0 {
+					return api.MutuallyExclusive("job-id", "repo")
+				}
+				return runRunStartRepo(f, opts)
+			}
+			if opts.allMatching {
+				return api.Validation("--all-matching requires --repo", "e.g. teamcity run start --repo git@github.com:org/app.git --all-matching")
+			}
 			jobID, _, err := cmdutil.ResolveOwnerID("job", args, 0, f.ResolveDefaultJob)
 			if err != nil {
 				return err
@@ -222,25 +419,47 @@ func newRunStartCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.comment, "comment", "m", "", "Comment to attach")
 	cmd.Flags().StringSliceVarP(&opts.tags, "tag", "t", nil, "Tags (can be repeated)")
 	cmd.Flags().BoolVar(&opts.personal, "personal", false, "Personal build")
-	localChangesFlag := cmd.Flags().VarPF(&localChangesValue{val: &opts.localChanges}, "local-changes", "l", "Include local changes (git, -, or path; default: git)")
+	localChangesFlag := cmd.Flags().VarPF(&localChangesValue{val: &opts.localChanges}, "local-changes", "l", "Include local changes (git, staged, git:<paths>, staged:<paths>, -, or path; default: git)")
 	localChangesFlag.NoOptDefVal = "git"
 	cmd.Flags().BoolVar(&opts.noPush, "no-push", false, "Skip auto-push of branch to remote")
 	cmd.Flags().BoolVar(&opts.cleanSources, "clean", false, "Clean sources before start")
 	cmd.Flags().BoolVar(&opts.rebuildDeps, "rebuild-deps", false, "Rebuild all dependencies")
 	cmd.Flags().BoolVar(&opts.rebuildFailedDeps, "rebuild-failed-deps", false, "Rebuild failed/incomplete dependencies")
 	cmd.Flags().IntSliceVar(&opts.reuseDeps, "reuse-deps", nil, "Reuse existing as snapshot dependencies (IDs, comma-separated or repeated)")
+	cmd.Flags().StringArrayVar(&opts.artifactFrom, "artifact-from", nil, "Take artifact dependency from a specific run, as <job-id>:<run-id> (repeatable)")
 	cmd.Flags().BoolVar(&opts.queueAtTop, "top", false, "Add to top of queue")
 	cmd.Flags().IntVar(&opts.agent, "agent", 0, "Use specific agent (by ID)")
 	cmd.Flags().StringVar(&opts.settings, "settings", "", "Settings source: 'vcs' or 'current' (default: job's configured mode)")
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Start the job attached to this git repository URL instead of a job ID")
+	cmd.Flags().BoolVar(&opts.allMatching, "all-matching", false, "With --repo, start every job attached to the repository")
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "Read the job and run settings from a YAML file ('-' for stdin); flags override it")
+	cmd.Flags().StringVar(&opts.emitFile, "emit-file", "", "With --dry-run, write the job and run settings to a YAML file for -f ('-' for stdout)")
 	opts.addToCmd(cmd)
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open in browser")
+	cmdutil.AddCopyFlag(cmd, &opts.copy)
 	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview without triggering")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
 
-	_ = cmd.RegisterFlagCompletionFunc("branch", completion.GitBranches())
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "watch")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "wait-queue")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "timeout")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "follow-deps")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "on-success")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "on-failure")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "local-changes")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "web")
+	cmd.MarkFlagsMutuallyExclusive("all-matching", "copy")
+	cmd.MarkFlagsMutuallyExclusive("file", "repo")
+	cmd.MarkFlagsMutuallyExclusive("emit-file", "repo")
+	// A run file has no place for a diff, and without it a personal run would come back as an ordinary one.
+	cmd.MarkFlagsMutuallyExclusive("emit-file", "local-changes")
+	_ = cmd.MarkFlagFilename("file", "yml", "yaml")
+	_ = cmd.MarkFlagFilename("emit-file", "yml", "yaml")
+
+	_ = cmd.RegisterFlagCompletionFunc("branch", completion.Branches())
 	_ = cmd.RegisterFlagCompletionFunc("revision", completion.AtHead())
 	_ = cmd.RegisterFlagCompletionFunc("local-changes", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
-		return []string{"git", "-"}, cobra.ShellCompDirectiveDefault
+		return []string{"git", "staged", "-"}, cobra.ShellCompDirectiveDefault
 	})
 	_ = cmd.RegisterFlagCompletionFunc("settings", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
 		return []string{"vcs", "current"}, cobra.ShellCompDirectiveNoFileComp
@@ -249,20 +468,37 @@ func newRunStartCmd(f *cmdutil.Factory) *cobra.Command {
 	return cmd
 }
 
-func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error {
-	p := f.Printer
-	opts.resolve()
+// prepare resolves the flag shorthands in place and returns the settings override and artifact pins for the runs to queue.
+func (opts *runStartOptions) prepare(f *cmdutil.Factory) (*bool, []api.ArtifactBuild, error) {
+	if err := opts.resolve(); err != nil {
+		return nil, nil, err
+	}
+	opts.dryRun = opts.dryRun || f.IsDryRun()
 	branch, err := resolveBranchFlag(opts.branch)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	opts.branch = branch
 	revision, err := resolveRevisionFlag(opts.revision)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	opts.revision = revision
 	freezeSettings, err := resolveSettingsFlag(opts.settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	artifactPins, err := parseArtifactFrom(opts.artifactFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+	return freezeSettings, artifactPins, nil
+}
+
+func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error {
+	p := f.Printer
+	unresolved := *opts
+	freezeSettings, artifactPins, err := opts.prepare(f)
 	if err != nil {
 		return err
 	}
@@ -277,6 +513,19 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 				"Check the job ID with: teamcity job list",
 			)
 		}
+		artifactBuilds, _, err := resolveArtifactFrom(f.Context(), client, jobID, artifactPins)
+		if err != nil {
+			return err
+		}
+		if opts.emitFile != "" {
+			if err := writeRunFile(opts.emitFile, p.Out, jobID, &unresolved); err != nil {
+				return err
+			}
+			if opts.emitFile == "-" {
+				return nil
+			}
+			p.Success("Wrote run definition to %s", opts.emitFile)
+		}
 		f.Analytics.Track(analytics.GroupBuild, analytics.EventStarted, map[string]any{
 			"is_personal":       opts.personal,
 			"has_local_changes": opts.localChanges != "",
@@ -289,24 +538,25 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 
 		if opts.json {
 			return p.PrintJSON(struct {
-				DryRun            bool              `json:"dry_run"`
-				Job               string            `json:"job"`
-				Branch            string            `json:"branch,omitempty"`
-				Revision          string            `json:"revision,omitempty"`
-				Personal          bool              `json:"personal"`
-				LocalChanges      string            `json:"local_changes,omitempty"`
-				Params            map[string]string `json:"params,omitempty"`
-				SystemProps       map[string]string `json:"system_properties,omitempty"`
-				EnvVars           map[string]string `json:"environment_variables,omitempty"`
-				Comment           string            `json:"comment,omitempty"`
-				Tags              []string          `json:"tags,omitempty"`
-				CleanSources      bool              `json:"clean_sources,omitempty"`
-				RebuildDeps       bool              `json:"rebuild_deps,omitempty"`
-				RebuildFailedDeps bool              `json:"rebuild_failed_deps,omitempty"`
-				QueueAtTop        bool              `json:"queue_at_top,omitempty"`
-				Agent             int               `json:"agent_id,omitempty"`
-				ReuseDeps         []int             `json:"reuse_deps,omitempty"`
-				Settings          string            `json:"settings,omitempty"`
+				DryRun            bool               `json:"dry_run"`
+				Job               string             `json:"job"`
+				Branch            string             `json:"branch,omitempty"`
+				Revision          string             `json:"revision,omitempty"`
+				Personal          bool               `json:"personal"`
+				LocalChanges      string             `json:"local_changes,omitempty"`
+				Params            map[string]string  `json:"params,omitempty"`
+				SystemProps       map[string]string  `json:"system_properties,omitempty"`
+				EnvVars           map[string]string  `json:"environment_variables,omitempty"`
+				Comment           string             `json:"comment,omitempty"`
+				Tags              []string           `json:"tags,omitempty"`
+				CleanSources      bool               `json:"clean_sources,omitempty"`
+				RebuildDeps       bool               `json:"rebuild_deps,omitempty"`
+				RebuildFailedDeps bool               `json:"rebuild_failed_deps,omitempty"`
+				QueueAtTop        bool               `json:"queue_at_top,omitempty"`
+				Agent             int                `json:"agent_id,omitempty"`
+				ReuseDeps         []int              `json:"reuse_deps,omitempty"`
+				ArtifactFrom      []artifactFromJSON `json:"artifact_from,omitempty"`
+				Settings          string             `json:"settings,omitempty"`
 			}{
 				DryRun:            true,
 				Job:               jobID,
@@ -325,6 +575,7 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 				QueueAtTop:        opts.queueAtTop,
 				Agent:             opts.agent,
 				ReuseDeps:         opts.reuseDeps,
+				ArtifactFrom:      artifactFromView(artifactBuilds),
 				Settings:          opts.settings,
 			})
 		}
@@ -378,6 +629,7 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		if len(opts.reuseDeps) > 0 {
 			printReuseDeps(p, fetchReuseDeps(f.Context(), client, opts.reuseDeps))
 		}
+		printArtifactFrom(p, artifactBuilds)
 		if opts.queueAtTop {
 			_, _ = fmt.Fprintln(p.Out, "  Queue at top: yes")
 		}
@@ -390,6 +642,84 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		return nil
 	}
 
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+	build, err := queueRun(f, client, jobID, opts, freezeSettings, artifactPins)
+	if err != nil {
+		return err
+	}
+	opts.copy.Copy(p, build.WebURL, strconv.Itoa(build.ID))
+
+	if opts.json {
+		if opts.waitQueue && build.State == "queued" {
+			if build, err = waitForQueue(f, client, build, &opts.watchFlags, true); err != nil || build.State == "queued" {
+				return err
+			}
+			if build.State == "finished" {
+				if err := p.PrintJSON(build); err != nil {
+					return err
+				}
+				return cmdutil.BuildExitError(build)
+			}
+		}
+		if opts.watch {
+			return doRunWatch(f, strconv.Itoa(build.ID), opts.watchOpts(false, true))
+		}
+		return p.PrintJSON(build)
+	}
+
+	reused := build.State == "finished"
+	if reused {
+		ref := strconv.Itoa(build.ID)
+		if build.Number != "" {
+			ref = fmt.Sprintf("%d  #%s", build.ID, build.Number)
+		}
+		p.Info("Reused existing #%s for %s (optimization)", ref, jobID)
+	} else {
+		printQueuedRun(p, build, jobID)
+	}
+
+	if opts.branch != "" {
+		p.Info("  Branch: %s", opts.branch)
+	}
+	if opts.comment != "" {
+		p.Info("  Comment: %s", opts.comment)
+	}
+	if len(opts.tags) > 0 {
+		p.Info("  Tags: %s", strings.Join(opts.tags, ", "))
+	}
+	if l := settingsLabel(opts.settings); l != "" {
+		p.Info("  Settings: %s", l)
+	}
+	if len(opts.reuseDeps) > 0 {
+		printReuseDeps(p, fetchReuseDeps(f.Context(), client, opts.reuseDeps))
+	}
+	p.Info("  URL: %s", build.WebURL)
+	if opts.agent > 0 {
+		_, _ = fmt.Fprintf(p.Out, "  %s teamcity agent term %d\n", output.Faint("Agent terminal:"), opts.agent)
+	}
+	if build.WaitReason != "" {
+		p.Info("  Wait reason: %s", build.WaitReason)
+	}
+	if !reused && !opts.watch {
+		_, _ = fmt.Fprintf(p.Out, "  %s teamcity run log -f %d\n", output.Faint("Follow logs:"), build.ID)
+	}
+
+	if reused {
+		if opts.web {
+			cmdutil.OpenURLOrWarn(f.Printer, build.WebURL)
+		}
+		return nil
+	}
+	return afterQueue(f, build, opts.web, &opts.watchFlags)
+}
+
+// queueRun pushes and uploads local changes when asked, then queues a run of jobID with opts.
+func queueRun(f *cmdutil.Factory, client api.ClientInterface, jobID string, opts *runStartOptions, freezeSettings *bool, artifactPins []api.ArtifactBuild) (*api.Build, error) {
+	p := f.Printer
+
 	// Progress lines write to stdout; suppress them in --json mode so they don't corrupt the document.
 	info := func(format string, a ...any) {
 		if !opts.json {
@@ -402,16 +732,22 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		}
 	}
 
+	// Validate pins before pushing or uploading anything.
+	_, artifactDeps, err := resolveArtifactFrom(f.Context(), client, jobID, artifactPins)
+	if err != nil {
+		return nil, err
+	}
+
 	if opts.localChanges != "" && opts.branch == "" {
 		if !isGitRepoFn() {
-			return api.Validation(
+			return nil, api.Validation(
 				"not a git repository",
 				"Run this command from within a git repository, or specify --branch explicitly",
 			)
 		}
 		branch, err := currentBranchFn()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		opts.branch = branch
 		info("Using current branch: %s", branch)
@@ -421,22 +757,21 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		if !git.BranchExistsOnRemote(opts.branch) {
 			info("Pushing branch to remote...")
 			if err := pushBranch(opts.branch); err != nil {
-				return err
+				return nil, err
 			}
 			success("Branch pushed to remote")
 		}
 	}
 
-	client, err := f.Client()
-	if err != nil {
-		return err
-	}
-
 	var personalChangeID string
 	if opts.localChanges != "" {
 		patch, err := loadLocalChanges(opts.localChanges, f.IOStreams.In)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if binary := git.BinaryFiles(patch); len(binary) > 0 {
+			p.Warn("The patch leaves out the contents of %s; TeamCity can't apply binary changes from an uploaded patch: %s",
+				english.Plural(len(binary), "binary file", ""), strings.Join(binary, ", "))
 		}
 
 		info("Uploading local changes...")
@@ -444,7 +779,7 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 
 		changeID, err := client.UploadDiffChanges(patch, description)
 		if err != nil {
-			return fmt.Errorf("failed to upload changes: %w", err)
+			return nil, fmt.Errorf("failed to upload changes: %w", err)
 		}
 		personalChangeID = changeID
 		success("Uploaded changes (ID: %s)", changeID)
@@ -468,10 +803,12 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		PersonalChangeID:          personalChangeID,
 		Revision:                  opts.revision,
 		SnapshotDependencies:      opts.reuseDeps,
+		ArtifactBuilds:            artifactPins,
+		ArtifactDependencies:      artifactDeps,
 		FreezeSettings:            freezeSettings,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	f.Analytics.Track(analytics.GroupBuild, analytics.EventStarted, map[string]any{
@@ -483,56 +820,5 @@ func runRunStart(f *cmdutil.Factory, jobID string, opts *runStartOptions) error
 		"is_watched":        opts.watch,
 		"is_dry_run":        false,
 	})
-
-	if opts.json {
-		if opts.watch {
-			return doRunWatch(f, strconv.Itoa(build.ID), opts.watchOpts(false, true))
-		}
-		return p.PrintJSON(build)
-	}
-
-	reused := build.State == "finished"
-	if reused {
-		ref := strconv.Itoa(build.ID)
-		if build.Number != "" {
-			ref = fmt.Sprintf("%d  #%s", build.ID, build.Number)
-		}
-		p.Info("Reused existing #%s for %s (optimization)", ref, jobID)
-	} else {
-		printQueuedRun(p, build, jobID)
-	}
-
-	if opts.branch != "" {
-		p.Info("  Branch: %s", opts.branch)
-	}
-	if opts.comment != "" {
-		p.Info("  Comment: %s", opts.comment)
-	}
-	if len(opts.tags) > 0 {
-		p.Info("  Tags: %s", strings.Join(opts.tags, ", "))
-	}
-	if l := settingsLabel(opts.settings); l != "" {
-		p.Info("  Settings: %s", l)
-	}
-	if len(opts.reuseDeps) > 0 {
-		printReuseDeps(p, fetchReuseDeps(f.Context(), client, opts.reuseDeps))
-	}
-	p.Info("  URL: %s", build.WebURL)
-	if opts.agent > 0 {
-		_, _ = fmt.Fprintf(p.Out, "  %s teamcity agent term %d\n", output.Faint("Agent terminal:"), opts.agent)
-	}
-	if build.WaitReason != "" {
-		p.Info("  Wait reason: %s", build.WaitReason)
-	}
-	if !reused && !opts.watch {
-		_, _ = fmt.Fprintf(p.Out, "  %s teamcity run log -f %d\n", output.Faint("Follow logs:"), build.ID)
-	}
-
-	if reused {
-		if opts.web {
-			cmdutil.OpenURLOrWarn(f.Printer, build.WebURL)
-		}
-		return nil
-	}
-	return afterQueue(f, build, opts.web, &opts.watchFlags)
+	return build, nil
 }
@@ -0,0 +1,237 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// runFile is a run definition for run start -f: the run start flags, written down. Each key is named after the
+// flag it stands in for, and a flag given on the command line overrides it.
+type runFile struct {
+	Job               string            `yaml:"job,omitempty"`
+	Branch            string            `yaml:"branch,omitempty"`
+	Revision          string            `yaml:"revision,omitempty"`
+	Params            map[string]string `yaml:"params,omitempty"`
+	System            map[string]string `yaml:"system,omitempty"`
+	Env               map[string]string `yaml:"env,omitempty"`
+	Comment           string            `yaml:"comment,omitempty"`
+	Tags              []string          `yaml:"tags,omitempty"`
+	Personal          bool              `yaml:"personal,omitempty"`
+	Clean             bool              `yaml:"clean,omitempty"`
+	RebuildDeps       bool              `yaml:"rebuild-deps,omitempty"`
+	RebuildFailedDeps bool              `yaml:"rebuild-failed-deps,omitempty"`
+	Top               bool              `yaml:"top,omitempty"`
+	Agent             int               `yaml:"agent,omitempty"`
+	ReuseDeps         []int             `yaml:"reuse-deps,omitempty"`
+	ArtifactFrom      []string          `yaml:"artifact-from,omitempty"`
+	Settings          string            `yaml:"settings,omitempty"`
+}
+
+// runFileField is one key of a run file: where it decodes to, what it expects, and the flag that overrides it.
+type runFileField struct {
+	dst  any
+	want string
+	flag string
+}
+
+func (rf *runFile) fields() map[string]runFileField {
+	return map[string]runFileField{
+		"job":                 {&rf.Job, "a job ID", ""},
+		"branch":              {&rf.Branch, "a branch name", "branch"},
+		"revision":            {&rf.Revision, "a commit SHA", "revision"},
+		"params":              {&rf.Params, "a map of name: value", "param"},
+		"system":              {&rf.System, "a map of name: value", "system"},
+		"env":                 {&rf.Env, "a map of name: value", "env"},
+		"comment":             {&rf.Comment, "text", "comment"},
+		"tags":                {&rf.Tags, "a list of tags", "tag"},
+		"personal":            {&rf.Personal, "true or false", "personal"},
+		"clean":               {&rf.Clean, "true or false", "clean"},
+		"rebuild-deps":        {&rf.RebuildDeps, "true or false", "rebuild-deps"},
+		"rebuild-failed-deps": {&rf.RebuildFailedDeps, "true or false", "rebuild-failed-deps"},
+		"top":                 {&rf.Top, "true or false", "top"},
+		"agent":               {&rf.Agent, "an agent ID", "agent"},
+		"reuse-deps":          {&rf.ReuseDeps, "a list of run IDs", "reuse-deps"},
+		"artifact-from":       {&rf.ArtifactFrom, "a list of <job-id>:<run-id>", "artifact-from"},
+		"settings":            {&rf.Settings, "vcs or current", "settings"},
+	}
+}
+
+// readRunFile reads the run definition at path ("-" for in). Errors name the file, line, and key at fault.
+func readRunFile(path string, in io.Reader) (*runFile, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, api.Validation(fmt.Sprintf("%s: %s", path, strings.TrimPrefix(err.Error(), "yaml: ")), "Check the file is valid YAML")
+	}
+	rf := &runFile{}
+	if len(doc.Content) == 0 {
+		return rf, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, api.Validation(fmt.Sprintf("%s:%d: expected a map of run settings", path, root.Line), runFileTip())
+	}
+	fields := rf.fields()
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		field, ok := fields[key.Value]
+		if !ok {
+			return nil, api.Validation(fmt.Sprintf("%s:%d: unknown key %q", path, key.Line, key.Value), runFileTip())
+		}
+		if seen[key.Value] {
+			return nil, api.Validation(fmt.Sprintf("%s:%d: %s is set twice", path, key.Line, key.Value), "Keep one of them")
+		}
+		seen[key.Value] = true
+		if err := value.Decode(field.dst); err != nil {
+			return nil, api.Validation(fmt.Sprintf("%s:%d: %s must be %s", path, value.Line, key.Value, field.want), runFileTip())
+		}
+		var invalid error
+		switch key.Value {
+		case "settings":
+			_, invalid = resolveSettingsFlag(rf.Settings)
+		case "artifact-from":
+			_, invalid = parseArtifactFrom(rf.ArtifactFrom)
+		}
+		if invalid != nil {
+			return nil, api.Validation(fmt.Sprintf("%s:%d: %s: %v", path, value.Line, key.Value, invalid), runFileTip())
+		}
+	}
+	return rf, nil
+}
+
+func runFileTip() string {
+	keys := slices.Sorted(maps.Keys((&runFile{}).fields()))
+	return "Keys are named after the run start flags: " + strings.Join(keys, ", ")
+}
+
+// apply copies the file's settings into opts, except those whose flag was given: a flag replaces the key, and for
+// params, system and env, a flag replaces just the names it sets.
+func (rf *runFile) apply(flags *pflag.FlagSet, opts *runStartOptions) {
+	fromFile := func(flag string) bool { return !flags.Changed(flag) }
+	if fromFile("branch") {
+		opts.branch = rf.Branch
+	}
+	if fromFile("revision") {
+		opts.revision = rf.Revision
+	}
+	opts.params = mergeRunFileMap(rf.Params, opts.params)
+	opts.systemProps = mergeRunFileMap(rf.System, opts.systemProps)
+	opts.envVars = mergeRunFileMap(rf.Env, opts.envVars)
+	if fromFile("comment") {
+		opts.comment = rf.Comment
+	}
+	if fromFile("tag") {
+		opts.tags = rf.Tags
+	}
+	if fromFile("personal") {
+		opts.personal = rf.Personal
+	}
+	if fromFile("clean") {
+		opts.cleanSources = rf.Clean
+	}
+	if fromFile("rebuild-deps") {
+		opts.rebuildDeps = rf.RebuildDeps
+	}
+	if fromFile("rebuild-failed-deps") {
+		opts.rebuildFailedDeps = rf.RebuildFailedDeps
+	}
+	if fromFile("top") {
+		opts.queueAtTop = rf.Top
+	}
+	if fromFile("agent") {
+		opts.agent = rf.Agent
+	}
+	if fromFile("reuse-deps") {
+		opts.reuseDeps = rf.ReuseDeps
+	}
+	if fromFile("artifact-from") {
+		opts.artifactFrom = rf.ArtifactFrom
+	}
+	if fromFile("settings") {
+		opts.settings = rf.Settings
+	}
+}
+
+// mergeRunFileMap returns fromFile with fromFlags on top.
+func mergeRunFileMap(fromFile, fromFlags map[string]string) map[string]string {
+	if len(fromFile) == 0 {
+		return fromFlags
+	}
+	merged := maps.Clone(fromFile)
+	maps.Copy(merged, fromFlags)
+	return merged
+}
+
+// writeRunFile writes the run definition of jobID and opts to path ("-" for out), before shorthands such as
+// @this are resolved, so the file behaves the same wherever it is used.
+func writeRunFile(path string, out io.Writer, jobID string, opts *runStartOptions) error {
+	rf := runFile{
+		Job:               jobID,
+		Branch:            opts.branch,
+		Revision:          opts.revision,
+		Params:            opts.params,
+		System:            opts.systemProps,
+		Env:               opts.envVars,
+		Comment:           opts.comment,
+		Tags:              opts.tags,
+		Personal:          opts.personal,
+		Clean:             opts.cleanSources,
+		RebuildDeps:       opts.rebuildDeps,
+		RebuildFailedDeps: opts.rebuildFailedDeps,
+		Top:               opts.queueAtTop,
+		Agent:             opts.agent,
+		ReuseDeps:         opts.reuseDeps,
+		ArtifactFrom:      opts.artifactFrom,
+		Settings:          opts.settings,
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Run definition for %s. Start it with: teamcity run start -f <file>\n", jobID)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(rf); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err := out.Write(buf.Bytes())
+		return err
+	}
+	// Parameters can be credentials, so the file is readable by its owner only.
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write run file: %w", err)
+	}
+	return nil
+}
+
+// loadRunFile reads the file behind -f into opts and returns the job it names, or "" when it names none.
+func loadRunFile(f *cmdutil.Factory, flags *pflag.FlagSet, path string, opts *runStartOptions) (string, error) {
+	rf, err := readRunFile(path, f.IOStreams.In)
+	if err != nil {
+		return "", err
+	}
+	rf.apply(flags, opts)
+	return rf.Job, nil
+}
@@ -0,0 +1,171 @@
+package run
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+)
+
+// defaultSanitizeRule is how test frameworks commonly turn a test name into a file name.
+const defaultSanitizeRule = `[^A-Za-z0-9._-]+=_`
+
+// sanitizeRule replaces every match of re in a test name with repl.
+type sanitizeRule struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// testNameSanitizer turns a test name into the form a test framework uses for it in artifact paths.
+type testNameSanitizer []sanitizeRule
+
+// parseSanitizeRules parses REGEXP=REPLACEMENT rules, applied in order; no rules means defaultSanitizeRule.
+func parseSanitizeRules(rules []string) (testNameSanitizer, error) {
+	if len(rules) == 0 {
+		rules = []string{defaultSanitizeRule}
+	}
+	s := make(testNameSanitizer, len(rules))
+	for i, rule := range rules {
+		expr, repl, ok := strings.Cut(rule, "=")
+		if !ok || expr == "" {
+			return nil, api.Validation(fmt.Sprintf("invalid --sanitize rule %q", rule), "use REGEXP=REPLACEMENT, e.g. --sanitize '[^A-Za-z0-9]+=_'")
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --sanitize rule %q: %w", rule, err)
+		}
+		s[i] = sanitizeRule{re: re, repl: repl}
+	}
+	return s, nil
+}
+
+func (s testNameSanitizer) apply(name string) string {
+	for _, r := range s {
+		name = r.re.ReplaceAllLiteralString(name, r.repl)
+	}
+	return name
+}
+
+// expandArtifactTemplate fills {test} in template with a sanitized test name, escaped so it matches literally.
+func expandArtifactTemplate(template, test string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(test)
+	return strings.ReplaceAll(template, "{test}", escaped)
+}
+
+// testArtifactsOptions configures run tests --download-artifacts.
+type testArtifactsOptions struct {
+	dir       string
+	template  string
+	sanitize  []string
+	sanitizer testNameSanitizer
+}
+
+func (o *testArtifactsOptions) validate() error {
+	if !strings.Contains(o.template, "{test}") {
+		return api.Validation(fmt.Sprintf("--artifact-template %q does not contain {test}", o.template), "include {test} where the test name appears, e.g. 'screenshots/{test}*'")
+	}
+	if _, err := path.Match(expandArtifactTemplate(o.template, "x"), ""); err != nil {
+		return fmt.Errorf("invalid --artifact-template %q: %w", o.template, err)
+	}
+	sanitizer, err := parseSanitizeRules(o.sanitize)
+	if err != nil {
+		return err
+	}
+	o.sanitizer = sanitizer
+	return nil
+}
+
+// downloadTestArtifacts downloads, for each failed test, the artifacts matching opts.template into a folder
+// named after the test. Tests without artifacts are reported; only failed downloads fail the command.
+func downloadTestArtifacts(f *cmdutil.Factory, client api.ClientInterface, runID string, tests []api.TestOccurrence, opts *testArtifactsOptions) error {
+	p := f.Printer
+	ctx := f.Context()
+
+	absDir, err := filepath.Abs(opts.dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+
+	var failed []api.TestOccurrence
+	for _, t := range tests {
+		if t.Status == "FAILURE" {
+			failed = append(failed, t)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(p.Out, "\nDownloading artifacts of %s to %s\n", english.Plural(len(failed), "failed test", ""), opts.dir)
+
+	// Tests of merged batches carry their own batch build, whose artifacts are listed once.
+	listings := map[string][]api.Artifact{}
+	downloaded, total, missing := 0, 0, 0
+	for _, t := range failed {
+		buildID := runID
+		if t.Build != nil && t.Build.ID != 0 {
+			buildID = strconv.Itoa(t.Build.ID)
+		}
+		artifacts, ok := listings[buildID]
+		if !ok {
+			if artifacts, _, err = cmdutil.FetchAllArtifacts(ctx, client, buildID, ""); err != nil {
+				return fmt.Errorf("failed to get artifacts of run %s: %w", buildID, err)
+			}
+			listings[buildID] = artifacts
+		}
+
+		name := opts.sanitizer.apply(t.Name)
+		pattern := expandArtifactTemplate(opts.template, name)
+		matches, _, _ := filterArtifacts(artifacts, pattern)
+		if len(matches) == 0 {
+			missing++
+			_, _ = fmt.Fprintf(p.Out, "\n%s %s: no artifacts match %s\n", output.Yellow("!"), t.Name, pattern)
+			continue
+		}
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Red(output.Sym().Cross), t.Name)
+		if !filepath.IsLocal(name) {
+			total += len(matches)
+			_, _ = fmt.Fprintf(p.Out, "    %s %s: folder name escapes the output directory\n", output.Red(output.Sym().Cross), name)
+			continue
+		}
+
+		for _, a := range matches {
+			total++
+			local := filepath.Join(name, testArtifactFileName(a, matches))
+			if _, err := downloadArtifact(ctx, client, buildID, a, filepath.Join(absDir, local), nil); err != nil {
+				_, _ = fmt.Fprintf(p.Out, "    %s %s %v\n", output.Red(output.Sym().Cross), a.Name, err)
+				continue
+			}
+			downloaded++
+			_, _ = fmt.Fprintf(p.Out, "    %s %s %s %s\n", output.Green(output.Sym().Check), a.Name, output.Faint("->"), filepath.Join(opts.dir, local))
+		}
+	}
+
+	if downloaded < total {
+		return fmt.Errorf("downloaded %d of %d test artifacts", downloaded, total)
+	}
+	_, _ = fmt.Fprintf(p.Out, "\n%s %s downloaded", output.Green(output.Sym().Check), english.Plural(downloaded, "artifact", ""))
+	if missing > 0 {
+		_, _ = fmt.Fprintf(p.Out, ", %s", output.Yellow(fmt.Sprintf("%d %s without artifacts", missing, english.PluralWord(missing, "test", "tests"))))
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	return nil
+}
+
+// testArtifactFileName names artifact a inside its test's folder: its base name, or its full path with
+// slashes replaced when another match has the same base name.
+func testArtifactFileName(a api.Artifact, matches []api.Artifact) string {
+	base := path.Base(a.Name)
+	for _, m := range matches {
+		if m.Name != a.Name && path.Base(m.Name) == base {
+			return strings.ReplaceAll(a.Name, "/", "_")
+		}
+	}
+	return base
+}
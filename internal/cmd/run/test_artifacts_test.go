@@ -0,0 +1,89 @@
+package run_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+)
+
+func setupTestArtifactsServer(t *testing.T) *cmdtest.TestServer {
+	t.Helper()
+	ts := cmdtest.NewTestServer(t)
+
+	ts.Handle("GET /app/rest/server", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Server{VersionMajor: 2025, VersionMinor: 7, BuildNumber: "197398"})
+	})
+	ts.Handle("GET /app/rest/builds/id:1", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Build{ID: 1, Number: "1", State: "finished", Status: "FAILURE"})
+	})
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.TestOccurrences{Count: 2, Failed: 2, TestOccurrence: []api.TestOccurrence{
+			{ID: "1", Name: "LoginTest: can sign in", Status: "FAILURE"},
+			{ID: "2", Name: "CartTest: adds item", Status: "FAILURE"},
+		}})
+	})
+	content := &api.Content{Href: "/download"}
+	ts.Handle("GET /app/rest/builds/id:1/artifacts/children", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Artifacts{File: []api.Artifact{{Name: "screenshots"}, {Name: "report.html", Size: 4, Content: content}}})
+	})
+	ts.Handle("GET /app/rest/builds/id:1/artifacts/children/screenshots", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.Artifacts{File: []api.Artifact{
+			{Name: "LoginTest_can_sign_in-1.png", Size: 3, Content: content},
+			{Name: "LoginTest_can_sign_in-2.png", Size: 3, Content: content},
+			{Name: "Other_test.png", Size: 3, Content: content},
+		}})
+	})
+	ts.Handle("GET /app/rest/builds/id:1/artifacts/content/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("png"))
+	})
+
+	config.SetUserForServer(ts.URL, "admin")
+	return ts
+}
+
+func TestRunTestsDownloadArtifacts(T *testing.T) {
+	ts := setupTestArtifactsServer(T)
+	dir := T.TempDir()
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "tests", "1", "--failed",
+		"--download-artifacts="+dir, "--artifact-template", "screenshots/{test}*")
+
+	for _, name := range []string{"LoginTest_can_sign_in-1.png", "LoginTest_can_sign_in-2.png"} {
+		data, err := os.ReadFile(filepath.Join(dir, "LoginTest_can_sign_in", name))
+		require.NoError(T, err)
+		assert.Equal(T, "png", string(data))
+	}
+	assert.NoDirExists(T, filepath.Join(dir, "CartTest_adds_item"))
+	assert.Contains(T, got, "CartTest: adds item: no artifacts match screenshots/CartTest_adds_item*")
+	assert.Contains(T, got, "2 artifacts downloaded, 1 test without artifacts")
+}
+
+func TestRunTestsDownloadArtifactsSanitize(T *testing.T) {
+	ts := setupTestArtifactsServer(T)
+	dir := T.TempDir()
+
+	got := cmdtest.CaptureOutput(T, ts.Factory, "run", "tests", "1", "--failed",
+		"--download-artifacts="+dir, "--artifact-template", "screenshots/{test}-1.png",
+		"--sanitize", ": =_", "--sanitize", " =_")
+
+	assert.FileExists(T, filepath.Join(dir, "LoginTest_can_sign_in", "LoginTest_can_sign_in-1.png"))
+	assert.NoFileExists(T, filepath.Join(dir, "LoginTest_can_sign_in", "LoginTest_can_sign_in-2.png"))
+	assert.Equal(T, 1, strings.Count(got, "no artifacts match"))
+}
+
+func TestRunTestsDownloadArtifactsInvalid(T *testing.T) {
+	ts := setupTestArtifactsServer(T)
+
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "--download-artifacts requires --failed", "run", "tests", "1", "--download-artifacts")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, "does not contain {test}", "run", "tests", "1", "--failed", "--download-artifacts", "--artifact-template", "screenshots/*")
+	cmdtest.RunCmdWithFactoryExpectErr(T, ts.Factory, `invalid --sanitize rule "nope"`, "run", "tests", "1", "--failed", "--download-artifacts", "--sanitize", "nope")
+}
@@ -0,0 +1,131 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+)
+
+// newFailuresJSON is the run tests --new-failures --json output. The previous run is absent for the first run on
+// a branch, when every failure is new.
+type newFailuresJSON struct {
+	RunID          int                  `json:"run_id"`
+	Number         string               `json:"number"`
+	Branch         string               `json:"branch,omitempty"`
+	PreviousRunID  int                  `json:"previous_run_id,omitempty"`
+	PreviousNumber string               `json:"previous_number,omitempty"`
+	NewFailures    []api.TestOccurrence `json:"new_failures"`
+	StillFailing   []api.TestOccurrence `json:"still_failing"`
+}
+
+// runNewFailures prints the tests that failed in build but not in the job's previous finished run on the same
+// branch, and counts the ones that failed in both.
+func runNewFailures(f *cmdutil.Factory, client api.ClientInterface, build *api.Build, opts *runTestsOptions) error {
+	p := f.Printer
+	ctx := f.Context()
+
+	prev, err := cmdutil.PreviousRun(ctx, client, build)
+	if err != nil {
+		return fmt.Errorf("failed to find the previous run: %w", err)
+	}
+
+	current, err := failedTests(ctx, client, build.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get tests: %w", err)
+	}
+	failedBefore := map[string]bool{}
+	if prev != nil {
+		before, err := failedTests(ctx, client, prev.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get the tests of #%s: %w", prev.Number, err)
+		}
+		for _, t := range before.TestOccurrence {
+			failedBefore[testKey(t)] = true
+		}
+	}
+
+	result := newFailuresJSON{
+		RunID:        build.ID,
+		Number:       build.Number,
+		Branch:       build.BranchName,
+		NewFailures:  []api.TestOccurrence{},
+		StillFailing: []api.TestOccurrence{},
+	}
+	if prev != nil {
+		result.PreviousRunID, result.PreviousNumber = prev.ID, prev.Number
+	}
+	for _, t := range current.TestOccurrence {
+		if failedBefore[testKey(t)] {
+			result.StillFailing = append(result.StillFailing, t)
+		} else {
+			result.NewFailures = append(result.NewFailures, t)
+		}
+	}
+	if opts.limit > 0 && len(result.NewFailures) > opts.limit {
+		result.NewFailures = result.NewFailures[:opts.limit]
+	}
+
+	if opts.json {
+		return p.PrintJSON(result)
+	}
+
+	if prev == nil {
+		p.Info("No earlier finished run of %s on this branch; every failure is new", build.BuildTypeID)
+	}
+	if len(result.NewFailures) == 0 {
+		since := ""
+		if prev != nil {
+			since = " since #" + prev.Number
+		}
+		p.Success("No new failed tests in #%s%s", build.Number, since)
+	} else {
+		heading := fmt.Sprintf("New failures in #%s (%d):", build.Number, len(result.NewFailures))
+		if prev != nil {
+			heading = fmt.Sprintf("New failures in #%s since #%s (%d):", build.Number, prev.Number, len(result.NewFailures))
+		}
+		_, _ = fmt.Fprintln(p.Out, heading)
+		for _, t := range result.NewFailures {
+			_, _ = fmt.Fprintf(p.Out, "%s %s\n", output.Red(output.Sym().Cross), t.Name)
+			if opts.links {
+				_, _ = fmt.Fprintf(p.Out, "    %s\n", output.Faint(testWebURL(t, build.WebURL)))
+			}
+		}
+	}
+	if n := len(result.StillFailing); n > 0 {
+		_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint(fmt.Sprintf("Still failing: %s that also failed in #%s", english.Plural(n, "test", ""), prev.Number)))
+	}
+	if build.WebURL != "" {
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("View in browser:"), runTestsBrowserURL(build.WebURL, opts))
+	}
+	return nil
+}
+
+// failedTests fetches the unmuted failures of a run along with the test ID and the job each ran in, so that
+// tests sharing a name across the batches of a composite run stay apart.
+func failedTests(ctx context.Context, client api.ClientInterface, buildID int) (*api.TestOccurrences, error) {
+	return client.ListTestOccurrences(ctx, api.TestOccurrenceQuery{
+		Build:  strconv.Itoa(buildID),
+		Status: "failed",
+		Muted:  new(false),
+		Fields: []string{"id", "name", "status", "duration", "details", "newFailure", "muted", "firstFailed(build(id,number))", "test(id)", "build(id,buildTypeId)"},
+	})
+}
+
+// testKey identifies a test across two runs of a job: its test ID (its name when the server omits the ID) within
+// the job that ran it, which differs per batch of a composite run.
+func testKey(t api.TestOccurrence) string {
+	id := t.Name
+	if t.Test != nil && t.Test.ID != "" {
+		id = t.Test.ID
+	}
+	batch := ""
+	if t.Build != nil {
+		batch = t.Build.BuildTypeID
+	}
+	return batch + "\x00" + id
+}
@@ -41,6 +41,7 @@ func (n RunTreeNode) toDisplayNode() output.TreeNode {
 func newRunTreeCmd(f *cmdutil.Factory) *cobra.Command {
 	var depth int
 	var jsonOut bool
+	var job string
 
 	cmd := &cobra.Command{
 		Use:   "tree <id>",
@@ -50,22 +51,28 @@ func newRunTreeCmd(f *cmdutil.Factory) *cobra.Command {
   teamcity run tree 12345 --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRunTree(f, args[0], depth, jsonOut)
+			return runRunTree(f, args[0], job, depth, jsonOut)
 		},
 	}
 
 	cmd.Flags().IntVarP(&depth, "depth", "d", 0, "Limit tree depth (0 = unlimited)")
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	addRunJobFlag(cmd, &job)
 
 	return cmd
 }
 
-func runRunTree(f *cmdutil.Factory, runID string, depth int, jsonOut bool) error {
+func runRunTree(f *cmdutil.Factory, runID, job string, depth int, jsonOut bool) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	runID, err = resolveRunRef(f, client, runID, job)
+	if err != nil {
+		return err
+	}
+
 	build, err := client.GetBuild(f.Context(), runID)
 	if err != nil {
 		return err
@@ -155,10 +162,7 @@ func buildStatusSummary(deps []RunTreeNode) string {
 }
 
 func buildRunTree(client api.ClientInterface, b api.Build, depth int, path map[string]bool) (RunTreeNode, error) {
-	name := b.BuildTypeID
-	if b.BuildType != nil && b.BuildType.Name != "" {
-		name = b.BuildType.Name
-	}
+	name := cmdutil.JobName(b.BuildType, b.BuildTypeID)
 	node := RunTreeNode{
 		ID:           b.ID,
 		Number:       b.Number,
@@ -189,10 +193,7 @@ func buildRunTree(client api.ClientInterface, b api.Build, depth int, path map[s
 	for i, dep := range deps.Builds {
 		sid := strconv.Itoa(dep.ID)
 		if path[sid] {
-			name := dep.BuildTypeID
-			if dep.BuildType != nil && dep.BuildType.Name != "" {
-				name = dep.BuildType.Name
-			}
+			name := cmdutil.JobName(dep.BuildType, dep.BuildTypeID)
 			results[i] = result{idx: i, node: RunTreeNode{
 				ID:           dep.ID,
 				Number:       dep.Number,
@@ -209,15 +209,12 @@ func (m watchModel) renderHeader() string {
 		return output.Yellow("*") + " Refreshing..."
 	}
 
-	jobName := m.build.BuildTypeID
-	if m.build.BuildType != nil {
-		jobName = m.build.BuildType.Name
-	}
+	jobName := cmdutil.JobName(m.build.BuildType, m.build.BuildTypeID)
 
 	icon := output.StatusIcon(m.build.Status, m.build.State, m.build.StatusText)
 	status := output.StatusText(m.build.Status, m.build.State, m.build.StatusText)
 
-	header := fmt.Sprintf("%s %s %d  #%s %s "+output.Sym().Sep+" %s", icon, output.Bold(jobName), m.build.ID, m.build.Number, output.Faint(m.build.WebURL), status)
+	header := fmt.Sprintf("%s %s %d  %s %s "+output.Sym().Sep+" %s", icon, output.Bold(jobName), m.build.ID, cmdutil.RunNumber(m.build.Number), output.Faint(m.build.WebURL), status)
 	if m.build.PercentageComplete > 0 && m.build.State != "finished" {
 		header += fmt.Sprintf(" (%d%%)", m.build.PercentageComplete)
 	}
@@ -255,8 +252,8 @@ func (m watchModel) renderLogs(height int) string {
 	return result.String()
 }
 
-// RunWatchTUI launches the interactive TUI for watching a build.
-func RunWatchTUI(ctx context.Context, client api.ClientInterface, runID string, interval int) error {
+// RunWatchTUI launches the interactive TUI for watching a build; brief prints the final result without the failure summary.
+func RunWatchTUI(ctx context.Context, client api.ClientInterface, runID string, interval int, brief bool) error {
 	m := newWatchModel(ctx, client, runID, interval)
 	output.StopSpinner() // hand the terminal to bubbletea's alt screen
 	p := tea.NewProgram(m, tea.WithAltScreen())
@@ -286,6 +283,9 @@ func RunWatchTUI(ctx context.Context, client api.ClientInterface, runID string,
 		return nil
 	}
 
+	if brief {
+		return cmdutil.BuildResultBrief(printer, fm.build)
+	}
 	return cmdutil.BuildResultError(ctx, printer, client, fm.build, true)
 }
 
@@ -0,0 +1,144 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+)
+
+// failedTestsShown caps how many failed tests run view names for a failed run.
+const failedTestsShown = 5
+
+// runViewDetails is what run view adds to the build: how long it waited in the queue, the problems and first
+// failed tests of a failed run, and with --full its comment, artifacts and dependencies. run view --json merges
+// it into the build; each lookup is best-effort and left empty on error.
+type runViewDetails struct {
+	QueueWait   int                     `json:"queueWaitSeconds,omitempty"`
+	Problems    []api.ProblemOccurrence `json:"problems,omitempty"`
+	FailedTests []string                `json:"failedTests,omitempty"`
+	// MoreFailedTests is set when the run has more failed tests than FailedTests names.
+	MoreFailedTests bool                `json:"moreFailedTests,omitempty"`
+	Comment         string              `json:"comment,omitempty"`
+	Artifacts       *runArtifactSummary `json:"artifacts,omitempty"`
+}
+
+// runArtifactSummary counts the artifact files of a run, in all directories; Size is in bytes.
+type runArtifactSummary struct {
+	Files int   `json:"files"`
+	Size  int64 `json:"size"`
+}
+
+// fetchRunViewDetails collects the details for build read from src. The --full ones need a live server; its
+// dependencies are stored on build itself.
+func fetchRunViewDetails(ctx context.Context, src runSource, build *api.Build, full bool) runViewDetails {
+	var d runViewDetails
+	id := strconv.Itoa(build.ID)
+	if wait := queueWait(build); wait > 0 {
+		d.QueueWait = int(wait.Seconds())
+	}
+
+	if build.State == "finished" && build.Status == "FAILURE" && !cmdutil.RunCanceled(build) {
+		if problems, err := src.GetBuildProblems(id); err == nil {
+			d.Problems = problems.ProblemOccurrence
+		}
+		if tests, err := src.GetBuildTests(ctx, id, api.BuildTestsOptions{FailedOnly: true, Limit: failedTestsShown + 1}); err == nil {
+			for _, t := range tests.TestOccurrence {
+				if len(d.FailedTests) == failedTestsShown {
+					d.MoreFailedTests = true
+					break
+				}
+				d.FailedTests = append(d.FailedTests, t.Name)
+			}
+		}
+	}
+
+	client, live := src.(api.ClientInterface)
+	if !full || !live || build.State == "queued" {
+		return d
+	}
+	if comment, err := client.GetBuildComment(id); err == nil {
+		d.Comment = comment
+	}
+	if files, size, err := cmdutil.FetchAllArtifacts(ctx, client, id, ""); err == nil {
+		d.Artifacts = &runArtifactSummary{Files: len(files), Size: size}
+	}
+	if deps, err := client.GetBuildSnapshotDependencies(id); err == nil && len(deps.Builds) > 0 {
+		build.SnapshotDependencies = deps
+	}
+	if deps, err := client.GetBuildArtifactDependencies(id); err == nil && len(deps.Builds) > 0 {
+		build.ArtifactDependencies = deps
+	}
+	return d
+}
+
+// queueWait is how long build waited in the queue before it started; zero while it has not started.
+func queueWait(build *api.Build) time.Duration {
+	queued, err := api.ParseTeamCityTime(build.QueuedDate)
+	if err != nil {
+		return 0
+	}
+	started, err := api.ParseTeamCityTime(build.StartDate)
+	if err != nil {
+		return 0
+	}
+	return max(started.Sub(queued), 0)
+}
+
+// printFailureDetails renders the problems line and the first failed tests of a failed run.
+func printFailureDetails(p *output.Printer, build *api.Build, d runViewDetails) {
+	if len(d.Problems) > 0 {
+		_, _ = fmt.Fprintf(p.Out, "%s %s  %s teamcity run problems %d\n", output.Red("Problems:"), problemSummary(d.Problems),
+			output.Faint(output.Sym().Sep), build.ID)
+	}
+	if len(d.FailedTests) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(p.Out, "\n%s:\n", output.Cyan("Failed tests"))
+	for _, name := range d.FailedTests {
+		_, _ = fmt.Fprintf(p.Out, "  %s %s\n", output.Red(output.Sym().Cross), name)
+	}
+	hint := "Details"
+	if d.MoreFailedTests {
+		hint = "More"
+	}
+	_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(fmt.Sprintf("%s: teamcity run tests %d --failed", hint, build.ID)))
+}
+
+// printFullDetails renders the --full sections of run view.
+func printFullDetails(p *output.Printer, build *api.Build, d runViewDetails) {
+	if d.Comment != "" {
+		_, _ = fmt.Fprintf(p.Out, "\nComment: %s\n", d.Comment)
+	}
+	if a := d.Artifacts; a != nil {
+		artifacts := "none"
+		if a.Files > 0 {
+			artifacts = fmt.Sprintf("%s, %s", english.Plural(a.Files, "file", ""), humanize.IBytes(uint64(a.Size)))
+		}
+		_, _ = fmt.Fprintf(p.Out, "\nArtifacts: %s\n", artifacts)
+	}
+	printDependencies(p, "Snapshot dependencies", build.SnapshotDependencies)
+	printDependencies(p, "Artifact dependencies", build.ArtifactDependencies)
+}
+
+func printDependencies(p *output.Printer, title string, deps *api.BuildList) {
+	if deps == nil || len(deps.Builds) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(p.Out, "\n%s:\n", output.Cyan(title))
+	for _, b := range deps.Builds {
+		_, _ = fmt.Fprintf(p.Out, "  %s %s %d  %s %s %s\n",
+			output.StatusIcon(b.Status, b.State, b.StatusText),
+			cmdutil.JobName(b.BuildType, b.BuildTypeID),
+			b.ID,
+			cmdutil.RunNumber(b.Number),
+			output.Sym().Sep,
+			output.StatusText(b.Status, b.State, b.StatusText))
+	}
+}
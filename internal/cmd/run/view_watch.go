@@ -0,0 +1,156 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+type runViewWatchOptions struct {
+	enabled  bool
+	interval int
+}
+
+// viewWatchLogLines is how many of the latest log lines the watched view shows.
+const viewWatchLogLines = 8
+
+// clearScreen moves the cursor home and clears the terminal, so the watched view redraws in place.
+const clearScreen = "\033[H\033[2J"
+
+// viewWatchRedrawFn reports whether the watched view can be redrawn in place; tests override it.
+var viewWatchRedrawFn = func() bool {
+	return output.IsTerminal() && output.VT
+}
+
+// runViewWatch re-renders the run view every interval until the run finishes or is canceled, then prints its
+// result. The error follows the result, as for run watch.
+func runViewWatch(f *cmdutil.Factory, client api.ClientInterface, runID string, opts *runViewWatchOptions) error {
+	if opts.interval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second, got %d", opts.interval)
+	}
+	p := f.Printer
+	ctx := f.Context()
+	redraw := viewWatchRedrawFn()
+
+	last := ""
+	for {
+		var buf bytes.Buffer
+		build, err := renderRunView(f, p.WithOut(&buf), client, runID, &cmdutil.ViewOptions{}, false, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return viewWatchInterrupted(p, runID)
+			}
+			return err
+		}
+		if build.State != "finished" {
+			printRunProgress(ctx, &buf, client, build)
+		}
+
+		snapshot := buf.String()
+		switch {
+		case redraw:
+			_, _ = fmt.Fprint(p.Out, clearScreen+snapshot)
+		case snapshot != last:
+			if last != "" {
+				_, _ = fmt.Fprintf(p.Out, "\n%s\n\n", output.Faint("--- "+time.Now().Format(time.TimeOnly)))
+			}
+			_, _ = fmt.Fprint(p.Out, snapshot)
+		}
+		last = snapshot
+
+		if build.State != "finished" && cmdutil.RunCanceled(build) {
+			_, _ = fmt.Fprintln(p.Out)
+			return cmdutil.BuildResultBrief(p, build)
+		}
+		if build.State == "finished" {
+			_, _ = fmt.Fprintln(p.Out)
+			summary := cmdutil.CollectRunSummary(ctx, client, build)
+			err := cmdutil.BuildResultError(ctx, p, client, build, false)
+			cmdutil.PrintRunSummary(p, summary)
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return viewWatchInterrupted(p, runID)
+		case <-time.After(time.Duration(opts.interval) * time.Second):
+		}
+	}
+}
+
+func viewWatchInterrupted(p *output.Printer, runID string) error {
+	_, _ = fmt.Fprintln(p.Out)
+	_, _ = fmt.Fprintln(p.Out, output.Faint("Interrupted. Run continues in background."))
+	p.Tip("%s", output.TipResumeWatchFor(runID))
+	return nil
+}
+
+// printRunProgress adds what a running run is doing to its view: the current step, the time it has taken
+// against the estimate, and the latest log lines.
+func printRunProgress(ctx context.Context, w io.Writer, client api.ClientInterface, build *api.Build) {
+	if build.State != "running" {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	if step := currentStep(build); step != "" {
+		_, _ = fmt.Fprintf(w, "Step: %s\n", step)
+	}
+	if elapsed := elapsedText(build); elapsed != "" {
+		_, _ = fmt.Fprintf(w, "Elapsed: %s\n", elapsed)
+	}
+
+	resp, err := client.GetBuildMessages(ctx, strconv.Itoa(build.ID), api.BuildMessagesOptions{
+		Count:     -viewWatchLogLines,
+		Tail:      true,
+		ExpandAll: true,
+	})
+	if err != nil || len(resp.Messages) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n%s:\n", output.Cyan("Latest log"))
+	for _, msg := range resp.Messages {
+		if line := formatMessage(msg, false); line != "" {
+			_, _ = fmt.Fprintln(w, "  "+line)
+		}
+	}
+}
+
+// currentStep is the step a running run is on, as TeamCity reports it in the run's running info.
+func currentStep(build *api.Build) string {
+	if build.RunningInfo == nil {
+		return ""
+	}
+	return build.RunningInfo.CurrentStageText
+}
+
+// elapsedText is how long a running run has taken, with the server's estimate of the total when there is one.
+func elapsedText(build *api.Build) string {
+	var elapsed, estimate time.Duration
+	if info := build.RunningInfo; info != nil {
+		elapsed = time.Duration(info.ElapsedSeconds) * time.Second
+		estimate = time.Duration(info.EstimatedTotalSeconds) * time.Second
+	}
+	if elapsed == 0 {
+		start, err := api.ParseTeamCityTime(build.StartDate)
+		if err != nil {
+			return ""
+		}
+		elapsed = time.Since(start)
+	}
+
+	text := output.FormatDuration(elapsed)
+	if estimate > 0 {
+		text += " of ~" + output.FormatDuration(estimate)
+	}
+	if build.RunningInfo != nil && build.RunningInfo.ProbablyHanging {
+		text += " " + output.Yellow("(probably hanging)")
+	}
+	return text
+}
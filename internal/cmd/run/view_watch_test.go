@@ -0,0 +1,110 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunViewWatchUntilFinished(t *testing.T) {
+	var polls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/app/rest/builds/id:55" && r.URL.Query().Get("fields") != "":
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/app/rest/builds/id:55":
+			build := api.Build{ID: 55, Number: "12", BuildTypeID: "Falcon_Build", WebURL: "https://tc.example.com/build/55"}
+			if polls.Add(1) == 1 {
+				build.State = "running"
+				build.PercentageComplete = 40
+				build.StartDate = api.FormatTeamCityTime(time.Now().Add(-2 * time.Minute))
+				build.RunningInfo = &api.RunningInfo{ElapsedSeconds: 120, EstimatedTotalSeconds: 300, CurrentStageText: "Step 2/3: Gradle"}
+			} else {
+				build.State, build.Status, build.StatusText = "finished", "FAILURE", "Tests failed: 2, passed: 10"
+			}
+			_ = json.NewEncoder(w).Encode(build)
+		case r.URL.Path == "/app/messages":
+			_ = json.NewEncoder(w).Encode(api.BuildMessagesResponse{Messages: []api.BuildMessage{{Text: "Compiling module core"}}})
+		case r.URL.Path == "/app/rest/testOccurrences":
+			_ = json.NewEncoder(w).Encode(api.TestOccurrences{Count: 12, Passed: 10, Failed: 2})
+		case r.URL.Path == "/app/rest/builds":
+			_ = json.NewEncoder(w).Encode(api.BuildList{})
+		case r.URL.Path == "/app/rest/problemOccurrences":
+			_ = json.NewEncoder(w).Encode(api.ProblemOccurrences{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	origRedraw := viewWatchRedrawFn
+	t.Cleanup(func() { viewWatchRedrawFn = origRedraw })
+	viewWatchRedrawFn = func() bool { return false }
+
+	var out bytes.Buffer
+	f := &cmdutil.Factory{Printer: &output.Printer{Out: &out, ErrOut: &out}}
+	client := api.NewClient(ts.URL, "test-token")
+
+	err := runViewWatch(f, client, "55", &runViewWatchOptions{interval: 1})
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, cmdutil.ExitFailure, exitErr.Code)
+
+	got := out.String()
+	assert.NotContains(t, got, clearScreen, "without a terminal, snapshots are appended")
+	assert.Contains(t, got, "Progress: 40%")
+	assert.Contains(t, got, "Step: Step 2/3: Gradle")
+	assert.Contains(t, got, "Elapsed: 2m 0s of ~5m 0s")
+	assert.Contains(t, got, "Compiling module core")
+	assert.Contains(t, got, "--- ", "the second snapshot is set off from the first")
+	assert.Contains(t, got, "Tests: 10 passed, 2 failed")
+	assert.Equal(t, 1, strings.Count(got, "Latest log"), "finished runs show no log tail")
+}
+
+func TestRunViewWatchRedrawsOnTerminal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/app/rest/builds/id:56":
+			_ = json.NewEncoder(w).Encode(api.Build{ID: 56, Number: "3", BuildTypeID: "Falcon_Build", State: "finished", Status: "SUCCESS"})
+		case "/app/rest/builds":
+			_ = json.NewEncoder(w).Encode(api.BuildList{})
+		case "/app/rest/testOccurrences":
+			_ = json.NewEncoder(w).Encode(api.TestOccurrences{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	origRedraw := viewWatchRedrawFn
+	t.Cleanup(func() { viewWatchRedrawFn = origRedraw })
+	viewWatchRedrawFn = func() bool { return true }
+
+	var out bytes.Buffer
+	f := &cmdutil.Factory{Printer: &output.Printer{Out: &out, ErrOut: &out}}
+	require.NoError(t, runViewWatch(f, api.NewClient(ts.URL, "test-token"), "56", &runViewWatchOptions{interval: 1}))
+	assert.True(t, strings.HasPrefix(out.String(), clearScreen))
+	assert.Contains(t, out.String(), "succeeded")
+	assert.NotContains(t, out.String(), "Tests:", "runs without tests print no test line")
+}
+
+func TestElapsedText(t *testing.T) {
+	build := &api.Build{RunningInfo: &api.RunningInfo{ElapsedSeconds: 90, ProbablyHanging: true}}
+	assert.Contains(t, elapsedText(build), "1m 30s")
+	assert.Contains(t, elapsedText(build), "probably hanging")
+	assert.Empty(t, elapsedText(&api.Build{}))
+}
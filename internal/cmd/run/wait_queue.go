@@ -0,0 +1,170 @@
+package run
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+)
+
+// waitForQueue polls build while it is queued, showing its queue position, wait reason and start estimate as they
+// change, and once who can approve it, until it leaves the queue. It returns the build as last read, still queued
+// when the wait was interrupted; quiet prints nothing, for --json.
+func waitForQueue(f *cmdutil.Factory, client api.ClientInterface, build *api.Build, wf *watchFlags, quiet bool) (*api.Build, error) {
+	p := f.Printer
+	if wf.interval < 1 {
+		return nil, fmt.Errorf("--interval must be at least 1 second, got %d", wf.interval)
+	}
+	topCtx := f.Context()
+	ctx := topCtx
+	if wf.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wf.timeout)
+		defer cancel()
+	}
+
+	id := strconv.Itoa(build.ID)
+	waitStart := time.Now()
+	redraw := redrawInPlace()
+	printed := false
+	lastState := ""
+	approvalShown := false
+	for {
+		b, err := client.GetBuild(ctx, id)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if err == nil {
+			build = b
+		}
+		if err == nil && build.State != "queued" {
+			break
+		}
+
+		if err == nil && !quiet {
+			info := fetchQueuedRunInfo(ctx, client, build)
+			state := fmt.Sprintf("%d\x00%s\x00%s", info.QueuePosition, build.WaitReason, info.StartEstimate)
+			line := output.StatusIcon("", "queued") + " " + queueWaitLine(build, info)
+			switch {
+			case redraw:
+				_, _ = fmt.Fprint(p.Out, "\r"+line+"\033[K")
+				printed = true
+			case state != lastState:
+				_, _ = fmt.Fprintln(p.Out, line)
+			}
+			lastState = state
+			if a := info.Approval; a != nil && a.Status == "waitingForApproval" && !approvalShown {
+				if printed {
+					_, _ = fmt.Fprintln(p.Out)
+					printed = false
+				}
+				printApprovalHint(p, build.ID, a)
+				approvalShown = true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(wf.interval) * time.Second):
+			continue
+		}
+		if printed {
+			_, _ = fmt.Fprintln(p.Out)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if !quiet {
+				_, _ = fmt.Fprintf(p.Out, "%s Timeout exceeded; the run is still queued\n", output.Red(output.Sym().Cross))
+			}
+			return build, &cmdutil.ExitError{Code: cmdutil.ExitTimeout}
+		}
+		if !quiet {
+			_, _ = fmt.Fprintln(p.Out, output.Faint("Interrupted. The run stays queued."))
+			p.Tip("%s", output.TipResumeWatchFor(id))
+		}
+		return build, nil
+	}
+
+	if printed {
+		_, _ = fmt.Fprintln(p.Out)
+	}
+	if !quiet && build.State == "running" {
+		wait := queueWait(build)
+		if wait <= 0 {
+			wait = time.Since(waitStart)
+		}
+		agent := "an agent"
+		if build.Agent != nil && build.Agent.Name != "" {
+			agent = build.Agent.Name
+		}
+		p.Success("Started on %s after %s in the queue", agent, output.FormatDuration(wait))
+	}
+	return build, nil
+}
+
+// queueWaitLine is the state of a queued run in one line: where it is in the queue, why it waits and when the
+// server expects it to start.
+func queueWaitLine(build *api.Build, info queuedRunInfo) string {
+	parts := []string{"Queued"}
+	if info.QueuePosition > 0 {
+		parts = append(parts, "position "+info.positionText())
+	}
+	if build.WaitReason != "" {
+		parts = append(parts, build.WaitReason)
+	}
+	if t, err := api.ParseTeamCityTime(info.StartEstimate); err == nil {
+		parts = append(parts, "starts "+startEstimateText(t, timeref.Now()))
+	}
+	return output.Faint(strings.Join(parts, " "+output.Sym().Sep+" "))
+}
+
+// printApprovalHint says who can approve a queued run, and how.
+func printApprovalHint(p *output.Printer, buildID int, a *api.ApprovalInfo) {
+	line := output.Yellow("Waiting for approval")
+	if who := approvers(a); who != "" {
+		line += " from " + who
+	}
+	_, _ = fmt.Fprintln(p.Out, line)
+	approve := fmt.Sprintf("teamcity queue approve %d", buildID)
+	if a.CanBeApprovedByCurrentUser {
+		_, _ = fmt.Fprintf(p.Out, "  %s %s\n", output.Faint("You can approve it:"), approve)
+		return
+	}
+	_, _ = fmt.Fprintf(p.Out, "  %s %s\n", output.Faint("Approver runs:"), approve)
+}
+
+// approvers names who can still approve a run under its approval rules, e.g. "alice, bob or 2 of group Release".
+func approvers(a *api.ApprovalInfo) string {
+	var who []string
+	if r := a.UserApprovalRule; r != nil && r.UserApprovals != nil {
+		var users []string
+		for _, ua := range r.UserApprovals.UserApproval {
+			if name := cmdutil.UserName(ua.User); name != "" && !ua.Approved {
+				users = append(users, name)
+			}
+		}
+		if len(users) > 0 {
+			list := strings.Join(users, ", ")
+			if r.RequiredApprovalsCount > 1 {
+				list = fmt.Sprintf("%d of %s", r.RequiredApprovalsCount, list)
+			}
+			who = append(who, list)
+		}
+	}
+	if r := a.GroupApprovalRule; r != nil && r.GroupApprovals != nil {
+		for _, ga := range r.GroupApprovals.GroupApproval {
+			if ga.Group == nil {
+				continue
+			}
+			who = append(who, fmt.Sprintf("%d of group %s", max(ga.RequiredApprovalsCount, 1), cmp.Or(ga.Group.Name, ga.Group.Key)))
+		}
+	}
+	return strings.Join(who, " or ")
+}
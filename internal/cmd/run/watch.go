@@ -2,8 +2,11 @@ package run
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,16 +14,23 @@ import (
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
 	"github.com/JetBrains/teamcity-cli/internal/cmd/run/tui"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/lockfile"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 type runWatchOptions struct {
+	job      string
 	interval int
 	logs     bool
 	quiet    bool
 	json     bool
 	timeout  time.Duration
+	// followDeps watches the run's whole snapshot-dependency chain; see doRunWatchChain.
+	followDeps bool
+	// singleton coordinates watchers of the same run so only one prints the final result summary.
+	singleton bool
+	hooks     watchHooks
 }
 
 var runWatchTUIFn = tui.RunWatchTUI
@@ -39,13 +49,55 @@ func newRunWatchCmd(f *cmdutil.Factory) *cobra.Command {
 Shows build status with periodic polling. Use --logs for a full-screen TUI
 with live log output.
 
-For a simpler, pipe-friendly log stream, use "teamcity run log --follow" instead.`,
+For a simpler, pipe-friendly log stream, use "teamcity run log --follow" instead.
+
+With --singleton-lock, watchers of the same run coordinate through a lock
+file: the first one prints the final result summary as usual, while the
+others note that another watcher owns it and end with a one-line result.
+Only the first runs --on-success/--on-failure and sends --notify. Every
+watcher still shows status and exits with the run's result code.
+A lock left by a watcher that crashed is taken over.
+
+--on-success and --on-failure run a shell command once the run finishes
+(--on-failure also covers canceled runs). The command sees the run as
+TC_RUN_ID, TC_RUN_NUMBER, TC_RUN_STATUS, TC_RUN_STATUS_TEXT, TC_RUN_JOB,
+TC_RUN_BRANCH, and TC_RUN_URL, and is killed after --hook-timeout. Hooks
+don't run when watching is interrupted or times out. The watch still exits
+with the run's result code unless --hook-exit-code is set.
+
+--notify shows a desktop notification with the job, number, status and
+duration once the run finishes (osascript on macOS, notify-send on Linux,
+a toast on Windows), and posts the run as JSON to the server's
+notify_webhook, if one is set with 'teamcity config set notify_webhook'.
+--notify-only-failure does the same for failed and canceled runs only.
+A notification that fails is a warning; it never changes the exit code.
+
+--follow-deps watches the run together with every run of its snapshot
+dependency chain, one status line each, and exits once all of them have
+finished: 0 when every run succeeded, 1 when any failed, 2 when none failed
+but some were canceled. With --json it prints a summary of the chain with
+each run's status and duration. Hooks see the watched run.`,
 		Args: cobra.ExactArgs(1),
 		Example: `  teamcity run watch 12345
   teamcity run watch 12345 --interval 10
-  teamcity run watch 12345 --logs`,
+  teamcity run watch 12345 --logs
+  teamcity run watch 12345 --singleton-lock
+  teamcity run watch 12345 --follow-deps
+  teamcity run watch 12345 --notify
+  teamcity run watch 12345 --on-failure 'notify-send "Run $TC_RUN_NUMBER failed" "$TC_RUN_URL"'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return doRunWatch(f, args[0], opts)
+			client, err := f.Client()
+			if err != nil {
+				return err
+			}
+			runID, err := resolveRunRef(f, client, args[0], opts.job)
+			if err != nil {
+				return err
+			}
+			if err := opts.hooks.validate(); err != nil {
+				return err
+			}
+			return doRunWatch(f, runID, opts)
 		},
 	}
 
@@ -54,6 +106,12 @@ For a simpler, pipe-friendly log stream, use "teamcity run log --follow" instead
 	cmd.Flags().BoolVar(&opts.quiet, "quiet", false, "Minimal output, show only state changes and result")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Wait for completion and output result as JSON")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Timeout duration (e.g., 30m, 1h)")
+	cmd.Flags().BoolVar(&opts.singleton, "singleton-lock", false, "Let only one watcher of this run print the final result summary")
+	cmd.Flags().BoolVar(&opts.followDeps, "follow-deps", false, "Also watch every run of the run's snapshot-dependency chain until all finish")
+	opts.hooks.addToCmd(cmd)
+	addRunJobFlag(cmd, &opts.job)
+	cmd.MarkFlagsMutuallyExclusive("follow-deps", "logs")
+	cmd.MarkFlagsMutuallyExclusive("follow-deps", "singleton-lock")
 	cmd.MarkFlagsMutuallyExclusive("quiet", "logs")
 	cmd.MarkFlagsMutuallyExclusive("json", "logs")
 	cmd.MarkFlagsMutuallyExclusive("json", "quiet")
@@ -62,6 +120,9 @@ For a simpler, pipe-friendly log stream, use "teamcity run log --follow" instead
 }
 
 func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr error) {
+	if opts.followDeps {
+		return doRunWatchChain(f, runID, opts)
+	}
 	p := f.Printer
 	if f.Quiet {
 		opts.quiet = true
@@ -75,6 +136,13 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 		return err
 	}
 
+	follower := false
+	if opts.singleton {
+		var release func()
+		release, follower = acquireWatchLock(p, client.ServerURL(), runID)
+		defer release()
+	}
+
 	topCtx := f.Context()
 	ctx := topCtx
 	if opts.timeout > 0 {
@@ -86,7 +154,7 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 	if opts.logs && !opts.quiet {
 		if watchHasTTYFn() {
 			tuiStart := time.Now()
-			tuiErr := runWatchTUIFn(ctx, client, runID, opts.interval)
+			tuiErr := runWatchTUIFn(ctx, client, runID, opts.interval, follower)
 			status := watchExitStatus(tuiErr)
 			// TUI returns nil even when the user quits early; treat any context cancel as canceled.
 			if errors.Is(ctx.Err(), context.Canceled) || errors.Is(topCtx.Err(), context.Canceled) {
@@ -98,7 +166,16 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 				"had_logs":         true,
 				"is_timed_out":     errors.Is(ctx.Err(), context.DeadlineExceeded),
 			})
-			return tuiErr
+			// A follower leaves the hook and notification to the watcher holding the lock, so they fire once.
+			if !opts.hooks.active() || follower || topCtx.Err() != nil || ctx.Err() != nil {
+				return tuiErr
+			}
+			// The TUI doesn't hand back the run; fetch it so a hook or notification only fires if it really finished.
+			build, err := client.GetBuild(topCtx, runID)
+			if err != nil || build.State != "finished" {
+				return tuiErr
+			}
+			return opts.hooks.run(topCtx, p, build, tuiErr, false)
 		}
 		p.Warn("--logs requires a TTY; falling back to standard watch mode")
 	}
@@ -112,6 +189,8 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 		case errors.Is(ctx.Err(), context.DeadlineExceeded):
 			timedOut = true
 			status = analytics.BuildStatusCanceled
+		case lastBuild != nil && cmdutil.RunCanceled(lastBuild):
+			status = analytics.BuildStatusCanceled
 		case lastBuild != nil && lastBuild.State == "finished":
 			status = buildFinalStatus(lastBuild.Status)
 		case resErr == nil || errors.Is(resErr, context.Canceled) || topCtx.Err() != nil:
@@ -140,6 +219,12 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 		resErr = nil
 	}()
 
+	defer func() {
+		if !follower && topCtx.Err() == nil && lastBuild != nil && (lastBuild.State == "finished" || cmdutil.RunCanceled(lastBuild)) {
+			resErr = opts.hooks.run(topCtx, p, lastBuild, resErr, opts.json)
+		}
+	}()
+
 	build, err := client.GetBuild(ctx, runID)
 	if err != nil {
 		return err
@@ -159,7 +244,9 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 	lastWaitReason := ""
 	lastPercent := 0
 	lastOvertimeMin := 0
+	lastLine := ""
 	var reachedComplete time.Time
+	redraw := redrawInPlace()
 	for {
 		select {
 		case <-ctx.Done():
@@ -179,10 +266,7 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 		}
 		lastBuild = build
 
-		jobName := build.BuildTypeID
-		if build.BuildType != nil {
-			jobName = build.BuildType.Name
-		}
+		jobName := cmdutil.JobName(build.BuildType, build.BuildTypeID)
 
 		switch {
 		case opts.json:
@@ -193,12 +277,12 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 				case "queued":
 					_, _ = fmt.Fprint(p.Out, "Queued")
 				case "running":
-					_, _ = fmt.Fprint(p.Out, "\rRunning")
+					_, _ = fmt.Fprint(p.Out, restartLine(redraw, lastState != "")+"Running")
 				}
 				lastState = build.State
 			}
 			if build.State == "queued" && build.WaitReason != "" && build.WaitReason != lastWaitReason {
-				_, _ = fmt.Fprintf(p.Out, "\rQueued (%s)", build.WaitReason)
+				_, _ = fmt.Fprintf(p.Out, restartLine(redraw, true)+"Queued (%s)", build.WaitReason)
 				lastWaitReason = build.WaitReason
 			}
 			if build.State == "running" {
@@ -230,7 +314,7 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 			if build.PercentageComplete > 0 {
 				progress = fmt.Sprintf(" (%d%%)", build.PercentageComplete)
 			}
-			_, _ = fmt.Fprintf(p.Out, "\r%s %s %d  #%s %s "+output.Sym().Sep+" %s%s    ",
+			line := fmt.Sprintf("%s %s %d  #%s %s "+output.Sym().Sep+" %s%s",
 				output.StatusIcon(build.Status, build.State, build.StatusText),
 				output.Cyan(jobName),
 				build.ID,
@@ -238,28 +322,34 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 				output.Faint(build.WebURL),
 				status,
 				progress)
+			switch {
+			case redraw:
+				_, _ = fmt.Fprint(p.Out, "\r"+line+"    ")
+			case line != lastLine:
+				_, _ = fmt.Fprintln(p.Out, line)
+			}
+			lastLine = line
 		}
 
-		if build.State == "finished" {
+		// A run someone stopped has canceledInfo while it winds down; stop watching then rather than when it finishes.
+		if build.State == "finished" || cmdutil.RunCanceled(build) {
 			if opts.json {
 				if err := p.PrintJSON(build); err != nil {
 					return err
 				}
-				switch build.Status {
-				case "SUCCESS":
-					return nil
-				case "FAILURE":
-					return &cmdutil.ExitError{Code: cmdutil.ExitFailure}
-				default:
-					return &cmdutil.ExitError{Code: cmdutil.ExitCancelled}
-				}
+				return cmdutil.BuildExitError(build)
 			}
 
-			_, _ = fmt.Fprintln(p.Out)
+			if redraw || opts.quiet {
+				_, _ = fmt.Fprintln(p.Out)
+			}
 			if !opts.quiet {
 				_, _ = fmt.Fprintln(p.Out)
 			}
 
+			if follower || build.State != "finished" {
+				return cmdutil.BuildResultBrief(p, build)
+			}
 			return cmdutil.BuildResultError(ctx, p, client, build, !opts.quiet)
 		}
 
@@ -277,6 +367,52 @@ func doRunWatch(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr
 	}
 }
 
+// watchLockPath names the --singleton-lock file for a run on a server.
+func watchLockPath(dir, serverURL, runID string) string {
+	sum := sha256.Sum256([]byte(serverURL + "\x00" + runID))
+	return filepath.Join(dir, "watch-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
+// acquireWatchLock takes the --singleton-lock for runID; follower is true when another watcher holds it.
+// A lock that can't be taken for any other reason is warned about, and the watch goes on uncoordinated.
+func acquireWatchLock(p *output.Printer, serverURL, runID string) (release func(), follower bool) {
+	release = func() {}
+	dir, err := lockfile.Dir()
+	if err == nil {
+		var lock *lockfile.Lock
+		if lock, err = lockfile.Acquire(watchLockPath(dir, serverURL, runID)); err == nil {
+			return func() { _ = lock.Release() }, false
+		}
+	}
+	if held, ok := errors.AsType[*lockfile.HeldError](err); ok {
+		if !p.Quiet {
+			_, _ = fmt.Fprintln(p.ErrOut, output.Faint(fmt.Sprintf("Another watcher (PID %d) owns the result summary for run #%s; showing status only", held.PID, runID)))
+		}
+		return release, true
+	}
+	p.Warn("--singleton-lock: %v; watching without coordination", err)
+	return release, false
+}
+
+// redrawInPlace reports whether watchers may redraw a status line in place. Consoles without VT support can't be
+// trusted to, so there each change is printed on its own line instead.
+func redrawInPlace() bool {
+	return output.VT
+}
+
+// restartLine returns the prefix that starts a new status: '\r' to overwrite the current line,
+// or a newline to keep it when in-place redraw is unavailable (nothing at all before the first status).
+func restartLine(redraw, printed bool) string {
+	switch {
+	case redraw:
+		return "\r"
+	case printed:
+		return "\n"
+	default:
+		return ""
+	}
+}
+
 // buildFinalStatus maps the TeamCity build Status string to the analytics wire enum.
 func buildFinalStatus(s string) string {
 	switch strings.ToLower(s) {
@@ -0,0 +1,328 @@
+package run
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+)
+
+// chainWatchWorkers caps how many chain runs are refreshed at once.
+const chainWatchWorkers = 8
+
+// chainWatchJSON is the --follow-deps --json summary. Status is SUCCESS only when every run of the chain succeeded.
+type chainWatchJSON struct {
+	RunID  int            `json:"run_id"`
+	Status string         `json:"status"`
+	Runs   []chainRunJSON `json:"runs"`
+}
+
+// chainRunJSON is one run of a watched chain; Duration is in seconds, from its start to its finish.
+type chainRunJSON struct {
+	ID         int    `json:"id"`
+	Number     string `json:"number,omitempty"`
+	Job        string `json:"job"`
+	JobName    string `json:"job_name,omitempty"`
+	State      string `json:"state"`
+	Status     string `json:"status,omitempty"`
+	StatusText string `json:"status_text,omitempty"`
+	Canceled   bool   `json:"canceled,omitempty"`
+	Duration   int    `json:"duration_seconds"`
+	WebURL     string `json:"web_url,omitempty"`
+}
+
+// discoverChain returns root and every run it snapshot-depends on, directly or not, in the order they run:
+// the deepest dependencies first and root last.
+func discoverChain(client api.ClientInterface, root *api.Build) ([]*api.Build, error) {
+	depth := map[int]int{root.ID: 0}
+	runs := map[int]*api.Build{root.ID: root}
+	pending := []int{root.ID}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		deps, err := client.GetBuildSnapshotDependencies(strconv.Itoa(id))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps.Builds {
+			// A run reached by a longer path runs earlier; keep its deepest position.
+			if seen, ok := depth[d.ID]; ok && seen >= depth[id]+1 {
+				continue
+			}
+			depth[d.ID] = depth[id] + 1
+			if _, ok := runs[d.ID]; !ok {
+				runs[d.ID] = &d
+			}
+			pending = append(pending, d.ID)
+		}
+	}
+
+	chain := make([]*api.Build, 0, len(runs))
+	for _, b := range runs {
+		chain = append(chain, b)
+	}
+	slices.SortFunc(chain, func(a, b *api.Build) int {
+		return cmp.Or(cmp.Compare(depth[b.ID], depth[a.ID]), cmp.Compare(a.ID, b.ID))
+	})
+	return chain, nil
+}
+
+// chainRunDone reports whether a chain run needs no more refreshing.
+func chainRunDone(b *api.Build) bool {
+	return b.State == "finished" || cmdutil.RunCanceled(b)
+}
+
+// refreshChain fetches every run of chain that has not finished yet, a few at a time; all fetches finished runs too,
+// since the dependency list carries only a few fields of each run.
+func refreshChain(ctx context.Context, f *cmdutil.Factory, client api.ClientInterface, chain []*api.Build, all bool) error {
+	errs := make([]error, len(chain))
+	limiter := f.NewLimiter(chainWatchWorkers)
+	var wg sync.WaitGroup
+	for i, b := range chain {
+		if !all && chainRunDone(b) {
+			continue
+		}
+		wg.Go(func() {
+			limiter.Acquire()
+			defer limiter.Release()
+			fresh, err := client.GetBuild(ctx, strconv.Itoa(b.ID))
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to refresh run %d: %w", b.ID, err)
+				return
+			}
+			chain[i] = fresh
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// chainRunLine is the status line of one chain run.
+func chainRunLine(b *api.Build) string {
+	var status string
+	switch {
+	case chainRunDone(b):
+		status = output.StatusText(b.Status, b.State, b.StatusText)
+	case b.State == "queued":
+		status = output.Faint("Queued")
+		if b.WaitReason != "" {
+			status = output.Faint("Queued " + output.Sym().Sep + " " + b.WaitReason)
+		}
+	default:
+		status = output.Yellow("Running")
+		if b.PercentageComplete > 0 {
+			status += fmt.Sprintf(" (%d%%)", b.PercentageComplete)
+		}
+	}
+	return fmt.Sprintf("%s %s %d  %s %s %s",
+		output.StatusIcon(b.Status, b.State, b.StatusText),
+		output.Cyan(cmdutil.JobName(b.BuildType, b.BuildTypeID)),
+		b.ID,
+		cmdutil.RunNumber(b.Number),
+		output.Sym().Sep,
+		status)
+}
+
+// chainExitError is the chain's result: a failure if any run failed, otherwise canceled if any run was.
+func chainExitError(chain []*api.Build) error {
+	var result error
+	for _, b := range chain {
+		switch err := cmdutil.BuildExitError(b); {
+		case err == nil:
+		case !cmdutil.RunCanceled(b):
+			return err
+		default:
+			result = err
+		}
+	}
+	return result
+}
+
+func chainSummaryJSON(root int, chain []*api.Build) chainWatchJSON {
+	summary := chainWatchJSON{RunID: root, Status: "SUCCESS", Runs: make([]chainRunJSON, len(chain))}
+	if chainExitError(chain) != nil {
+		summary.Status = "FAILURE"
+	}
+	for i, b := range chain {
+		run := chainRunJSON{
+			ID:         b.ID,
+			Number:     b.Number,
+			Job:        b.BuildTypeID,
+			State:      b.State,
+			Status:     b.Status,
+			StatusText: b.StatusText,
+			Canceled:   cmdutil.RunCanceled(b),
+			WebURL:     b.WebURL,
+		}
+		if b.BuildType != nil {
+			run.JobName = b.BuildType.Name
+		}
+		start, startErr := api.ParseTeamCityTime(b.StartDate)
+		finish, finishErr := api.ParseTeamCityTime(b.FinishDate)
+		if startErr == nil && finishErr == nil {
+			run.Duration = int(finish.Sub(start).Seconds())
+		}
+		summary.Runs[i] = run
+	}
+	return summary
+}
+
+// doRunWatchChain is doRunWatch for --follow-deps: it watches runID and every run of its snapshot-dependency
+// chain with one status line each, until all of them finish.
+func doRunWatchChain(f *cmdutil.Factory, runID string, opts *runWatchOptions) (resErr error) {
+	p := f.Printer
+	if f.Quiet {
+		opts.quiet = true
+	}
+	if opts.interval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second, got %d", opts.interval)
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	topCtx := f.Context()
+	ctx := topCtx
+	if opts.timeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, opts.timeout)
+		defer timeoutCancel()
+	}
+
+	root, err := client.GetBuild(ctx, runID)
+	if err != nil {
+		return err
+	}
+	chain, err := discoverChain(client, root)
+	if err != nil {
+		return fmt.Errorf("failed to discover the dependency chain of #%s: %w", runID, err)
+	}
+
+	defer func() {
+		if topCtx.Err() == nil {
+			return
+		}
+		if !opts.quiet && !opts.json {
+			_, _ = fmt.Fprintln(p.Out)
+			_, _ = fmt.Fprintln(p.Out, output.Faint("Interrupted. Runs continue in background."))
+			p.Tip("%s", output.TipResumeWatchFor(runID))
+		}
+		resErr = nil
+	}()
+
+	if !opts.json {
+		p.Info("Watching run #%s and %s... %s\n", runID, english.Plural(len(chain)-1, "dependency", "dependencies"),
+			output.Faint("(Ctrl-C to stop watching)"))
+	}
+
+	redraw := redrawInPlace() && !opts.quiet
+	lastLines := map[int]string{}
+	drawn := 0
+	for first := true; ; first = false {
+		if err := refreshChain(ctx, f, client, chain, first); err != nil && ctx.Err() == nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if !opts.json {
+			if redraw && drawn > 0 {
+				_, _ = fmt.Fprintf(p.Out, "\033[%dA", drawn)
+			}
+			drawn = 0
+			for _, b := range chain {
+				line := chainRunLine(b)
+				if opts.quiet {
+					// Only state changes: the icon and status, not the progress.
+					line = fmt.Sprintf("%s %d  %s", cmdutil.JobName(b.BuildType, b.BuildTypeID), b.ID, chainRunState(b))
+				}
+				switch {
+				case redraw:
+					_, _ = fmt.Fprint(p.Out, "\r"+line+"\033[K\n")
+					drawn++
+				case line != lastLines[b.ID]:
+					_, _ = fmt.Fprintln(p.Out, line)
+				}
+				lastLines[b.ID] = line
+			}
+		}
+
+		if !slices.ContainsFunc(chain, func(b *api.Build) bool { return !chainRunDone(b) }) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(opts.interval) * time.Second):
+		}
+	}
+
+	if topCtx.Err() != nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if !opts.json {
+			_, _ = fmt.Fprintf(p.Out, "\n%s Timeout exceeded\n", output.Red(output.Sym().Cross))
+		}
+		return &cmdutil.ExitError{Code: cmdutil.ExitTimeout}
+	}
+
+	resErr = chainExitError(chain)
+	if opts.json {
+		if err := p.PrintJSON(chainSummaryJSON(root.ID, chain)); err != nil {
+			return err
+		}
+	} else {
+		printChainResult(p, chain)
+	}
+	// Root runs last, so it is the last of the chain; hooks see it.
+	return opts.hooks.run(topCtx, p, chain[len(chain)-1], resErr, opts.json)
+}
+
+// chainRunState is a chain run's state in words, for --quiet.
+func chainRunState(b *api.Build) string {
+	switch {
+	case cmdutil.RunCanceled(b):
+		return "canceled"
+	case b.State == "finished" && b.Status == "SUCCESS":
+		return "succeeded"
+	case b.State == "finished":
+		return "failed"
+	}
+	return b.State
+}
+
+// printChainResult prints how the chain ended: one line when every run succeeded, otherwise each run that did not.
+func printChainResult(p *output.Printer, chain []*api.Build) {
+	_, _ = fmt.Fprintln(p.Out)
+	var unsuccessful []*api.Build
+	for _, b := range chain {
+		if cmdutil.BuildExitError(b) != nil {
+			unsuccessful = append(unsuccessful, b)
+		}
+	}
+	if len(unsuccessful) == 0 {
+		_, _ = fmt.Fprintf(p.Out, "%s All %s of the chain succeeded\n", output.Green(output.Sym().Check), english.Plural(len(chain), "run", ""))
+		return
+	}
+	for _, b := range unsuccessful {
+		_ = cmdutil.BuildResultBrief(p, b)
+	}
+	_, _ = fmt.Fprintf(p.Out, "\n%s %d of %s of the chain did not succeed\n",
+		output.Red(output.Sym().Cross), len(unsuccessful), english.Plural(len(chain), "run", ""))
+	if failed := slices.IndexFunc(unsuccessful, func(b *api.Build) bool { return !cmdutil.RunCanceled(b) }); failed >= 0 {
+		p.Tip("See why: teamcity run log %d --failed", unsuccessful[failed].ID)
+	}
+}
@@ -0,0 +1,138 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// watchHooks holds the --on-success/--on-failure commands run when a watched run finishes.
+type watchHooks struct {
+	onSuccess string
+	onFailure string
+	timeout   time.Duration
+	// exitCode makes the command exit with the hook's exit code instead of the run's.
+	exitCode bool
+	notify   watchNotify
+}
+
+// addToCmd registers the hook flags on a cobra command.
+func (h *watchHooks) addToCmd(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&h.onSuccess, "on-success", "", "Shell command to run when the watched run succeeds")
+	cmd.Flags().StringVar(&h.onFailure, "on-failure", "", "Shell command to run when the watched run fails or is canceled")
+	cmd.Flags().DurationVar(&h.timeout, "hook-timeout", 5*time.Minute, "Kill the --on-success/--on-failure command after this duration")
+	cmd.Flags().BoolVar(&h.exitCode, "hook-exit-code", false, "Exit with the hook's exit code instead of the run's")
+	h.notify.addToCmd(cmd)
+}
+
+// set reports whether any hook command was given.
+func (h *watchHooks) set() bool {
+	return h.onSuccess != "" || h.onFailure != ""
+}
+
+// active reports whether anything waits for the run to finish: a hook command or a notification.
+func (h *watchHooks) active() bool {
+	return h.set() || h.notify.set()
+}
+
+// command picks the hook for a finished run: --on-success for SUCCESS, --on-failure for anything else, cancels included.
+func (h *watchHooks) command(build *api.Build) string {
+	if build.Status == "SUCCESS" && !cmdutil.RunCanceled(build) {
+		return h.onSuccess
+	}
+	return h.onFailure
+}
+
+// hookShellFn returns the shell invocation for a hook command; a variable so tests can swap it.
+var hookShellFn = func(command string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
+
+// hookEnv describes the finished run to the hook as TC_RUN_* environment variables.
+func hookEnv(build *api.Build) []string {
+	return []string{
+		"TC_RUN_ID=" + strconv.Itoa(build.ID),
+		"TC_RUN_NUMBER=" + build.Number,
+		"TC_RUN_STATUS=" + build.Status,
+		"TC_RUN_STATUS_TEXT=" + build.StatusText,
+		"TC_RUN_JOB=" + build.BuildTypeID,
+		"TC_RUN_BRANCH=" + build.BranchName,
+		"TC_RUN_URL=" + build.WebURL,
+	}
+}
+
+// run sends the notification and executes the hook matching build, if any, and returns the error the command should exit with.
+// watchErr is the watch's own result; it stands unless --hook-exit-code is set and a hook ran.
+// Hook output goes to stderr when stdout carries JSON.
+func (h *watchHooks) run(ctx context.Context, p *output.Printer, build *api.Build, watchErr error, jsonOut bool) error {
+	h.notify.send(ctx, p, build)
+	command := h.command(build)
+	if command == "" {
+		return watchErr
+	}
+
+	hookCtx := ctx
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	name, args := hookShellFn(command)
+	cmd := exec.CommandContext(hookCtx, name, args...)
+	cmd.Env = append(os.Environ(), hookEnv(build)...)
+	var stdout io.Writer = p.Out
+	if jsonOut {
+		stdout = p.ErrOut
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, stdout, p.ErrOut
+	// Children the shell left behind can hold the output pipes open after it is killed.
+	cmd.WaitDelay = time.Second
+
+	code := 0
+	err := cmd.Run()
+	switch exitErr, isExit := errors.AsType[*exec.ExitError](err); {
+	case err == nil:
+	case errors.Is(hookCtx.Err(), context.DeadlineExceeded):
+		p.Warn("hook timed out after %s: %s", h.timeout, command)
+		code = cmdutil.ExitTimeout
+	case isExit:
+		code = exitErr.ExitCode()
+		p.Warn("hook exited with code %d: %s", code, command)
+	default:
+		p.Warn("hook failed to start: %v", err)
+		code = cmdutil.ExitFailure
+	}
+
+	if !h.exitCode {
+		return watchErr
+	}
+	if code == 0 {
+		return nil
+	}
+	return &cmdutil.ExitError{Code: code}
+}
+
+// validate rejects hook flag values and combinations that can't work.
+func (h *watchHooks) validate() error {
+	if h.timeout < 0 {
+		return fmt.Errorf("--hook-timeout must not be negative, got %s", h.timeout)
+	}
+	if h.exitCode && !h.set() {
+		return fmt.Errorf("--hook-exit-code requires --on-success or --on-failure")
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// notifyTimeout bounds each notification, so a stuck notifier or webhook can't hold the command after the run finished.
+const notifyTimeout = 10 * time.Second
+
+// watchNotify holds --notify/--notify-only-failure: a desktop notification, plus a post to the server's notify_webhook,
+// when a watched run finishes.
+type watchNotify struct {
+	enabled     bool
+	onlyFailure bool
+}
+
+// addToCmd registers the notification flags on a cobra command.
+func (n *watchNotify) addToCmd(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&n.enabled, "notify", false, "Show a desktop notification, and post to the server's notify_webhook, when the run finishes")
+	cmd.Flags().BoolVar(&n.onlyFailure, "notify-only-failure", false, "Like --notify, but only when the run fails or is canceled")
+}
+
+// set reports whether a notification was asked for.
+func (n *watchNotify) set() bool {
+	return n.enabled || n.onlyFailure
+}
+
+// runNotification is what a notification says about a finished run; it is also the notify_webhook payload.
+type runNotification struct {
+	Text            string `json:"text"`
+	ID              int    `json:"id"`
+	Number          string `json:"number,omitempty"`
+	Job             string `json:"job"`
+	JobName         string `json:"job_name,omitempty"`
+	Status          string `json:"status"`
+	StatusText      string `json:"status_text,omitempty"`
+	Branch          string `json:"branch,omitempty"`
+	DurationSeconds int    `json:"duration_seconds"`
+	URL             string `json:"url,omitempty"`
+	title           string
+}
+
+func newRunNotification(build *api.Build) runNotification {
+	status := build.Status
+	if cmdutil.RunCanceled(build) {
+		status = "CANCELED"
+	}
+	jobName := cmdutil.JobName(build.BuildType, build.BuildTypeID)
+	duration := buildDuration(build)
+	title := fmt.Sprintf("%s #%s %s", jobName, build.Number, status)
+	text := title
+	if duration > 0 {
+		text += " in " + output.FormatDuration(duration)
+	}
+	if build.StatusText != "" && build.StatusText != build.Status {
+		text += ": " + build.StatusText
+	}
+	n := runNotification{
+		Text:            text,
+		ID:              build.ID,
+		Number:          build.Number,
+		Job:             build.BuildTypeID,
+		Status:          status,
+		StatusText:      build.StatusText,
+		Branch:          build.BranchName,
+		DurationSeconds: int(duration.Seconds()),
+		URL:             build.WebURL,
+		title:           title,
+	}
+	if jobName != build.BuildTypeID {
+		n.JobName = jobName
+	}
+	return n
+}
+
+// desktopNotifyFn shows a desktop notification with the platform's own tool; a variable so tests can swap it.
+var desktopNotifyFn = func(ctx context.Context, title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv", "-e", "display notification (item 2 of argv) with title (item 1 of argv)", "-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "TC_NOTIFY_TITLE="+title, "TC_NOTIFY_BODY="+body)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=TeamCity", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if len(out) > 0 {
+			return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+		}
+		return err
+	}
+	return nil
+}
+
+// windowsToastScript shows a toast with the title and body from the environment, which spares quoting them.
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode($env:TC_NOTIFY_TITLE)) > $null
+$x.Item(1).AppendChild($t.CreateTextNode($env:TC_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('TeamCity CLI').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+// postNotifyWebhook posts n as JSON to url.
+func postNotifyWebhook(ctx context.Context, url string, n runNotification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// send notifies about a finished run, if asked to. Failures are warnings: they never change how the command exits.
+func (n *watchNotify) send(ctx context.Context, p *output.Printer, build *api.Build) {
+	if !n.set() {
+		return
+	}
+	if n.onlyFailure && !n.enabled && build.Status == "SUCCESS" && !cmdutil.RunCanceled(build) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	msg := newRunNotification(build)
+	body := msg.Text
+	if msg.URL != "" {
+		body += "\n" + msg.URL
+	}
+	if err := desktopNotifyFn(ctx, msg.title, body); err != nil {
+		p.Warn("desktop notification failed: %v", err)
+	}
+	if url := config.NotifyWebhook(); url != "" {
+		if err := postNotifyWebhook(ctx, url, msg); err != nil {
+			p.Warn("notify_webhook failed: %v", err)
+		}
+	}
+}
@@ -1,15 +1,25 @@
 package run
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 )
 
@@ -57,7 +67,7 @@ func TestDoRunWatchLogsFallsBackWithoutTTY(t *testing.T) {
 	}
 
 	tuiCalled := false
-	runWatchTUIFn = func(_ context.Context, client api.ClientInterface, runID string, interval int) error {
+	runWatchTUIFn = func(_ context.Context, client api.ClientInterface, runID string, interval int, _ bool) error {
 		tuiCalled = true
 		return errors.New("runWatchTUI should not be called without TTY")
 	}
@@ -154,6 +164,80 @@ func TestDoRunWatchJSONReturnsExitErrorOnFailure(t *testing.T) {
 	}
 }
 
+// canceledRunServer serves run 321 as running, then as running with canceledInfo, as TeamCity does while a
+// run stopped from the web UI winds down; it counts the polls.
+func canceledRunServer(t *testing.T, polls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app/rest/builds/id:321" {
+			http.NotFound(w, r)
+			return
+		}
+		build := api.Build{ID: 321, Number: "12", BuildTypeID: "App_Deploy", State: "running", Status: "SUCCESS"}
+		if polls.Add(1) > 2 {
+			build.CanceledInfo = &api.CanceledInfo{
+				User:      &api.User{Username: "jdoe", Name: "Jane Doe"},
+				Timestamp: "20260101T120000+0000",
+				Text:      "Deploying the wrong branch",
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(build)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestDoRunWatchStopsOnServerCancel(t *testing.T) {
+	var polls atomic.Int32
+	ts := canceledRunServer(t, &polls)
+	var out bytes.Buffer
+	f := &cmdutil.Factory{
+		Printer:    &output.Printer{Out: &out, ErrOut: &out},
+		ClientFunc: func() (api.ClientInterface, error) { return api.NewClient(ts.URL, "test-token"), nil },
+	}
+
+	err := doRunWatch(f, "321", &runWatchOptions{interval: 1})
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	if !ok || exitErr.Code != cmdutil.ExitCancelled {
+		t.Fatalf("expected exit code %d, got %v", cmdutil.ExitCancelled, err)
+	}
+	if got := polls.Load(); got != 3 {
+		t.Errorf("watch should stop at the first poll with canceledInfo, polled %d times", got)
+	}
+	for _, want := range []string{"App_Deploy 321  #12 canceled by Jane Doe", "Deploying the wrong branch"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "failed") || strings.Contains(out.String(), "succeeded") {
+		t.Errorf("a canceled run is reported as canceled only:\n%s", out.String())
+	}
+}
+
+func TestDoRunWatchJSONReturnsCancelExitCode(t *testing.T) {
+	var polls atomic.Int32
+	ts := canceledRunServer(t, &polls)
+	var out bytes.Buffer
+	f := &cmdutil.Factory{
+		Printer:    &output.Printer{Out: &out, ErrOut: &out},
+		ClientFunc: func() (api.ClientInterface, error) { return api.NewClient(ts.URL, "test-token"), nil },
+	}
+
+	err := doRunWatch(f, "321", &runWatchOptions{interval: 1, json: true})
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	if !ok || exitErr.Code != cmdutil.ExitCancelled {
+		t.Fatalf("expected exit code %d, got %v", cmdutil.ExitCancelled, err)
+	}
+	var build api.Build
+	if err := json.Unmarshal(out.Bytes(), &build); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if build.CanceledInfo == nil || build.CanceledInfo.User.Name != "Jane Doe" {
+		t.Errorf("JSON should carry canceledInfo, got %+v", build.CanceledInfo)
+	}
+}
+
 func TestDoRunWatchLogsUsesTUIWhenTTYIsAvailable(t *testing.T) {
 	origRunWatchTUIFn := runWatchTUIFn
 	origWatchHasTTYFn := watchHasTTYFn
@@ -171,7 +255,7 @@ func TestDoRunWatchLogsUsesTUIWhenTTYIsAvailable(t *testing.T) {
 
 	sentinelErr := errors.New("tui path reached")
 	tuiCalled := false
-	runWatchTUIFn = func(_ context.Context, client api.ClientInterface, runID string, interval int) error {
+	runWatchTUIFn = func(_ context.Context, client api.ClientInterface, runID string, interval int, _ bool) error {
 		tuiCalled = true
 		if runID != "123" {
 			t.Fatalf("unexpected runID: %s", runID)
@@ -191,3 +275,449 @@ func TestDoRunWatchLogsUsesTUIWhenTTYIsAvailable(t *testing.T) {
 		t.Fatal("expected runWatchTUI to be called when TTY is available")
 	}
 }
+
+func TestDoRunWatchPrintsFullLinesWithoutVT(t *testing.T) {
+	origVT := output.VT
+	t.Cleanup(func() { output.VT = origVT })
+	output.VT = false
+
+	// The first response is the initial lookup; polls then see queued twice before the run finishes.
+	states := []string{"queued", "queued", "queued", "finished"}
+	poll := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/app/rest/builds/id:321" {
+			build := api.Build{ID: 321, Number: "9", BuildTypeID: "Legacy", WebURL: "https://example.invalid/build/321", State: states[min(poll, len(states)-1)]}
+			if build.State == "finished" {
+				build.Status = "SUCCESS"
+			}
+			poll++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(build)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	f := &cmdutil.Factory{
+		Printer: &output.Printer{Out: &out, ErrOut: &bytes.Buffer{}},
+		ClientFunc: func() (api.ClientInterface, error) {
+			return api.NewClient(ts.URL, "test-token"), nil
+		},
+	}
+
+	if err := doRunWatch(f, "321", &runWatchOptions{interval: 1}); err != nil {
+		t.Fatalf("doRunWatch returned error: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "\r") {
+		t.Fatalf("expected no carriage returns without VT, got %q", got)
+	}
+	if n := strings.Count(got, "Queued\n"); n != 1 {
+		t.Fatalf("expected the unchanged queued line once, got %d in %q", n, got)
+	}
+	if !strings.Contains(got, "Running\n\n") {
+		t.Fatalf("expected final status on its own line, got %q", got)
+	}
+}
+
+func TestRestartLine(t *testing.T) {
+	tests := []struct {
+		redraw, printed bool
+		want            string
+	}{
+		{redraw: true, want: "\r"},
+		{redraw: true, printed: true, want: "\r"},
+		{printed: true, want: "\n"},
+		{want: ""},
+	}
+	for _, tc := range tests {
+		if got := restartLine(tc.redraw, tc.printed); got != tc.want {
+			t.Errorf("restartLine(%v, %v) = %q, want %q", tc.redraw, tc.printed, got, tc.want)
+		}
+	}
+}
+
+func TestDoRunWatchSingletonLock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/app/rest/builds/id:555" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.Build{
+				ID:          555,
+				Number:      "3",
+				BuildTypeID: "Deploy",
+				WebURL:      "https://example.invalid/build/555",
+				State:       "finished",
+				Status:      "FAILURE",
+			})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	lockPath := watchLockPath(filepath.Join(dir, "tc"), ts.URL, "555")
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	watch := func() (stdout, stderr string, err error) {
+		var out, errOut bytes.Buffer
+		f := &cmdutil.Factory{
+			Printer: &output.Printer{Out: &out, ErrOut: &errOut},
+			ClientFunc: func() (api.ClientInterface, error) {
+				return api.NewClient(ts.URL, "test-token"), nil
+			},
+		}
+		err = doRunWatch(f, "555", &runWatchOptions{interval: 1, singleton: true, hooks: watchHooks{notify: watchNotify{enabled: true}}})
+		return out.String(), errOut.String(), err
+	}
+	notified := 0
+	orig := desktopNotifyFn
+	desktopNotifyFn = func(context.Context, string, string) error {
+		notified++
+		return nil
+	}
+	t.Cleanup(func() { desktopNotifyFn = orig })
+	assertFailure := func(err error) {
+		t.Helper()
+		if exitErr, ok := errors.AsType[*cmdutil.ExitError](err); !ok || exitErr.Code != cmdutil.ExitFailure {
+			t.Fatalf("expected exit code %d, got: %v", cmdutil.ExitFailure, err)
+		}
+	}
+
+	stdout, stderr, err := watch()
+	assertFailure(err)
+	if !strings.Contains(stdout, "View details: https://example.invalid/build/555") || strings.Contains(stderr, "Another watcher") {
+		t.Fatalf("the lock owner should print the full summary, got stdout %q, stderr %q", stdout, stderr)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the lock to be released, stat returned %v", err)
+	}
+	if notified != 1 {
+		t.Fatalf("the lock owner should notify once, got %d", notified)
+	}
+
+	// A live process (this one) holding the lock makes the watcher a follower.
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getpid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, err = watch()
+	assertFailure(err)
+	if !strings.Contains(stderr, fmt.Sprintf("Another watcher (PID %d) owns the result summary for run #555", os.Getpid())) {
+		t.Fatalf("expected the follower note, got %q", stderr)
+	}
+	if !strings.Contains(stdout, "#3 failed") || strings.Contains(stdout, "View details") {
+		t.Fatalf("expected only a one-line result from the follower, got %q", stdout)
+	}
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("the follower must leave the owner's lock in place: %v", err)
+	}
+	if notified != 1 {
+		t.Fatalf("the follower must leave notifications to the owner, got %d in all", notified)
+	}
+}
+
+func TestWatchLockPath(t *testing.T) {
+	a := watchLockPath("/locks", "https://tc.example.com", "1")
+	if filepath.Dir(a) != "/locks" || !strings.HasPrefix(filepath.Base(a), "watch-") || !strings.HasSuffix(a, ".lock") {
+		t.Fatalf("unexpected lock path %q", a)
+	}
+	if a != watchLockPath("/locks", "https://tc.example.com", "1") {
+		t.Fatal("the lock path must be stable")
+	}
+	if a == watchLockPath("/locks", "https://tc.example.com", "12") || a == watchLockPath("/locks", "https://other.example.com", "1") {
+		t.Fatal("the lock path must be keyed by server and run")
+	}
+}
+
+func TestDoRunWatchHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are sh scripts")
+	}
+
+	// lifecycle serves run 900 as running for the first poll, then finished with status.
+	lifecycle := func(t *testing.T, status string, onRequest func()) string {
+		t.Helper()
+		var polls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/app/rest/builds/id:900" {
+				http.NotFound(w, r)
+				return
+			}
+			if onRequest != nil {
+				onRequest()
+			}
+			build := api.Build{ID: 900, Number: "12", BuildTypeID: "Deploy", BranchName: "main", WebURL: "https://example.invalid/build/900", State: "running"}
+			if polls.Add(1) > 1 {
+				build.State, build.Status = "finished", status
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(build)
+		}))
+		t.Cleanup(ts.Close)
+		return ts.URL
+	}
+	watch := func(ctx context.Context, url string, hooks watchHooks) (string, error) {
+		var out bytes.Buffer
+		f := &cmdutil.Factory{
+			Printer: &output.Printer{Out: &out, ErrOut: &out},
+			ClientFunc: func() (api.ClientInterface, error) {
+				return api.NewClient(url, "test-token"), nil
+			},
+		}
+		f.SetContext(ctx)
+		err := doRunWatch(f, "900", &runWatchOptions{interval: 1, quiet: true, hooks: hooks})
+		return out.String(), err
+	}
+	exitCode := func(err error) int {
+		if exitErr, ok := errors.AsType[*cmdutil.ExitError](err); ok {
+			return exitErr.Code
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return 0
+	}
+
+	t.Run("on-failure sees the run", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "hook.out")
+		_, err := watch(t.Context(), lifecycle(t, "FAILURE", nil), watchHooks{
+			onSuccess: "echo wrong hook > " + marker,
+			onFailure: `echo "$TC_RUN_ID $TC_RUN_NUMBER $TC_RUN_STATUS $TC_RUN_JOB $TC_RUN_BRANCH $TC_RUN_URL" > ` + marker,
+			timeout:   time.Minute,
+		})
+		if code := exitCode(err); code != cmdutil.ExitFailure {
+			t.Fatalf("expected the run's exit code %d, got %d", cmdutil.ExitFailure, code)
+		}
+		got, readErr := os.ReadFile(marker)
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		if want := "900 12 FAILURE Deploy main https://example.invalid/build/900\n"; string(got) != want {
+			t.Fatalf("hook env = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("hook exit code only with --hook-exit-code", func(t *testing.T) {
+		out, err := watch(t.Context(), lifecycle(t, "SUCCESS", nil), watchHooks{onSuccess: "exit 3", timeout: time.Minute})
+		if code := exitCode(err); code != 0 {
+			t.Fatalf("expected the run's exit code 0, got %d", code)
+		}
+		if !strings.Contains(out, "hook exited with code 3") {
+			t.Fatalf("expected a warning about the hook, got %q", out)
+		}
+
+		_, err = watch(t.Context(), lifecycle(t, "SUCCESS", nil), watchHooks{onSuccess: "exit 3", timeout: time.Minute, exitCode: true})
+		if code := exitCode(err); code != 3 {
+			t.Fatalf("expected the hook's exit code 3, got %d", code)
+		}
+		_, err = watch(t.Context(), lifecycle(t, "FAILURE", nil), watchHooks{onFailure: "true", timeout: time.Minute, exitCode: true})
+		if code := exitCode(err); code != 0 {
+			t.Fatalf("expected the hook's exit code 0 to replace the run's, got %d", code)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := watch(t.Context(), lifecycle(t, "SUCCESS", nil), watchHooks{onSuccess: "sleep 5", timeout: 100 * time.Millisecond, exitCode: true})
+		if code := exitCode(err); code != cmdutil.ExitTimeout {
+			t.Fatalf("expected exit code %d for a timed-out hook, got %d", cmdutil.ExitTimeout, code)
+		}
+	})
+
+	t.Run("not on interrupt", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "hook.out")
+		ctx, cancel := context.WithCancel(t.Context())
+		var requests atomic.Int32
+		url := lifecycle(t, "FAILURE", func() {
+			if requests.Add(1) == 2 {
+				cancel()
+			}
+		})
+		_, err := watch(ctx, url, watchHooks{onFailure: "touch " + marker, timeout: time.Minute, exitCode: true})
+		if err != nil {
+			t.Fatalf("an interrupted watch should exit cleanly, got %v", err)
+		}
+		if _, statErr := os.Stat(marker); !os.IsNotExist(statErr) {
+			t.Fatalf("the hook must not run on interrupt, stat returned %v", statErr)
+		}
+	})
+}
+
+func TestDoRunWatchNotify(t *testing.T) {
+	// serve answers run 900 as finished with status, and records what the webhook receives.
+	serve := func(t *testing.T, status string, webhookStatus int) (string, *[]runNotification) {
+		t.Helper()
+		var posted []runNotification
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/hook":
+				var n runNotification
+				_ = json.NewDecoder(r.Body).Decode(&n)
+				posted = append(posted, n)
+				w.WriteHeader(webhookStatus)
+			case r.Method == http.MethodGet && r.URL.Path == "/app/rest/builds/id:900":
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(api.Build{
+					ID: 900, Number: "12", BuildTypeID: "Deploy", BuildType: &api.BuildType{Name: "Deploy to prod"},
+					State: "finished", Status: status, StatusText: "Tests failed: 2",
+					StartDate: "20250101T100000+0000", FinishDate: "20250101T100230+0000",
+					WebURL: "https://example.invalid/build/900",
+				})
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		t.Cleanup(ts.Close)
+		config.ResetForTest()
+		t.Cleanup(config.ResetForTest)
+		t.Setenv(config.EnvServerURL, ts.URL)
+		config.Get().Servers[config.NormalizeURL(ts.URL)] = config.ServerConfig{NotifyWebhook: ts.URL + "/hook"}
+		return ts.URL, &posted
+	}
+	watch := func(url string, notify watchNotify) (string, error) {
+		var out bytes.Buffer
+		f := &cmdutil.Factory{
+			Printer: &output.Printer{Out: &out, ErrOut: &out},
+			ClientFunc: func() (api.ClientInterface, error) {
+				return api.NewClient(url, "test-token"), nil
+			},
+		}
+		f.SetContext(t.Context())
+		err := doRunWatch(f, "900", &runWatchOptions{interval: 1, quiet: true, hooks: watchHooks{notify: notify}})
+		return out.String(), err
+	}
+	var desktop []string
+	desktopErr := error(nil)
+	orig := desktopNotifyFn
+	desktopNotifyFn = func(_ context.Context, title, body string) error {
+		desktop = append(desktop, title+"|"+body)
+		return desktopErr
+	}
+	t.Cleanup(func() { desktopNotifyFn = orig })
+
+	t.Run("desktop and webhook", func(t *testing.T) {
+		desktop = nil
+		url, posted := serve(t, "FAILURE", http.StatusOK)
+		_, err := watch(url, watchNotify{enabled: true})
+		if exitErr, ok := errors.AsType[*cmdutil.ExitError](err); !ok || exitErr.Code != cmdutil.ExitFailure {
+			t.Fatalf("expected the run's exit code, got %v", err)
+		}
+		want := "Deploy to prod #12 FAILURE|Deploy to prod #12 FAILURE in 2m 30s: Tests failed: 2\nhttps://example.invalid/build/900"
+		if len(desktop) != 1 || desktop[0] != want {
+			t.Fatalf("desktop notifications = %q, want %q", desktop, want)
+		}
+		if len(*posted) != 1 {
+			t.Fatalf("expected one webhook post, got %d", len(*posted))
+		}
+		got := (*posted)[0]
+		if got.ID != 900 || got.Job != "Deploy" || got.JobName != "Deploy to prod" || got.Status != "FAILURE" || got.DurationSeconds != 150 {
+			t.Fatalf("unexpected webhook payload %+v", got)
+		}
+	})
+
+	t.Run("only failure skips success", func(t *testing.T) {
+		desktop = nil
+		url, posted := serve(t, "SUCCESS", http.StatusOK)
+		if _, err := watch(url, watchNotify{onlyFailure: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(desktop) != 0 || len(*posted) != 0 {
+			t.Fatalf("expected no notification for a successful run, got %q and %d posts", desktop, len(*posted))
+		}
+	})
+
+	t.Run("failures don't change the exit code", func(t *testing.T) {
+		desktop, desktopErr = nil, errors.New("notify-send not found")
+		t.Cleanup(func() { desktopErr = nil })
+		url, _ := serve(t, "SUCCESS", http.StatusInternalServerError)
+		out, err := watch(url, watchNotify{enabled: true})
+		if err != nil {
+			t.Fatalf("expected a successful run to exit 0 despite failed notifications, got %v", err)
+		}
+		if !strings.Contains(out, "desktop notification failed: notify-send not found") || !strings.Contains(out, "notify_webhook failed: webhook returned 500") {
+			t.Fatalf("expected warnings about both notifications, got %q", out)
+		}
+	})
+}
+
+func TestWatchHooksValidate(t *testing.T) {
+	if err := (&watchHooks{exitCode: true}).validate(); err == nil {
+		t.Fatal("expected --hook-exit-code without a hook to be rejected")
+	}
+	if err := (&watchHooks{onSuccess: "true", timeout: -time.Second}).validate(); err == nil {
+		t.Fatal("expected a negative --hook-timeout to be rejected")
+	}
+	if err := (&watchHooks{onFailure: "true", exitCode: true}).validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDoRunWatchChain(t *testing.T) {
+	// Run 10 depends on 11 and 12, and 11 on 12 too; 12 fails on its second poll.
+	deps := map[string][]api.Build{"10": {{ID: 11}, {ID: 12}}, "11": {{ID: 12}}}
+	var polls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/app/rest/builds" {
+			locator := r.URL.Query().Get("locator")
+			var list []api.Build
+			for id, builds := range deps {
+				if strings.Contains(locator, "(id:"+id+")") {
+					list = builds
+				}
+			}
+			_ = json.NewEncoder(w).Encode(api.BuildList{Count: len(list), Builds: list})
+			return
+		}
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/app/rest/builds/id:"))
+		build := api.Build{
+			ID: id, Number: strconv.Itoa(id), BuildTypeID: fmt.Sprintf("Job%d", id), State: "finished", Status: "SUCCESS",
+			StartDate: "20260101T120000+0000", FinishDate: "20260101T120130+0000",
+		}
+		if id == 12 {
+			if polls.Add(1) < 2 {
+				build.State, build.Status, build.FinishDate = "running", "", ""
+			} else {
+				build.Status = "FAILURE"
+			}
+		}
+		_ = json.NewEncoder(w).Encode(build)
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	f := &cmdutil.Factory{
+		Printer:    &output.Printer{Out: &out, ErrOut: &out},
+		ClientFunc: func() (api.ClientInterface, error) { return api.NewClient(ts.URL, "test-token"), nil },
+	}
+
+	err := doRunWatch(f, "10", &runWatchOptions{interval: 1, json: true, followDeps: true})
+	exitErr, ok := errors.AsType[*cmdutil.ExitError](err)
+	if !ok || exitErr.Code != cmdutil.ExitFailure {
+		t.Fatalf("expected exit code %d, got: %v", cmdutil.ExitFailure, err)
+	}
+
+	var summary chainWatchJSON
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatalf("output is not a chain summary: %v\n%s", err, out.String())
+	}
+	if summary.RunID != 10 || summary.Status != "FAILURE" {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	var order []int
+	for _, run := range summary.Runs {
+		order = append(order, run.ID)
+		if run.Duration != 90 {
+			t.Errorf("run %d: expected a 90s duration, got %d", run.ID, run.Duration)
+		}
+	}
+	if fmt.Sprint(order) != "[12 11 10]" {
+		t.Fatalf("expected the chain in run order, got %v", order)
+	}
+	if summary.Runs[0].Status != "FAILURE" || summary.Runs[2].Status != "SUCCESS" {
+		t.Fatalf("unexpected statuses: %+v", summary.Runs)
+	}
+}
@@ -0,0 +1,155 @@
+package setting
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/spf13/cobra"
+)
+
+// valueKind is the type of value TeamCity accepts for a setting.
+type valueKind int
+
+const (
+	kindText valueKind = iota
+	kindInt
+	kindBool
+	kindEnum
+)
+
+// settingSpec describes one build configuration setting; values lists the choices of an enum setting.
+type settingSpec struct {
+	kind   valueKind
+	values []string
+}
+
+// knownSettings are the build configuration settings the REST API accepts under /buildTypes/{id}/settings.
+var knownSettings = map[string]settingSpec{
+	"allowExternalStatus":              {kind: kindBool},
+	"allowPersonalBuildTriggering":     {kind: kindBool},
+	"artifactRules":                    {kind: kindText},
+	"buildConfigurationType":           {kind: kindEnum, values: []string{"REGULAR", "COMPOSITE", "DEPLOYMENT"}},
+	"buildDefaultBranch":               {kind: kindBool},
+	"buildNumberCounter":               {kind: kindInt},
+	"buildNumberPattern":               {kind: kindText},
+	"checkoutDirectory":                {kind: kindText},
+	"checkoutMode":                     {kind: kindEnum, values: []string{"AUTO", "ON_AGENT", "ON_SERVER", "MANUAL"}},
+	"cleanBuild":                       {kind: kindBool},
+	"enableHangingBuildsDetection":     {kind: kindBool},
+	"excludeDefaultBranchChanges":      {kind: kindBool},
+	"executionTimeoutMin":              {kind: kindInt},
+	"maximumNumberOfBuilds":            {kind: kindInt},
+	"publishArtifactCondition":         {kind: kindEnum, values: []string{"NORMALLY_FINISHED", "SUCCESSFUL", "ALWAYS"}},
+	"shouldFailBuildIfTestsFailed":     {kind: kindBool},
+	"shouldFailBuildOnAnyErrorMessage": {kind: kindBool},
+	"shouldFailBuildOnBadExitCode":     {kind: kindBool},
+	"shouldFailBuildOnOOMEOrCrash":     {kind: kindBool},
+	"showDependenciesChanges":          {kind: kindBool},
+	"supportTestRetry":                 {kind: kindBool},
+	"vcsLabelingBranchFilter":          {kind: kindText},
+}
+
+// checkSettingName rejects a setting TeamCity doesn't know, suggesting the closest known name. A name missing from
+// knownSettings is still accepted, as text, when the server lists it for the job, so settings added in newer
+// TeamCity versions keep working.
+func checkSettingName(client api.ClientInterface, id, name string) (settingSpec, error) {
+	if spec, ok := knownSettings[name]; ok {
+		return spec, nil
+	}
+	settings, err := client.GetBuildTypeSettings(id)
+	if err != nil {
+		return settingSpec{}, err
+	}
+	names := slices.Collect(maps.Keys(knownSettings))
+	for _, s := range settings.Property {
+		if s.Name == name {
+			return settingSpec{kind: kindText}, nil
+		}
+		if _, ok := knownSettings[s.Name]; !ok {
+			names = append(names, s.Name)
+		}
+	}
+	slices.Sort(names)
+	msg := fmt.Sprintf("unknown setting %q", name)
+	if guess := closestSetting(name, names); guess != "" {
+		msg += fmt.Sprintf("; did you mean %q?", guess)
+	}
+	return settingSpec{}, api.Validation(msg, "Known settings: "+strings.Join(names, ", "))
+}
+
+// completeSetting completes the job ID, then the setting name from knownSettings.
+func completeSetting(idComplete completion.CompFunc) completion.CompFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return idComplete(cmd, args, toComplete)
+		case 1:
+			return slices.Sorted(maps.Keys(knownSettings)), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// closestSetting returns the known name within a few edits of name, ignoring case, or "" if none is close.
+func closestSetting(name string, names []string) string {
+	best, bestDist := "", max(2, len(name)/4)+1
+	for _, n := range names {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(n)); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// normalize checks value against the setting's type and returns it in the form TeamCity stores.
+func (s settingSpec) normalize(name, value string) (string, error) {
+	switch s.kind {
+	case kindInt:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return "", api.Validation(fmt.Sprintf("%s must be a non-negative whole number, got %q", name, value), "Use 0 for no limit")
+		}
+		return strconv.Itoa(n), nil
+	case kindBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return "", api.Validation(fmt.Sprintf("%s must be true or false, got %q", name, value), "")
+		}
+		return strconv.FormatBool(b), nil
+	case kindEnum:
+		for _, v := range s.values {
+			if strings.EqualFold(strings.TrimSpace(value), v) {
+				return v, nil
+			}
+		}
+		return "", api.Validation(fmt.Sprintf("invalid %s %q", name, value), "Valid values: "+strings.Join(s.values, ", "))
+	default:
+		return value, nil
+	}
+}
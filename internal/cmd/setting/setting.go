@@ -13,7 +13,7 @@ import (
 
 // NewCmd builds the settings command group for a resource, using resolveID as the linked default.
 func NewCmd(f *cmdutil.Factory, resource string, resolveID cmdutil.IDResolver) *cobra.Command {
-	idComplete := completion.LinkedJobs()
+	idComplete := completion.Jobs()
 	cmd := &cobra.Command{
 		Use:   "settings",
 		Short: fmt.Sprintf("Manage %s settings", resource),
@@ -24,10 +24,16 @@ format, execution timeout, artifact rules, checkout rules, etc.).
 Unlike parameters they always have a server default and cannot be
 deleted, only changed.
 
+Setting names are checked before anything is sent; a name the CLI does
+not know is accepted when the server lists it for the %s. set
+validates the value's type: whole numbers for executionTimeoutMin and
+maximumNumberOfBuilds, true/false for flags such as cleanBuild, and
+one of the listed values for checkoutMode and publishArtifactCondition.
+
 The <%s-id> positional is optional when teamcity.toml binds this
 repo via 'teamcity link' - the linked %s is used automatically.
 
-See: https://www.jetbrains.com/help/teamcity/configuring-general-settings.html`, resource, resource, resource),
+See: https://www.jetbrains.com/help/teamcity/configuring-general-settings.html`, resource, resource, resource, resource),
 		Args: cobra.NoArgs,
 		RunE: cmdutil.SubcommandRequired,
 	}
@@ -122,7 +128,7 @@ func newSettingGetCmd(f *cmdutil.Factory, resource string, resolveID cmdutil.IDR
 		Use:               fmt.Sprintf("get [%s-id] <setting>", resource),
 		Short:             fmt.Sprintf("Get a %s setting value", resource),
 		Args:              cobra.RangeArgs(1, 2),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(idComplete),
+		ValidArgsFunction: completeSetting(idComplete),
 		Example: fmt.Sprintf(`  teamcity %s settings get MyID buildNumberPattern
   teamcity %s settings get executionTimeoutMin   # uses linked %s
   teamcity %s settings get MyID artifactRules`, resource, resource, resource, resource),
@@ -146,6 +152,10 @@ func runSettingGet(f *cmdutil.Factory, id, name string, jsonOutput bool) error {
 		return err
 	}
 
+	if _, err := checkSettingName(client, id, name); err != nil {
+		return err
+	}
+
 	value, err := client.GetBuildTypeSetting(id, name)
 	if err != nil {
 		return err
@@ -161,40 +171,60 @@ func runSettingGet(f *cmdutil.Factory, id, name string, jsonOutput bool) error {
 
 // newSettingSetCmd builds the `settings set` subcommand.
 func newSettingSetCmd(f *cmdutil.Factory, resource string, resolveID cmdutil.IDResolver, idComplete completion.CompFunc) *cobra.Command {
+	var forceVCSManaged bool
+
 	cmd := &cobra.Command{
 		Use:   fmt.Sprintf("set [%s-id] <setting> <value>", resource),
 		Short: fmt.Sprintf("Set a %s setting value", resource),
 		Long: fmt.Sprintf(`Set or update a %s setting.
 
+The value is checked against the setting's type first (for example,
+executionTimeoutMin takes minutes as a whole number, 0 for none).
+
 Omit the <%s-id> when this repo is linked via 'teamcity link'.`, resource, resource),
 		Args:              cobra.RangeArgs(2, 3),
-		ValidArgsFunction: cmdutil.CompleteOwnerID(idComplete),
+		ValidArgsFunction: completeSetting(idComplete),
 		Example: fmt.Sprintf(`  teamcity %s settings set MyID buildNumberPattern "2.0.%%build.counter%%"
   teamcity %s settings set executionTimeoutMin 30        # uses linked %s
-  teamcity %s settings set MyID artifactRules "build/** => artifacts"`, resource, resource, resource, resource),
+  teamcity %s settings set MyID artifactRules "build/** => artifacts"
+  teamcity %s settings set MyID checkoutMode on_agent`, resource, resource, resource, resource, resource),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, rest, err := cmdutil.ResolveOwnerID(resource, args, 2, resolveID)
 			if err != nil {
 				return err
 			}
-			return runSettingSet(f, id, rest[0], rest[1])
+			return runSettingSet(f, id, rest[0], rest[1], forceVCSManaged)
 		},
 	}
 
+	cmdutil.AddForceVCSManagedFlag(cmd, &forceVCSManaged)
+
 	return cmd
 }
 
 // runSettingSet writes a single setting value and confirms the change.
-func runSettingSet(f *cmdutil.Factory, id, name, value string) error {
+func runSettingSet(f *cmdutil.Factory, id, name, value string, forceVCSManaged bool) error {
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
+	spec, err := checkSettingName(client, id, name)
+	if err != nil {
+		return err
+	}
+	if value, err = spec.normalize(name, value); err != nil {
+		return err
+	}
+
+	if err := f.GuardVCSManagedJob(client, id, forceVCSManaged); err != nil {
+		return err
+	}
+
 	if err := client.SetBuildTypeSetting(id, name, value); err != nil {
 		return fmt.Errorf("failed to set setting: %w", err)
 	}
 
-	f.Printer.Success("Set setting %s", name)
+	f.Printer.Success("Set setting %s to %q", name, value)
 	return nil
 }
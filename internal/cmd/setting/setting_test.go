@@ -1,9 +1,14 @@
 package setting_test
 
 import (
+	"errors"
+	"io"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
 )
 
@@ -45,6 +50,7 @@ func TestSettingsGet(t *testing.T) {
 
 func TestSettingsSet(t *testing.T) {
 	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
 
 	out := cmdtest.CaptureOutput(t, ts.Factory, "job", "settings", "set", "TestProject_Build", "buildNumberPattern", "2.0.%build.counter%")
 	if !strings.Contains(out, "buildNumberPattern") {
@@ -68,3 +74,101 @@ func TestSettingsRequiresID(t *testing.T) {
 
 	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "job id is required", "job", "settings", "list")
 }
+
+func TestSettingsSetNormalizesTypedValues(t *testing.T) {
+	tests := []struct {
+		setting, value, want string
+	}{
+		{"executionTimeoutMin", " 30", "30"},
+		{"cleanBuild", "TRUE", "true"},
+		{"checkoutMode", "on_agent", "ON_AGENT"},
+		{"artifactRules", "build/** => out", "build/** => out"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.setting, func(t *testing.T) {
+			ts := cmdtest.SetupMockClient(t)
+			ts.ServeServerSettings("TestProject")
+			var sent string
+			ts.Handle("PUT /app/rest/buildTypes/id:TestProject_Build/settings/"+tc.setting, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				sent = string(body)
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "settings", "set", "TestProject_Build", tc.setting, tc.value)
+			if sent != tc.want {
+				t.Fatalf("sent %q, want %q", sent, tc.want)
+			}
+		})
+	}
+}
+
+func TestSettingsRejectsUnknownAndInvalid(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	var writes atomic.Int32
+	ts.Handle("PUT /app/rest/buildTypes/id:TestProject_Build/settings/", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := cmdtest.CaptureErr(t, ts.Factory, "job", "settings", "set", "TestProject_Build", "buildNumberPatern", "1")
+	if !strings.Contains(err.Error(), `unknown setting "buildNumberPatern"; did you mean "buildNumberPattern"?`) {
+		t.Fatalf("error = %q, want a suggestion", err)
+	}
+	if verr, ok := errors.AsType[*api.ValidationError](err); !ok || !strings.Contains(verr.Tip, "checkoutMode, cleanBuild") {
+		t.Fatalf("error = %#v, want the full list of settings as the tip", err)
+	}
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `unknown setting "colour"`, "job", "settings", "get", "TestProject_Build", "colour")
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "executionTimeoutMin must be a non-negative whole number", "job", "settings", "set", "TestProject_Build", "executionTimeoutMin", "1h")
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "cleanBuild must be true or false", "job", "settings", "set", "TestProject_Build", "cleanBuild", "sometimes")
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, `invalid checkoutMode "agent"`, "job", "settings", "set", "TestProject_Build", "checkoutMode", "agent")
+
+	if writes.Load() != 0 {
+		t.Fatalf("invalid settings reached the server %d times", writes.Load())
+	}
+}
+
+func TestSettingsAcceptsServerListedName(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.ServeServerSettings("TestProject")
+	ts.Handle("GET /app/rest/buildTypes/id:TestProject_Build/settings", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.SettingsList{Count: 1, Property: []api.Setting{{Name: "newServerSetting", Value: "x"}}})
+	})
+	var sent string
+	ts.Handle("PUT /app/rest/buildTypes/id:TestProject_Build/settings/newServerSetting", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cmdtest.RunCmdWithFactory(t, ts.Factory, "job", "settings", "set", "TestProject_Build", "newServerSetting", "y")
+	if sent != "y" {
+		t.Fatalf("sent %q, want y", sent)
+	}
+	err := cmdtest.CaptureErr(t, ts.Factory, "job", "settings", "get", "TestProject_Build", "colour")
+	if verr, ok := errors.AsType[*api.ValidationError](err); !ok || !strings.Contains(verr.Tip, "maximumNumberOfBuilds, newServerSetting, publishArtifactCondition") {
+		t.Fatalf("error = %#v, want the server's settings in the tip", err)
+	}
+}
+
+func TestSettingsCompletesNames(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	out := cmdtest.CaptureOutput(t, ts.Factory, "__complete", "job", "settings", "set", "TestProject_Build", "")
+	if !strings.Contains(out, "checkoutMode\n") || !strings.Contains(out, "executionTimeoutMin\n") {
+		t.Fatalf("completion = %q, want the known setting names", out)
+	}
+}
+
+func TestSettingsSetVCSManaged(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/projects/TestProject/versionedSettings/config", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", BuildSettingsMode: "useFromVCS"})
+	})
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "project TestProject takes its settings from VCS",
+		"job", "settings", "set", "TestProject_Build", "executionTimeoutMin", "30")
+	out := cmdtest.CaptureOutput(t, ts.Factory, "job", "settings", "set", "TestProject_Build", "executionTimeoutMin", "30", "--force-vcs-managed")
+	if !strings.Contains(out, "may be overwritten on the next sync") {
+		t.Fatalf("forced set output = %q, want the VCS warning", out)
+	}
+}
@@ -0,0 +1,172 @@
+package tag
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
+	"github.com/spf13/cobra"
+)
+
+type tagListOptions struct {
+	job     string
+	branch  string
+	maxRuns int
+	json    bool
+}
+
+// tagUsage is one tag found on a job's runs.
+type tagUsage struct {
+	Name   string    `json:"name"`
+	Runs   int       `json:"runs"`
+	Pinned int       `json:"pinned"`
+	Latest taggedRun `json:"latest"`
+}
+
+// taggedRun is the most recent run carrying a tag.
+type taggedRun struct {
+	ID     int    `json:"id"`
+	Number string `json:"number,omitempty"`
+	Date   string `json:"date,omitempty"`
+}
+
+type tagListResult struct {
+	Job       string     `json:"job"`
+	Scanned   int        `json:"scanned"`
+	Truncated bool       `json:"truncated"`
+	Tags      []tagUsage `json:"tags"`
+}
+
+func newTagListCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tagListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tags in use with run counts",
+		Long: `List the tags on a job's recent runs, with how many runs carry each
+tag and how many of those are pinned, so a retention audit doesn't need
+the UI. Scans the latest --max-runs runs, newest first.
+
+With no --job, uses the linked default job from teamcity.toml.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity tag list --job Falcon_Build
+  teamcity tag list --job Falcon_Build --branch main --max-runs 2000
+  teamcity tag list --job Falcon_Build --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.job = f.ResolveDefaultJob(opts.job)
+			if opts.job == "" {
+				return api.Validation(
+					"job id is required",
+					"Pass --job <id> or run 'teamcity link' to bind this repository to a job",
+				)
+			}
+			return runTagList(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Job ID to audit")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Only consider runs on this branch")
+	cmd.Flags().IntVar(&opts.maxRuns, "max-runs", 1000, "Maximum number of runs to scan")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+func runTagList(f *cmdutil.Factory, opts *tagListOptions) error {
+	if opts.maxRuns < 1 {
+		return fmt.Errorf("--max-runs must be at least 1, got %d", opts.maxRuns)
+	}
+
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	builds, truncated, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+		BuildTypeID: opts.job,
+		Branch:      opts.branch,
+		Limit:       opts.maxRuns,
+		Fields:      []string{"id", "number", "pinned", "tags.tag.name", "queuedDate", "startDate", "finishDate"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	result := tagListResult{
+		Job:       opts.job,
+		Scanned:   len(builds.Builds),
+		Truncated: truncated,
+		Tags:      countTags(builds.Builds),
+	}
+	if opts.json {
+		return p.PrintJSON(result)
+	}
+
+	if len(result.Tags) == 0 {
+		p.Empty(fmt.Sprintf("No tagged runs in the latest %s of %s", english.Plural(result.Scanned, "run", ""), opts.job), "Tag a run with 'teamcity run tag <id> <tag>'")
+		return nil
+	}
+
+	headers := []string{"TAG", "RUNS", "PINNED", "LATEST"}
+	rows := make([][]string, 0, len(result.Tags))
+	for _, t := range result.Tags {
+		latest := "#" + cmp.Or(t.Latest.Number, strconv.Itoa(t.Latest.ID))
+		if d, err := api.ParseTeamCityTime(t.Latest.Date); err == nil {
+			latest += "  " + output.Faint(output.RelativeTime(d))
+		}
+		rows = append(rows, []string{t.Name, strconv.Itoa(t.Runs), strconv.Itoa(t.Pinned), latest})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint(fmt.Sprintf("%s on %s of %s", english.Plural(len(result.Tags), "tag", ""), english.Plural(result.Scanned, "run", ""), opts.job)))
+	if truncated {
+		p.Tip("Only the latest %d runs were scanned - raise --max-runs to look further back", result.Scanned)
+	}
+	p.Tip("List a tag's runs with 'teamcity run list --job %s --tag <tag>'", opts.job)
+	return nil
+}
+
+// countTags tallies the tags on builds, which are newest first, most-used tag first.
+func countTags(builds []api.Build) []tagUsage {
+	byName := map[string]*tagUsage{}
+	for _, b := range builds {
+		if b.Tags == nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, t := range b.Tags.Tag {
+			if t.Name == "" || seen[t.Name] {
+				continue
+			}
+			seen[t.Name] = true
+			u := byName[t.Name]
+			if u == nil {
+				date := cmp.Or(b.FinishDate, b.StartDate, b.QueuedDate)
+				u = &tagUsage{Name: t.Name, Latest: taggedRun{ID: b.ID, Number: b.Number, Date: date}}
+				byName[t.Name] = u
+			}
+			u.Runs++
+			if b.Pinned {
+				u.Pinned++
+			}
+		}
+	}
+	tags := []tagUsage{}
+	for _, u := range byName {
+		tags = append(tags, *u)
+	}
+	slices.SortFunc(tags, func(a, b tagUsage) int {
+		return cmp.Or(cmp.Compare(b.Runs, a.Runs), cmp.Compare(a.Name, b.Name))
+	})
+	return tags
+}
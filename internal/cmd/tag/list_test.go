@@ -0,0 +1,94 @@
+package tag_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+func tags(names ...string) *api.TagList {
+	list := &api.TagList{}
+	for _, n := range names {
+		list.Tag = append(list.Tag, api.Tag{Name: n})
+	}
+	return list
+}
+
+// setupTagServer serves four runs of Falcon_Build, newest first: two releases (one pinned), an untagged run, and a nightly.
+func setupTagServer(t *testing.T) *cmdtest.TestServer {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("locator"), "buildType:Falcon_Build")
+		assert.Contains(t, r.URL.Query().Get("fields"), "tags(tag(name))")
+		cmdtest.JSON(w, api.BuildList{Count: 4, Builds: []api.Build{
+			{ID: 104, Number: "4", Tags: tags("release-2024.3", "qa"), FinishDate: "20260301T120000+0000"},
+			{ID: 103, Number: "3"},
+			{ID: 102, Number: "2", Tags: tags("release-2024.2", "qa", "qa"), Pinned: true},
+			{ID: 101, Number: "1", Tags: tags("nightly", "release-2024.2"), Pinned: true},
+		}})
+	})
+	return ts
+}
+
+func TestTagList(t *testing.T) {
+	ts := setupTagServer(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "tag", "list", "--job", "Falcon_Build")
+	assert.Regexp(t, `TAG\s+RUNS\s+PINNED\s+LATEST`, got)
+	assert.Regexp(t, `qa\s+2\s+1\s+#4`, got)
+	assert.Regexp(t, `release-2024.2\s+2\s+2\s+#2`, got)
+	assert.Regexp(t, `nightly\s+1\s+1\s+#1`, got)
+	assert.Contains(t, got, "4 tags on 4 runs of Falcon_Build")
+	assert.Contains(t, got, "teamcity run list --job Falcon_Build --tag <tag>")
+}
+
+func TestTagListJSON(t *testing.T) {
+	ts := setupTagServer(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "tag", "list", "--job", "Falcon_Build", "--json")
+	var result struct {
+		Job     string
+		Scanned int
+		Tags    []struct {
+			Name   string
+			Runs   int
+			Pinned int
+			Latest struct{ ID int }
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(got), &result))
+	assert.Equal(t, "Falcon_Build", result.Job)
+	assert.Equal(t, 4, result.Scanned)
+	require.Len(t, result.Tags, 4)
+
+	var order []string
+	for _, tag := range result.Tags {
+		order = append(order, tag.Name)
+	}
+	assert.Equal(t, []string{"qa", "release-2024.2", "nightly", "release-2024.3"}, order, "most-used first, then by name")
+	assert.Equal(t, 2, result.Tags[0].Runs, "a tag listed twice on one run counts once")
+	assert.Equal(t, 104, result.Tags[0].Latest.ID)
+	assert.Equal(t, 102, result.Tags[1].Latest.ID)
+}
+
+func TestTagListEmpty(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.JSON(w, api.BuildList{Count: 1, Builds: []api.Build{{ID: 1}}})
+	})
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "tag", "list", "--job", "Falcon_Build")
+	assert.Contains(t, got, "No tagged runs in the latest 1 run of Falcon_Build")
+}
+
+func TestTagListRequiresJob(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	t.Chdir(t.TempDir())
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "job id is required", "tag", "list")
+}
@@ -0,0 +1,23 @@
+package tag
+
+import (
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Audit run tags",
+		Long: `Audit the tags on a job's runs.
+
+To tag a single run, use teamcity run tag; to find runs by tag, use
+teamcity run list --tag.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newTagListCmd(f))
+
+	return cmd
+}
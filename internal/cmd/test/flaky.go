@@ -0,0 +1,274 @@
+package test
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/completion"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// flakyWorkers caps concurrent per-run test fetches so wide windows don't flood the server.
+const flakyWorkers = 8
+
+type testFlakyOptions struct {
+	job     string
+	branch  string
+	since   string
+	maxRuns int
+	minRuns int
+	limit   int
+	json    bool
+}
+
+// flakyTest is one test that both passed and failed within the analyzed window.
+type flakyTest struct {
+	Name        string      `json:"name"`
+	Runs        int         `json:"runs"`
+	Failures    int         `json:"failures"`
+	Flips       int         `json:"flips"`
+	FailureRate float64     `json:"failureRate"`
+	LastFailure *flakyBuild `json:"lastFailure,omitempty"`
+}
+
+type flakyBuild struct {
+	ID     int    `json:"id"`
+	Number string `json:"number,omitempty"`
+	WebURL string `json:"webUrl,omitempty"`
+}
+
+func newTestFlakyCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &testFlakyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "flaky",
+		Short: "Find tests that flip between passing and failing",
+		Long: `Find flaky tests in a job's recent history.
+
+Fetches test results from the job's finished runs in the window and
+reports tests that both passed and failed, ordered by how often their
+outcome flipped between consecutive runs. Ignored results are skipped;
+muted failures count as failures.
+
+With no --job, uses the linked default job from teamcity.toml.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity test flaky --job Falcon_Build
+  teamcity test flaky --job Falcon_Build --since 30d --branch main
+  teamcity test flaky --job Falcon_Build --min-runs 5 --limit 10
+  teamcity test flaky --job Falcon_Build --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.job = f.ResolveDefaultJob(opts.job)
+			if opts.job == "" {
+				return api.Validation(
+					"job id is required",
+					"Pass --job <id> or run 'teamcity link' to bind this repository to a job",
+				)
+			}
+			return runTestFlaky(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.job, "job", "j", "", "Job ID to analyze")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Only consider runs on this branch")
+	cmd.Flags().StringVar(&opts.since, "since", "14d", "Analyze runs finished after this time (e.g., 24h, 14d, 2026-01-01)")
+	cmd.Flags().IntVar(&opts.maxRuns, "max-runs", 100, "Maximum number of runs to analyze")
+	cmd.Flags().IntVar(&opts.minRuns, "min-runs", 2, "Skip tests with fewer recorded results than this")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 30, "Maximum number of tests to show (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("job", completion.Jobs())
+
+	return cmd
+}
+
+func runTestFlaky(f *cmdutil.Factory, opts *testFlakyOptions) error {
+	if err := cmdutil.CheckLimit(f, opts.limit); err != nil {
+		return err
+	}
+	if opts.maxRuns < 1 {
+		return fmt.Errorf("--max-runs must be at least 1, got %d", opts.maxRuns)
+	}
+	sinceDate, err := api.ParseUserDate(opts.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+
+	p := f.Printer
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	builds, _, err := client.GetBuilds(f.Context(), api.BuildsOptions{
+		BuildTypeID: opts.job,
+		Branch:      opts.branch,
+		State:       "finished",
+		SinceDate:   sinceDate,
+		Limit:       opts.maxRuns,
+		Fields:      []string{"id", "number", "webUrl"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	// Oldest first, so flips are counted between consecutive runs.
+	runs := slices.Clone(builds.Builds)
+	slices.SortFunc(runs, func(a, b api.Build) int { return cmp.Compare(a.ID, b.ID) })
+
+	results, err := fetchRunTests(f, client, runs)
+	if err != nil {
+		return err
+	}
+
+	tests := findFlakyTests(runs, results, opts.minRuns)
+	total := len(tests)
+	if opts.limit > 0 && len(tests) > opts.limit {
+		tests = tests[:opts.limit]
+	}
+
+	if opts.json {
+		return p.PrintJSON(tests)
+	}
+
+	if len(tests) == 0 {
+		p.Empty(fmt.Sprintf("No flaky tests found in %d runs of %s", len(runs), opts.job), "Widen the window with --since or lower --min-runs")
+		return nil
+	}
+
+	headers := []string{"TEST", "RUNS", "FAILS", "FLIPS", "FAIL RATE", "LAST FAILURE"}
+	rows := make([][]string, 0, len(tests))
+	for _, t := range tests {
+		last := "-"
+		if t.LastFailure != nil {
+			last = "#" + t.LastFailure.Number
+		}
+		rows = append(rows, []string{
+			t.Name,
+			strconv.Itoa(t.Runs),
+			strconv.Itoa(t.Failures),
+			strconv.Itoa(t.Flips),
+			fmt.Sprintf("%.0f%%", t.FailureRate*100),
+			last,
+		})
+	}
+	output.AutoSizeColumns(headers, rows, 2, 0)
+	p.PrintTable(headers, rows)
+	_, _ = fmt.Fprintf(p.Out, "\n%s\n", output.Faint(fmt.Sprintf("%d flaky of %d runs analyzed", total, len(runs))))
+	if total > len(tests) {
+		p.Tip("Showing %d of %d - use --limit 0 to show all", len(tests), total)
+	}
+	return nil
+}
+
+// fetchRunTests loads each run's test outcomes with at most flakyWorkers requests in flight (fewer once the server throttles), reporting progress on a terminal.
+func fetchRunTests(f *cmdutil.Factory, client api.ClientInterface, runs []api.Build) ([][]api.TestOccurrence, error) {
+	results := make([][]api.TestOccurrence, len(runs))
+	errs := make([]error, len(runs))
+
+	showProgress := !f.Printer.Quiet && output.IsTerminal()
+	var mu sync.Mutex
+	done := 0
+
+	limiter := f.NewLimiter(flakyWorkers)
+	var wg sync.WaitGroup
+	for i, b := range runs {
+		wg.Go(func() {
+			limiter.Acquire()
+			defer limiter.Release()
+
+			tests, err := client.ListTestOccurrences(f.Context(), api.TestOccurrenceQuery{
+				Build:  strconv.Itoa(b.ID),
+				Fields: []string{"name", "status", "muted"},
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get tests for run %d: %w", b.ID, err)
+			} else {
+				results[i] = tests.TestOccurrence
+			}
+
+			if showProgress {
+				mu.Lock()
+				done++
+				_, _ = fmt.Fprintf(f.Printer.ErrOut, "\rFetching test results... %d/%d", done, len(runs))
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if showProgress && len(runs) > 0 {
+		output.ClearLine(f.Printer.ErrOut)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// findFlakyTests groups outcomes by test name across chronologically ordered runs and keeps tests that both passed and failed.
+func findFlakyTests(runs []api.Build, results [][]api.TestOccurrence, minRuns int) []flakyTest {
+	type history struct {
+		runs, failures, flips int
+		lastFailed            bool
+		lastFailure           *api.Build
+	}
+
+	byName := map[string]*history{}
+	var order []string
+	for i, tests := range results {
+		for _, t := range tests {
+			if t.Status != "SUCCESS" && t.Status != "FAILURE" {
+				continue
+			}
+			h, ok := byName[t.Name]
+			if !ok {
+				h = &history{}
+				byName[t.Name] = h
+				order = append(order, t.Name)
+			}
+			failed := t.Status == "FAILURE"
+			if h.runs > 0 && failed != h.lastFailed {
+				h.flips++
+			}
+			h.runs++
+			h.lastFailed = failed
+			if failed {
+				h.failures++
+				h.lastFailure = &runs[i]
+			}
+		}
+	}
+
+	flaky := []flakyTest{}
+	for _, name := range order {
+		h := byName[name]
+		if h.failures == 0 || h.failures == h.runs || h.runs < minRuns {
+			continue
+		}
+		flaky = append(flaky, flakyTest{
+			Name:        name,
+			Runs:        h.runs,
+			Failures:    h.failures,
+			Flips:       h.flips,
+			FailureRate: float64(h.failures) / float64(h.runs),
+			LastFailure: &flakyBuild{ID: h.lastFailure.ID, Number: h.lastFailure.Number, WebURL: h.lastFailure.WebURL},
+		})
+	}
+
+	slices.SortFunc(flaky, func(a, b flakyTest) int {
+		return cmp.Or(
+			cmp.Compare(b.Flips, a.Flips),
+			cmp.Compare(b.FailureRate, a.FailureRate),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return flaky
+}
@@ -0,0 +1,86 @@
+package test_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+)
+
+// setupFlakyServer serves three runs of Falcon_Build: Flaky fails only in the middle run, Broken always fails, Stable always passes.
+func setupFlakyServer(t *testing.T) *cmdtest.TestServer {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/builds", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("locator"), "buildType:Falcon_Build")
+		cmdtest.JSON(w, api.BuildList{Count: 3, Builds: []api.Build{
+			{ID: 103, Number: "3"}, {ID: 102, Number: "2"}, {ID: 101, Number: "1"},
+		}})
+	})
+	outcomes := map[string]string{"101": "SUCCESS", "102": "FAILURE", "103": "SUCCESS"}
+	ts.Handle("GET /app/rest/testOccurrences", func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		var tests []api.TestOccurrence
+		for id, status := range outcomes {
+			if strings.Contains(locator, "id:"+id) {
+				tests = []api.TestOccurrence{
+					{Name: "Flaky", Status: status},
+					{Name: "Broken", Status: "FAILURE"},
+					{Name: "Stable", Status: "SUCCESS"},
+				}
+			}
+		}
+		cmdtest.JSON(w, api.TestOccurrences{Count: len(tests), TestOccurrence: tests})
+	})
+	return ts
+}
+
+func TestTestFlaky(t *testing.T) {
+	ts := setupFlakyServer(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "test", "flaky", "--job", "Falcon_Build")
+	assert.Contains(t, got, "Flaky")
+	assert.Contains(t, got, "#2")
+	assert.Contains(t, got, "33%")
+	assert.NotContains(t, got, "Broken")
+	assert.NotContains(t, got, "Stable")
+}
+
+func TestTestFlakyJSON(t *testing.T) {
+	ts := setupFlakyServer(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "test", "flaky", "--job", "Falcon_Build", "--json")
+	var tests []struct {
+		Name        string
+		Runs        int
+		Failures    int
+		Flips       int
+		LastFailure struct{ ID int }
+	}
+	require.NoError(t, json.Unmarshal([]byte(got), &tests))
+	require.Len(t, tests, 1)
+	assert.Equal(t, "Flaky", tests[0].Name)
+	assert.Equal(t, 3, tests[0].Runs)
+	assert.Equal(t, 1, tests[0].Failures)
+	assert.Equal(t, 2, tests[0].Flips)
+	assert.Equal(t, 102, tests[0].LastFailure.ID)
+}
+
+func TestTestFlakyMinRuns(t *testing.T) {
+	ts := setupFlakyServer(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "test", "flaky", "--job", "Falcon_Build", "--min-runs", "4", "--json")
+	assert.Equal(t, "[]\n", got)
+}
+
+func TestTestFlakyRequiresJob(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	t.Chdir(t.TempDir())
+
+	cmdtest.RunCmdWithFactoryExpectErr(t, ts.Factory, "job id is required", "test", "flaky")
+}
@@ -0,0 +1,22 @@
+package test
+
+import (
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Analyze tests across runs",
+		Long: `Analyze test results across many runs of a job.
+
+For a single run's tests, use teamcity run tests.`,
+		Args: cobra.NoArgs,
+		RunE: cmdutil.SubcommandRequired,
+	}
+
+	cmd.AddCommand(newTestFlakyCmd(f))
+
+	return cmd
+}
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdutil"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/JetBrains/teamcity-cli/internal/version"
+	"github.com/spf13/cobra"
+)
+
+type versionOptions struct {
+	json    bool
+	offline bool
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &versionOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show CLI version and server capabilities",
+		Long: `Show the CLI version and what the connected TeamCity server supports.
+
+Each capability is reported as supported, unsupported, or unknown (the
+server could not be reached, denied access, or there was nothing to probe).
+Version-gated features come from the server version; endpoints the CLI
+only uses when present are probed with small read-only requests.
+
+Scripts and agents can read --json before choosing which commands to use.
+Pass --offline to skip the server entirely.`,
+		Args: cobra.NoArgs,
+		Example: `  teamcity version
+  teamcity version --json
+  teamcity version --offline --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVersion(f, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&opts.offline, "offline", false, "Skip the server; report only the CLI version")
+
+	return cmd
+}
+
+type cliInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+type serverInfo struct {
+	URL          string `json:"url"`
+	Version      string `json:"version,omitempty"`
+	VersionMajor int    `json:"versionMajor,omitempty"`
+	VersionMinor int    `json:"versionMinor,omitempty"`
+	BuildNumber  string `json:"buildNumber,omitempty"`
+	// ClockSkewSeconds is the server's clock minus the local one, from the Date header of its responses.
+	ClockSkewSeconds *int64 `json:"clockSkewSeconds,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// versionInfo is the --json contract: cli is always set, server is null when offline or unconfigured,
+// and capabilities always has every name, unknown when the server was skipped.
+type versionInfo struct {
+	CLI          cliInfo                         `json:"cli"`
+	Server       *serverInfo                     `json:"server"`
+	Capabilities map[string]api.CapabilityStatus `json:"capabilities"`
+}
+
+func runVersion(f *cmdutil.Factory, opts *versionOptions) error {
+	info := versionInfo{
+		CLI: cliInfo{
+			Version:   version.String(),
+			Commit:    version.CommitHash(),
+			BuildDate: version.BuildDate(),
+			GoVersion: runtime.Version(),
+			Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		},
+		Capabilities: map[string]api.CapabilityStatus{},
+	}
+	for _, name := range api.CapabilityNames() {
+		info.Capabilities[name] = api.CapabilityUnknown
+	}
+
+	var clientErr error
+	if !opts.offline {
+		var client api.ClientInterface
+		client, clientErr = f.Client()
+		if clientErr == nil {
+			info.Server = &serverInfo{URL: client.ServerURL()}
+			if server, err := client.ServerVersion(); err != nil {
+				info.Server.Error = err.Error()
+			} else {
+				info.Server.Version = server.Version
+				info.Server.VersionMajor = server.VersionMajor
+				info.Server.VersionMinor = server.VersionMinor
+				info.Server.BuildNumber = server.BuildNumber
+			}
+			if skew, ok := timeref.Skew(); ok {
+				seconds := int64(skew.Round(time.Second) / time.Second)
+				info.Server.ClockSkewSeconds = &seconds
+			}
+			info.Capabilities = client.Capabilities(f.Context())
+		}
+	}
+
+	if opts.json {
+		return f.Printer.PrintJSON(info)
+	}
+
+	p := f.Printer
+	_, _ = fmt.Fprintf(p.Out, "TeamCity CLI %s\n", output.Cyan("v"+info.CLI.Version))
+	if info.CLI.Commit != "" {
+		p.PrintField("Commit", info.CLI.Commit)
+	}
+	if info.CLI.BuildDate != "" {
+		p.PrintField("Built", info.CLI.BuildDate)
+	}
+	p.PrintField("Platform", info.CLI.Platform+" ("+info.CLI.GoVersion+")")
+
+	switch {
+	case opts.offline:
+		return nil
+	case clientErr != nil:
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s\n", output.Faint("Server:"), "not connected ("+clientErr.Error()+")")
+		return nil
+	case info.Server.Error != "":
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s %s\n", output.Faint("Server:"), info.Server.URL, output.Red("("+info.Server.Error+")"))
+	default:
+		_, _ = fmt.Fprintf(p.Out, "\n%s %s, TeamCity %s (build %s)\n", output.Faint("Server:"), info.Server.URL, info.Server.Version, info.Server.BuildNumber)
+	}
+	if s := info.Server.ClockSkewSeconds; s != nil && timeref.Significant(time.Duration(*s)*time.Second) {
+		p.Warn("Local clock is %s", cmdutil.ClockSkewText(time.Duration(*s)*time.Second))
+	}
+
+	names := slices.Sorted(maps.Keys(info.Capabilities))
+	rows := make([][]string, len(names))
+	for i, name := range names {
+		rows[i] = []string{name, capabilityLabel(info.Capabilities[name])}
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	p.PrintTable([]string{"CAPABILITY", "STATUS"}, rows)
+	return nil
+}
+
+func capabilityLabel(s api.CapabilityStatus) string {
+	switch s {
+	case api.CapabilitySupported:
+		return output.Green(string(s))
+	case api.CapabilityUnsupported:
+		return output.Red(string(s))
+	default:
+		return output.Faint(string(s))
+	}
+}
@@ -0,0 +1,90 @@
+package version_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/cmdtest"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionJSON struct {
+	CLI struct {
+		Version  string
+		Platform string
+	}
+	Server *struct {
+		URL         string
+		Version     string
+		BuildNumber string
+	}
+	Capabilities map[string]api.CapabilityStatus
+}
+
+func TestVersionJSON(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Handle("GET /app/rest/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	var got versionJSON
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "version", "--json")), &got))
+	assert.NotEmpty(t, got.CLI.Version)
+	assert.NotEmpty(t, got.CLI.Platform)
+	require.NotNil(t, got.Server)
+	assert.Equal(t, ts.URL, got.Server.URL)
+	assert.NotEmpty(t, got.Server.Version)
+	assert.Equal(t, api.CapabilityUnsupported, got.Capabilities["pipelines_api"])
+	assert.Len(t, got.Capabilities, len(api.CapabilityNames()))
+}
+
+func TestVersionOffline(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	ts.Factory.ClientFunc = func() (api.ClientInterface, error) {
+		t.Error("--offline must not create a client")
+		return nil, errors.New("offline")
+	}
+
+	var got versionJSON
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "version", "--offline", "--json")), &got))
+	assert.Nil(t, got.Server)
+	for _, name := range api.CapabilityNames() {
+		assert.Equal(t, api.CapabilityUnknown, got.Capabilities[name], name)
+	}
+
+	text := cmdtest.CaptureOutput(t, ts.Factory, "version", "--offline")
+	assert.Contains(t, text, "TeamCity CLI v")
+	assert.NotContains(t, text, "CAPABILITY")
+}
+
+func TestVersionText(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+
+	got := cmdtest.CaptureOutput(t, ts.Factory, "version")
+	assert.Contains(t, got, "Server: "+ts.URL)
+	assert.Regexp(t, `CAPABILITY\s+STATUS`, got)
+	assert.Regexp(t, `secure_tokens\s+\w+`, got)
+}
+
+func TestVersionClockSkew(t *testing.T) {
+	ts := cmdtest.SetupMockClient(t)
+	now := time.Now()
+	timeref.Observe(now.Add(5*time.Minute), now)
+
+	var got struct {
+		Server struct {
+			ClockSkewSeconds *int64
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(cmdtest.CaptureOutput(t, ts.Factory, "version", "--json")), &got))
+	require.NotNil(t, got.Server.ClockSkewSeconds)
+	assert.Equal(t, int64(300), *got.Server.ClockSkewSeconds)
+
+	assert.Contains(t, cmdtest.CaptureOutput(t, ts.Factory, "version"), "Local clock is 5m 0s behind the server")
+}
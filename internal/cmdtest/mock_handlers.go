@@ -577,7 +577,7 @@ func SetupMockClient(t *testing.T) *TestServer {
 		JSON(w, api.VersionedSettingsConfig{
 			SynchronizationMode: "enabled",
 			Format:              "xml",
-			BuildSettingsMode:   "useCurrentByDefault",
+			BuildSettingsMode:   "useFromVCS",
 		})
 	})
 
@@ -886,3 +886,11 @@ func SetupMockClient(t *testing.T) *TestServer {
 
 	return ts
 }
+
+// ServeServerSettings answers projectID's versioned settings with settings kept on the server, so edit commands
+// pass the VCS-managed guard that TestProject, whose settings come from VCS, trips.
+func (ts *TestServer) ServeServerSettings(projectID string) {
+	ts.Handle("GET /app/rest/projects/"+projectID+"/versionedSettings/config", func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, api.VersionedSettingsConfig{SynchronizationMode: "enabled", Format: "kotlin", BuildSettingsMode: "useCurrentByDefault"})
+	})
+}
@@ -88,6 +88,30 @@ func NewTestServer(t *testing.T) *TestServer {
 	return ts
 }
 
+// NewCassetteFactory returns a Factory whose client answers from a cassette recorded with TC_RECORD
+// instead of a server, so command tests can assert on real server shapes without writing handlers.
+func NewCassetteFactory(t *testing.T, path string) *cmdutil.Factory {
+	t.Helper()
+	cassette, err := api.LoadCassette(path)
+	require.NoError(t, err)
+
+	const serverURL = "https://teamcity.test"
+	t.Setenv("TEAMCITY_URL", serverURL)
+	t.Setenv("TEAMCITY_TOKEN", "test-token")
+	t.Setenv("DO_NOT_TRACK", "1")
+	_ = config.Init()
+
+	replayer := cassette.Replayer()
+	f := cmdutil.NewFactory()
+	f.ClientFunc = func() (api.ClientInterface, error) {
+		return api.NewClient(serverURL, "test-token", api.WithRoundTripper(func(http.RoundTripper) http.RoundTripper {
+			return replayer
+		})), nil
+	}
+	f.SkipLinkLookup()
+	return f
+}
+
 // CloneFactory returns a new Factory that shares the same ClientFunc and IOStreams
 // but has its own flag storage, making it safe for parallel subtests.
 func (ts *TestServer) CloneFactory() *cmdutil.Factory {
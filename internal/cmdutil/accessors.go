@@ -0,0 +1,50 @@
+package cmdutil
+
+import (
+	"cmp"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+// Servers leave out the fields a user's per-project permissions hide, so any nested entity may be nil and
+// any name empty. Rendering code reads them through these accessors, which return "" rather than panic.
+
+// UserName returns the display name of u, or its username when the name is hidden.
+func UserName(u *api.User) string {
+	if u == nil {
+		return ""
+	}
+	return cmp.Or(u.Name, u.Username)
+}
+
+// TriggeredBy names who or what triggered a run: the user when known, otherwise the trigger type.
+func TriggeredBy(t *api.Triggered) string {
+	if t == nil {
+		return ""
+	}
+	return cmp.Or(UserName(t.User), t.Type)
+}
+
+// JobName returns the name of job bt, or its ID when the name is hidden. jobID is used when bt itself is.
+func JobName(bt *api.BuildType, jobID string) string {
+	if bt == nil {
+		return jobID
+	}
+	return cmp.Or(bt.Name, bt.ID, jobID)
+}
+
+// AgentName returns the name of agent a, or "" when there is none.
+func AgentName(a *api.Agent) string {
+	if a == nil {
+		return ""
+	}
+	return a.Name
+}
+
+// RunNumber returns "#<number>" of a run, or "" while it has none (queued runs) or it is hidden.
+func RunNumber(number string) string {
+	if number == "" {
+		return ""
+	}
+	return "#" + number
+}
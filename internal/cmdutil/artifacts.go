@@ -0,0 +1,54 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+const maxArtifactDepth = 20
+
+// FetchAllArtifacts lists every artifact file of a run under basePath, walking directories one request each.
+// Names are full paths from the artifacts root; the total is the sum of the file sizes.
+func FetchAllArtifacts(ctx context.Context, client api.ClientInterface, runID, basePath string) ([]api.Artifact, int64, error) {
+	return fetchArtifactsRecursive(ctx, client, runID, basePath, 0)
+}
+
+func fetchArtifactsRecursive(ctx context.Context, client api.ClientInterface, runID, basePath string, depth int) ([]api.Artifact, int64, error) {
+	if depth > maxArtifactDepth {
+		return nil, 0, fmt.Errorf("artifact tree exceeds maximum depth (%d)", maxArtifactDepth)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	default:
+	}
+
+	artifacts, err := client.GetArtifacts(ctx, runID, basePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []api.Artifact
+	var totalSize int64
+	for _, a := range artifacts.File {
+		name := a.Name
+		if basePath != "" {
+			name = basePath + "/" + a.Name
+		}
+		if a.Content != nil {
+			result = append(result, api.Artifact{Name: name, Size: a.Size, ModTime: a.ModTime, Content: a.Content})
+			totalSize += a.Size
+		} else {
+			nested, size, err := fetchArtifactsRecursive(ctx, client, runID, name, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			result = append(result, nested...)
+			totalSize += size
+		}
+	}
+	return result, totalSize, nil
+}
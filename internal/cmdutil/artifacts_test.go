@@ -0,0 +1,98 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockArtifactClient struct {
+	api.ClientInterface
+	responses map[string]*api.Artifacts
+}
+
+func (m *mockArtifactClient) GetArtifacts(_ context.Context, buildID, path string) (*api.Artifacts, error) {
+	key := fmt.Sprintf("%s:%s", buildID, path)
+	resp, ok := m.responses[key]
+	if !ok {
+		return &api.Artifacts{}, nil
+	}
+	return resp, nil
+}
+
+func TestFetchAllArtifacts(T *testing.T) {
+	T.Parallel()
+
+	contentRef := new(api.Content{Href: "/download"})
+
+	T.Run("flat files", func(t *testing.T) {
+		t.Parallel()
+		mock := &mockArtifactClient{responses: map[string]*api.Artifacts{
+			"1:": {Count: 2, File: []api.Artifact{
+				{Name: "a.txt", Size: 10, Content: contentRef},
+				{Name: "b.txt", Size: 20, Content: contentRef},
+			}},
+		}}
+
+		got, size, err := FetchAllArtifacts(t.Context(), mock, "1", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), size)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "a.txt", got[0].Name)
+		assert.Equal(t, "b.txt", got[1].Name)
+	})
+
+	T.Run("recursive directories", func(t *testing.T) {
+		t.Parallel()
+		mock := &mockArtifactClient{responses: map[string]*api.Artifacts{
+			"1:": {Count: 2, File: []api.Artifact{
+				{Name: "root.txt", Size: 5, Content: contentRef},
+				{Name: "subdir"},
+			}},
+			"1:subdir": {Count: 1, File: []api.Artifact{
+				{Name: "nested.txt", Size: 15, Content: contentRef},
+			}},
+		}}
+
+		got, size, err := FetchAllArtifacts(t.Context(), mock, "1", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), size)
+		require.Len(t, got, 2)
+		assert.Equal(t, "root.txt", got[0].Name)
+		assert.Equal(t, "subdir/nested.txt", got[1].Name)
+	})
+
+	T.Run("with base path", func(t *testing.T) {
+		t.Parallel()
+		mock := &mockArtifactClient{responses: map[string]*api.Artifacts{
+			"1:build": {Count: 1, File: []api.Artifact{
+				{Name: "app.jar", Size: 100, Content: contentRef},
+			}},
+		}}
+
+		got, size, err := FetchAllArtifacts(t.Context(), mock, "1", "build")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), size)
+		require.Len(t, got, 1)
+		assert.Equal(t, "build/app.jar", got[0].Name)
+	})
+
+	T.Run("respects context cancellation", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		mock := &mockArtifactClient{responses: map[string]*api.Artifacts{
+			"1:": {Count: 1, File: []api.Artifact{
+				{Name: "a.txt", Size: 10, Content: contentRef},
+			}},
+		}}
+
+		_, _, err := FetchAllArtifacts(ctx, mock, "1", "")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
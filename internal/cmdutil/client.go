@@ -1,39 +1,41 @@
 package cmdutil
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
 	"github.com/JetBrains/teamcity-cli/internal/version"
 )
 
 func (f *Factory) defaultGetClient() (api.ClientInterface, error) {
-	serverURL := config.GetServerURL()
-	token, source, keyringErr := config.GetTokenWithSource()
+	serverURL := cmp.Or(f.ServerURL, config.GetServerURL())
+	token, source, tokenErr := config.TokenWithSourceFor(f.ServerURL)
 
-	debugOpt := api.WithDebugFunc(f.Printer.Debug)
-	roOpt := api.WithReadOnly(config.IsReadOnly())
-	verOpt := api.WithVersion(version.String())
-
-	opts := []api.ClientOption{debugOpt, roOpt, verOpt}
-
-	if config.IsGuestAuth() {
+	if config.IsGuestAuthFor(serverURL) {
 		if serverURL == "" {
 			return nil, api.Validation(
 				"TEAMCITY_GUEST is set but no server URL configured",
-				fmt.Sprintf("Set %s environment variable or run 'teamcity auth login --guest -s <url>'", config.EnvServerURL),
+				fmt.Sprintf("Set %s environment variable or run 'teamcity auth guest <url>'", config.EnvServerURL),
 			)
 		}
 		f.Printer.Debug("Using guest authentication")
-		opts = append(opts, api.WithAuthSource(api.AuthSourceGuest))
+		opts := append(f.clientOptions(serverURL), api.WithAuthSource(api.AuthSourceGuest))
 		return api.NewGuestClient(serverURL, opts...).WithContext(f.Context()), nil
 	}
 
+	// A token command that fails is an authentication error of its own, not a reason to try other credentials.
+	if cmdErr, ok := errors.AsType[*config.TokenCommandError](tokenErr); ok {
+		return nil, cmdErr
+	}
+
 	if serverURL != "" && token != "" {
 		f.WarnInsecureHTTP(serverURL, "authentication token")
-		opts = append(opts, api.WithAuthSource(resolveAuthSource(source)))
+		opts := append(f.clientOptions(serverURL), api.WithAuthSource(resolveAuthSource(source)))
 		return api.NewClient(serverURL, token, opts...).WithContext(f.Context()), nil
 	}
 
@@ -43,11 +45,72 @@ func (f *Factory) defaultGetClient() (api.ClientInterface, error) {
 		}
 		f.Printer.Debug("Using build-level authentication")
 		f.WarnInsecureHTTP(serverURL, "credentials")
-		opts = append(opts, api.WithAuthSource(api.AuthSourceBuild))
+		opts := append(f.clientOptions(serverURL), api.WithAuthSource(api.AuthSourceBuild))
 		return api.NewClientWithBasicAuth(serverURL, buildAuth.Username, buildAuth.Password, opts...).WithContext(f.Context()), nil
 	}
 
-	return nil, NotAuthenticatedError(f.Context(), serverURL, keyringErr)
+	return nil, NotAuthenticatedError(f.Context(), serverURL, tokenErr)
+}
+
+// clientOptions are the options every client the Factory creates for serverURL shares.
+func (f *Factory) clientOptions(serverURL string) []api.ClientOption {
+	debugOpt := api.WithDebugFunc(f.Printer.Debug)
+	roOpt := api.WithReadOnly(config.IsReadOnlyFor(serverURL))
+	verOpt := api.WithVersion(version.String())
+
+	// TLS settings replace the transport, so they come before anything that wraps it.
+	opts := []api.ClientOption{f.TLSOption(serverURL), debugOpt, roOpt, verOpt, api.WithRetries(f.RetryCount(), 0)}
+	// The cache goes under the recorder, so a cassette holds the responses commands saw, cached or not.
+	if c := f.ResponseCache(); c != nil {
+		opts = append(opts, api.WithRoundTripper(c.Wrap))
+	}
+	if r := f.Recorder(); r != nil {
+		opts = append(opts, api.WithRoundTripper(r.Wrap))
+	}
+	opts = append(opts, api.WithThrottle(f.Throttle()), api.WithServerClock(timeref.Observe))
+	timeref.OnSkew(f.warnClockSkew)
+	if label := f.CLIContext(); label != "" {
+		opts = append(opts, api.WithCLIContext(label))
+	}
+	return opts
+}
+
+// BasicAuthClient is Client for a username and password given on the command line instead of the stored credentials.
+func (f *Factory) BasicAuthClient(serverURL, username, password string) api.ClientInterface {
+	f.WarnInsecureHTTP(serverURL, "credentials")
+	var client api.ClientInterface = api.NewClientWithBasicAuth(serverURL, username, password, f.clientOptions(serverURL)...).WithContext(f.Context())
+	if f.IsDryRun() {
+		f.Printer.Quiet = true
+		client = api.NewDryRunClient(f.Context(), client, f.printDryRunCall)
+	}
+	return client
+}
+
+// TLSOption verifies serverURL's certificate as its ca_cert and insecure_skip_verify keys say, for clients built
+// outside Client, such as during login. It warns, once per server, when verification is off.
+func (f *Factory) TLSOption(serverURL string) api.ClientOption {
+	o := config.ServerTLS(serverURL)
+	if o.InsecureSkipVerify {
+		if _, warned := f.tlsWarned.LoadOrStore(serverURL, true); !warned {
+			f.Printer.Warn("TLS certificate verification is off for %s (insecure_skip_verify).", serverURL)
+			f.Printer.Warn("Anyone on the network path can read and change this traffic, token included.")
+		}
+	}
+	return api.WithTLS(o)
+}
+
+// CLIContext returns the configured audit context label (TC_CONTEXT or the context config key),
+// or "" with a warning when it isn't safe to send.
+func (f *Factory) CLIContext() string {
+	label := config.CLIContext()
+	if label == "" {
+		return ""
+	}
+	if err := api.ValidateCLIContext(label); err != nil {
+		f.Printer.Warn("Not sending %s: %v", api.HeaderCLIContext, err)
+		return ""
+	}
+	return label
 }
 
 // resolveAuthSource maps a token-source string plus config state onto an api.AuthSource.
@@ -69,7 +132,7 @@ func ProbeGuestAccess(ctx context.Context, serverURL string) bool {
 	if serverURL == "" {
 		return false
 	}
-	guest := api.NewGuestClient(serverURL, api.WithVersion(version.String())).WithContext(ctx)
+	guest := api.NewGuestClient(serverURL, api.WithTLS(config.ServerTLS(serverURL)), api.WithVersion(version.String())).WithContext(ctx)
 	_, err := guest.GetServer()
 	return err == nil
 }
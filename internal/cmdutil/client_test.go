@@ -1,12 +1,17 @@
 package cmdutil
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,3 +79,83 @@ func TestDefaultGetClient_AppliesEnvHeaders(t *testing.T) {
 	assert.Equal(t, "abc.id", got.Get("Cf-Access-Client-Id"))
 	assert.Equal(t, "shh", got.Get("Cf-Access-Client-Secret"))
 }
+
+func TestDefaultGetClient_SendsCLIContext(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(config.EnvServerURL, server.URL)
+	t.Setenv(config.EnvToken, "tok")
+
+	for _, tc := range []struct {
+		label, want, warning string
+	}{
+		{label: "release-bot", want: "release-bot"},
+		{label: "eyJ0eXAiOiAiVENWMiJ9.c2VjcmV0", warning: "Not sending X-TC-CLI-Context: context label looks like a token"},
+		{label: ""},
+	} {
+		t.Run(cmp.Or(tc.label, "unset"), func(t *testing.T) {
+			t.Setenv(config.EnvContext, tc.label)
+			config.ResetForTest()
+			t.Cleanup(config.ResetForTest)
+
+			var errOut bytes.Buffer
+			f := NewFactory()
+			f.Printer = &output.Printer{Out: &bytes.Buffer{}, ErrOut: &errOut}
+			client, err := f.Client()
+			require.NoError(t, err)
+			_, err = client.GetServer()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, got.Get(api.HeaderCLIContext))
+			assert.Contains(t, got.Get("User-Agent"), "teamcity-cli/")
+			if tc.warning != "" {
+				assert.Contains(t, errOut.String(), tc.warning)
+			} else {
+				assert.NotContains(t, errOut.String(), "Not sending")
+			}
+		})
+	}
+}
+
+func TestDefaultGetClient_TokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(config.EnvServerURL, server.URL)
+	t.Setenv(config.EnvToken, "")
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	t.Run("its output is the token", func(t *testing.T) {
+		t.Setenv(config.EnvTokenCommand, "echo from-vault")
+		client, err := NewFactory().Client()
+		require.NoError(t, err)
+		_, err = client.GetServer()
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer from-vault", got.Get("Authorization"))
+	})
+
+	t.Run("a failure is an auth error with its stderr", func(t *testing.T) {
+		t.Setenv(config.EnvTokenCommand, "echo 'vault: token expired' >&2; exit 1")
+		_, err := NewFactory().Client()
+		require.Error(t, err)
+		ue, ok := errors.AsType[api.UserError](err)
+		require.True(t, ok)
+		assert.Equal(t, api.CatAuth, ue.Category())
+		assert.Contains(t, err.Error(), "vault: token expired")
+	})
+}
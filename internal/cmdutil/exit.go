@@ -5,7 +5,10 @@ import "fmt"
 const (
 	ExitFailure   = 1
 	ExitCancelled = 2
-	ExitTimeout   = 124
+	// ExitHTTPClientError and ExitHTTPServerError are what 'teamcity api --fail' exits with on a 4xx or 5xx response.
+	ExitHTTPClientError = 4
+	ExitHTTPServerError = 5
+	ExitTimeout         = 124
 )
 
 // ExitError is returned by commands that need a specific exit code.
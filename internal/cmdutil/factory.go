@@ -2,12 +2,16 @@ package cmdutil
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/analytics"
+	"github.com/JetBrains/teamcity-cli/internal/config"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"golang.org/x/term"
 )
@@ -27,9 +31,34 @@ type IOStreams struct {
 type Factory struct {
 	// Global flags — set once by root command, read by subcommands.
 	NoColor bool
+	// Color is the --color mode: ColorAuto, ColorAlways, or ColorNever. --no-color is short for never.
+	Color   string
 	Quiet   bool
 	Verbose bool
 	NoInput bool
+	DryRun  bool
+	MaxRPS  float64
+
+	// Retries is the --retries value, used only when RetriesSet; see RetryCount.
+	Retries    int
+	RetriesSet bool
+
+	// Timeout bounds the whole command, API calls included (--timeout); 0 means no limit. See ApplyTimeout.
+	Timeout time.Duration
+
+	// Cache is the --cache value, how long API responses are cached, used only when CacheSet; see CacheTTL.
+	Cache    time.Duration
+	CacheSet bool
+
+	// RecordPath is the cassette file API traffic is recorded to (--record or TC_RECORD); see Recorder.
+	RecordPath string
+
+	// Server is the --server value, a server URL or context label to use instead of the default for this invocation.
+	Server string
+	// ServerURL is the server Server resolved to, or "" for the default; see ApplyServer.
+	ServerURL string
+	// FollowRenames continues with a job's new ID when a reference to it was renamed; see renamed.go.
+	FollowRenames bool
 
 	// JSONOutput is set by commands that accept --json to signal that errors
 	// should be emitted as structured JSON instead of human-readable text.
@@ -58,8 +87,37 @@ type Factory struct {
 	// ctx is the signal-aware root context set by cmd.Execute; read via Context(), unset falls back to Background.
 	ctx context.Context
 
+	// child marks a Factory made by Child; see ApplyServer and KeepFlags.
+	child bool
+
+	// stopTimeout releases the --timeout deadline; see ApplyTimeout.
+	stopTimeout context.CancelFunc
+
 	// link caches teamcity.toml lookup; see link.go.
 	link *linkResolver
+
+	// throttle is shared by every client this Factory builds; see Throttle.
+	throttle     *api.Throttle
+	throttleOnce sync.Once
+	throttleNote sync.Once
+
+	// responseCache is shared by every client this Factory builds; see ResponseCache.
+	responseCache     *api.ResponseCache
+	responseCacheOnce sync.Once
+
+	// recorder is shared by every client this Factory builds, so they all write one cassette; see Recorder.
+	recorder     *api.Recorder
+	recorderOnce sync.Once
+
+	// vcs caches versioned-settings lookups for the edit guard; see vcs_managed.go.
+	vcs     *vcsManagedCache
+	vcsOnce sync.Once
+
+	// tlsWarned holds the servers already warned about having certificate verification off; see TLSOption.
+	tlsWarned sync.Map
+
+	// renamedJobs maps job IDs to the IDs they were renamed to; see FollowRename.
+	renamedJobs map[string]string
 }
 
 // NewFactory creates a Factory with production defaults.
@@ -76,20 +134,201 @@ func NewFactory() *Factory {
 	return f
 }
 
-// Client returns an API client using the configured ClientFunc.
+// Child returns a Factory for running another command in-process, as batch does. It shares f's client,
+// throttle, link and versioned-settings lookups, context, and analytics, but uses streams and never prompts.
+func (f *Factory) Child(streams *IOStreams) *Factory {
+	f.linkScope() // resolve before children run concurrently
+	c := &Factory{
+		NoColor:       f.NoColor,
+		Color:         f.Color,
+		Quiet:         f.Quiet,
+		Verbose:       f.Verbose,
+		NoInput:       true,
+		DryRun:        f.DryRun,
+		MaxRPS:        f.MaxRPS,
+		Retries:       f.Retries,
+		RetriesSet:    f.RetriesSet,
+		Cache:         f.Cache,
+		CacheSet:      f.CacheSet,
+		ServerURL:     f.ServerURL,
+		FollowRenames: f.FollowRenames,
+		IOStreams:     streams,
+		Printer:       &output.Printer{Out: streams.Out, ErrOut: streams.ErrOut},
+		ClientFunc:    f.ClientFunc,
+		Analytics:     f.Analytics,
+		StartTime:     f.StartTime,
+		child:         true,
+		ctx:           f.ctx,
+		link:          f.link,
+		throttle:      f.Throttle(),
+		recorder:      f.Recorder(),
+		vcs:           f.vcsManagedCache(),
+
+		responseCache: f.ResponseCache(),
+	}
+	c.throttleOnce.Do(func() {})
+	c.recorderOnce.Do(func() {})
+	c.responseCacheOnce.Do(func() {})
+	c.vcsOnce.Do(func() {})
+	return c
+}
+
+// KeepFlags runs bind, which binds the root command's persistent flags to f and so resets them to their defaults, and
+// on a Child puts back the values it inherited; flags on the child's own command line still win when parsed. Elsewhere
+// it also forgets RetriesSet and CacheSet from an earlier run, which the command's flags set again.
+func (f *Factory) KeepFlags(bind func()) {
+	if !f.child {
+		f.RetriesSet, f.CacheSet = false, false
+		bind()
+		return
+	}
+	noColor, color, quiet, verbose, noInput, dryRun := f.NoColor, f.Color, f.Quiet, f.Verbose, f.NoInput, f.DryRun
+	maxRPS, retries, cache, followRenames := f.MaxRPS, f.Retries, f.Cache, f.FollowRenames
+	bind()
+	f.NoColor, f.Color, f.Quiet, f.Verbose, f.NoInput, f.DryRun = noColor, color, quiet, verbose, noInput, dryRun
+	f.MaxRPS, f.Retries, f.Cache, f.FollowRenames = maxRPS, retries, cache, followRenames
+}
+
+// ApplyServer resolves --server into ServerURL. Only a top-level Factory also makes it the server the config package
+// reports, since children run concurrently under batch; a child naming another server gets a client of its own.
+func (f *Factory) ApplyServer() error {
+	server, err := config.ResolveServer(f.Server)
+	if err != nil {
+		return err
+	}
+	if !f.child {
+		f.ServerURL = server
+		config.SetServerOverride(server)
+		return nil
+	}
+	if server != "" && server != f.ServerURL {
+		f.ServerURL = server
+		f.ClientFunc = f.defaultGetClient
+	}
+	return nil
+}
+
+// Client returns an API client using the configured ClientFunc, wrapped in an api.DryRunClient under --dry-run.
 func (f *Factory) Client() (api.ClientInterface, error) {
-	return f.ClientFunc()
+	client, err := f.ClientFunc()
+	if err != nil || !f.IsDryRun() {
+		return client, err
+	}
+	// Success lines would claim changes that never happened; the recorded calls are the output instead.
+	f.Printer.Quiet = true
+	return api.NewDryRunClient(f.Context(), client, f.printDryRunCall), nil
+}
+
+// IsDryRun reports whether mutations should be printed instead of sent (--dry-run or TC_DRY_RUN).
+func (f *Factory) IsDryRun() bool {
+	return f.DryRun || config.IsDryRun()
 }
 
+// printDryRunCall writes one recorded call; under --json it goes to stderr so stdout stays a valid document.
+func (f *Factory) printDryRunCall(call api.DryRunCall) {
+	w := f.Printer.Out
+	if f.JSONOutput {
+		w = f.Printer.ErrOut
+	}
+	_, _ = fmt.Fprintf(w, "%s %s\n", output.Faint("[dry-run] Would send"), call)
+}
+
+// Throttle returns the request throttle shared by this Factory's clients, capped by --max-rps or TEAMCITY_MAX_RPS.
+func (f *Factory) Throttle() *api.Throttle {
+	f.throttleOnce.Do(func() {
+		rps := f.MaxRPS
+		if rps <= 0 {
+			rps = config.MaxRPS()
+		}
+		f.throttle = api.NewThrottle(rps, f.noteThrottled)
+	})
+	return f.throttle
+}
+
+// RetryCount returns how many times transient read failures are retried: --retries, TEAMCITY_RETRIES, or the API default.
+func (f *Factory) RetryCount() int {
+	if f.RetriesSet {
+		return max(f.Retries, 0)
+	}
+	if n, ok := config.Retries(); ok {
+		return n
+	}
+	return int(api.ReadRetry.MaxRetries)
+}
+
+// CacheTTL returns how long API responses are cached: --cache, the default_cache config key, or 0 for not at all.
+func (f *Factory) CacheTTL() time.Duration {
+	if f.CacheSet {
+		return max(f.Cache, 0)
+	}
+	return config.DefaultCache()
+}
+
+// ResponseCache returns the on-disk cache of API responses shared by this Factory's clients, or nil when caching is off.
+func (f *Factory) ResponseCache() *api.ResponseCache {
+	f.responseCacheOnce.Do(func() {
+		ttl := f.CacheTTL()
+		if ttl <= 0 {
+			return
+		}
+		dir, err := config.CacheDir()
+		if err != nil {
+			f.Printer.Debug("Not caching responses: %v", err)
+			return
+		}
+		f.responseCache = api.NewResponseCache(dir, ttl, f.Printer.Debug)
+	})
+	return f.responseCache
+}
+
+// Recorder returns the recorder that writes this Factory's API traffic to a cassette, or nil when recording is off.
+func (f *Factory) Recorder() *api.Recorder {
+	f.recorderOnce.Do(func() {
+		path := f.RecordPath
+		if path == "" {
+			path = config.RecordPath()
+		}
+		if path != "" {
+			f.recorder = api.NewRecorder(path)
+		}
+	})
+	return f.recorder
+}
+
+// noteThrottled warns once per command that the server is throttling, instead of surfacing each retried request.
+func (f *Factory) noteThrottled(wait time.Duration) {
+	f.throttleNote.Do(func() {
+		f.Printer.Warn("Server is throttling requests, slowing down (retrying in %s)...", output.FormatDuration(wait))
+	})
+}
+
+// --color modes.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
 // InitOutput configures output settings from Factory flags.
 // Called once after flags are parsed (in PersistentPreRun).
-func (f *Factory) InitOutput() {
+func (f *Factory) InitOutput() error {
+	mode := f.Color
+	switch {
+	case mode == "":
+		mode = ColorAuto
+	case mode != ColorAuto && mode != ColorAlways && mode != ColorNever:
+		return api.Validation(fmt.Sprintf("invalid --color %q", f.Color), "Use auto, always, or never")
+	}
+	if f.NoColor {
+		mode = ColorNever
+	}
 	explicitDisable := os.Getenv("NO_COLOR") != "" ||
-		os.Getenv("TEAMCITY_NO_COLOR") != "" ||
-		f.NoColor
-	forceColor := os.Getenv("FORCE_COLOR") != "" && !explicitDisable
-	output.NoColor = !forceColor &&
-		(explicitDisable || os.Getenv("TERM") == "dumb" || !term.IsTerminal(int(os.Stdout.Fd())))
+		os.Getenv("TEAMCITY_NO_COLOR") != ""
+	forceColor := os.Getenv("FORCE_COLOR") != "" ||
+		(os.Getenv("CLICOLOR_FORCE") != "" && os.Getenv("CLICOLOR_FORCE") != "0")
+	vt := output.EnableVirtualTerminal()
+	output.NoColor = colorDisabled(mode, explicitDisable, forceColor,
+		os.Getenv("TERM") == "dumb", term.IsTerminal(int(os.Stdout.Fd())), vt)
 
 	output.ASCII = os.Getenv("TEAMCITY_ASCII") != "" ||
 		os.Getenv("TERM") == "dumb" ||
@@ -97,6 +336,26 @@ func (f *Factory) InitOutput() {
 
 	f.Printer.Quiet = f.Quiet
 	f.Printer.Verbose = f.Verbose
+	return nil
+}
+
+// colorDisabled decides output.NoColor. --color always and never settle it; in auto mode an
+// opt-out in the environment wins, and a force (FORCE_COLOR, CLICOLOR_FORCE) overrides the rest:
+// dumb terminals, non-TTY stdout, and consoles without VT support.
+func colorDisabled(mode string, explicitDisable, forceColor, dumb, tty, vt bool) bool {
+	switch mode {
+	case ColorAlways:
+		return false
+	case ColorNever:
+		return true
+	}
+	if explicitDisable {
+		return true
+	}
+	if forceColor {
+		return false
+	}
+	return dumb || !tty || !vt
 }
 
 // IsInteractive returns true if the CLI can prompt the user.
@@ -116,3 +375,24 @@ func (f *Factory) Context() context.Context {
 func (f *Factory) SetContext(ctx context.Context) {
 	f.ctx = ctx
 }
+
+// ApplyTimeout bounds f's context by --timeout, once, so every request made through it is canceled when the time
+// is up; call StopTimeout when the command is done.
+func (f *Factory) ApplyTimeout() context.Context {
+	if f.Timeout > 0 && f.stopTimeout == nil {
+		f.ctx, f.stopTimeout = context.WithTimeout(f.Context(), f.Timeout)
+	}
+	return f.Context()
+}
+
+// StopTimeout releases the deadline ApplyTimeout set, if any.
+func (f *Factory) StopTimeout() {
+	if f.stopTimeout != nil {
+		f.stopTimeout()
+	}
+}
+
+// TimedOut reports whether --timeout ran out.
+func (f *Factory) TimedOut() bool {
+	return f.stopTimeout != nil && errors.Is(f.Context().Err(), context.DeadlineExceeded)
+}
@@ -0,0 +1,89 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorDisabled(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		mode                                  string
+		explicitDisable, force, dumb, tty, vt bool
+		want                                  bool
+	}{
+		{name: "tty with vt", tty: true, vt: true, want: false},
+		{name: "piped", vt: true, want: true},
+		{name: "dumb terminal", dumb: true, tty: true, vt: true, want: true},
+		{name: "legacy console", tty: true, want: true},
+		{name: "force on legacy console", force: true, tty: true, want: false},
+		{name: "force when piped", force: true, vt: true, want: false},
+		{name: "explicit beats force", explicitDisable: true, force: true, tty: true, vt: true, want: true},
+		{name: "always when piped", mode: ColorAlways, vt: true, want: false},
+		{name: "always beats explicit", mode: ColorAlways, explicitDisable: true, want: false},
+		{name: "never on tty", mode: ColorNever, force: true, tty: true, vt: true, want: true},
+		{name: "auto", mode: ColorAuto, tty: true, vt: true, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, colorDisabled(tc.mode, tc.explicitDisable, tc.force, tc.dumb, tc.tty, tc.vt))
+		})
+	}
+}
+
+func TestInitOutputColor(t *testing.T) {
+	old := output.NoColor
+	t.Cleanup(func() { output.NoColor = old })
+	for _, env := range []string{"NO_COLOR", "TEAMCITY_NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE"} {
+		t.Setenv(env, "")
+	}
+	f := NewFactory()
+
+	f.Color = ColorAlways
+	require.NoError(t, f.InitOutput())
+	assert.False(t, output.NoColor, "always colors piped output")
+
+	f.NoColor = true
+	require.NoError(t, f.InitOutput())
+	assert.True(t, output.NoColor, "--no-color is never")
+
+	f.Color, f.NoColor = ColorAuto, false
+	t.Setenv("CLICOLOR_FORCE", "1")
+	require.NoError(t, f.InitOutput())
+	assert.False(t, output.NoColor, "CLICOLOR_FORCE forces color in auto mode")
+
+	t.Setenv("CLICOLOR_FORCE", "0")
+	require.NoError(t, f.InitOutput())
+	assert.True(t, output.NoColor, "CLICOLOR_FORCE=0 does not force")
+
+	f.Color = "sometimes"
+	assert.ErrorContains(t, f.InitOutput(), `invalid --color "sometimes"`)
+}
+
+func TestRetryCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		flag    int
+		flagSet bool
+		want    int
+	}{
+		{name: "default", want: 3},
+		{name: "env", env: "5", want: 5},
+		{name: "env disables", env: "0", want: 0},
+		{name: "invalid env ignored", env: "-1", want: 3},
+		{name: "flag beats env", env: "5", flag: 1, flagSet: true, want: 1},
+		{name: "negative flag disables", flag: -2, flagSet: true, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(config.EnvRetries, tc.env)
+			f := &Factory{Retries: tc.flag, RetriesSet: tc.flagSet}
+			assert.Equal(t, tc.want, f.RetryCount())
+		})
+	}
+}
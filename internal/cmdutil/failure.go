@@ -13,7 +13,8 @@ import (
 
 const maxFailedTestsToShow = 10
 
-func PrintFailureSummary(ctx context.Context, p *output.Printer, client api.ClientInterface, buildID, buildNumber, webURL, statusText string) {
+// PrintFailureSummary prints a failed build's problems and failed tests; links adds a web link under each.
+func PrintFailureSummary(ctx context.Context, p *output.Printer, client api.ClientInterface, buildID, buildNumber, webURL, statusText string, links bool) {
 	header := fmt.Sprintf("%s %s  #%s failed", output.Red(output.Sym().Cross), buildID, buildNumber)
 	if statusText != "" {
 		header += ": " + statusText
@@ -44,6 +45,9 @@ func PrintFailureSummary(ctx context.Context, p *output.Printer, client api.Clie
 				detail = prob.Identity
 			}
 			_, _ = fmt.Fprintf(p.Out, "  %s %s\n", output.Red(output.Sym().Bullet), detail)
+			if links {
+				_, _ = fmt.Fprintf(p.Out, "    %s\n", output.Faint(ProblemOccurrenceURL(webURL, prob)))
+			}
 		}
 	}
 
@@ -61,6 +65,9 @@ func PrintFailureSummary(ctx context.Context, p *output.Printer, client api.Clie
 				line += " " + output.Faint(fmt.Sprintf("(failing since #%s)", t.FirstFailed.Build.Number))
 			}
 			_, _ = fmt.Fprintln(p.Out, line)
+			if links {
+				_, _ = fmt.Fprintf(p.Out, "    %s\n", output.Faint(TestOccurrenceURL(webURL, t)))
+			}
 			if t.Details != "" {
 				for dl := range strings.SplitSeq(strings.TrimSpace(t.Details), "\n") {
 					_, _ = fmt.Fprintf(p.Out, "    %s\n", output.Faint(dl))
@@ -77,24 +84,71 @@ func PrintFailureSummary(ctx context.Context, p *output.Printer, client api.Clie
 
 // BuildResultError prints the final build result and returns an appropriate exit error.
 // Used by both the standard watch and TUI watch paths.
+// With showDetails, the run summary (duration, test deltas, problems, log errors) comes last.
+// A canceled run is reported as canceled even when it had already failed, with no failure summary.
 func BuildResultError(ctx context.Context, p *output.Printer, client api.ClientInterface, build *api.Build, showDetails bool) error {
-	jobName := build.BuildTypeID
-	if build.BuildType != nil {
-		jobName = build.BuildType.Name
+	if showDetails {
+		defer PrintRunSummary(p, CollectRunSummary(ctx, client, build))
+	}
+	if !RunCanceled(build) && build.Status != "SUCCESS" {
+		PrintFailureSummary(ctx, p, client, strconv.Itoa(build.ID), build.Number, build.WebURL, build.StatusText, false)
+		return &ExitError{Code: ExitFailure}
+	}
+	err := BuildResultBrief(p, build)
+	if build.Status == "SUCCESS" && showDetails {
+		_, _ = fmt.Fprintf(p.Out, "\nView details: %s\n", build.WebURL)
 	}
+	return err
+}
+
+// BuildResultBrief prints the final build result on one line, without the failure summary,
+// and returns the same exit error as BuildResultError.
+func BuildResultBrief(p *output.Printer, build *api.Build) error {
+	jobName := JobName(build.BuildType, build.BuildTypeID)
 
-	switch build.Status {
-	case "SUCCESS":
-		_, _ = fmt.Fprintf(p.Out, "%s %s %d  #%s succeeded\n", output.Green(output.Sym().Check), output.Cyan(jobName), build.ID, build.Number)
-		if showDetails {
-			_, _ = fmt.Fprintf(p.Out, "\nView details: %s\n", build.WebURL)
+	switch {
+	case RunCanceled(build):
+		line := fmt.Sprintf("%s %s %d  %s canceled", output.Yellow(output.Sym().Neutral), output.Cyan(jobName), build.ID, RunNumber(build.Number))
+		if by := CanceledBy(build.CanceledInfo); by != "" {
+			line += " " + by
 		}
-		return nil
-	case "FAILURE":
-		PrintFailureSummary(ctx, p, client, strconv.Itoa(build.ID), build.Number, build.WebURL, build.StatusText)
-		return &ExitError{Code: ExitFailure}
+		_, _ = fmt.Fprintln(p.Out, line)
+		if build.CanceledInfo != nil && build.CanceledInfo.Text != "" {
+			_, _ = fmt.Fprintf(p.Out, "  %s\n", output.Faint(build.CanceledInfo.Text))
+		}
+	case build.Status == "SUCCESS":
+		_, _ = fmt.Fprintf(p.Out, "%s %s %d  %s succeeded\n", output.Green(output.Sym().Check), output.Cyan(jobName), build.ID, RunNumber(build.Number))
 	default:
-		_, _ = fmt.Fprintf(p.Out, "%s %s %d  #%s canceled\n", output.Yellow(output.Sym().Neutral), output.Cyan(jobName), build.ID, build.Number)
+		_, _ = fmt.Fprintf(p.Out, "%s %s %d  %s failed\n", output.Red(output.Sym().Cross), output.Cyan(jobName), build.ID, RunNumber(build.Number))
+	}
+	return BuildExitError(build)
+}
+
+// BuildExitError returns the exit error for a finished run: nil on success, ExitCancelled when it was
+// canceled, and ExitFailure otherwise, so wrappers can tell an intentional cancel from a failure to retry.
+func BuildExitError(build *api.Build) error {
+	switch {
+	case RunCanceled(build):
 		return &ExitError{Code: ExitCancelled}
+	case build.Status == "SUCCESS":
+		return nil
+	}
+	return &ExitError{Code: ExitFailure}
+}
+
+// RunCanceled reports whether build was canceled. TeamCity sets canceledInfo as soon as someone stops
+// the run, before it finishes and whatever its status; servers that omit it finish the run with status UNKNOWN.
+func RunCanceled(build *api.Build) bool {
+	return build.CanceledInfo != nil || (build.State == "finished" && build.Status == "UNKNOWN")
+}
+
+// CanceledBy returns "by <user>" for who canceled a run, or "" when it is not known.
+func CanceledBy(info *api.CanceledInfo) string {
+	if info == nil {
+		return ""
+	}
+	if name := UserName(info.User); name != "" {
+		return "by " + name
 	}
+	return ""
 }
@@ -34,7 +34,7 @@ func failureSummaryFixture(t *testing.T, tests api.TestOccurrences, problems api
 	var buf bytes.Buffer
 	p := &output.Printer{Out: &buf, ErrOut: &buf}
 	client := api.NewClient(ts.URL, "test")
-	PrintFailureSummary(t.Context(), p, client, "123", "42", "https://tc/build/123", statusText)
+	PrintFailureSummary(t.Context(), p, client, "123", "42", "https://tc/build/123", statusText, false)
 	return ansi.Strip(buf.String())
 }
 
@@ -194,10 +194,39 @@ func TestPrintFailureSummary(t *testing.T) {
 		var buf bytes.Buffer
 		p := &output.Printer{Out: &buf, ErrOut: &buf}
 		client := api.NewClient(ts.URL, "test")
-		PrintFailureSummary(t.Context(), p, client, "1", "42", "https://tc/build/1", "")
+		PrintFailureSummary(t.Context(), p, client, "1", "42", "https://tc/build/1", "", false)
 		out := ansi.Strip(buf.String())
 		// Should still print header and URL, not panic
 		assert.Contains(t, out, "#42 failed")
 		assert.Contains(t, out, "https://tc/build/1")
 	})
 }
+
+func TestBuildExitError(t *testing.T) {
+	canceledInfo := &api.CanceledInfo{User: &api.User{Username: "jdoe", Name: "Jane Doe"}, Text: "wrong branch"}
+	code := func(b api.Build) int {
+		err := BuildExitError(&b)
+		if err == nil {
+			return 0
+		}
+		return err.(*ExitError).Code
+	}
+
+	assert.Equal(t, 0, code(api.Build{State: "finished", Status: "SUCCESS"}))
+	assert.Equal(t, ExitFailure, code(api.Build{State: "finished", Status: "FAILURE"}))
+	assert.Equal(t, ExitFailure, code(api.Build{State: "finished", Status: "ERROR"}), "an error is a failure, not a cancel")
+	assert.Equal(t, ExitCancelled, code(api.Build{State: "finished", Status: "FAILURE", CanceledInfo: canceledInfo}), "a canceled run that had failed is canceled")
+	assert.Equal(t, ExitCancelled, code(api.Build{State: "running", Status: "SUCCESS", CanceledInfo: canceledInfo}), "a run being stopped is canceled")
+	assert.Equal(t, ExitCancelled, code(api.Build{State: "finished", Status: "UNKNOWN"}), "servers without canceledInfo")
+
+	assert.Equal(t, "by Jane Doe", CanceledBy(canceledInfo))
+	assert.Empty(t, CanceledBy(&api.CanceledInfo{}))
+
+	var buf bytes.Buffer
+	err := BuildResultBrief(&output.Printer{Out: &buf}, &api.Build{ID: 7, Number: "3", BuildTypeID: "App_Build", State: "finished", Status: "FAILURE", CanceledInfo: canceledInfo})
+	assert.Equal(t, &ExitError{Code: ExitCancelled}, err)
+	out := ansi.Strip(buf.String())
+	assert.Contains(t, out, "App_Build 7  #3 canceled by Jane Doe")
+	assert.Contains(t, out, "wrong branch")
+	assert.NotContains(t, out, "failed")
+}
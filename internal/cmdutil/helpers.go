@@ -5,8 +5,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/clipboard"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/JetBrains/teamcity-cli/internal/datebucket"
 	"github.com/JetBrains/teamcity-cli/internal/output"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
@@ -63,10 +67,64 @@ func OpenURLOrWarn(p *output.Printer, url string) {
 	}
 }
 
+// CopyToClipboard puts text on the system clipboard; overridable in tests.
+var CopyToClipboard = clipboard.Copy
+
+// CopyOptions holds --copy: which of an entity's identifiers to put on the clipboard, "url" or "id".
+type CopyOptions struct {
+	What string
+}
+
+// AddCopyFlag adds --copy, which copies the web URL, or the ID with --copy=id.
+func AddCopyFlag(cmd *cobra.Command, opts *CopyOptions) {
+	cmd.Flags().StringVar(&opts.What, "copy", "", "Copy the web URL to the clipboard (--copy=id for the ID)")
+	cmd.Flags().Lookup("copy").NoOptDefVal = "url"
+	_ = cmd.RegisterFlagCompletionFunc("copy", cobra.FixedCompletions([]string{"url", "id"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// Validate rejects a --copy value other than url or id.
+func (o *CopyOptions) Validate() error {
+	switch o.What {
+	case "", "url", "id":
+		return nil
+	}
+	return api.Validation(fmt.Sprintf("invalid --copy value %q", o.What), "Use --copy for the web URL or --copy=id for the ID")
+}
+
+// Copy handles --copy: puts url or id on the clipboard and confirms on stderr, so stdout keeps its format.
+// A failure is only warned about, so it is safe to call after a mutation.
+func (o *CopyOptions) Copy(p *output.Printer, url, id string) {
+	text := url
+	if o.What == "id" {
+		text = id
+	}
+	if o.What == "" || text == "" {
+		return
+	}
+	if err := CopyToClipboard(text); err != nil {
+		p.Warn("could not copy to clipboard: %v", err)
+		return
+	}
+	if !p.Quiet {
+		_, _ = fmt.Fprintf(p.ErrOut, "%s Copied %s to clipboard\n", output.Green(output.Sym().Check), text)
+	}
+}
+
 // ValidateLimit returns an error if limit is negative. Zero means "fetch all".
 func ValidateLimit(limit int) error {
 	if limit < 0 {
-		return fmt.Errorf("--limit must not be negative, got %d", limit)
+		return api.Validation(fmt.Sprintf("--limit must not be negative, got %d", limit), "Use a positive number, or --limit 0 to fetch all")
+	}
+	return nil
+}
+
+// CheckLimit validates limit for a command about to fetch, warning when it is above the soft cap from config.LimitWarn.
+func CheckLimit(f *Factory, limit int) error {
+	if err := ValidateLimit(limit); err != nil {
+		return err
+	}
+	if soft := config.LimitWarn(); soft > 0 && limit > soft {
+		f.Printer.Warn("--limit %d is above %d; large lists are slow to fetch and render", limit, soft)
 	}
 	return nil
 }
@@ -106,6 +164,33 @@ func (f *Factory) WarnInsecureHTTP(serverURL, credentialType string) {
 	f.Printer.Warn("Consider using HTTPS for secure communication.")
 }
 
+func (f *Factory) warnClockSkew(skew time.Duration) {
+	f.Printer.Warn("Local clock is %s; relative times follow the server's clock", ClockSkewText(skew))
+}
+
+// ClockSkewText describes skew, the server's time minus the local time, as where the local clock stands.
+func ClockSkewText(skew time.Duration) string {
+	skew = skew.Round(time.Second)
+	switch {
+	case skew < 0:
+		return output.FormatDuration(-skew) + " ahead of the server"
+	case skew > 0:
+		return output.FormatDuration(skew) + " behind the server"
+	default:
+		return "in sync with the server"
+	}
+}
+
+// DateLayout returns the layout for calendar dates in output: the date_format config key, or the usual format of
+// the user's locale when it is unset. An unknown value in a hand-edited config falls back to ISO 8601.
+func DateLayout() string {
+	layout, err := datebucket.DateLayout(config.GetDateFormat(), datebucket.Locale())
+	if err != nil {
+		return datebucket.DateFormats["iso"]
+	}
+	return layout
+}
+
 // FormatAgentStatus returns a formatted status string for an agent.
 func FormatAgentStatus(a api.Agent) string {
 	if !a.Authorized {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/output"
@@ -22,6 +23,12 @@ func TestValidateLimit(t *testing.T) {
 	assert.Contains(t, ValidateLimit(-5).Error(), "--limit must not be negative")
 }
 
+func TestClockSkewText(t *testing.T) {
+	assert.Equal(t, "5m 0s behind the server", ClockSkewText(5*time.Minute))
+	assert.Equal(t, "2h 3m ahead of the server", ClockSkewText(-(2*time.Hour + 3*time.Minute + 400*time.Millisecond)))
+	assert.Equal(t, "in sync with the server", ClockSkewText(300*time.Millisecond))
+}
+
 func TestParseID(t *testing.T) {
 	id, err := ParseID("42", "build")
 	require.NoError(t, err)
@@ -55,6 +62,27 @@ func TestFormatAgentStatus(t *testing.T) {
 	}
 }
 
+func TestAccessorsOnHiddenFields(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, UserName(nil))
+	assert.Equal(t, "alice", UserName(&api.User{Username: "alice"}))
+	assert.Equal(t, "Alice", UserName(&api.User{Username: "alice", Name: "Alice"}))
+
+	assert.Empty(t, TriggeredBy(nil))
+	assert.Equal(t, "vcs", TriggeredBy(&api.Triggered{Type: "vcs"}))
+	assert.Equal(t, "vcs", TriggeredBy(&api.Triggered{Type: "vcs", User: &api.User{}}))
+	assert.Equal(t, "alice", TriggeredBy(&api.Triggered{Type: "user", User: &api.User{Username: "alice"}}))
+
+	assert.Equal(t, "Falcon_Build", JobName(nil, "Falcon_Build"))
+	assert.Equal(t, "Falcon_Build", JobName(&api.BuildType{}, "Falcon_Build"))
+	assert.Equal(t, "Build", JobName(&api.BuildType{Name: "Build"}, "Falcon_Build"))
+
+	assert.Empty(t, AgentName(nil))
+	assert.Empty(t, RunNumber(""))
+	assert.Equal(t, "#42", RunNumber("42"))
+}
+
 func TestAddViewFlags(t *testing.T) {
 	cmd := &cobra.Command{Use: "test"}
 	opts := &ViewOptions{}
@@ -104,6 +132,47 @@ func TestEmitWebURL(t *testing.T) {
 	})
 }
 
+func TestCopyOptions(t *testing.T) {
+	orig := CopyToClipboard
+	t.Cleanup(func() { CopyToClipboard = orig })
+
+	t.Run("copies url or id and confirms on stderr only", func(t *testing.T) {
+		var copied []string
+		CopyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+		var out, errOut bytes.Buffer
+		p := &output.Printer{Out: &out, ErrOut: &errOut}
+
+		(&CopyOptions{What: "url"}).Copy(p, "https://tc.example.com/build/1", "1")
+		(&CopyOptions{What: "id"}).Copy(p, "https://tc.example.com/build/1", "1")
+		(&CopyOptions{}).Copy(p, "https://tc.example.com/build/1", "1")
+
+		assert.Equal(t, []string{"https://tc.example.com/build/1", "1"}, copied)
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), "Copied https://tc.example.com/build/1 to clipboard")
+		assert.Contains(t, errOut.String(), "Copied 1 to clipboard")
+	})
+
+	t.Run("failure warns but does not error", func(t *testing.T) {
+		CopyToClipboard = func(string) error { return errors.New("no clipboard available: no display server") }
+		var out, errOut bytes.Buffer
+		p := &output.Printer{Out: &out, ErrOut: &errOut}
+
+		(&CopyOptions{What: "url"}).Copy(p, "https://tc.example.com/build/1", "1")
+
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), "could not copy to clipboard: no clipboard available")
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		for _, v := range []string{"", "url", "id"} {
+			assert.NoError(t, (&CopyOptions{What: v}).Validate(), v)
+		}
+		err := (&CopyOptions{What: "name"}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid --copy value "name"`)
+	})
+}
+
 func TestEmitListWebURL(t *testing.T) {
 	orig := OpenInBrowser
 	t.Cleanup(func() { OpenInBrowser = orig })
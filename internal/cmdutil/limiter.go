@@ -0,0 +1,57 @@
+package cmdutil
+
+import (
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+// Limiter bounds in-flight requests for bulk commands and halves the bound whenever the server reports new throttling.
+type Limiter struct {
+	throttle *api.Throttle
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	seen   int64
+}
+
+// NewLimiter returns a Limiter starting at workers concurrent slots, backing off as f's clients get throttled.
+func (f *Factory) NewLimiter(workers int) *Limiter {
+	l := &Limiter{throttle: f.Throttle(), limit: max(workers, 1)}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until a slot is free under the current limit.
+func (l *Limiter) Acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		if hits := l.throttle.Hits(); hits > l.seen {
+			l.seen = hits
+			l.limit = max(l.limit/2, 1)
+		}
+		if l.active < l.limit {
+			l.active++
+			return
+		}
+		l.cond.Wait()
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	l.active--
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+// Limit returns the current concurrency bound.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
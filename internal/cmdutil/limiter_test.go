@@ -0,0 +1,52 @@
+package cmdutil
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterBacksOffWhenThrottled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	var errOut bytes.Buffer
+	f := &Factory{Printer: &output.Printer{Out: &bytes.Buffer{}, ErrOut: &errOut}}
+	client := api.NewClient(server.URL, "token", api.WithThrottle(f.Throttle()))
+
+	limiter := f.NewLimiter(8)
+	limiter.Acquire()
+	limiter.Release()
+	assert.Equal(t, 8, limiter.Limit())
+
+	for range 2 {
+		_, err := client.RawRequest(t.Context(), "POST", "/app/rest/buildQueue", nil, nil)
+		require.NoError(t, err)
+	}
+	limiter.Acquire()
+	limiter.Release()
+	assert.Equal(t, 4, limiter.Limit(), "new throttling since the last check halves the limit once")
+	assert.Equal(t, 1, bytes.Count(errOut.Bytes(), []byte("throttling")), "the notice is printed once per command")
+	assert.Contains(t, errOut.String(), "retrying in 5s")
+
+	limiter.Acquire()
+	limiter.Release()
+	assert.Equal(t, 4, limiter.Limit(), "no new throttling keeps the limit")
+}
+
+func TestLimiterNeverDropsBelowOne(t *testing.T) {
+	f := &Factory{}
+	limiter := f.NewLimiter(0)
+	limiter.Acquire()
+	limiter.Release()
+	assert.Equal(t, 1, limiter.Limit())
+}
@@ -84,8 +84,13 @@ func (f *Factory) ResolveProject(explicit string) string {
 	return ""
 }
 
-// ResolveDefaultJob returns explicit, then TEAMCITY_JOB, then scope.Job, then a single Jobs entry.
+// ResolveDefaultJob returns explicit, then TEAMCITY_JOB, then scope.Job, then a single Jobs entry,
+// mapped through any rename followed with FollowRename.
 func (f *Factory) ResolveDefaultJob(explicit string) string {
+	return f.renamedJob(f.resolveDefaultJob(explicit))
+}
+
+func (f *Factory) resolveDefaultJob(explicit string) string {
 	if explicit != "" {
 		return explicit
 	}
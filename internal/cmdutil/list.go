@@ -2,10 +2,13 @@ package cmdutil
 
 import (
 	"cmp"
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/dustin/go-humanize/english"
 	"github.com/spf13/cobra"
 )
 
@@ -15,8 +18,15 @@ type ListFlags struct {
 	JSONFields string
 	Plain      bool
 	NoHeader   bool
+	PageSize   int
+	// Template is --template, on the lists that add it with AddTemplateFlag.
+	Template string
+	SortFlags
 }
 
+// DefaultPageSize is the --page-size of streamed lists: small enough that the first page renders in well under a second on large servers.
+const DefaultPageSize = 100
+
 // AddListFlags registers --limit, --json, --plain, and --no-header flags on a command.
 func AddListFlags(cmd *cobra.Command, flags *ListFlags, defaultLimit int) {
 	cmd.Flags().IntVarP(&flags.Limit, "limit", "n", defaultLimit, "Maximum number of items (0 for all)")
@@ -32,6 +42,11 @@ func AddPlainFlags(cmd *cobra.Command, flags *ListFlags) {
 	cmd.MarkFlagsMutuallyExclusive("json", "plain")
 }
 
+// AddPageSizeFlag registers --page-size on a list command printed with RunStreamList.
+func AddPageSizeFlag(cmd *cobra.Command, flags *ListFlags) {
+	cmd.Flags().IntVar(&flags.PageSize, "page-size", DefaultPageSize, "Number of items to fetch per request; rows print as each page arrives")
+}
+
 // ListTable holds the data needed to print a table.
 type ListTable struct {
 	Headers  []string
@@ -40,11 +55,13 @@ type ListTable struct {
 }
 
 // ListResult is returned by a list command's fetch function.
-// Set either JSON (for JSON output) or Table (for table output).
+// Set either JSON (for JSON output) or Table (for table output); lists with --template also set Items, the slice
+// of listed items.
 // EmptyTip is shown alongside EmptyMsg when the table is empty.
 // Truncated reports that a finite --limit capped the result; RunList turns it into a stderr hint.
 type ListResult struct {
 	JSON      any
+	Items     any
 	Table     ListTable
 	EmptyMsg  string
 	EmptyTip  string
@@ -61,7 +78,7 @@ func RunList(
 	fetch func(client api.ClientInterface, fields []string) (*ListResult, error),
 ) error {
 	if cmd.Flags().Lookup("limit") != nil {
-		if err := ValidateLimit(flags.Limit); err != nil {
+		if err := CheckLimit(f, flags.Limit); err != nil {
 			return err
 		}
 	}
@@ -73,22 +90,38 @@ func RunList(
 	if showHelp {
 		return nil
 	}
+	tmpl, err := ParseTemplate(flags.Template)
+	if err != nil {
+		return err
+	}
 
 	client, err := f.Client()
 	if err != nil {
 		return err
 	}
 
-	result, err := fetch(client, jsonResult.Fields)
+	fields := jsonResult.Fields
+	if tmpl != nil {
+		fields = fieldSpec.Available
+	}
+	result, err := fetch(client, fields)
 	if err != nil {
 		return err
 	}
 
+	if tmpl != nil {
+		if err := tmpl.ExecuteEach(f.Printer.Out, result.Items); err != nil {
+			return err
+		}
+		WarnSortedListTruncated(f, &flags.SortFlags, result.Truncated, flags.Limit)
+		return nil
+	}
+
 	if jsonResult.Enabled {
 		if err := f.Printer.PrintJSON(result.JSON); err != nil {
 			return err
 		}
-		WarnListTruncated(f, result.Truncated, flags.Limit)
+		WarnSortedListTruncated(f, &flags.SortFlags, result.Truncated, flags.Limit)
 		return nil
 	}
 
@@ -109,10 +142,24 @@ func RunList(
 		}
 		f.Printer.PrintTable(result.Table.Headers, result.Table.Rows)
 	}
-	WarnListTruncated(f, result.Truncated, flags.Limit)
+	WarnSortedListTruncated(f, &flags.SortFlags, result.Truncated, flags.Limit)
 	return nil
 }
 
+// Interrupted reports whether err comes from the user interrupting the command, as opposed to a failure or
+// --timeout; lists then print what they fetched before it.
+func Interrupted(f *Factory, err error) bool {
+	return err != nil && errors.Is(f.Context().Err(), context.Canceled)
+}
+
+// WarnInterrupted emits a stderr note that an interrupted list holds only the n items fetched before the interrupt,
+// and returns the error the command exits with, so scripts can tell the partial list from a complete one.
+func WarnInterrupted(f *Factory, n int, noun string) error {
+	_, _ = fmt.Fprintln(f.Printer.ErrOut)
+	f.Printer.Warn("Interrupted - showing the %s fetched so far", english.Plural(n, noun, ""))
+	return &ExitError{Code: ExitCancelled}
+}
+
 // WarnListTruncated emits a stderr hint, set off by a blank line, when a finite --limit capped the result; no-op for --limit <= 0 or under --quiet.
 func WarnListTruncated(f *Factory, truncated bool, limit int) {
 	if !truncated || limit <= 0 || f.Printer.Quiet {
@@ -121,3 +168,172 @@ func WarnListTruncated(f *Factory, truncated bool, limit int) {
 	_, _ = fmt.Fprintln(f.Printer.ErrOut)
 	f.Printer.Warn("Showing only the first %d results - use --limit 0 to fetch all", limit)
 }
+
+// WarnSortedListTruncated is WarnListTruncated for sortable lists: when --sort or --desc was given, it says that
+// only the fetched results were sorted, since the rest of the list could have sorted ahead of them.
+func WarnSortedListTruncated(f *Factory, sort *SortFlags, truncated bool, limit int) {
+	if !sort.Requested() || !truncated || limit <= 0 || f.Printer.Quiet {
+		WarnListTruncated(f, truncated, limit)
+		return
+	}
+	_, _ = fmt.Fprintln(f.Printer.ErrOut)
+	f.Printer.Warn("Sorted only the first %d results fetched - use --limit 0 to fetch and sort all", limit)
+}
+
+// StreamedList describes how RunStreamList prints a list.
+// JSONKey names the array field of the --json envelope (e.g. "buildType"); Noun is what the count footer counts (e.g. "job").
+type StreamedList struct {
+	JSONKey  string
+	Headers  []string
+	FlexCols []int
+	Noun     string
+	EmptyMsg string
+	EmptyTip string
+}
+
+// StreamFetch fetches a list page by page, calling emit with each page's items and their table rows as they arrive; it reports whether a finite --limit capped the result.
+type StreamFetch[T any] func(client api.ClientInterface, fields []string, emit func(items []T, rows [][]string) error) (truncated bool, err error)
+
+// RunStreamList is RunList for lists that can run to tens of thousands of items: rows (or --json array elements)
+// are printed as each page arrives rather than after the last, and the table ends with a count footer.
+// Ordering by --sort or --desc needs every page first, so only --sort none streams; fetch must emit one row per item.
+func RunStreamList[T any](
+	f *Factory,
+	cmd *cobra.Command,
+	flags *ListFlags,
+	fieldSpec *api.FieldSpec,
+	list StreamedList,
+	columns []SortColumn[T],
+	fetch StreamFetch[T],
+) error {
+	if err := CheckLimit(f, flags.Limit); err != nil {
+		return err
+	}
+	if err := ValidateSort(&flags.SortFlags, columns); err != nil {
+		return err
+	}
+	if flags.reorders() {
+		fetch = sortedFetch(f, fetch, &flags.SortFlags, columns)
+	}
+	if flags.PageSize <= 0 {
+		return api.Validation(fmt.Sprintf("--page-size must be positive, got %d", flags.PageSize), fmt.Sprintf("The default is %d", DefaultPageSize))
+	}
+
+	jsonResult, showHelp, err := ParseJSONFields(cmd, flags.JSONFields, fieldSpec, f.Printer.Out)
+	if err != nil {
+		return err
+	}
+	if showHelp {
+		return nil
+	}
+	if err := ValidateSortFields(&flags.SortFlags, columns, jsonResult.Fields); err != nil {
+		return err
+	}
+	tmpl, err := ParseTemplate(flags.Template)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	// Templates render item by item as pages arrive, and may use any field --json can give.
+	if tmpl != nil {
+		written := 0
+		truncated, err := fetch(client, fieldSpec.Available, func(items []T, _ [][]string) error {
+			written += len(items)
+			return tmpl.ExecuteEach(f.Printer.Out, items)
+		})
+		if err != nil && !Interrupted(f, err) {
+			return err
+		}
+		if err != nil {
+			return WarnInterrupted(f, written, list.Noun)
+		}
+		WarnSortedListTruncated(f, &flags.SortFlags, truncated, flags.Limit)
+		return nil
+	}
+
+	if jsonResult.Enabled {
+		w := output.NewJSONListWriter[T](f.Printer, list.JSONKey)
+		written := 0
+		truncated, err := fetch(client, jsonResult.Fields, func(items []T, _ [][]string) error {
+			written += len(items)
+			return w.Write(items)
+		})
+		if err != nil && !Interrupted(f, err) {
+			w.Abort()
+			return err
+		}
+		w.Close()
+		if err != nil {
+			return WarnInterrupted(f, written, list.Noun)
+		}
+		WarnSortedListTruncated(f, &flags.SortFlags, truncated, flags.Limit)
+		return nil
+	}
+
+	table := f.Printer.NewTableStream(list.Headers, list.FlexCols, flags.Plain, flags.NoHeader)
+	truncated, err := fetch(client, jsonResult.Fields, func(_ []T, rows [][]string) error {
+		table.Write(rows)
+		return nil
+	})
+	if err != nil && !Interrupted(f, err) {
+		return err
+	}
+	if err != nil {
+		return WarnInterrupted(f, table.Rows(), list.Noun)
+	}
+
+	if table.Rows() == 0 {
+		tip := list.EmptyTip
+		if truncated && flags.Limit > 0 {
+			tip = fmt.Sprintf("Searched only the first %d results - use --limit 0 to fetch all", flags.Limit)
+		}
+		f.Printer.Empty(cmp.Or(list.EmptyMsg, "No items found"), tip)
+		return nil
+	}
+	if !flags.Plain {
+		noun := list.Noun
+		if table.Rows() != 1 {
+			noun += "s"
+		}
+		f.Printer.Info("\n%s", output.Faint(fmt.Sprintf("%d %s", table.Rows(), noun)))
+	}
+	WarnSortedListTruncated(f, &flags.SortFlags, truncated, flags.Limit)
+	return nil
+}
+
+// sortedFetch collects every page fetch emits and emits them once, ordered by flags; when fetching is interrupted,
+// it emits the pages that arrived before returning the error.
+func sortedFetch[T any](f *Factory, fetch StreamFetch[T], flags *SortFlags, columns []SortColumn[T]) StreamFetch[T] {
+	type sortedRow struct {
+		item T
+		row  []string
+	}
+	return func(client api.ClientInterface, fields []string, emit func([]T, [][]string) error) (bool, error) {
+		var all []sortedRow
+		truncated, err := fetch(client, fields, func(items []T, rows [][]string) error {
+			for i, item := range items {
+				all = append(all, sortedRow{item: item, row: rows[i]})
+			}
+			return nil
+		})
+		if err != nil && !Interrupted(f, err) {
+			return truncated, err
+		}
+		if err := sortBy(all, func(r sortedRow) T { return r.item }, flags, columns); err != nil {
+			return truncated, err
+		}
+		items, rows := make([]T, len(all)), make([][]string, len(all))
+		for i, r := range all {
+			items[i], rows[i] = r.item, r.row
+		}
+		if emitErr := emit(items, rows); emitErr != nil {
+			return truncated, emitErr
+		}
+		return truncated, err
+	}
+}
@@ -0,0 +1,92 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+// RenamedJobError is a job-not-found error for a reference the job may have been renamed away from.
+type RenamedJobError struct {
+	*api.NotFoundError
+	Candidates []string // IDs of the jobs the reference may now point to
+}
+
+func (e *RenamedJobError) Error() string {
+	if len(e.Candidates) == 1 {
+		return fmt.Sprintf("job '%s' was not found; did you mean '%s' (renamed)?", e.ID, e.Candidates[0])
+	}
+	return e.NotFoundError.Error()
+}
+
+func (e *RenamedJobError) Suggestion() string {
+	if len(e.Candidates) == 1 {
+		return fmt.Sprintf("Update the reference to '%s', or pass --follow-renames to continue with it", e.Candidates[0])
+	}
+	return "It may have been renamed to one of: " + strings.Join(e.Candidates, ", ")
+}
+
+func (e *RenamedJobError) Unwrap() error { return e.NotFoundError }
+
+// NewID returns the ID the job was renamed to, or "" when there is not exactly one candidate.
+func (e *RenamedJobError) NewID() string {
+	if len(e.Candidates) != 1 {
+		return ""
+	}
+	return e.Candidates[0]
+}
+
+// SuggestRenamedJob looks for the jobs a not-found job reference in err may have been renamed to. It returns
+// a *RenamedJobError when there are candidates and err unchanged otherwise, including when the search fails.
+func SuggestRenamedJob(f *Factory, err error) error {
+	nf, ok := errors.AsType[*api.NotFoundError](err)
+	if !ok || nf.Resource != "job" || nf.ID == "" {
+		return err
+	}
+	client, cerr := f.Client()
+	if cerr != nil {
+		return err
+	}
+	found, serr := client.FindRenamedBuildTypes(nf.ID)
+	if serr != nil || len(found) == 0 {
+		return err
+	}
+	candidates := make([]string, len(found))
+	for i, bt := range found {
+		candidates[i] = bt.ID
+	}
+	return &RenamedJobError{NotFoundError: nf, Candidates: candidates}
+}
+
+// FollowRename makes job references resolve oldID to newID for the rest of the invocation: ResolveDefaultJob
+// maps it, and RenameJobArgs rewrites it in command-line arguments.
+func (f *Factory) FollowRename(oldID, newID string) {
+	if f.renamedJobs == nil {
+		f.renamedJobs = map[string]string{}
+	}
+	f.renamedJobs[oldID] = newID
+}
+
+// renamedJob returns the ID job was renamed to under FollowRename, or job itself.
+func (f *Factory) renamedJob(job string) string {
+	if newID, ok := f.renamedJobs[job]; ok {
+		return newID
+	}
+	return job
+}
+
+// RenameJobArgs returns args with every argument equal to oldID, or a --flag=oldID value, replaced by newID.
+func RenameJobArgs(args []string, oldID, newID string) []string {
+	renamed := make([]string, len(args))
+	for i, arg := range args {
+		if flag, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(flag, "-") && value == oldID {
+			arg = flag + "=" + newID
+		} else if arg == oldID {
+			arg = newID
+		}
+		renamed[i] = arg
+	}
+	return renamed
+}
@@ -0,0 +1,27 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameJobArgs(t *testing.T) {
+	args := []string{"run", "start", "Falcon_Build", "--job=Falcon_Build", "-m", "Falcon_Build_Old", "--comment=x=Falcon_Build"}
+	assert.Equal(t,
+		[]string{"run", "start", "Falcon_Backend_Build", "--job=Falcon_Backend_Build", "-m", "Falcon_Build_Old", "--comment=x=Falcon_Build"},
+		RenameJobArgs(args, "Falcon_Build", "Falcon_Backend_Build"))
+}
+
+func TestResolveDefaultJobFollowsRenames(t *testing.T) {
+	t.Setenv(config.EnvJob, "Falcon_Build")
+	f := NewFactory()
+	f.SkipLinkLookup()
+	assert.Equal(t, "Falcon_Build", f.ResolveDefaultJob(""))
+
+	f.FollowRename("Falcon_Build", "Falcon_Backend_Build")
+	assert.Equal(t, "Falcon_Backend_Build", f.ResolveDefaultJob(""))
+	assert.Equal(t, "Hawk_Build", f.ResolveDefaultJob("Hawk_Build"))
+}
@@ -0,0 +1,215 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+)
+
+const (
+	// summaryErrorLines is how many log error lines the run summary shows.
+	summaryErrorLines = 3
+	// summaryLogTail is how many of the last log messages are searched for error lines.
+	summaryLogTail = 500
+	// logStatusError is the status of an error message in the structured build log.
+	logStatusError = 4
+	// maxSummaryLineWidth truncates long log lines so the summary stays a few lines tall.
+	maxSummaryLineWidth = 160
+)
+
+// RunSummary is the verdict of a finished run: how long it queued and ran, how its tests compare with
+// the job's previous finished run, and what went wrong. A nil section means it could not be fetched.
+type RunSummary struct {
+	Duration   time.Duration
+	QueueWait  time.Duration
+	Tests      *api.TestOccurrences
+	Previous   *api.Build
+	PrevTests  *api.TestOccurrences
+	Problems   *int
+	ErrorLines []string
+}
+
+// CollectRunSummary fetches the parts of build's summary concurrently. A failed fetch leaves its part out.
+func CollectRunSummary(ctx context.Context, client api.ClientInterface, build *api.Build) *RunSummary {
+	s := &RunSummary{}
+	queued, qErr := api.ParseTeamCityTime(build.QueuedDate)
+	started, sErr := api.ParseTeamCityTime(build.StartDate)
+	finished, fErr := api.ParseTeamCityTime(build.FinishDate)
+	if sErr == nil && fErr == nil && !finished.Before(started) {
+		s.Duration = finished.Sub(started)
+	}
+	if qErr == nil && sErr == nil && !started.Before(queued) {
+		s.QueueWait = started.Sub(queued)
+	}
+
+	buildID := strconv.Itoa(build.ID)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		if tests, err := client.GetBuildTestSummary(buildID); err == nil {
+			s.Tests = tests
+		}
+	})
+	wg.Go(func() {
+		s.Previous, s.PrevTests = previousRunTests(ctx, client, build)
+	})
+	wg.Go(func() {
+		if problems, err := client.GetBuildProblems(buildID); err == nil {
+			s.Problems = &problems.Count
+		}
+	})
+	if build.Status != "SUCCESS" {
+		wg.Go(func() {
+			s.ErrorLines = lastLogErrors(ctx, client, buildID)
+		})
+	}
+	wg.Wait()
+	return s
+}
+
+// previousRunTests finds the job's last finished run before build on the same branch, with its test counts.
+func previousRunTests(ctx context.Context, client api.ClientInterface, build *api.Build) (*api.Build, *api.TestOccurrences) {
+	prev, err := PreviousRun(ctx, client, build)
+	if err != nil || prev == nil {
+		return nil, nil
+	}
+	tests, err := client.GetBuildTestSummary(strconv.Itoa(prev.ID))
+	if err != nil {
+		return nil, nil
+	}
+	return prev, tests
+}
+
+// PreviousRun returns the job's last finished run before build on the same branch, or nil when there is none.
+// Runs started after build are skipped on the server, so an old build finds its own predecessor.
+func PreviousRun(ctx context.Context, client api.ClientInterface, build *api.Build) (*api.Build, error) {
+	if build.BuildTypeID == "" {
+		return nil, nil
+	}
+	builds, _, err := client.GetBuilds(ctx, api.BuildsOptions{
+		BuildTypeID: build.BuildTypeID,
+		Branch:      build.BranchName,
+		State:       "finished",
+		UntilDate:   build.StartDate,
+		Limit:       5,
+		Fields:      []string{"id", "number", "status", "branchName"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range builds.Builds {
+		if prev := &builds.Builds[i]; prev.ID < build.ID {
+			return prev, nil
+		}
+	}
+	return nil, nil
+}
+
+// lastLogErrors returns the last distinct error lines of the build log, oldest first.
+func lastLogErrors(ctx context.Context, client api.ClientInterface, buildID string) []string {
+	resp, err := client.GetBuildMessages(ctx, buildID, api.BuildMessagesOptions{
+		Count:     -summaryLogTail,
+		Tail:      true,
+		ExpandAll: true,
+	})
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	seen := map[string]bool{}
+	for i := len(resp.Messages) - 1; i >= 0 && len(lines) < summaryErrorLines; i-- {
+		msg := resp.Messages[i]
+		if msg.Status != logStatusError {
+			continue
+		}
+		line, _, _ := strings.Cut(strings.TrimSpace(msg.Text), "\n")
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, output.Truncate(line, maxSummaryLineWidth))
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// PrintRunSummary prints s as a short block under a run's result.
+func PrintRunSummary(p *output.Printer, s *RunSummary) {
+	var lines []string
+	if s.Duration > 0 {
+		line := "Duration: " + output.FormatDuration(s.Duration)
+		if s.QueueWait > 0 {
+			line += output.Faint(" (queued " + output.FormatDuration(s.QueueWait) + ")")
+		}
+		lines = append(lines, line)
+	}
+	if s.Tests != nil && s.Tests.Count > 0 {
+		lines = append(lines, "Tests: "+testCounts(s.Tests)+testDelta(s))
+	}
+	if s.Problems != nil && *s.Problems > 0 {
+		lines = append(lines, fmt.Sprintf("Problems: %d", *s.Problems))
+	}
+	if len(s.ErrorLines) > 0 {
+		lines = append(lines, "Errors:")
+		for _, l := range s.ErrorLines {
+			lines = append(lines, "  "+output.Red(l))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(p.Out)
+	for _, l := range lines {
+		_, _ = fmt.Fprintln(p.Out, l)
+	}
+}
+
+func testCounts(t *api.TestOccurrences) string {
+	failed := fmt.Sprintf("%d failed", t.Failed)
+	if t.Failed > 0 {
+		failed = output.Red(failed)
+	}
+	parts := []string{fmt.Sprintf("%d passed", t.Passed), failed}
+	if t.Ignored > 0 {
+		parts = append(parts, fmt.Sprintf("%d ignored", t.Ignored))
+	}
+	if t.Muted > 0 {
+		parts = append(parts, fmt.Sprintf("%d muted", t.Muted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// testDelta compares the test counts with the previous run, e.g. " (vs #41: +2 failed, 2 new failures)".
+func testDelta(s *RunSummary) string {
+	var parts []string
+	if s.Previous != nil && s.PrevTests != nil {
+		if d := s.Tests.Passed - s.PrevTests.Passed; d != 0 {
+			parts = append(parts, fmt.Sprintf("%+d passed", d))
+		}
+		if d := s.Tests.Failed - s.PrevTests.Failed; d != 0 {
+			parts = append(parts, fmt.Sprintf("%+d failed", d))
+		}
+	}
+	if n := s.Tests.NewFailed; n > 0 {
+		noun := "failures"
+		if n == 1 {
+			noun = "failure"
+		}
+		parts = append(parts, output.Yellow(fmt.Sprintf("%d new %s", n, noun)))
+	}
+	switch {
+	case len(parts) == 0:
+		return ""
+	case s.Previous != nil && s.PrevTests != nil:
+		return output.Faint(" (vs #"+s.Previous.Number+": ") + strings.Join(parts, ", ") + output.Faint(")")
+	default:
+		return " (" + strings.Join(parts, ", ") + ")"
+	}
+}
@@ -0,0 +1,115 @@
+package cmdutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func finishedRun(status string) *api.Build {
+	return &api.Build{
+		ID:          100,
+		Number:      "42",
+		BuildTypeID: "Falcon_Build",
+		BranchName:  "main",
+		State:       "finished",
+		Status:      status,
+		QueuedDate:  "20260407T120000+0000",
+		StartDate:   "20260407T120045+0000",
+		FinishDate:  "20260407T121245+0000",
+	}
+}
+
+// runSummaryFixture serves one run's summary data; the previous run is #41 (ID 90).
+func runSummaryFixture(t *testing.T) *api.Client {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		locator := r.URL.Query().Get("locator")
+		switch {
+		case r.URL.Path == "/app/rest/testOccurrences" && strings.Contains(locator, "id:100"):
+			json.NewEncoder(w).Encode(api.TestOccurrences{Count: 126, Passed: 120, Failed: 3, Ignored: 3, NewFailed: 2})
+		case r.URL.Path == "/app/rest/testOccurrences" && strings.Contains(locator, "id:90"):
+			json.NewEncoder(w).Encode(api.TestOccurrences{Count: 126, Passed: 122, Failed: 1, Ignored: 3})
+		case r.URL.Path == "/app/rest/builds":
+			json.NewEncoder(w).Encode(api.BuildList{Count: 2, Builds: []api.Build{{ID: 100, Number: "42"}, {ID: 90, Number: "41"}}})
+		case r.URL.Path == "/app/rest/problemOccurrences":
+			json.NewEncoder(w).Encode(api.ProblemOccurrences{Count: 2})
+		case r.URL.Path == "/app/messages":
+			json.NewEncoder(w).Encode(api.BuildMessagesResponse{Messages: []api.BuildMessage{
+				{ID: 1, Text: "error: first", Status: logStatusError},
+				{ID: 2, Text: "compiling", Status: 1},
+				{ID: 3, Text: "error: second\nstack trace", Status: logStatusError},
+				{ID: 4, Text: "error: third", Status: logStatusError},
+				{ID: 5, Text: "error: third", Status: logStatusError},
+				{ID: 6, Text: "error: fourth", Status: logStatusError},
+				{ID: 7, Text: "Process exited with code 1", Status: 2},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return api.NewClient(ts.URL, "test")
+}
+
+func TestCollectRunSummary(t *testing.T) {
+	client := runSummaryFixture(t)
+
+	s := CollectRunSummary(t.Context(), client, finishedRun("FAILURE"))
+
+	assert.Equal(t, "12m 0s", output.FormatDuration(s.Duration))
+	assert.Equal(t, "45s", output.FormatDuration(s.QueueWait))
+	require.NotNil(t, s.Tests)
+	assert.Equal(t, 2, s.Tests.NewFailed)
+	require.NotNil(t, s.Previous)
+	assert.Equal(t, "41", s.Previous.Number)
+	require.NotNil(t, s.Problems)
+	assert.Equal(t, 2, *s.Problems)
+	assert.Equal(t, []string{"error: second", "error: third", "error: fourth"}, s.ErrorLines)
+
+	var buf bytes.Buffer
+	PrintRunSummary(&output.Printer{Out: &buf}, s)
+	out := ansi.Strip(buf.String())
+	assert.Contains(t, out, "Duration: 12m 0s (queued 45s)")
+	assert.Contains(t, out, "Tests: 120 passed, 3 failed, 3 ignored (vs #41: -2 passed, +2 failed, 2 new failures)")
+	assert.Contains(t, out, "Problems: 2")
+	assert.Contains(t, out, "Errors:\n  error: second\n  error: third\n  error: fourth\n")
+	assert.LessOrEqual(t, strings.Count(out, "\n"), 15)
+}
+
+func TestCollectRunSummarySuccessSkipsLog(t *testing.T) {
+	client := runSummaryFixture(t)
+
+	s := CollectRunSummary(t.Context(), client, finishedRun("SUCCESS"))
+
+	assert.Empty(t, s.ErrorLines)
+	require.NotNil(t, s.Tests)
+}
+
+func TestCollectRunSummaryDegrades(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	t.Cleanup(ts.Close)
+
+	s := CollectRunSummary(t.Context(), api.NewClient(ts.URL, "test"), finishedRun("FAILURE"))
+
+	assert.Nil(t, s.Tests)
+	assert.Nil(t, s.Previous)
+	assert.Nil(t, s.Problems)
+	assert.Empty(t, s.ErrorLines)
+
+	var buf bytes.Buffer
+	PrintRunSummary(&output.Printer{Out: &buf}, s)
+	assert.Equal(t, "\nDuration: 12m 0s (queued 45s)\n", ansi.Strip(buf.String()))
+}
@@ -17,16 +17,16 @@ import (
 const schemaCacheTTL = 24 * time.Hour
 
 // FetchOrCachePipelineSchema returns (schema, fromCache, fellBackToEmbedded, err); refresh=true bypasses the 24h cache.
-func FetchOrCachePipelineSchema(client *api.Client, refresh bool) ([]byte, bool, bool, error) {
+func FetchOrCachePipelineSchema(client api.ClientInterface, refresh bool) ([]byte, bool, bool, error) {
 	if !refresh {
-		if cached, err := loadSchemaCache(client.BaseURL); err == nil {
+		if cached, err := loadSchemaCache(client.ServerURL()); err == nil {
 			return cached, true, false, nil
 		}
 	}
 
 	schema, err := client.GetPipelineSchema()
 	if err == nil {
-		_ = saveSchemaCache(client.BaseURL, schema)
+		_ = saveSchemaCache(client.ServerURL(), schema)
 		return schema, false, false, nil
 	}
 
@@ -0,0 +1,61 @@
+package cmdutil
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/dustin/go-humanize"
+)
+
+// ParseSize parses a size flag such as 500KB, 100MB, or 1.5GiB into bytes. KB, MB, and GB are powers of 1000,
+// KiB, MiB, and GiB powers of 1024; a bare number is bytes. flag names the flag in the error.
+func ParseSize(flag, value string) (int64, error) {
+	n, err := humanize.ParseBytes(strings.TrimSpace(value))
+	if err != nil || n > math.MaxInt64 {
+		return 0, api.Validation(
+			fmt.Sprintf("invalid %s value %q", flag, value),
+			"Use a size such as 500KB, 100MB, or 1.5GiB",
+		)
+	}
+	return int64(n), nil
+}
+
+// SizeRange is an inclusive byte range from --min-size and --max-size; a zero bound is open.
+type SizeRange struct {
+	Min, Max int64
+}
+
+// ParseSizeRange parses --min-size and --max-size values, either of which may be empty.
+func ParseSizeRange(minSize, maxSize string) (SizeRange, error) {
+	var r SizeRange
+	var err error
+	if minSize != "" {
+		if r.Min, err = ParseSize("--min-size", minSize); err != nil {
+			return r, err
+		}
+	}
+	if maxSize != "" {
+		if r.Max, err = ParseSize("--max-size", maxSize); err != nil {
+			return r, err
+		}
+	}
+	if r.Max > 0 && r.Min > r.Max {
+		return r, api.Validation(
+			fmt.Sprintf("--min-size %s is larger than --max-size %s", minSize, maxSize),
+			"Swap the values or drop one of the bounds",
+		)
+	}
+	return r, nil
+}
+
+// IsSet reports whether either bound is given.
+func (r SizeRange) IsSet() bool {
+	return r.Min > 0 || r.Max > 0
+}
+
+// Contains reports whether size lies within the range.
+func (r SizeRange) Contains(size int64) bool {
+	return size >= r.Min && (r.Max == 0 || size <= r.Max)
+}
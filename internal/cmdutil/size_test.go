@@ -0,0 +1,61 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]int64{
+		"512":    512,
+		"500KB":  500_000,
+		"10 kb":  10_000,
+		"100MB":  100_000_000,
+		"100MiB": 100 << 20,
+		"1.5GiB": 3 << 29,
+		" 2GB ":  2_000_000_000,
+		"0":      0,
+	} {
+		got, err := ParseSize("--min-size", in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "abc", "-5MB", "10 parsecs", "99999999999999 EB"} {
+		_, err := ParseSize("--min-size", in)
+		require.Error(t, err, in)
+		assert.Contains(t, err.Error(), "invalid --min-size value")
+	}
+}
+
+func TestParseSizeRange(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseSizeRange("", "")
+	require.NoError(t, err)
+	assert.False(t, r.IsSet())
+	assert.True(t, r.Contains(0))
+
+	r, err = ParseSizeRange("1KiB", "1MiB")
+	require.NoError(t, err)
+	assert.True(t, r.IsSet())
+	assert.False(t, r.Contains(1023))
+	assert.True(t, r.Contains(1024))
+	assert.True(t, r.Contains(1<<20), "bounds are inclusive")
+	assert.False(t, r.Contains(1<<20+1))
+
+	r, err = ParseSizeRange("100MB", "")
+	require.NoError(t, err)
+	assert.True(t, r.Contains(1<<40), "no --max-size means no upper bound")
+
+	_, err = ParseSizeRange("2MB", "1MB")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than --max-size")
+
+	_, err = ParseSizeRange("", "big")
+	assert.ErrorContains(t, err, "invalid --max-size value")
+}
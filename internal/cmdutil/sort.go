@@ -0,0 +1,179 @@
+package cmdutil
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// SortNone is the --sort value that keeps items in the order the server returned them.
+const SortNone = "none"
+
+// SortFlags holds --sort and --desc for list commands.
+type SortFlags struct {
+	Sort string
+	Desc bool
+
+	flags *pflag.FlagSet
+}
+
+// Requested reports whether --sort or --desc was given explicitly rather than left at the list's default order.
+func (s *SortFlags) Requested() bool {
+	return s.flags != nil && (s.flags.Changed("sort") || s.flags.Changed("desc"))
+}
+
+// reorders reports whether the flags change the fetched order at all.
+func (s *SortFlags) reorders() bool {
+	return s.Desc || !isSortNone(s.Sort)
+}
+
+func isSortNone(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	return spec == "" || strings.EqualFold(spec, SortNone)
+}
+
+// SortColumn is a column a list can be sorted by and how two items compare on it.
+// Fields are the --json fields Compare reads; sorting a --json=<fields> list needs them all.
+type SortColumn[T any] struct {
+	Name    string
+	Fields  []string
+	Compare func(a, b T) int
+}
+
+// AddSortFlags registers --sort, defaulting to defaultSort, and --desc on a list command.
+func AddSortFlags[T any](cmd *cobra.Command, flags *SortFlags, columns []SortColumn[T], defaultSort string) {
+	names := sortColumnNames(columns)
+	flags.flags = cmd.Flags()
+	cmd.Flags().StringVar(&flags.Sort, "sort", defaultSort,
+		fmt.Sprintf("Sort by column (%s); separate several with commas, or use %q for the server's order", strings.Join(names, ", "), SortNone))
+	cmd.Flags().BoolVar(&flags.Desc, "desc", false, "Reverse the sort order")
+	_ = cmd.RegisterFlagCompletionFunc("sort", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return append(names, SortNone), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// ValidateSort checks --sort against columns, so a typo fails before anything is fetched.
+func ValidateSort[T any](flags *SortFlags, columns []SortColumn[T]) error {
+	_, err := sortComparator(flags, columns)
+	return err
+}
+
+// ValidateSortFields checks that a --json field list includes every field an explicit --sort reads, since a
+// column missing from the response would compare equal everywhere and silently not sort. The default order
+// needs no check: without its fields, items just keep the fetched order. An empty list means the default
+// fields, which cover every column.
+func ValidateSortFields[T any](flags *SortFlags, columns []SortColumn[T], fields []string) error {
+	if len(fields) == 0 || !flags.Requested() || isSortNone(flags.Sort) {
+		return nil
+	}
+	for name := range strings.SplitSeq(flags.Sort, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		i := slices.IndexFunc(columns, func(c SortColumn[T]) bool { return c.Name == name })
+		if i < 0 {
+			continue
+		}
+		for _, field := range columns[i].Fields {
+			if !slices.Contains(fields, field) {
+				return api.Validation(
+					fmt.Sprintf("--sort %s needs the %s field in --json", name, field),
+					fmt.Sprintf("Add it: --json=%s,%s", strings.Join(fields, ","), field),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// SortItems stably orders items by --sort, breaking ties with each further column and then by the fetched order;
+// --desc sorts every column descending. With --sort none, items keep the fetched order, reversed by --desc.
+func SortItems[T any](items []T, flags *SortFlags, columns []SortColumn[T]) error {
+	return sortBy(items, func(item T) T { return item }, flags, columns)
+}
+
+func sortBy[E, T any](elems []E, item func(E) T, flags *SortFlags, columns []SortColumn[T]) error {
+	compare, err := sortComparator(flags, columns)
+	if err != nil {
+		return err
+	}
+	switch {
+	case compare != nil:
+		slices.SortStableFunc(elems, func(a, b E) int { return compare(item(a), item(b)) })
+	case flags.Desc:
+		slices.Reverse(elems)
+	}
+	return nil
+}
+
+// sortComparator returns the comparator --sort and --desc describe, or nil for --sort none.
+func sortComparator[T any](flags *SortFlags, columns []SortColumn[T]) (func(a, b T) int, error) {
+	if isSortNone(flags.Sort) {
+		return nil, nil
+	}
+	var compares []func(a, b T) int
+	for name := range strings.SplitSeq(flags.Sort, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		i := slices.IndexFunc(columns, func(c SortColumn[T]) bool { return c.Name == name })
+		if i < 0 {
+			return nil, api.Validation(
+				fmt.Sprintf("unknown --sort column %q", name),
+				fmt.Sprintf("Sort by %s, or %q", strings.Join(sortColumnNames(columns), ", "), SortNone),
+			)
+		}
+		compares = append(compares, columns[i].Compare)
+	}
+	return func(a, b T) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				if flags.Desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+func sortColumnNames[T any](columns []SortColumn[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// CompareFold compares strings case-insensitively, falling back to byte order so the result is deterministic.
+func CompareFold(a, b string) int {
+	return cmp.Or(strings.Compare(strings.ToLower(a), strings.ToLower(b)), strings.Compare(a, b))
+}
+
+// CompareTimes compares TeamCity timestamps chronologically; a missing or unparsable one sorts first.
+func CompareTimes(a, b string) int {
+	at, aErr := api.ParseTeamCityTime(a)
+	bt, bErr := api.ParseTeamCityTime(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return -1
+	case bErr != nil:
+		return 1
+	}
+	return at.Compare(bt)
+}
+
+// CompareBools orders false before true.
+func CompareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,94 @@
+package cmdutil
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sortRow struct {
+	id   int
+	name string
+	date string
+}
+
+var sortRowColumns = []SortColumn[sortRow]{
+	{Name: "id", Fields: []string{"id"}, Compare: func(a, b sortRow) int { return cmp.Compare(a.id, b.id) }},
+	{Name: "name", Fields: []string{"name"}, Compare: func(a, b sortRow) int { return CompareFold(a.name, b.name) }},
+	{Name: "date", Fields: []string{"date"}, Compare: func(a, b sortRow) int { return CompareTimes(a.date, b.date) }},
+}
+
+func sortedIDs(t *testing.T, rows []sortRow, flags SortFlags) []int {
+	t.Helper()
+	rows = append([]sortRow(nil), rows...)
+	require.NoError(t, SortItems(rows, &flags, sortRowColumns))
+	ids := make([]int, len(rows))
+	for i, r := range rows {
+		ids[i] = r.id
+	}
+	return ids
+}
+
+func TestCompareFold(t *testing.T) {
+	t.Parallel()
+	assert.Negative(t, CompareFold("alpha", "Beta"))
+	assert.Positive(t, CompareFold("beta", "Alpha"))
+	assert.Negative(t, CompareFold("Alpha", "alpha"), "case only breaks ties")
+	assert.Zero(t, CompareFold("same", "same"))
+}
+
+func TestCompareTimes(t *testing.T) {
+	t.Parallel()
+	assert.Negative(t, CompareTimes("20260101T100000+0000", "20260101T110000+0000"))
+	assert.Positive(t, CompareTimes("20260102T000000+0000", "20260101T235959+0000"))
+	assert.Zero(t, CompareTimes("20260101T120000+0200", "20260101T100000+0000"), "zones are taken into account")
+	assert.Negative(t, CompareTimes("", "20260101T100000+0000"), "a missing time sorts first")
+	assert.Zero(t, CompareTimes("", "not a date"))
+}
+
+func TestCompareBools(t *testing.T) {
+	t.Parallel()
+	assert.Negative(t, CompareBools(false, true))
+	assert.Positive(t, CompareBools(true, false))
+	assert.Zero(t, CompareBools(true, true))
+}
+
+func TestSortItems(t *testing.T) {
+	t.Parallel()
+	rows := []sortRow{
+		{id: 10, name: "beta", date: "20260103T000000+0000"},
+		{id: 2, name: "Alpha", date: "20260101T000000+0000"},
+		{id: 33, name: "alpha", date: "20260102T000000+0000"},
+		{id: 4, name: "Beta", date: "20260101T000000+0000"},
+	}
+
+	assert.Equal(t, []int{2, 4, 10, 33}, sortedIDs(t, rows, SortFlags{Sort: "id"}), "IDs sort numerically")
+	assert.Equal(t, []int{33, 10, 4, 2}, sortedIDs(t, rows, SortFlags{Sort: "id", Desc: true}))
+	assert.Equal(t, []int{2, 33, 4, 10}, sortedIDs(t, rows, SortFlags{Sort: "name"}))
+	assert.Equal(t, []int{2, 4, 33, 10}, sortedIDs(t, rows, SortFlags{Sort: "date"}), "ties keep the fetched order")
+	assert.Equal(t, []int{10, 33, 4, 2}, sortedIDs(t, rows, SortFlags{Sort: " Date , id ", Desc: true}), "later columns break ties")
+	assert.Equal(t, []int{10, 2, 33, 4}, sortedIDs(t, rows, SortFlags{Sort: SortNone}))
+	assert.Equal(t, []int{4, 33, 2, 10}, sortedIDs(t, rows, SortFlags{Sort: SortNone, Desc: true}))
+
+	err := SortItems(rows, &SortFlags{Sort: "id,size"}, sortRowColumns)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown --sort column "size"`)
+}
+
+func TestValidateSortFields(t *testing.T) {
+	t.Parallel()
+	cmd := &cobra.Command{Use: "list"}
+	flags := &SortFlags{}
+	AddSortFlags(cmd, flags, sortRowColumns, "id")
+
+	assert.NoError(t, ValidateSortFields(flags, sortRowColumns, []string{"name"}), "the default order needs no fields")
+	require.NoError(t, cmd.Flags().Set("sort", "name,date"))
+	assert.NoError(t, ValidateSortFields(flags, sortRowColumns, nil), "default fields cover every column")
+	err := ValidateSortFields(flags, sortRowColumns, []string{"id", "name"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--sort date needs the date field in --json")
+	assert.NoError(t, ValidateSortFields(flags, sortRowColumns, []string{"name", "date"}))
+}
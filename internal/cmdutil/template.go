@@ -0,0 +1,130 @@
+package cmdutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"text/template"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/output"
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
+	"github.com/spf13/cobra"
+)
+
+// Template renders items through the Go template given to --template.
+type Template struct {
+	tmpl *template.Template
+}
+
+// AddTemplateFlag adds --template, mutually exclusive with --json, --plain and --web where the command has them;
+// register it after those.
+func AddTemplateFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().StringVar(target, "template", "", "Format each item with a Go template, e.g. '{{.ID}} {{.Status}}'")
+	for _, other := range []string{"json", "plain", "web"} {
+		if cmd.Flags().Lookup(other) != nil {
+			cmd.MarkFlagsMutuallyExclusive(other, "template")
+		}
+	}
+}
+
+// templateFuncs are the helpers --template offers besides the text/template builtins. Times are TeamCity
+// timestamps, such as .StartDate; one that doesn't parse is passed through unchanged.
+var templateFuncs = template.FuncMap{
+	// truncate shortens s to n characters, ending it with "..." when cut.
+	"truncate": func(n int, s string) string {
+		if r := []rune(s); len(r) > n {
+			if n <= 3 {
+				return string(r[:n])
+			}
+			return string(r[:n-3]) + "..."
+		}
+		return s
+	},
+	// timefmt formats a time with a Go layout, e.g. timefmt "2006-01-02 15:04" .FinishDate.
+	"timefmt": func(layout, ts string) string {
+		t, err := api.ParseTeamCityTime(ts)
+		if err != nil {
+			return ts
+		}
+		return t.Local().Format(layout)
+	},
+	// timeago says how long ago a time was, e.g. "5m ago".
+	"timeago": func(ts string) string {
+		t, err := api.ParseTeamCityTime(ts)
+		if err != nil {
+			return ts
+		}
+		return output.RelativeTime(t)
+	},
+	// elapsed is the time between two times, e.g. elapsed .StartDate .FinishDate; until now when end is empty.
+	"elapsed": func(start, end string) string {
+		from, err := api.ParseTeamCityTime(start)
+		if err != nil {
+			return ""
+		}
+		to, err := api.ParseTeamCityTime(end)
+		if err != nil {
+			to = timeref.Now()
+		}
+		return output.FormatDuration(to.Sub(from))
+	},
+	// duration formats a number of seconds, e.g. duration .QueueWait.
+	"duration": func(seconds int) string {
+		return output.FormatDuration(time.Duration(seconds) * time.Second)
+	},
+}
+
+// ParseTemplate compiles a --template value, or returns nil for an empty one. Commands call it before any request,
+// so a mistake in the template fails fast.
+func ParseTemplate(text string) (*Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("template").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, api.Validation(
+			"invalid --template: "+templateError(err),
+			"Fields use the Go names of the --json fields, e.g. '{{.ID}} {{.Status}}'",
+		)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute writes item rendered through the template, ending with a newline when the template doesn't.
+func (t *Template) Execute(w io.Writer, item any) error {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, item); err != nil {
+		return fmt.Errorf("--template: %s", templateError(err))
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// templateErrorPrefix is how text/template starts its errors, naming the template and where in it the error is.
+var templateErrorPrefix = regexp.MustCompile(`^template: template:[\d:]+ (executing "template" )?`)
+
+// templateError is err from text/template without that prefix, which says nothing a one-line template needs.
+func templateError(err error) string {
+	return templateErrorPrefix.ReplaceAllString(err.Error(), "")
+}
+
+// ExecuteEach renders each element of the slice items in turn.
+func (t *Template) ExecuteEach(w io.Writer, items any) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return t.Execute(w, items)
+	}
+	for i := range v.Len() {
+		if err := t.Execute(w, v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
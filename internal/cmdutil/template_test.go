@@ -0,0 +1,66 @@
+package cmdutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(T *testing.T) {
+	T.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"fields", "{{.ID}} {{.Status}} {{.BranchName}}", "7 FAILURE main\n"},
+		{"own newline", "{{.ID}}\n", "7\n"},
+		{"truncate", `{{truncate 8 .StatusText}}`, "Tests...\n"},
+		{"truncate short", `{{truncate 20 .StatusText}}`, "Tests failed: 3\n"},
+		{"timefmt", `{{timefmt "2006-01" .StartDate}}`, "2026-01\n"},
+		{"timefmt unparsable", `{{timefmt "2006" .QueuedDate}}`, "soon\n"},
+		{"elapsed", `{{elapsed .StartDate .FinishDate}}`, "2m 30s\n"},
+		{"duration", `{{duration 90}}`, "1m 30s\n"},
+	}
+	build := api.Build{ID: 7, Status: "FAILURE", BranchName: "main", StatusText: "Tests failed: 3",
+		QueuedDate: "soon", StartDate: "20260121T120000+0000", FinishDate: "20260121T120230+0000"}
+
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmpl, err := ParseTemplate(tc.text)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, build))
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestTemplateErrors(T *testing.T) {
+	T.Parallel()
+
+	tmpl, err := ParseTemplate("")
+	require.NoError(T, err)
+	assert.Nil(T, tmpl, "an empty --template is not set")
+
+	_, err = ParseTemplate("{{.ID")
+	require.Error(T, err)
+	assert.Equal(T, "invalid --template: unclosed action", err.Error())
+	_, err = ParseTemplate("{{nope .ID}}")
+	assert.ErrorContains(T, err, `function "nope" not defined`)
+
+	tmpl, err = ParseTemplate("{{.Nope}}")
+	require.NoError(T, err)
+	err = tmpl.Execute(&bytes.Buffer{}, api.Build{})
+	assert.EqualError(T, err, "--template: at <.Nope>: can't evaluate field Nope in type api.Build")
+
+	var buf bytes.Buffer
+	tmpl, _ = ParseTemplate("{{.Name}}")
+	require.NoError(T, tmpl.ExecuteEach(&buf, []api.Project{{Name: "A"}, {Name: "B"}}))
+	assert.Equal(T, "A\nB\n", buf.String())
+}
@@ -0,0 +1,92 @@
+package cmdutil
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// ForceVCSManagedFlag is the flag mutating job/project commands accept to edit settings that are synchronized from VCS.
+const ForceVCSManagedFlag = "force-vcs-managed"
+
+// vcsManagedCache remembers per-project versioned-settings lookups for one invocation, so bulk edits check each project once.
+type vcsManagedCache struct {
+	mu      sync.Mutex
+	project map[string]bool
+	job     map[string]string
+}
+
+// AddForceVCSManagedFlag registers --force-vcs-managed on a mutating job/project command.
+func AddForceVCSManagedFlag(cmd *cobra.Command, force *bool) {
+	cmd.Flags().BoolVar(force, ForceVCSManagedFlag, false, "Edit even if the project's settings are synchronized from VCS")
+}
+
+// IsVCSManaged reports whether projectID takes its settings from VCS, so UI/REST edits get reverted on the next sync.
+// Projects without versioned settings, or whose config can't be read, count as not managed.
+func (f *Factory) IsVCSManaged(client api.ClientInterface, projectID string) bool {
+	c := f.vcsManagedCache()
+	c.mu.Lock()
+	managed, ok := c.project[projectID]
+	c.mu.Unlock()
+	if ok {
+		return managed
+	}
+
+	cfg, err := client.GetVersionedSettingsConfig(projectID)
+	if err != nil {
+		f.Printer.Debug("versioned settings for %s: %v", projectID, err)
+	}
+	managed = err == nil && cfg.SynchronizationMode == "enabled" && cfg.BuildSettingsMode == "useFromVCS"
+
+	c.mu.Lock()
+	c.project[projectID] = managed
+	c.mu.Unlock()
+	return managed
+}
+
+// GuardVCSManagedProject refuses to edit a VCS-managed project unless force or the allow_vcs_edits config is set, and warns when it proceeds.
+func (f *Factory) GuardVCSManagedProject(client api.ClientInterface, projectID string, force bool) error {
+	if !f.IsVCSManaged(client, projectID) {
+		return nil
+	}
+	if !force && !config.AllowVCSEdits() {
+		return api.Validation(
+			fmt.Sprintf("project %s takes its settings from VCS; this change would be overwritten on the next sync", projectID),
+			fmt.Sprintf("Change the settings in VCS instead, or pass --%s (or run 'teamcity config set allow_vcs_edits true') to edit anyway", ForceVCSManagedFlag),
+		)
+	}
+	f.Printer.Warn("Project %s takes its settings from VCS; this change may be overwritten on the next sync", projectID)
+	return nil
+}
+
+// GuardVCSManagedJob is GuardVCSManagedProject for the project that owns jobID.
+func (f *Factory) GuardVCSManagedJob(client api.ClientInterface, jobID string, force bool) error {
+	c := f.vcsManagedCache()
+	c.mu.Lock()
+	projectID, ok := c.job[jobID]
+	c.mu.Unlock()
+	if !ok {
+		job, err := client.GetBuildType(jobID)
+		if err != nil {
+			return err
+		}
+		projectID = job.ProjectID
+		c.mu.Lock()
+		c.job[jobID] = projectID
+		c.mu.Unlock()
+	}
+	if projectID == "" {
+		return nil
+	}
+	return f.GuardVCSManagedProject(client, projectID, force)
+}
+
+func (f *Factory) vcsManagedCache() *vcsManagedCache {
+	f.vcsOnce.Do(func() {
+		f.vcs = &vcsManagedCache{project: map[string]bool{}, job: map[string]string{}}
+	})
+	return f.vcs
+}
@@ -0,0 +1,42 @@
+package cmdutil
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+// TestOccurrenceURL links to a run's tests tab filtered to t and with its details expanded; webURL is the run's WebURL.
+func TestOccurrenceURL(webURL string, t api.TestOccurrence) string {
+	return withQuery(webURL, "buildTab", "tests", "name", t.Name, "expandedTest", t.ID)
+}
+
+// ProblemOccurrenceURL links to a run's overview with build problem p expanded.
+func ProblemOccurrenceURL(webURL string, p api.ProblemOccurrence) string {
+	return withQuery(webURL, "buildTab", "overview", "expandedProblem", p.ID)
+}
+
+// LogLineURL links to a run's build log scrolled to line (1-based); line <= 0 opens the log at the top.
+func LogLineURL(webURL string, line int) string {
+	if line <= 0 {
+		return withQuery(webURL, "buildTab", "buildLog")
+	}
+	return withQuery(webURL, "buildTab", "buildLog", "focusLine", strconv.Itoa(line))
+}
+
+// withQuery sets key/value pairs on webURL's query, escaping values and keeping any existing parameters; empty values are skipped.
+func withQuery(webURL string, kv ...string) string {
+	u, err := url.Parse(webURL)
+	if err != nil || webURL == "" {
+		return webURL
+	}
+	q := u.Query()
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			q.Set(kv[i], kv[i+1])
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,51 @@
+package cmdutil
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestOccurrenceURL(t *testing.T) {
+	const webURL = "https://tc.example.com/buildConfiguration/Falcon_Build/12345"
+	tests := []struct {
+		name     string
+		testName string
+		wantName string
+	}{
+		{"plain", "com.acme.FooTest.bar", "com.acme.FooTest.bar"},
+		{"spaces", "Suite: login works with SSO", "Suite%3A+login+works+with+SSO"},
+		{"slashes", "src/app/login.spec.ts: logs in", "src%2Fapp%2Flogin.spec.ts%3A+logs+in"},
+		{"unicode", "Тест проверки ✓", "%D0%A2%D0%B5%D1%81%D1%82+%D0%BF%D1%80%D0%BE%D0%B2%D0%B5%D1%80%D0%BA%D0%B8+%E2%9C%93"},
+		{"reserved", "a&b=c#d?e+f", "a%26b%3Dc%23d%3Fe%2Bf"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := TestOccurrenceURL(webURL, api.TestOccurrence{ID: "build:(id:12345),id:2000000017", Name: tc.testName})
+			assert.Equal(t, webURL+"?buildTab=tests&expandedTest=build%3A%28id%3A12345%29%2Cid%3A2000000017&name="+tc.wantName, got)
+
+			u, err := url.Parse(got)
+			require.NoError(t, err)
+			assert.Equal(t, tc.testName, u.Query().Get("name"), "the name must round-trip through the URL")
+		})
+	}
+}
+
+func TestTestOccurrenceURLKeepsExistingQuery(t *testing.T) {
+	got := TestOccurrenceURL("https://tc.example.com/viewLog.html?buildId=7", api.TestOccurrence{ID: "id:1", Name: "x"})
+	assert.Equal(t, "https://tc.example.com/viewLog.html?buildId=7&buildTab=tests&expandedTest=id%3A1&name=x", got)
+}
+
+func TestProblemOccurrenceURL(t *testing.T) {
+	got := ProblemOccurrenceURL("https://tc.example.com/build/1", api.ProblemOccurrence{ID: "problem:(id:5),build:(id:1)"})
+	assert.Equal(t, "https://tc.example.com/build/1?buildTab=overview&expandedProblem=problem%3A%28id%3A5%29%2Cbuild%3A%28id%3A1%29", got)
+}
+
+func TestLogLineURL(t *testing.T) {
+	assert.Equal(t, "https://tc.example.com/build/1?buildTab=buildLog&focusLine=120", LogLineURL("https://tc.example.com/build/1", 120))
+	assert.Equal(t, "https://tc.example.com/build/1?buildTab=buildLog", LogLineURL("https://tc.example.com/build/1", 0))
+	assert.Empty(t, LogLineURL("", 5), "no web URL, no link")
+}
@@ -1,4 +1,5 @@
-// Package completion exposes shell-completion helpers backed by static enums and on-disk config.
+// Package completion exposes shell-completion helpers backed by static enums, on-disk config, and short, cached
+// server queries.
 package completion
 
 import (
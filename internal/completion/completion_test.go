@@ -1,10 +1,16 @@
 package completion
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/link"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -41,3 +47,57 @@ func TestLinkedJobsAndProjectsReadFromTOML(t *testing.T) {
 	scopes, _ := LinkScopes()(nil, nil, "")
 	assert.Equal(t, []string{"backend", "docs"}, scopes)
 }
+
+func TestServerCompletionsAreCachedAndDegrade(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TEAMCITY_TOKEN", "")
+	t.Chdir(t.TempDir())
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/app/rest/buildTypes/id:Falcon_Build/branches"):
+			_, _ = w.Write([]byte(`{"branch":[{"name":"main"},{"name":"feature/login"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/app/rest/buildTypes"):
+			_, _ = w.Write([]byte(`{"count":2,"buildType":[{"id":"Falcon_Test"},{"id":"Falcon_Build"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/app/rest/agents"):
+			_, _ = w.Write([]byte(`{"count":1,"agent":[{"name":"linux-01"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { serverClient.Store(nil) })
+
+	t.Setenv("TEAMCITY_URL", ts.URL)
+	UseServer(func() (api.ClientInterface, error) { return api.NewClient(ts.URL, "token"), nil })
+
+	jobs, _ := Jobs()(nil, nil, "")
+	assert.Equal(t, []string{"Falcon_Build", "Falcon_Test"}, jobs)
+	jobs, _ = Jobs()(nil, nil, "")
+	assert.Equal(t, []string{"Falcon_Build", "Falcon_Test"}, jobs)
+	assert.EqualValues(t, 1, requests.Load(), "second completion is served from the cache")
+
+	agents, _ := Agents()(nil, nil, "")
+	assert.Equal(t, []string{"linux-01"}, agents)
+
+	cmd := &cobra.Command{Use: "start"}
+	branches, _ := Branches()(cmd, []string{"Falcon_Build"}, "")
+	assert.Contains(t, branches, "@this")
+	assert.Contains(t, branches, "feature/login")
+
+	// A server that can't answer leaves only what's known offline, and cached values need no client at all.
+	var clients atomic.Int32
+	UseServer(func() (api.ClientInterface, error) {
+		clients.Add(1)
+		return nil, errors.New("not authenticated")
+	})
+	projects, _ := Projects()(nil, nil, "")
+	assert.Equal(t, []string{"_Root"}, projects)
+	assert.EqualValues(t, 1, clients.Load())
+	agents, _ = Agents()(nil, nil, "")
+	assert.Equal(t, []string{"linux-01"}, agents)
+	assert.EqualValues(t, 1, clients.Load(), "a cache hit builds no client")
+}
@@ -0,0 +1,206 @@
+package completion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// serverTimeout bounds how long a completion waits for the server before offering what it has offline.
+	serverTimeout = 2 * time.Second
+	// serverCacheTTL is how long completion values fetched from the server are reused, so tabbing repeatedly
+	// costs one request.
+	serverCacheTTL = time.Minute
+	// serverLimit caps how many values a completion fetches.
+	serverLimit = 5000
+)
+
+// ClientFunc returns a client for the configured server.
+type ClientFunc func() (api.ClientInterface, error)
+
+var serverClient atomic.Pointer[ClientFunc]
+
+// UseServer lets completions query the server through client; without it they only offer values found offline.
+func UseServer(client ClientFunc) {
+	serverClient.Store(&client)
+}
+
+// Jobs completes job IDs from teamcity.toml and the server.
+func Jobs() CompFunc {
+	return merge(LinkedJobs(), serverValues("jobs", func(c api.ClientInterface) ([]string, error) {
+		jobs, _, err := c.GetBuildTypes(api.BuildTypesOptions{Limit: serverLimit, Fields: []string{"id"}})
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, len(jobs.BuildTypes))
+		for i, j := range jobs.BuildTypes {
+			ids[i] = j.ID
+		}
+		return ids, nil
+	}))
+}
+
+// Projects completes project IDs from teamcity.toml and the server.
+func Projects() CompFunc {
+	return merge(LinkedProjects(), serverValues("projects", func(c api.ClientInterface) ([]string, error) {
+		projects, _, err := c.GetProjects(api.ProjectsOptions{Limit: serverLimit, Fields: []string{"id"}})
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, len(projects.Projects))
+		for i, p := range projects.Projects {
+			ids[i] = p.ID
+		}
+		return ids, nil
+	}))
+}
+
+// Agents completes agent names from the server.
+func Agents() CompFunc {
+	return serverValues("agents", func(c api.ClientInterface) ([]string, error) {
+		agents, _, err := c.GetAgents(api.AgentsOptions{Limit: serverLimit, Fields: []string{"name"}})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(agents.Agents))
+		for i, a := range agents.Agents {
+			names[i] = a.Name
+		}
+		return names, nil
+	})
+}
+
+// Branches completes branch flags like GitBranches, adding the branches the server knows for the command's job:
+// its --job flag, or else its first argument.
+func Branches() CompFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		out, directive := GitBranches()(cmd, args, toComplete)
+		job := ""
+		if flag := cmd.Flags().Lookup("job"); flag != nil {
+			job = flag.Value.String()
+		}
+		if job == "" && len(args) > 0 {
+			job = args[0]
+		}
+		if job == "" {
+			return out, directive
+		}
+		server, _ := serverValues("branches-"+job, func(c api.ClientInterface) ([]string, error) {
+			branches, err := c.GetBuildTypeBranches(context.Background(), job)
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(branches.Branch))
+			for _, b := range branches.Branch {
+				if !b.Unspecified {
+					names = append(names, b.Name)
+				}
+			}
+			return names, nil
+		})(cmd, args, toComplete)
+		return append(out[:1], dedupe(append(out[1:], server...))...), directive
+	}
+}
+
+// merge completes the values of both a and b, sorted and without duplicates.
+func merge(a, b CompFunc) CompFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		first, _ := a(cmd, args, toComplete)
+		second, _ := b(cmd, args, toComplete)
+		return dedupe(append(first, second...)), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// serverValues completes the values fetch gets from the server, cached under kind for serverCacheTTL. It offers
+// nothing when there is no server, the user is not logged in, or the server does not answer within serverTimeout.
+// A cached answer needs no client, so a token command or keyring lookup only runs on a miss, and within the timeout.
+func serverValues(kind string, fetch func(api.ClientInterface) ([]string, error)) CompFunc {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		clientFunc := serverClient.Load()
+		if clientFunc == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		path, pathErr := serverCachePath(config.GetServerURL(), kind)
+		if pathErr == nil {
+			if values, err := loadServerCache(path); err == nil {
+				return values, cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+
+		// The request is abandoned, not canceled, on timeout: the completion process exits right after.
+		done := make(chan []string, 1)
+		go func() {
+			var values []string
+			client, err := (*clientFunc)()
+			if err == nil {
+				values, err = fetch(client)
+			}
+			if err != nil {
+				values = nil
+			}
+			done <- values
+		}()
+		select {
+		case values := <-done:
+			if values != nil && pathErr == nil {
+				_ = saveServerCache(path, values)
+			}
+			return values, cobra.ShellCompDirectiveNoFileComp
+		case <-time.After(serverTimeout):
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+}
+
+func serverCachePath(serverURL, kind string) (string, error) {
+	if serverURL == "" {
+		return "", errors.New("no server to cache completions for")
+	}
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256([]byte(serverURL + "\x00" + kind))
+	return filepath.Join(dir, "completion", fmt.Sprintf("%x.json", h[:8])), nil
+}
+
+func loadServerCache(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > serverCacheTTL {
+		return nil, errors.New("completion cache expired")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func saveServerCache(path string, values []string) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/JetBrains/teamcity-cli/internal/atomicfile"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -26,6 +29,22 @@ const (
 	EnvDSLDir    = "TEAMCITY_DSL_DIR"
 	EnvProject   = "TEAMCITY_PROJECT"
 	EnvJob       = "TEAMCITY_JOB"
+	EnvDryRun    = "TC_DRY_RUN"
+	EnvContext   = "TC_CONTEXT"
+	EnvRecord    = "TC_RECORD"
+	EnvMaxRPS    = "TEAMCITY_MAX_RPS"
+	EnvRetries   = "TEAMCITY_RETRIES"
+	EnvLimitWarn = "TEAMCITY_LIMIT_WARN"
+
+	// EnvTokenCommand is a shell command that prints the token, for machines that can't store one.
+	EnvTokenCommand = "TEAMCITY_TOKEN_COMMAND"
+
+	// EnvCACert and EnvInsecureSkipVerify override the ca_cert and insecure_skip_verify keys of every server.
+	EnvCACert             = "TEAMCITY_CA_CERT"
+	EnvInsecureSkipVerify = "TEAMCITY_INSECURE_SKIP_VERIFY"
+
+	// DefaultLimitWarn is the --limit above which list commands warn that the result may be slow to fetch.
+	DefaultLimitWarn = 1000
 
 	DefaultDSLDirTeamCity = ".teamcity"
 	DefaultDSLDirTC       = ".tc"
@@ -34,11 +53,25 @@ const (
 )
 
 type ServerConfig struct {
-	Token       string `mapstructure:"token"`
-	User        string `mapstructure:"user"`
-	Guest       bool   `mapstructure:"guest,omitempty"`
-	RO          bool   `mapstructure:"ro,omitempty"`
-	TokenExpiry string `mapstructure:"token_expiry,omitempty"`
+	Token         string `mapstructure:"token"`
+	User          string `mapstructure:"user"`
+	Guest         bool   `mapstructure:"guest,omitempty"`
+	RO            bool   `mapstructure:"ro,omitempty"`
+	TokenExpiry   string `mapstructure:"token_expiry,omitempty"`
+	TokenName     string `mapstructure:"token_name,omitempty"`
+	AllowVCSEdits bool   `mapstructure:"allow_vcs_edits,omitempty"`
+	// TokenCommand is a shell command that prints the token on stdout; it takes precedence over a stored token.
+	TokenCommand string `mapstructure:"token_command,omitempty"`
+	// Context labels requests to this server for audit attribution; see CLIContext.
+	Context string `mapstructure:"context,omitempty"`
+	// CommitLinks maps VCS root IDs to commit URL templates with a {sha} placeholder.
+	CommitLinks map[string]string `mapstructure:"commit_links,omitempty"`
+	// NotifyWebhook is a URL that run watch --notify posts a finished run to.
+	NotifyWebhook string `mapstructure:"notify_webhook,omitempty"`
+	// CACert is a PEM bundle trusted, on top of the system's CAs, for this server's certificate.
+	CACert string `mapstructure:"ca_cert,omitempty"`
+	// InsecureSkipVerify turns off verification of this server's certificate.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify,omitempty"`
 }
 
 type Config struct {
@@ -47,6 +80,10 @@ type Config struct {
 	Aliases              map[string]string       `mapstructure:"aliases"`
 	Analytics            *bool                   `mapstructure:"analytics,omitempty"`
 	AnalyticsNoticeShown bool                    `mapstructure:"analytics_notice_shown,omitempty"`
+	// DateFormat names the layout for calendar dates in grouped output (see datebucket.DateFormats); empty follows the locale.
+	DateFormat string `mapstructure:"date_format,omitempty"`
+	// DefaultCache is how long API responses are cached when --cache isn't given, as a duration; empty caches nothing.
+	DefaultCache string `mapstructure:"default_cache,omitempty"`
 }
 
 var (
@@ -158,8 +195,41 @@ func keyringService(serverURL string) string {
 	return "tc:" + serverURL
 }
 
-// GetServerURL resolves the target server from TEAMCITY_URL, then the configured default; never from DSL (avoids routing a stored token to an untrusted repo's .teamcity/pom.xml — opt in via `auth login`).
+// serverOverride is the server --server picked for this invocation; see SetServerOverride.
+var serverOverride string
+
+// SetServerOverride makes GetServerURL return serverURL, ahead of TEAMCITY_URL and the default server, until it is
+// set back to "".
+func SetServerOverride(serverURL string) {
+	serverOverride = serverURL
+}
+
+// ResolveServer resolves a --server value: a configured server's URL in any form NormalizeURL accepts (a trailing
+// slash or a missing scheme), or its context label. Any other URL is taken as is, to be used with TEAMCITY_TOKEN.
+func ResolveServer(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if key, _, ok := findServer(value); ok {
+		return key, nil
+	}
+	for _, key := range SortedServerURLs(Get()) {
+		if cfg.Servers[key].Context == value {
+			return key, nil
+		}
+	}
+	if strings.ContainsAny(value, ".:/") {
+		return NormalizeURL(value), nil
+	}
+	return "", fmt.Errorf("no configured server has the URL or context label %q; run 'teamcity auth status --all' to list them", value)
+}
+
+// GetServerURL resolves the target server from --server, then TEAMCITY_URL, then the configured default; never from DSL (avoids routing a stored token to an untrusted repo's .teamcity/pom.xml — opt in via `auth login`).
 func GetServerURL() string {
+	if serverOverride != "" {
+		return serverOverride
+	}
 	if serverUrl := os.Getenv(EnvServerURL); serverUrl != "" {
 		return NormalizeURL(serverUrl)
 	}
@@ -182,41 +252,83 @@ func GetToken() string {
 	return token
 }
 
-func GetTokenWithSource() (token, source string, keyringErr error) {
-	if token := os.Getenv(EnvToken); token != "" {
-		return token, "env", nil
+// GetTokenWithSource returns the token for the server in use and where it came from: "env", "command", "keyring" or
+// "config". The error is why a token that should be there is not: an inaccessible keyring, or a failed token command
+// (a *TokenCommandError).
+func GetTokenWithSource() (token, source string, err error) {
+	return TokenWithSourceFor(serverOverride)
+}
+
+// TokenWithSourceFor is GetTokenWithSource for a server picked with --server, given as serverURL instead of through
+// SetServerOverride; "" means the default server.
+func TokenWithSourceFor(serverURL string) (token, source string, err error) {
+	envToken := os.Getenv(EnvToken)
+	if envToken != "" && serverURL == "" {
+		return envToken, "env", nil
+	}
+	envCommand := os.Getenv(EnvTokenCommand)
+	if envToken == "" && envCommand != "" && serverURL == "" {
+		return commandToken(envCommand)
 	}
 
-	serverURL := GetServerURL()
+	if serverURL == "" {
+		serverURL = GetServerURL()
+	}
 	if serverURL == "" {
 		return "", "", nil
 	}
 
-	server, ok := cfg.Servers[serverURL]
-	if ok && server.User != "" {
-		t, err := keyringGet(keyringService(serverURL), server.User)
-		if err == nil && t != "" {
-			return t, "keyring", nil
-		}
-		if err != nil && !errors.Is(err, errKeyringNotFound) {
-			keyringErr = err
-		}
+	// A server picked with --server uses its stored token; TEAMCITY_TOKEN and TEAMCITY_TOKEN_COMMAND only stand in
+	// for a missing one.
+	token, source, err = GetTokenForServer(serverURL)
+	if token == "" && envToken != "" {
+		return envToken, "env", nil
+	}
+	if token == "" && envCommand != "" {
+		return commandToken(envCommand)
 	}
+	return token, source, err
+}
 
-	if ok && server.Token != "" {
-		return server.Token, "config", nil
+// TokenCommand returns the token command GetTokenWithSource would run first, without running it, or "" when a
+// token comes from elsewhere first; for callers that only need to know where the token comes from.
+func TokenCommand() string {
+	envToken, envCommand := os.Getenv(EnvToken), os.Getenv(EnvTokenCommand)
+	if serverOverride == "" && envToken != "" {
+		return ""
 	}
-	return "", "", keyringErr
+	if serverOverride == "" && envCommand != "" {
+		return envCommand
+	}
+	if _, server, ok := findServer(GetServerURL()); ok && server.TokenCommand != "" {
+		return server.TokenCommand
+	}
+	return ""
+}
+
+// commandToken is tokenFromCommand in the shape of GetTokenWithSource.
+func commandToken(command string) (token, source string, err error) {
+	token, err = tokenFromCommand(command)
+	if err != nil {
+		return "", "", err
+	}
+	return token, "command", nil
 }
 
 // GetTokenForServer retrieves the token for a specific server URL.
 // Unlike GetTokenWithSource, it does not use GetServerURL() — the caller
 // provides the server URL directly. Returns the token and its source
-// ("keyring" or "config"), or empty strings if none found.
+// ("command", "keyring" or "config"), or empty strings if none found.
+// A server's token_command wins over its stored token. Entries are
+// matched like config doctor --migrate normalizes them, so a key saved
+// with a trailing slash still resolves.
 func GetTokenForServer(serverURL string) (token, source string, keyringErr error) {
-	server, ok := cfg.Servers[serverURL]
+	key, server, ok := findServer(serverURL)
+	if ok && server.TokenCommand != "" {
+		return commandToken(server.TokenCommand)
+	}
 	if ok && server.User != "" {
-		t, err := keyringGet(keyringService(serverURL), server.User)
+		t, err := keyringGet(keyringService(key), server.User)
 		if err == nil && t != "" {
 			return t, "keyring", nil
 		}
@@ -244,25 +356,57 @@ func GetCurrentUser() string {
 }
 
 func SetServer(serverURL, token, user string) error {
-	_, err := SetServerWithKeyring(serverURL, token, user, "", false)
+	_, err := SetServerWithKeyring(serverURL, token, user, "", "", false)
 	return err
 }
 
-func SetServerWithKeyring(serverURL, token, user, tokenExpiry string, insecureStorage bool) (insecureFallback bool, err error) {
+// SetServerWithKeyring stores the token for serverURL, in the system keyring unless insecureStorage or the keyring is
+// unavailable, and makes it the default server. tokenName is the token's name on the server, or "" when unknown.
+func SetServerWithKeyring(serverURL, token, user, tokenName, tokenExpiry string, insecureStorage bool) (insecureFallback bool, err error) {
 	serverURL = NormalizeURL(serverURL)
 	cfg.DefaultServer = serverURL
 
 	if !insecureStorage {
 		if krErr := keyringSet(keyringService(serverURL), user, token); krErr == nil {
-			cfg.Servers[serverURL] = ServerConfig{User: user, TokenExpiry: tokenExpiry}
+			cfg.Servers[serverURL] = ServerConfig{User: user, TokenName: tokenName, TokenExpiry: tokenExpiry}
 			return false, writeConfig()
 		}
 	}
 
-	cfg.Servers[serverURL] = ServerConfig{Token: token, User: user, TokenExpiry: tokenExpiry}
+	cfg.Servers[serverURL] = ServerConfig{Token: token, User: user, TokenName: tokenName, TokenExpiry: tokenExpiry}
 	return true, writeConfig()
 }
 
+// ReplaceToken swaps the token stored for serverURL, keeping the server's other settings and where its token is kept:
+// the system keyring, unless the old token was in plain text or the keyring is unavailable.
+func ReplaceToken(serverURL, token, tokenName, tokenExpiry string) (insecureFallback bool, err error) {
+	serverURL = NormalizeURL(serverURL)
+	if cfg.Servers == nil {
+		cfg.Servers = make(map[string]ServerConfig)
+	}
+	server := cfg.Servers[serverURL]
+	server.TokenName, server.TokenExpiry = tokenName, tokenExpiry
+
+	if server.Token == "" {
+		if krErr := keyringSet(keyringService(serverURL), server.User, token); krErr == nil {
+			cfg.Servers[serverURL] = server
+			return false, writeConfig()
+		}
+	}
+
+	server.Token = token
+	cfg.Servers[serverURL] = server
+	return true, writeConfig()
+}
+
+// GetTokenName returns the name of the token stored for the server in use, or "" when it was not recorded.
+func GetTokenName() string {
+	if server, ok := cfg.Servers[GetServerURL()]; ok {
+		return server.TokenName
+	}
+	return ""
+}
+
 func GetTokenExpiry() string {
 	if server, ok := cfg.Servers[GetServerURL()]; ok {
 		return server.TokenExpiry
@@ -287,6 +431,30 @@ func serverConfigToMap(sc ServerConfig) map[string]any {
 	if sc.TokenExpiry != "" {
 		m["token_expiry"] = sc.TokenExpiry
 	}
+	if sc.TokenName != "" {
+		m["token_name"] = sc.TokenName
+	}
+	if sc.AllowVCSEdits {
+		m["allow_vcs_edits"] = true
+	}
+	if sc.TokenCommand != "" {
+		m["token_command"] = sc.TokenCommand
+	}
+	if sc.Context != "" {
+		m["context"] = sc.Context
+	}
+	if len(sc.CommitLinks) > 0 {
+		m["commit_links"] = sc.CommitLinks
+	}
+	if sc.NotifyWebhook != "" {
+		m["notify_webhook"] = sc.NotifyWebhook
+	}
+	if sc.CACert != "" {
+		m["ca_cert"] = sc.CACert
+	}
+	if sc.InsecureSkipVerify {
+		m["insecure_skip_verify"] = true
+	}
 	return m
 }
 
@@ -310,6 +478,12 @@ func writeConfig() error {
 	if cfg.AnalyticsNoticeShown {
 		w.Set("analytics_notice_shown", true)
 	}
+	if cfg.DateFormat != "" {
+		w.Set("date_format", cfg.DateFormat)
+	}
+	if cfg.DefaultCache != "" {
+		w.Set("default_cache", cfg.DefaultCache)
+	}
 
 	data, err := yaml.Marshal(w.AllSettings())
 	if err != nil {
@@ -376,6 +550,35 @@ func SetAnalyticsEnabled(enabled bool) error {
 	return writeConfig()
 }
 
+// GetDateFormat returns the date_format setting; empty when unset.
+func GetDateFormat() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.DateFormat
+}
+
+// DefaultCache returns the default_cache setting: how long API responses are cached without --cache, or 0.
+func DefaultCache() time.Duration {
+	if cfg == nil || cfg.DefaultCache == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(cfg.DefaultCache)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// CacheDir returns the directory cached API responses are kept in, under ConfigDir.
+func CacheDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "http"), nil
+}
+
 func IsAnalyticsNoticeShown() bool {
 	if cfg == nil {
 		return false
@@ -393,10 +596,14 @@ func MarkAnalyticsNoticeShown() error {
 
 // IsGuestAuth returns true if guest authentication is enabled via env var or server config
 func IsGuestAuth() bool {
+	return IsGuestAuthFor(GetServerURL())
+}
+
+// IsGuestAuthFor is IsGuestAuth for serverURL instead of the server in use.
+func IsGuestAuthFor(serverURL string) bool {
 	if v := os.Getenv(EnvGuestAuth); v == "1" || v == "true" || v == "yes" {
 		return true
 	}
-	serverURL := GetServerURL()
 	if serverURL == "" || cfg == nil {
 		return false
 	}
@@ -409,10 +616,14 @@ func IsGuestAuth() bool {
 // IsReadOnly returns true if read-only mode is enabled via env var or server config.
 // When enabled, all non-GET API requests are blocked.
 func IsReadOnly() bool {
+	return IsReadOnlyFor(GetServerURL())
+}
+
+// IsReadOnlyFor is IsReadOnly for serverURL instead of the server in use.
+func IsReadOnlyFor(serverURL string) bool {
 	if v := os.Getenv(EnvReadOnly); v == "1" || v == "true" || v == "yes" {
 		return true
 	}
-	serverURL := GetServerURL()
 	if serverURL == "" || cfg == nil {
 		return false
 	}
@@ -422,6 +633,110 @@ func IsReadOnly() bool {
 	return false
 }
 
+// IsDryRun returns true if TC_DRY_RUN asks every command to record mutations instead of sending them.
+func IsDryRun() bool {
+	v := os.Getenv(EnvDryRun)
+	return v == "1" || v == "true" || v == "yes"
+}
+
+// RecordPath returns the cassette file TC_RECORD asks API traffic to be recorded to, or "" when recording is off.
+func RecordPath() string {
+	return strings.TrimSpace(os.Getenv(EnvRecord))
+}
+
+// AllowVCSEdits returns true if the current server is configured to edit projects whose settings are synchronized from VCS without --force-vcs-managed.
+func AllowVCSEdits() bool {
+	serverURL := GetServerURL()
+	if serverURL == "" || cfg == nil {
+		return false
+	}
+	return cfg.Servers[serverURL].AllowVCSEdits
+}
+
+// CLIContext returns the label sent with every request so audit logs can attribute automation:
+// TC_CONTEXT, else the current server's context key, else "".
+func CLIContext() string {
+	if v := strings.TrimSpace(os.Getenv(EnvContext)); v != "" {
+		return v
+	}
+	serverURL := GetServerURL()
+	if serverURL == "" || cfg == nil {
+		return ""
+	}
+	return cfg.Servers[serverURL].Context
+}
+
+// CommitLinkTemplate returns the current server's commit URL template for a VCS root, or "" when none is configured.
+// Config keys are case-insensitive, so the VCS root ID is matched the same way.
+func CommitLinkTemplate(vcsRootID string) string {
+	serverURL := GetServerURL()
+	if serverURL == "" || cfg == nil || vcsRootID == "" {
+		return ""
+	}
+	for id, tpl := range cfg.Servers[serverURL].CommitLinks {
+		if strings.EqualFold(id, vcsRootID) {
+			return tpl
+		}
+	}
+	return ""
+}
+
+// NotifyWebhook returns the current server's webhook URL for run watch --notify, or "" when none is configured.
+func NotifyWebhook() string {
+	serverURL := GetServerURL()
+	if serverURL == "" || cfg == nil {
+		return ""
+	}
+	return cfg.Servers[serverURL].NotifyWebhook
+}
+
+// ServerTLS returns how to verify serverURL's certificate: its ca_cert and insecure_skip_verify keys, each
+// overridden by TEAMCITY_CA_CERT and TEAMCITY_INSECURE_SKIP_VERIFY when set.
+func ServerTLS(serverURL string) api.TLSOptions {
+	var o api.TLSOptions
+	if cfg != nil {
+		sc := cfg.Servers[NormalizeURL(serverURL)]
+		o = api.TLSOptions{CACertFile: sc.CACert, InsecureSkipVerify: sc.InsecureSkipVerify}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvCACert)); v != "" {
+		o.CACertFile = v
+	}
+	switch os.Getenv(EnvInsecureSkipVerify) {
+	case "1", "true", "yes":
+		o.InsecureSkipVerify = true
+	case "0", "false", "no":
+		o.InsecureSkipVerify = false
+	}
+	return o
+}
+
+// MaxRPS returns the client-side request rate cap from TEAMCITY_MAX_RPS; 0 (unset, invalid, or non-positive) means unlimited.
+func MaxRPS() float64 {
+	v, err := strconv.ParseFloat(os.Getenv(EnvMaxRPS), 64)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return v
+}
+
+// Retries returns how many times to retry transient read failures from TEAMCITY_RETRIES; ok is false when unset or invalid.
+func Retries() (n int, ok bool) {
+	n, err := strconv.Atoi(os.Getenv(EnvRetries))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// LimitWarn returns the soft --limit cap from TEAMCITY_LIMIT_WARN, or DefaultLimitWarn when unset or invalid; 0 disables the warning.
+func LimitWarn() int {
+	v, err := strconv.Atoi(os.Getenv(EnvLimitWarn))
+	if err != nil || v < 0 {
+		return DefaultLimitWarn
+	}
+	return v
+}
+
 // SetGuestServer saves a server with guest auth enabled and no token
 func SetGuestServer(serverURL string) error {
 	serverURL = NormalizeURL(serverURL)
@@ -541,6 +856,7 @@ func SetConfigPathForTest(path string) {
 }
 
 func ResetForTest() {
+	serverOverride = ""
 	cfg = &Config{
 		Servers: make(map[string]ServerConfig),
 		Aliases: make(map[string]string),
@@ -6,7 +6,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,6 +36,7 @@ func saveCfgState(t *testing.T) {
 	oldVi := vi
 	vi = viper.NewWithOptions(viper.KeyDelimiter("::"))
 	keyringMockInitWithError(errors.New("keyring disabled in test"))
+	t.Setenv(EnvTokenCommand, "")
 	t.Cleanup(func() {
 		cfg = oldCfg
 		configPath = oldPath
@@ -804,7 +807,7 @@ func TestSetServerWithKeyring(T *testing.T) {
 	configPath = tmpDir + "/config.yml"
 	cfg = &Config{Servers: make(map[string]ServerConfig)}
 
-	insecure, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", false)
+	insecure, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", "", false)
 	require.NoError(T, err)
 	assert.False(T, insecure)
 
@@ -822,7 +825,7 @@ func TestSetServerKeyringFallback(T *testing.T) {
 	configPath = tmpDir + "/config.yml"
 	cfg = &Config{Servers: make(map[string]ServerConfig)}
 
-	insecure, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", false)
+	insecure, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", "", false)
 	require.NoError(T, err)
 	assert.True(T, insecure)
 
@@ -836,7 +839,7 @@ func TestRemoveServerCleansKeyring(T *testing.T) {
 	configPath = tmpDir + "/config.yml"
 	cfg = &Config{Servers: make(map[string]ServerConfig)}
 
-	_, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", false)
+	_, err := SetServerWithKeyring("https://tc.example.com", "my-token", "admin", "", "", false)
 	require.NoError(T, err)
 
 	err = RemoveServer("https://tc.example.com")
@@ -984,3 +987,180 @@ func TestIsReadOnly(T *testing.T) {
 		assert.False(t, IsReadOnly())
 	})
 }
+
+func TestCLIContext(T *testing.T) {
+	saveCfgState(T)
+	configPath = T.TempDir() + "/config.yml"
+	T.Setenv(EnvServerURL, "")
+	T.Setenv(EnvContext, "")
+	cfg = &Config{
+		DefaultServer: "https://tc.example.com",
+		Servers:       map[string]ServerConfig{"https://tc.example.com": {Token: "token", User: "user"}},
+	}
+	assert.Empty(T, CLIContext())
+
+	require.NoError(T, SetField("context", "release-bot", ""))
+	assert.Equal(T, "release-bot", CLIContext())
+	got, err := GetField("context", "")
+	require.NoError(T, err)
+	assert.Equal(T, "release-bot", got)
+
+	T.Setenv(EnvContext, " nightly ")
+	assert.Equal(T, "nightly", CLIContext(), "TC_CONTEXT overrides the config key")
+
+	err = SetField("context", "eyJ0eXAiOiAiVENWMiJ9", "")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "looks like a token")
+	assert.Equal(T, "release-bot", cfg.Servers["https://tc.example.com"].Context, "a rejected label leaves the old one")
+
+	require.NoError(T, SetField("context", "", ""))
+	assert.Empty(T, cfg.Servers["https://tc.example.com"].Context)
+}
+
+func TestNotifyWebhook(T *testing.T) {
+	saveCfgState(T)
+	configPath = T.TempDir() + "/config.yml"
+	T.Setenv(EnvServerURL, "")
+	cfg = &Config{
+		DefaultServer: "https://tc.example.com",
+		Servers:       map[string]ServerConfig{"https://tc.example.com": {Token: "token", User: "user"}},
+	}
+	assert.Empty(T, NotifyWebhook())
+
+	require.NoError(T, SetField("notify_webhook", "https://hooks.example.com/T1", ""))
+	assert.Equal(T, "https://hooks.example.com/T1", NotifyWebhook())
+	got, err := GetField("notify_webhook", "")
+	require.NoError(T, err)
+	assert.Equal(T, "https://hooks.example.com/T1", got)
+
+	err = SetField("notify_webhook", "hooks.example.com/T1", "")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "must be an http or https URL")
+
+	require.NoError(T, SetField("notify_webhook", "", ""))
+	assert.Empty(T, NotifyWebhook())
+}
+
+func TestServerTLS(T *testing.T) {
+	saveCfgState(T)
+	dir := T.TempDir()
+	configPath = dir + "/config.yml"
+	T.Setenv(EnvServerURL, "")
+	T.Setenv(EnvCACert, "")
+	T.Setenv(EnvInsecureSkipVerify, "")
+	cfg = &Config{
+		DefaultServer: "https://tc.example.com",
+		Servers:       map[string]ServerConfig{"https://tc.example.com": {Token: "token", User: "user"}},
+	}
+	assert.True(T, ServerTLS("https://tc.example.com").IsZero())
+
+	notPEM := filepath.Join(dir, "ca.txt")
+	require.NoError(T, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+	err := SetField("ca_cert", notPEM, "")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "no PEM certificates found")
+	err = SetField("ca_cert", filepath.Join(dir, "missing.pem"), "")
+	require.Error(T, err)
+
+	require.NoError(T, SetField("insecure_skip_verify", "true", ""))
+	got, err := GetField("insecure_skip_verify", "")
+	require.NoError(T, err)
+	assert.Equal(T, "true", got)
+	assert.Equal(T, api.TLSOptions{InsecureSkipVerify: true}, ServerTLS("https://tc.example.com/"))
+	assert.True(T, ServerTLS("https://other.example.com").IsZero(), "the keys are per server")
+
+	T.Setenv(EnvInsecureSkipVerify, "0")
+	T.Setenv(EnvCACert, "/etc/tc/ca.pem")
+	assert.Equal(T, api.TLSOptions{CACertFile: "/etc/tc/ca.pem"}, ServerTLS("https://tc.example.com"))
+}
+
+func TestDateFormatField(T *testing.T) {
+	saveCfgState(T)
+	configPath = T.TempDir() + "/config.yml"
+	cfg = &Config{Servers: map[string]ServerConfig{}}
+	assert.Empty(T, GetDateFormat())
+
+	require.NoError(T, SetField("date_format", "EU", ""))
+	got, err := GetField("date_format", "")
+	require.NoError(T, err)
+	assert.Equal(T, "eu", got)
+
+	err = SetField("date_format", "dd/mm/yyyy", "")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "iso, us, eu, uk")
+	assert.Equal(T, "eu", GetDateFormat(), "a rejected format leaves the old one")
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(T, err)
+	assert.Contains(T, string(data), "date_format: eu")
+}
+
+func TestDefaultCacheField(T *testing.T) {
+	saveCfgState(T)
+	configPath = T.TempDir() + "/config.yml"
+	cfg = &Config{Servers: map[string]ServerConfig{}}
+	assert.Zero(T, DefaultCache())
+
+	require.NoError(T, SetField("default_cache", "90s", ""))
+	assert.Equal(T, 90*time.Second, DefaultCache())
+
+	err := SetField("default_cache", "soon", "")
+	require.Error(T, err)
+	assert.Contains(T, err.Error(), "must be a duration")
+	assert.Equal(T, 90*time.Second, DefaultCache(), "a rejected value leaves the old one")
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(T, err)
+	assert.Contains(T, string(data), "default_cache: 90s")
+
+	require.NoError(T, SetField("default_cache", "0", ""))
+	got, err := GetField("default_cache", "")
+	require.NoError(T, err)
+	assert.Empty(T, got)
+	assert.Zero(T, DefaultCache())
+}
+
+func TestServerOverride(T *testing.T) {
+	saveCfgState(T)
+	T.Cleanup(func() { SetServerOverride("") })
+	T.Setenv(EnvServerURL, "")
+	T.Setenv(EnvToken, "env-token")
+	cfg = &Config{
+		DefaultServer: "https://tc.example.com",
+		Servers: map[string]ServerConfig{
+			"https://tc.example.com":         {Token: "prod-token"},
+			"https://staging.example.com/bs": {Token: "staging-token", Context: "staging"},
+		},
+	}
+
+	for _, value := range []string{"staging.example.com/bs/", "https://staging.example.com/bs", "staging"} {
+		got, err := ResolveServer(value)
+		require.NoError(T, err, value)
+		assert.Equal(T, "https://staging.example.com/bs", got, value)
+	}
+	got, err := ResolveServer("other.example.com")
+	require.NoError(T, err)
+	assert.Equal(T, "https://other.example.com", got, "unconfigured URLs are taken as is")
+	_, err = ResolveServer("prod")
+	assert.Error(T, err)
+
+	token, source, _ := GetTokenWithSource()
+	assert.Equal(T, "env-token", token, "TEAMCITY_TOKEN wins without --server")
+	assert.Equal(T, "env", source)
+
+	SetServerOverride("https://staging.example.com/bs")
+	assert.Equal(T, "https://staging.example.com/bs", GetServerURL())
+	token, source, _ = GetTokenWithSource()
+	assert.Equal(T, "staging-token", token, "the picked server's stored token wins")
+	assert.Equal(T, "config", source)
+
+	SetServerOverride("https://other.example.com")
+	token, _, _ = GetTokenWithSource()
+	assert.Equal(T, "env-token", token, "TEAMCITY_TOKEN stands in for a missing stored token")
+
+	SetServerOverride("")
+	token, source, _ = TokenWithSourceFor("https://staging.example.com/bs")
+	assert.Equal(T, "staging-token", token, "a server given directly works like --server")
+	assert.Equal(T, "config", source)
+	assert.Equal(T, "https://tc.example.com", GetServerURL(), "without touching the override")
+}
@@ -3,14 +3,26 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/JetBrains/teamcity-cli/internal/datebucket"
 )
 
-var validKeys = []string{"default_server", "guest", "ro", "token_expiry", "analytics"}
+var validKeys = []string{"default_server", "guest", "ro", "token_expiry", "token_command", "allow_vcs_edits", "context", "notify_webhook", "ca_cert", "insecure_skip_verify", "analytics", "date_format", "default_cache"}
+
+// commitLinkPrefix starts the per-VCS-root keys holding commit URL templates, e.g. commit_link.Falcon_GitHub.
+const commitLinkPrefix = "commit_link."
 
 func IsValidKey(key string) bool {
+	if id, ok := strings.CutPrefix(key, commitLinkPrefix); ok {
+		return id != ""
+	}
 	return slices.Contains(validKeys, key)
 }
 
@@ -28,6 +40,12 @@ func GetField(key, serverURL string) (string, error) {
 	if key == "analytics" {
 		return strconv.FormatBool(IsAnalyticsEnabled()), nil
 	}
+	if key == "date_format" {
+		return GetDateFormat(), nil
+	}
+	if key == "default_cache" {
+		return Get().DefaultCache, nil
+	}
 	serverURL, err := resolveServerForConfig(serverURL)
 	if err != nil {
 		return "", err
@@ -43,6 +61,25 @@ func GetField(key, serverURL string) (string, error) {
 		return strconv.FormatBool(sc.RO), nil
 	case "token_expiry":
 		return sc.TokenExpiry, nil
+	case "token_command":
+		return sc.TokenCommand, nil
+	case "allow_vcs_edits":
+		return strconv.FormatBool(sc.AllowVCSEdits), nil
+	case "context":
+		return sc.Context, nil
+	case "notify_webhook":
+		return sc.NotifyWebhook, nil
+	case "ca_cert":
+		return sc.CACert, nil
+	case "insecure_skip_verify":
+		return strconv.FormatBool(sc.InsecureSkipVerify), nil
+	}
+	if id, ok := strings.CutPrefix(key, commitLinkPrefix); ok {
+		for k, tpl := range sc.CommitLinks {
+			if strings.EqualFold(k, id) {
+				return tpl, nil
+			}
+		}
 	}
 	return "", nil
 }
@@ -69,6 +106,27 @@ func SetField(key, value, serverURL string) error {
 		}
 		return SetAnalyticsEnabled(b)
 	}
+	if key == "date_format" {
+		if value != "" {
+			if _, err := datebucket.DateLayout(value, ""); err != nil {
+				return err
+			}
+		}
+		cfg.DateFormat = strings.ToLower(value)
+		return writeConfig()
+	}
+	if key == "default_cache" {
+		if value == "0" {
+			value = ""
+		}
+		if value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("default_cache %q must be a duration such as 60s or 5m", value)
+			}
+		}
+		cfg.DefaultCache = value
+		return writeConfig()
+	}
 	serverURL, err := resolveServerForConfig(serverURL)
 	if err != nil {
 		return err
@@ -92,6 +150,61 @@ func SetField(key, value, serverURL string) error {
 		sc.RO = b
 	case "token_expiry":
 		sc.TokenExpiry = value
+	case "token_command":
+		sc.TokenCommand = value
+	case "allow_vcs_edits":
+		b, err := parseBoolValue(value)
+		if err != nil {
+			return err
+		}
+		sc.AllowVCSEdits = b
+	case "context":
+		if value != "" {
+			if err := api.ValidateCLIContext(value); err != nil {
+				return err
+			}
+		}
+		sc.Context = value
+	case "notify_webhook":
+		if value != "" {
+			if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("notify_webhook %q must be an http or https URL", value)
+			}
+		}
+		sc.NotifyWebhook = value
+	case "ca_cert":
+		if value != "" {
+			abs, err := filepath.Abs(value)
+			if err != nil {
+				return err
+			}
+			if _, err := (api.TLSOptions{CACertFile: abs}).Config(); err != nil {
+				return err
+			}
+			value = abs
+		}
+		sc.CACert = value
+	case "insecure_skip_verify":
+		b, err := parseBoolValue(value)
+		if err != nil {
+			return err
+		}
+		sc.InsecureSkipVerify = b
+	}
+	if id, ok := strings.CutPrefix(key, commitLinkPrefix); ok {
+		if value != "" && !strings.Contains(value, "{sha}") {
+			return fmt.Errorf("commit link template %q must contain {sha}", value)
+		}
+		links := make(map[string]string, len(sc.CommitLinks)+1)
+		for k, tpl := range sc.CommitLinks {
+			if !strings.EqualFold(k, id) {
+				links[k] = tpl
+			}
+		}
+		if value != "" {
+			links[strings.ToLower(id)] = value
+		}
+		sc.CommitLinks = links
 	}
 	cfg.Servers[serverURL] = sc
 	return writeConfig()
@@ -120,5 +233,5 @@ func parseBoolValue(s string) (bool, error) {
 }
 
 func unknownKeyError(key string) error {
-	return fmt.Errorf("unknown key %q; valid keys: %s", key, strings.Join(validKeys, ", "))
+	return fmt.Errorf("unknown key %q; valid keys: %s, %s<vcs-root-id>", key, strings.Join(validKeys, ", "), commitLinkPrefix)
 }
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// Migration step kinds reported by Migrate.
+const (
+	StepMergeServers   = "merge_servers"
+	StepRenameServer   = "rename_server"
+	StepDefaultServer  = "default_server"
+	StepMoveToken      = "move_token"
+	StepKeepToken      = "keep_token"
+	StepFixPermissions = "fix_permissions"
+)
+
+// MigrationStep is one change Migrate made, or would make in a dry run. Done is false for steps that
+// were skipped or only planned.
+type MigrationStep struct {
+	Kind   string `json:"kind"`
+	Server string `json:"server,omitempty"`
+	Detail string `json:"detail"`
+	Done   bool   `json:"done"`
+}
+
+// MigrateOptions controls Migrate.
+type MigrateOptions struct {
+	DryRun bool
+	// MoveTokens moves plaintext tokens into the keyring; without it they are only reported.
+	MoveTokens bool
+	// VerifyToken returns the username token authenticates as on serverURL. A token is moved only once verified.
+	VerifyToken func(serverURL, token string) (string, error)
+}
+
+// findServer returns the configured entry for serverURL and its key. Keys are matched by NormalizeURL, so
+// entries written by older versions with a trailing slash or without a scheme are still found; an exact key wins.
+func findServer(serverURL string) (string, ServerConfig, bool) {
+	if cfg == nil {
+		return "", ServerConfig{}, false
+	}
+	if sc, ok := cfg.Servers[serverURL]; ok {
+		return serverURL, sc, true
+	}
+	want := NormalizeURL(serverURL)
+	for _, key := range slices.Sorted(maps.Keys(cfg.Servers)) {
+		if NormalizeURL(key) == want {
+			return key, cfg.Servers[key], true
+		}
+	}
+	return "", ServerConfig{}, false
+}
+
+// PlaintextTokenServers returns the servers whose token is stored in the config file, sorted.
+func PlaintextTokenServers() []string {
+	var urls []string
+	for _, key := range slices.Sorted(maps.Keys(Get().Servers)) {
+		if cfg.Servers[key].Token != "" {
+			urls = append(urls, NormalizeURL(key))
+		}
+	}
+	return slices.Compact(urls)
+}
+
+// Migrate brings a config written by an older version up to date: server keys are normalized (merging
+// duplicates), plaintext tokens are moved into the keyring when opts.MoveTokens is set, and the file is
+// made private. It returns what changed; with opts.DryRun nothing is written.
+func Migrate(opts MigrateOptions) ([]MigrationStep, error) {
+	c := Get()
+	servers, steps := normalizeServers(c.Servers, opts.DryRun)
+	changed := len(steps) > 0
+
+	if !opts.DryRun {
+		for _, s := range steps {
+			moveKeyringEntry(c.Servers, s)
+		}
+	}
+
+	defaultServer := c.DefaultServer
+	if n := NormalizeURL(defaultServer); n != defaultServer {
+		steps = append(steps, MigrationStep{Kind: StepDefaultServer, Server: n, Detail: fmt.Sprintf("%s default server %s to %s", verb(opts.DryRun, "set", "would set"), defaultServer, n)})
+		defaultServer, changed = n, true
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(servers)) {
+		sc := servers[key]
+		if sc.Token == "" {
+			continue
+		}
+		step := moveToken(key, &sc, opts)
+		if step.Done {
+			servers[key] = sc
+			changed = true
+		}
+		steps = append(steps, step)
+	}
+
+	if step, ok := fixPermissions(opts.DryRun); ok {
+		steps = append(steps, step)
+	}
+
+	if opts.DryRun {
+		return steps, nil
+	}
+	for i := range steps {
+		if steps[i].Kind != StepKeepToken && steps[i].Kind != StepFixPermissions {
+			steps[i].Done = true
+		}
+	}
+	if changed {
+		c.Servers, c.DefaultServer = servers, defaultServer
+		if err := writeConfig(); err != nil {
+			return steps, err
+		}
+	}
+	return steps, nil
+}
+
+// normalizeServers rekeys servers by NormalizeURL. Duplicates are merged into the entry with a user set,
+// then the one already normalized; the others only fill settings it lacks.
+func normalizeServers(servers map[string]ServerConfig, dryRun bool) (map[string]ServerConfig, []MigrationStep) {
+	groups := map[string][]string{}
+	for key := range servers {
+		n := NormalizeURL(key)
+		groups[n] = append(groups[n], key)
+	}
+
+	out := make(map[string]ServerConfig, len(groups))
+	var steps []MigrationStep
+	for _, n := range slices.Sorted(maps.Keys(groups)) {
+		keys := groups[n]
+		slices.SortFunc(keys, func(a, b string) int {
+			return cmp.Or(
+				compareBool(servers[a].User != "", servers[b].User != ""),
+				compareBool(a == n, b == n),
+				cmp.Compare(a, b),
+			)
+		})
+		merged := servers[keys[0]]
+		for _, other := range keys[1:] {
+			merged = mergeServer(merged, servers[other])
+		}
+		out[n] = merged
+
+		switch {
+		case len(keys) > 1:
+			steps = append(steps, MigrationStep{Kind: StepMergeServers, Server: n,
+				Detail: fmt.Sprintf("%s %d entries for %s, keeping %s", verb(dryRun, "merged", "would merge"), len(keys), n, describeEntry(keys[0], servers[keys[0]]))})
+		case keys[0] != n:
+			steps = append(steps, MigrationStep{Kind: StepRenameServer, Server: n, Detail: fmt.Sprintf("%s %s to %s", verb(dryRun, "renamed", "would rename"), keys[0], n)})
+		}
+	}
+	return out, steps
+}
+
+// verb picks the wording of a step for a dry run or a real one.
+func verb(dryRun bool, done, planned string) string {
+	if dryRun {
+		return planned
+	}
+	return done
+}
+
+// compareBool orders true before false.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func describeEntry(key string, sc ServerConfig) string {
+	if sc.User != "" {
+		return fmt.Sprintf("%s (user %s)", key, sc.User)
+	}
+	return key
+}
+
+// mergeServer fills settings missing from keep with those of other. A token is only taken from an entry
+// of the same (or no) user, since it authenticates as that user.
+func mergeServer(keep, other ServerConfig) ServerConfig {
+	sameUser := other.User == "" || keep.User == "" || other.User == keep.User
+	if keep.Token == "" && sameUser {
+		keep.Token = other.Token
+		keep.TokenExpiry = cmp.Or(keep.TokenExpiry, other.TokenExpiry)
+		keep.TokenName = cmp.Or(keep.TokenName, other.TokenName)
+	}
+	keep.User = cmp.Or(keep.User, other.User)
+	keep.Guest = keep.Guest || other.Guest
+	keep.RO = keep.RO || other.RO
+	keep.AllowVCSEdits = keep.AllowVCSEdits || other.AllowVCSEdits
+	keep.Context = cmp.Or(keep.Context, other.Context)
+	keep.NotifyWebhook = cmp.Or(keep.NotifyWebhook, other.NotifyWebhook)
+	keep.CACert = cmp.Or(keep.CACert, other.CACert)
+	keep.InsecureSkipVerify = keep.InsecureSkipVerify || other.InsecureSkipVerify
+	if len(other.CommitLinks) > 0 {
+		links := maps.Clone(other.CommitLinks)
+		maps.Copy(links, keep.CommitLinks)
+		keep.CommitLinks = links
+	}
+	return keep
+}
+
+// moveKeyringEntry carries the keyring token of a renamed or merged server over to its normalized key.
+func moveKeyringEntry(servers map[string]ServerConfig, step MigrationStep) {
+	for key, sc := range servers {
+		if key == step.Server || sc.User == "" || NormalizeURL(key) != step.Server {
+			continue
+		}
+		token, err := keyringGet(keyringService(key), sc.User)
+		if err != nil || token == "" {
+			continue
+		}
+		if _, err := keyringGet(keyringService(step.Server), sc.User); err == nil {
+			continue
+		}
+		if keyringSet(keyringService(step.Server), sc.User, token) == nil {
+			_ = keyringDelete(keyringService(key), sc.User)
+		}
+	}
+}
+
+// moveToken moves the plaintext token of serverURL into the keyring, clearing it from sc only once the
+// token is verified and the keyring returns it back.
+func moveToken(serverURL string, sc *ServerConfig, opts MigrateOptions) MigrationStep {
+	keep := func(format string, args ...any) MigrationStep {
+		return MigrationStep{Kind: StepKeepToken, Server: serverURL, Detail: fmt.Sprintf(format, args...)}
+	}
+	switch {
+	case !opts.MoveTokens:
+		return keep("token for %s is stored in plain text", serverURL)
+	case opts.DryRun:
+		return MigrationStep{Kind: StepMoveToken, Server: serverURL, Detail: fmt.Sprintf("would verify the token for %s and move it into the keyring", serverURL)}
+	case opts.VerifyToken == nil:
+		return keep("token for %s left in plain text: cannot verify it", serverURL)
+	}
+
+	user, err := opts.VerifyToken(serverURL, sc.Token)
+	if err != nil {
+		return keep("token for %s left in plain text: verification failed: %v", serverURL, err)
+	}
+	service := keyringService(serverURL)
+	if err := keyringSet(service, user, sc.Token); err != nil {
+		return keep("token for %s left in plain text: keyring unavailable: %v", serverURL, err)
+	}
+	if stored, err := keyringGet(service, user); err != nil || stored != sc.Token {
+		return keep("token for %s left in plain text: keyring did not return it back", serverURL)
+	}
+	sc.Token, sc.User = "", user
+	return MigrationStep{Kind: StepMoveToken, Server: serverURL, Detail: fmt.Sprintf("moved token for %s (user %s) into the keyring", serverURL, user), Done: true}
+}
+
+// fixPermissions makes the config file readable by its owner only.
+func fixPermissions(dryRun bool) (MigrationStep, bool) {
+	path := resolveSymlink(configPath)
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0o077 == 0 {
+		return MigrationStep{}, false
+	}
+	step := MigrationStep{Kind: StepFixPermissions, Detail: fmt.Sprintf("%s permissions of %s from %04o to 0600", verb(dryRun, "changed", "would change"), path, info.Mode().Perm())}
+	if dryRun {
+		return step, true
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		step.Detail = fmt.Sprintf("could not change permissions of %s: %v", path, err)
+		return step, true
+	}
+	step.Done = true
+	return step, true
+}
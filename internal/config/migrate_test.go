@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFixture installs testdata/name as the config file, world-readable as older versions wrote it, and loads it.
+func loadFixture(t *testing.T, name string) string {
+	t.Helper()
+	saveCfgState(t)
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path := filepath.Join(dir, "tc", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	require.NoError(t, os.Chmod(path, 0o644))
+	require.NoError(t, Init())
+	return path
+}
+
+// reload reads the config file back from disk.
+func reload(t *testing.T) *Config {
+	t.Helper()
+	cfg = nil
+	require.NoError(t, Init())
+	return cfg
+}
+
+func stepKinds(steps []MigrationStep) []string {
+	kinds := make([]string, len(steps))
+	for i, s := range steps {
+		kinds[i] = s.Kind
+	}
+	return kinds
+}
+
+func TestMigrateNormalizesServers(T *testing.T) {
+	path := loadFixture(T, "legacy-duplicates.yml")
+
+	steps, err := Migrate(MigrateOptions{})
+	require.NoError(T, err)
+
+	wantKinds := []string{StepMergeServers, StepRenameServer, StepDefaultServer, StepKeepToken, StepKeepToken}
+	if runtime.GOOS != "windows" {
+		wantKinds = append(wantKinds, StepFixPermissions)
+	}
+	assert.Equal(T, wantKinds, stepKinds(steps))
+	assert.Contains(T, steps[0].Detail, "merged 2 entries for https://tc.example.com, keeping https://tc.example.com (user alice)")
+
+	c := reload(T)
+	assert.Equal(T, "https://tc.example.com", c.DefaultServer)
+	assert.Equal(T, map[string]ServerConfig{
+		"https://tc.example.com": {User: "alice", Token: "old-token", RO: true,
+			CommitLinks: map[string]string{"falcon_github": "https://github.com/acme/falcon/commit/{sha}"}},
+		"https://tc.other.com": {User: "bob", Token: "other-token"},
+	}, c.Servers)
+	assert.Equal(T, "run list", c.Aliases["rl"], "unrelated settings survive")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(T, err)
+		assert.Equal(T, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	steps, err = Migrate(MigrateOptions{})
+	require.NoError(T, err)
+	assert.Equal(T, []string{StepKeepToken, StepKeepToken}, stepKinds(steps), "a migrated config only reports plaintext tokens")
+}
+
+func TestMigrateDryRun(T *testing.T) {
+	path := loadFixture(T, "legacy-duplicates.yml")
+	before, err := os.ReadFile(path)
+	require.NoError(T, err)
+
+	steps, err := Migrate(MigrateOptions{DryRun: true, MoveTokens: true, VerifyToken: func(string, string) (string, error) {
+		T.Fatal("dry run must not verify tokens")
+		return "", nil
+	}})
+	require.NoError(T, err)
+	assert.Contains(T, stepKinds(steps), StepMoveToken)
+	for _, s := range steps {
+		assert.False(T, s.Done, s.Detail)
+	}
+	assert.Equal(T, "would merge 2 entries for https://tc.example.com, keeping https://tc.example.com (user alice)", steps[0].Detail)
+
+	after, err := os.ReadFile(path)
+	require.NoError(T, err)
+	assert.Equal(T, string(before), string(after))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(T, err)
+		assert.Equal(T, os.FileMode(0o644), info.Mode().Perm())
+	}
+}
+
+func TestMigrateMovesVerifiedTokens(T *testing.T) {
+	loadFixture(T, "legacy-duplicates.yml")
+	keyringMockInit()
+
+	verify := func(serverURL, token string) (string, error) {
+		if token == "old-token" {
+			return "alice", nil
+		}
+		return "", errors.New("401 Unauthorized")
+	}
+	steps, err := Migrate(MigrateOptions{MoveTokens: true, VerifyToken: verify})
+	require.NoError(T, err)
+
+	var moved, kept []string
+	for _, s := range steps {
+		switch s.Kind {
+		case StepMoveToken:
+			moved = append(moved, s.Server)
+		case StepKeepToken:
+			kept = append(kept, s.Detail)
+		}
+	}
+	assert.Equal(T, []string{"https://tc.example.com"}, moved)
+	require.Len(T, kept, 1)
+	assert.Contains(T, kept[0], "verification failed: 401 Unauthorized")
+
+	c := reload(T)
+	assert.Empty(T, c.Servers["https://tc.example.com"].Token)
+	assert.Equal(T, "other-token", c.Servers["https://tc.other.com"].Token, "an unverified token stays in place")
+
+	token, source, err := GetTokenForServer("https://tc.example.com")
+	require.NoError(T, err)
+	assert.Equal(T, "old-token", token)
+	assert.Equal(T, "keyring", source)
+}
+
+func TestMigrateKeepsTokenWhenKeyringUnavailable(T *testing.T) {
+	loadFixture(T, "legacy-duplicates.yml")
+
+	steps, err := Migrate(MigrateOptions{MoveTokens: true, VerifyToken: func(string, string) (string, error) { return "alice", nil }})
+	require.NoError(T, err)
+	assert.NotContains(T, stepKinds(steps), StepMoveToken)
+	assert.Equal(T, "old-token", reload(T).Servers["https://tc.example.com"].Token)
+}
+
+func TestMigrateMovesKeyringEntryOfRenamedServer(T *testing.T) {
+	loadFixture(T, "legacy-keyring.yml")
+	keyringMockInit()
+	require.NoError(T, keyringSet("tc:https://tc.example.com/", "alice", "keyring-token"))
+
+	token, _, err := GetTokenForServer("https://tc.example.com")
+	require.NoError(T, err)
+	assert.Equal(T, "keyring-token", token, "the legacy key is found before migrating")
+
+	_, err = Migrate(MigrateOptions{})
+	require.NoError(T, err)
+
+	assert.Contains(T, reload(T).Servers, "https://tc.example.com")
+	got, err := keyringGet("tc:https://tc.example.com", "alice")
+	require.NoError(T, err)
+	assert.Equal(T, "keyring-token", got)
+	_, err = keyringGet("tc:https://tc.example.com/", "alice")
+	assert.ErrorIs(T, err, errKeyringNotFound)
+}
+
+func TestMigrateCurrentConfig(T *testing.T) {
+	path := loadFixture(T, "current.yml")
+	require.NoError(T, os.Chmod(path, 0o600))
+
+	steps, err := Migrate(MigrateOptions{MoveTokens: true})
+	require.NoError(T, err)
+	assert.Empty(T, steps)
+}
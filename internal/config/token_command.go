@@ -0,0 +1,95 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+)
+
+const (
+	// tokenCommandTTL is how long a token a command printed is reused before the command runs again.
+	tokenCommandTTL = time.Minute
+	// tokenCommandTimeout bounds a token command, so a hung vault CLI can't hang every command with it.
+	tokenCommandTimeout = 30 * time.Second
+)
+
+// TokenCommandError is a token command that failed or printed no token, with what it wrote to stderr.
+type TokenCommandError struct {
+	Command string
+	Stderr  string
+	Err     error
+}
+
+func (e *TokenCommandError) Error() string {
+	msg := fmt.Sprintf("token command %q failed: %v", e.Command, e.Err)
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+func (e *TokenCommandError) Unwrap() error        { return e.Err }
+func (*TokenCommandError) Category() api.Category { return api.CatAuth }
+func (*TokenCommandError) Suggestion() string {
+	return fmt.Sprintf("Run the command yourself to check it prints a token, or fix %s or the server's token_command", EnvTokenCommand)
+}
+
+type cachedToken struct {
+	token   string
+	fetched time.Time
+}
+
+var (
+	tokenCacheMu sync.Mutex
+	tokenCache   = map[string]cachedToken{}
+
+	// injectable for testing
+	tokenShellFn = func(command string) (string, []string) {
+		if runtime.GOOS == "windows" {
+			return "cmd", []string{"/C", command}
+		}
+		return "sh", []string{"-c", command}
+	}
+	tokenNowFn = time.Now
+)
+
+// tokenFromCommand returns the token command prints on stdout, trimmed, running it through the shell at most once
+// per tokenCommandTTL.
+func tokenFromCommand(command string) (string, error) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+	if c, ok := tokenCache[command]; ok && tokenNowFn().Sub(c.fetched) < tokenCommandTTL {
+		return c.token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+	name, args := tokenShellFn(command)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", tokenCommandTimeout)
+	}
+	token := strings.TrimSpace(stdout.String())
+	if err == nil && token == "" {
+		err = errors.New("it printed no token")
+	}
+	if err == nil && strings.ContainsAny(token, "\r\n") {
+		err = errors.New("it printed more than one line")
+	}
+	if err != nil {
+		return "", &TokenCommandError{Command: command, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	tokenCache[command] = cachedToken{token: token, fetched: tokenNowFn()}
+	return token, nil
+}
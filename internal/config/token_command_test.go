@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/JetBrains/teamcity-cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTokenCommands clears the token cache and pins its clock for the test.
+func withTokenCommands(t *testing.T) *time.Time {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("token command tests use sh")
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	oldCache, oldNow := tokenCache, tokenNowFn
+	tokenCache = map[string]cachedToken{}
+	tokenNowFn = func() time.Time { return now }
+	t.Cleanup(func() { tokenCache, tokenNowFn = oldCache, oldNow })
+	return &now
+}
+
+func TestTokenCommand(T *testing.T) {
+	saveCfgState(T)
+	withTokenCommands(T)
+	T.Setenv(EnvServerURL, "")
+	T.Setenv(EnvToken, "")
+
+	serverURL := "https://tc.example.com"
+	cfg = &Config{
+		DefaultServer: serverURL,
+		Servers: map[string]ServerConfig{
+			serverURL: {Token: "config-token", TokenCommand: "echo '  command-token  '"},
+		},
+	}
+
+	T.Run("server's command wins over its stored token", func(t *testing.T) {
+		token, source, err := GetTokenWithSource()
+		require.NoError(t, err)
+		assert.Equal(t, "command-token", token)
+		assert.Equal(t, "command", source)
+		assert.Equal(t, "echo '  command-token  '", TokenCommand())
+	})
+
+	T.Run("env command wins over the server's", func(t *testing.T) {
+		t.Setenv(EnvTokenCommand, "echo env-command-token")
+		token, source, err := GetTokenWithSource()
+		require.NoError(t, err)
+		assert.Equal(t, "env-command-token", token)
+		assert.Equal(t, "command", source)
+	})
+
+	T.Run("TEAMCITY_TOKEN wins over both", func(t *testing.T) {
+		t.Setenv(EnvToken, "env-token")
+		t.Setenv(EnvTokenCommand, "echo env-command-token")
+		token, source, err := GetTokenWithSource()
+		require.NoError(t, err)
+		assert.Equal(t, "env-token", token)
+		assert.Equal(t, "env", source)
+		assert.Empty(t, TokenCommand())
+	})
+}
+
+func TestTokenCommandCache(T *testing.T) {
+	now := withTokenCommands(T)
+	counter := filepath.Join(T.TempDir(), "runs")
+	command := "echo run >> " + counter + "; echo token"
+
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return len(data) / len("run\n")
+	}
+	for range 3 {
+		token, err := tokenFromCommand(command)
+		require.NoError(T, err)
+		assert.Equal(T, "token", token)
+	}
+	assert.Equal(T, 1, runs(), "the token is reused within the TTL")
+
+	*now = now.Add(tokenCommandTTL)
+	_, err := tokenFromCommand(command)
+	require.NoError(T, err)
+	assert.Equal(T, 2, runs(), "and fetched again after it")
+}
+
+func TestTokenCommandErrors(T *testing.T) {
+	withTokenCommands(T)
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"fails", "echo 'vault: permission denied' >&2; exit 3", "exit status 3\nvault: permission denied"},
+		{"prints nothing", "true", "it printed no token"},
+		{"prints several lines", "printf 'a\\nb\\n'", "it printed more than one line"},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			_, err := tokenFromCommand(tc.command)
+			cmdErr, ok := errors.AsType[*TokenCommandError](err)
+			require.True(t, ok, "want a TokenCommandError, got %v", err)
+			assert.Contains(t, cmdErr.Error(), tc.want)
+			assert.Equal(t, api.CatAuth, cmdErr.Category())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package datebucket groups times into calendar days, weeks, or months. Boundaries are
+// computed on the calendar in the times' own location, so a bucket spans 23 or 25 hours
+// across a DST change rather than drifting by an hour, and weeks start on the configured day.
+package datebucket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Period is the calendar unit runs are grouped by.
+type Period string
+
+const (
+	Day   Period = "day"
+	Week  Period = "week"
+	Month Period = "month"
+)
+
+// Periods lists the valid values of Period, for flag help and completion.
+var Periods = []string{string(Day), string(Week), string(Month)}
+
+// ParsePeriod parses a --group-by value.
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(s)); p {
+	case Day, Week, Month:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid period %q, must be one of: %s", s, strings.Join(Periods, ", "))
+}
+
+// WeekStarts lists the days a week may start on.
+var WeekStarts = []string{"sunday", "monday"}
+
+// ParseWeekStart parses a --week-start value.
+func ParseWeekStart(s string) (time.Weekday, error) {
+	switch strings.ToLower(s) {
+	case "sunday", "sun":
+		return time.Sunday, nil
+	case "monday", "mon":
+		return time.Monday, nil
+	}
+	return 0, fmt.Errorf("invalid week start %q, must be one of: %s", s, strings.Join(WeekStarts, ", "))
+}
+
+// Start returns the beginning of the period containing t, in t's location.
+func Start(t time.Time, p Period, weekStart time.Weekday) time.Time {
+	y, m, d := t.Date()
+	switch p {
+	case Week:
+		d -= (int(t.Weekday()) - int(weekStart) + 7) % 7
+	case Month:
+		d = 1
+	}
+	// time.Date normalizes an out-of-range day into the previous month or year.
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Next returns the beginning of the period after the one starting at start.
+func Next(start time.Time, p Period) time.Time {
+	y, m, d := start.Date()
+	switch p {
+	case Week:
+		d += 7
+	case Month:
+		m++
+	default:
+		d++
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+}
+
+// Range returns the start of every period from the one containing from to the one containing to, in order.
+// It is empty when to is before from.
+func Range(from, to time.Time, p Period, weekStart time.Weekday) []time.Time {
+	var starts []time.Time
+	last := Start(to, p, weekStart)
+	for s := Start(from, p, weekStart); !s.After(last); s = Next(s, p) {
+		starts = append(starts, s)
+	}
+	return starts
+}
+
+// ISOWeek returns the ISO 8601 year and week of the week starting at start. A Sunday-start week shares
+// six days with the ISO week of the Monday after it, so that Monday names it.
+func ISOWeek(start time.Time) (year, week int) {
+	if start.Weekday() == time.Sunday {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.ISOWeek()
+}
+
+// Label names the period starting at start: the date for a day, the ISO week and its first date for a week,
+// and the month and year for a month. Dates are formatted with layout, and months in its style.
+func Label(start time.Time, p Period, layout string) string {
+	switch p {
+	case Week:
+		year, week := ISOWeek(start)
+		return fmt.Sprintf("%d-W%02d (%s)", year, week, start.Format(layout))
+	case Month:
+		if month, ok := monthLayouts[layout]; ok {
+			return start.Format(month)
+		}
+		return start.Format("Jan 2006")
+	}
+	return start.Format(layout)
+}
@@ -0,0 +1,172 @@
+package datebucket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestStart(T *testing.T) {
+	T.Parallel()
+	utc := time.UTC
+	// Thursday, Jan 1 2026.
+	newYear := time.Date(2026, 1, 1, 15, 30, 0, 0, utc)
+
+	tests := []struct {
+		name      string
+		t         time.Time
+		period    Period
+		weekStart time.Weekday
+		want      time.Time
+	}{
+		{"day", newYear, Day, time.Monday, time.Date(2026, 1, 1, 0, 0, 0, 0, utc)},
+		{"monday week across the year", newYear, Week, time.Monday, time.Date(2025, 12, 29, 0, 0, 0, 0, utc)},
+		{"sunday week across the year", newYear, Week, time.Sunday, time.Date(2025, 12, 28, 0, 0, 0, 0, utc)},
+		{"sunday in a monday week", time.Date(2026, 1, 4, 23, 59, 0, 0, utc), Week, time.Monday, time.Date(2025, 12, 29, 0, 0, 0, 0, utc)},
+		{"sunday starts its own week", time.Date(2026, 1, 4, 0, 0, 0, 0, utc), Week, time.Sunday, time.Date(2026, 1, 4, 0, 0, 0, 0, utc)},
+		{"month", time.Date(2026, 3, 31, 23, 0, 0, 0, utc), Month, time.Monday, time.Date(2026, 3, 1, 0, 0, 0, 0, utc)},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, Start(tc.t, tc.period, tc.weekStart))
+		})
+	}
+}
+
+func TestBucketsAcrossDST(T *testing.T) {
+	T.Parallel()
+	berlin := mustLoad(T, "Europe/Berlin")
+	newYork := mustLoad(T, "America/New_York")
+
+	// Clocks go forward on Mar 29 2026 in Berlin, so that day has 23 hours.
+	spring := time.Date(2026, 3, 29, 0, 0, 0, 0, berlin)
+	assert.Equal(T, 23*time.Hour, Next(spring, Day).Sub(spring))
+	assert.Equal(T, time.Date(2026, 3, 30, 0, 0, 0, 0, berlin), Next(spring, Day))
+	// Late on the short day still belongs to it.
+	assert.Equal(T, spring, Start(time.Date(2026, 3, 29, 23, 30, 0, 0, berlin), Day, time.Monday))
+
+	// Clocks go back on Nov 1 2026 in New York: the week holding it has 169 hours, and a time in the repeated
+	// hour lands in the right week.
+	week := Start(time.Date(2026, 11, 1, 1, 30, 0, 0, newYork), Week, time.Sunday)
+	assert.Equal(T, time.Date(2026, 11, 1, 0, 0, 0, 0, newYork), week)
+	assert.Equal(T, 169*time.Hour, Next(week, Week).Sub(week))
+
+	// Every bucket of a range starts at midnight local time, before and after the change.
+	for _, s := range Range(time.Date(2026, 3, 20, 12, 0, 0, 0, berlin), time.Date(2026, 4, 5, 12, 0, 0, 0, berlin), Day, time.Monday) {
+		assert.Equal(T, 0, s.Hour(), "bucket %s", s)
+	}
+}
+
+func TestRange(T *testing.T) {
+	T.Parallel()
+	from := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	months := Range(from, to, Month, time.Monday)
+	require.Len(T, months, 3)
+	assert.Equal(T, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), months[2])
+
+	weeks := Range(from, to, Week, time.Monday)
+	assert.Equal(T, time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC), weeks[0])
+	assert.Equal(T, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), weeks[len(weeks)-1])
+
+	assert.Empty(T, Range(to, from, Day, time.Monday))
+}
+
+func TestISOWeekAtYearBoundaries(T *testing.T) {
+	T.Parallel()
+	tests := []struct {
+		start      time.Time
+		year, week int
+	}{
+		// Dec 29 2025 is a Monday in week 1 of 2026.
+		{time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), 2026, 1},
+		// The Sunday-start week before it is named by its Monday, so it is also week 1 of 2026.
+		{time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC), 2026, 1},
+		// 2020 had 53 ISO weeks; the week of Jan 1 2021 is its last.
+		{time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC), 2020, 53},
+		{time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 2021, 1},
+	}
+	for _, tc := range tests {
+		year, week := ISOWeek(tc.start)
+		assert.Equal(T, [2]int{tc.year, tc.week}, [2]int{year, week}, "week starting %s", tc.start.Format(time.DateOnly))
+	}
+}
+
+func TestLabel(T *testing.T) {
+	T.Parallel()
+	start := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	assert.Equal(T, "12/28/2025", Label(start, Day, DateFormats["us"]))
+	assert.Equal(T, "2026-W01 (28.12.2025)", Label(start, Week, DateFormats["eu"]))
+	assert.Equal(T, "2025-12", Label(start, Month, DateFormats["iso"]))
+	assert.Equal(T, "12/2025", Label(start, Month, DateFormats["us"]))
+	assert.Equal(T, "12.2025", Label(start, Month, DateFormats["eu"]))
+	assert.Equal(T, "Dec 2025", Label(start, Month, "Jan 2, 2006"))
+	for name, layout := range DateFormats {
+		assert.Contains(T, monthLayouts, layout, "date format %s has no month layout", name)
+	}
+}
+
+func TestParse(T *testing.T) {
+	T.Parallel()
+	p, err := ParsePeriod("Week")
+	require.NoError(T, err)
+	assert.Equal(T, Week, p)
+	_, err = ParsePeriod("year")
+	assert.ErrorContains(T, err, "day, week, month")
+
+	ws, err := ParseWeekStart("sunday")
+	require.NoError(T, err)
+	assert.Equal(T, time.Sunday, ws)
+	_, err = ParseWeekStart("saturday")
+	assert.Error(T, err)
+}
+
+func TestLocale(T *testing.T) {
+	T.Parallel()
+	tests := []struct {
+		locale    string
+		weekStart time.Weekday
+		format    string
+	}{
+		{"en_US.UTF-8", time.Sunday, "us"},
+		{"en_GB.UTF-8", time.Monday, "uk"},
+		{"de_DE@euro", time.Monday, "eu"},
+		{"ja_JP", time.Sunday, "iso"},
+		{"pt-BR", time.Sunday, "uk"},
+		{"C", time.Monday, "iso"},
+		{"", time.Monday, "iso"},
+	}
+	for _, tc := range tests {
+		assert.Equal(T, tc.weekStart, LocaleWeekStart(tc.locale), "week start for %q", tc.locale)
+		assert.Equal(T, tc.format, LocaleDateFormat(tc.locale), "date format for %q", tc.locale)
+	}
+
+	layout, err := DateLayout("", "en_US.UTF-8")
+	require.NoError(T, err)
+	assert.Equal(T, "01/02/2006", layout)
+	layout, err = DateLayout("ISO", "en_US.UTF-8")
+	require.NoError(T, err)
+	assert.Equal(T, "2006-01-02", layout)
+	_, err = DateLayout("dd/mm", "")
+	assert.ErrorContains(T, err, "iso, us, eu, uk")
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "en_US.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "en_US.UTF-8", Locale())
+}
@@ -0,0 +1,93 @@
+package datebucket
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DateFormats maps the date_format config values to their layouts.
+var DateFormats = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02.01.2006",
+	"uk":  "02/01/2006",
+}
+
+// monthLayouts maps each DateFormats layout to the layout of a month in the same style.
+var monthLayouts = map[string]string{
+	"2006-01-02": "2006-01",
+	"01/02/2006": "01/2006",
+	"02.01.2006": "01.2006",
+	"02/01/2006": "01/2006",
+}
+
+// sundayTerritories are the regions whose calendars start the week on Sunday (CLDR firstDay "sun"); most
+// others start it on Monday.
+var sundayTerritories = []string{
+	"AG", "AS", "BD", "BR", "BS", "BT", "BW", "BZ", "CA", "CN", "CO", "DM", "DO", "ET", "GT", "GU", "HK", "HN",
+	"ID", "IL", "IN", "JM", "JP", "KE", "KH", "KR", "LA", "MH", "MM", "MO", "MT", "MX", "MZ", "NI", "NP", "PA",
+	"PE", "PH", "PK", "PR", "PT", "PY", "SA", "SG", "SV", "TH", "TT", "TW", "UM", "US", "VE", "VI", "WS", "YE",
+	"ZA", "ZW",
+}
+
+// Locale returns the user's time locale from LC_ALL, LC_TIME, or LANG, the first one set, as in POSIX.
+func Locale() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// territory extracts the region of a POSIX locale such as en_US.UTF-8 or de_CH@euro; "" when there is none.
+func territory(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	_, region, ok := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	if !ok {
+		return ""
+	}
+	return strings.ToUpper(region)
+}
+
+// LocaleWeekStart returns the first day of the week in locale's region: Sunday for the US and others that
+// use it, otherwise Monday, as ISO 8601 does. The C and POSIX locales use Monday.
+func LocaleWeekStart(locale string) time.Weekday {
+	if slices.Contains(sundayTerritories, territory(locale)) {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// LocaleDateFormat returns the date_format value matching locale's usual numeric dates; iso when unknown.
+func LocaleDateFormat(locale string) string {
+	switch territory(locale) {
+	case "US", "PH", "PR", "AS", "GU", "UM", "VI":
+		return "us"
+	case "GB", "IE", "AU", "NZ", "IN", "FR", "ES", "IT", "BE", "BR", "PT", "GR", "MX", "AR":
+		return "uk"
+	case "DE", "AT", "CH", "RU", "PL", "CZ", "SK", "FI", "NO", "DK", "TR", "UA", "RO", "HU", "HR", "SI":
+		return "eu"
+	}
+	return "iso"
+}
+
+// DateLayout returns the layout for a date_format value; an empty value follows locale.
+func DateLayout(format, locale string) (string, error) {
+	if format == "" {
+		format = LocaleDateFormat(locale)
+	}
+	if layout, ok := DateFormats[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	return "", fmt.Errorf("invalid date format %q, must be one of: %s", format, strings.Join(DateFormatNames(), ", "))
+}
+
+// DateFormatNames lists the date_format values in a stable order.
+func DateFormatNames() []string {
+	return []string{"iso", "us", "eu", "uk"}
+}
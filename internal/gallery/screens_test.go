@@ -419,7 +419,7 @@ func authScreens(t *testing.T, ts *cmdtest.TestServer) []termbook.Screen {
 		termbook.Scr("auth-status", "auth status", "Authentication status",
 			"teamcity auth status", capture(t, ts, "auth", "status")),
 		termbook.Manual("auth-status-multi", "auth status (multi-server)", "Multi-server authentication status", "teamcity auth status", func(w io.Writer) {
-			fmt.Fprintf(w, "%s Logged in to %s %s\n", output.Green("✓"), output.Cyan("https://tc.example.com"), output.Faint("(default)"))
+			fmt.Fprintf(w, "%s Logged in to %s %s\n", output.Green("✓"), output.Cyan("https://tc.example.com"), output.Faint("(active)"))
 			fmt.Fprintf(w, "  %s Viktor Tiulpin (vtiulpin) %s %s\n", output.Faint("User:"), output.Faint("·"), output.Faint("system keyring"))
 			fmt.Fprintln(w, "  Token expires: Dec 31, 2026")
 			fmt.Fprintf(w, "  %s\n", output.Faint("Server: TeamCity 2025.7 (build 197398)"))
@@ -555,7 +555,7 @@ func updateScreens(t *testing.T, ts *cmdtest.TestServer) []termbook.Screen {
 func errorScreens() []termbook.Screen {
 	return []termbook.Screen{
 		termbook.Manual("error-not-found", "Not Found", "Resource not found with contextual hint", "", func(w io.Writer) {
-			fmt.Fprintf(w, "Error: run %q not found\n\n%s\n", "999999", output.FormatTip("Run 'teamcity run list' to see available runs"))
+			fmt.Fprintf(w, "Error: run %q not found\n\n%s\n", "999999", output.FormatTip("If 999999 is a build number rather than an ID, use '#999999 --job <id>'"))
 		}),
 		termbook.Manual("error-auth", "Authentication Failed", "Invalid or expired token", "", func(w io.Writer) {
 			fmt.Fprintf(w, "Error: authentication failed: invalid or expired credentials\n\n%s\n", output.FormatTip("Run 'teamcity auth login' to re-authenticate"))
@@ -574,7 +574,7 @@ func errorScreens() []termbook.Screen {
 			fmt.Fprintln(w, `  "error": {`)
 			fmt.Fprintln(w, `    "code": "not_found",`)
 			fmt.Fprintln(w, `    "message": "run \"999999\" not found",`)
-			fmt.Fprintln(w, `    "suggestion": "Run 'teamcity run list' to see available runs"`)
+			fmt.Fprintln(w, `    "suggestion": "If 999999 is a build number rather than an ID, use '#999999 --job <id>'"`)
 			fmt.Fprintln(w, `  }`)
 			fmt.Fprintln(w, `}`)
 		}),
@@ -135,9 +135,10 @@ func Push(branch string) error {
 	return nil
 }
 
-// UntrackedFiles returns files reported by `git ls-files --others --exclude-standard`.
-func UntrackedFiles() ([]string, error) {
-	out, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
+// UntrackedFiles returns files reported by `git ls-files --others --exclude-standard`, limited to pathspecs if any.
+func UntrackedFiles(pathspecs ...string) ([]string, error) {
+	args := append([]string{"ls-files", "--others", "--exclude-standard", "--"}, pathspecs...)
+	out, err := exec.Command("git", args...).Output()
 	if err != nil {
 		return nil, fmt.Errorf("git ls-files: %w", err)
 	}
@@ -149,9 +150,9 @@ func UntrackedFiles() ([]string, error) {
 }
 
 // WorkingTreeDiffFrom returns `git diff <base>` output, including committed, staged,
-// unstaged, and untracked changes relative to base.
-func WorkingTreeDiffFrom(base string) ([]byte, error) {
-	untracked, err := UntrackedFiles()
+// unstaged, and untracked changes relative to base. With pathspecs, only the files they match are included.
+func WorkingTreeDiffFrom(base string, pathspecs ...string) ([]byte, error) {
+	untracked, err := UntrackedFiles(pathspecs...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,13 +165,54 @@ func WorkingTreeDiffFrom(base string) ([]byte, error) {
 			}()
 		}
 	}
-	out, err := exec.Command("git", "diff", base).Output()
+	return diff(base, false, pathspecs)
+}
+
+// StagedDiffFrom returns `git diff --cached <base>` output: committed and staged changes relative to base, leaving
+// out whatever is not in the index. With pathspecs, only the files they match are included.
+func StagedDiffFrom(base string, pathspecs ...string) ([]byte, error) {
+	return diff(base, true, pathspecs)
+}
+
+func diff(base string, cached bool, pathspecs []string) ([]byte, error) {
+	args := []string{"diff"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	args = append(append(args, base, "--"), pathspecs...)
+	out, err := exec.Command("git", args...).Output()
 	if err != nil {
-		return nil, fmt.Errorf("git diff %s: %w", base, err)
+		return nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
 	}
 	return out, nil
 }
 
+// BinaryFiles returns the files a patch written by `git diff` marks as binary ("Binary files a/x and b/x differ"),
+// whose contents it leaves out.
+func BinaryFiles(patch []byte) []string {
+	var files []string
+	for line := range strings.Lines(string(patch)) {
+		rest, ok := strings.CutPrefix(strings.TrimRight(line, "\r\n"), "Binary files ")
+		if !ok {
+			continue
+		}
+		rest, ok = strings.CutSuffix(rest, " differ")
+		if !ok {
+			continue
+		}
+		from, to, _ := strings.Cut(rest, " and ")
+		name := to
+		if name == "/dev/null" {
+			name = from
+		}
+		if i := strings.Index(name, "/"); i >= 0 && name != "/dev/null" {
+			name = name[i+1:]
+		}
+		files = append(files, name)
+	}
+	return files
+}
+
 // LocalBranches returns short names of local branches, or nil outside a working tree.
 func LocalBranches() []string {
 	out, err := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/").Output()
@@ -221,6 +221,82 @@ func TestUntrackedFiles(t *testing.T) {
 	})
 }
 
+func TestWorkingTreeDiffFrom(t *testing.T) {
+	dir := setupRepo(t)
+	t.Chdir(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "src"), 0o755))
+	writeFile(t, dir, "src/app.go", "package app\n")
+	writeFile(t, dir, "README.md", "readme\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "initial")
+
+	writeFile(t, dir, "src/app.go", "package app // edited\n")
+	writeFile(t, dir, "src/new.go", "package app // new\n")
+	writeFile(t, dir, "README.md", "readme, staged\n")
+	runGit(t, dir, "add", "README.md")
+
+	t.Run("whole tree", func(t *testing.T) {
+		patch, err := WorkingTreeDiffFrom("HEAD")
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "// edited")
+		assert.Contains(t, string(patch), "// new")
+		assert.Contains(t, string(patch), "readme, staged")
+	})
+
+	t.Run("pathspecs", func(t *testing.T) {
+		patch, err := WorkingTreeDiffFrom("HEAD", "src")
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "// edited")
+		assert.Contains(t, string(patch), "// new", "untracked files under the path are included")
+		assert.NotContains(t, string(patch), "readme")
+
+		untracked, err := UntrackedFiles()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"src/new.go"}, untracked, "untracked files stay untracked")
+	})
+
+	t.Run("pathspec matching nothing", func(t *testing.T) {
+		patch, err := WorkingTreeDiffFrom("HEAD", "docs")
+		require.NoError(t, err)
+		assert.Empty(t, patch)
+	})
+
+	t.Run("staged", func(t *testing.T) {
+		patch, err := StagedDiffFrom("HEAD")
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "readme, staged")
+		assert.NotContains(t, string(patch), "// edited")
+		assert.NotContains(t, string(patch), "// new")
+
+		patch, err = StagedDiffFrom("HEAD", "src")
+		require.NoError(t, err)
+		assert.Empty(t, patch)
+	})
+}
+
+func TestBinaryFiles(t *testing.T) {
+	dir := setupRepo(t)
+	t.Chdir(dir)
+	writeFile(t, dir, "logo.png", "\x89PNG\x00\x01")
+	writeFile(t, dir, "old.bin", "\x00\x01\x02")
+	writeFile(t, dir, "main.go", "package main\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "initial")
+
+	writeFile(t, dir, "logo.png", "\x89PNG\x00\x02")
+	require.NoError(t, os.Remove(filepath.Join(dir, "old.bin")))
+	writeFile(t, dir, "new.bin", "\x00\x03")
+	writeFile(t, dir, "main.go", "package main // edited\n")
+
+	patch, err := WorkingTreeDiffFrom("HEAD")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"logo.png", "old.bin", "new.bin"}, BinaryFiles(patch))
+
+	patch, err = WorkingTreeDiffFrom("HEAD", "main.go")
+	require.NoError(t, err)
+	assert.Empty(t, BinaryFiles(patch))
+}
+
 func TestCanonicalURL(t *testing.T) {
 	cases := []struct {
 		in, want string
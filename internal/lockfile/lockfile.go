@@ -0,0 +1,158 @@
+// Package lockfile implements advisory locks between CLI processes as files holding the owner's PID.
+// A lock whose owner is no longer running is stale and is taken over.
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// acquireAttempts bounds the take-over retries when other processes race for the same stale lock.
+const acquireAttempts = 3
+
+// writeGrace is how long a new, still empty lock counts as being written; writeWait is how long Acquire waits
+// for the PID to land before looking again.
+const (
+	writeGrace = time.Second
+	writeWait  = 20 * time.Millisecond
+)
+
+// processAlive reports whether pid is a running process; swapped in tests.
+var processAlive = alive
+
+// HeldError reports that another running process holds the lock.
+type HeldError struct {
+	Path string
+	PID  int
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("lock %s is held by process %d", e.Path, e.PID)
+}
+
+// Lock is a held lock; Release frees it.
+type Lock struct {
+	path string
+	pid  int
+}
+
+// Dir returns the per-user directory for lock files: $XDG_RUNTIME_DIR/tc when set, else tc/locks under the user cache directory.
+func Dir() (string, error) {
+	if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+		return filepath.Join(runtime, "tc"), nil
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate a directory for lock files: %w", err)
+	}
+	return filepath.Join(cache, "tc", "locks"), nil
+}
+
+// Acquire takes the lock at path, creating its directory. It returns a *HeldError when a running
+// process, including this one, already holds it, and takes the lock over when its owner has exited.
+func Acquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	pid := os.Getpid()
+	for range acquireAttempts {
+		err := create(path, pid)
+		if err == nil {
+			return &Lock{path: path, pid: pid}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		owner, err := readOwner(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // released in between
+		}
+		if err != nil {
+			return nil, err
+		}
+		if owner == 0 && beingWritten(path) {
+			time.Sleep(writeWait) // its owner is still writing the PID
+			continue
+		}
+		if owner == pid || processAlive(owner) {
+			return nil, &HeldError{Path: path, PID: owner}
+		}
+		if err := removeStale(path, owner); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire lock %s: other processes keep taking it", path)
+}
+
+// Release frees the lock. It leaves the file alone if another process has since taken it over.
+func (l *Lock) Release() error {
+	owner, err := readOwner(l.path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && owner != l.pid) {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// create makes the lock with O_EXCL, so exactly one process succeeds and the others get fs.ErrExist, then
+// writes pid into it. Until the write lands the lock is empty; see beingWritten.
+func create(path string, pid int) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(strconv.Itoa(pid))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+	}
+	return err
+}
+
+// beingWritten reports whether the lock at path is empty and was made within writeGrace, so its owner is still
+// writing the PID rather than having left it behind.
+func beingWritten(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Size() == 0 && time.Since(info.ModTime()) < writeGrace
+}
+
+// removeStale deletes the lock at path left by the exited owner. It first moves the lock aside, which only one
+// process can do, and puts it back if a live process took it over after owner was read.
+func removeStale(path string, owner int) error {
+	aside := fmt.Sprintf("%s.%d.stale", path, os.Getpid())
+	if err := os.Rename(path, aside); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // taken over and released in between
+		}
+		return fmt.Errorf("failed to remove stale lock %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(aside) }()
+	if now, err := readOwner(aside); err == nil && now != owner {
+		_ = os.Link(aside, path)
+		return &HeldError{Path: path, PID: now}
+	}
+	return nil
+}
+
+// readOwner returns the PID stored in the lock at path; unreadable content yields 0, which is never alive.
+func readOwner(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, nil
+	}
+	return pid, nil
+}
@@ -0,0 +1,112 @@
+package lockfile
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "run.lock")
+
+	lock, err := Acquire(path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid()), string(data))
+
+	_, err = Acquire(path)
+	held, ok := errors.AsType[*HeldError](err)
+	require.True(t, ok, "second acquire must report the holder, got %v", err)
+	assert.Equal(t, os.Getpid(), held.PID)
+
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, path)
+
+	again, err := Acquire(path)
+	require.NoError(t, err)
+	require.NoError(t, again.Release())
+}
+
+func TestAcquireHeldByLiveProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.lock")
+	require.NoError(t, os.WriteFile(path, []byte("4242"), 0o600))
+	orig := processAlive
+	t.Cleanup(func() { processAlive = orig })
+	processAlive = func(pid int) bool { return pid == 4242 }
+
+	_, err := Acquire(path)
+	held, ok := errors.AsType[*HeldError](err)
+	require.True(t, ok)
+	assert.Equal(t, 4242, held.PID)
+	assert.Contains(t, err.Error(), "held by process 4242")
+}
+
+func TestAcquireTakesOverStaleLock(t *testing.T) {
+	// A child that has exited and been reaped is a PID no running process owns.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	dead := cmd.Process.Pid
+	require.False(t, alive(dead))
+
+	for name, content := range map[string]string{"dead owner": strconv.Itoa(dead), "garbage": "not a pid", "empty": ""} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.lock")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			old := time.Now().Add(-time.Minute)
+			require.NoError(t, os.Chtimes(path, old, old))
+
+			lock, err := Acquire(path)
+			require.NoError(t, err)
+			data, _ := os.ReadFile(path)
+			assert.Equal(t, strconv.Itoa(os.Getpid()), string(data))
+			require.NoError(t, lock.Release())
+		})
+	}
+}
+
+func TestAcquireWaitsForNewLock(t *testing.T) {
+	// An empty lock just made is one whose owner has yet to write its PID, not one left behind.
+	path := filepath.Join(t.TempDir(), "run.lock")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	_, err := Acquire(path)
+	require.Error(t, err)
+	assert.FileExists(t, path)
+}
+
+func TestReleaseKeepsTakenOverLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.lock")
+	lock, err := Acquire(path)
+	require.NoError(t, err)
+
+	// Another process decided this lock was stale and took it over.
+	require.NoError(t, os.WriteFile(path, []byte("4242"), 0o600))
+	require.NoError(t, lock.Release())
+	assert.FileExists(t, path)
+}
+
+func TestAliveCurrentProcess(t *testing.T) {
+	assert.True(t, alive(os.Getpid()))
+	assert.False(t, alive(0))
+	assert.False(t, alive(-1))
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	dir, err := Dir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/run/user/1000", "tc"), dir)
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	dir, err = Dir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("tc", "locks"), filepath.Join(filepath.Base(filepath.Dir(dir)), filepath.Base(dir)))
+}
@@ -11,7 +11,8 @@ import (
 )
 
 // NoColor disables ANSI styling when true. Factory.InitOutput writes it from
-// NO_COLOR / TEAMCITY_NO_COLOR / FORCE_COLOR / --no-color / TTY detection;
+// --color / --no-color / NO_COLOR / TEAMCITY_NO_COLOR / FORCE_COLOR /
+// CLICOLOR_FORCE / TTY detection;
 // tests flip it directly for deterministic golden output.
 var NoColor bool
 
@@ -0,0 +1,15 @@
+package output
+
+// VT reports whether the console interprets ANSI escapes (colors, cursor moves).
+// Factory.InitOutput writes it from EnableVirtualTerminal; when false, status
+// lines are printed in full instead of redrawn with '\r'. Tests flip it directly.
+var VT = true
+
+// enableVTFn switches the console into VT mode; tests override to simulate legacy consoles.
+var enableVTFn = enableVirtualTerminal
+
+// EnableVirtualTerminal turns on ANSI escape processing where the platform needs it and records the result in VT.
+func EnableVirtualTerminal() bool {
+	VT = enableVTFn()
+	return VT
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package output
+
+// fallbackPagers are tried when PAGER is unset and less is not installed; none elsewhere, so paging is skipped.
+var fallbackPagers []string
+
+// enableVirtualTerminal reports whether the console interprets ANSI escapes.
+// Non-Windows terminals always do; force plain output with NO_COLOR or TERM=dumb.
+func enableVirtualTerminal() bool { return true }
@@ -0,0 +1,26 @@
+package output
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// fallbackPagers are tried when PAGER is unset and less is not installed; more ships with every Windows.
+var fallbackPagers = []string{"more"}
+
+// enableVirtualTerminal turns on ANSI escape processing for the stdout console.
+// Consoles older than Windows 10 reject the mode, so colors and in-place redraws
+// must be turned off. Redirected stdout has no console mode and is reported as
+// capable: nothing renders the escapes there, and NoColor already covers pipes.
+func enableVirtualTerminal() bool {
+	h := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		return true
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
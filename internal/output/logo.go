@@ -29,7 +29,7 @@ func PrintLogo(w io.Writer) {
 		_, _ = fmt.Fprintln(w, Cyan("\n"+LogoASCII))
 		return
 	}
-	if !IsTerminal() {
+	if !IsTerminal() || NoColor {
 		_, _ = fmt.Fprintln(w, Cyan("\n"+Logo))
 		return
 	}
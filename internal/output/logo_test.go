@@ -17,6 +17,9 @@ func TestPrintLogo(T *testing.T) {
 
 func TestPrintLogoTerminal(T *testing.T) {
 	overrideTerminal(T, true, 80, 24, nil)
+	old := NoColor
+	T.Cleanup(func() { NoColor = old })
+	NoColor = false
 
 	var buf bytes.Buffer
 	PrintLogo(&buf)
@@ -25,3 +28,15 @@ func TestPrintLogoTerminal(T *testing.T) {
 	assert.Contains(T, buf.String(), "\033[", "should contain ANSI escape sequences")
 	assert.NotEmpty(T, buf.String(), "logo output should not be empty")
 }
+
+func TestPrintLogoNoColor(T *testing.T) {
+	overrideTerminal(T, true, 80, 24, nil)
+	old := NoColor
+	T.Cleanup(func() { NoColor = old })
+	NoColor = true
+
+	var buf bytes.Buffer
+	PrintLogo(&buf)
+
+	assert.NotContains(T, buf.String(), "\033[", "a terminal with color off gets the plain logo")
+}
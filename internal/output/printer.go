@@ -27,6 +27,11 @@ func DefaultPrinter() *Printer {
 	}
 }
 
+// WithOut returns a Printer with p's settings that writes its regular output to w.
+func (p *Printer) WithOut(w io.Writer) *Printer {
+	return &Printer{Out: w, ErrOut: p.ErrOut, Quiet: p.Quiet, Verbose: p.Verbose}
+}
+
 // stopWriter halts the activity spinner before its first write; the spinner writes to the raw fd, not through this wrapper, so there is no feedback loop.
 type stopWriter struct{ w io.Writer }
 
@@ -35,6 +40,14 @@ func (s stopWriter) Write(b []byte) (int, error) {
 	return s.w.Write(b)
 }
 
+// IsStdout reports whether w writes to os.Stdout, directly or through the wrapper DefaultPrinter puts around it.
+func IsStdout(w io.Writer) bool {
+	if s, ok := w.(stopWriter); ok {
+		w = s.w
+	}
+	return w == os.Stdout
+}
+
 // write atomically emits s to w, serializing concurrent calls across all Printer methods.
 func (p *Printer) write(w io.Writer, s string) {
 	p.mu.Lock()
@@ -117,7 +130,9 @@ func (p *Printer) PrintField(label, value string) {
 func (p *Printer) PrintViewHeader(title, webURL string, details func()) {
 	p.write(p.Out, Cyan(title)+"\n")
 	details()
-	p.write(p.Out, fmt.Sprintf("\n%s %s\n", Faint("View in browser:"), Green(webURL)))
+	if webURL != "" {
+		p.write(p.Out, fmt.Sprintf("\n%s %s\n", Faint("View in browser:"), Green(webURL)))
+	}
 }
 
 func (p *Printer) PrintTable(headers []string, rows [][]string) {
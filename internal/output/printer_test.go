@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"slices"
 	"strings"
 	"sync"
@@ -226,3 +227,10 @@ func newForbidden(body string) error {
 		Body:       io.NopCloser(strings.NewReader(body)),
 	})
 }
+
+func TestIsStdout(t *testing.T) {
+	assert.True(t, IsStdout(os.Stdout))
+	assert.True(t, IsStdout(DefaultPrinter().Out))
+	assert.False(t, IsStdout(DefaultPrinter().ErrOut))
+	assert.False(t, IsStdout(&bytes.Buffer{}))
+}
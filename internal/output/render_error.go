@@ -51,8 +51,14 @@ func tipFor(err error) string {
 		}
 		return permissionTip(pe)
 	case api.CatReadOnly:
+		if ue == api.ErrGuestReadOnly {
+			return "Log in with a token via 'teamcity auth login' to make changes"
+		}
 		return "Unset the TEAMCITY_RO environment variable to allow write operations"
 	case api.CatNotFound:
+		if nf, ok := errors.AsType[*api.NotFoundError](ue); ok && nf.Resource == "run" && isNumber(nf.ID) {
+			return fmt.Sprintf("If %s is a build number rather than an ID, use '#%s --job <id>'", nf.ID, nf.ID)
+		}
 		if nf, ok := errors.AsType[*api.NotFoundError](ue); ok && hasListCommand(nf.Resource) {
 			return fmt.Sprintf("Run 'teamcity %s list' to see available %ss", nf.Resource, nf.Resource)
 		}
@@ -92,6 +98,11 @@ func hasListCommand(resource string) bool {
 	return false
 }
 
+// isNumber reports whether s is a non-empty string of digits, as both run IDs and build numbers often are.
+func isNumber(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
 // notFoundTip suggests the matching 'teamcity X list' command for a 404 message.
 func notFoundTip(message string) string {
 	msg := strings.ToLower(message)
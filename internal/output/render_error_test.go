@@ -207,6 +207,15 @@ func TestClassifyError_NotFoundResource(t *testing.T) {
 	}
 }
 
+// TestClassifyError_NotFoundRunNumber pins the build-number tip for a numeric run ID the server does not know.
+func TestClassifyError_NotFoundRunNumber(t *testing.T) {
+	t.Parallel()
+
+	nf := notFoundErr(t, "No build found by locator 'id:482'")
+	_, _, tip := output.ClassifyError(nf)
+	assert.Equal(t, "If 482 is a build number rather than an ID, use '#482 --job <id>'", tip)
+}
+
 // TestClassifyError_InputErrors pins the prefix/substring list that classifies cobra error strings as validation.
 func TestClassifyError_InputErrors(t *testing.T) {
 	t.Parallel()
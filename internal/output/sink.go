@@ -0,0 +1,52 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"syscall"
+)
+
+// ErrSinkClosed is returned by a SinkWriter's writes once its reader has gone away.
+var ErrSinkClosed = fmt.Errorf("output closed: %w", io.ErrClosedPipe)
+
+// IsClosedSink reports whether err means the reader of the output went away, such as a pager quit with q or
+// `| head` having read enough. That is how paged or piped output normally ends, so it is not an error.
+func IsClosedSink(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed)
+}
+
+// SinkWriter passes writes through to w until the reader goes away; it then cancels the context feeding it,
+// so background fetches stop, and fails every later write with ErrSinkClosed without touching w.
+type SinkWriter struct {
+	w      io.Writer
+	cancel context.CancelFunc
+	closed atomic.Bool
+}
+
+// NewSinkWriter wraps w; cancel is called once, on the first write that finds the reader gone.
+func NewSinkWriter(w io.Writer, cancel context.CancelFunc) *SinkWriter {
+	return &SinkWriter{w: w, cancel: cancel}
+}
+
+func (s *SinkWriter) Write(b []byte) (int, error) {
+	if s.closed.Load() {
+		return 0, ErrSinkClosed
+	}
+	n, err := s.w.Write(b)
+	if err != nil && IsClosedSink(err) {
+		if s.closed.CompareAndSwap(false, true) {
+			s.cancel()
+		}
+		return n, ErrSinkClosed
+	}
+	return n, err
+}
+
+// Closed reports whether the reader has gone away.
+func (s *SinkWriter) Closed() bool {
+	return s.closed.Load()
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closingWriter accepts n writes, then fails every write as a pipe whose reader has exited.
+type closingWriter struct {
+	n      int
+	writes int
+}
+
+func (c *closingWriter) Write(b []byte) (int, error) {
+	c.writes++
+	if c.writes > c.n {
+		return 0, &os.PathError{Op: "write", Path: "/dev/stdout", Err: syscall.EPIPE}
+	}
+	return len(b), nil
+}
+
+func TestIsClosedSink(T *testing.T) {
+	assert.True(T, IsClosedSink(&os.PathError{Op: "write", Path: "/dev/stdout", Err: syscall.EPIPE}))
+	assert.True(T, IsClosedSink(fmt.Errorf("copy: %w", io.ErrClosedPipe)))
+	assert.True(T, IsClosedSink(os.ErrClosed))
+	assert.True(T, IsClosedSink(ErrSinkClosed))
+	assert.False(T, IsClosedSink(nil))
+	assert.False(T, IsClosedSink(errors.New("disk full")))
+}
+
+func TestSinkWriter(T *testing.T) {
+	under := &closingWriter{n: 2}
+	canceled := 0
+	sink := NewSinkWriter(under, func() { canceled++ })
+
+	for range 2 {
+		_, err := fmt.Fprintln(sink, "line")
+		require.NoError(T, err)
+	}
+	assert.False(T, sink.Closed())
+
+	_, err := fmt.Fprintln(sink, "line")
+	assert.ErrorIs(T, err, ErrSinkClosed)
+	assert.True(T, sink.Closed())
+
+	_, err = fmt.Fprintln(sink, "line")
+	assert.ErrorIs(T, err, ErrSinkClosed)
+	assert.Equal(T, 3, under.writes, "writes after the reader left never reach it")
+	assert.Equal(T, 1, canceled)
+}
+
+func TestSinkWriterOtherErrors(T *testing.T) {
+	sink := NewSinkWriter(errWriter{errors.New("disk full")}, func() { T.Fatal("only a closed reader cancels") })
+	_, err := sink.Write([]byte("x"))
+	assert.EqualError(T, err, "disk full")
+	assert.False(T, sink.Closed())
+}
+
+type errWriter struct{ err error }
+
+func (e errWriter) Write([]byte) (int, error) { return 0, e.err }
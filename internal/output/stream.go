@@ -0,0 +1,200 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TableStream prints a table a page at a time, so the first rows show while later pages are still loading. The
+// first page fixes the column widths exactly as PrintTable would size it alone; later rows are truncated (flex
+// columns) or padded to match.
+type TableStream struct {
+	p        *Printer
+	headers  []string
+	flexCols []int
+	plain    bool
+	noHeader bool
+	widths   []int
+	rows     int
+}
+
+// NewTableStream starts a table; plain and noHeader select the tab-separated layout of PrintPlainTable.
+func (p *Printer) NewTableStream(headers []string, flexCols []int, plain, noHeader bool) *TableStream {
+	return &TableStream{p: p, headers: headers, flexCols: flexCols, plain: plain, noHeader: noHeader}
+}
+
+// Write prints rows, preceded by the header on the first call that has any.
+func (t *TableStream) Write(rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	defer func() { t.rows += len(rows) }()
+
+	if t.widths == nil {
+		if t.plain {
+			stripANSI(rows)
+			t.widths = plainColumnWidths(t.headers, rows)
+			t.p.write(t.p.Out, renderPlainTable(t.headers, rows, t.noHeader))
+			return
+		}
+		AutoSizeColumns(t.headers, rows, 2, t.flexCols...)
+		t.widths = measureColumnWidths(t.headers, rows)
+		t.p.write(t.p.Out, renderTable(t.headers, rows)+"\n")
+		return
+	}
+
+	var b strings.Builder
+	if t.plain {
+		stripANSI(rows)
+		for _, row := range rows {
+			b.WriteString(padPlainRow(row, t.widths))
+			b.WriteByte('\n')
+		}
+	} else {
+		for _, row := range rows {
+			b.WriteString(t.alignRow(row))
+			b.WriteByte('\n')
+		}
+	}
+	t.p.write(t.p.Out, b.String())
+}
+
+// alignRow lays a row out on the first page's columns, two spaces apart like renderTable.
+func (t *TableStream) alignRow(row []string) string {
+	var b strings.Builder
+	for i, cell := range row {
+		if i >= len(t.widths) {
+			break
+		}
+		if slices.Contains(t.flexCols, i) {
+			cell = Truncate(cell, t.widths[i])
+		}
+		b.WriteString(cell)
+		if i < len(t.widths)-1 {
+			b.WriteString(strings.Repeat(" ", max(t.widths[i]-lipgloss.Width(cell), 0)+2))
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Rows returns how many rows have been written.
+func (t *TableStream) Rows() int {
+	return t.rows
+}
+
+// JSONListWriter streams a list envelope such as {"buildType": [...], "count": N} a page at a time, indented like
+// PrintJSON. The count comes last, after the array, as only then is it known.
+type JSONListWriter[T any] struct {
+	p       *Printer
+	key     string
+	count   int
+	started bool
+}
+
+// NewJSONListWriter starts an envelope whose array field is key.
+func NewJSONListWriter[T any](p *Printer, key string) *JSONListWriter[T] {
+	return &JSONListWriter[T]{p: p, key: key}
+}
+
+// Write appends items to the array.
+func (w *JSONListWriter[T]) Write(items []T) error {
+	var b strings.Builder
+	w.open(&b)
+	for _, item := range items {
+		data, err := json.MarshalIndent(item, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		if w.count > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n    ")
+		b.Write(data)
+		w.count++
+	}
+	w.p.write(w.p.Out, b.String())
+	return nil
+}
+
+// Close ends the array and writes the count.
+func (w *JSONListWriter[T]) Close() {
+	var b strings.Builder
+	w.open(&b)
+	if w.count > 0 {
+		b.WriteString("\n  ")
+	}
+	fmt.Fprintf(&b, "],\n  \"count\": %d\n}\n", w.count)
+	w.p.write(w.p.Out, b.String())
+}
+
+// Abort ends an envelope cut short by an error, so what was written is still valid JSON. It writes nothing when
+// no page has arrived.
+func (w *JSONListWriter[T]) Abort() {
+	if w.started {
+		w.Close()
+	}
+}
+
+func (w *JSONListWriter[T]) open(b *strings.Builder) {
+	if !w.started {
+		fmt.Fprintf(b, "{\n  %q: [", w.key)
+		w.started = true
+	}
+}
+
+// WriteJSONString writes everything r yields to w as one quoted JSON string, escaped exactly as PrintJSON would
+// escape it, a chunk at a time so a long text such as a build log is never held in memory whole.
+func WriteJSONString(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, `"`); err != nil {
+		return err
+	}
+	buf := make([]byte, 32<<10)
+	carry := 0
+	for {
+		n, err := r.Read(buf[carry:])
+		n += carry
+		end := n
+		if err == nil {
+			// Hold back a rune cut off by the read, or it would be escaped as invalid UTF-8.
+			end = completeRunes(buf[:n])
+		}
+		if end > 0 {
+			escaped, merr := json.Marshal(string(buf[:end]))
+			if merr != nil {
+				return merr
+			}
+			if _, werr := w.Write(escaped[1 : len(escaped)-1]); werr != nil {
+				return werr
+			}
+		}
+		carry = copy(buf, buf[end:n])
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
+
+// completeRunes returns the length of b without an incomplete UTF-8 sequence at its end.
+func completeRunes(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableStream(T *testing.T) {
+	T.Run("later pages align to the first", func(t *testing.T) {
+		var out bytes.Buffer
+		p := &Printer{Out: &out}
+		ts := p.NewTableStream([]string{"ID", "NAME", "STATUS"}, []int{0, 1}, false, false)
+
+		ts.Write([][]string{{"A_Build", "Build", "Active"}, {"A_Test", "Test", "Paused"}})
+		first := out.String()
+		ts.Write([][]string{{"B_Deploy_To_Production_Cluster", "Deploy", "Active"}})
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.True(t, strings.HasPrefix(out.String(), first), "the first page is printed as is")
+		col := strings.Index(lines[0], "NAME")
+		for _, line := range lines[1:] {
+			assert.Equal(t, "  ", line[col-2:col], "line %q", line)
+			assert.NotEqual(t, byte(' '), line[col], "line %q", line)
+		}
+		assert.Contains(t, lines[3], "B_De...", "overlong flex cells are truncated to the column")
+		assert.Equal(t, 3, ts.Rows())
+	})
+
+	T.Run("plain", func(t *testing.T) {
+		var out bytes.Buffer
+		p := &Printer{Out: &out}
+		ts := p.NewTableStream([]string{"ID", "NAME"}, nil, true, true)
+
+		ts.Write([][]string{{"A", Green("one")}})
+		ts.Write(nil)
+		ts.Write([][]string{{"LONGER", "two"}})
+
+		assert.Equal(t, "A \tone \nLONGER\ttwo \n", out.String())
+		assert.Equal(t, 2, ts.Rows())
+	})
+}
+
+func TestJSONListWriter(T *testing.T) {
+	type item struct {
+		ID string `json:"id"`
+	}
+
+	T.Run("pages", func(t *testing.T) {
+		var out bytes.Buffer
+		w := NewJSONListWriter[item](&Printer{Out: &out}, "item")
+		require.NoError(t, w.Write([]item{{"a"}, {"b"}}))
+		require.NoError(t, w.Write([]item{{"c"}}))
+		w.Close()
+
+		var got struct {
+			Count int    `json:"count"`
+			Items []item `json:"item"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, 3, got.Count)
+		assert.Equal(t, []item{{"a"}, {"b"}, {"c"}}, got.Items)
+		assert.Contains(t, out.String(), "\n    {\n      \"id\": \"a\"\n    },\n")
+	})
+
+	T.Run("empty", func(t *testing.T) {
+		var out bytes.Buffer
+		NewJSONListWriter[item](&Printer{Out: &out}, "item").Close()
+		assert.Equal(t, "{\n  \"item\": [],\n  \"count\": 0\n}\n", out.String())
+	})
+
+	T.Run("abort", func(t *testing.T) {
+		var out bytes.Buffer
+		NewJSONListWriter[item](&Printer{Out: &out}, "item").Abort()
+		assert.Empty(t, out.String(), "nothing to close before the first page")
+
+		w := NewJSONListWriter[item](&Printer{Out: &out}, "item")
+		require.NoError(t, w.Write([]item{{"a"}}))
+		w.Abort()
+		assert.True(t, json.Valid(out.Bytes()))
+	})
+}
+
+func TestWriteJSONString(T *testing.T) {
+	for _, text := range []string{
+		"",
+		"plain",
+		"line 1\n\"quoted\" <tag> & tab\there\r\n",
+		"Größe → 日本語 🚀 done",
+		"bad \xff byte, cut \xe2\x86 rune, then \xe2",
+	} {
+		want, err := json.Marshal(text)
+		require.NoError(T, err)
+
+		var whole, bytewise bytes.Buffer
+		require.NoError(T, WriteJSONString(&whole, strings.NewReader(text)))
+		require.NoError(T, WriteJSONString(&bytewise, iotest.OneByteReader(strings.NewReader(text))))
+		assert.Equal(T, string(want), whole.String())
+		assert.Equal(T, string(want), bytewise.String(), "runes split across reads are escaped whole")
+	}
+
+	T.Run("read error", func(t *testing.T) {
+		var out bytes.Buffer
+		err := WriteJSONString(&out, iotest.TimeoutReader(iotest.HalfReader(strings.NewReader("abcdef"))))
+		assert.ErrorIs(t, err, iotest.ErrTimeout)
+	})
+}
@@ -8,6 +8,7 @@ type Symbols struct {
 	Cross     string // failure
 	Neutral   string // neutral / unknown
 	Skip      string // canceled / skipped
+	Paused    string // paused job
 	Arrow     string // transition / "then"
 	ArrowLeft string
 	Bullet    string // list item
@@ -25,7 +26,7 @@ type Symbols struct {
 }
 
 var unicodeSymbols = Symbols{
-	Check: "✓", Cross: "✗", Neutral: "○", Skip: "⊘",
+	Check: "✓", Cross: "✗", Neutral: "○", Skip: "⊘", Paused: "⏸",
 	Arrow: "→", ArrowLeft: "←", Bullet: "•", Sep: "·",
 	Pipeline: "⬡", Recycle: "⟳", Pinned: "📌",
 	DeltaUp: "▲", DeltaDown: "▼", Ellipsis: "…",
@@ -33,7 +34,7 @@ var unicodeSymbols = Symbols{
 }
 
 var asciiSymbols = Symbols{
-	Check: "+", Cross: "x", Neutral: "-", Skip: "/",
+	Check: "+", Cross: "x", Neutral: "-", Skip: "/", Paused: "||",
 	Arrow: "->", ArrowLeft: "<-", Bullet: "*", Sep: "|",
 	Pipeline: "*", Recycle: "~", Pinned: "*",
 	DeltaUp: "^", DeltaDown: "v", Ellipsis: "...",
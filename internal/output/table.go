@@ -12,8 +12,8 @@ import (
 // renderTable renders a formatted table string with proper Unicode/ANSI handling.
 func renderTable(headers []string, rows [][]string) string {
 	noBorder := lipgloss.Border{}
-	headerStyle := lipgloss.NewStyle().Faint(true)
-	cellStyle := lipgloss.NewStyle()
+	headerStyle := ansiRenderer.NewStyle().Faint(!NoColor)
+	cellStyle := ansiRenderer.NewStyle()
 
 	t := table.New().
 		Headers(headers...).
@@ -38,12 +38,31 @@ func renderTable(headers []string, rows [][]string) string {
 
 // renderPlainTable renders tab-separated output for scripting (works with cut -f, awk).
 func renderPlainTable(headers []string, rows [][]string, noHeader bool) string {
+	stripANSI(rows)
+	colWidths := plainColumnWidths(headers, rows)
+
+	var b strings.Builder
+	if !noHeader {
+		b.WriteString(padPlainRow(headers, colWidths))
+		b.WriteByte('\n')
+	}
+	for _, row := range rows {
+		b.WriteString(padPlainRow(row, colWidths))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func stripANSI(rows [][]string) {
 	for i, row := range rows {
 		for j, cell := range row {
 			rows[i][j] = ansi.Strip(cell)
 		}
 	}
+}
 
+// plainColumnWidths returns the max display width of each header's column.
+func plainColumnWidths(headers []string, rows [][]string) []int {
 	colWidths := make([]int, len(headers))
 	for i, h := range headers {
 		colWidths[i] = runewidth.StringWidth(h)
@@ -57,29 +76,20 @@ func renderPlainTable(headers []string, rows [][]string, noHeader bool) string {
 			}
 		}
 	}
+	return colWidths
+}
 
-	padRow := func(cells []string) string {
-		padded := make([]string, len(cells))
-		for i, cell := range cells {
-			if i < len(colWidths) {
-				padded[i] = runewidth.FillRight(cell, colWidths[i])
-			} else {
-				padded[i] = cell
-			}
+// padPlainRow pads each cell to its column width and joins them with tabs; cells wider than the column are kept whole.
+func padPlainRow(cells []string, colWidths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		if i < len(colWidths) {
+			padded[i] = runewidth.FillRight(cell, colWidths[i])
+		} else {
+			padded[i] = cell
 		}
-		return strings.Join(padded, "\t")
-	}
-
-	var b strings.Builder
-	if !noHeader {
-		b.WriteString(padRow(headers))
-		b.WriteByte('\n')
-	}
-	for _, row := range rows {
-		b.WriteString(padRow(row))
-		b.WriteByte('\n')
 	}
-	return b.String()
+	return strings.Join(padded, "\t")
 }
 
 // AutoSizeColumns truncates flexible columns in-place to fit the terminal width.
@@ -269,3 +269,16 @@ func TestPrintPlainTable(T *testing.T) {
 		})
 	}
 }
+
+func TestTableColor(T *testing.T) {
+	old := NoColor
+	T.Cleanup(func() { NoColor = old })
+	headers, rows := []string{"ID", "STATUS"}, func() [][]string { return [][]string{{"1", Green("ok")}} }
+
+	NoColor = false
+	assert.Contains(T, renderTable(headers, rows()), "\x1b[2mID", "faint header when color is on")
+	assert.NotContains(T, renderPlainTable(headers, rows(), false), "\x1b[", "plain output never carries escapes")
+
+	NoColor = true
+	assert.NotContains(T, renderTable(headers, rows()), "\x1b[")
+}
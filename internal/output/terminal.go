@@ -48,30 +48,52 @@ func TerminalWidth() int {
 	return w
 }
 
+// lookPathFn resolves a binary on PATH; tests override to simulate installed pagers.
+var lookPathFn = exec.LookPath
+
 // pagerCmdFn creates the pager command. Tests can override this.
 var pagerCmdFn = func() (*exec.Cmd, error) {
-	if pager := os.Getenv("PAGER"); pager != "" {
-		parts := strings.Fields(pager)
+	return resolvePager(os.Getenv("PAGER"), lookPathFn, fallbackPagers)
+}
+
+// resolvePager picks the pager: $PAGER when set, else less, else the first fallback found.
+// An error means no pager is usable and WithPager writes output directly.
+func resolvePager(env string, lookPath func(string) (string, error), fallbacks []string) (*exec.Cmd, error) {
+	if env != "" {
+		parts := strings.Fields(env)
 		if len(parts) == 0 {
 			return nil, errors.New("PAGER is set but empty")
 		}
-		bin, err := exec.LookPath(parts[0])
+		bin, err := lookPath(parts[0])
 		if err != nil {
 			return nil, err
 		}
 		return exec.Command(bin, parts[1:]...), nil
 	}
-	lessPath, err := exec.LookPath("less")
-	if err != nil {
-		return nil, err
+	lessPath, err := lookPath("less")
+	if err == nil {
+		return exec.Command(lessPath, "-FIRX", "--mouse", "--incsearch"), nil
+	}
+	for _, name := range fallbacks {
+		if bin, fbErr := lookPath(name); fbErr == nil {
+			return exec.Command(bin), nil
+		}
 	}
-	return exec.Command(lessPath, "-FIRX", "--mouse", "--incsearch"), nil
+	return nil, err
 }
 
 // WithPager pipes output through less if it exceeds terminal height.
-// The out writer is used as a fallback when paging is not available.
+// The out writer is used as a fallback when paging is not available, and directly when stdout is not a terminal;
+// fn should stop at its first failed write, which is how an early-quitting reader shows up.
 func WithPager(out io.Writer, fn func(w io.Writer)) {
 	StopSpinner() // paging writes straight to os.Stdout, bypassing the stopWriter
+	if !IsTerminal() {
+		// Stream to pipes rather than buffer: when the reader stops early (| head, an external pager),
+		// fn's next write fails and it can stop instead of producing output nobody reads.
+		fn(out)
+		return
+	}
+
 	var buf bytes.Buffer
 	fn(&buf)
 
@@ -79,7 +101,7 @@ func WithPager(out io.Writer, fn func(w io.Writer)) {
 	lineCount := bytes.Count(buf.Bytes(), []byte{'\n'})
 	pager, err := pagerCmdFn()
 
-	if !IsTerminal() || err != nil || lineCount <= height-2 {
+	if err != nil || lineCount <= height-2 {
 		_, _ = out.Write(buf.Bytes())
 		return
 	}
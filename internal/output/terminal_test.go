@@ -131,6 +131,24 @@ func TestWithPagerNonTerminal(T *testing.T) {
 	assert.Contains(T, buf.String(), "hello pager")
 }
 
+// TestWithPagerNonTerminalStreams checks that piped output is written as it is produced, so a reader that quits early stops fn.
+func TestWithPagerNonTerminalStreams(T *testing.T) {
+	overrideTerminal(T, false, 120, 40, nil)
+
+	out := &closingWriter{n: 3}
+	produced := 0
+	WithPager(out, func(w io.Writer) {
+		for range 1000 {
+			if _, err := fmt.Fprintln(w, "line"); err != nil {
+				return
+			}
+			produced++
+		}
+	})
+	assert.Equal(T, 3, produced)
+	assert.Equal(T, 4, out.writes)
+}
+
 func TestWithPagerFallbackShortContent(T *testing.T) {
 	overrideTerminal(T, true, 80, 50, nil)
 
@@ -212,3 +230,74 @@ func TestWithPagerEarlyQuit(T *testing.T) {
 	// No fallback: buf stays empty because the pager exited 0.
 	assert.Empty(T, buf.String())
 }
+
+func TestResolvePager(T *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/bin/" + name, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+
+	tests := []struct {
+		name      string
+		env       string
+		installed []string
+		fallbacks []string
+		wantArgs  []string
+		wantErr   bool
+	}{
+		{name: "PAGER wins", env: "bat --plain", installed: []string{"bat", "less"}, wantArgs: []string{"/bin/bat", "--plain"}},
+		{name: "PAGER missing", env: "bat", installed: []string{"less"}, wantErr: true},
+		{name: "PAGER blank", env: "   ", installed: []string{"less"}, wantErr: true},
+		{name: "less", installed: []string{"less", "more"}, fallbacks: []string{"more"}, wantArgs: []string{"/bin/less", "-FIRX", "--mouse", "--incsearch"}},
+		{name: "more fallback", installed: []string{"more"}, fallbacks: []string{"more"}, wantArgs: []string{"/bin/more"}},
+		{name: "no fallbacks", installed: []string{"more"}, wantErr: true},
+		{name: "nothing installed", fallbacks: []string{"more"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			cmd, err := resolvePager(tc.env, installed(tc.installed...), tc.fallbacks)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantArgs, cmd.Args)
+		})
+	}
+}
+
+func TestWithPagerNoPagerWritesDirectly(T *testing.T) {
+	overrideTerminal(T, true, 80, 5, nil)
+
+	oldPager := pagerCmdFn
+	T.Cleanup(func() { pagerCmdFn = oldPager })
+	pagerCmdFn = func() (*exec.Cmd, error) {
+		return resolvePager("", func(string) (string, error) { return "", exec.ErrNotFound }, []string{"more"})
+	}
+
+	content := strings.Repeat("line\n", 20)
+	var buf bytes.Buffer
+	WithPager(&buf, func(w io.Writer) {
+		fmt.Fprint(w, content)
+	})
+	assert.Equal(T, content, buf.String())
+}
+
+func TestEnableVirtualTerminal(T *testing.T) {
+	oldFn, oldVT := enableVTFn, VT
+	T.Cleanup(func() { enableVTFn, VT = oldFn, oldVT })
+
+	enableVTFn = func() bool { return false }
+	assert.False(T, EnableVirtualTerminal())
+	assert.False(T, VT)
+
+	enableVTFn = func() bool { return true }
+	assert.True(T, EnableVirtualTerminal())
+	assert.True(T, VT)
+}
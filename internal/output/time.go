@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/JetBrains/teamcity-cli/internal/timeref"
 	"github.com/dustin/go-humanize"
 )
 
@@ -17,13 +18,16 @@ var shortTimeMagnitudes = []humanize.RelTimeMagnitude{
 	{D: 7 * 24 * time.Hour, Format: "%dd ago", DivBy: 24 * time.Hour},
 }
 
-// RelativeTime formats a time as relative to now
+// RelativeTime formats a time as relative to now, on the server's clock when the local one is off (see timeref).
 func RelativeTime(t time.Time) string {
+	return relativeTime(t, timeref.Now())
+}
+
+func relativeTime(t, now time.Time) string {
 	if t.IsZero() {
 		return "-"
 	}
 
-	now := time.Now()
 	if now.Sub(t) < 0 {
 		return "now"
 	}
@@ -81,6 +81,17 @@ func TestRelativeTime(T *testing.T) {
 	})
 }
 
+func TestRelativeTimeAgainstReference(t *testing.T) {
+	t.Parallel()
+	// A run that finished 10 minutes ago on a server whose clock is 5 minutes behind the local one.
+	serverNow := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	finished := serverNow.Add(-10 * time.Minute)
+
+	assert.Equal(t, "10m ago", relativeTime(finished, serverNow))
+	assert.Equal(t, "15m ago", relativeTime(finished, serverNow.Add(5*time.Minute)))
+	assert.Equal(t, "now", relativeTime(finished, serverNow.Add(-20*time.Minute)))
+}
+
 func TestFormatDuration(T *testing.T) {
 	T.Parallel()
 	tests := []struct {
@@ -59,7 +59,7 @@ func TipEnableReadOnly() string {
 
 // TipSwitchDefaultServer returns a tip pointing at the command that switches the default server.
 func TipSwitchDefaultServer() string {
-	return "To switch the default server, run " + Cyan("teamcity config set default_server <url>")
+	return "To switch the default server, run " + Cyan("teamcity auth switch") + "; to use another for one command, add " + Cyan("--server <url>")
 }
 
 // TipResumeLogFor returns the resume-hint for an interrupted `teamcity run log` follow session.
@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/JetBrains/teamcity-cli/internal/config"
+)
+
+// Environment variables set for a plugin. The token is passed only when the user opts in with
+// EnvPassToken, since a plugin is any executable that happens to be on PATH.
+const (
+	EnvPassToken   = "TC_PLUGIN_PASS_TOKEN"
+	EnvServerURL   = "TC_PLUGIN_SERVER_URL"
+	EnvTokenSource = "TC_PLUGIN_TOKEN_SOURCE"
+	EnvToken       = "TC_PLUGIN_TOKEN"
+	EnvConfigPath  = "TC_PLUGIN_CONFIG"
+	EnvExecutable  = "TC_PLUGIN_CLI"
+)
+
+// Env returns the variables that tell a plugin which server the CLI would talk to and how it would
+// authenticate: the server URL, where the token comes from (env, command, keyring, config, guest, build, or none),
+// the config file, and the CLI executable so the plugin can call back into it.
+func Env() []string {
+	serverURL := config.ResolveServerURL()
+	token, source, _ := config.GetTokenWithSource()
+	switch {
+	case config.IsGuestAuth():
+		token, source = "", "guest"
+	case serverURL == "" || token == "":
+		token, source = "", "none"
+		if _, ok := config.GetBuildAuth(); ok {
+			source = "build"
+		}
+	}
+
+	env := []string{
+		EnvServerURL + "=" + serverURL,
+		EnvTokenSource + "=" + source,
+		EnvConfigPath + "=" + config.ConfigPath(),
+	}
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, EnvExecutable+"="+exe)
+	}
+	if token != "" && os.Getenv(EnvPassToken) == "1" {
+		env = append(env, EnvToken+"="+token)
+	}
+	return env
+}
+
+// Run executes p with args and the CLI's environment plus env, on the given streams, and returns its exit
+// status. The error is set only when p could not be started. A plugin killed by a signal exits 1.
+func Run(p Plugin, args, env []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	//nolint:gosec // plugins are executables the user put on PATH, run by name as git and kubectl do
+	c := exec.Command(p.Path, args...)
+	c.Env = append(os.Environ(), env...)
+	c.Stdin = stdin
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	err := c.Run()
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+		if code := exitErr.ExitCode(); code > 0 {
+			return code, nil
+		}
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to run plugin %s: %w", p.Name, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,149 @@
+// Package plugin finds and runs external subcommands. As with git and kubectl, an executable named
+// tc-<name> on PATH becomes the command "teamcity <name>" unless a built-in command or an alias
+// already has that name.
+package plugin
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Prefix starts the file name of every plugin executable.
+const Prefix = "tc-"
+
+// Plugin is an executable found on PATH. Shadowed lists the later PATH entries with the same name, which
+// are never run.
+type Plugin struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// finder resolves plugin executables the way the platform's shell does: on Windows a file is a command
+// when its extension is listed in PATHEXT, elsewhere when it has an execute bit.
+type finder struct {
+	windows bool
+	pathExt []string
+}
+
+func newFinder() finder {
+	if runtime.GOOS != "windows" {
+		return finder{}
+	}
+	return finder{windows: true, pathExt: parsePathExt(os.Getenv("PATHEXT"))}
+}
+
+// parsePathExt splits PATHEXT into lower-case extensions, using the Windows default when it is unset.
+func parsePathExt(v string) []string {
+	if v == "" {
+		v = ".com;.exe;.bat;.cmd"
+	}
+	var exts []string
+	for e := range strings.SplitSeq(strings.ToLower(v), ";") {
+		if e = strings.TrimSpace(e); e != "" {
+			if !strings.HasPrefix(e, ".") {
+				e = "." + e
+			}
+			exts = append(exts, e)
+		}
+	}
+	return exts
+}
+
+// commandName returns the plugin name of file, or false when file is not a plugin executable.
+func (fd finder) commandName(file string, mode os.FileMode) (string, bool) {
+	if !mode.IsRegular() && mode&os.ModeSymlink == 0 {
+		return "", false
+	}
+	if !strings.HasPrefix(strings.ToLower(file), Prefix) {
+		return "", false
+	}
+	name := file[len(Prefix):]
+	if fd.windows {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext == "" || !slices.Contains(fd.pathExt, ext) {
+			return "", false
+		}
+		name = strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+	} else if mode&0o111 == 0 {
+		return "", false
+	}
+	if name == "" || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	return name, true
+}
+
+// discover lists the plugins in the directories of pathList, sorted by name. The first directory that has a
+// plugin wins, as it would in the shell. Empty and relative entries are skipped, as exec.LookPath refuses them
+// (exec.ErrDot): a tc-<name> file in whatever directory the CLI runs in must not become a command.
+func (fd finder) discover(pathList string) []Plugin {
+	byName := map[string]*Plugin{}
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(pathList) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := os.Stat(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue
+			}
+			name, ok := fd.commandName(e.Name(), info.Mode())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if p, ok := byName[name]; ok {
+				p.Shadowed = append(p.Shadowed, path)
+				continue
+			}
+			byName[name] = &Plugin{Name: name, Path: path}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(byName))
+	for _, p := range byName {
+		plugins = append(plugins, *p)
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return cmp.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// lookup returns the plugin named name from pathList, or false when there is none.
+func (fd finder) lookup(name, pathList string) (Plugin, bool) {
+	if fd.windows {
+		name = strings.ToLower(name)
+	}
+	for _, p := range fd.discover(pathList) {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// Discover lists the plugins on PATH, sorted by name.
+func Discover() []Plugin {
+	return newFinder().discover(os.Getenv("PATH"))
+}
+
+// Lookup finds the plugin for the command name on PATH.
+func Lookup(name string) (Plugin, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, false
+	}
+	return newFinder().lookup(name, os.Getenv("PATH"))
+}
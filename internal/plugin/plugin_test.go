@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/JetBrains/teamcity-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), mode))
+	return path
+}
+
+// writeFixture writes a plugin that prints its arguments and token variables, then exits with its first argument.
+func writeFixture(t *testing.T, dir, name string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		return writeFile(t, dir, Prefix+name+".cmd",
+			"@echo off\r\necho args:%*\r\necho source:%TC_PLUGIN_TOKEN_SOURCE% token:%TC_PLUGIN_TOKEN%\r\nexit /b %1\r\n", 0o755)
+	}
+	return writeFile(t, dir, Prefix+name,
+		"#!/bin/sh\necho \"args:$*\"\necho \"source:$TC_PLUGIN_TOKEN_SOURCE token:$TC_PLUGIN_TOKEN\"\nexit \"$1\"\n", 0o755)
+}
+
+func TestDiscoverUnix(T *testing.T) {
+	T.Parallel()
+	first, second := T.TempDir(), T.TempDir()
+	writeFile(T, first, "tc-deploy", "", 0o755)
+	writeFile(T, first, "tc-notes", "", 0o644)
+	writeFile(T, first, "tc-", "", 0o755)
+	writeFile(T, first, "kubectl-foo", "", 0o755)
+	shadowed := writeFile(T, second, "tc-deploy", "", 0o755)
+	writeFile(T, second, "tc-audit", "", 0o755)
+	require.NoError(T, os.Mkdir(filepath.Join(second, "tc-dir"), 0o755))
+
+	plugins := finder{}.discover(first + string(os.PathListSeparator) + second + string(os.PathListSeparator) + first)
+	require.Len(T, plugins, 2)
+	assert.Equal(T, "audit", plugins[0].Name)
+	assert.Equal(T, Plugin{Name: "deploy", Path: filepath.Join(first, "tc-deploy"), Shadowed: []string{shadowed}}, plugins[1])
+
+	p, ok := finder{}.lookup("deploy", first+string(os.PathListSeparator)+second)
+	require.True(T, ok)
+	assert.Equal(T, filepath.Join(first, "tc-deploy"), p.Path)
+	_, ok = finder{}.lookup("notes", first)
+	assert.False(T, ok, "a file without an execute bit is not a plugin")
+}
+
+func TestDiscoverWindows(T *testing.T) {
+	T.Parallel()
+	dir := T.TempDir()
+	writeFile(T, dir, "tc-deploy.exe", "", 0o644)
+	writeFile(T, dir, "tc-Audit.CMD", "", 0o644)
+	writeFile(T, dir, "tc-notes.txt", "", 0o644)
+	writeFile(T, dir, "tc-bare", "", 0o755)
+
+	windows := finder{windows: true, pathExt: parsePathExt(".COM;.EXE;.BAT;.CMD")}
+	plugins := windows.discover(dir)
+	require.Len(T, plugins, 2)
+	assert.Equal(T, "audit", plugins[0].Name)
+	assert.Equal(T, "deploy", plugins[1].Name)
+
+	p, ok := windows.lookup("AUDIT", dir)
+	require.True(T, ok, "Windows command names are case-insensitive")
+	assert.Equal(T, filepath.Join(dir, "tc-Audit.CMD"), p.Path)
+
+	assert.Equal(T, []string{".com", ".exe", ".bat", ".cmd"}, parsePathExt(""))
+	assert.Equal(T, []string{".exe", ".ps1"}, parsePathExt("EXE; .ps1;"))
+}
+
+func TestLookupRejectsPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "deploy")
+	t.Setenv("PATH", dir)
+
+	_, ok := Lookup("deploy")
+	assert.True(t, ok)
+	_, ok = Lookup("../deploy")
+	assert.False(t, ok)
+	_, ok = Lookup("")
+	assert.False(t, ok)
+}
+
+func TestLookupSkipsCurrentDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "foo")
+	t.Chdir(dir)
+	t.Setenv("PATH", string(os.PathListSeparator)+"."+string(os.PathListSeparator)+"bin")
+
+	_, ok := Lookup("foo")
+	assert.False(t, ok, "an empty or relative PATH entry does not find plugins in the working directory")
+	assert.Empty(t, Discover())
+}
+
+func TestRunPreservesExitCodeAndStreams(t *testing.T) {
+	dir := t.TempDir()
+	p := Plugin{Name: "deploy", Path: writeFixture(t, dir, "deploy")}
+
+	var out, errOut bytes.Buffer
+	code, err := Run(p, []string{"0", "--to", "prod"}, nil, strings.NewReader(""), &out, &errOut)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out.String(), "args:0 --to prod")
+
+	out.Reset()
+	code, err = Run(p, []string{"3"}, nil, strings.NewReader(""), &out, &errOut)
+	require.NoError(t, err)
+	assert.Equal(t, 3, code)
+
+	_, err = Run(Plugin{Name: "gone", Path: filepath.Join(dir, "tc-gone")}, nil, nil, nil, &out, &errOut)
+	assert.ErrorContains(t, err, "failed to run plugin gone")
+}
+
+func TestEnvPassesTokenOnlyOnRequest(t *testing.T) {
+	t.Setenv("TEAMCITY_URL", "https://tc.example.com")
+	t.Setenv("TEAMCITY_TOKEN", "secret-token")
+	t.Setenv("TEAMCITY_GUEST", "")
+	t.Setenv(EnvPassToken, "")
+	config.SetConfigPathForTest(filepath.Join(t.TempDir(), "config.yml"))
+	config.ResetForTest()
+
+	env := Env()
+	assert.Contains(t, env, EnvServerURL+"=https://tc.example.com")
+	assert.Contains(t, env, EnvTokenSource+"=env")
+	assert.Contains(t, env, EnvConfigPath+"="+config.ConfigPath())
+	assert.NotContains(t, strings.Join(env, "\n"), "secret-token")
+
+	t.Setenv(EnvPassToken, "1")
+	assert.Contains(t, Env(), EnvToken+"=secret-token")
+
+	var out bytes.Buffer
+	p := Plugin{Name: "deploy", Path: writeFixture(t, t.TempDir(), "deploy")}
+	_, err := Run(p, []string{"0"}, Env(), strings.NewReader(""), &out, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "source:env token:secret-token")
+}
@@ -3,6 +3,7 @@ package terminal
 import (
 	"cmp"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -50,6 +51,9 @@ type Client struct {
 	httpClient   *http.Client
 	debugf       func(string, ...any)
 	extraHeaders map[string]string
+	userAgent    string
+	cliContext   string
+	tlsConfig    *tls.Config
 }
 
 func NewClient(baseURL, username, token string, debugf func(string, ...any)) *Client {
@@ -67,6 +71,51 @@ func NewClient(baseURL, username, token string, debugf func(string, ...any)) *Cl
 	}
 }
 
+// Identify sets the User-Agent and audit context label sent with the terminal's requests, matching the API client's.
+func (c *Client) Identify(userAgent, cliContext string) *Client {
+	c.userAgent, c.cliContext = userAgent, cliContext
+	return c
+}
+
+// WithTLS verifies the server's certificate as o says, for the HTTP requests and the WebSocket connection alike.
+// It replaces the transport, so call it before WithRoundTripper.
+func (c *Client) WithTLS(o api.TLSOptions) (*Client, error) {
+	cfg, err := o.Config()
+	if err != nil || cfg == nil {
+		return c, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.TLSClientConfig = cfg
+	c.httpClient.Transport = t
+	c.tlsConfig = cfg
+	return c, nil
+}
+
+// WithRoundTripper routes the terminal's HTTP requests through wrap(current transport), as api.WithRoundTripper
+// does for the API client. The WebSocket connection itself is not affected.
+func (c *Client) WithRoundTripper(wrap func(http.RoundTripper) http.RoundTripper) *Client {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.httpClient.Transport = wrap(base)
+	return c
+}
+
+// setHeaders applies the identity headers and TEAMCITY_HEADER_* extras to h.
+func (c *Client) setHeaders(h http.Header) {
+	if c.userAgent != "" {
+		h.Set("User-Agent", c.userAgent)
+	}
+	if c.cliContext != "" {
+		h.Set(api.HeaderCLIContext, c.cliContext)
+	}
+	for k, v := range c.extraHeaders {
+		h.Set(k, v)
+	}
+}
+
 func (c *Client) OpenSession(agentID int) (*Session, error) {
 	endpoint := fmt.Sprintf("%s/httpAuth/plugins/teamcity-agent-terminal/agentTerminal.html?id=%d", c.baseURL, agentID)
 
@@ -77,9 +126,7 @@ func (c *Client) OpenSession(agentID int) (*Session, error) {
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
-	for k, v := range c.extraHeaders {
-		req.Header.Set(k, v)
-	}
+	c.setHeaders(req.Header)
 
 	req.SetBasicAuth(cmp.Or(c.username, "token"), c.token)
 
@@ -132,9 +179,7 @@ func (c *Client) Connect(session *Session, cols, rows int) (*Conn, error) {
 
 	header := http.Header{}
 	header.Set("Origin", c.baseURL)
-	for k, v := range c.extraHeaders {
-		header.Set(k, v)
-	}
+	c.setHeaders(header)
 
 	var cookies []string
 	for _, cookie := range c.httpClient.Jar.Cookies(u) {
@@ -149,7 +194,9 @@ func (c *Client) Connect(session *Session, cols, rows int) (*Conn, error) {
 
 	c.debugf("WebSocket URL: %s://%s/app/agentTerminal/terminal/<redacted>?cols=%d&rows=%d", scheme, u.Host, cols, rows)
 
-	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = c.tlsConfig
+	conn, resp, err := dialer.Dial(wsURL, header)
 	if err != nil {
 		if resp != nil {
 			body, _ := io.ReadAll(resp.Body)
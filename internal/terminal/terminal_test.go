@@ -1,9 +1,13 @@
 package terminal
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/JetBrains/teamcity-cli/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewClient(t *testing.T) {
@@ -20,3 +24,22 @@ func TestNewClientEmptyUsername(t *testing.T) {
 	assert.Equal(t, "http://localhost:8111", c.baseURL)
 	assert.Empty(t, c.username)
 }
+
+func TestOpenSessionSendsIdentityHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"s","nodeId":"n"}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("TEAMCITY_HEADER_CF_ACCESS_CLIENT_ID", "abc.id")
+
+	c := NewClient(server.URL, "admin", "tok", func(string, ...any) {}).Identify("teamcity-cli/1.2.3 (linux; amd64)", "release-bot")
+	_, err := c.OpenSession(1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "teamcity-cli/1.2.3 (linux; amd64)", got.Get("User-Agent"))
+	assert.Equal(t, "release-bot", got.Get(api.HeaderCLIContext))
+	assert.Equal(t, "abc.id", got.Get("Cf-Access-Client-Id"))
+}
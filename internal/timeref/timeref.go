@@ -0,0 +1,102 @@
+// Package timeref is the reference "now" for relative times such as "3m ago".
+// It is the local clock until the server's clock is seen to differ from it by
+// more than SkewThreshold; from then on it follows the server's clock, so a
+// machine with a drifting clock doesn't show negative ages or "now" for old runs.
+package timeref
+
+import (
+	"sync"
+	"time"
+)
+
+// SkewThreshold is how far the local clock may drift from the server's before relative times follow the server.
+const SkewThreshold = 2 * time.Minute
+
+// Clock tracks the skew between a local clock and a server's.
+type Clock struct {
+	mu       sync.Mutex
+	local    func() time.Time
+	skew     time.Duration
+	observed bool
+	warned   bool
+	warn     func(skew time.Duration)
+}
+
+// New returns a Clock that reads local time from local.
+func New(local func() time.Time) *Clock {
+	return &Clock{local: local}
+}
+
+// Observe records the server's time as read when the local clock showed local.
+// Only the first observation counts, so the skew stays fixed for the rest of the invocation.
+func (c *Clock) Observe(server, local time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.observed {
+		return
+	}
+	c.skew, c.observed = server.Sub(local), true
+}
+
+// Skew returns the server's time minus the local time, and whether the server's clock has been observed.
+func (c *Clock) Skew() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skew, c.observed
+}
+
+// OnSkew sets the function called, once, the first time Now corrects for a skew beyond SkewThreshold.
+func (c *Clock) OnSkew(fn func(skew time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warn = fn
+}
+
+// Now returns the local time, moved onto the server's clock when the two differ by more than SkewThreshold.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	now := c.local()
+	if !c.observed || !Significant(c.skew) {
+		c.mu.Unlock()
+		return now
+	}
+	skew, warn := c.skew, c.warn
+	if c.warned {
+		warn = nil
+	}
+	c.warned = true
+	c.mu.Unlock()
+
+	if warn != nil {
+		warn(skew)
+	}
+	return now.Add(skew)
+}
+
+// Since returns the time elapsed since t on the reference clock.
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Significant reports whether skew is large enough for relative times to follow the server's clock.
+func Significant(skew time.Duration) bool {
+	return skew.Abs() > SkewThreshold
+}
+
+// Default is the process-wide clock; API clients made by the command factory feed it the server's time.
+var Default = New(time.Now)
+
+// Now returns Default.Now().
+func Now() time.Time { return Default.Now() }
+
+// Since returns Default.Since(t).
+func Since(t time.Time) time.Duration { return Default.Since(t) }
+
+// Observe calls Default.Observe.
+func Observe(server, local time.Time) { Default.Observe(server, local) }
+
+// Skew returns Default.Skew().
+func Skew() (time.Duration, bool) { return Default.Skew() }
+
+// OnSkew calls Default.OnSkew.
+func OnSkew(fn func(skew time.Duration)) { Default.OnSkew(fn) }
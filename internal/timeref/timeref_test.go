@@ -0,0 +1,108 @@
+package timeref
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeClock(now time.Time) *Clock {
+	return New(func() time.Time { return now })
+}
+
+func TestClockNow(T *testing.T) {
+	T.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		server time.Time
+		want   time.Time
+	}{
+		{"in sync", local, local},
+		{"within threshold", local.Add(90 * time.Second), local},
+		{"exactly threshold", local.Add(-SkewThreshold), local},
+		{"server ahead", local.Add(10 * time.Minute), local.Add(10 * time.Minute)},
+		{"server behind", local.Add(-3 * time.Hour), local.Add(-3 * time.Hour)},
+	}
+	for _, tc := range tests {
+		T.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := fakeClock(local)
+			c.Observe(tc.server, local)
+			assert.Equal(t, tc.want, c.Now())
+		})
+	}
+}
+
+func TestClockUnobserved(t *testing.T) {
+	t.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fakeClock(local)
+
+	assert.Equal(t, local, c.Now())
+	_, ok := c.Skew()
+	assert.False(t, ok)
+}
+
+func TestClockFirstObservationWins(t *testing.T) {
+	t.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fakeClock(local)
+
+	c.Observe(local.Add(5*time.Minute), local)
+	c.Observe(local, local)
+
+	skew, ok := c.Skew()
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Minute, skew)
+	assert.Equal(t, 5*time.Minute+time.Hour, c.Since(local.Add(-time.Hour)))
+}
+
+func TestClockWarnsOnce(t *testing.T) {
+	t.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fakeClock(local)
+	var warned []time.Duration
+	c.OnSkew(func(skew time.Duration) { warned = append(warned, skew) })
+
+	c.Now()
+	assert.Empty(t, warned, "no warning before the server's clock is known")
+
+	c.Observe(local.Add(-4*time.Minute), local)
+	c.Now()
+	c.Now()
+	assert.Equal(t, []time.Duration{-4 * time.Minute}, warned)
+}
+
+func TestClockNoWarningWithinThreshold(t *testing.T) {
+	t.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fakeClock(local)
+	called := false
+	c.OnSkew(func(time.Duration) { called = true })
+
+	c.Observe(local.Add(time.Minute), local)
+	c.Now()
+	assert.False(t, called)
+}
+
+func TestClockConcurrent(t *testing.T) {
+	t.Parallel()
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fakeClock(local)
+	var calls int
+	c.OnSkew(func(time.Duration) { calls++ })
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			c.Observe(local.Add(time.Duration(i+3)*time.Minute), local)
+			c.Now()
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
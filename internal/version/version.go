@@ -1,8 +1,45 @@
 package version
 
+import "runtime/debug"
+
 // Version is set at build time for release binaries.
 var Version = "dev"
 
+// Commit and Date are set at build time for release binaries; source builds fall back to the Go build info.
+var (
+	Commit = ""
+	Date   = ""
+)
+
 func String() string {
 	return Version
 }
+
+// CommitHash returns the VCS revision the binary was built from, or "" when unknown.
+func CommitHash() string {
+	if Commit != "" {
+		return Commit
+	}
+	return buildSetting("vcs.revision")
+}
+
+// BuildDate returns when the binary was built (RFC 3339), or "" when unknown; source builds report the commit time.
+func BuildDate() string {
+	if Date != "" {
+		return Date
+	}
+	return buildSetting("vcs.time")
+}
+
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
@@ -18,15 +18,17 @@ import (
 )
 
 // Preferred ordering (unlisted commands added alphabetically at end).
-var preferredOrder = []string{"auth", "run", "job", "project", "queue", "agent", "pool", "api"}
+var preferredOrder = []string{"auth", "run", "test", "job", "project", "queue", "agent", "pool", "api"}
 
 // Custom display names for commands that need special treatment.
 var displayNames = map[string]string{
-	"alias": "Aliases",
-	"api":   "API",
-	"auth":  "Authentication",
-	"link":  "Link",
-	"pool":  "Agent Pools",
+	"alias":    "Aliases",
+	"api":      "API",
+	"auth":     "Authentication",
+	"examples": "Examples",
+	"link":     "Link",
+	"plugins":  "Plugins",
+	"pool":     "Agent Pools",
 }
 
 // sectionDescriptions maps command groups to their description and detail page link for Writerside docs.
@@ -36,6 +38,7 @@ var sectionDescriptions = map[string]struct {
 }{
 	"auth":       {"Manage server authentication.", "teamcity-cli-authentication.md"},
 	"run":        {"Start, monitor, and manage builds.", "teamcity-cli-managing-runs.md"},
+	"test":       {"Analyze test results across runs.", "teamcity-cli-managing-runs.md#flaky-tests"},
 	"job":        {"View and configure build configurations.", "teamcity-cli-managing-jobs.md"},
 	"project":    {"Browse projects and manage parameters and settings.", "teamcity-cli-managing-projects.md"},
 	"queue":      {"Manage the build queue.", "teamcity-cli-managing-build-queue.md"},
@@ -43,8 +46,10 @@ var sectionDescriptions = map[string]struct {
 	"pool":       {"Manage agent pool assignments.", "teamcity-cli-managing-agent-pools.md"},
 	"api":        {"Make raw REST API requests.", "teamcity-cli-rest-api-access.md"},
 	"alias":      {"Create custom command shortcuts.", "teamcity-cli-aliases.md"},
+	"plugins":    {"Run executables named `tc-<name>` as `teamcity <name>`.", "teamcity-cli-aliases.md#plugins"},
 	"completion": {"Generate shell completion scripts.", "teamcity-cli-configuration.md#shell-completion"},
 	"skill":      {"Manage AI agent integration.", "teamcity-cli-ai-agent-integration.md"},
+	"examples":   {"Walk through runnable recipes for common workflows.", "teamcity-cli-get-started.md#recipes"},
 }
 
 const commandsDocPath = "docs/topics/teamcity-cli-commands.md"
@@ -203,6 +208,7 @@ func pageLinkText(page string) string {
 	links := map[string]string{
 		"teamcity-cli-authentication.md":                 "Authentication",
 		"teamcity-cli-managing-runs.md":                  "Managing runs",
+		"teamcity-cli-managing-runs.md#flaky-tests":      "Flaky tests",
 		"teamcity-cli-managing-jobs.md":                  "Managing jobs",
 		"teamcity-cli-managing-projects.md":              "Managing projects",
 		"teamcity-cli-managing-build-queue.md":           "Managing the build queue",
@@ -210,6 +216,7 @@ func pageLinkText(page string) string {
 		"teamcity-cli-managing-agent-pools.md":           "Managing agent pools",
 		"teamcity-cli-rest-api-access.md":                "REST API access",
 		"teamcity-cli-aliases.md":                        "Aliases",
+		"teamcity-cli-aliases.md#plugins":                "Plugins",
 		"teamcity-cli-configuration.md#shell-completion": "Configuration",
 		"teamcity-cli-ai-agent-integration.md":           "AI agent integration",
 	}
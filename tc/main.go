@@ -34,6 +34,9 @@ func run() (exitCode int) {
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	// Without this, a write to stdout after its reader quits (a pager, | head) kills the process with SIGPIPE;
+	// ignored, the write fails with EPIPE and commands wind down and exit 0.
+	signal.Ignore(syscall.SIGPIPE)
 
 	if err := cmd.Execute(ctx); err != nil {
 		if exitErr, ok := errors.AsType[*cmdutil.ExitError](err); ok {